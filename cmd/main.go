@@ -17,6 +17,9 @@ limitations under the License.
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"os"
 
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -27,9 +30,20 @@ import (
 	"github.com/kong/gateway-operator/modules/manager"
 	"github.com/kong/gateway-operator/modules/manager/metadata"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/modules/plan"
+	"github.com/kong/gateway-operator/modules/supportbundle"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == cli.SupportBundleCommand {
+		runSupportBundle(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == cli.PlanCommand {
+		runPlan(os.Args[2:])
+		return
+	}
+
 	m := metadata.Metadata()
 
 	cli := cli.New(m)
@@ -42,3 +56,36 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func runSupportBundle(args []string) {
+	cfg, err := cli.ParseSupportBundle(args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	if err := supportbundle.Run(ctrl.SetupSignalHandler(), cfg, scheme.Get()); err != nil {
+		fmt.Printf("ERROR: failed to collect support bundle: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Support bundle written to %s\n", cfg.OutputPath)
+}
+
+func runPlan(args []string) {
+	cfg, err := cli.ParsePlan(args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	if err := plan.Run(ctrl.SetupSignalHandler(), cfg, scheme.Get(), os.Stdout); err != nil {
+		fmt.Printf("ERROR: failed to plan changes: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,14 +28,20 @@ import (
 
 	"github.com/kong/gateway-operator/controller"
 	"github.com/kong/gateway-operator/controller/pkg/controlplane"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
 	"github.com/kong/gateway-operator/internal/utils/index"
 	"github.com/kong/gateway-operator/internal/versions"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -54,6 +61,20 @@ type Reconciler struct {
 	DevelopmentMode          bool
 	KonnectEnabled           bool
 	EnforceConfig            bool
+	EventRecorder            record.EventRecorder
+	// WatchNamespaces is the set of namespaces the operator is restricted to.
+	// When set, ControlPlanes are granted namespaced Roles instead of ClusterRoles
+	// and no cluster-scoped resources are managed for them.
+	WatchNamespaces []string
+
+	// syncStatusGetter allows overriding how the configuration sync status is
+	// retrieved from ControlPlane Pods. Used in tests.
+	syncStatusGetter controlPlaneSyncStatusGetter
+}
+
+// isNamespaceScoped returns true when the operator runs in namespace-scoped mode.
+func (r *Reconciler) isNamespaceScoped() bool {
+	return len(r.WatchNamespaces) > 0
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -74,7 +95,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		return r.validatingWebhookConfigurationHasControlPlaneOwner(e.ObjectOld)
 	}
 
-	builder := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		// watch ControlPlane objects
 		For(&operatorv1beta1.ControlPlane{}).
 		// watch for changes in Secrets created by the controlplane controller
@@ -85,31 +106,6 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		Owns(&appsv1.Deployment{}).
 		// watch for changes in Services created by the controlplane controller
 		Owns(&corev1.Service{}).
-		// watch for changes in ValidatingWebhookConfigurations created by the controlplane controller.
-		// Since the ValidatingWebhookConfigurations are cluster-wide but controlplanes are namespaced,
-		// we need to manually detect the owner by means of the UID
-		// (Owns cannot be used in this case)
-		Watches(
-			&admregv1.ValidatingWebhookConfiguration{},
-			handler.EnqueueRequestsFromMapFunc(r.getControlPlaneForValidatingWebhookConfiguration),
-			builder.WithPredicates(validatinWebhookConfigurationOwnerPredicate),
-		).
-		// watch for changes in ClusterRoles created by the controlplane controller.
-		// Since the ClusterRoles are cluster-wide but controlplanes are namespaced,
-		// we need to manually detect the owner by means of the UID
-		// (Owns cannot be used in this case)
-		Watches(
-			&rbacv1.ClusterRole{},
-			handler.EnqueueRequestsFromMapFunc(r.getControlPlaneForClusterRole),
-			builder.WithPredicates(clusterRoleOwnerPredicate)).
-		// watch for changes in ClusterRoleBindings created by the controlplane controller.
-		// Since the ClusterRoleBindings are cluster-wide but controlplanes are namespaced,
-		// we need to manually detect the owner by means of the UID
-		// (Owns cannot be used in this case)
-		Watches(
-			&rbacv1.ClusterRoleBinding{},
-			handler.EnqueueRequestsFromMapFunc(r.getControlPlaneForClusterRoleBinding),
-			builder.WithPredicates(clusterRoleBindingOwnerPredicate)).
 		Watches(
 			&operatorv1beta1.DataPlane{},
 			handler.EnqueueRequestsFromMapFunc(r.getControlPlanesFromDataPlane)).
@@ -120,10 +116,45 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 			&appsv1.Deployment{},
 			handler.EnqueueRequestsFromMapFunc(r.getControlPlanesFromDataPlaneDeployment))
 
+	if r.isNamespaceScoped() {
+		// In namespace-scoped mode ControlPlanes are granted namespaced Roles
+		// and RoleBindings which can be owned by them.
+		b.
+			Owns(&rbacv1.Role{}).
+			Owns(&rbacv1.RoleBinding{})
+	} else {
+		b.
+			// watch for changes in ValidatingWebhookConfigurations created by the controlplane controller.
+			// Since the ValidatingWebhookConfigurations are cluster-wide but controlplanes are namespaced,
+			// we need to manually detect the owner by means of the UID
+			// (Owns cannot be used in this case)
+			Watches(
+				&admregv1.ValidatingWebhookConfiguration{},
+				handler.EnqueueRequestsFromMapFunc(r.getControlPlaneForValidatingWebhookConfiguration),
+				builder.WithPredicates(validatinWebhookConfigurationOwnerPredicate),
+			).
+			// watch for changes in ClusterRoles created by the controlplane controller.
+			// Since the ClusterRoles are cluster-wide but controlplanes are namespaced,
+			// we need to manually detect the owner by means of the UID
+			// (Owns cannot be used in this case)
+			Watches(
+				&rbacv1.ClusterRole{},
+				handler.EnqueueRequestsFromMapFunc(r.getControlPlaneForClusterRole),
+				builder.WithPredicates(clusterRoleOwnerPredicate)).
+			// watch for changes in ClusterRoleBindings created by the controlplane controller.
+			// Since the ClusterRoleBindings are cluster-wide but controlplanes are namespaced,
+			// we need to manually detect the owner by means of the UID
+			// (Owns cannot be used in this case)
+			Watches(
+				&rbacv1.ClusterRoleBinding{},
+				handler.EnqueueRequestsFromMapFunc(r.getControlPlaneForClusterRoleBinding),
+				builder.WithPredicates(clusterRoleBindingOwnerPredicate))
+	}
+
 	if r.KonnectEnabled {
 		// Watch for changes in KonnectExtension objects that are referenced by ControlPlane objects.
 		// They may trigger reconciliation of DataPlane resources.
-		builder.WatchesRawSource(
+		b.WatchesRawSource(
 			source.Kind(
 				mgr.GetCache(),
 				&konnectv1alpha1.KonnectExtension{},
@@ -132,16 +163,30 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		)
 	}
 
-	return builder.Complete(r)
+	// ControlPlanes managed for a paused Gateway are paused as well.
+	if err := pause.WatchPausedOwners(mgr, b, &gwtypes.Gateway{}, &operatorv1beta1.ControlPlaneList{}); err != nil {
+		return err
+	}
+
+	return b.Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("ControlPlane",
+		pause.NewReconciler(r.Client, "ControlPlane", pause.ConditionsAware[*operatorv1beta1.ControlPlane], r,
+			pause.WithPausedOwners(&gwtypes.Gateway{}),
+		),
+		tracing.WithWriterLinks(r.Client, &operatorv1beta1.ControlPlane{}),
+	)))
 }
 
 // Reconcile moves the current state of an object to the intended state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	logger := log.GetLogger(ctx, "controlplane", r.DevelopmentMode)
 
 	log.Trace(logger, "reconciling ControlPlane resource")
 	cp := new(operatorv1beta1.ControlPlane)
 	if err := r.Client.Get(ctx, req.NamespacedName, cp); err != nil {
+		if k8serrors.IsNotFound(err) {
+			metrics.DeleteManagedCertificateExpiryForOwner("ControlPlane", req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -160,10 +205,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			}, nil
 		}
 
-		log.Trace(logger, "controlplane marked for deletion, removing owned cluster roles, cluster role bindings and validating webhook configurations")
-
 		newControlPlane := cp.DeepCopy()
 
+		// Requeue is triggered by the deletion of the owned cluster wide resources.
+		deletionResult := ctrl.Result{}
+		if r.isNamespaceScoped() {
+			// In namespace-scoped mode namespaced Roles and RoleBindings are garbage
+			// collected through owner references. Cluster wide resources only exist
+			// (and the finalizers are only set) when the ControlPlane was created
+			// before the operator was restricted to namespaces. These are not
+			// watched in namespace-scoped mode so their deletion has to be
+			// followed by an explicit requeue.
+			if !hasClusterResourcesFinalizers(cp) {
+				return ctrl.Result{}, nil
+			}
+			deletionResult = ctrl.Result{RequeueAfter: controller.RequeueWithoutBackoff}
+		}
+
+		log.Trace(logger, "controlplane marked for deletion, removing owned cluster roles, cluster role bindings and validating webhook configurations")
+
 		// ensure that the ValidatingWebhookConfigurations which was created for the ControlPlane is deleted
 		deletions, err := r.ensureOwnedValidatingWebhookConfigurationDeleted(ctx, cp)
 		if err != nil {
@@ -171,7 +231,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		if deletions {
 			log.Debug(logger, "ValidatingWebhookConfiguration deleted")
-			return ctrl.Result{}, nil // ValidatingWebhookConfiguration deletion will requeue
+			return deletionResult, nil // ValidatingWebhookConfiguration deletion will requeue
 		}
 
 		// now that ValidatingWebhookConfigurations are cleaned up, remove the relevant finalizer
@@ -190,7 +250,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		if deletions {
 			log.Debug(logger, "clusterRoleBinding deleted")
-			return ctrl.Result{}, nil // ClusterRoleBinding deletion will requeue
+			return deletionResult, nil // ClusterRoleBinding deletion will requeue
 		}
 
 		// now that ClusterRoleBindings are cleaned up, remove the relevant finalizer
@@ -209,7 +269,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 		if deletions {
 			log.Debug(logger, "clusterRole deleted")
-			return ctrl.Result{}, nil // ClusterRole deletion will requeue
+			return deletionResult, nil // ClusterRole deletion will requeue
 		}
 
 		// now that ClusterRoles are cleaned up, remove the relevant finalizer
@@ -227,9 +287,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	// ensure the controlplane has a finalizer to delete owned cluster wide resources on delete.
-	crFinalizerSet := controllerutil.AddFinalizer(cp, string(ControlPlaneFinalizerCleanupClusterRole))
-	crbFinalizerSet := controllerutil.AddFinalizer(cp, string(ControlPlaneFinalizerCleanupClusterRoleBinding))
-	vwcFinalizerSet := controllerutil.AddFinalizer(cp, string(ControlPlaneFinalizerCleanupValidatingWebhookConfiguration))
+	// In namespace-scoped mode no cluster wide resources are created, hence no finalizers are needed.
+	var crFinalizerSet, crbFinalizerSet, vwcFinalizerSet bool
+	if !r.isNamespaceScoped() {
+		crFinalizerSet = controllerutil.AddFinalizer(cp, string(ControlPlaneFinalizerCleanupClusterRole))
+		crbFinalizerSet = controllerutil.AddFinalizer(cp, string(ControlPlaneFinalizerCleanupClusterRoleBinding))
+		vwcFinalizerSet = controllerutil.AddFinalizer(cp, string(ControlPlaneFinalizerCleanupValidatingWebhookConfiguration))
+	}
 	if crFinalizerSet || crbFinalizerSet || vwcFinalizerSet {
 		log.Trace(logger, "setting finalizers")
 		if err := r.Client.Update(ctx, cp); err != nil {
@@ -291,22 +355,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	log.Trace(logger, "configuring ControlPlane resource")
 
-	defaultArgs := controlplane.DefaultsArgs{
-		Namespace:                   cp.Namespace,
-		ControlPlaneName:            cp.Name,
-		DataPlaneIngressServiceName: dataplaneIngressServiceName,
-		DataPlaneAdminServiceName:   dataplaneAdminServiceName,
-		AnonymousReportsEnabled:     controlplane.DeduceAnonymousReportsEnabled(r.DevelopmentMode, &cp.Spec.ControlPlaneOptions),
-	}
-	for _, owner := range cp.OwnerReferences {
-		if strings.HasPrefix(owner.APIVersion, gatewayv1.GroupName) && owner.Kind == "Gateway" {
-			defaultArgs.OwnedByGateway = owner.Name
-			continue
-		}
-	}
 	_ = controlplane.SetDefaults(
 		&cp.Spec.ControlPlaneOptions,
-		defaultArgs)
+		r.defaultsArgs(cp, dataplaneIngressServiceName, dataplaneAdminServiceName))
 	stop, result, err := extensions.ApplyExtensions(ctx, r.Client, cp, r.KonnectEnabled)
 	if err != nil {
 		if extensionserrors.IsKonnectExtensionError(err) {
@@ -350,24 +401,46 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
 	}
 
-	log.Trace(logger, "ensuring ClusterRoles for ControlPlane deployment exist")
-	createdOrUpdated, controlplaneClusterRole, err := r.ensureClusterRole(ctx, cp)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-	if createdOrUpdated {
-		log.Debug(logger, "clusterRole updated")
-		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
-	}
+	if r.isNamespaceScoped() {
+		log.Trace(logger, "ensuring Roles for ControlPlane deployment exist")
+		createdOrUpdated, controlplaneRole, err := r.ensureRole(ctx, cp)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if createdOrUpdated {
+			log.Debug(logger, "role updated")
+			return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
+		}
 
-	log.Trace(logger, "ensuring that ClusterRoleBindings for ControlPlane Deployment exist")
-	createdOrUpdated, _, err = r.ensureClusterRoleBinding(ctx, cp, controlplaneServiceAccount.Name, controlplaneClusterRole.Name)
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-	if createdOrUpdated {
-		log.Debug(logger, "clusterRoleBinding updated")
-		return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
+		log.Trace(logger, "ensuring that RoleBindings for ControlPlane Deployment exist")
+		createdOrUpdated, _, err = r.ensureRoleBinding(ctx, cp, controlplaneServiceAccount.Name, controlplaneRole.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if createdOrUpdated {
+			log.Debug(logger, "roleBinding updated")
+			return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
+		}
+	} else {
+		log.Trace(logger, "ensuring ClusterRoles for ControlPlane deployment exist")
+		createdOrUpdated, controlplaneClusterRole, err := r.ensureClusterRole(ctx, cp)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if createdOrUpdated {
+			log.Debug(logger, "clusterRole updated")
+			return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
+		}
+
+		log.Trace(logger, "ensuring that ClusterRoleBindings for ControlPlane Deployment exist")
+		createdOrUpdated, _, err = r.ensureClusterRoleBinding(ctx, cp, controlplaneServiceAccount.Name, controlplaneClusterRole.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if createdOrUpdated {
+			log.Debug(logger, "clusterRoleBinding updated")
+			return ctrl.Result{}, nil // requeue will be triggered by the creation or update of the owned object
+		}
 	}
 
 	log.Trace(logger, "creating mTLS certificate")
@@ -395,6 +468,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	deploymentParams.AdmissionWebhookCertSecretName = admissionWebhookCertificateSecretName
 
+	log.Trace(logger, "ensuring blue/green rollout of ControlPlane Deployment")
+	promoted, res, err := r.ensureBlueGreenRollout(ctx, logger, deploymentParams, dataplane)
+	if err != nil || res != op.Noop {
+		if _, pErr := r.patchStatus(ctx, logger, cp); pErr != nil {
+			err = errors.Join(err, pErr)
+		}
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to ensure blue/green rollout: %w", err)
+		}
+		return ctrl.Result{}, nil // preview Deployment modification will trigger reconciliation
+	}
+	deploymentParams.KeepLiveDeployment = !promoted
+
 	log.Trace(logger, "looking for existing Deployments for ControlPlane resource")
 	res, controlplaneDeployment, err := r.ensureDeployment(ctx, logger, deploymentParams)
 	if err != nil {
@@ -451,6 +537,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return result, nil
 	}
 
+	if isAwaitingPreviewSync(cp) {
+		log.Debug(logger, "waiting for the preview Deployment to sync its configuration")
+		return ctrl.Result{RequeueAfter: controlPlanePreviewSyncCheckInterval}, nil
+	}
+
 	log.Debug(logger, "reconciliation complete for ControlPlane resource")
 	return ctrl.Result{}, nil
 }
@@ -493,6 +584,12 @@ func (r *Reconciler) ensureWebhookResources(
 	ctx context.Context, logger logr.Logger, cp *operatorv1beta1.ControlPlane,
 ) (string, op.Result, error) {
 	webhookEnabled := isAdmissionWebhookEnabled(ctx, r.Client, logger, cp)
+	if webhookEnabled && r.isNamespaceScoped() {
+		return "", op.Noop, errors.New(
+			"admission webhook requires a cluster-scoped ValidatingWebhookConfiguration which is not supported in namespace-scoped mode, " +
+				"set CONTROLLER_ADMISSION_WEBHOOK_LISTEN to \"off\" in the ControlPlane's controller container",
+		)
+	}
 	if !webhookEnabled {
 		log.Debug(logger, "admission webhook disabled, ensuring admission webhook resources are not present")
 	} else {
@@ -527,6 +624,10 @@ func (r *Reconciler) ensureWebhookResources(
 		return "", res, nil // requeue will be triggered by the creation or update of the owned object
 	}
 
+	if r.isNamespaceScoped() {
+		return "", op.Noop, nil
+	}
+
 	log.Trace(logger, "ensuring admission webhook configuration")
 	res, err = r.ensureValidatingWebhookConfiguration(ctx, cp, admissionWebhookCertificateSecret, admissionWebhookService)
 	if err != nil {
@@ -565,3 +666,29 @@ func isAdmissionWebhookEnabled(ctx context.Context, cl client.Client, logger log
 	// We don't validate the value of the env var here, just that it is set.
 	return len(admissionWebhookListen) > 0 && admissionWebhookListen != "off"
 }
+
+// defaultsArgs returns the arguments used to set the defaults of the provided
+// ControlPlane's options.
+func (r *Reconciler) defaultsArgs(
+	cp *operatorv1beta1.ControlPlane,
+	dataplaneIngressServiceName string,
+	dataplaneAdminServiceName string,
+) controlplane.DefaultsArgs {
+	defaultArgs := controlplane.DefaultsArgs{
+		Namespace:                   cp.Namespace,
+		ControlPlaneName:            cp.Name,
+		DataPlaneIngressServiceName: dataplaneIngressServiceName,
+		DataPlaneAdminServiceName:   dataplaneAdminServiceName,
+		AnonymousReportsEnabled:     controlplane.DeduceAnonymousReportsEnabled(r.DevelopmentMode, &cp.Spec.ControlPlaneOptions),
+	}
+	if r.isNamespaceScoped() {
+		defaultArgs.WatchNamespace = cp.Namespace
+	}
+	for _, owner := range cp.OwnerReferences {
+		if strings.HasPrefix(owner.APIVersion, gatewayv1.GroupName) && owner.Kind == "Gateway" {
+			defaultArgs.OwnedByGateway = owner.Name
+			continue
+		}
+	}
+	return defaultArgs
+}
@@ -0,0 +1,351 @@
+package controlplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/pkg/clientops"
+	"github.com/kong/gateway-operator/pkg/consts"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+const (
+	// controlPlaneConditionMessageRolloutInitialized is the RolledOut condition
+	// message set when the "preview" Deployment has been created or updated.
+	controlPlaneConditionMessageRolloutInitialized = "Rollout initialized"
+	// controlPlaneConditionMessagePreviewDeploymentNotYetReady is the RolledOut
+	// condition message set when the "preview" Deployment is not ready yet,
+	// i.e. it has not synced its configuration with the "preview" DataPlane Pods.
+	controlPlaneConditionMessagePreviewDeploymentNotYetReady = "Preview Deployment not yet ready"
+	// controlPlaneConditionMessagePreviewDeploymentNotYetSynced is the RolledOut
+	// condition message prefix set when the "preview" Deployment is ready but
+	// has not successfully pushed its configuration to the "preview" DataPlane
+	// Pods yet.
+	controlPlaneConditionMessagePreviewDeploymentNotYetSynced = "Preview Deployment configuration not yet synced"
+)
+
+// getControlPlaneRolloutOptions returns the rollout options configured with
+// consts.ControlPlaneRolloutAnnotation or nil if the annotation is not set.
+func getControlPlaneRolloutOptions(cp *operatorv1beta1.ControlPlane) (*operatorv1beta1.Rollout, error) {
+	value, ok := cp.Annotations[consts.ControlPlaneRolloutAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var rollout operatorv1beta1.Rollout
+	if err := json.Unmarshal([]byte(value), &rollout); err != nil {
+		return nil, fmt.Errorf("failed parsing %s annotation: %w", consts.ControlPlaneRolloutAnnotation, err)
+	}
+	if rollout.Strategy.BlueGreen == nil {
+		return nil, fmt.Errorf("%s annotation has to configure the blueGreen strategy", consts.ControlPlaneRolloutAnnotation)
+	}
+	switch rollout.Strategy.BlueGreen.Promotion.Strategy {
+	case "":
+		rollout.Strategy.BlueGreen.Promotion.Strategy = operatorv1beta1.BreakBeforePromotion
+	case operatorv1beta1.AutomaticPromotion, operatorv1beta1.BreakBeforePromotion:
+	default:
+		return nil, fmt.Errorf("unknown promotion strategy %q in %s annotation",
+			rollout.Strategy.BlueGreen.Promotion.Strategy, consts.ControlPlaneRolloutAnnotation)
+	}
+	return &rollout, nil
+}
+
+// ensureBlueGreenRollout performs the blue/green rollout of the ControlPlane's
+// Deployment when it is configured with consts.ControlPlaneRolloutAnnotation.
+//
+// When the "live" Deployment is outdated, a "preview" Deployment running the new
+// version is deployed next to it and configured to translate the configuration
+// against the DataPlane's "preview" Pods. Once the preview Deployment is ready,
+// its Pods have successfully pushed their configuration to the DataPlane's
+// "preview" Pods (as reported by the ingress controller's metrics) and the
+// promotion is allowed by the promotion strategy, the "live" Deployment can be
+// updated.
+// The "preview" Deployment is removed once the "live" Deployment is rolled out.
+//
+// It returns true when the "live" Deployment may be updated, i.e. when no rollout
+// is configured or needed or when the "preview" Deployment has been promoted.
+// The ControlPlane's RolledOut condition is set accordingly and is expected to
+// be patched by the caller.
+func (r *Reconciler) ensureBlueGreenRollout(
+	ctx context.Context,
+	logger logr.Logger,
+	params ensureDeploymentParams,
+	dataplane *operatorv1beta1.DataPlane,
+) (bool, op.Result, error) {
+	cp := params.ControlPlane
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(ctx,
+		r.Client,
+		cp.Namespace,
+		cp.UID,
+		client.MatchingLabels{
+			consts.GatewayOperatorManagedByLabel: consts.ControlPlaneManagedLabelValue,
+		},
+	)
+	if err != nil {
+		return false, op.Noop, err
+	}
+	previews, live := lo.FilterReject(deployments, func(d appsv1.Deployment, _ int) bool {
+		return isPreviewDeployment(&d)
+	})
+
+	rollout, err := getControlPlaneRolloutOptions(cp)
+	if err != nil {
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutFailed, err.Error())
+		return false, op.Noop, nil
+	}
+
+	dataplaneIsSet := dataplane != nil && cp.Spec.DataPlane != nil && *cp.Spec.DataPlane == dataplane.Name
+	if rollout == nil || !dataplaneIsSet || len(live) != 1 {
+		// Either there's no rollout configured or there's nothing to roll out
+		// from. Let the "live" Deployment be handled as usual.
+		res, err := r.ensurePreviewDeploymentsDeleted(ctx, previews)
+		return true, res, err
+	}
+
+	hash, err := k8sresources.CalculateHash(cp.Spec)
+	if err != nil {
+		return false, op.Noop, fmt.Errorf("failed to calculate hash spec from ControlPlane: %w", err)
+	}
+	if live[0].Annotations[consts.AnnotationPodTemplateSpecHash] == hash {
+		if !isDeploymentReady(&live[0]) {
+			if len(previews) > 0 {
+				log.Trace(logger, "waiting for the promoted live Deployment to be rolled out")
+				setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutPromotionInProgress, "")
+			}
+			return true, op.Noop, nil
+		}
+
+		setRolledOutCondition(cp, metav1.ConditionTrue, ControlPlaneConditionReasonRolloutPromotionDone, "")
+		if len(previews) == 0 {
+			return true, op.Noop, nil
+		}
+		log.Debug(logger, "ControlPlane promotion done, removing preview Deployment")
+		if err := r.resetPromoteWhenReadyAnnotation(ctx, cp); err != nil {
+			return true, op.Noop, err
+		}
+		res, err := r.ensurePreviewDeploymentsDeleted(ctx, previews)
+		return true, res, err
+	}
+
+	log.Trace(logger, "ControlPlane Deployment is outdated, performing blue/green rollout")
+	previewAdminServiceName, err := gatewayutils.GetDataPlanePreviewServiceName(ctx, r.Client, dataplane, consts.DataPlaneAdminServiceLabelValue)
+	if err != nil {
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutFailed,
+			fmt.Sprintf("DataPlane %s has no preview Admin API Service, make sure it uses the BlueGreen rollout strategy: %v", dataplane.Name, err))
+		return false, op.Noop, nil
+	}
+	previewIngressServiceName, err := gatewayutils.GetDataPlanePreviewServiceName(ctx, r.Client, dataplane, consts.DataPlaneIngressServiceLabelValue)
+	if err != nil {
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutFailed,
+			fmt.Sprintf("DataPlane %s has no preview ingress Service, make sure it uses the BlueGreen rollout strategy: %v", dataplane.Name, err))
+		return false, op.Noop, nil
+	}
+
+	res, preview, err := r.ensurePreviewDeployment(ctx, logger, params, previews, previewAdminServiceName, previewIngressServiceName)
+	if err != nil {
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutFailed, "failed to ensure preview Deployment")
+		return false, op.Noop, err
+	}
+	if res != op.Noop {
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutProgressing, controlPlaneConditionMessageRolloutInitialized)
+		return false, res, nil // preview Deployment creation/update will trigger reconciliation
+	}
+
+	if !isDeploymentReady(preview) {
+		log.Trace(logger, "preview Deployment for ControlPlane not ready yet")
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutProgressing, controlPlaneConditionMessagePreviewDeploymentNotYetReady)
+		return false, op.Noop, nil
+	}
+
+	synced, msg, err := r.isPreviewDeploymentSynced(ctx, preview)
+	if err != nil {
+		return false, op.Noop, err
+	}
+	if !synced {
+		log.Trace(logger, "preview Deployment for ControlPlane has not synced its configuration yet", "reason", msg)
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutProgressing,
+			fmt.Sprintf("%s: %s", controlPlaneConditionMessagePreviewDeploymentNotYetSynced, msg))
+		return false, op.Noop, nil
+	}
+
+	if rollout.Strategy.BlueGreen.Promotion.Strategy == operatorv1beta1.BreakBeforePromotion &&
+		cp.Annotations[consts.ControlPlanePromoteWhenReadyAnnotation] != "true" {
+		log.Debug(logger, "ControlPlane preview Deployment is awaiting promotion trigger")
+		setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutAwaitingPromotion, "")
+		return false, op.Noop, nil
+	}
+
+	log.Debug(logger, "promoting ControlPlane preview Deployment")
+	setRolledOutCondition(cp, metav1.ConditionFalse, ControlPlaneConditionReasonRolloutPromotionInProgress, "")
+	return true, op.Noop, nil
+}
+
+// ensurePreviewDeployment ensures that the "preview" Deployment of the ControlPlane
+// exists and is up to date with the ControlPlane's spec.
+func (r *Reconciler) ensurePreviewDeployment(
+	ctx context.Context,
+	logger logr.Logger,
+	params ensureDeploymentParams,
+	previews []appsv1.Deployment,
+	previewAdminServiceName string,
+	previewIngressServiceName string,
+) (op.Result, *appsv1.Deployment, error) {
+	generated, err := r.generateDeployment(params)
+	if err != nil {
+		return op.Noop, nil, err
+	}
+	if err := setPreviewDeploymentOptions(generated, params.ControlPlane, previewAdminServiceName, previewIngressServiceName); err != nil {
+		return op.Noop, nil, err
+	}
+
+	switch len(previews) {
+	case 0:
+		if err := r.Client.Create(ctx, generated); err != nil {
+			return op.Noop, nil, fmt.Errorf("failed creating ControlPlane preview Deployment %s: %w", generated.Name, err)
+		}
+		log.Debug(logger, "preview deployment for ControlPlane created", "deployment", generated.Name)
+		return op.Created, generated, nil
+	case 1:
+	default:
+		// The selector of the generated Deployment is always the same so
+		// there's no point in keeping more than one preview Deployment.
+		res, err := r.ensurePreviewDeploymentsDeleted(ctx, previews[1:])
+		return res, nil, err
+	}
+
+	existing := &previews[0]
+	old := existing.DeepCopy()
+	var updated bool
+	updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, generated.ObjectMeta)
+	if !cmp.Equal(existing.Spec.Template, generated.Spec.Template, cmp.Comparer(k8sresources.ResourceRequirementsEqual)) {
+		existing.Spec.Template = generated.Spec.Template
+		updated = true
+	}
+	if !cmp.Equal(existing.Spec.Replicas, generated.Spec.Replicas) {
+		existing.Spec.Replicas = generated.Spec.Replicas
+		updated = true
+	}
+	return patch.ApplyPatchIfNotEmpty(ctx, r.Client, logger, existing, old, updated)
+}
+
+// setPreviewDeploymentOptions turns the provided ControlPlane Deployment into
+// a "preview" Deployment: it gets its own selector so that it's not managed
+// together with the "live" one and its controller container is configured to
+// push the configuration to the DataPlane's "preview" Pods, without contending
+// with the "live" controller for leadership or updating resources' statuses.
+func setPreviewDeploymentOptions(
+	deployment *appsv1.Deployment,
+	cp *operatorv1beta1.ControlPlane,
+	previewAdminServiceName string,
+	previewIngressServiceName string,
+) error {
+	deployment.GenerateName = k8sutils.TrimGenerateName(fmt.Sprintf("%s-%s-%s-", consts.ControlPlanePrefix, cp.Name, consts.ControlPlaneStateLabelValuePreview))
+	deployment.Labels[consts.ControlPlaneDeploymentStateLabel] = consts.ControlPlaneStateLabelValuePreview
+	deployment.Spec.Selector.MatchLabels[consts.ControlPlaneDeploymentStateLabel] = consts.ControlPlaneStateLabelValuePreview
+	if deployment.Spec.Template.Labels == nil {
+		deployment.Spec.Template.Labels = map[string]string{}
+	}
+	deployment.Spec.Template.Labels[consts.ControlPlaneDeploymentStateLabel] = consts.ControlPlaneStateLabelValuePreview
+	// A single replica is enough to validate the configuration.
+	deployment.Spec.Replicas = lo.ToPtr(int32(1))
+
+	container := k8sutils.GetPodContainerByName(&deployment.Spec.Template.Spec, consts.ControlPlaneControllerContainerName)
+	if container == nil {
+		return fmt.Errorf("container %s not found in ControlPlane Deployment", consts.ControlPlaneControllerContainerName)
+	}
+	electionID := k8sutils.EnvValueByName(container.Env, "CONTROLLER_ELECTION_ID")
+	if electionID == "" {
+		electionID = fmt.Sprintf("%s.konghq.com", cp.Name)
+	}
+	container.Env = k8sutils.UpdateEnv(container.Env, "CONTROLLER_ELECTION_ID", fmt.Sprintf("%s-%s", consts.ControlPlaneStateLabelValuePreview, electionID))
+	container.Env = k8sutils.UpdateEnv(container.Env, "CONTROLLER_KONG_ADMIN_SVC",
+		k8stypes.NamespacedName{Namespace: cp.Namespace, Name: previewAdminServiceName}.String())
+	container.Env = k8sutils.UpdateEnv(container.Env, "CONTROLLER_PUBLISH_SERVICE",
+		k8stypes.NamespacedName{Namespace: cp.Namespace, Name: previewIngressServiceName}.String())
+	container.Env = k8sutils.UpdateEnv(container.Env, "CONTROLLER_UPDATE_STATUS", "false")
+	return nil
+}
+
+// ensurePreviewDeploymentsDeleted deletes the provided "preview" Deployments.
+func (r *Reconciler) ensurePreviewDeploymentsDeleted(ctx context.Context, previews []appsv1.Deployment) (op.Result, error) {
+	if len(previews) == 0 {
+		return op.Noop, nil
+	}
+	if err := clientops.DeleteAll(ctx, r.Client, previews); err != nil {
+		return op.Noop, fmt.Errorf("failed deleting ControlPlane preview Deployments: %w", err)
+	}
+	return op.Deleted, nil
+}
+
+// resetPromoteWhenReadyAnnotation removes the promote-when-ready annotation from
+// the ControlPlane so that the next rollout is not promoted unintentionally.
+// The ControlPlane is re-fetched so that the defaults set on the provided one
+// in memory are not persisted.
+func (r *Reconciler) resetPromoteWhenReadyAnnotation(ctx context.Context, cp *operatorv1beta1.ControlPlane) error {
+	if _, ok := cp.Annotations[consts.ControlPlanePromoteWhenReadyAnnotation]; !ok {
+		return nil
+	}
+	current := &operatorv1beta1.ControlPlane{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cp), current); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	old := current.DeepCopy()
+	delete(current.Annotations, consts.ControlPlanePromoteWhenReadyAnnotation)
+	if err := r.Client.Patch(ctx, current, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed resetting promote-when-ready annotation: %w", err)
+	}
+	return nil
+}
+
+// setRolledOutCondition sets the RolledOut condition on the provided ControlPlane.
+func setRolledOutCondition(
+	cp *operatorv1beta1.ControlPlane,
+	status metav1.ConditionStatus,
+	reason kcfgconsts.ConditionReason,
+	message string,
+) {
+	k8sutils.SetCondition(
+		k8sutils.NewConditionWithGeneration(ControlPlaneConditionTypeRolledOut, status, reason, message, cp.Generation),
+		cp,
+	)
+}
+
+// isPreviewDeployment returns true if the provided Deployment is a ControlPlane's
+// "preview" Deployment.
+func isPreviewDeployment(deployment *appsv1.Deployment) bool {
+	return deployment.Labels[consts.ControlPlaneDeploymentStateLabel] == consts.ControlPlaneStateLabelValuePreview
+}
+
+// isDeploymentReady returns true if all the replicas of the provided Deployment
+// are up to date, ready and available.
+func isDeploymentReady(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	status := deployment.Status
+	return status.ObservedGeneration >= deployment.Generation &&
+		replicas > 0 &&
+		status.Replicas == replicas &&
+		status.UpdatedReplicas == replicas &&
+		status.ReadyReplicas == replicas &&
+		status.AvailableReplicas == replicas
+}
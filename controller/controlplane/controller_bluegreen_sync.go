@@ -0,0 +1,180 @@
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// ControlPlane - blue/green preview configuration sync
+// -----------------------------------------------------------------------------
+
+const (
+	// controlPlanePreviewSyncCheckInterval is the interval at which the
+	// configuration sync of the "preview" Deployment is checked while it is
+	// awaited, as it is not reflected in any watched resource.
+	controlPlanePreviewSyncCheckInterval = 10 * time.Second
+
+	// controlPlaneMetricsRequestTimeout is the timeout for the requests sent to
+	// the ControlPlane Pods' metrics endpoint.
+	controlPlaneMetricsRequestTimeout = 5 * time.Second
+
+	// controlPlaneConfigPushMetric and controlPlaneTranslationMetric are the
+	// metrics the ingress controller exposes about the outcome of translating
+	// the configuration and pushing it to the DataPlane Pods.
+	controlPlaneConfigPushMetric  = "ingress_controller_configpush_count"
+	controlPlaneTranslationMetric = "ingress_controller_translation_count"
+)
+
+// controlPlaneSyncStatus is the configuration sync status reported by
+// a ControlPlane Pod since it started.
+type controlPlaneSyncStatus struct {
+	successfulPushes   float64
+	failedPushes       float64
+	failedTranslations float64
+}
+
+// controlPlaneSyncStatusGetter returns the configuration sync status of the
+// provided ControlPlane Pod.
+type controlPlaneSyncStatusGetter func(ctx context.Context, pod *corev1.Pod) (controlPlaneSyncStatus, error)
+
+// getSyncStatusGetter returns the controlPlaneSyncStatusGetter used by the Reconciler.
+func (r *Reconciler) getSyncStatusGetter() controlPlaneSyncStatusGetter {
+	if r.syncStatusGetter != nil {
+		return r.syncStatusGetter
+	}
+	return controlPlaneSyncStatusFromMetricsEndpoint(&http.Client{Timeout: controlPlaneMetricsRequestTimeout})
+}
+
+// controlPlaneSyncStatusFromMetricsEndpoint returns a controlPlaneSyncStatusGetter
+// which scrapes the metrics endpoint of the ControlPlane Pods.
+func controlPlaneSyncStatusFromMetricsEndpoint(httpClient *http.Client) controlPlaneSyncStatusGetter {
+	return func(ctx context.Context, pod *corev1.Pod) (controlPlaneSyncStatus, error) {
+		url := fmt.Sprintf("http://%s%s",
+			net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(consts.ControlPlaneMetricsPort)), consts.ControlPlaneMetricsEndpoint)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return controlPlaneSyncStatus{}, err
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return controlPlaneSyncStatus{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return controlPlaneSyncStatus{}, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		}
+		return parseControlPlaneSyncStatus(resp.Body)
+	}
+}
+
+// parseControlPlaneSyncStatus parses the configuration sync status from the
+// provided ingress controller metrics in the Prometheus text format.
+func parseControlPlaneSyncStatus(in io.Reader) (controlPlaneSyncStatus, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return controlPlaneSyncStatus{}, fmt.Errorf("failed parsing metrics: %w", err)
+	}
+
+	succeeded := func(m *dto.Metric) bool {
+		for _, lp := range m.GetLabel() {
+			if lp.GetName() == "success" {
+				return lp.GetValue() == "true"
+			}
+		}
+		return false
+	}
+
+	var status controlPlaneSyncStatus
+	if family, ok := families[controlPlaneConfigPushMetric]; ok {
+		for _, m := range family.GetMetric() {
+			if succeeded(m) {
+				status.successfulPushes += m.GetCounter().GetValue()
+			} else {
+				status.failedPushes += m.GetCounter().GetValue()
+			}
+		}
+	}
+	if family, ok := families[controlPlaneTranslationMetric]; ok {
+		for _, m := range family.GetMetric() {
+			if !succeeded(m) {
+				status.failedTranslations += m.GetCounter().GetValue()
+			}
+		}
+	}
+	return status, nil
+}
+
+// isPreviewDeploymentSynced returns true when all the ready Pods of the
+// provided "preview" Deployment have successfully pushed their configuration
+// to the DataPlane's "preview" Pods, without any translation or push failure.
+// When it returns false, the returned message explains why.
+func (r *Reconciler) isPreviewDeploymentSynced(ctx context.Context, preview *appsv1.Deployment) (bool, string, error) {
+	if preview.Spec.Selector == nil {
+		return false, "preview Deployment has no selector", nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(preview.Spec.Selector)
+	if err != nil {
+		return false, "", fmt.Errorf("failed parsing preview Deployment %s selector: %w", preview.Name, err)
+	}
+	var pods corev1.PodList
+	if err := r.Client.List(ctx, &pods,
+		client.InNamespace(preview.Namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	); err != nil {
+		return false, "", fmt.Errorf("failed listing preview Deployment %s Pods: %w", preview.Name, err)
+	}
+
+	var ready int
+	getSyncStatus := r.getSyncStatusGetter()
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || pod.Status.PodIP == "" || !k8sutils.IsPodReady(pod) {
+			continue
+		}
+		ready++
+		status, err := getSyncStatus(ctx, pod)
+		switch {
+		case err != nil:
+			return false, fmt.Sprintf("failed getting configuration sync status of Pod %s: %v", pod.Name, err), nil
+		case status.failedTranslations > 0:
+			return false, fmt.Sprintf("Pod %s failed translating the configuration %.0f times", pod.Name, status.failedTranslations), nil
+		case status.failedPushes > 0:
+			return false, fmt.Sprintf("Pod %s failed pushing the configuration %.0f times", pod.Name, status.failedPushes), nil
+		case status.successfulPushes == 0:
+			return false, fmt.Sprintf("Pod %s has not pushed the configuration yet", pod.Name), nil
+		}
+	}
+	if ready == 0 {
+		return false, "no ready preview Pods", nil
+	}
+	return true, "", nil
+}
+
+// isAwaitingPreviewSync returns true when the ControlPlane's blue/green rollout
+// waits for the "preview" Deployment to sync its configuration.
+func isAwaitingPreviewSync(cp *operatorv1beta1.ControlPlane) bool {
+	c, ok := k8sutils.GetCondition(ControlPlaneConditionTypeRolledOut, cp)
+	return ok && c.Status == metav1.ConditionFalse &&
+		c.Reason == string(ControlPlaneConditionReasonRolloutProgressing) &&
+		strings.HasPrefix(c.Message, controlPlaneConditionMessagePreviewDeploymentNotYetSynced)
+}
@@ -0,0 +1,420 @@
+package controlplane
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestGetControlPlaneRolloutOptions(t *testing.T) {
+	testCases := []struct {
+		name             string
+		annotations      map[string]string
+		expectedStrategy operatorv1beta1.PromotionStrategy
+		expectedNil      bool
+		expectedErr      bool
+	}{
+		{
+			name:        "no annotation",
+			expectedNil: true,
+		},
+		{
+			name: "promotion strategy defaults to BreakBeforePromotion",
+			annotations: map[string]string{
+				consts.ControlPlaneRolloutAnnotation: `{"strategy":{"blueGreen":{}}}`,
+			},
+			expectedStrategy: operatorv1beta1.BreakBeforePromotion,
+		},
+		{
+			name: "automatic promotion",
+			annotations: map[string]string{
+				consts.ControlPlaneRolloutAnnotation: `{"strategy":{"blueGreen":{"promotion":{"strategy":"AutomaticPromotion"}}}}`,
+			},
+			expectedStrategy: operatorv1beta1.AutomaticPromotion,
+		},
+		{
+			name: "missing blueGreen strategy",
+			annotations: map[string]string{
+				consts.ControlPlaneRolloutAnnotation: `{"strategy":{}}`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unknown promotion strategy",
+			annotations: map[string]string{
+				consts.ControlPlaneRolloutAnnotation: `{"strategy":{"blueGreen":{"promotion":{"strategy":"Canary"}}}}`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "malformed annotation",
+			annotations: map[string]string{
+				consts.ControlPlaneRolloutAnnotation: `strategy: blueGreen`,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cp := &operatorv1beta1.ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			rollout, err := getControlPlaneRolloutOptions(cp)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectedNil {
+				require.Nil(t, rollout)
+				return
+			}
+			require.NotNil(t, rollout)
+			require.Equal(t, tc.expectedStrategy, rollout.Strategy.BlueGreen.Promotion.Strategy)
+		})
+	}
+}
+
+func TestSetPreviewDeploymentOptions(t *testing.T) {
+	cp := &operatorv1beta1.ControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "default",
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "cp"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: lo.ToPtr(int32(3)),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "cp"},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "cp"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: consts.ControlPlaneControllerContainerName,
+							Env: []corev1.EnvVar{
+								{Name: "CONTROLLER_ELECTION_ID", Value: "cp.konghq.com"},
+								{Name: "CONTROLLER_KONG_ADMIN_SVC", Value: "default/dp-admin"},
+								{Name: "CONTROLLER_PUBLISH_SERVICE", Value: "default/dp-ingress"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, setPreviewDeploymentOptions(deployment, cp, "dp-admin-preview", "dp-ingress-preview"))
+	require.True(t, isPreviewDeployment(deployment))
+	require.Equal(t, "controlplane-cp-preview-", deployment.GenerateName)
+	require.Equal(t, consts.ControlPlaneStateLabelValuePreview, deployment.Spec.Selector.MatchLabels[consts.ControlPlaneDeploymentStateLabel])
+	require.Equal(t, consts.ControlPlaneStateLabelValuePreview, deployment.Spec.Template.Labels[consts.ControlPlaneDeploymentStateLabel])
+	require.Equal(t, "cp", deployment.Spec.Template.Labels["app"], "app label is expected to be kept to match DataPlane's NetworkPolicy")
+	require.Equal(t, int32(1), *deployment.Spec.Replicas)
+
+	env := deployment.Spec.Template.Spec.Containers[0].Env
+	require.Equal(t, "preview-cp.konghq.com", k8sutils.EnvValueByName(env, "CONTROLLER_ELECTION_ID"))
+	require.Equal(t, "default/dp-admin-preview", k8sutils.EnvValueByName(env, "CONTROLLER_KONG_ADMIN_SVC"))
+	require.Equal(t, "default/dp-ingress-preview", k8sutils.EnvValueByName(env, "CONTROLLER_PUBLISH_SERVICE"))
+	require.Equal(t, "false", k8sutils.EnvValueByName(env, "CONTROLLER_UPDATE_STATUS"))
+}
+
+func TestEnsureBlueGreenRollout(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       types.UID("dp-uid"),
+		},
+	}
+	cp := &operatorv1beta1.ControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway-operator.konghq.com/v1beta1",
+			Kind:       "ControlPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "cp",
+			Namespace:  "default",
+			UID:        types.UID("cp-uid"),
+			Generation: 2,
+			Annotations: map[string]string{
+				consts.ControlPlaneRolloutAnnotation: `{"strategy":{"blueGreen":{"promotion":{"strategy":"BreakBeforePromotion"}}}}`,
+			},
+		},
+		Spec: operatorv1beta1.ControlPlaneSpec{
+			ControlPlaneOptions: operatorv1beta1.ControlPlaneOptions{
+				DataPlane: lo.ToPtr(dataplane.Name),
+				Deployment: operatorv1beta1.ControlPlaneDeploymentOptions{
+					Replicas: lo.ToPtr(int32(1)),
+					PodTemplateSpec: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  consts.ControlPlaneControllerContainerName,
+									Image: consts.DefaultControlPlaneImage,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	liveDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp-live",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":                                cp.Name,
+				consts.GatewayOperatorManagedByLabel: consts.ControlPlaneManagedLabelValue,
+			},
+			Annotations: map[string]string{
+				consts.AnnotationPodTemplateSpecHash: "outdated",
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: lo.ToPtr(int32(1)),
+		},
+	}
+	k8sutils.SetOwnerForObject(liveDeployment, cp)
+	dataPlaneService := func(serviceType consts.ServiceType, state string) *corev1.Service {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dp-" + string(serviceType) + "-" + state,
+				Namespace: "default",
+				Labels: map[string]string{
+					consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+					consts.DataPlaneServiceTypeLabel:     string(serviceType),
+					consts.DataPlaneServiceStateLabel:    state,
+				},
+			},
+		}
+		k8sutils.SetOwnerForObject(svc, dataplane)
+		return svc
+	}
+
+	var (
+		ctx      = t.Context()
+		live     = liveDeployment.DeepCopy()
+		services = []client.Object{
+			dataPlaneService(consts.DataPlaneAdminServiceLabelValue, consts.DataPlaneStateLabelValueLive),
+			dataPlaneService(consts.DataPlaneIngressServiceLabelValue, consts.DataPlaneStateLabelValueLive),
+		}
+	)
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(append(services, cp.DeepCopy(), live)...).
+		Build()
+	var syncStatus controlPlaneSyncStatus
+	r := &Reconciler{
+		Client:          fakeClient,
+		DevelopmentMode: true,
+		syncStatusGetter: func(context.Context, *corev1.Pod) (controlPlaneSyncStatus, error) {
+			return syncStatus, nil
+		},
+	}
+	params := ensureDeploymentParams{
+		ControlPlane:            cp,
+		ServiceAccountName:      "sa",
+		AdminMTLSCertSecretName: "cert",
+	}
+	requireRolledOut := func(t *testing.T, status metav1.ConditionStatus, reason string) {
+		t.Helper()
+		c, ok := k8sutils.GetCondition(ControlPlaneConditionTypeRolledOut, cp)
+		require.True(t, ok)
+		require.Equal(t, status, c.Status)
+		require.Equal(t, reason, c.Reason)
+		require.Equal(t, cp.Generation, c.ObservedGeneration)
+	}
+	listPreviews := func(t *testing.T) []appsv1.Deployment {
+		t.Helper()
+		var deployments appsv1.DeploymentList
+		require.NoError(t, fakeClient.List(ctx, &deployments))
+		return lo.Filter(deployments.Items, func(d appsv1.Deployment, _ int) bool {
+			return isPreviewDeployment(&d)
+		})
+	}
+
+	t.Run("rollout fails when the DataPlane has no preview services", func(t *testing.T) {
+		promoted, res, err := r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, op.Noop, res)
+		require.False(t, promoted)
+		requireRolledOut(t, metav1.ConditionFalse, string(ControlPlaneConditionReasonRolloutFailed))
+		require.Empty(t, listPreviews(t))
+	})
+
+	t.Run("preview Deployment is created", func(t *testing.T) {
+		require.NoError(t, fakeClient.Create(ctx, dataPlaneService(consts.DataPlaneAdminServiceLabelValue, consts.DataPlaneStateLabelValuePreview)))
+		require.NoError(t, fakeClient.Create(ctx, dataPlaneService(consts.DataPlaneIngressServiceLabelValue, consts.DataPlaneStateLabelValuePreview)))
+
+		promoted, res, err := r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, op.Created, res)
+		require.False(t, promoted)
+		requireRolledOut(t, metav1.ConditionFalse, string(ControlPlaneConditionReasonRolloutProgressing))
+
+		previews := listPreviews(t)
+		require.Len(t, previews, 1)
+		env := previews[0].Spec.Template.Spec.Containers[0].Env
+		require.Equal(t, "default/dp-admin-preview", k8sutils.EnvValueByName(env, "CONTROLLER_KONG_ADMIN_SVC"))
+	})
+
+	t.Run("rollout waits for the preview Deployment to be ready", func(t *testing.T) {
+		promoted, res, err := r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, op.Noop, res)
+		require.False(t, promoted)
+		requireRolledOut(t, metav1.ConditionFalse, string(ControlPlaneConditionReasonRolloutProgressing))
+	})
+
+	t.Run("ready preview Deployment waits for its configuration to be synced", func(t *testing.T) {
+		preview := listPreviews(t)[0]
+		preview.Status = readyDeploymentStatus(&preview)
+		require.NoError(t, fakeClient.Status().Update(ctx, &preview))
+
+		requireNotSynced := func(t *testing.T, reason string) {
+			t.Helper()
+			promoted, res, err := r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+			require.NoError(t, err)
+			require.Equal(t, op.Noop, res)
+			require.False(t, promoted)
+			requireRolledOut(t, metav1.ConditionFalse, string(ControlPlaneConditionReasonRolloutProgressing))
+			c, _ := k8sutils.GetCondition(ControlPlaneConditionTypeRolledOut, cp)
+			require.Equal(t, controlPlaneConditionMessagePreviewDeploymentNotYetSynced+": "+reason, c.Message)
+			require.True(t, isAwaitingPreviewSync(cp))
+		}
+
+		requireNotSynced(t, "no ready preview Pods")
+
+		require.NoError(t, fakeClient.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "preview-pod",
+				Namespace: "default",
+				Labels:    preview.Spec.Selector.MatchLabels,
+			},
+			Status: corev1.PodStatus{
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}))
+		requireNotSynced(t, "Pod preview-pod has not pushed the configuration yet")
+
+		syncStatus = controlPlaneSyncStatus{successfulPushes: 1, failedPushes: 2}
+		requireNotSynced(t, "Pod preview-pod failed pushing the configuration 2 times")
+
+		syncStatus = controlPlaneSyncStatus{successfulPushes: 3}
+	})
+
+	t.Run("ready preview Deployment awaits promotion", func(t *testing.T) {
+		promoted, res, err := r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, op.Noop, res)
+		require.False(t, promoted)
+		requireRolledOut(t, metav1.ConditionFalse, string(ControlPlaneConditionReasonRolloutAwaitingPromotion))
+	})
+
+	t.Run("preview Deployment is promoted when requested", func(t *testing.T) {
+		old := cp.DeepCopy()
+		cp.Annotations[consts.ControlPlanePromoteWhenReadyAnnotation] = "true"
+		require.NoError(t, fakeClient.Patch(ctx, cp.DeepCopy(), client.MergeFrom(old)))
+
+		promoted, res, err := r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, op.Noop, res)
+		require.True(t, promoted)
+		requireRolledOut(t, metav1.ConditionFalse, string(ControlPlaneConditionReasonRolloutPromotionInProgress))
+	})
+
+	t.Run("preview Deployment is removed once the live Deployment is rolled out", func(t *testing.T) {
+		hash, err := k8sresources.CalculateHash(cp.Spec)
+		require.NoError(t, err)
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(live), live))
+		live.Annotations[consts.AnnotationPodTemplateSpecHash] = hash
+		require.NoError(t, fakeClient.Update(ctx, live))
+
+		promoted, res, err := r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, op.Noop, res)
+		require.True(t, promoted)
+		requireRolledOut(t, metav1.ConditionFalse, string(ControlPlaneConditionReasonRolloutPromotionInProgress))
+		require.Len(t, listPreviews(t), 1)
+
+		live.Status = readyDeploymentStatus(live)
+		require.NoError(t, fakeClient.Status().Update(ctx, live))
+
+		promoted, res, err = r.ensureBlueGreenRollout(ctx, logr.Discard(), params, dataplane)
+		require.NoError(t, err)
+		require.Equal(t, op.Deleted, res)
+		require.True(t, promoted)
+		requireRolledOut(t, metav1.ConditionTrue, string(ControlPlaneConditionReasonRolloutPromotionDone))
+		require.Empty(t, listPreviews(t))
+
+		var got operatorv1beta1.ControlPlane
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(cp), &got))
+		require.NotContains(t, got.Annotations, consts.ControlPlanePromoteWhenReadyAnnotation)
+	})
+}
+
+func readyDeploymentStatus(d *appsv1.Deployment) appsv1.DeploymentStatus {
+	return appsv1.DeploymentStatus{
+		ObservedGeneration: d.Generation,
+		Replicas:           *d.Spec.Replicas,
+		UpdatedReplicas:    *d.Spec.Replicas,
+		ReadyReplicas:      *d.Spec.Replicas,
+		AvailableReplicas:  *d.Spec.Replicas,
+	}
+}
+
+func TestParseControlPlaneSyncStatus(t *testing.T) {
+	metrics := `# HELP ingress_controller_configpush_count Count of successful/failed configuration pushes to Kong.
+# TYPE ingress_controller_configpush_count counter
+ingress_controller_configpush_count{dataplane="https://10.0.0.1:8444",failure_reason="",protocol="db-less",success="true"} 4
+ingress_controller_configpush_count{dataplane="https://10.0.0.2:8444",failure_reason="",protocol="db-less",success="true"} 3
+ingress_controller_configpush_count{dataplane="https://10.0.0.2:8444",failure_reason="network",protocol="db-less",success="false"} 1
+# HELP ingress_controller_translation_count Count of translations from Kubernetes state to KongState.
+# TYPE ingress_controller_translation_count counter
+ingress_controller_translation_count{success="true"} 7
+ingress_controller_translation_count{success="false"} 2
+`
+	status, err := parseControlPlaneSyncStatus(strings.NewReader(metrics))
+	require.NoError(t, err)
+	require.Equal(t, controlPlaneSyncStatus{
+		successfulPushes:   7,
+		failedPushes:       1,
+		failedTranslations: 2,
+	}, status)
+
+	status, err = parseControlPlaneSyncStatus(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Zero(t, status)
+}
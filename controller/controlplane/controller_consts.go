@@ -1,5 +1,9 @@
 package controlplane
 
+import (
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+)
+
 // -----------------------------------------------------------------------------
 // ControlPlane - Finalizers
 // -----------------------------------------------------------------------------
@@ -15,3 +19,30 @@ const (
 	// ControlPlaneFinalizerCleanupValidatingWebhookConfiguration is the finalizer to cleanup validatingwebhookconfigurations owned by controlplane on deleting.
 	ControlPlaneFinalizerCleanupValidatingWebhookConfiguration ControlPlaneFinalizer = "gateway-operator.konghq.com/cleanup-validatingwebhookconfiguration"
 )
+
+// -----------------------------------------------------------------------------
+// ControlPlane - RolledOut Condition Constants
+// -----------------------------------------------------------------------------
+
+const (
+	// ControlPlaneConditionTypeRolledOut is a condition type indicating whether
+	// the ControlPlane's blue/green rollout has been completed.
+	ControlPlaneConditionTypeRolledOut kcfgconsts.ConditionType = "RolledOut"
+
+	// ControlPlaneConditionReasonRolloutProgressing indicates that the ControlPlane's
+	// "preview" Deployment is being created or is not ready yet.
+	ControlPlaneConditionReasonRolloutProgressing kcfgconsts.ConditionReason = "Progressing"
+	// ControlPlaneConditionReasonRolloutAwaitingPromotion indicates that the
+	// ControlPlane's "preview" Deployment is ready and is waiting for the
+	// promotion to be triggered with the promote-when-ready annotation.
+	ControlPlaneConditionReasonRolloutAwaitingPromotion kcfgconsts.ConditionReason = "AwaitingPromotion"
+	// ControlPlaneConditionReasonRolloutPromotionInProgress indicates that the
+	// "live" Deployment is being updated to the promoted version.
+	ControlPlaneConditionReasonRolloutPromotionInProgress kcfgconsts.ConditionReason = "PromotionInProgress"
+	// ControlPlaneConditionReasonRolloutPromotionDone indicates that the
+	// "live" Deployment runs the promoted version.
+	ControlPlaneConditionReasonRolloutPromotionDone kcfgconsts.ConditionReason = "PromotionDone"
+	// ControlPlaneConditionReasonRolloutFailed indicates that the rollout
+	// could not be performed.
+	ControlPlaneConditionReasonRolloutFailed kcfgconsts.ConditionReason = "Failed"
+)
@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/kong/gateway-operator/controller/pkg/controlplane"
 	"github.com/kong/gateway-operator/controller/pkg/log"
@@ -150,6 +151,10 @@ type ensureDeploymentParams struct {
 	// (typically mutation webhook that enforces some cluster-wide policy,
 	// typically for resources or security).
 	EnforceConfig bool
+	// KeepLiveDeployment prevents updates of an existing Deployment. It is set
+	// when a blue/green rollout of the ControlPlane is in progress and the
+	// "preview" Deployment has not been promoted yet.
+	KeepLiveDeployment bool
 }
 
 // ensureDeployment ensures that a Deployment is created for the
@@ -173,6 +178,10 @@ func (r *Reconciler) ensureDeployment(
 	if err != nil {
 		return op.Noop, nil, err
 	}
+	// "preview" Deployments are managed by ensureBlueGreenRollout.
+	deployments = lo.Reject(deployments, func(d appsv1.Deployment, _ int) bool {
+		return isPreviewDeployment(&d)
+	})
 
 	count := len(deployments)
 	if count > 1 {
@@ -182,21 +191,7 @@ func (r *Reconciler) ensureDeployment(
 		return op.Noop, nil, errors.New("number of deployments reduced")
 	}
 
-	versionValidationOptions := make([]versions.VersionValidationOption, 0)
-	if !r.DevelopmentMode {
-		versionValidationOptions = append(versionValidationOptions, versions.IsControlPlaneImageVersionSupported)
-	}
-	controlplaneImage, err := controlplane.GenerateImage(&params.ControlPlane.Spec.ControlPlaneOptions, versionValidationOptions...)
-	if err != nil {
-		return op.Noop, nil, err
-	}
-	generatedDeployment, err := k8sresources.GenerateNewDeploymentForControlPlane(k8sresources.GenerateNewDeploymentForControlPlaneParams{
-		ControlPlane:                   params.ControlPlane,
-		ControlPlaneImage:              controlplaneImage,
-		ServiceAccountName:             params.ServiceAccountName,
-		AdminMTLSCertSecretName:        params.AdminMTLSCertSecretName,
-		AdmissionWebhookCertSecretName: params.AdmissionWebhookCertSecretName,
-	})
+	generatedDeployment, err := r.generateDeployment(params)
 	if err != nil {
 		return op.Noop, nil, err
 	}
@@ -204,15 +199,20 @@ func (r *Reconciler) ensureDeployment(
 	if count == 1 {
 		existingDeployment := &deployments[0]
 
+		if params.KeepLiveDeployment {
+			log.Trace(logger, "ControlPlane blue/green rollout awaits promotion, skipping Deployment update")
+			return op.Noop, existingDeployment, nil
+		}
+
 		// If the enforceConfig flag is not set, we compare the spec hash of the
 		// existing Deployment with the spec hash of the desired Deployment. If
 		// the hashes match, we skip the update.
 		if !params.EnforceConfig {
-			hash, err := k8sresources.CalculateHash(params.ControlPlane.Spec)
+			matches, hash, err := controlPlaneDeploymentSpecHashMatches(params.ControlPlane, existingDeployment)
 			if err != nil {
-				return op.Noop, nil, fmt.Errorf("failed to calculate hash spec from ControlPlane: %w", err)
+				return op.Noop, nil, err
 			}
-			if h, ok := existingDeployment.GetAnnotations()[consts.AnnotationPodTemplateSpecHash]; ok && h == hash {
+			if matches {
 				log.Debug(logger, "ControlPlane Deployment spec hash matches existing Deployment, skipping update", "hash", hash)
 				return op.Noop, existingDeployment, nil
 			}
@@ -220,43 +220,8 @@ func (r *Reconciler) ensureDeployment(
 			// so fall through to the update logic.
 		}
 
-		var updated bool
 		oldExistingDeployment := existingDeployment.DeepCopy()
-
-		// ensure that object metadata is up to date
-		updated, existingDeployment.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingDeployment.ObjectMeta, generatedDeployment.ObjectMeta)
-
-		// some custom comparison rules are needed for some PodTemplateSpec sub-attributes, in particular
-		// resources and affinity.
-		opts := []cmp.Option{
-			cmp.Comparer(k8sresources.ResourceRequirementsEqual),
-		}
-
-		// ensure that PodTemplateSpec is up to date
-		if !cmp.Equal(existingDeployment.Spec.Template, generatedDeployment.Spec.Template, opts...) {
-			existingDeployment.Spec.Template = generatedDeployment.Spec.Template
-			updated = true
-		}
-
-		// ensure that replication strategy is up to date
-		replicas := params.ControlPlane.Spec.ControlPlaneOptions.Deployment.Replicas
-		switch {
-		case !dataplaneIsSet && (replicas == nil || *replicas != numReplicasWhenNoDataPlane):
-			// DataPlane was just unset, so we need to scale down the Deployment.
-			if !cmp.Equal(existingDeployment.Spec.Replicas, lo.ToPtr(int32(numReplicasWhenNoDataPlane))) {
-				existingDeployment.Spec.Replicas = lo.ToPtr(int32(numReplicasWhenNoDataPlane))
-				updated = true
-			}
-		case dataplaneIsSet && (replicas != nil && *replicas != numReplicasWhenNoDataPlane):
-			// DataPlane was just set, so we need to scale up the Deployment
-			// and ensure the env variables that might have been changed in
-			// deployment are updated.
-			if !cmp.Equal(existingDeployment.Spec.Replicas, replicas) {
-				existingDeployment.Spec.Replicas = replicas
-				updated = true
-			}
-		}
-
+		updated := updateControlPlaneDeployment(params.ControlPlane, existingDeployment, generatedDeployment, dataplaneIsSet)
 		return patch.ApplyPatchIfNotEmpty(ctx, r.Client, logger, existingDeployment, oldExistingDeployment, updated)
 	}
 
@@ -271,6 +236,88 @@ func (r *Reconciler) ensureDeployment(
 	return op.Created, generatedDeployment, nil
 }
 
+// controlPlaneDeploymentSpecHashMatches reports whether the spec hash annotation
+// of the existing Deployment matches the hash of the ControlPlane spec. It also
+// returns the calculated hash.
+func controlPlaneDeploymentSpecHashMatches(
+	cp *operatorv1beta1.ControlPlane,
+	existing *appsv1.Deployment,
+) (bool, string, error) {
+	hash, err := k8sresources.CalculateHash(cp.Spec)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to calculate hash spec from ControlPlane: %w", err)
+	}
+	h, ok := existing.GetAnnotations()[consts.AnnotationPodTemplateSpecHash]
+	return ok && h == hash, hash, nil
+}
+
+// updateControlPlaneDeployment updates the existing ControlPlane Deployment in
+// place with the fields of the generated one that the operator enforces. It
+// returns true when the existing Deployment was changed.
+func updateControlPlaneDeployment(
+	cp *operatorv1beta1.ControlPlane,
+	existing *appsv1.Deployment,
+	generated *appsv1.Deployment,
+	dataplaneIsSet bool,
+) bool {
+	var updated bool
+
+	// ensure that object metadata is up to date
+	updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, generated.ObjectMeta)
+
+	// some custom comparison rules are needed for some PodTemplateSpec sub-attributes, in particular
+	// resources and affinity.
+	opts := []cmp.Option{
+		cmp.Comparer(k8sresources.ResourceRequirementsEqual),
+	}
+
+	// ensure that PodTemplateSpec is up to date
+	if !cmp.Equal(existing.Spec.Template, generated.Spec.Template, opts...) {
+		existing.Spec.Template = generated.Spec.Template
+		updated = true
+	}
+
+	// ensure that replication strategy is up to date
+	replicas := cp.Spec.ControlPlaneOptions.Deployment.Replicas
+	switch {
+	case !dataplaneIsSet && (replicas == nil || *replicas != numReplicasWhenNoDataPlane):
+		// DataPlane was just unset, so we need to scale down the Deployment.
+		if !cmp.Equal(existing.Spec.Replicas, lo.ToPtr(int32(numReplicasWhenNoDataPlane))) {
+			existing.Spec.Replicas = lo.ToPtr(int32(numReplicasWhenNoDataPlane))
+			updated = true
+		}
+	case dataplaneIsSet && (replicas != nil && *replicas != numReplicasWhenNoDataPlane):
+		// DataPlane was just set, so we need to scale up the Deployment
+		// and ensure the env variables that might have been changed in
+		// deployment are updated.
+		if !cmp.Equal(existing.Spec.Replicas, replicas) {
+			existing.Spec.Replicas = replicas
+			updated = true
+		}
+	}
+
+	return updated
+}
+
+// generateDeployment generates the Deployment for the ControlPlane resource.
+func (r *Reconciler) generateDeployment(params ensureDeploymentParams) (*appsv1.Deployment, error) {
+	versionValidationOptions := make([]versions.VersionValidationOption, 0)
+	if !r.DevelopmentMode {
+		versionValidationOptions = append(versionValidationOptions, versions.IsControlPlaneImageVersionSupported)
+	}
+	controlplaneImage, err := controlplane.GenerateImage(&params.ControlPlane.Spec.ControlPlaneOptions, versionValidationOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return k8sresources.GenerateNewDeploymentForControlPlane(k8sresources.GenerateNewDeploymentForControlPlaneParams{
+		ControlPlane:                   params.ControlPlane,
+		ControlPlaneImage:              controlplaneImage,
+		ServiceAccountName:             params.ServiceAccountName,
+		AdminMTLSCertSecretName:        params.AdminMTLSCertSecretName,
+		AdmissionWebhookCertSecretName: params.AdmissionWebhookCertSecretName,
+	})
+}
+
 func (r *Reconciler) ensureServiceAccount(
 	ctx context.Context,
 	cp *operatorv1beta1.ControlPlane,
@@ -434,6 +481,137 @@ func (r *Reconciler) ensureClusterRoleBinding(
 	return true, generated, r.Client.Create(ctx, generated)
 }
 
+func (r *Reconciler) ensureRole(
+	ctx context.Context,
+	cp *operatorv1beta1.ControlPlane,
+) (createdOrUpdated bool, role *rbacv1.Role, err error) {
+	roles, err := k8sutils.ListRolesForOwner(
+		ctx,
+		r.Client,
+		cp.Namespace,
+		cp.UID,
+		client.MatchingLabels{
+			consts.GatewayOperatorManagedByLabel: consts.ControlPlaneManagedLabelValue,
+		},
+	)
+	if err != nil {
+		return false, nil, err
+	}
+
+	count := len(roles)
+	if count > 1 {
+		if err := k8sreduce.ReduceRoles(ctx, r.Client, roles); err != nil {
+			return false, nil, err
+		}
+		return false, nil, errors.New("number of roles reduced")
+	}
+
+	controlplaneContainer := k8sutils.GetPodContainerByName(&cp.Spec.Deployment.PodTemplateSpec.Spec, consts.ControlPlaneControllerContainerName)
+	generated, err := k8sresources.GenerateNewRoleForControlPlane(cp.Namespace, cp.Name, controlplaneContainer.Image, r.DevelopmentMode)
+	if err != nil {
+		return false, nil, err
+	}
+	k8sutils.SetOwnerForObject(generated, cp)
+
+	if count == 1 {
+		var (
+			updated  bool
+			existing = &roles[0]
+			old      = existing.DeepCopy()
+		)
+
+		updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, generated.ObjectMeta)
+		if updated || !cmp.Equal(existing.Rules, generated.Rules) {
+			existing.Rules = generated.Rules
+			if err := r.Client.Patch(ctx, existing, client.MergeFrom(old)); err != nil {
+				return false, existing, fmt.Errorf("failed patching ControlPlane's Role %s: %w", existing.Name, err)
+			}
+			return true, existing, nil
+		}
+		return false, existing, nil
+	}
+
+	return true, generated, r.Client.Create(ctx, generated)
+}
+
+func (r *Reconciler) ensureRoleBinding(
+	ctx context.Context,
+	cp *operatorv1beta1.ControlPlane,
+	serviceAccountName string,
+	roleName string,
+) (createdOrUpdate bool, rb *rbacv1.RoleBinding, err error) {
+	logger := log.GetLogger(ctx, "controlplane.ensureRoleBinding", r.DevelopmentMode)
+
+	roleBindings, err := k8sutils.ListRoleBindingsForOwner(
+		ctx,
+		r.Client,
+		cp.Namespace,
+		cp.UID,
+		client.MatchingLabels{
+			consts.GatewayOperatorManagedByLabel: consts.ControlPlaneManagedLabelValue,
+		},
+	)
+	if err != nil {
+		return false, nil, err
+	}
+
+	count := len(roleBindings)
+	if count > 1 {
+		if err := k8sreduce.ReduceRoleBindings(ctx, r.Client, roleBindings); err != nil {
+			return false, nil, err
+		}
+		return false, nil, errors.New("number of roleBindings reduced")
+	}
+
+	generated := k8sresources.GenerateNewRoleBindingForControlPlane(cp.Namespace, cp.Name, serviceAccountName, roleName)
+	k8sutils.SetOwnerForObject(generated, cp)
+
+	if count == 1 {
+		existing := &roleBindings[0]
+		// Delete and re-create RoleBinding if name of Role changed because RoleRef is immutable.
+		if !k8sresources.CompareRoleName(existing, roleName) {
+			log.Debug(logger, "Role name changed, delete and re-create a RoleBinding",
+				"old_role", existing.RoleRef.Name,
+				"new_role", roleName,
+			)
+			if err := r.Client.Delete(ctx, existing); err != nil {
+				return false, nil, err
+			}
+			return false, nil, errors.New("name of Role changed, out of date RoleBinding deleted")
+		}
+
+		var (
+			old                   = existing.DeepCopy()
+			updated               bool
+			updatedServiceAccount bool
+		)
+		updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, generated.ObjectMeta)
+
+		if !k8sresources.RoleBindingContainsServiceAccount(existing, cp.Namespace, serviceAccountName) {
+			existing.Subjects = generated.Subjects
+			updatedServiceAccount = true
+		}
+
+		if updated || updatedServiceAccount {
+			if err := r.Client.Patch(ctx, existing, client.MergeFrom(old)); err != nil {
+				return false, existing, fmt.Errorf("failed patching ControlPlane's RoleBinding %s: %w", existing.Name, err)
+			}
+			return true, existing, nil
+		}
+		return false, existing, nil
+	}
+
+	return true, generated, r.Client.Create(ctx, generated)
+}
+
+// hasClusterResourcesFinalizers returns true if the ControlPlane has any of
+// the finalizers used to clean up the cluster wide resources it owns.
+func hasClusterResourcesFinalizers(cp *operatorv1beta1.ControlPlane) bool {
+	return controllerutil.ContainsFinalizer(cp, string(ControlPlaneFinalizerCleanupClusterRole)) ||
+		controllerutil.ContainsFinalizer(cp, string(ControlPlaneFinalizerCleanupClusterRoleBinding)) ||
+		controllerutil.ContainsFinalizer(cp, string(ControlPlaneFinalizerCleanupValidatingWebhookConfiguration))
+}
+
 // ensureAdminMTLSCertificateSecret ensures that a Secret is created with the certificate for mTLS communication between the
 // ControlPlane and the DataPlane.
 func (r *Reconciler) ensureAdminMTLSCertificateSecret(
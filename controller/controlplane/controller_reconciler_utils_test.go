@@ -200,3 +200,73 @@ func Test_ensureValidatingWebhookConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func Test_ensureRoleAndRoleBinding(t *testing.T) {
+	cp := &operatorv1beta1.ControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway-operator.konghq.com/v1beta1",
+			Kind:       "ControlPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cp",
+			Namespace: "ns",
+			UID:       "cp-uid",
+		},
+		Spec: operatorv1beta1.ControlPlaneSpec{
+			ControlPlaneOptions: operatorv1beta1.ControlPlaneOptions{
+				Deployment: operatorv1beta1.ControlPlaneDeploymentOptions{
+					PodTemplateSpec: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  consts.ControlPlaneControllerContainerName,
+									Image: consts.DefaultControlPlaneImage,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(cp).
+		Build()
+	r := &Reconciler{
+		Client:          fakeClient,
+		WatchNamespaces: []string{"ns"},
+	}
+	ctx := t.Context()
+
+	createdOrUpdated, role, err := r.ensureRole(ctx, cp)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.Equal(t, "ns", role.Namespace)
+	require.NotEmpty(t, role.Rules)
+
+	createdOrUpdated, existingRole, err := r.ensureRole(ctx, cp)
+	require.NoError(t, err)
+	require.False(t, createdOrUpdated)
+	require.Equal(t, role.Name, existingRole.Name)
+
+	createdOrUpdated, rb, err := r.ensureRoleBinding(ctx, cp, "sa", role.Name)
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.True(t, k8sresources.CompareRoleName(rb, role.Name))
+	require.True(t, k8sresources.RoleBindingContainsServiceAccount(rb, "ns", "sa"))
+
+	createdOrUpdated, _, err = r.ensureRoleBinding(ctx, cp, "sa", role.Name)
+	require.NoError(t, err)
+	require.False(t, createdOrUpdated)
+
+	t.Log("changing the referenced Role re-creates the RoleBinding")
+	_, _, err = r.ensureRoleBinding(ctx, cp, "sa", "other-role")
+	require.Error(t, err)
+	createdOrUpdated, rb, err = r.ensureRoleBinding(ctx, cp, "sa", "other-role")
+	require.NoError(t, err)
+	require.True(t, createdOrUpdated)
+	require.True(t, k8sresources.CompareRoleName(rb, "other-role"))
+}
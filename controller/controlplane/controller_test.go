@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -463,3 +465,67 @@ func TestReconciler_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconciler_ReconcileNamespaceScopedDeletion(t *testing.T) {
+	cp := &operatorv1beta1.ControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway-operator.konghq.com/v1beta1",
+			Kind:       "ControlPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cp",
+			Namespace:         "ns",
+			UID:               types.UID(uuid.NewString()),
+			DeletionTimestamp: lo.ToPtr(metav1.NewTime(metav1.Now().Add(-time.Minute))),
+			Finalizers: []string{
+				string(ControlPlaneFinalizerCleanupClusterRole),
+				string(ControlPlaneFinalizerCleanupClusterRoleBinding),
+				string(ControlPlaneFinalizerCleanupValidatingWebhookConfiguration),
+			},
+		},
+	}
+	// Cluster wide resources created before the operator was restricted to namespaces.
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cp-cluster-role",
+			Labels: k8sutils.GetManagedByLabelSet(cp),
+		},
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cp-cluster-role-binding",
+			Labels: k8sutils.GetManagedByLabelSet(cp),
+		},
+	}
+
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(cp, clusterRole, clusterRoleBinding).
+		Build()
+	r := &Reconciler{
+		Client:          fakeClient,
+		WatchNamespaces: []string{"ns"},
+	}
+	ctx := t.Context()
+	req := reconcile.Request{NamespacedName: controllerruntimeclient.ObjectKeyFromObject(cp)}
+
+	for range 10 {
+		res, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+		if res.IsZero() {
+			err := fakeClient.Get(ctx, req.NamespacedName, &operatorv1beta1.ControlPlane{})
+			if k8serrors.IsNotFound(err) {
+				break
+			}
+			require.NoError(t, err)
+		}
+	}
+
+	require.True(t, k8serrors.IsNotFound(fakeClient.Get(ctx, req.NamespacedName, &operatorv1beta1.ControlPlane{})),
+		"ControlPlane should be deleted once its finalizers are removed")
+	require.True(t, k8serrors.IsNotFound(fakeClient.Get(ctx, controllerruntimeclient.ObjectKeyFromObject(clusterRole), &rbacv1.ClusterRole{})),
+		"ClusterRole should be deleted before its finalizer is removed")
+	require.True(t, k8serrors.IsNotFound(fakeClient.Get(ctx, controllerruntimeclient.ObjectKeyFromObject(clusterRoleBinding), &rbacv1.ClusterRoleBinding{})),
+		"ClusterRoleBinding should be deleted before its finalizer is removed")
+}
@@ -0,0 +1,131 @@
+package controlplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/controlplane"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/pkg/consts"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// PlanOptions configures how the resources owned by a ControlPlane are planned.
+// The options mirror the ControlPlane controller's configuration.
+type PlanOptions struct {
+	// DevelopmentMode disables the ControlPlane image version validation.
+	DevelopmentMode bool
+	// EnforceConfig makes the plan compare the Deployment even when the
+	// ControlPlane spec hash did not change.
+	EnforceConfig bool
+	// WatchNamespaces is the set of namespaces the operator is restricted to.
+	WatchNamespaces []string
+}
+
+// OwnedResourcesPlan holds the live and the desired state of the resources
+// owned by a ControlPlane. The live Deployment is nil when it does not exist
+// yet. The desired Deployment is the live one with the fields enforced by the
+// operator updated or the newly generated one when it does not exist yet.
+type OwnedResourcesPlan struct {
+	LiveDeployment    *appsv1.Deployment
+	DesiredDeployment *appsv1.Deployment
+}
+
+// PlanOwnedResources sets the defaults of the provided ControlPlane and runs the
+// ControlPlane Deployment generator and update rules without changing anything
+// in the cluster.
+//
+// cp is expected to be the live ControlPlane with the proposed spec set so that
+// its UID can be used to find the owned resources. ControlPlane extensions are
+// not applied.
+func PlanOwnedResources(
+	ctx context.Context,
+	cl client.Client,
+	cp *operatorv1beta1.ControlPlane,
+	opts PlanOptions,
+) (OwnedResourcesPlan, error) {
+	r := &Reconciler{
+		Client:          cl,
+		DevelopmentMode: opts.DevelopmentMode,
+		WatchNamespaces: opts.WatchNamespaces,
+	}
+	cp = cp.DeepCopy()
+
+	var dataplaneIngressServiceName, dataplaneAdminServiceName string
+	dataplane, err := gatewayutils.GetDataPlaneForControlPlane(ctx, cl, cp)
+	if err != nil {
+		if !errors.Is(err, operatorerrors.ErrDataPlaneNotSet) {
+			return OwnedResourcesPlan{}, err
+		}
+	} else {
+		dataplaneIngressServiceName, err = gatewayutils.GetDataPlaneServiceName(ctx, cl, dataplane, consts.DataPlaneIngressServiceLabelValue)
+		if err != nil {
+			return OwnedResourcesPlan{}, err
+		}
+		dataplaneAdminServiceName, err = gatewayutils.GetDataPlaneServiceName(ctx, cl, dataplane, consts.DataPlaneAdminServiceLabelValue)
+		if err != nil {
+			return OwnedResourcesPlan{}, err
+		}
+	}
+	_ = controlplane.SetDefaults(
+		&cp.Spec.ControlPlaneOptions,
+		r.defaultsArgs(cp, dataplaneIngressServiceName, dataplaneAdminServiceName))
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(ctx, cl, cp.Namespace, cp.UID,
+		client.MatchingLabels{
+			consts.GatewayOperatorManagedByLabel: consts.ControlPlaneManagedLabelValue,
+		},
+	)
+	if err != nil {
+		return OwnedResourcesPlan{}, err
+	}
+	deployments = lo.Reject(deployments, func(d appsv1.Deployment, _ int) bool {
+		return isPreviewDeployment(&d)
+	})
+
+	// The ServiceAccount and the certificates are not reconciled by the plan,
+	// the ones used by the live Deployment are kept.
+	params := ensureDeploymentParams{ControlPlane: cp}
+	var live *appsv1.Deployment
+	if len(deployments) > 0 {
+		live = &deployments[0]
+		podSpec := live.Spec.Template.Spec
+		params.ServiceAccountName = podSpec.ServiceAccountName
+		params.AdminMTLSCertSecretName = k8sutils.GetSecretVolumeSecretName(podSpec, consts.ClusterCertificateVolume)
+		params.AdmissionWebhookCertSecretName = k8sutils.GetSecretVolumeSecretName(podSpec, consts.ControlPlaneAdmissionWebhookVolumeName)
+	}
+
+	generated, err := r.generateDeployment(params)
+	if err != nil {
+		return OwnedResourcesPlan{}, fmt.Errorf("could not generate Deployment for ControlPlane %s/%s: %w", cp.Namespace, cp.Name, err)
+	}
+
+	dataplaneIsSet := cp.Spec.DataPlane != nil && *cp.Spec.DataPlane != ""
+	if live == nil {
+		if !dataplaneIsSet {
+			generated.Spec.Replicas = lo.ToPtr(int32(numReplicasWhenNoDataPlane))
+		}
+		return OwnedResourcesPlan{DesiredDeployment: generated}, nil
+	}
+
+	desired := live.DeepCopy()
+	if !opts.EnforceConfig {
+		matches, _, err := controlPlaneDeploymentSpecHashMatches(cp, live)
+		if err != nil {
+			return OwnedResourcesPlan{}, err
+		}
+		if matches {
+			return OwnedResourcesPlan{LiveDeployment: live, DesiredDeployment: desired}, nil
+		}
+	}
+	updateControlPlaneDeployment(cp, desired, generated, dataplaneIsSet)
+	return OwnedResourcesPlan{LiveDeployment: live, DesiredDeployment: desired}, nil
+}
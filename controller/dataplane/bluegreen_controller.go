@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
@@ -13,8 +14,10 @@ import (
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -22,11 +25,17 @@ import (
 	"github.com/kong/gateway-operator/controller/pkg/address"
 	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
 	"github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
@@ -70,6 +79,10 @@ type BlueGreenReconciler struct {
 
 	ContextInjector ctxinjector.CtxInjector
 
+	// EventRecorder records the Events emitted for the DataPlanes, e.g. during
+	// their promotion.
+	EventRecorder record.EventRecorder
+
 	DefaultImage string
 
 	KonnectEnabled bool
@@ -83,9 +96,20 @@ func (r *BlueGreenReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 	if !ok {
 		return fmt.Errorf("incorrect delegate controller type: %T", r.DataPlaneController)
 	}
-	delegate.eventRecorder = mgr.GetEventRecorderFor("dataplane")
-	return DataPlaneWatchBuilder(mgr, r.KonnectEnabled).
-		Complete(r)
+	if delegate.EventRecorder == nil {
+		delegate.EventRecorder = r.EventRecorder
+	}
+	b := DataPlaneWatchBuilder(mgr, r.KonnectEnabled)
+	// DataPlanes managed for a paused Gateway are paused as well.
+	if err := pause.WatchPausedOwners(mgr, b, &gwtypes.Gateway{}, &operatorv1beta1.DataPlaneList{}); err != nil {
+		return err
+	}
+	return b.Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("DataPlaneBlueGreen",
+		pause.NewReconciler(r.Client, "DataPlane", pause.ConditionsAware[*operatorv1beta1.DataPlane], r,
+			pause.WithPausedOwners(&gwtypes.Gateway{}),
+		),
+		tracing.WithWriterLinks(r.Client, &operatorv1beta1.DataPlane{}),
+	)))
 }
 
 // -----------------------------------------------------------------------------
@@ -96,8 +120,12 @@ func (r *BlueGreenReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// Calling it here ensures that evaluated values will be used for the duration of this function.
 	ctx = r.ContextInjector.InjectKeyValues(ctx)
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	var dataplane operatorv1beta1.DataPlane
 	if err := r.Client.Get(ctx, req.NamespacedName, &dataplane); err != nil {
+		if k8serrors.IsNotFound(err) {
+			metrics.DeleteManagedCertificateExpiryForOwner("DataPlane", req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -212,6 +240,22 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
+	// Ensure "preview" additional ingress services.
+	res, _, err = ensureAdditionalIngressServicesForDataPlane(
+		ctx,
+		logger,
+		r.Client,
+		&dataplane,
+		consts.DataPlaneStateLabelValuePreview,
+		labelSelectorFromDataPlaneRolloutStatusSelectorServiceOpt(&dataplane),
+	)
+	if err != nil {
+		cErr := r.ensureRolledOutCondition(ctx, logger, &dataplane, metav1.ConditionFalse, kcfgdataplane.DataPlaneConditionReasonRolloutFailed, "failed to ensure preview additional ingress Services")
+		return ctrl.Result{}, fmt.Errorf("failed ensuring preview additional ingress services for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, errors.Join(cErr, err))
+	} else if res != op.Noop {
+		return ctrl.Result{}, nil // dataplane additional ingress services modification will trigger reconciliation
+	}
+
 	// Ensure "preview" Deployment.
 	deployment, res, err := r.ensureDeploymentForDataPlane(ctx, logger, &dataplane, certSecret)
 	if err != nil {
@@ -283,6 +327,15 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			return r.DataPlaneController.Reconcile(ctx, req)
 		}
 	}
+	if ok, err := r.waitForLiveAdditionalIngressServicesSelectorPropagation(ctx,
+		&dataplane,
+		expectedLiveSelector,
+	); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed waiting for live additional ingress services to have the expected selector: %w", err)
+	} else if !ok {
+		log.Debug(logger, "live additional ingress services do not have the expected selector yet, delegating to DP controller")
+		return r.DataPlaneController.Reconcile(ctx, req)
+	}
 
 	{
 		// Promotion is effectively done (live services point to the preview).
@@ -467,6 +520,12 @@ func (r *BlueGreenReconciler) prunePreviewSubresources(
 		}
 	}
 
+	if len(deployments) > 0 || len(services) > 0 {
+		events.FromContext(ctx).Event(dataplane, corev1.EventTypeNormal, events.ReasonRolledBack,
+			"BlueGreen rollout strategy has been removed, preview resources have been pruned",
+		)
+	}
+
 	return nil
 }
 
@@ -638,9 +697,53 @@ func (r *BlueGreenReconciler) ensureRolledOutCondition(
 	if err != nil {
 		return fmt.Errorf("failed patching Rollout Status Conditions for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
 	}
+	if !ok || c.Reason != string(reason) || c.Message != message {
+		recordRolledOutConditionEvent(ctx, dataplane, reason, message)
+	}
+	if ok && c.Reason != string(reason) {
+		recordRolledOutConditionMetrics(c, reason)
+	}
 	return nil
 }
 
+// recordRolledOutConditionMetrics records the duration of the rollout phase
+// which has ended with the change of the RolledOut condition from the previous
+// one, and the outcome of the rollout if the new reason finishes it.
+func recordRolledOutConditionMetrics(previous metav1.Condition, reason kcfgconsts.ConditionReason) {
+	metrics.RecordDataPlaneRolloutPhase(previous.Reason, time.Since(previous.LastTransitionTime.Time))
+
+	switch reason {
+	case kcfgdataplane.DataPlaneConditionReasonRolloutPromotionDone:
+		metrics.RecordDataPlaneRolloutOutcome(metrics.DataPlaneRolloutOutcomePromoted)
+	case kcfgdataplane.DataPlaneConditionReasonRolloutPromotionFailed:
+		metrics.RecordDataPlaneRolloutOutcome(metrics.DataPlaneRolloutOutcomePromotionFailed)
+	case kcfgdataplane.DataPlaneConditionReasonRolloutFailed:
+		metrics.RecordDataPlaneRolloutOutcome(metrics.DataPlaneRolloutOutcomeRolloutFailed)
+	}
+}
+
+// recordRolledOutConditionEvent emits an Event for the DataPlane, using the
+// recorder from the context, when its RolledOut condition changes to a reason
+// marking a step of the promotion or a failure.
+func recordRolledOutConditionEvent(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	reason kcfgconsts.ConditionReason,
+	message string,
+) {
+	recorder := events.FromContext(ctx)
+	switch reason {
+	case kcfgdataplane.DataPlaneConditionReasonRolloutPromotionInProgress:
+		recorder.Event(dataplane, corev1.EventTypeNormal, events.ReasonPromotionStarted, "Promoting preview resources to live")
+	case kcfgdataplane.DataPlaneConditionReasonRolloutPromotionDone:
+		recorder.Event(dataplane, corev1.EventTypeNormal, events.ReasonPromoted, "Preview resources have been promoted to live")
+	case kcfgdataplane.DataPlaneConditionReasonRolloutPromotionFailed:
+		recorder.Event(dataplane, corev1.EventTypeWarning, events.ReasonPromotionFailed, message)
+	case kcfgdataplane.DataPlaneConditionReasonRolloutFailed:
+		recorder.Event(dataplane, corev1.EventTypeWarning, events.ReasonRolloutFailed, message)
+	}
+}
+
 // labelSelectorFromDataPlaneRolloutStatusSelectorDeploymentOpt returns a DeploymentOpt
 // function which will set Deployment's selector and spec template labels, based
 // on provided DataPlane's Rollout Status selector field.
@@ -915,6 +1018,43 @@ func (r *BlueGreenReconciler) waitForLiveServiceSelectorsPropagation(
 	return ok, nil
 }
 
+// waitForLiveAdditionalIngressServicesSelectorPropagation waits for all the live
+// additional ingress services to have the expected selector.
+// It's used during promotion, next to waitForLiveServiceSelectorsPropagation.
+func (r *BlueGreenReconciler) waitForLiveAdditionalIngressServicesSelectorPropagation(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	expectedSelector map[string]string,
+) (ok bool, err error) {
+	servicesOpts, err := k8sresources.GetDataPlaneAdditionalIngressServices(dataplane)
+	if err != nil {
+		return false, err
+	}
+
+	services, err := k8sutils.ListServicesForOwner(
+		ctx,
+		r.Client,
+		dataplane.Namespace,
+		dataplane.UID,
+		client.MatchingLabels{
+			"app":                                dataplane.Name,
+			consts.DataPlaneServiceTypeLabel:     string(consts.DataPlaneAdditionalIngressServiceLabelValue),
+			consts.DataPlaneServiceStateLabel:    consts.DataPlaneStateLabelValueLive,
+			consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+		},
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed listing live additional ingress services for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+
+	if len(services) != len(servicesOpts) {
+		return false, nil
+	}
+	return lo.EveryBy(services, func(svc corev1.Service) bool {
+		return cmp.Equal(svc.Spec.Selector, expectedSelector)
+	}), nil
+}
+
 // ensurePreviewDeploymentLabeledLive ensures that the preview deployment with a given selector is labeled as live.
 // It's used to mark the preview deployment as live during promotion.
 func (r *BlueGreenReconciler) ensurePreviewDeploymentLabeledLive(
@@ -0,0 +1,343 @@
+package dataplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlane - ConfigurationSynced Condition Constants
+// -----------------------------------------------------------------------------
+
+const (
+	// ConfigurationSyncedType indicates whether all the ready DataPlane Pods
+	// have the configuration pushed by the ControlPlane loaded.
+	ConfigurationSyncedType kcfgconsts.ConditionType = "ConfigurationSynced"
+
+	// ConfigurationSyncedReasonSynced indicates that all the ready DataPlane
+	// Pods have the expected configuration loaded.
+	ConfigurationSyncedReasonSynced kcfgconsts.ConditionReason = "Synced"
+	// ConfigurationSyncedReasonOutdated indicates that some of the ready
+	// DataPlane Pods have a configuration that differs from the expected one.
+	ConfigurationSyncedReasonOutdated kcfgconsts.ConditionReason = "ConfigurationOutdated"
+	// ConfigurationSyncedReasonNotLoaded indicates that some of the ready
+	// DataPlane Pods have no configuration loaded yet.
+	ConfigurationSyncedReasonNotLoaded kcfgconsts.ConditionReason = "ConfigurationNotLoaded"
+	// ConfigurationSyncedReasonUnknown indicates that the configuration of the
+	// DataPlane Pods could not be determined, e.g. because there are no ready
+	// Pods or none of them could be reached.
+	ConfigurationSyncedReasonUnknown kcfgconsts.ConditionReason = "Unknown"
+)
+
+// emptyConfigHash is the configuration hash reported by Kong when it has no
+// configuration loaded.
+const emptyConfigHash = "00000000000000000000000000000000"
+
+// configHashRequestTimeout is the timeout for the requests sent to the
+// DataPlane Pods' status endpoint.
+const configHashRequestTimeout = 5 * time.Second
+
+// maxConcurrentConfigHashRequests is the maximum number of DataPlane Pods
+// queried concurrently for their configuration hash.
+const maxConcurrentConfigHashRequests = 10
+
+// maxOutdatedPodsInMessage is the maximum number of outdated Pods listed in
+// the ConfigurationSynced condition message.
+const maxOutdatedPodsInMessage = 5
+
+// -----------------------------------------------------------------------------
+// DataPlane - Pods configuration hash
+// -----------------------------------------------------------------------------
+
+// getConfigHash returns the configHashGetter used by the Reconciler.
+func (r *Reconciler) getConfigHash() configHashGetter {
+	if r.configHashGetter != nil {
+		return r.configHashGetter
+	}
+	return configHashFromStatusEndpoint(&http.Client{Timeout: configHashRequestTimeout})
+}
+
+// configHashGetter returns the hash of the Kong configuration loaded by the
+// provided DataPlane Pod.
+type configHashGetter func(ctx context.Context, pod *corev1.Pod) (string, error)
+
+// configHashFromStatusEndpoint returns a configHashGetter which queries the
+// status endpoint of the DataPlane Pods for the loaded configuration hash.
+// The status endpoint port is the one used by the proxy container's readiness
+// probe, which defaults to consts.DataPlaneStatusPort.
+func configHashFromStatusEndpoint(httpClient *http.Client) configHashGetter {
+	return func(ctx context.Context, pod *corev1.Pod) (string, error) {
+		port := dataPlanePodStatusPort(pod)
+		url := fmt.Sprintf("http://%s%s", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(port)), consts.DataPlaneStatusEndpoint)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		}
+
+		var status struct {
+			ConfigurationHash string `json:"configuration_hash"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+			return "", fmt.Errorf("failed decoding response from %s: %w", url, err)
+		}
+		return status.ConfigurationHash, nil
+	}
+}
+
+// dataPlanePodStatusPort returns the port of the status endpoint of the
+// provided DataPlane Pod.
+func dataPlanePodStatusPort(pod *corev1.Pod) int {
+	container := k8sutils.GetPodContainerByName(&pod.Spec, consts.DataPlaneProxyContainerName)
+	if container == nil ||
+		container.ReadinessProbe == nil ||
+		container.ReadinessProbe.HTTPGet == nil {
+		return consts.DataPlaneStatusPort
+	}
+
+	port := container.ReadinessProbe.HTTPGet.Port
+	if port.Type == intstr.Int {
+		return port.IntValue()
+	}
+	for _, p := range container.Ports {
+		if p.Name == port.StrVal {
+			return int(p.ContainerPort)
+		}
+	}
+	return consts.DataPlaneStatusPort
+}
+
+// ensureDataPlaneConfigurationSyncedStatus queries the ready "live" DataPlane
+// Pods for their loaded configuration hash, annotates each Pod with it (see
+// consts.DataPlanePodConfigHashAnnotation) and sets the ConfigurationSynced
+// condition on the DataPlane accordingly.
+//
+// The Pods are compared against the hash set with the
+// consts.DataPlaneExpectedConfigHashAnnotation annotation when present.
+// Otherwise, as the DataPlane itself does not know which configuration is
+// pushed to it, the configuration loaded by the majority of the Pods is
+// considered to be the expected one. Pods with a different configuration or
+// with no configuration loaded at all are reported.
+func ensureDataPlaneConfigurationSyncedStatus(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+	getConfigHash configHashGetter,
+) error {
+	deployments, err := listDataPlaneLiveDeployments(ctx, cl, dataplane)
+	if err != nil {
+		return fmt.Errorf("failed listing deployments for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	if len(deployments) != 1 {
+		return nil
+	}
+
+	var podList corev1.PodList
+	if err := cl.List(ctx, &podList,
+		client.InNamespace(dataplane.Namespace),
+		client.MatchingLabels(deployments[0].Spec.Selector.MatchLabels),
+	); err != nil {
+		return fmt.Errorf("failed listing Pods for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	pods := lo.Filter(podList.Items, func(pod corev1.Pod, _ int) bool {
+		return pod.DeletionTimestamp.IsZero() && pod.Status.PodIP != "" && k8sutils.IsPodReady(&pod)
+	})
+
+	podHashes, unreachable := getPodsConfigHashes(ctx, logger, pods, getConfigHash)
+	for i := range pods {
+		hash, ok := podHashes[pods[i].Name]
+		if !ok {
+			continue
+		}
+		if err := ensureDataPlanePodConfigHashAnnotation(ctx, cl, &pods[i], hash); err != nil {
+			return err
+		}
+	}
+
+	expectedHash := dataplane.Annotations[consts.DataPlaneExpectedConfigHashAnnotation]
+	if expectedHash == "" {
+		expectedHash = mostCommonConfigHash(podHashes)
+	}
+	var (
+		outdated  = make([]string, 0, len(podHashes))
+		notLoaded = make([]string, 0, len(podHashes))
+	)
+	for name, hash := range podHashes {
+		switch {
+		case hash == "" || hash == emptyConfigHash:
+			notLoaded = append(notLoaded, name)
+		case hash != expectedHash:
+			outdated = append(outdated, name)
+		}
+	}
+	sort.Strings(outdated)
+	sort.Strings(notLoaded)
+
+	var (
+		status  = metav1.ConditionTrue
+		reason  = ConfigurationSyncedReasonSynced
+		message string
+	)
+	switch {
+	case len(podHashes) == 0:
+		status = metav1.ConditionUnknown
+		reason = ConfigurationSyncedReasonUnknown
+		message = "No ready DataPlane Pods reported their configuration"
+	case len(outdated) > 0:
+		status = metav1.ConditionFalse
+		reason = ConfigurationSyncedReasonOutdated
+		message = fmt.Sprintf("%d/%d ready DataPlane Pods have outdated configuration: %s",
+			len(outdated)+len(notLoaded), len(podHashes), joinPodNames(append(outdated, notLoaded...)))
+	case len(notLoaded) > 0:
+		status = metav1.ConditionFalse
+		reason = ConfigurationSyncedReasonNotLoaded
+		message = fmt.Sprintf("%d/%d ready DataPlane Pods have no configuration loaded: %s",
+			len(notLoaded), len(podHashes), joinPodNames(notLoaded))
+	default:
+		message = fmt.Sprintf("%d/%d ready DataPlane Pods have configuration %s loaded",
+			len(podHashes), len(podHashes), expectedHash)
+	}
+	if len(unreachable) > 0 {
+		sort.Strings(unreachable)
+		message = fmt.Sprintf("%s; failed to get configuration from %d Pods: %s",
+			message, len(unreachable), joinPodNames(unreachable))
+	}
+
+	condition := k8sutils.NewConditionWithGeneration(
+		ConfigurationSyncedType,
+		status,
+		reason,
+		message,
+		dataplane.Generation,
+	)
+	if current, ok := k8sutils.GetCondition(ConfigurationSyncedType, dataplane); ok &&
+		current.Status == condition.Status && current.Reason == condition.Reason &&
+		current.Message == condition.Message && current.ObservedGeneration == condition.ObservedGeneration {
+		return nil
+	}
+	k8sutils.SetCondition(condition, dataplane)
+	if _, err := patchDataPlaneStatus(ctx, cl, logger, dataplane); err != nil {
+		return fmt.Errorf("failed patching ConfigurationSynced condition for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	return nil
+}
+
+// ensureDataPlanePodConfigHashAnnotation ensures the provided Pod is annotated
+// with the provided configuration hash.
+func ensureDataPlanePodConfigHashAnnotation(ctx context.Context, cl client.Client, pod *corev1.Pod, hash string) error {
+	if current, ok := pod.Annotations[consts.DataPlanePodConfigHashAnnotation]; ok && current == hash {
+		return nil
+	}
+	old := pod.DeepCopy()
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[consts.DataPlanePodConfigHashAnnotation] = hash
+	if err := cl.Patch(ctx, pod, client.MergeFrom(old)); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed annotating DataPlane Pod %s/%s with configuration hash: %w", pod.Namespace, pod.Name, err)
+	}
+	return nil
+}
+
+// mostCommonConfigHash returns the configuration hash loaded by the highest
+// number of Pods, ignoring the Pods with no configuration loaded. Ties are
+// broken by picking the lexicographically smallest hash so that the result is
+// stable.
+func mostCommonConfigHash(podHashes map[string]string) string {
+	counts := make(map[string]int, len(podHashes))
+	for _, h := range podHashes {
+		if h != "" && h != emptyConfigHash {
+			counts[h]++
+		}
+	}
+	var (
+		hash  string
+		count int
+	)
+	for h, c := range counts {
+		if c > count || (c == count && h < hash) {
+			hash, count = h, c
+		}
+	}
+	return hash
+}
+
+// getPodsConfigHashes queries the provided Pods for their configuration hash,
+// at most maxConcurrentConfigHashRequests at a time. It returns the hashes by
+// Pod name and the names of the Pods that could not be queried.
+func getPodsConfigHashes(
+	ctx context.Context,
+	logger logr.Logger,
+	pods []corev1.Pod,
+	getConfigHash configHashGetter,
+) (map[string]string, []string) {
+	var (
+		mu          sync.Mutex
+		wg          sync.WaitGroup
+		sem         = make(chan struct{}, maxConcurrentConfigHashRequests)
+		podHashes   = make(map[string]string, len(pods))
+		unreachable []string
+	)
+	for i := range pods {
+		pod := &pods[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			hash, err := getConfigHash(ctx, pod)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Debug(logger, "failed getting configuration hash from DataPlane Pod", "pod", pod.Name, "error", err)
+				unreachable = append(unreachable, pod.Name)
+				return
+			}
+			podHashes[pod.Name] = hash
+		}()
+	}
+	wg.Wait()
+	return podHashes, unreachable
+}
+
+// joinPodNames returns a comma separated list of the provided Pod names,
+// truncated to maxOutdatedPodsInMessage names.
+func joinPodNames(names []string) string {
+	if len(names) <= maxOutdatedPodsInMessage {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more",
+		strings.Join(names[:maxOutdatedPodsInMessage], ", "), len(names)-maxOutdatedPodsInMessage)
+}
@@ -0,0 +1,322 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestEnsureDataPlaneConfigurationSyncedStatus(t *testing.T) {
+	const (
+		hashA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		hashB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+
+	dataplane := &operatorv1beta1.DataPlane{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "gateway-operator.konghq.com/v1beta1",
+			Kind:       "DataPlane",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dp",
+			Namespace:  "default",
+			UID:        types.UID("dp-uid"),
+			Generation: 2,
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp-deployment",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":                                dataplane.Name,
+				consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueLive,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": dataplane.Name},
+			},
+		},
+	}
+	k8sutils.SetOwnerForObject(deployment, dataplane)
+
+	pod := func(name string, ready bool) *corev1.Pod {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": dataplane.Name},
+			},
+			Status: corev1.PodStatus{
+				PodIP: "10.0.0.1",
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: readyStatus},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		expectedHash    string
+		pods            []*corev1.Pod
+		podHashes       map[string]string
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "all pods have the expected configuration",
+			expectedHash:    hashA,
+			pods:            []*corev1.Pod{pod("pod-1", true), pod("pod-2", true)},
+			podHashes:       map[string]string{"pod-1": hashA, "pod-2": hashA},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  string(ConfigurationSyncedReasonSynced),
+			expectedMessage: "2/2 ready DataPlane Pods have configuration " + hashA + " loaded",
+		},
+		{
+			name:            "the majority of pods can be outdated",
+			expectedHash:    hashA,
+			pods:            []*corev1.Pod{pod("pod-1", true), pod("pod-2", true), pod("pod-3", true), pod("pod-4", false)},
+			podHashes:       map[string]string{"pod-1": hashA, "pod-2": hashB, "pod-3": hashB, "pod-4": hashB},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(ConfigurationSyncedReasonOutdated),
+			expectedMessage: "2/3 ready DataPlane Pods have outdated configuration: pod-2, pod-3",
+		},
+		{
+			name:            "a single pod with outdated configuration is reported",
+			expectedHash:    hashA,
+			pods:            []*corev1.Pod{pod("pod-1", true)},
+			podHashes:       map[string]string{"pod-1": hashB},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(ConfigurationSyncedReasonOutdated),
+			expectedMessage: "1/1 ready DataPlane Pods have outdated configuration: pod-1",
+		},
+		{
+			name:            "pods with no configuration loaded are reported",
+			pods:            []*corev1.Pod{pod("pod-1", true), pod("pod-2", true)},
+			podHashes:       map[string]string{"pod-1": hashA, "pod-2": emptyConfigHash},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(ConfigurationSyncedReasonNotLoaded),
+			expectedMessage: "1/2 ready DataPlane Pods have no configuration loaded: pod-2",
+		},
+		{
+			name:            "without the expected hash all pods with the same configuration are synced",
+			pods:            []*corev1.Pod{pod("pod-1", true), pod("pod-2", true)},
+			podHashes:       map[string]string{"pod-1": hashA, "pod-2": hashA},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  string(ConfigurationSyncedReasonSynced),
+			expectedMessage: "2/2 ready DataPlane Pods have configuration " + hashA + " loaded",
+		},
+		{
+			name:            "without the expected hash pods differing from the majority are outdated",
+			pods:            []*corev1.Pod{pod("pod-1", true), pod("pod-2", true), pod("pod-3", true)},
+			podHashes:       map[string]string{"pod-1": hashB, "pod-2": hashA, "pod-3": hashB},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(ConfigurationSyncedReasonOutdated),
+			expectedMessage: "1/3 ready DataPlane Pods have outdated configuration: pod-2",
+		},
+		{
+			name:            "without the expected hash ties are broken by the smallest hash",
+			pods:            []*corev1.Pod{pod("pod-1", true), pod("pod-2", true)},
+			podHashes:       map[string]string{"pod-1": hashB, "pod-2": hashA},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(ConfigurationSyncedReasonOutdated),
+			expectedMessage: "1/2 ready DataPlane Pods have outdated configuration: pod-1",
+		},
+		{
+			name:            "unreachable pods are reported",
+			expectedHash:    hashA,
+			pods:            []*corev1.Pod{pod("pod-1", true), pod("pod-2", true)},
+			podHashes:       map[string]string{"pod-1": hashA},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  string(ConfigurationSyncedReasonSynced),
+			expectedMessage: "1/1 ready DataPlane Pods have configuration " + hashA + " loaded; failed to get configuration from 1 Pods: pod-2",
+		},
+		{
+			name:            "no ready pods",
+			expectedHash:    hashA,
+			pods:            []*corev1.Pod{pod("pod-1", false)},
+			expectedStatus:  metav1.ConditionUnknown,
+			expectedReason:  string(ConfigurationSyncedReasonUnknown),
+			expectedMessage: "No ready DataPlane Pods reported their configuration",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+			dp := dataplane.DeepCopy()
+			if tc.expectedHash != "" {
+				dp.Annotations = map[string]string{consts.DataPlaneExpectedConfigHashAnnotation: tc.expectedHash}
+			}
+			objs := []client.Object{dp, deployment.DeepCopy()}
+			for _, p := range tc.pods {
+				objs = append(objs, p.DeepCopy())
+			}
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(objs...).
+				WithStatusSubresource(dataplane).
+				Build()
+
+			getConfigHash := func(_ context.Context, pod *corev1.Pod) (string, error) {
+				hash, ok := tc.podHashes[pod.Name]
+				if !ok {
+					return "", errors.New("connection refused")
+				}
+				return hash, nil
+			}
+
+			var current operatorv1beta1.DataPlane
+			require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(dataplane), &current))
+			require.NoError(t, ensureDataPlaneConfigurationSyncedStatus(ctx, fakeClient, logr.Discard(), &current, getConfigHash))
+
+			var got operatorv1beta1.DataPlane
+			require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(dataplane), &got))
+			c, ok := k8sutils.GetCondition(ConfigurationSyncedType, &got)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedStatus, c.Status)
+			require.Equal(t, tc.expectedReason, c.Reason)
+			require.Equal(t, tc.expectedMessage, c.Message)
+			require.Equal(t, dataplane.Generation, c.ObservedGeneration)
+
+			for _, p := range tc.pods {
+				var gotPod corev1.Pod
+				require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(p), &gotPod))
+				hash, ok := tc.podHashes[p.Name]
+				if !ok || !k8sutils.IsPodReady(p) {
+					require.NotContains(t, gotPod.Annotations, consts.DataPlanePodConfigHashAnnotation)
+					continue
+				}
+				require.Equal(t, hash, gotPod.Annotations[consts.DataPlanePodConfigHashAnnotation])
+			}
+		})
+	}
+}
+
+func TestConfigHashFromStatusEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != consts.DataPlaneStatusEndpoint {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"configuration_hash":"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa","server":{"connections_active":1}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: consts.DataPlaneProxyContainerName,
+					Ports: []corev1.ContainerPort{
+						{Name: "metrics", ContainerPort: int32(port)},
+					},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{
+							HTTPGet: &corev1.HTTPGetAction{
+								Path: consts.DataPlaneStatusEndpoint,
+								Port: intstr.FromString("metrics"),
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: host,
+		},
+	}
+
+	hash, err := configHashFromStatusEndpoint(server.Client())(t.Context(), pod)
+	require.NoError(t, err)
+	require.Equal(t, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", hash)
+}
+
+func TestDataPlanePodStatusPort(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected int
+	}{
+		{
+			name:     "no proxy container",
+			pod:      &corev1.Pod{},
+			expected: consts.DataPlaneStatusPort,
+		},
+		{
+			name: "readiness probe with port number",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: consts.DataPlaneProxyContainerName,
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromInt(8200)},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: 8200,
+		},
+		{
+			name: "readiness probe with unknown port name",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: consts.DataPlaneProxyContainerName,
+							ReadinessProbe: &corev1.Probe{
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{Port: intstr.FromString("status")},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: consts.DataPlaneStatusPort,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, dataPlanePodStatusPort(tc.pod))
+		})
+	}
+}
@@ -3,10 +3,12 @@ package dataplane
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
 	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -14,11 +16,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
@@ -35,7 +43,7 @@ import (
 type Reconciler struct {
 	client.Client
 	Scheme                   *runtime.Scheme
-	eventRecorder            record.EventRecorder
+	EventRecorder            record.EventRecorder
 	ClusterCASecretName      string
 	ClusterCASecretNamespace string
 	ClusterCAKeyConfig       secrets.KeyConfig
@@ -45,14 +53,34 @@ type Reconciler struct {
 	DefaultImage             string
 	KonnectEnabled           bool
 	EnforceConfig            bool
+
+	// ConfigSyncCheckInterval is the interval at which the configuration loaded
+	// by the DataPlane Pods is checked and reported through the
+	// ConfigurationSynced condition. Zero disables the check.
+	ConfigSyncCheckInterval time.Duration
+	// ConfigSyncCheckIntervalFunc, when set, takes precedence over
+	// ConfigSyncCheckInterval and allows the interval to be changed without
+	// restarting the operator.
+	ConfigSyncCheckIntervalFunc func() time.Duration
+
+	// configHashGetter allows overriding how the configuration hash is
+	// retrieved from DataPlane Pods. Used in tests.
+	configHashGetter configHashGetter
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
-	r.eventRecorder = mgr.GetEventRecorderFor("dataplane")
-
-	return DataPlaneWatchBuilder(mgr, r.KonnectEnabled).
-		Complete(r)
+	b := DataPlaneWatchBuilder(mgr, r.KonnectEnabled)
+	// DataPlanes managed for a paused Gateway are paused as well.
+	if err := pause.WatchPausedOwners(mgr, b, &gwtypes.Gateway{}, &operatorv1beta1.DataPlaneList{}); err != nil {
+		return err
+	}
+	return b.Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("DataPlane",
+		pause.NewReconciler(r.Client, "DataPlane", pause.ConditionsAware[*operatorv1beta1.DataPlane], r,
+			pause.WithPausedOwners(&gwtypes.Gateway{}),
+		),
+		tracing.WithWriterLinks(r.Client, &operatorv1beta1.DataPlane{}),
+	)))
 }
 
 // -----------------------------------------------------------------------------
@@ -63,12 +91,16 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// Calling it here ensures that evaluated values will be used for the duration of this function.
 	ctx = r.ContextInjector.InjectKeyValues(ctx)
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	logger := log.GetLogger(ctx, "dataplane", r.DevelopmentMode)
 
 	log.Trace(logger, "reconciling DataPlane resource")
 	dpNn := req.NamespacedName
 	dataplane := new(operatorv1beta1.DataPlane)
 	if err := r.Client.Get(ctx, dpNn, dataplane); err != nil {
+		if k8serrors.IsNotFound(err) {
+			metrics.DeleteManagedCertificateExpiryForOwner("DataPlane", dpNn)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -136,6 +168,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, nil
 	}
 
+	log.Trace(logger, "exposing DataPlane deployment via additional ingress services")
+	additionalServicesRes, additionalIngressServices, err := ensureAdditionalIngressServicesForDataPlane(
+		ctx,
+		log.GetLogger(ctx, "dataplane_ingress_service", r.DevelopmentMode),
+		r.Client,
+		dataplane,
+		consts.DataPlaneStateLabelValueLive,
+		k8sresources.LabelSelectorFromDataPlaneStatusSelectorServiceOpt(dataplane),
+	)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if additionalServicesRes != op.Noop {
+		log.Debug(logger, "DataPlane additional ingress services modified", "reason", additionalServicesRes)
+		return ctrl.Result{}, nil
+	}
+
 	dataplaneServiceChanged, err := r.ensureDataPlaneServiceStatus(ctx, logger, dataplane, dataplaneIngressService.Name)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -171,7 +220,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	log.Trace(logger, "ensuring DataPlane has service addresses in status", "service", dataplaneIngressService.Name)
-	if updated, err := r.ensureDataPlaneAddressesStatus(ctx, logger, dataplane, dataplaneIngressService); err != nil {
+	if updated, err := r.ensureDataPlaneAddressesStatus(ctx, logger, dataplane, dataplaneIngressService, additionalIngressServices...); err != nil {
 		return ctrl.Result{}, err
 	} else if updated {
 		log.Debug(logger, "dataplane status.Addresses updated")
@@ -239,6 +288,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return res, nil
 	}
 
+	if interval := r.configSyncCheckInterval(); interval > 0 {
+		log.Trace(logger, "checking configuration sync status of DataPlane Pods")
+		if err := ensureDataPlaneConfigurationSyncedStatus(ctx, r.Client, logger, dataplane, r.getConfigHash()); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Debug(logger, "reconciliation complete for DataPlane resource")
+		// Pods' configuration is pushed by the ControlPlane and not observable
+		// through any watched resource hence the periodic requeue.
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
+
 	log.Debug(logger, "reconciliation complete for DataPlane resource")
 	return ctrl.Result{}, nil
 }
@@ -273,3 +333,10 @@ func statusReadyEndpointDeploymentOpt(_ *operatorv1beta1.DataPlane) func(s *apps
 		}
 	}
 }
+
+func (r *Reconciler) configSyncCheckInterval() time.Duration {
+	if r.ConfigSyncCheckIntervalFunc != nil {
+		return r.ConfigSyncCheckIntervalFunc()
+	}
+	return r.ConfigSyncCheckInterval
+}
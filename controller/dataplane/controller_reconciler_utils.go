@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -62,31 +64,97 @@ func (r *Reconciler) ensureDataPlaneServiceStatus(
 
 // ensureDataPlaneAddressesStatus ensures that provided DataPlane's status addresses
 // are as expected and patches its status if there's a difference between the
-// current state and what's expected.
+// current state and what's expected. status.addresses holds the addresses of the
+// provided ingress Service followed by the addresses of the provided additional
+// ingress Services, ordered by the name under which they have been configured.
+// The readiness of each additional ingress Service is reported in a dedicated
+// condition (see ingressServiceConditionType).
 // It returns a boolean indicating if the patch has been triggered and an error.
 func (r *Reconciler) ensureDataPlaneAddressesStatus(
 	ctx context.Context,
 	log logr.Logger,
 	dataplane *operatorv1beta1.DataPlane,
 	dataplaneService *corev1.Service,
+	additionalServices ...corev1.Service,
 ) (bool, error) {
 	addresses, err := address.AddressesFromService(dataplaneService)
 	if err != nil {
 		return false, fmt.Errorf("failed getting addresses for service %s: %w", dataplaneService, err)
 	}
 
+	additionalServices = slices.Clone(additionalServices)
+	slices.SortFunc(additionalServices, func(a, b corev1.Service) int {
+		return strings.Compare(a.Labels[consts.DataPlaneIngressServiceNameLabel], b.Labels[consts.DataPlaneIngressServiceNameLabel])
+	})
+
+	var shouldUpdate bool
+	conditionTypes := make(map[string]struct{}, len(additionalServices))
+	for i := range additionalServices {
+		svc := &additionalServices[i]
+		additionalAddresses, err := address.AddressesFromService(svc)
+		if err != nil {
+			return false, fmt.Errorf("failed getting addresses for service %s: %w", svc.Name, err)
+		}
+		addresses = append(addresses, additionalAddresses...)
+
+		condition := ingressServiceAddressesCondition(svc, additionalAddresses, dataplane.Generation)
+		conditionTypes[condition.Type] = struct{}{}
+		if current, ok := k8sutils.GetCondition(kcfgconsts.ConditionType(condition.Type), dataplane); !ok ||
+			current.Status != condition.Status || current.Reason != condition.Reason || current.Message != condition.Message ||
+			current.ObservedGeneration != condition.ObservedGeneration {
+			k8sutils.SetCondition(condition, dataplane)
+			shouldUpdate = true
+		}
+	}
+
 	// Compare the lengths prior to cmp.Equal() because cmp.Equal() will return
 	// false when comparing nil slice and 0 length slice.
 	if len(addresses) != len(dataplane.Status.Addresses) ||
 		!cmp.Equal(addresses, dataplane.Status.Addresses) {
 		dataplane.Status.Addresses = addresses
+		shouldUpdate = true
+	}
+
+	conditions := lo.Reject(dataplane.Status.Conditions, func(c metav1.Condition, _ int) bool {
+		_, configured := conditionTypes[c.Type]
+		return strings.HasPrefix(c.Type, IngressServiceConditionTypePrefix) && !configured
+	})
+	if len(conditions) != len(dataplane.Status.Conditions) {
+		dataplane.Status.Conditions = conditions
+		shouldUpdate = true
+	}
+
+	if shouldUpdate {
 		_, err := patchDataPlaneStatus(ctx, r.Client, log, dataplane)
 		return true, err
 	}
-
 	return false, nil
 }
 
+// ingressServiceAddressesCondition returns the condition reporting whether the
+// provided additional ingress Service has addresses assigned.
+func ingressServiceAddressesCondition(
+	svc *corev1.Service, addresses []operatorv1beta1.Address, generation int64,
+) metav1.Condition {
+	conditionType := ingressServiceConditionType(svc.Labels[consts.DataPlaneIngressServiceNameLabel])
+	if len(addresses) == 0 {
+		return k8sutils.NewConditionWithGeneration(
+			conditionType,
+			metav1.ConditionFalse,
+			IngressServiceReasonAddressesPending,
+			fmt.Sprintf("Service %s has no addresses assigned yet", svc.Name),
+			generation,
+		)
+	}
+	return k8sutils.NewConditionWithGeneration(
+		conditionType,
+		metav1.ConditionTrue,
+		IngressServiceReasonAddressesAssigned,
+		fmt.Sprintf("Service %s has addresses assigned", svc.Name),
+		generation,
+	)
+}
+
 // ensureMappedConfigMapToKongPluginInstallationForDataPlane ensures that the KongPluginInstallation
 // resources referenced by the DataPlane are resolved and DataPlane is configured to use them.
 // During resolving for each DataPlane based on each instance of KongPluginInstallation
@@ -115,6 +183,9 @@ func ensureMappedConfigMapToKongPluginInstallationForDataPlane(
 			return nil, requeue, err
 		}
 		configMapsToRetain[cp.ConfigMapNN] = struct{}{}
+		for _, part := range cp.Parts {
+			configMapsToRetain[part.ConfigMapNN] = struct{}{}
+		}
 		cps = append(cps, cp)
 	}
 	for _, cm := range configMapsOwned {
@@ -157,6 +228,29 @@ func populateDedicatedConfigMapForKongPluginInstallation(
 		return customPlugin{}, true, nil
 	}
 
+	return customPluginForKongPluginInstallation(ctx, logger, c, kpi,
+		func(part string, underlyingCM corev1.ConfigMap) (corev1.ConfigMap, bool, error) {
+			return ensureMappedConfigMapForKongPluginInstallation(ctx, logger, c, cms, kpi, part, underlyingCM, dataplane)
+		},
+	)
+}
+
+// mapConfigMapFunc returns the ConfigMap of the DataPlane mapped to one of the
+// ConfigMaps the plugin of a KongPluginInstallation is stored in. part is the
+// index of the additional ConfigMap the plugin is split across, empty for the
+// underlying ConfigMap of the KongPluginInstallation.
+type mapConfigMapFunc func(part string, underlyingCM corev1.ConfigMap) (cm corev1.ConfigMap, requeue bool, err error)
+
+// customPluginForKongPluginInstallation discovers the ConfigMaps the plugin of
+// the KongPluginInstallation is stored in and returns the custom plugin mounting
+// the ConfigMaps they are mapped to by mapConfigMap.
+func customPluginForKongPluginInstallation(
+	ctx context.Context,
+	logger logr.Logger,
+	c client.Client,
+	kpi operatorv1alpha1.KongPluginInstallation,
+	mapConfigMap mapConfigMapFunc,
+) (cp customPlugin, requeue bool, err error) {
 	var underlyingCM corev1.ConfigMap
 	backingCMNN := types.NamespacedName{
 		Namespace: kpi.Namespace,
@@ -167,37 +261,132 @@ func populateDedicatedConfigMapForKongPluginInstallation(
 		return customPlugin{}, false, fmt.Errorf("could not fetch underlying ConfigMap to clone %s: %w", backingCMNN, err)
 	}
 
-	log.Trace(logger, "Find ConfigMap mapped to KongPluginInstallation")
-	mappedConfigMapForKPI := lo.Filter(cms, func(cm corev1.ConfigMap, _ int) bool {
+	cm, requeue, err := mapConfigMap("", underlyingCM)
+	if err != nil || requeue {
+		return customPlugin{}, requeue, err
+	}
+	files, err := k8sresources.GetKongPluginInstallationFiles(&cm)
+	if err != nil {
+		return customPlugin{}, false, err
+	}
+	cp = customPlugin{
+		Name:        kpi.Name,
+		ConfigMapNN: client.ObjectKeyFromObject(&cm),
+		Generation:  kpi.Generation,
+		Digest:      underlyingCM.Annotations[consts.AnnotationKongPluginInstallationDigest],
+		Files:       files,
+	}
+
+	// The plugin may be split across additional ConfigMaps when it doesn't fit in one.
+	partNames, hasParts := underlyingCM.Annotations[consts.AnnotationKongPluginInstallationParts]
+	if !hasParts {
+		return cp, false, nil
+	}
+	for i, partName := range strings.Split(partNames, ",") {
+		partNN := types.NamespacedName{Namespace: kpi.Namespace, Name: partName}
+		var underlyingPart corev1.ConfigMap
+		if err := c.Get(ctx, partNN, &underlyingPart); err != nil {
+			if k8serrors.IsNotFound(err) {
+				// The KongPluginInstallation is being updated.
+				return customPlugin{}, true, nil
+			}
+			return customPlugin{}, false, fmt.Errorf("could not fetch underlying ConfigMap to clone %s: %w", partNN, err)
+		}
+		partCM, requeue, err := mapConfigMap(strconv.Itoa(i+1), underlyingPart)
+		if err != nil || requeue {
+			return customPlugin{}, requeue, err
+		}
+		partFiles, err := k8sresources.GetKongPluginInstallationFiles(&partCM)
+		if err != nil {
+			return customPlugin{}, false, err
+		}
+		cp.Parts = append(cp.Parts, customPluginPart{
+			ConfigMapNN: client.ObjectKeyFromObject(&partCM),
+			Files:       partFiles,
+		})
+	}
+	return cp, false, nil
+}
+
+// findMappedConfigMapsForKongPluginInstallation returns the ConfigMaps mapped to
+// one of the ConfigMaps the plugin of the KongPluginInstallation is stored in.
+func findMappedConfigMapsForKongPluginInstallation(
+	cms []corev1.ConfigMap, kpi operatorv1alpha1.KongPluginInstallation, part string,
+) []corev1.ConfigMap {
+	return lo.Filter(cms, func(cm corev1.ConfigMap, _ int) bool {
 		kpiNN := cm.Annotations[consts.AnnotationMappedToKongPluginInstallation]
-		return kpiNN == client.ObjectKeyFromObject(&kpi).String()
+		return kpiNN == client.ObjectKeyFromObject(&kpi).String() &&
+			cm.Annotations[consts.AnnotationKongPluginInstallationPart] == part
 	})
+}
+
+// newMappedConfigMapForKongPluginInstallation returns a new ConfigMap owned by the
+// DataPlane holding a copy of one of the ConfigMaps the plugin of the KongPluginInstallation
+// is stored in.
+func newMappedConfigMapForKongPluginInstallation(
+	kpi operatorv1alpha1.KongPluginInstallation,
+	part string,
+	underlyingCM corev1.ConfigMap,
+	dataplane *operatorv1beta1.DataPlane,
+) corev1.ConfigMap {
 	var cm corev1.ConfigMap
+	cm.GenerateName = dataplane.Name + "-"
+	cm.Namespace = dataplane.Namespace
+	k8sutils.SetOwnerForObject(&cm, dataplane)
+	k8sresources.LabelObjectAsDataPlaneManaged(&cm)
+	k8sresources.AnnotateConfigMapWithKongPluginInstallation(&cm, kpi)
+	if part != "" {
+		cm.Annotations[consts.AnnotationKongPluginInstallationPart] = part
+	}
+	if files := underlyingCM.Annotations[consts.AnnotationKongPluginInstallationFiles]; files != "" {
+		cm.Annotations[consts.AnnotationKongPluginInstallationFiles] = files
+	}
+	cm.Data = underlyingCM.Data
+	return cm
+}
+
+// ensureMappedConfigMapForKongPluginInstallation ensures that the DataPlane owns a
+// copy, in its namespace, of one of the ConfigMaps the plugin of the KongPluginInstallation
+// is stored in. part is the index of the additional ConfigMap the plugin is split across,
+// empty for the underlying ConfigMap of the KongPluginInstallation.
+func ensureMappedConfigMapForKongPluginInstallation(
+	ctx context.Context,
+	logger logr.Logger,
+	c client.Client,
+	cms []corev1.ConfigMap,
+	kpi operatorv1alpha1.KongPluginInstallation,
+	part string,
+	underlyingCM corev1.ConfigMap,
+	dataplane *operatorv1beta1.DataPlane,
+) (cm corev1.ConfigMap, requeue bool, err error) {
+	log.Trace(logger, "Find ConfigMap mapped to KongPluginInstallation", "part", part)
+	mappedConfigMapForKPI := findMappedConfigMapsForKongPluginInstallation(cms, kpi, part)
+	files := underlyingCM.Annotations[consts.AnnotationKongPluginInstallationFiles]
 	switch len(mappedConfigMapForKPI) {
 	case 0:
 		log.Trace(logger, "Create new ConfigMap for KongPluginInstallation")
-		cm.GenerateName = dataplane.Name + "-"
-		cm.Namespace = dataplane.Namespace
-		k8sutils.SetOwnerForObject(&cm, dataplane)
-		k8sresources.LabelObjectAsDataPlaneManaged(&cm)
-		k8sresources.AnnotateConfigMapWithKongPluginInstallation(&cm, kpi)
-		cm.Data = underlyingCM.Data
+		cm = newMappedConfigMapForKongPluginInstallation(kpi, part, underlyingCM, dataplane)
 		if err := c.Create(ctx, &cm); err != nil {
-			return customPlugin{}, false, fmt.Errorf("could not create new ConfigMap for KongPluginInstallation: %w", err)
+			return cm, false, fmt.Errorf("could not create new ConfigMap for KongPluginInstallation: %w", err)
 		}
 	case 1:
-		log.Trace(logger, fmt.Sprintf("Check if update existing ConfigMap %s for KongPluginInstallation", client.ObjectKeyFromObject(&cm)))
 		cm = mappedConfigMapForKPI[0]
-		if maps.Equal(cm.Data, underlyingCM.Data) {
+		log.Trace(logger, fmt.Sprintf("Check if update existing ConfigMap %s for KongPluginInstallation", client.ObjectKeyFromObject(&cm)))
+		if maps.Equal(cm.Data, underlyingCM.Data) && cm.Annotations[consts.AnnotationKongPluginInstallationFiles] == files {
 			log.Trace(logger, fmt.Sprintf("Nothing to update in existing ConfigMap %s for KongPluginInstallation", client.ObjectKeyFromObject(&cm)))
 		} else {
 			log.Trace(logger, fmt.Sprintf("Update existing ConfigMap %s for KongPluginInstallation", client.ObjectKeyFromObject(&cm)))
 			cm.Data = underlyingCM.Data
+			if files != "" {
+				cm.Annotations[consts.AnnotationKongPluginInstallationFiles] = files
+			} else {
+				delete(cm.Annotations, consts.AnnotationKongPluginInstallationFiles)
+			}
 			if err := c.Update(ctx, &cm); err != nil {
 				if k8serrors.IsConflict(err) {
-					return customPlugin{}, true, nil
+					return cm, true, nil
 				}
-				return customPlugin{}, false, fmt.Errorf("could not update mapped: %w", err)
+				return cm, false, fmt.Errorf("could not update mapped: %w", err)
 			}
 		}
 
@@ -206,13 +395,9 @@ func populateDedicatedConfigMapForKongPluginInstallation(
 		names := strings.Join(lo.Map(mappedConfigMapForKPI, func(cm corev1.ConfigMap, _ int) string {
 			return client.ObjectKeyFromObject(&cm).String()
 		}), ", ")
-		return customPlugin{}, false, fmt.Errorf("unexpected error happened - more than one ConfigMap found: %s", names)
+		return cm, false, fmt.Errorf("unexpected error happened - more than one ConfigMap found: %s", names)
 	}
-	return customPlugin{
-		Name:        kpi.Name,
-		ConfigMapNN: client.ObjectKeyFromObject(&cm),
-		Generation:  kpi.Generation,
-	}, false, nil
+	return cm, false, nil
 }
 
 // verifyKPIReadinessForDataPlane updates DataPlane status conditions based on status of KPI object.
@@ -243,10 +428,10 @@ func verifyKPIReadinessForDataPlane(
 		)
 		return kpi, false, markErr
 	}
+	// Any other reason than pending, e.g. a failed signature verification, means it's failing.
 	if lo.ContainsBy(kpi.Status.Conditions, func(c metav1.Condition) bool {
 		return c.Type == string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted) &&
-			c.Status == metav1.ConditionFalse &&
-			c.Reason == string(operatorv1alpha1.KongPluginInstallationReasonFailed)
+			c.Status == metav1.ConditionFalse
 	}) {
 		msgFailed := fmt.Sprintf("something wrong with referenced KongPluginInstallation %s, please check it", kpiNN)
 		markErr := ensureDataPlaneIsMarkedNotReady(
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/go-logr/logr"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
@@ -21,6 +22,7 @@ import (
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/internal/versions"
 	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
 
 	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
@@ -616,3 +618,150 @@ func TestDataPlaneIngressServiceIsReady(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureDataPlaneAddressesStatus(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dp", Generation: 1},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dataplane).
+		WithStatusSubresource(dataplane).
+		Build()
+	r := &Reconciler{Client: cl}
+
+	loadBalancer := func(name, ip string) corev1.Service {
+		svc := corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "dp-" + name,
+				Labels:    map[string]string{consts.DataPlaneIngressServiceNameLabel: name},
+			},
+			Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		}
+		if ip != "" {
+			svc.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: ip}}
+		}
+		return svc
+	}
+	ingressService := loadBalancer("ingress", "1.1.1.1")
+
+	updated, err := r.ensureDataPlaneAddressesStatus(t.Context(), logr.Discard(), dataplane, &ingressService,
+		loadBalancer("public", "2.2.2.2"), loadBalancer("internal", ""),
+	)
+	require.NoError(t, err)
+	require.True(t, updated)
+	addressValues := func() []string {
+		return lo.Map(dataplane.Status.Addresses, func(a operatorv1beta1.Address, _ int) string { return a.Value })
+	}
+	require.Equal(t, []string{"1.1.1.1", "2.2.2.2"}, addressValues())
+
+	public, ok := k8sutils.GetCondition(ingressServiceConditionType("public"), dataplane)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionTrue, public.Status)
+	require.EqualValues(t, IngressServiceReasonAddressesAssigned, public.Reason)
+	internal, ok := k8sutils.GetCondition(ingressServiceConditionType("internal"), dataplane)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionFalse, internal.Status)
+	require.EqualValues(t, IngressServiceReasonAddressesPending, internal.Reason)
+
+	updated, err = r.ensureDataPlaneAddressesStatus(t.Context(), logr.Discard(), dataplane, &ingressService,
+		loadBalancer("public", "2.2.2.2"), loadBalancer("internal", ""),
+	)
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	updated, err = r.ensureDataPlaneAddressesStatus(t.Context(), logr.Discard(), dataplane, &ingressService,
+		loadBalancer("public", "2.2.2.2"),
+	)
+	require.NoError(t, err)
+	require.True(t, updated)
+	_, ok = k8sutils.GetCondition(ingressServiceConditionType("internal"), dataplane)
+	require.False(t, ok)
+
+	t.Log("additional ingress Service addresses are ordered by the Service's configured name")
+	updated, err = r.ensureDataPlaneAddressesStatus(t.Context(), logr.Discard(), dataplane, &ingressService,
+		loadBalancer("public", "2.2.2.2"), loadBalancer("internal", "3.3.3.3"),
+	)
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, []string{"1.1.1.1", "3.3.3.3", "2.2.2.2"}, addressValues())
+	internal, ok = k8sutils.GetCondition(ingressServiceConditionType("internal"), dataplane)
+	require.True(t, ok)
+	require.Equal(t, metav1.ConditionTrue, internal.Status)
+}
+
+func TestPlanCustomPluginsForDataPlane(t *testing.T) {
+	kpi := &operatorv1alpha1.KongPluginInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "plugins",
+			Name:      "rate-limiter",
+		},
+		Status: operatorv1alpha1.KongPluginInstallationStatus{
+			Conditions: []metav1.Condition{
+				{
+					Type:   string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+					Status: metav1.ConditionTrue,
+					Reason: string(operatorv1alpha1.KongPluginInstallationReasonReady),
+				},
+			},
+			UnderlyingConfigMapName: "rate-limiter-abcde",
+		},
+	}
+	underlyingCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "plugins",
+			Name:      "rate-limiter-abcde",
+			Annotations: map[string]string{
+				consts.AnnotationKongPluginInstallationDigest: "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+				consts.AnnotationKongPluginInstallationFiles:  `{"handler.lua":"handler.lua","migrations.init.lua":"migrations/init.lua"}`,
+				consts.AnnotationKongPluginInstallationParts:  "rate-limiter-fghij",
+			},
+		},
+		Data: map[string]string{"handler.lua": "handler", "migrations.init.lua": "migration"},
+	}
+	partCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "plugins",
+			Name:      "rate-limiter-fghij",
+			Annotations: map[string]string{
+				consts.AnnotationKongPluginInstallationFiles: `{"schema.lua":"schema.lua"}`,
+			},
+		},
+		Data: map[string]string{"schema.lua": "schema"},
+	}
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "dp",
+			UID:       "dp-uid",
+		},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				PluginsToInstall: []operatorv1beta1.NamespacedName{{Namespace: "plugins", Name: "rate-limiter"}},
+			},
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(kpi, underlyingCM, partCM, dataplane).
+		Build()
+	ctx := t.Context()
+
+	t.Log("the plan has the files, the parts and the digest of the plugin before the ConfigMaps are mapped")
+	planned, err := planCustomPluginsForDataPlane(ctx, cl, dataplane)
+	require.NoError(t, err)
+	require.Len(t, planned, 1)
+	require.Equal(t, map[string]string{"handler.lua": "handler.lua", "migrations.init.lua": "migrations/init.lua"}, planned[0].Files)
+	require.Len(t, planned[0].Parts, 1)
+	require.Equal(t, map[string]string{"schema.lua": "schema.lua"}, planned[0].Parts[0].Files)
+	require.Equal(t, underlyingCM.Annotations[consts.AnnotationKongPluginInstallationDigest], planned[0].Digest)
+
+	t.Log("the plan matches the custom plugins of the reconciled DataPlane once the ConfigMaps are mapped")
+	cps, requeue, err := ensureMappedConfigMapToKongPluginInstallationForDataPlane(ctx, logr.Discard(), cl, dataplane)
+	require.NoError(t, err)
+	require.False(t, requeue)
+	planned, err = planCustomPluginsForDataPlane(ctx, cl, dataplane)
+	require.NoError(t, err)
+	require.Equal(t, cps, planned)
+}
@@ -2,8 +2,11 @@ package dataplane
 
 import (
 	"fmt"
+	"path"
+	"slices"
 	"strings"
 
+	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -20,6 +23,22 @@ type customPlugin struct {
 	ConfigMapNN types.NamespacedName
 	// Generation is the generation of the KongPluginInstallation that contains the plugin.
 	Generation int64
+	// Digest is the digest of the image's manifest the plugin was fetched from, empty when unknown.
+	Digest string
+	// Files maps the keys of the ConfigMap to the paths of the files in the plugin's directory,
+	// nil when all the files are stored under their names.
+	Files map[string]string
+	// Parts are the additional ConfigMaps the plugin is split across when it doesn't fit in one.
+	Parts []customPluginPart
+}
+
+// customPluginPart is an additional ConfigMap a custom plugin is split across.
+type customPluginPart struct {
+	// ConfigMapNN is the namespace/name of the ConfigMap that contains a part of the plugin.
+	ConfigMapNN types.NamespacedName
+	// Files maps the keys of the ConfigMap to the paths of the files in the plugin's directory,
+	// nil when all the files are stored under their names.
+	Files map[string]string
 }
 
 func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt {
@@ -43,21 +62,17 @@ func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt
 
 	for _, cp := range customPlugins {
 		kpisNames = append(kpisNames, cp.Name)
-		kpisGenerations = append(kpisGenerations, fmt.Sprintf("%s:%d", cp.Name, cp.Generation))
+		// The digest is part of the annotation so that Pods are rolled out when the image's tag moves.
+		if cp.Digest != "" {
+			kpisGenerations = append(kpisGenerations, fmt.Sprintf("%s:%d@%s", cp.Name, cp.Generation, cp.Digest))
+		} else {
+			kpisGenerations = append(kpisGenerations, fmt.Sprintf("%s:%d", cp.Name, cp.Generation))
+		}
 		kpisVolumeMounts = append(kpisVolumeMounts, corev1.VolumeMount{
 			Name:      cp.Name,
 			MountPath: "/opt/kong/plugins/" + cp.Name,
 		})
-		kpisVolumes = append(kpisVolumes, corev1.Volume{
-			Name: cp.Name,
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: cp.ConfigMapNN.Name,
-					},
-				},
-			},
-		})
+		kpisVolumes = append(kpisVolumes, customPluginVolume(cp))
 	}
 
 	return func(deployment *appsv1.Deployment) {
@@ -67,7 +82,7 @@ func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt
 		deployment.Spec.Template.Annotations[consts.AnnotationKongPluginInstallationGenerationInternal] = strings.Join(kpisGenerations, ",")
 		deployment.Spec.Template.Spec.Containers[0].Env = append(
 			deployment.Spec.Template.Spec.Containers[0].Env,
-			config.ConfigureKongPluginRelatedEnvVars(kpisNames)...,
+			config.ConfigureKongPluginRelatedEnvVars(kpisNames, lo.SomeBy(customPlugins, customPlugin.hasDirectoryModules))...,
 		)
 		deployment.Spec.Template.Spec.Containers[0].VolumeMounts = append(
 			deployment.Spec.Template.Spec.Containers[0].VolumeMounts,
@@ -79,3 +94,64 @@ func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt
 		)
 	}
 }
+
+// hasDirectoryModules returns true when the plugin has a Lua module made of a
+// subdirectory with an init.lua file, e.g. migrations/init.lua.
+func (cp customPlugin) hasDirectoryModules() bool {
+	files := lo.Values(cp.Files)
+	for _, part := range cp.Parts {
+		files = append(files, lo.Values(part.Files)...)
+	}
+	return lo.SomeBy(files, func(filePath string) bool {
+		return strings.Contains(filePath, "/") && path.Base(filePath) == "init.lua"
+	})
+}
+
+// customPluginVolume returns the volume with the files of the custom plugin. A plugin
+// with files in subdirectories or split across several ConfigMaps is mounted through
+// a projected volume which restores the layout of its directory.
+func customPluginVolume(cp customPlugin) corev1.Volume {
+	if cp.Files == nil && len(cp.Parts) == 0 {
+		return corev1.Volume{
+			Name: cp.Name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cp.ConfigMapNN.Name,
+					},
+				},
+			},
+		}
+	}
+
+	sources := make([]corev1.VolumeProjection, 0, len(cp.Parts)+1)
+	sources = append(sources, configMapProjection(cp.ConfigMapNN.Name, cp.Files))
+	for _, part := range cp.Parts {
+		sources = append(sources, configMapProjection(part.ConfigMapNN.Name, part.Files))
+	}
+	return corev1.Volume{
+		Name: cp.Name,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: sources,
+			},
+		},
+	}
+}
+
+func configMapProjection(name string, files map[string]string) corev1.VolumeProjection {
+	projection := &corev1.ConfigMapProjection{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: name,
+		},
+	}
+	keys := lo.Keys(files)
+	slices.Sort(keys)
+	for _, key := range keys {
+		projection.Items = append(projection.Items, corev1.KeyToPath{
+			Key:  key,
+			Path: files[key],
+		})
+	}
+	return corev1.VolumeProjection{ConfigMap: projection}
+}
@@ -84,6 +84,7 @@ func TestOptWithCustomPlugin(t *testing.T) {
 						Name: "configmap2",
 					},
 					Generation: 2,
+					Digest:     "sha256:3c8a6f33e0a3e5a8b4d1f8b9c1f0a4d3e5c6b7a8d9e0f1a2b3c4d5e6f7a8b9c0",
 				},
 			},
 			expectedEnv: []corev1.EnvVar{
@@ -129,7 +130,84 @@ func TestOptWithCustomPlugin(t *testing.T) {
 				},
 			},
 			expectedAnnotations: map[string]string{
-				consts.AnnotationKongPluginInstallationGenerationInternal: "plugin1:1,plugin2:2",
+				consts.AnnotationKongPluginInstallationGenerationInternal: "plugin1:1,plugin2:2@sha256:3c8a6f33e0a3e5a8b4d1f8b9c1f0a4d3e5c6b7a8d9e0f1a2b3c4d5e6f7a8b9c0",
+			},
+		},
+		{
+			name: "custom plugin with files in subdirectories split across ConfigMaps",
+			customPlugins: []customPlugin{
+				{
+					Name: "plugin1",
+					ConfigMapNN: types.NamespacedName{
+						Name: "configmap1",
+					},
+					Generation: 1,
+					Files: map[string]string{
+						"schema.lua":  "schema.lua",
+						"handler.lua": "handler.lua",
+					},
+					Parts: []customPluginPart{
+						{
+							ConfigMapNN: types.NamespacedName{
+								Name: "configmap1-part",
+							},
+							Files: map[string]string{
+								"migrations.init.lua": "migrations/init.lua",
+							},
+						},
+					},
+				},
+			},
+			expectedEnv: []corev1.EnvVar{
+				{
+					Name:  "KONG_PLUGINS",
+					Value: "bundled,plugin1",
+				},
+				{
+					Name:  "KONG_LUA_PACKAGE_PATH",
+					Value: "/opt/?.lua;/opt/?/init.lua;;",
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name: "plugin1",
+					VolumeSource: corev1.VolumeSource{
+						Projected: &corev1.ProjectedVolumeSource{
+							Sources: []corev1.VolumeProjection{
+								{
+									ConfigMap: &corev1.ConfigMapProjection{
+										LocalObjectReference: corev1.LocalObjectReference{
+											Name: "configmap1",
+										},
+										Items: []corev1.KeyToPath{
+											{Key: "handler.lua", Path: "handler.lua"},
+											{Key: "schema.lua", Path: "schema.lua"},
+										},
+									},
+								},
+								{
+									ConfigMap: &corev1.ConfigMapProjection{
+										LocalObjectReference: corev1.LocalObjectReference{
+											Name: "configmap1-part",
+										},
+										Items: []corev1.KeyToPath{
+											{Key: "migrations.init.lua", Path: "migrations/init.lua"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedVolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "plugin1",
+					MountPath: "/opt/kong/plugins/plugin1",
+				},
+			},
+			expectedAnnotations: map[string]string{
+				consts.AnnotationKongPluginInstallationGenerationInternal: "plugin1:1",
 			},
 		},
 	}
@@ -111,10 +111,32 @@ func (d *DeploymentBuilder) BuildAndDeploy(
 		return nil, op.Noop, nil
 	}
 
+	desiredDeployment, err := d.build(ctx, dataplane, developmentMode)
+	if err != nil {
+		return nil, op.Noop, err
+	}
+
+	// push the complete Deployment to Kubernetes
+	res, deployment, err := reconcileDataPlaneDeployment(ctx, d.client, d.logger, enforceConfig,
+		dataplane, existingDeployment, desiredDeployment.Unwrap())
+	if err != nil {
+		return nil, op.Noop, err
+	}
+	return deployment, res, nil
+}
+
+// build generates the desired Deployment for a DataPlane: it runs the generator,
+// the after generation callbacks, applies the user patches and the default
+// environment variables and annotates the result with the DataPlane spec hash.
+func (d *DeploymentBuilder) build(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	developmentMode bool,
+) (*k8sresources.Deployment, error) {
 	// generate the initial Deployment struct
 	desiredDeployment, err := generateDataPlaneDeployment(developmentMode, dataplane, d.defaultImage, d.additionalLabels, d.opts...)
 	if err != nil {
-		return nil, op.Noop, fmt.Errorf("could not generate Deployment: %w", err)
+		return nil, fmt.Errorf("could not generate Deployment: %w", err)
 	}
 
 	// Add the cluster certificate to the generated Deployment
@@ -122,13 +144,13 @@ func (d *DeploymentBuilder) BuildAndDeploy(
 
 	// run any callbacks that patch the initial Deployment struct
 	afterDeploymentCallbacks := NewCallbackRunner(d.client)
-	cbErrors = afterDeploymentCallbacks.For(dataplane).Runs(d.afterCallbacks).
+	cbErrors := afterDeploymentCallbacks.For(dataplane).Runs(d.afterCallbacks).
 		Modifies(reflect.TypeFor[k8sresources.Deployment]()).Do(ctx, desiredDeployment)
 	if len(cbErrors) > 0 {
 		for _, err := range cbErrors {
 			d.logger.Error(err, "callback failed")
 		}
-		return nil, op.Noop, fmt.Errorf("after generation callbacks failed")
+		return nil, fmt.Errorf("after generation callbacks failed")
 	}
 
 	// TODO https://github.com/Kong/gateway-operator/issues/128
@@ -139,22 +161,15 @@ func (d *DeploymentBuilder) BuildAndDeploy(
 	// apply user patches and set any default environment variables that aren't already set
 	desiredDeployment, err = applyDeploymentUserPatchesForDataPlane(dataplane, desiredDeployment)
 	if err != nil {
-		return nil, op.Noop, err
+		return nil, err
 	}
 	// apply default envvars and restore the hacked-out ones
 	desiredDeployment = applyEnvForDataPlane(existingEnvVars, desiredDeployment, config.KongDefaults)
 
 	if err := k8sresources.AnnotateObjWithHash(desiredDeployment.Unwrap(), dataplane.Spec); err != nil {
-		return nil, op.Noop, err
-	}
-
-	// push the complete Deployment to Kubernetes
-	res, deployment, err := reconcileDataPlaneDeployment(ctx, d.client, d.logger, enforceConfig,
-		dataplane, existingDeployment, desiredDeployment.Unwrap())
-	if err != nil {
-		return nil, op.Noop, err
+		return nil, err
 	}
-	return deployment, res, nil
+	return desiredDeployment, nil
 }
 
 // generateDataPlaneDeployment generates the base Deployment for a DataPlane. It determines the image to use and
@@ -289,11 +304,11 @@ func reconcileDataPlaneDeployment(
 		// existing Deployment with the spec hash of the desired Deployment. If
 		// the hashes match, we skip the update.
 		if !enforceConfig {
-			hash, err := k8sresources.CalculateHash(dataplane.Spec)
+			matches, hash, err := dataPlaneDeploymentSpecHashMatches(dataplane, existing)
 			if err != nil {
-				return op.Noop, nil, fmt.Errorf("failed to calculate hash spec from DataPlane: %w", err)
+				return op.Noop, nil, err
 			}
-			if h, ok := existing.GetAnnotations()[consts.AnnotationPodTemplateSpecHash]; ok && h == hash {
+			if matches {
 				log.Debug(logger, "DataPlane Deployment spec hash matches existing Deployment, skipping update", "hash", hash)
 				return op.Noop, existing, nil
 			}
@@ -301,51 +316,10 @@ func reconcileDataPlaneDeployment(
 			// so fall through to the update logic.
 		}
 
-		var updated bool
 		original := existing.DeepCopy()
-
-		k8sresources.SetDefaultsPodTemplateSpec(&desired.Spec.Template)
-
-		// ensure that object metadata is up to date
-		updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, desired.ObjectMeta)
-
-		// some custom comparison rules are needed for some PodTemplateSpec sub-attributes, in particular
-		// resources and affinity.
-		opts := []cmp.Option{
-			cmp.Comparer(k8sresources.ResourceRequirementsEqual),
-		}
-
-		// ensure that PodTemplateSpec is up to date
-		if !cmp.Equal(existing.Spec.Template, desired.Spec.Template, opts...) {
-			existing.Spec.Template = desired.Spec.Template
-			updated = true
-		}
-
-		// ensure that rollout strategy is up to date
-		if !cmp.Equal(existing.Spec.Strategy, desired.Spec.Strategy) {
-			existing.Spec.Strategy = desired.Spec.Strategy
-			updated = true
-		}
-
-		if scaling := dataplane.Spec.Deployment.DeploymentOptions.Scaling; false ||
-			// If the scaling strategy is not specified, we compare the replicas.
-			(scaling == nil || scaling.HorizontalScaling == nil) ||
-			// If the scaling strategy is specified with minReplicas, we compare
-			// the minReplicas with the existing Deployment replicas and we set
-			// the replicas to the minReplicas if the existing Deployment replicas
-			// are less than the minReplicas to enforce faster scaling before HPA
-			// kicks in.
-			(scaling.HorizontalScaling != nil &&
-				scaling.HorizontalScaling.MinReplicas != nil &&
-				existing.Spec.Replicas != nil &&
-				*existing.Spec.Replicas < *scaling.HorizontalScaling.MinReplicas) {
-			if !cmp.Equal(existing.Spec.Replicas, desired.Spec.Replicas) {
-				existing.Spec.Replicas = desired.Spec.Replicas
-				updated = true
-			}
-		}
+		updated := updateDataPlaneDeployment(dataplane, existing, desired)
 		if updated {
-			diff := cmp.Diff(original.Spec.Template, desired.Spec.Template, opts...)
+			diff := cmp.Diff(original.Spec.Template, desired.Spec.Template, cmp.Comparer(k8sresources.ResourceRequirementsEqual))
 			log.Trace(logger, "DataPlane Deployment diff detected", "diff", diff)
 		}
 
@@ -359,3 +333,72 @@ func reconcileDataPlaneDeployment(
 	log.Debug(logger, "deployment for DataPlane created", "deployment", desired.Name)
 	return op.Created, desired, nil
 }
+
+// dataPlaneDeploymentSpecHashMatches reports whether the spec hash annotation of
+// the existing Deployment matches the hash of the DataPlane spec. It also returns
+// the calculated hash.
+func dataPlaneDeploymentSpecHashMatches(
+	dataplane *operatorv1beta1.DataPlane,
+	existing *appsv1.Deployment,
+) (bool, string, error) {
+	hash, err := k8sresources.CalculateHash(dataplane.Spec)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to calculate hash spec from DataPlane: %w", err)
+	}
+	h, ok := existing.GetAnnotations()[consts.AnnotationPodTemplateSpecHash]
+	return ok && h == hash, hash, nil
+}
+
+// updateDataPlaneDeployment updates the existing DataPlane Deployment in place
+// with the fields of the desired one that the operator enforces. It returns
+// true when the existing Deployment was changed.
+func updateDataPlaneDeployment(
+	dataplane *operatorv1beta1.DataPlane,
+	existing *appsv1.Deployment,
+	desired *appsv1.Deployment,
+) bool {
+	var updated bool
+
+	k8sresources.SetDefaultsPodTemplateSpec(&desired.Spec.Template)
+
+	// ensure that object metadata is up to date
+	updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, desired.ObjectMeta)
+
+	// some custom comparison rules are needed for some PodTemplateSpec sub-attributes, in particular
+	// resources and affinity.
+	opts := []cmp.Option{
+		cmp.Comparer(k8sresources.ResourceRequirementsEqual),
+	}
+
+	// ensure that PodTemplateSpec is up to date
+	if !cmp.Equal(existing.Spec.Template, desired.Spec.Template, opts...) {
+		existing.Spec.Template = desired.Spec.Template
+		updated = true
+	}
+
+	// ensure that rollout strategy is up to date
+	if !cmp.Equal(existing.Spec.Strategy, desired.Spec.Strategy) {
+		existing.Spec.Strategy = desired.Spec.Strategy
+		updated = true
+	}
+
+	if scaling := dataplane.Spec.Deployment.DeploymentOptions.Scaling; false ||
+		// If the scaling strategy is not specified, we compare the replicas.
+		(scaling == nil || scaling.HorizontalScaling == nil) ||
+		// If the scaling strategy is specified with minReplicas, we compare
+		// the minReplicas with the existing Deployment replicas and we set
+		// the replicas to the minReplicas if the existing Deployment replicas
+		// are less than the minReplicas to enforce faster scaling before HPA
+		// kicks in.
+		(scaling.HorizontalScaling != nil &&
+			scaling.HorizontalScaling.MinReplicas != nil &&
+			existing.Spec.Replicas != nil &&
+			*existing.Spec.Replicas < *scaling.HorizontalScaling.MinReplicas) {
+		if !cmp.Equal(existing.Spec.Replicas, desired.Spec.Replicas) {
+			existing.Spec.Replicas = desired.Spec.Replicas
+			updated = true
+		}
+	}
+
+	return updated
+}
@@ -3,6 +3,7 @@ package dataplane
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/samber/lo"
@@ -19,6 +20,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/internal/tracing"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
@@ -106,7 +109,7 @@ func (r *DataPlaneOwnedResourceFinalizerReconciler[T, PT]) SetupWithManager(ctx
 			predicate.NewPredicateFuncs(objectIsOwnedByDataPlane),
 		)).
 		Watches(&operatorv1beta1.DataPlane{}, handler.EnqueueRequestsFromMapFunc(requestsForDataPlaneOwnedObjects[T](r.Client))).
-		Complete(r)
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("DataPlaneOwned"+reflect.TypeFor[T]().Name()+"Finalizer", r)))
 }
 
 // Reconcile reconciles the DataPlaneOwnedResource object.
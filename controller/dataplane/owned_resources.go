@@ -17,6 +17,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
@@ -25,6 +26,7 @@ import (
 	k8sreduce "github.com/kong/gateway-operator/pkg/utils/kubernetes/reduce"
 	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
 
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
 )
 
@@ -324,54 +326,9 @@ func ensureIngressServiceForDataPlane(
 	k8sutils.SetOwnerForObject(generatedService, dataPlane)
 
 	if count == 1 {
-		var updated bool
 		existingService := &services[0]
 		old := existingService.DeepCopy()
-		updated, existingService.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingService.ObjectMeta, generatedService.ObjectMeta,
-			// enforce all the annotations provided through the dataplane API
-			func(existingMeta metav1.ObjectMeta, generatedMeta metav1.ObjectMeta) (bool, metav1.ObjectMeta) {
-				metaToUpdate, updatedAnnotations, err := ensureDataPlaneIngressServiceAnnotationsUpdated(
-					dataPlane, existingMeta.Annotations, generatedMeta.Annotations,
-				)
-				if err != nil {
-					logger.Error(err, "failed to update annotations of existing ingress service for dataplane",
-						"dataplane", fmt.Sprintf("%s/%s", dataPlane.Namespace, dataPlane.Name),
-						"ingress_service", fmt.Sprintf("%s/%s", existingService.Namespace, existingService.Name))
-					return true, existingMeta
-				}
-				existingMeta.Annotations = updatedAnnotations
-				return metaToUpdate, existingMeta
-			})
-
-		if existingService.Spec.Type != generatedService.Spec.Type {
-			existingService.Spec.Type = generatedService.Spec.Type
-			updated = true
-		}
-
-		const (
-			defaultExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
-		)
-		// Do not update when
-		// - the existing service has the default value for ExternalTrafficPolicy
-		// - and the generated service has the default value for ExternalTrafficPolicy or is empty.
-		if !(existingService.Spec.ExternalTrafficPolicy == defaultExternalTrafficPolicy &&
-			(generatedService.Spec.ExternalTrafficPolicy == "" || generatedService.Spec.ExternalTrafficPolicy == defaultExternalTrafficPolicy)) {
-			existingService.Spec.ExternalTrafficPolicy = generatedService.Spec.ExternalTrafficPolicy
-			updated = true
-		}
-
-		if !cmp.Equal(existingService.Spec.Selector, generatedService.Spec.Selector) {
-			existingService.Spec.Selector = generatedService.Spec.Selector
-			updated = true
-		}
-		if !cmp.Equal(generatedService.Spec.Ports, existingService.Spec.Ports, cmp.FilterPath(func(p cmp.Path) bool {
-			// We need to check all the service values but the NodePort, as this field is assigned by
-			// the K8S controlplane components.
-			return p.Last().String() == ".NodePort"
-		}, cmp.Ignore())) {
-			existingService.Spec.Ports = generatedService.Spec.Ports
-			updated = true
-		}
+		updated := updateDataPlaneIngressService(logger, dataPlane, existingService, generatedService)
 
 		if updated {
 			res, existingService, err := patch.ApplyPatchIfNotEmpty(ctx, cl, logger, existingService, old, updated)
@@ -385,3 +342,167 @@ func ensureIngressServiceForDataPlane(
 
 	return op.Created, generatedService, cl.Create(ctx, generatedService)
 }
+
+// updateDataPlaneIngressService updates the existing DataPlane ingress Service
+// in place with the fields of the generated one that the operator enforces.
+// It returns true when the existing Service was changed.
+func updateDataPlaneIngressService(
+	logger logr.Logger,
+	dataPlane *operatorv1beta1.DataPlane,
+	existing *corev1.Service,
+	generated *corev1.Service,
+) bool {
+	var updated bool
+	updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, generated.ObjectMeta,
+		// enforce all the annotations provided through the dataplane API
+		func(existingMeta metav1.ObjectMeta, generatedMeta metav1.ObjectMeta) (bool, metav1.ObjectMeta) {
+			metaToUpdate, updatedAnnotations, err := ensureDataPlaneIngressServiceAnnotationsUpdated(
+				dataPlane, existingMeta.Annotations, generatedMeta.Annotations,
+			)
+			if err != nil {
+				logger.Error(err, "failed to update annotations of existing ingress service for dataplane",
+					"dataplane", fmt.Sprintf("%s/%s", dataPlane.Namespace, dataPlane.Name),
+					"ingress_service", fmt.Sprintf("%s/%s", existing.Namespace, existing.Name))
+				return true, existingMeta
+			}
+			existingMeta.Annotations = updatedAnnotations
+			return metaToUpdate, existingMeta
+		})
+
+	if existing.Spec.Type != generated.Spec.Type {
+		existing.Spec.Type = generated.Spec.Type
+		updated = true
+	}
+
+	const (
+		defaultExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyCluster
+	)
+	// Do not update when
+	// - the existing service has the default value for ExternalTrafficPolicy
+	// - and the generated service has the default value for ExternalTrafficPolicy or is empty.
+	if !(existing.Spec.ExternalTrafficPolicy == defaultExternalTrafficPolicy &&
+		(generated.Spec.ExternalTrafficPolicy == "" || generated.Spec.ExternalTrafficPolicy == defaultExternalTrafficPolicy)) {
+		existing.Spec.ExternalTrafficPolicy = generated.Spec.ExternalTrafficPolicy
+		updated = true
+	}
+
+	if !cmp.Equal(existing.Spec.Selector, generated.Spec.Selector) {
+		existing.Spec.Selector = generated.Spec.Selector
+		updated = true
+	}
+	if !cmp.Equal(generated.Spec.Ports, existing.Spec.Ports, cmp.FilterPath(func(p cmp.Path) bool {
+		// We need to check all the service values but the NodePort, as this field is assigned by
+		// the K8S controlplane components.
+		return p.Last().String() == ".NodePort"
+	}, cmp.Ignore())) {
+		existing.Spec.Ports = generated.Spec.Ports
+		updated = true
+	}
+
+	return updated
+}
+
+const (
+	// IngressServiceConditionTypePrefix is the prefix of the types of the DataPlane
+	// conditions reporting the readiness of the additional ingress Services, which
+	// are followed by the name under which the Service has been configured.
+	IngressServiceConditionTypePrefix = "IngressService-"
+
+	// IngressServiceReasonAddressesAssigned indicates that the additional ingress
+	// Service has addresses assigned, which are listed in the DataPlane's status.addresses.
+	IngressServiceReasonAddressesAssigned kcfgconsts.ConditionReason = "AddressesAssigned"
+	// IngressServiceReasonAddressesPending indicates that the additional ingress
+	// Service has no addresses assigned yet.
+	IngressServiceReasonAddressesPending kcfgconsts.ConditionReason = "AddressesPending"
+)
+
+// ingressServiceConditionType returns the type of the DataPlane condition
+// reporting the readiness of the additional ingress Service with the provided name.
+func ingressServiceConditionType(name string) kcfgconsts.ConditionType {
+	return kcfgconsts.ConditionType(IngressServiceConditionTypePrefix + name)
+}
+
+// ensureAdditionalIngressServicesForDataPlane ensures that the additional ingress
+// Services configured for the DataPlane through the consts.DataPlaneIngressServicesAnnotation
+// annotation exist and are up to date. Additional ingress Services that are not
+// configured anymore are deleted.
+// The provided state is used as the consts.DataPlaneServiceStateLabel value so that
+// both "live" and "preview" Services can be managed.
+func ensureAdditionalIngressServicesForDataPlane(
+	ctx context.Context,
+	logger logr.Logger,
+	cl client.Client,
+	dataPlane *operatorv1beta1.DataPlane,
+	state string,
+	opts ...k8sresources.ServiceOpt,
+) (op.Result, []corev1.Service, error) {
+	servicesOpts, err := k8sresources.GetDataPlaneAdditionalIngressServices(dataPlane)
+	if err != nil {
+		return op.Noop, nil, err
+	}
+
+	existingServices, err := k8sutils.ListServicesForOwner(
+		ctx,
+		cl,
+		dataPlane.Namespace,
+		dataPlane.UID,
+		client.MatchingLabels{
+			consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+			consts.DataPlaneServiceTypeLabel:     string(consts.DataPlaneAdditionalIngressServiceLabelValue),
+			consts.DataPlaneServiceStateLabel:    state,
+		},
+	)
+	if err != nil {
+		return op.Noop, nil, fmt.Errorf("failed listing additional ingress Services for DataPlane %s/%s: %w", dataPlane.Namespace, dataPlane.Name, err)
+	}
+
+	configured := make(map[string]struct{}, len(servicesOpts))
+	for _, svcOpts := range servicesOpts {
+		configured[svcOpts.Name] = struct{}{}
+	}
+	var deleted bool
+	for i := range existingServices {
+		svc := &existingServices[i]
+		if _, ok := configured[svc.Labels[consts.DataPlaneIngressServiceNameLabel]]; ok {
+			continue
+		}
+		if err := dataplane.OwnedObjectPreDeleteHook(ctx, cl, svc); err != nil {
+			return op.Noop, nil, err
+		}
+		if err := cl.Delete(ctx, svc); client.IgnoreNotFound(err) != nil {
+			return op.Noop, nil, fmt.Errorf("failed deleting additional ingress Service %s: %w", svc.Name, err)
+		}
+		log.Debug(logger, "additional ingress Service deleted", "service", svc.Name)
+		deleted = true
+	}
+	if deleted {
+		return op.Deleted, nil, nil
+	}
+
+	services := make([]corev1.Service, 0, len(servicesOpts))
+	for _, svcOpts := range servicesOpts {
+		dp := k8sresources.DataPlaneForAdditionalIngressService(dataPlane, svcOpts)
+		serviceLabels := client.MatchingLabels{
+			consts.DataPlaneServiceTypeLabel:        string(consts.DataPlaneAdditionalIngressServiceLabelValue),
+			consts.DataPlaneIngressServiceNameLabel: svcOpts.Name,
+			consts.DataPlaneServiceStateLabel:       state,
+		}
+		serviceOpts := append(
+			[]k8sresources.ServiceOpt{
+				k8sresources.ServiceWithAdditionalIngressServiceGenerateName(dataPlane, svcOpts.Name),
+				k8sresources.ServicePortsFromDataPlaneIngressOpt(dp),
+			},
+			opts...,
+		)
+		res, svc, err := ensureIngressServiceForDataPlane(ctx, logger, cl, dp, serviceLabels, serviceOpts...)
+		if err != nil {
+			return op.Noop, nil, err
+		}
+		if res != op.Noop {
+			return res, nil, nil
+		}
+		services = append(services, *svc)
+	}
+
+	return op.Noop, services, nil
+}
@@ -331,3 +331,75 @@ func TestEnsureIngressServiceForDataPlane(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureAdditionalIngressServicesForDataPlane(t *testing.T) {
+	ctx := t.Context()
+	dp := builder.
+		NewDataPlaneBuilder().
+		WithObjectMeta(metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "dp-1",
+			UID:       "dp-uid",
+			Annotations: map[string]string{
+				consts.DataPlaneIngressServicesAnnotation: `[
+					{"name":"internal","type":"ClusterIP"},
+					{"name":"public","ports":[{"port":8080,"targetPort":8000}]}
+				]`,
+			},
+		}).
+		Build()
+
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		Build()
+	require.NoError(t, fakeClient.Create(ctx, dp))
+
+	ensure := func() (op.Result, []corev1.Service) {
+		res, services, err := ensureAdditionalIngressServicesForDataPlane(
+			ctx, logr.Discard(), fakeClient, dp, consts.DataPlaneStateLabelValueLive,
+		)
+		require.NoError(t, err)
+		return res, services
+	}
+
+	t.Log("creating the configured services one by one")
+	res, _ := ensure()
+	require.Equal(t, op.Created, res)
+	res, _ = ensure()
+	require.Equal(t, op.Created, res)
+	res, services := ensure()
+	require.Equal(t, op.Noop, res)
+	require.Len(t, services, 2)
+
+	svcs := make(map[string]corev1.Service, len(services))
+	for _, svc := range services {
+		require.Equal(t, string(consts.DataPlaneAdditionalIngressServiceLabelValue), svc.Labels[consts.DataPlaneServiceTypeLabel])
+		require.Equal(t, consts.DataPlaneStateLabelValueLive, svc.Labels[consts.DataPlaneServiceStateLabel])
+		svcs[svc.Labels[consts.DataPlaneIngressServiceNameLabel]] = svc
+	}
+	require.Contains(t, svcs, "internal")
+	require.Contains(t, svcs, "public")
+	require.Equal(t, corev1.ServiceTypeClusterIP, svcs["internal"].Spec.Type)
+	require.Equal(t, "dataplane-ingress-internal-dp-1-", svcs["internal"].GenerateName)
+	require.Equal(t, k8sresources.DefaultDataPlaneIngressServicePorts, svcs["internal"].Spec.Ports)
+	require.Equal(t, corev1.ServiceTypeLoadBalancer, svcs["public"].Spec.Type)
+	require.Len(t, svcs["public"].Spec.Ports, 1)
+	require.Equal(t, int32(8080), svcs["public"].Spec.Ports[0].Port)
+
+	t.Log("removing a service from the configuration prunes it")
+	dp.Annotations[consts.DataPlaneIngressServicesAnnotation] = `[{"name":"public","ports":[{"port":8080,"targetPort":8000}]}]`
+	res, _ = ensure()
+	require.Equal(t, op.Deleted, res)
+	res, services = ensure()
+	require.Equal(t, op.Noop, res)
+	require.Len(t, services, 1)
+	require.Equal(t, "public", services[0].Labels[consts.DataPlaneIngressServiceNameLabel])
+
+	t.Log("invalid configuration returns an error")
+	dp.Annotations[consts.DataPlaneIngressServicesAnnotation] = `[{"name":"public"},{"name":"public"}]`
+	_, _, err := ensureAdditionalIngressServicesForDataPlane(
+		ctx, logr.Discard(), fakeClient, dp, consts.DataPlaneStateLabelValueLive,
+	)
+	require.Error(t, err)
+}
@@ -0,0 +1,257 @@
+package dataplane
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	kcfgkonnect "github.com/kong/kubernetes-configuration/api/konnect"
+)
+
+// PlanOptions configures how the resources owned by a DataPlane are planned.
+// The options mirror the DataPlane controller's configuration.
+type PlanOptions struct {
+	// DefaultImage is the image used when the DataPlane does not specify one.
+	DefaultImage string
+	// DevelopmentMode disables the DataPlane image version validation.
+	DevelopmentMode bool
+	// EnforceConfig makes the plan compare the Deployment even when the
+	// DataPlane spec hash did not change.
+	EnforceConfig bool
+}
+
+// OwnedResourcesPlan holds the live and the desired state of the resources
+// owned by a DataPlane. Live objects are nil when they do not exist yet.
+// Desired objects are the live ones with the fields enforced by the operator
+// updated or the newly generated ones when they do not exist yet.
+type OwnedResourcesPlan struct {
+	LiveDeployment        *appsv1.Deployment
+	DesiredDeployment     *appsv1.Deployment
+	LiveIngressService    *corev1.Service
+	DesiredIngressService *corev1.Service
+}
+
+// PlanOwnedResources runs the DataPlane Deployment and ingress Service
+// generators and update rules for the provided DataPlane without changing
+// anything in the cluster.
+//
+// dataplane is expected to be the live DataPlane with the proposed spec set so
+// that its UID and status can be used to find the owned resources. Callbacks
+// registered with the controller are not run.
+func PlanOwnedResources(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+	opts PlanOptions,
+) (OwnedResourcesPlan, error) {
+	var (
+		plan OwnedResourcesPlan
+		err  error
+	)
+	plan.LiveIngressService, plan.DesiredIngressService, err = planIngressServiceForDataPlane(ctx, cl, dataplane)
+	if err != nil {
+		return OwnedResourcesPlan{}, err
+	}
+	plan.LiveDeployment, plan.DesiredDeployment, err = planDeploymentForDataPlane(ctx, cl, dataplane, opts)
+	if err != nil {
+		return OwnedResourcesPlan{}, err
+	}
+	return plan, nil
+}
+
+func planIngressServiceForDataPlane(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) (live *corev1.Service, desired *corev1.Service, err error) {
+	additionalServiceLabels := client.MatchingLabels{
+		consts.DataPlaneServiceStateLabel: consts.DataPlaneStateLabelValueLive,
+	}
+	matchingLabels := k8sresources.GetManagedLabelForOwner(dataplane)
+	matchingLabels[consts.DataPlaneServiceTypeLabel] = string(consts.DataPlaneIngressServiceLabelValue)
+	for k, v := range additionalServiceLabels {
+		matchingLabels[k] = v
+	}
+	services, err := k8sutils.ListServicesForOwner(ctx, cl, dataplane.Namespace, dataplane.UID, matchingLabels)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed listing Services for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+
+	generated, err := k8sresources.GenerateNewIngressServiceForDataPlane(dataplane,
+		k8sresources.LabelSelectorFromDataPlaneStatusSelectorServiceOpt(dataplane),
+		k8sresources.ServicePortsFromDataPlaneIngressOpt(dataplane),
+		matchingLabelsToServiceOpt(additionalServiceLabels),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	addAnnotationsForDataPlaneIngressService(generated, *dataplane)
+	k8sutils.SetOwnerForObject(generated, dataplane)
+
+	// The controller reduces the Services to a single one named as requested
+	// by the DataPlane spec, so that is the one which gets updated.
+	if len(services) == 0 {
+		return nil, generated, nil
+	}
+	live = &services[0]
+	if name := k8sresources.GetDataPlaneIngressServiceName(dataplane); name != "" {
+		found, ok := lo.Find(services, func(s corev1.Service) bool { return s.Name == name })
+		if !ok {
+			return nil, generated, nil
+		}
+		live = &found
+	}
+	desired = live.DeepCopy()
+	updateDataPlaneIngressService(logr.Discard(), dataplane, desired, generated)
+	return live, desired, nil
+}
+
+func planDeploymentForDataPlane(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+	opts PlanOptions,
+) (live *appsv1.Deployment, desired *appsv1.Deployment, err error) {
+	deploymentLabels := client.MatchingLabels{
+		consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueLive,
+	}
+	matchingLabels := k8sresources.GetManagedLabelForOwner(dataplane)
+	for k, v := range deploymentLabels {
+		matchingLabels[k] = v
+	}
+	deployments, err := k8sutils.ListDeploymentsForOwner(ctx, cl, dataplane.Namespace, dataplane.UID, matchingLabels)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed listing Deployments for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	// The cluster certificate is not reissued by the plan, the one mounted in
+	// the live Deployment is kept.
+	var clusterCertificateSecretName string
+	if len(deployments) > 0 {
+		live = &deployments[0]
+		clusterCertificateSecretName = k8sutils.GetSecretVolumeSecretName(live.Spec.Template.Spec, consts.ClusterCertificateVolume)
+	}
+
+	deploymentOpts := []k8sresources.DeploymentOpt{
+		labelSelectorFromDataPlaneStatusSelectorDeploymentOpt(dataplane),
+	}
+	if _, konnectApplied := k8sutils.GetCondition(kcfgkonnect.KonnectExtensionAppliedType, dataplane); konnectApplied {
+		deploymentOpts = append(deploymentOpts, statusReadyEndpointDeploymentOpt(dataplane))
+	}
+	customPlugins, err := planCustomPluginsForDataPlane(ctx, cl, dataplane)
+	if err != nil {
+		return nil, nil, err
+	}
+	deploymentOpts = append(deploymentOpts, withCustomPlugins(customPlugins...))
+
+	built, err := NewDeploymentBuilder(logr.Discard(), cl).
+		WithClusterCertificate(clusterCertificateSecretName).
+		WithOpts(deploymentOpts...).
+		WithDefaultImage(opts.DefaultImage).
+		WithAdditionalLabels(deploymentLabels).
+		build(ctx, dataplane, opts.DevelopmentMode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not build Deployment for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	}
+	if live == nil {
+		return nil, built.Unwrap(), nil
+	}
+
+	desired = live.DeepCopy()
+	if !opts.EnforceConfig {
+		matches, _, err := dataPlaneDeploymentSpecHashMatches(dataplane, live)
+		if err != nil {
+			return nil, nil, err
+		}
+		if matches {
+			return live, desired, nil
+		}
+	}
+	updateDataPlaneDeployment(dataplane, desired, built.Unwrap())
+	return live, desired, nil
+}
+
+// planCustomPluginsForDataPlane returns the custom plugins the DataPlane
+// Deployment would mount. Unlike ensureMappedConfigMapToKongPluginInstallationForDataPlane
+// it does not create nor update the ConfigMaps mapped to KongPluginInstallations.
+func planCustomPluginsForDataPlane(
+	ctx context.Context, cl client.Client, dataplane *operatorv1beta1.DataPlane,
+) ([]customPlugin, error) {
+	configMapsOwned, err := findCustomPluginConfigMapsOwnedByDataPlane(ctx, cl, dataplane)
+	if err != nil {
+		return nil, err
+	}
+
+	cps := make([]customPlugin, 0, len(dataplane.Spec.PluginsToInstall))
+	for _, kpiNN := range dataplane.Spec.PluginsToInstall {
+		kpiNN := types.NamespacedName(kpiNN)
+		if kpiNN.Namespace == "" {
+			kpiNN.Namespace = dataplane.Namespace
+		}
+		var kpi operatorv1alpha1.KongPluginInstallation
+		if err := cl.Get(ctx, kpiNN, &kpi); err != nil {
+			return nil, fmt.Errorf("could not get KongPluginInstallation %s: %w", kpiNN, err)
+		}
+
+		cp := customPlugin{
+			Name:        kpi.Name,
+			ConfigMapNN: types.NamespacedName{Namespace: dataplane.Namespace, Name: dataplane.Name + "-"},
+			Generation:  kpi.Generation,
+		}
+		// Until the plugin is stored in the underlying ConfigMaps the DataPlane
+		// waits for the KongPluginInstallation, its Deployment isn't changed.
+		if kpi.Status.UnderlyingConfigMapName != "" {
+			planned, requeue, err := customPluginForKongPluginInstallation(ctx, logr.Discard(), cl, kpi,
+				func(part string, underlyingCM corev1.ConfigMap) (corev1.ConfigMap, bool, error) {
+					return planMappedConfigMapForKongPluginInstallation(configMapsOwned, kpi, part, underlyingCM, dataplane), false, nil
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+			if !requeue {
+				cp = planned
+			}
+		}
+		cps = append(cps, cp)
+	}
+	return cps, nil
+}
+
+// planMappedConfigMapForKongPluginInstallation returns the ConfigMap
+// ensureMappedConfigMapForKongPluginInstallation would create or update.
+func planMappedConfigMapForKongPluginInstallation(
+	cms []corev1.ConfigMap,
+	kpi operatorv1alpha1.KongPluginInstallation,
+	part string,
+	underlyingCM corev1.ConfigMap,
+	dataplane *operatorv1beta1.DataPlane,
+) corev1.ConfigMap {
+	mapped := findMappedConfigMapsForKongPluginInstallation(cms, kpi, part)
+	if len(mapped) == 0 {
+		cm := newMappedConfigMapForKongPluginInstallation(kpi, part, underlyingCM, dataplane)
+		// A ConfigMap which does not exist yet is created with the DataPlane
+		// name as its generate name.
+		cm.Name = cm.GenerateName
+		return cm
+	}
+	cm := *mapped[0].DeepCopy()
+	cm.Data = underlyingCM.Data
+	if files := underlyingCM.Annotations[consts.AnnotationKongPluginInstallationFiles]; files != "" {
+		cm.Annotations[consts.AnnotationKongPluginInstallationFiles] = files
+	} else {
+		delete(cm.Annotations, consts.AnnotationKongPluginInstallationFiles)
+	}
+	return cm
+}
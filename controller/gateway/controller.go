@@ -14,6 +14,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -25,15 +26,19 @@ import (
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	controlplanecontroller "github.com/kong/gateway-operator/controller/pkg/controlplane"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets/ref"
 	"github.com/kong/gateway-operator/controller/pkg/watch"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/tracing"
 	gwtypes "github.com/kong/gateway-operator/internal/types"
 	"github.com/kong/gateway-operator/internal/utils/gatewayclass"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -60,6 +65,7 @@ type Reconciler struct {
 	DevelopmentMode       bool
 	DefaultDataPlaneImage string
 	KonnectEnabled        bool
+	EventRecorder         record.EventRecorder
 }
 
 // provisionDataPlaneFailRequeueAfter is the time duration after which we retry provisioning
@@ -83,7 +89,9 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		// Gateway that is supported, enqueue that Gateway.
 		Watches(
 			&operatorv1beta1.GatewayConfiguration{},
-			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForGatewayConfig),
+			gatewayConfigurationEventHandler{
+				EventHandler: handler.EnqueueRequestsFromMapFunc(r.listGatewaysForGatewayConfig),
+			},
 			builder.WithPredicates(predicate.NewPredicateFuncs(r.gatewayConfigurationMatchesController))).
 		// watch for updates to GatewayClasses, if any GatewayClasses change, enqueue
 		// reconciliation for all supported gateway objects which reference it.
@@ -120,11 +128,17 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 			),
 		)
 	}
-	return builder.Complete(r)
+	return builder.Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("Gateway",
+		pause.NewReconciler(r.Client, "Gateway", func(gw *gwtypes.Gateway) k8sutils.ConditionsAware {
+			return gatewayConditionsAndListenersAware(gw)
+		}, r),
+		tracing.WithWriterLinks(r.Client, &gwtypes.Gateway{}),
+	)))
 }
 
 // Reconcile moves the current state of an object to the intended state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	logger := log.GetLogger(ctx, "gateway", r.DevelopmentMode)
 
 	log.Trace(logger, "reconciling gateway resource")
@@ -386,7 +400,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	// DataPlane NetworkPolicies
 	log.Trace(logger, "ensuring DataPlane's NetworkPolicy exists")
-	createdOrUpdated, err := r.ensureDataPlaneHasNetworkPolicy(ctx, &gateway, dataplane, controlplane)
+	createdOrUpdated, err := r.ensureDataPlaneHasNetworkPolicy(ctx, &gateway, gatewayConfig, dataplane, controlplane)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -482,13 +496,7 @@ func (r *Reconciler) provisionDataPlane(
 	log.Trace(logger, "ensuring dataplane config is up to date")
 	// compare deployment option of dataplane with dataplane deployment option of gatewayconfiguration.
 	// if not configured in gatewayconfiguration, compare deployment option of dataplane with an empty one.
-	expectedDataPlaneOptions := &operatorv1beta1.DataPlaneOptions{}
-	if gatewayConfig.Spec.DataPlaneOptions != nil {
-		expectedDataPlaneOptions = gatewayConfigDataPlaneOptionsToDataPlaneOptions(gatewayConfig.Namespace, *gatewayConfig.Spec.DataPlaneOptions)
-	}
-	// Don't require setting defaults for DataPlane when using Gateway CRD.
-	setDataPlaneOptionsDefaults(expectedDataPlaneOptions, r.DefaultDataPlaneImage)
-	err = setDataPlaneIngressServicePorts(expectedDataPlaneOptions, gateway.Spec.Listeners)
+	expectedDataPlaneOptions, err := r.expectedDataPlaneOptions(gateway, gatewayConfig)
 	if err != nil {
 		errWrap := fmt.Errorf("dataplane creation failed - error: %w", err)
 		k8sutils.SetCondition(
@@ -498,12 +506,42 @@ func (r *Reconciler) provisionDataPlane(
 		return nil, errWrap
 	}
 
-	expectedDataPlaneOptions.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, expectedDataPlaneOptions.Extensions)
+	gatewayConfigHash, err := gatewayConfigurationDataPlaneHash(gatewayConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed calculating GatewayConfiguration hash: %w", err)
+	}
+
+	specUpToDate := dataplaneSpecDeepEqual(&dataplane.Spec.DataPlaneOptions, expectedDataPlaneOptions)
+	if !specUpToDate {
+		allowed, err := isDataPlaneUpdateAllowedByRollout(logger, gateway, gatewayConfig, dataplane, gatewayConfigHash)
+		if err != nil {
+			k8sutils.SetCondition(
+				createDataPlaneCondition(metav1.ConditionFalse, kcfgdataplane.UnableToProvisionReason, err.Error(), gateway.Generation),
+				gatewayConditionsAndListenersAware(gateway),
+			)
+			return nil, fmt.Errorf("failed checking GatewayConfiguration rollout for dataplane %s: %w", dataplane.Name, err)
+		}
+		if !allowed {
+			// Keep the current DataPlane spec until the rollout reaches this Gateway.
+			specUpToDate = true
+			gatewayConfigHash = dataplane.Annotations[consts.DataPlaneGatewayConfigurationHashAnnotation]
+		}
+	}
+
+	if specUpToDate && dataplane.Annotations[consts.DataPlaneGatewayConfigurationHashAnnotation] != gatewayConfigHash {
+		log.Trace(logger, "dataplane GatewayConfiguration hash is out of date")
+		oldDataPlane := dataplane.DeepCopy()
+		setDataPlaneGatewayConfigurationHash(dataplane, gatewayConfigHash)
+		if err := r.Client.Patch(ctx, dataplane, client.MergeFrom(oldDataPlane)); err != nil {
+			return nil, fmt.Errorf("failed patching the dataplane %s: %w", dataplane.Name, err)
+		}
+	}
 
-	if !dataplaneSpecDeepEqual(&dataplane.Spec.DataPlaneOptions, expectedDataPlaneOptions) {
+	if !specUpToDate {
 		log.Trace(logger, "dataplane config is out of date")
 		oldDataPlane := dataplane.DeepCopy()
 		dataplane.Spec.DataPlaneOptions = *expectedDataPlaneOptions
+		setDataPlaneGatewayConfigurationHash(dataplane, gatewayConfigHash)
 
 		if err = r.Client.Patch(ctx, dataplane, client.MergeFrom(oldDataPlane)); err != nil {
 			k8sutils.SetCondition(
@@ -590,19 +628,9 @@ func (r *Reconciler) provisionControlPlane(
 
 	// If we continue, there is only one controlplane.
 	controlPlane = controlplanes[0].DeepCopy()
-	r.setControlPlaneGatewayConfigDefaults(gateway, gatewayConfig, dataplane.Name, ingressService.Name, adminService.Name, controlPlane.Name)
 
 	log.Trace(logger, "ensuring controlplane config is up to date")
-	// compare deployment option of controlplane with controlplane deployment option of gatewayconfiguration.
-	// if not configured in gatewayconfiguration, compare deployment option of controlplane with an empty one.
-	expectedControlPlaneOptions := &operatorv1beta1.ControlPlaneOptions{}
-	if gatewayConfig.Spec.ControlPlaneOptions != nil {
-		expectedControlPlaneOptions = gatewayConfig.Spec.ControlPlaneOptions
-	}
-	// Don't require setting defaults for ControlPlane when using Gateway CRD.
-	setControlPlaneOptionsDefaults(expectedControlPlaneOptions)
-
-	expectedControlPlaneOptions.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, expectedControlPlaneOptions.Extensions)
+	expectedControlPlaneOptions := r.expectedControlPlaneOptions(gateway, gatewayConfig, dataplane.Name, ingressService.Name, adminService.Name, controlPlane.Name)
 
 	if !controlplanecontroller.SpecDeepEqual(&controlPlane.Spec.ControlPlaneOptions, expectedControlPlaneOptions) {
 		log.Trace(logger, "controlplane config is out of date")
@@ -637,6 +665,54 @@ func (r *Reconciler) provisionControlPlane(
 	return controlPlane
 }
 
+// expectedDataPlaneOptions returns the DataPlane options which the provided
+// GatewayConfiguration sets for the Gateway's DataPlane.
+func (r *Reconciler) expectedDataPlaneOptions(
+	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) (*operatorv1beta1.DataPlaneOptions, error) {
+	// compare deployment option of dataplane with dataplane deployment option of gatewayconfiguration.
+	// if not configured in gatewayconfiguration, compare deployment option of dataplane with an empty one.
+	expectedDataPlaneOptions := &operatorv1beta1.DataPlaneOptions{}
+	if gatewayConfig.Spec.DataPlaneOptions != nil {
+		expectedDataPlaneOptions = gatewayConfigDataPlaneOptionsToDataPlaneOptions(gatewayConfig.Namespace, *gatewayConfig.Spec.DataPlaneOptions)
+	}
+	// Don't require setting defaults for DataPlane when using Gateway CRD.
+	setDataPlaneOptionsDefaults(expectedDataPlaneOptions, r.DefaultDataPlaneImage)
+	if err := setDataPlaneIngressServicePorts(expectedDataPlaneOptions, gateway.Spec.Listeners); err != nil {
+		return nil, err
+	}
+
+	expectedDataPlaneOptions.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, expectedDataPlaneOptions.Extensions)
+	return expectedDataPlaneOptions, nil
+}
+
+// expectedControlPlaneOptions returns the ControlPlane options which the
+// provided GatewayConfiguration sets for the Gateway's ControlPlane. The
+// GatewayConfiguration's ControlPlane options are defaulted in place.
+func (r *Reconciler) expectedControlPlaneOptions(
+	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	dataplaneName,
+	dataplaneIngressServiceName,
+	dataplaneAdminServiceName,
+	controlPlaneName string,
+) *operatorv1beta1.ControlPlaneOptions {
+	r.setControlPlaneGatewayConfigDefaults(gateway, gatewayConfig, dataplaneName, dataplaneIngressServiceName, dataplaneAdminServiceName, controlPlaneName)
+
+	// compare deployment option of controlplane with controlplane deployment option of gatewayconfiguration.
+	// if not configured in gatewayconfiguration, compare deployment option of controlplane with an empty one.
+	expectedControlPlaneOptions := &operatorv1beta1.ControlPlaneOptions{}
+	if gatewayConfig.Spec.ControlPlaneOptions != nil {
+		expectedControlPlaneOptions = gatewayConfig.Spec.ControlPlaneOptions
+	}
+	// Don't require setting defaults for ControlPlane when using Gateway CRD.
+	setControlPlaneOptionsDefaults(expectedControlPlaneOptions)
+
+	expectedControlPlaneOptions.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, expectedControlPlaneOptions.Extensions)
+	return expectedControlPlaneOptions
+}
+
 // setControlPlaneOptionsDefaults sets the default ControlPlane options not overriding
 // what's been provided only filling in those fields that were unset or empty.
 func setControlPlaneOptionsDefaults(opts *operatorv1beta1.ControlPlaneOptions) {
@@ -1,5 +1,9 @@
 package gateway
 
+import (
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+)
+
 // -----------------------------------------------------------------------------
 // Gateway - Finalizers
 // -----------------------------------------------------------------------------
@@ -15,3 +19,24 @@ const (
 	// GatewayFinalizerCleanupNetworkPolicies is the finalizer to cleanup owned network policies.
 	GatewayFinalizerCleanupNetworkPolicies GatewayFinalizer = "gateway-operator.konghq.com/cleanup-network-policies"
 )
+
+// -----------------------------------------------------------------------------
+// GatewayConfiguration - Rollout Condition Constants
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayConfigurationGatewaysRolledOutType is the condition type set on
+	// GatewayConfigurations with a staged rollout configured. It aggregates the
+	// rollout progress of the Gateways using the GatewayConfiguration.
+	GatewayConfigurationGatewaysRolledOutType kcfgconsts.ConditionType = "GatewaysRolledOut"
+
+	// GatewayConfigurationRolloutReasonDone indicates that all the Gateways
+	// have been updated and are available.
+	GatewayConfigurationRolloutReasonDone kcfgconsts.ConditionReason = "Done"
+	// GatewayConfigurationRolloutReasonProgressing indicates that the rollout
+	// is in progress.
+	GatewayConfigurationRolloutReasonProgressing kcfgconsts.ConditionReason = "Progressing"
+	// GatewayConfigurationRolloutReasonPaused indicates that the rollout has
+	// been paused.
+	GatewayConfigurationRolloutReasonPaused kcfgconsts.ConditionReason = "Paused"
+)
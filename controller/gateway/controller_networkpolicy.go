@@ -0,0 +1,221 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - DataPlane NetworkPolicy options
+// -----------------------------------------------------------------------------
+
+// dataPlaneNetworkPolicyOptions is the user provided configuration of the
+// NetworkPolicy generated for a DataPlane. It is read from the
+// consts.DataPlaneNetworkPolicyAnnotation annotation.
+type dataPlaneNetworkPolicyOptions struct {
+	// Ingress configures additional ingress allow-lists.
+	Ingress *dataPlaneNetworkPolicyIngressOptions `json:"ingress,omitempty"`
+	// Egress enables egress restrictions for the DataPlane Pods.
+	// When nil, egress traffic is not restricted.
+	Egress *dataPlaneNetworkPolicyEgressOptions `json:"egress,omitempty"`
+}
+
+// dataPlaneNetworkPolicyIngressOptions configures the ingress rules of the
+// DataPlane's NetworkPolicy.
+type dataPlaneNetworkPolicyIngressOptions struct {
+	// ProxyFrom is the list of peers allowed to reach the proxy ports.
+	// When empty, the proxy ports can be reached from anywhere.
+	ProxyFrom []networkingv1.NetworkPolicyPeer `json:"proxyFrom,omitempty"`
+	// Rules are additional ingress rules appended to the generated ones.
+	Rules []networkingv1.NetworkPolicyIngressRule `json:"rules,omitempty"`
+}
+
+// dataPlaneNetworkPolicyEgressOptions configures the egress rules of the
+// DataPlane's NetworkPolicy.
+//
+// Note: responses to the ControlPlane's calls to the DataPlane Admin API are
+// allowed by the ingress rule limiting the Admin API port to the ControlPlane
+// Pods, as NetworkPolicies are stateful. No egress rule is needed for those.
+type dataPlaneNetworkPolicyEgressOptions struct {
+	// AllowDNS allows DNS (port 53 over UDP and TCP) egress traffic.
+	// Defaults to true.
+	AllowDNS *bool `json:"allowDNS,omitempty"`
+	// AllowedNamespaces is the list of namespaces, e.g. the ones where
+	// upstream services live, that the DataPlane Pods are allowed to reach.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// Rules are additional egress rules appended to the generated ones.
+	Rules []networkingv1.NetworkPolicyEgressRule `json:"rules,omitempty"`
+}
+
+// getDataPlaneNetworkPolicyOptions returns the NetworkPolicy options for the
+// provided DataPlane. The DataPlane's annotation takes precedence over the one
+// set on the GatewayConfiguration.
+// It returns nil options when none of the objects have the annotation set.
+func getDataPlaneNetworkPolicyOptions(
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	dataplane *operatorv1beta1.DataPlane,
+) (*dataPlaneNetworkPolicyOptions, error) {
+	var annotationSources []map[string]string
+	if dataplane != nil {
+		annotationSources = append(annotationSources, dataplane.Annotations)
+	}
+	if gatewayConfig != nil {
+		annotationSources = append(annotationSources, gatewayConfig.Annotations)
+	}
+
+	for _, annotations := range annotationSources {
+		value, ok := annotations[consts.DataPlaneNetworkPolicyAnnotation]
+		if !ok {
+			continue
+		}
+		var opts dataPlaneNetworkPolicyOptions
+		if err := json.Unmarshal([]byte(value), &opts); err != nil {
+			return nil, fmt.Errorf("failed parsing %s annotation: %w", consts.DataPlaneNetworkPolicyAnnotation, err)
+		}
+		return &opts, nil
+	}
+	return nil, nil
+}
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - DataPlane NetworkPolicy ports
+// -----------------------------------------------------------------------------
+
+// dataPlaneListenPorts holds the ports the DataPlane's proxy container listens on.
+type dataPlaneListenPorts struct {
+	AdminAPISSL intstr.IntOrString
+	Proxy       intstr.IntOrString
+	ProxySSL    intstr.IntOrString
+	Metrics     intstr.IntOrString
+}
+
+// resolveDataPlaneListenPorts returns the ports the DataPlane's proxy container
+// listens on. The listen configuration is resolved from the container's full
+// environment, which includes values referenced through `valueFrom` and the
+// ConfigMaps and Secrets referenced in `envFrom`.
+func resolveDataPlaneListenPorts(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) (dataPlaneListenPorts, error) {
+	ports := dataPlaneListenPorts{
+		AdminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+		Proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+		ProxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+		Metrics:     intstr.FromInt(consts.DataPlaneMetricsPort),
+	}
+
+	podTemplateSpec := dataplane.Spec.Deployment.PodTemplateSpec
+	if podTemplateSpec == nil {
+		return ports, nil
+	}
+	container := k8sutils.GetPodContainerByName(&podTemplateSpec.Spec, consts.DataPlaneProxyContainerName)
+	if container == nil {
+		return ports, nil
+	}
+
+	getListenConfig := func(envName string) (*kongListenConfig, error) {
+		value, found, err := k8sutils.GetEnvValueFromContainer(ctx, container, dataplane.Namespace, envName, cl)
+		if err != nil {
+			return nil, fmt.Errorf("failed resolving %s env: %w", envName, err)
+		}
+		// "off" disables the listener so there are no ports to allow.
+		if !found || value == "" || value == "off" {
+			return nil, nil
+		}
+		cfg, err := parseKongListenEnv(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %s env: %w", envName, err)
+		}
+		return &cfg, nil
+	}
+
+	proxyListen, err := getListenConfig("KONG_PROXY_LISTEN")
+	if err != nil {
+		return ports, err
+	}
+	if proxyListen != nil {
+		if proxyListen.Endpoint != nil {
+			ports.Proxy = intstr.FromInt(proxyListen.Endpoint.Port)
+		}
+		if proxyListen.SSLEndpoint != nil {
+			ports.ProxySSL = intstr.FromInt(proxyListen.SSLEndpoint.Port)
+		}
+	}
+
+	adminListen, err := getListenConfig("KONG_ADMIN_LISTEN")
+	if err != nil {
+		return ports, err
+	}
+	if adminListen != nil && adminListen.SSLEndpoint != nil {
+		ports.AdminAPISSL = intstr.FromInt(adminListen.SSLEndpoint.Port)
+	}
+
+	statusListen, err := getListenConfig("KONG_STATUS_LISTEN")
+	if err != nil {
+		return ports, err
+	}
+	if statusListen != nil && statusListen.Endpoint != nil {
+		ports.Metrics = intstr.FromInt(statusListen.Endpoint.Port)
+	}
+
+	return ports, nil
+}
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - DataPlane NetworkPolicy rules
+// -----------------------------------------------------------------------------
+
+// generateDataPlaneNetworkPolicyEgressRules returns the egress rules for the
+// provided options. It returns nil when no egress traffic is allowed, which
+// matches the NetworkPolicy returned by the API server.
+func generateDataPlaneNetworkPolicyEgressRules(
+	opts *dataPlaneNetworkPolicyEgressOptions,
+) []networkingv1.NetworkPolicyEgressRule {
+	var (
+		protocolTCP = corev1.ProtocolTCP
+		protocolUDP = corev1.ProtocolUDP
+		dnsPort     = intstr.FromInt(53)
+		rules       []networkingv1.NetworkPolicyEgressRule
+	)
+
+	if opts.AllowDNS == nil || *opts.AllowDNS {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolUDP, Port: &dnsPort},
+				{Protocol: &protocolTCP, Port: &dnsPort},
+			},
+		})
+	}
+
+	if len(opts.AllowedNamespaces) > 0 {
+		peers := make([]networkingv1.NetworkPolicyPeer, 0, len(opts.AllowedNamespaces))
+		for _, ns := range opts.AllowedNamespaces {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{
+				// NamespaceDefaultLabelName feature gate must be enabled for this to work
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"kubernetes.io/metadata.name": ns,
+					},
+				},
+			})
+		}
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			To: peers,
+		})
+	}
+
+	return append(rules, opts.Rules...)
+}
@@ -0,0 +1,308 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestGetDataPlaneNetworkPolicyOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		gatewayConfig *operatorv1beta1.GatewayConfiguration
+		dataplane     *operatorv1beta1.DataPlane
+		expected      *dataPlaneNetworkPolicyOptions
+		expectedErr   bool
+	}{
+		{
+			name:          "no annotations",
+			gatewayConfig: &operatorv1beta1.GatewayConfiguration{},
+			dataplane:     &operatorv1beta1.DataPlane{},
+		},
+		{
+			name: "annotation on GatewayConfiguration",
+			gatewayConfig: &operatorv1beta1.GatewayConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						consts.DataPlaneNetworkPolicyAnnotation: `{"egress":{"allowedNamespaces":["upstreams"]}}`,
+					},
+				},
+			},
+			dataplane: &operatorv1beta1.DataPlane{},
+			expected: &dataPlaneNetworkPolicyOptions{
+				Egress: &dataPlaneNetworkPolicyEgressOptions{
+					AllowedNamespaces: []string{"upstreams"},
+				},
+			},
+		},
+		{
+			name: "annotation on DataPlane takes precedence",
+			gatewayConfig: &operatorv1beta1.GatewayConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						consts.DataPlaneNetworkPolicyAnnotation: `{"egress":{"allowedNamespaces":["upstreams"]}}`,
+					},
+				},
+			},
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						consts.DataPlaneNetworkPolicyAnnotation: `{"egress":{"allowDNS":false}}`,
+					},
+				},
+			},
+			expected: &dataPlaneNetworkPolicyOptions{
+				Egress: &dataPlaneNetworkPolicyEgressOptions{
+					AllowDNS: lo.ToPtr(false),
+				},
+			},
+		},
+		{
+			name: "invalid annotation",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						consts.DataPlaneNetworkPolicyAnnotation: `{"egress":`,
+					},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := getDataPlaneNetworkPolicyOptions(tc.gatewayConfig, tc.dataplane)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, opts)
+		})
+	}
+}
+
+func TestResolveDataPlaneListenPorts(t *testing.T) {
+	dataPlaneWithProxyContainer := func(container corev1.Container) *operatorv1beta1.DataPlane {
+		container.Name = consts.DataPlaneProxyContainerName
+		return &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dp",
+				Namespace: "ns",
+			},
+			Spec: operatorv1beta1.DataPlaneSpec{
+				DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+					Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+						DeploymentOptions: operatorv1beta1.DeploymentOptions{
+							PodTemplateSpec: &corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{container},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		dataplane *operatorv1beta1.DataPlane
+		objects   []client.Object
+		expected  dataPlaneListenPorts
+	}{
+		{
+			name:      "defaults",
+			dataplane: dataPlaneWithProxyContainer(corev1.Container{}),
+			expected: dataPlaneListenPorts{
+				AdminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+				Proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+				ProxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+				Metrics:     intstr.FromInt(consts.DataPlaneMetricsPort),
+			},
+		},
+		{
+			name: "ports from env",
+			dataplane: dataPlaneWithProxyContainer(corev1.Container{
+				Env: []corev1.EnvVar{
+					{Name: "KONG_PROXY_LISTEN", Value: "0.0.0.0:8001 reuseport, 0.0.0.0:8444 http2 ssl"},
+					{Name: "KONG_ADMIN_LISTEN", Value: "0.0.0.0:8555 http2 ssl"},
+					{Name: "KONG_STATUS_LISTEN", Value: "0.0.0.0:8101"},
+				},
+			}),
+			expected: dataPlaneListenPorts{
+				AdminAPISSL: intstr.FromInt(8555),
+				Proxy:       intstr.FromInt(8001),
+				ProxySSL:    intstr.FromInt(8444),
+				Metrics:     intstr.FromInt(8101),
+			},
+		},
+		{
+			name: "ports from ConfigMap referenced in envFrom",
+			dataplane: dataPlaneWithProxyContainer(corev1.Container{
+				EnvFrom: []corev1.EnvFromSource{
+					{
+						Prefix: "KONG_",
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "kong-env"},
+						},
+					},
+				},
+			}),
+			objects: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kong-env",
+						Namespace: "ns",
+					},
+					Data: map[string]string{
+						"PROXY_LISTEN": "0.0.0.0:8002, 0.0.0.0:8445 ssl",
+						"ADMIN_LISTEN": "0.0.0.0:8556 ssl",
+					},
+				},
+			},
+			expected: dataPlaneListenPorts{
+				AdminAPISSL: intstr.FromInt(8556),
+				Proxy:       intstr.FromInt(8002),
+				ProxySSL:    intstr.FromInt(8445),
+				Metrics:     intstr.FromInt(consts.DataPlaneMetricsPort),
+			},
+		},
+		{
+			name: "disabled listener keeps the default port",
+			dataplane: dataPlaneWithProxyContainer(corev1.Container{
+				Env: []corev1.EnvVar{
+					{Name: "KONG_STATUS_LISTEN", Value: "off"},
+				},
+			}),
+			expected: dataPlaneListenPorts{
+				AdminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+				Proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+				ProxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+				Metrics:     intstr.FromInt(consts.DataPlaneMetricsPort),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tc.objects...).
+				Build()
+
+			ports, err := resolveDataPlaneListenPorts(t.Context(), cl, tc.dataplane)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ports)
+		})
+	}
+}
+
+func TestGenerateDataPlaneNetworkPolicy(t *testing.T) {
+	var (
+		protocolTCP = corev1.ProtocolTCP
+		protocolUDP = corev1.ProtocolUDP
+		dnsPort     = intstr.FromInt(53)
+		ports       = dataPlaneListenPorts{
+			AdminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+			Proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+			ProxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+			Metrics:     intstr.FromInt(consts.DataPlaneMetricsPort),
+		}
+		dataplane = &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{Name: "dp", Namespace: "ns"},
+		}
+		controlplane = &operatorv1beta1.ControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Name: "cp", Namespace: "ns"},
+		}
+		ingressNginxPeer = networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"},
+			},
+		}
+	)
+
+	t.Run("without options only ingress is restricted", func(t *testing.T) {
+		policy := generateDataPlaneNetworkPolicy("ns", dataplane, controlplane, ports, nil)
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+		require.Len(t, policy.Spec.Ingress, 3)
+		require.Empty(t, policy.Spec.Ingress[1].From)
+		require.Empty(t, policy.Spec.Egress)
+	})
+
+	t.Run("proxy ingress peers and additional rules", func(t *testing.T) {
+		extraRule := networkingv1.NetworkPolicyIngressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolTCP, Port: lo.ToPtr(intstr.FromInt(9000))},
+			},
+		}
+		policy := generateDataPlaneNetworkPolicy("ns", dataplane, controlplane, ports, &dataPlaneNetworkPolicyOptions{
+			Ingress: &dataPlaneNetworkPolicyIngressOptions{
+				ProxyFrom: []networkingv1.NetworkPolicyPeer{ingressNginxPeer},
+				Rules:     []networkingv1.NetworkPolicyIngressRule{extraRule},
+			},
+		})
+		require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+		require.Len(t, policy.Spec.Ingress, 4)
+		require.Equal(t, []networkingv1.NetworkPolicyPeer{ingressNginxPeer}, policy.Spec.Ingress[1].From)
+		require.Equal(t, extraRule, policy.Spec.Ingress[3])
+	})
+
+	t.Run("egress restrictions", func(t *testing.T) {
+		policy := generateDataPlaneNetworkPolicy("ns", dataplane, controlplane, ports, &dataPlaneNetworkPolicyOptions{
+			Egress: &dataPlaneNetworkPolicyEgressOptions{
+				AllowedNamespaces: []string{"upstreams"},
+			},
+		})
+		require.Equal(t, []networkingv1.PolicyType{
+			networkingv1.PolicyTypeIngress,
+			networkingv1.PolicyTypeEgress,
+		}, policy.Spec.PolicyTypes)
+		require.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+			{
+				Ports: []networkingv1.NetworkPolicyPort{
+					{Protocol: &protocolUDP, Port: &dnsPort},
+					{Protocol: &protocolTCP, Port: &dnsPort},
+				},
+			},
+			{
+				To: []networkingv1.NetworkPolicyPeer{
+					{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"kubernetes.io/metadata.name": "upstreams"},
+						},
+					},
+				},
+			},
+		}, policy.Spec.Egress)
+		require.False(t, lo.ContainsBy(policy.Spec.Egress, func(rule networkingv1.NetworkPolicyEgressRule) bool {
+			return lo.ContainsBy(rule.To, func(peer networkingv1.NetworkPolicyPeer) bool {
+				return peer.PodSelector != nil && peer.PodSelector.MatchLabels["app"] == controlplane.Name
+			})
+		}), "no egress rule to the ControlPlane Pods is needed for the replies to its Admin API calls")
+	})
+
+	t.Run("egress restrictions without DNS", func(t *testing.T) {
+		policy := generateDataPlaneNetworkPolicy("ns", dataplane, controlplane, ports, &dataPlaneNetworkPolicyOptions{
+			Egress: &dataPlaneNetworkPolicyEgressOptions{
+				AllowDNS: lo.ToPtr(false),
+			},
+		})
+		require.Contains(t, policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		require.Nil(t, policy.Spec.Egress)
+	})
+}
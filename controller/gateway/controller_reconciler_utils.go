@@ -63,10 +63,15 @@ func (r *Reconciler) createDataPlane(ctx context.Context,
 
 	dataplane.Spec.DataPlaneOptions.Extensions = extensions.MergeExtensions(gatewayConfig.Spec.Extensions, dataplane.Spec.DataPlaneOptions.Extensions)
 
+	gatewayConfigHash, err := gatewayConfigurationDataPlaneHash(gatewayConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed calculating GatewayConfiguration hash: %w", err)
+	}
+	setDataPlaneGatewayConfigurationHash(dataplane, gatewayConfigHash)
+
 	k8sutils.SetOwnerForObject(dataplane, gateway)
 	gatewayutils.LabelObjectAsGatewayManaged(dataplane)
-	err := r.Client.Create(ctx, dataplane)
-	if err != nil {
+	if err := r.Client.Create(ctx, dataplane); err != nil {
 		return nil, err
 	}
 	return dataplane, nil
@@ -255,6 +260,7 @@ func (r *Reconciler) getGatewayConfigForGatewayClass(ctx context.Context, gatewa
 func (r *Reconciler) ensureDataPlaneHasNetworkPolicy(
 	ctx context.Context,
 	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
 	dataplane *operatorv1beta1.DataPlane,
 	controlplane *operatorv1beta1.ControlPlane,
 ) (createdOrUpdate bool, err error) {
@@ -271,10 +277,15 @@ func (r *Reconciler) ensureDataPlaneHasNetworkPolicy(
 		return false, errors.New("number of networkPolicies reduced")
 	}
 
-	generatedPolicy, err := generateDataPlaneNetworkPolicy(gateway.Namespace, dataplane, controlplane)
+	ports, err := resolveDataPlaneListenPorts(ctx, r.Client, dataplane)
+	if err != nil {
+		return false, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
+	}
+	opts, err := getDataPlaneNetworkPolicyOptions(gatewayConfig, dataplane)
 	if err != nil {
 		return false, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
 	}
+	generatedPolicy := generateDataPlaneNetworkPolicy(gateway.Namespace, dataplane, controlplane, ports, opts)
 	k8sutils.SetOwnerForObject(generatedPolicy, gateway)
 	gatewayutils.LabelObjectAsGatewayManaged(generatedPolicy)
 
@@ -298,50 +309,25 @@ func (r *Reconciler) ensureDataPlaneHasNetworkPolicy(
 	return true, r.Client.Create(ctx, generatedPolicy)
 }
 
+// generateDataPlaneNetworkPolicy generates the NetworkPolicy for the provided
+// DataPlane. The ports are expected to be resolved from the DataPlane's proxy
+// container environment (see resolveDataPlaneListenPorts) and opts, when
+// non nil, extend the generated ingress and egress rules.
 func generateDataPlaneNetworkPolicy(
 	namespace string,
 	dataplane *operatorv1beta1.DataPlane,
 	controlplane *operatorv1beta1.ControlPlane,
-) (*networkingv1.NetworkPolicy, error) {
+	ports dataPlaneListenPorts,
+	opts *dataPlaneNetworkPolicyOptions,
+) *networkingv1.NetworkPolicy {
 	var (
 		protocolTCP     = corev1.ProtocolTCP
-		adminAPISSLPort = intstr.FromInt(consts.DataPlaneAdminAPIPort)
-		proxyPort       = intstr.FromInt(consts.DataPlaneProxyPort)
-		proxySSLPort    = intstr.FromInt(consts.DataPlaneProxySSLPort)
-		metricsPort     = intstr.FromInt(consts.DataPlaneMetricsPort)
+		adminAPISSLPort = ports.AdminAPISSL
+		proxyPort       = ports.Proxy
+		proxySSLPort    = ports.ProxySSL
+		metricsPort     = ports.Metrics
 	)
 
-	// Check if KONG_PROXY_LISTEN and/or KONG_ADMIN_LISTEN are set in
-	// DataPlaneDeploymentOptions and in that's the case then update NetworkPolicy
-	// ports accordingly to allow communication on those ports.
-	//
-	// Note: for now only direct env variable manipulation is allowed (through
-	// the .Env field in DataPlaneDeploymentOptions). EnvFrom is not taken into
-	// account when updating NetworkPolicy ports.
-	dpOpts := dataplane.Spec.DataPlaneOptions
-	container := k8sutils.GetPodContainerByName(&dpOpts.Deployment.PodTemplateSpec.Spec, consts.DataPlaneProxyContainerName)
-	if proxyListen := k8sutils.EnvValueByName(container.Env, "KONG_PROXY_LISTEN"); proxyListen != "" {
-		kongListenConfig, err := parseKongListenEnv(proxyListen)
-		if err != nil {
-			return nil, fmt.Errorf("failed parsing KONG_PROXY_LISTEN env: %w", err)
-		}
-		if kongListenConfig.Endpoint != nil {
-			proxyPort = intstr.FromInt(kongListenConfig.Endpoint.Port)
-		}
-		if kongListenConfig.SSLEndpoint != nil {
-			proxySSLPort = intstr.FromInt(kongListenConfig.SSLEndpoint.Port)
-		}
-	}
-	if adminListen := k8sutils.EnvValueByName(container.Env, "KONG_ADMIN_LISTEN"); adminListen != "" {
-		kongListenConfig, err := parseKongListenEnv(adminListen)
-		if err != nil {
-			return nil, fmt.Errorf("failed parsing KONG_ADMIN_LISTEN env: %w", err)
-		}
-		if kongListenConfig.SSLEndpoint != nil {
-			adminAPISSLPort = intstr.FromInt(kongListenConfig.SSLEndpoint.Port)
-		}
-	}
-
 	limitAdminAPIIngress := networkingv1.NetworkPolicyIngressRule{
 		Ports: []networkingv1.NetworkPolicyPort{
 			{Protocol: &protocolTCP, Port: &adminAPISSLPort},
@@ -367,6 +353,9 @@ func generateDataPlaneNetworkPolicy(
 			{Protocol: &protocolTCP, Port: &proxySSLPort},
 		},
 	}
+	if opts != nil && opts.Ingress != nil {
+		allowProxyIngress.From = opts.Ingress.ProxyFrom
+	}
 
 	allowMetricsIngress := networkingv1.NetworkPolicyIngressRule{
 		Ports: []networkingv1.NetworkPolicyPort{
@@ -374,6 +363,23 @@ func generateDataPlaneNetworkPolicy(
 		},
 	}
 
+	var (
+		policyTypes  = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		ingressRules = []networkingv1.NetworkPolicyIngressRule{
+			limitAdminAPIIngress,
+			allowProxyIngress,
+			allowMetricsIngress,
+		}
+		egressRules []networkingv1.NetworkPolicyEgressRule
+	)
+	if opts != nil && opts.Ingress != nil {
+		ingressRules = append(ingressRules, opts.Ingress.Rules...)
+	}
+	if opts != nil && opts.Egress != nil {
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+		egressRules = generateDataPlaneNetworkPolicyEgressRules(opts.Egress)
+	}
+
 	return &networkingv1.NetworkPolicy{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:    namespace,
@@ -385,16 +391,11 @@ func generateDataPlaneNetworkPolicy(
 					"app": dataplane.Name,
 				},
 			},
-			PolicyTypes: []networkingv1.PolicyType{
-				networkingv1.PolicyTypeIngress,
-			},
-			Ingress: []networkingv1.NetworkPolicyIngressRule{
-				limitAdminAPIIngress,
-				allowProxyIngress,
-				allowMetricsIngress,
-			},
+			PolicyTypes: policyTypes,
+			Ingress:     ingressRules,
+			Egress:      egressRules,
 		},
-	}, nil
+	}
 }
 
 // ensureOwnedControlPlanesDeleted deletes all controlplanes owned by gateway.
@@ -0,0 +1,446 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	kcfgdataplane "github.com/kong/kubernetes-configuration/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - GatewayConfiguration staged rollout options
+// -----------------------------------------------------------------------------
+
+// defaultMaxUnavailableGateways is the default number of Gateways whose
+// DataPlane can be unavailable during a staged rollout.
+const defaultMaxUnavailableGateways = 1
+
+// gatewayConfigurationRolloutOptions is the user provided configuration of the
+// staged rollout of GatewayConfiguration's DataPlane options. It is read from
+// the consts.GatewayConfigurationRolloutAnnotation annotation.
+type gatewayConfigurationRolloutOptions struct {
+	// MaxUnavailableGateways is the maximum number of Gateways whose DataPlane
+	// can be unavailable during the rollout.
+	MaxUnavailableGateways *int `json:"maxUnavailableGateways,omitempty"`
+	// OrderByLabel is the key of the Gateway label used to order the rollout.
+	OrderByLabel string `json:"orderByLabel,omitempty"`
+}
+
+// getGatewayConfigurationRolloutOptions returns the staged rollout options of
+// the provided GatewayConfiguration. It returns nil options when the staged
+// rollout is not configured.
+func getGatewayConfigurationRolloutOptions(
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) (*gatewayConfigurationRolloutOptions, error) {
+	value, ok := gatewayConfig.Annotations[consts.GatewayConfigurationRolloutAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var opts gatewayConfigurationRolloutOptions
+	if err := json.Unmarshal([]byte(value), &opts); err != nil {
+		return nil, fmt.Errorf("failed parsing %s annotation: %w", consts.GatewayConfigurationRolloutAnnotation, err)
+	}
+	if opts.MaxUnavailableGateways == nil {
+		opts.MaxUnavailableGateways = lo.ToPtr(defaultMaxUnavailableGateways)
+	}
+	if *opts.MaxUnavailableGateways < 1 {
+		return nil, fmt.Errorf("invalid %s annotation: maxUnavailableGateways must be at least 1", consts.GatewayConfigurationRolloutAnnotation)
+	}
+	return &opts, nil
+}
+
+// isGatewayConfigurationRolloutPaused returns true when the staged rollout of
+// the provided GatewayConfiguration is paused.
+func isGatewayConfigurationRolloutPaused(gatewayConfig *operatorv1beta1.GatewayConfiguration) bool {
+	return gatewayConfig.Annotations[consts.GatewayConfigurationRolloutPausedAnnotation] == "true"
+}
+
+// gatewayConfigurationDataPlaneHash returns the hash of the parts of the
+// provided GatewayConfiguration that are applied to the DataPlanes.
+func gatewayConfigurationDataPlaneHash(gatewayConfig *operatorv1beta1.GatewayConfiguration) (string, error) {
+	return k8sresources.CalculateHash(struct {
+		DataPlaneOptions *operatorv1beta1.GatewayConfigDataPlaneOptions
+		Extensions       []commonv1alpha1.ExtensionRef
+	}{
+		DataPlaneOptions: gatewayConfig.Spec.DataPlaneOptions,
+		Extensions:       gatewayConfig.Spec.Extensions,
+	})
+}
+
+// setDataPlaneGatewayConfigurationHash sets the hash of the GatewayConfiguration
+// applied to the provided DataPlane.
+func setDataPlaneGatewayConfigurationHash(dataplane *operatorv1beta1.DataPlane, hash string) {
+	if dataplane.Annotations == nil {
+		dataplane.Annotations = map[string]string{}
+	}
+	dataplane.Annotations[consts.DataPlaneGatewayConfigurationHashAnnotation] = hash
+}
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - GatewayConfiguration staged rollout state
+// -----------------------------------------------------------------------------
+
+// gatewayRolloutState is the rollout state of a single Gateway.
+type gatewayRolloutState struct {
+	// Gateway is the Gateway's namespaced name.
+	Gateway client.ObjectKey
+	// OrderLabelValue is the value of the label the rollout is ordered by.
+	OrderLabelValue *string
+	// Updated is true when the Gateway's DataPlane has the current
+	// GatewayConfiguration's DataPlane options applied.
+	Updated bool
+	// Available is true when the Gateway's DataPlane is ready.
+	Available bool
+	// Claimed is true when the Gateway holds a rollout slot claimed on the
+	// GatewayConfiguration.
+	Claimed bool
+}
+
+// gatewayRolloutSlots are the rollout slots claimed by the Gateways whose
+// DataPlane is being updated. They are stored in the
+// consts.GatewayConfigurationRolloutSlotsAnnotation annotation.
+type gatewayRolloutSlots struct {
+	// Hash is the hash of the GatewayConfiguration's DataPlane options the
+	// slots were claimed for.
+	Hash string `json:"hash"`
+	// Gateways are the namespaced names of the Gateways holding a slot.
+	Gateways []string `json:"gateways,omitempty"`
+}
+
+// getGatewayRolloutSlots returns the rollout slots claimed on the provided
+// GatewayConfiguration for the provided hash. Slots claimed for a different
+// hash are ignored.
+func getGatewayRolloutSlots(
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	targetHash string,
+) gatewayRolloutSlots {
+	slots := gatewayRolloutSlots{Hash: targetHash}
+	value, ok := gatewayConfig.Annotations[consts.GatewayConfigurationRolloutSlotsAnnotation]
+	if !ok {
+		return slots
+	}
+	var claimed gatewayRolloutSlots
+	// An invalid annotation is overwritten with the next claim.
+	if err := json.Unmarshal([]byte(value), &claimed); err != nil || claimed.Hash != targetHash {
+		return slots
+	}
+	return claimed
+}
+
+// markClaimedGatewayRolloutStates sets Claimed on the states of the Gateways
+// holding one of the provided slots.
+func markClaimedGatewayRolloutStates(states []gatewayRolloutState, slots gatewayRolloutSlots) {
+	for i := range states {
+		states[i].Claimed = lo.Contains(slots.Gateways, states[i].Gateway.String())
+	}
+}
+
+// releaseGatewayRolloutSlots returns the slots without the slots of the
+// Gateways done with the rollout (or gone). The provided states are expected to
+// be marked with markClaimedGatewayRolloutStates.
+func releaseGatewayRolloutSlots(
+	states []gatewayRolloutState,
+	slots gatewayRolloutSlots,
+) gatewayRolloutSlots {
+	inProgress := lo.FilterMap(states, func(s gatewayRolloutState, _ int) (string, bool) {
+		return s.Gateway.String(), s.Claimed && (!s.Updated || !s.Available)
+	})
+	return gatewayRolloutSlots{
+		Hash: slots.Hash,
+		Gateways: lo.Filter(slots.Gateways, func(g string, _ int) bool {
+			return lo.Contains(inProgress, g)
+		}),
+	}
+}
+
+// claimGatewayRolloutSlots returns the slots with a slot claimed for each of the
+// outdated Gateways the rollout allows to be updated, in the rollout order.
+// The provided states, sorted in the rollout order, are marked as claimed
+// accordingly so that each claim uses the unavailability budget.
+func claimGatewayRolloutSlots(
+	states []gatewayRolloutState,
+	slots gatewayRolloutSlots,
+	maxUnavailable int,
+) gatewayRolloutSlots {
+	claimed := gatewayRolloutSlots{
+		Hash:     slots.Hash,
+		Gateways: slices.Clone(slots.Gateways),
+	}
+	for i := range states {
+		if states[i].Updated || states[i].Claimed {
+			continue
+		}
+		if !isGatewayRolloutAllowed(states, states[i].Gateway, maxUnavailable) {
+			continue
+		}
+		states[i].Claimed = true
+		claimed.Gateways = append(claimed.Gateways, states[i].Gateway.String())
+	}
+	return claimed
+}
+
+// listGatewayRolloutStates returns the rollout state of all the Gateways using
+// the provided GatewayConfiguration, sorted in the rollout order.
+//
+// The DataPlanes managed by Gateways are listed once and indexed by their
+// owners so that the number of requests does not depend on the number of
+// Gateways using the GatewayConfiguration.
+func listGatewayRolloutStates(
+	ctx context.Context,
+	cl client.Client,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	opts *gatewayConfigurationRolloutOptions,
+	targetHash string,
+) ([]gatewayRolloutState, error) {
+	gateways, err := listGatewaysUsingGatewayConfig(ctx, cl, gatewayConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataplaneList operatorv1beta1.DataPlaneList
+	if err := cl.List(ctx, &dataplaneList,
+		client.MatchingLabels{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+	); err != nil {
+		return nil, fmt.Errorf("failed listing DataPlanes managed by Gateways: %w", err)
+	}
+	dataplanesByOwner := make(map[types.UID][]*operatorv1beta1.DataPlane, len(dataplaneList.Items))
+	for i := range dataplaneList.Items {
+		dataplane := &dataplaneList.Items[i]
+		for _, ownerRef := range dataplane.OwnerReferences {
+			dataplanesByOwner[ownerRef.UID] = append(dataplanesByOwner[ownerRef.UID], dataplane)
+		}
+	}
+
+	states := make([]gatewayRolloutState, 0, len(gateways))
+	for i := range gateways {
+		gateway := &gateways[i]
+		state := gatewayRolloutState{
+			Gateway: client.ObjectKeyFromObject(gateway),
+		}
+		if opts.OrderByLabel != "" {
+			if v, ok := gateway.Labels[opts.OrderByLabel]; ok {
+				state.OrderLabelValue = &v
+			}
+		}
+
+		// DataPlanes are namespaced and owned by a Gateway from their namespace.
+		dataplanes := lo.Filter(dataplanesByOwner[gateway.UID], func(dp *operatorv1beta1.DataPlane, _ int) bool {
+			return dp.Namespace == gateway.Namespace
+		})
+		if len(dataplanes) == 1 {
+			dataplane := dataplanes[0]
+			state.Updated = dataplane.Annotations[consts.DataPlaneGatewayConfigurationHashAnnotation] == targetHash
+			state.Available = isDataPlaneAvailable(dataplane)
+		}
+		states = append(states, state)
+	}
+
+	sortGatewayRolloutStates(states)
+	return states, nil
+}
+
+// isDataPlaneAvailable returns true when the provided DataPlane is ready and
+// its readiness has been observed for its current generation.
+func isDataPlaneAvailable(dataplane *operatorv1beta1.DataPlane) bool {
+	c, ok := k8sutils.GetCondition(kcfgdataplane.ReadyType, dataplane)
+	return ok && c.Status == metav1.ConditionTrue && c.ObservedGeneration == dataplane.Generation
+}
+
+// sortGatewayRolloutStates sorts the provided states in the rollout order:
+// Gateways with the order label come first, in ascending order of the label
+// values, then the Gateways without the label. Ties are broken by the Gateways'
+// namespaced names.
+func sortGatewayRolloutStates(states []gatewayRolloutState) {
+	sort.SliceStable(states, func(i, j int) bool {
+		if c := compareOrderLabelValues(states[i].OrderLabelValue, states[j].OrderLabelValue); c != 0 {
+			return c < 0
+		}
+		return states[i].Gateway.String() < states[j].Gateway.String()
+	})
+}
+
+// compareOrderLabelValues compares the provided order label values. Values are
+// compared numerically when both are integers. Missing values sort last.
+func compareOrderLabelValues(a, b *string) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	ai, aErr := strconv.Atoi(*a)
+	bi, bErr := strconv.Atoi(*b)
+	switch {
+	case aErr == nil && bErr == nil && ai < bi:
+		return -1
+	case aErr == nil && bErr == nil && ai > bi:
+		return 1
+	case aErr == nil && bErr == nil:
+		return 0
+	case *a < *b:
+		return -1
+	case *a > *b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// isGatewayRolloutAllowed returns true when the Gateway can have its DataPlane
+// updated given the rollout state of all the Gateways, sorted in the rollout
+// order.
+//
+// A Gateway holding a rollout slot or whose DataPlane is already unavailable
+// can always be updated. Gateways holding a slot count as unavailable until
+// their DataPlane is updated and available again. Otherwise all the Gateways
+// in the previous stages (i.e. with a lower order label value) have to be
+// updated and available, and the Gateway has to be among the first outdated
+// Gateways that fit in the unavailability budget.
+func isGatewayRolloutAllowed(
+	states []gatewayRolloutState,
+	gateway client.ObjectKey,
+	maxUnavailable int,
+) bool {
+	idx := -1
+	for i := range states {
+		if states[i].Gateway == gateway {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return false
+	}
+	if states[idx].Claimed || !states[idx].Available {
+		return true
+	}
+
+	unavailable := 0
+	for _, s := range states {
+		if !s.Available || (s.Claimed && !s.Updated) {
+			unavailable++
+		}
+	}
+	budget := maxUnavailable - unavailable
+	if budget <= 0 {
+		return false
+	}
+
+	for _, s := range states[:idx] {
+		if compareOrderLabelValues(s.OrderLabelValue, states[idx].OrderLabelValue) != 0 &&
+			(!s.Updated || !s.Available) {
+			return false
+		}
+		if !s.Updated && s.Available && !s.Claimed {
+			budget--
+		}
+	}
+	return budget > 0
+}
+
+// gatewayConfigurationRolloutCondition returns the condition aggregating the
+// rollout state of the Gateways using a GatewayConfiguration.
+func gatewayConfigurationRolloutCondition(
+	states []gatewayRolloutState,
+	paused bool,
+	generation int64,
+) metav1.Condition {
+	var updated, unavailable int
+	for _, s := range states {
+		if s.Updated {
+			updated++
+		}
+		if !s.Available {
+			unavailable++
+		}
+	}
+
+	var (
+		status  = metav1.ConditionFalse
+		reason  kcfgconsts.ConditionReason
+		message = fmt.Sprintf("%d/%d Gateways updated, %d unavailable", updated, len(states), unavailable)
+	)
+	switch {
+	case updated == len(states) && unavailable == 0:
+		status = metav1.ConditionTrue
+		reason = GatewayConfigurationRolloutReasonDone
+	case paused:
+		reason = GatewayConfigurationRolloutReasonPaused
+		message = "Rollout paused: " + message
+	default:
+		reason = GatewayConfigurationRolloutReasonProgressing
+	}
+
+	return k8sutils.NewConditionWithGeneration(
+		GatewayConfigurationGatewaysRolledOutType,
+		status,
+		reason,
+		message,
+		generation,
+	)
+}
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - GatewayConfiguration staged rollout
+// -----------------------------------------------------------------------------
+
+// isDataPlaneUpdateAllowedByRollout returns true when the Gateway's DataPlane
+// can be updated with the current GatewayConfiguration's DataPlane options.
+// Updates are always allowed when no staged rollout is configured or when the
+// DataPlane already has the current GatewayConfiguration applied, e.g. when the
+// update is caused by a change of the Gateway's listeners.
+//
+// Otherwise the update is allowed only when the Gateway holds a rollout slot,
+// claimed for it by the GatewayConfigurationRolloutReconciler, and the rollout
+// is not paused.
+func isDataPlaneUpdateAllowedByRollout(
+	logger logr.Logger,
+	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	dataplane *operatorv1beta1.DataPlane,
+	targetHash string,
+) (bool, error) {
+	opts, err := getGatewayConfigurationRolloutOptions(gatewayConfig)
+	if err != nil {
+		return false, err
+	}
+	if opts == nil {
+		return true, nil
+	}
+	if dataplane.Annotations[consts.DataPlaneGatewayConfigurationHashAnnotation] == targetHash {
+		return true, nil
+	}
+	if isGatewayConfigurationRolloutPaused(gatewayConfig) {
+		log.Debug(logger, "GatewayConfiguration rollout is paused, not updating dataplane")
+		return false, nil
+	}
+
+	slots := getGatewayRolloutSlots(gatewayConfig, targetHash)
+	if !lo.Contains(slots.Gateways, client.ObjectKeyFromObject(gateway).String()) {
+		log.Debug(logger, "GatewayConfiguration rollout does not allow updating dataplane yet")
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,537 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgdataplane "github.com/kong/kubernetes-configuration/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestGetGatewayConfigurationRolloutOptions(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    *gatewayConfigurationRolloutOptions
+		expectedErr bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "defaults",
+			annotations: map[string]string{
+				consts.GatewayConfigurationRolloutAnnotation: `{}`,
+			},
+			expected: &gatewayConfigurationRolloutOptions{
+				MaxUnavailableGateways: lo.ToPtr(1),
+			},
+		},
+		{
+			name: "all options set",
+			annotations: map[string]string{
+				consts.GatewayConfigurationRolloutAnnotation: `{"maxUnavailableGateways":3,"orderByLabel":"stage"}`,
+			},
+			expected: &gatewayConfigurationRolloutOptions{
+				MaxUnavailableGateways: lo.ToPtr(3),
+				OrderByLabel:           "stage",
+			},
+		},
+		{
+			name: "invalid maxUnavailableGateways",
+			annotations: map[string]string{
+				consts.GatewayConfigurationRolloutAnnotation: `{"maxUnavailableGateways":0}`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "malformed annotation",
+			annotations: map[string]string{
+				consts.GatewayConfigurationRolloutAnnotation: `maxUnavailableGateways: 1`,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			opts, err := getGatewayConfigurationRolloutOptions(gatewayConfig)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, opts)
+		})
+	}
+}
+
+func TestSortGatewayRolloutStates(t *testing.T) {
+	states := []gatewayRolloutState{
+		{Gateway: client.ObjectKey{Namespace: "ns", Name: "no-label-b"}},
+		{Gateway: client.ObjectKey{Namespace: "ns", Name: "stage-10"}, OrderLabelValue: lo.ToPtr("10")},
+		{Gateway: client.ObjectKey{Namespace: "ns", Name: "no-label-a"}},
+		{Gateway: client.ObjectKey{Namespace: "ns", Name: "stage-2"}, OrderLabelValue: lo.ToPtr("2")},
+		{Gateway: client.ObjectKey{Namespace: "ns", Name: "canary"}, OrderLabelValue: lo.ToPtr("0")},
+	}
+	sortGatewayRolloutStates(states)
+
+	names := make([]string, 0, len(states))
+	for _, s := range states {
+		names = append(names, s.Gateway.Name)
+	}
+	require.Equal(t, []string{"canary", "stage-2", "stage-10", "no-label-a", "no-label-b"}, names)
+}
+
+func TestIsGatewayRolloutAllowed(t *testing.T) {
+	key := func(name string) client.ObjectKey {
+		return client.ObjectKey{Namespace: "ns", Name: name}
+	}
+
+	testCases := []struct {
+		name           string
+		states         []gatewayRolloutState
+		gateway        string
+		maxUnavailable int
+		expected       bool
+	}{
+		{
+			name: "first outdated gateway is allowed",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Available: true},
+				{Gateway: key("b"), Available: true},
+			},
+			gateway:        "a",
+			maxUnavailable: 1,
+			expected:       true,
+		},
+		{
+			name: "second outdated gateway has to wait for the first one",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Available: true},
+				{Gateway: key("b"), Available: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 1,
+			expected:       false,
+		},
+		{
+			name: "second outdated gateway is allowed with a bigger budget",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Available: true},
+				{Gateway: key("b"), Available: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 2,
+			expected:       true,
+		},
+		{
+			name: "no budget left when an updated gateway is unavailable",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Updated: true, Available: false},
+				{Gateway: key("b"), Available: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 1,
+			expected:       false,
+		},
+		{
+			name: "next gateway is allowed once the previous one is available",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Updated: true, Available: true},
+				{Gateway: key("b"), Available: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 1,
+			expected:       true,
+		},
+		{
+			name: "unavailable gateway is always allowed",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Updated: true, Available: false},
+				{Gateway: key("b"), Available: false},
+			},
+			gateway:        "b",
+			maxUnavailable: 1,
+			expected:       true,
+		},
+		{
+			name: "next stage waits for the canaries to be updated and available",
+			states: []gatewayRolloutState{
+				{Gateway: key("canary"), OrderLabelValue: lo.ToPtr("0"), Updated: true, Available: false},
+				{Gateway: key("b"), OrderLabelValue: lo.ToPtr("1"), Available: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 5,
+			expected:       false,
+		},
+		{
+			name: "next stage is allowed once the canaries are updated and available",
+			states: []gatewayRolloutState{
+				{Gateway: key("canary"), OrderLabelValue: lo.ToPtr("0"), Updated: true, Available: true},
+				{Gateway: key("b"), OrderLabelValue: lo.ToPtr("1"), Available: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 1,
+			expected:       true,
+		},
+		{
+			name: "claimed gateway counts as unavailable until updated",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Available: true, Claimed: true},
+				{Gateway: key("b"), Available: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 1,
+			expected:       false,
+		},
+		{
+			name: "gateway holding a slot is always allowed",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Updated: true, Available: false},
+				{Gateway: key("b"), Available: true, Claimed: true},
+			},
+			gateway:        "b",
+			maxUnavailable: 1,
+			expected:       true,
+		},
+		{
+			name: "unknown gateway is not allowed",
+			states: []gatewayRolloutState{
+				{Gateway: key("a"), Available: true},
+			},
+			gateway:        "unknown",
+			maxUnavailable: 1,
+			expected:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, isGatewayRolloutAllowed(tc.states, key(tc.gateway), tc.maxUnavailable))
+		})
+	}
+}
+
+func TestGatewayConfigurationRolloutCondition(t *testing.T) {
+	testCases := []struct {
+		name            string
+		states          []gatewayRolloutState
+		paused          bool
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name: "done",
+			states: []gatewayRolloutState{
+				{Updated: true, Available: true},
+				{Updated: true, Available: true},
+			},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  string(GatewayConfigurationRolloutReasonDone),
+			expectedMessage: "2/2 Gateways updated, 0 unavailable",
+		},
+		{
+			name: "progressing",
+			states: []gatewayRolloutState{
+				{Updated: true, Available: false},
+				{Updated: false, Available: true},
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(GatewayConfigurationRolloutReasonProgressing),
+			expectedMessage: "1/2 Gateways updated, 1 unavailable",
+		},
+		{
+			name: "paused",
+			states: []gatewayRolloutState{
+				{Updated: true, Available: true},
+				{Updated: false, Available: true},
+			},
+			paused:          true,
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  string(GatewayConfigurationRolloutReasonPaused),
+			expectedMessage: "Rollout paused: 1/2 Gateways updated, 0 unavailable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := gatewayConfigurationRolloutCondition(tc.states, tc.paused, 3)
+			require.Equal(t, string(GatewayConfigurationGatewaysRolledOutType), c.Type)
+			require.Equal(t, tc.expectedStatus, c.Status)
+			require.Equal(t, tc.expectedReason, c.Reason)
+			require.Equal(t, tc.expectedMessage, c.Message)
+			require.Equal(t, int64(3), c.ObservedGeneration)
+		})
+	}
+}
+
+func TestGatewayConfigurationDataPlaneHash(t *testing.T) {
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+		Spec: operatorv1beta1.GatewayConfigurationSpec{
+			DataPlaneOptions: &operatorv1beta1.GatewayConfigDataPlaneOptions{
+				Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+					DeploymentOptions: operatorv1beta1.DeploymentOptions{
+						Replicas: lo.ToPtr(int32(2)),
+					},
+				},
+			},
+		},
+	}
+	hash, err := gatewayConfigurationDataPlaneHash(gatewayConfig)
+	require.NoError(t, err)
+
+	gatewayConfig.Annotations = map[string]string{consts.GatewayConfigurationRolloutPausedAnnotation: "true"}
+	sameHash, err := gatewayConfigurationDataPlaneHash(gatewayConfig)
+	require.NoError(t, err)
+	require.Equal(t, hash, sameHash, "annotations should not affect the hash")
+
+	gatewayConfig.Spec.DataPlaneOptions.Deployment.Replicas = lo.ToPtr(int32(3))
+	newHash, err := gatewayConfigurationDataPlaneHash(gatewayConfig)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, newHash)
+}
+
+func TestClaimGatewayRolloutSlots(t *testing.T) {
+	key := func(name string) client.ObjectKey {
+		return client.ObjectKey{Namespace: "ns", Name: name}
+	}
+	states := []gatewayRolloutState{
+		{Gateway: key("a"), Updated: true, Available: true},
+		{Gateway: key("b"), Available: true, Claimed: true},
+		{Gateway: key("c"), Available: true},
+		{Gateway: key("d"), Available: true},
+		{Gateway: key("e"), Available: false},
+	}
+	slots := claimGatewayRolloutSlots(states, gatewayRolloutSlots{Hash: "new", Gateways: []string{"ns/b"}}, 3)
+	require.Equal(t, "new", slots.Hash)
+	require.Equal(t, []string{"ns/b", "ns/c", "ns/e"}, slots.Gateways)
+	require.True(t, states[2].Claimed)
+	require.False(t, states[3].Claimed)
+
+	t.Log("slots of the Gateways done with the rollout are released")
+	states[1].Updated = true
+	released := releaseGatewayRolloutSlots(states, slots)
+	require.Equal(t, []string{"ns/c", "ns/e"}, released.Gateways)
+}
+
+func TestGatewayConfigurationRolloutReconciler(t *testing.T) {
+	const (
+		namespace = "ns"
+		oldHash   = "old"
+	)
+	ctx := context.Background()
+
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gwconfig",
+			Namespace: namespace,
+			Annotations: map[string]string{
+				consts.GatewayConfigurationRolloutAnnotation: `{"maxUnavailableGateways": 1}`,
+			},
+		},
+	}
+	targetHash, err := gatewayConfigurationDataPlaneHash(gatewayConfig)
+	require.NoError(t, err)
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "gwclass"},
+		Spec: gatewayv1.GatewayClassSpec{
+			ParametersRef: &gatewayv1.ParametersReference{
+				Group:     gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+				Kind:      "GatewayConfiguration",
+				Name:      gatewayConfig.Name,
+				Namespace: lo.ToPtr(gatewayv1.Namespace(namespace)),
+			},
+		},
+	}
+	gateway := func(name string) *gwtypes.Gateway {
+		return &gwtypes.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				UID:       types.UID(name),
+			},
+			Spec: gatewayv1.GatewaySpec{GatewayClassName: gatewayv1.ObjectName(gatewayClass.Name)},
+		}
+	}
+	dataplane := func(gateway *gwtypes.Gateway) *operatorv1beta1.DataPlane {
+		return &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       gateway.Name,
+				Namespace:  namespace,
+				Generation: 1,
+				Labels: map[string]string{
+					consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue,
+				},
+				Annotations: map[string]string{
+					consts.DataPlaneGatewayConfigurationHashAnnotation: oldHash,
+				},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Gateway", UID: gateway.UID, Name: gateway.Name}},
+			},
+			Status: operatorv1beta1.DataPlaneStatus{
+				Conditions: []metav1.Condition{{
+					Type:               string(kcfgdataplane.ReadyType),
+					Status:             metav1.ConditionTrue,
+					ObservedGeneration: 1,
+				}},
+			},
+		}
+	}
+	gatewayA, gatewayB, gatewayC := gateway("a"), gateway("b"), gateway("c")
+	dataplaneA, dataplaneB, dataplaneC := dataplane(gatewayA), dataplane(gatewayB), dataplane(gatewayC)
+
+	var dataplaneLists int
+	fakeClient := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(gatewayConfig, gatewayClass, gatewayA, gatewayB, gatewayC, dataplaneA, dataplaneB, dataplaneC).
+		WithStatusSubresource(gatewayConfig, dataplaneA, dataplaneB, dataplaneC).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, cl client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*operatorv1beta1.DataPlaneList); ok {
+					dataplaneLists++
+				}
+				return cl.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+	r := GatewayConfigurationRolloutReconciler{Client: fakeClient}
+
+	getGatewayConfig := func(t *testing.T) *operatorv1beta1.GatewayConfiguration {
+		gc := &operatorv1beta1.GatewayConfiguration{}
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(gatewayConfig), gc))
+		return gc
+	}
+	reconcileGatewayConfig := func(t *testing.T) *operatorv1beta1.GatewayConfiguration {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(gatewayConfig)})
+		require.NoError(t, err)
+		return getGatewayConfig(t)
+	}
+	claimedGateways := func(t *testing.T, gc *operatorv1beta1.GatewayConfiguration) []string {
+		var slots gatewayRolloutSlots
+		require.NoError(t, json.Unmarshal([]byte(gc.Annotations[consts.GatewayConfigurationRolloutSlotsAnnotation]), &slots))
+		require.Equal(t, targetHash, slots.Hash)
+		return slots.Gateways
+	}
+	isAllowed := func(t *testing.T, gateway *gwtypes.Gateway, dataplane *operatorv1beta1.DataPlane) bool {
+		allowed, err := isDataPlaneUpdateAllowedByRollout(logr.Discard(), gateway, getGatewayConfig(t), dataplane, targetHash)
+		require.NoError(t, err)
+		return allowed
+	}
+
+	gc := reconcileGatewayConfig(t)
+	require.Equal(t, 1, dataplaneLists, "DataPlanes should be listed once regardless of the number of Gateways")
+	require.Equal(t, []string{"ns/a"}, claimedGateways(t, gc))
+	c, ok := k8sutils.GetCondition(GatewayConfigurationGatewaysRolledOutType, gc)
+	require.True(t, ok)
+	require.Equal(t, "0/3 Gateways updated, 0 unavailable", c.Message)
+	require.True(t, isAllowed(t, gatewayA, dataplaneA))
+	require.False(t, isAllowed(t, gatewayB, dataplaneB))
+
+	t.Log("claimed slot uses the unavailability budget until its DataPlane is updated")
+	gc = reconcileGatewayConfig(t)
+	require.Equal(t, []string{"ns/a"}, claimedGateways(t, gc))
+
+	t.Log("slot is released and the next one claimed once the DataPlane is updated and available")
+	dataplaneA.Annotations[consts.DataPlaneGatewayConfigurationHashAnnotation] = targetHash
+	require.NoError(t, fakeClient.Update(ctx, dataplaneA))
+	gc = reconcileGatewayConfig(t)
+	require.Equal(t, []string{"ns/b"}, claimedGateways(t, gc))
+	c, ok = k8sutils.GetCondition(GatewayConfigurationGatewaysRolledOutType, gc)
+	require.True(t, ok)
+	require.Equal(t, "1/3 Gateways updated, 0 unavailable", c.Message)
+	require.True(t, isAllowed(t, gatewayA, dataplaneA), "updated DataPlanes are always allowed")
+	require.True(t, isAllowed(t, gatewayB, dataplaneB))
+	require.False(t, isAllowed(t, gatewayC, dataplaneC))
+
+	t.Log("no slots are claimed while the rollout is paused")
+	gc.Annotations[consts.GatewayConfigurationRolloutPausedAnnotation] = "true"
+	require.NoError(t, fakeClient.Update(ctx, gc))
+	require.False(t, isAllowed(t, gatewayB, dataplaneB))
+	dataplaneB.Annotations[consts.DataPlaneGatewayConfigurationHashAnnotation] = targetHash
+	require.NoError(t, fakeClient.Update(ctx, dataplaneB))
+	gc = reconcileGatewayConfig(t)
+	require.Empty(t, claimedGateways(t, gc))
+	c, ok = k8sutils.GetCondition(GatewayConfigurationGatewaysRolledOutType, gc)
+	require.True(t, ok)
+	require.EqualValues(t, GatewayConfigurationRolloutReasonPaused, c.Reason)
+}
+
+func TestGatewayConfigurationEventHandler(t *testing.T) {
+	all := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "a"}},
+		{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "b"}},
+		{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "c"}},
+	}
+	h := gatewayConfigurationEventHandler{
+		EventHandler: handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+			return all
+		}),
+	}
+	gatewayConfig := func(generation int64, slots string) *operatorv1beta1.GatewayConfiguration {
+		return &operatorv1beta1.GatewayConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "gwconfig",
+				Namespace:  "ns",
+				Generation: generation,
+				Annotations: map[string]string{
+					consts.GatewayConfigurationRolloutAnnotation:      `{}`,
+					consts.GatewayConfigurationRolloutSlotsAnnotation: slots,
+				},
+			},
+		}
+	}
+	enqueued := func(oldObj, newObj client.Object) []reconcile.Request {
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer q.ShutDown()
+		h.Update(context.Background(), event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj}, q)
+		var reqs []reconcile.Request
+		for q.Len() > 0 {
+			req, _ := q.Get()
+			reqs = append(reqs, req)
+			q.Done(req)
+		}
+		return reqs
+	}
+
+	t.Log("slot updates only enqueue the Gateways granted a slot")
+	require.Equal(t,
+		[]reconcile.Request{all[2]},
+		enqueued(gatewayConfig(1, `{"hash":"new","gateways":["ns/a"]}`), gatewayConfig(1, `{"hash":"new","gateways":["ns/a","ns/c"]}`)),
+	)
+	require.Empty(t,
+		enqueued(gatewayConfig(1, `{"hash":"new","gateways":["ns/a","ns/c"]}`), gatewayConfig(1, `{"hash":"new","gateways":["ns/c"]}`)),
+	)
+
+	t.Log("other updates enqueue all the Gateways using the GatewayConfiguration")
+	require.ElementsMatch(t,
+		all,
+		enqueued(gatewayConfig(1, `{"hash":"new","gateways":["ns/a"]}`), gatewayConfig(2, `{"hash":"new","gateways":["ns/a"]}`)),
+	)
+}
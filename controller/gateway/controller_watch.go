@@ -2,15 +2,21 @@ package gateway
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"strings"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
@@ -155,48 +161,118 @@ func (r *Reconciler) listGatewaysForGatewayConfig(ctx context.Context, obj clien
 		return nil
 	}
 
-	gatewayClassList := new(gatewayv1.GatewayClassList)
-	if err := r.Client.List(ctx, gatewayClassList); err != nil {
+	gateways, err := listGatewaysUsingGatewayConfig(ctx, r.Client, gatewayConfig)
+	if err != nil {
 		ctrllog.FromContext(ctx).Error(
-			fmt.Errorf("unexpected error occurred while listing GatewayClass resources"),
+			fmt.Errorf("unexpected error occurred while listing Gateways using GatewayConfiguration"),
 			"failed to run map funcs",
 			"error", err.Error(),
 		)
 		return nil
 	}
 
+	recs := make([]reconcile.Request, 0, len(gateways))
+	for _, gateway := range gateways {
+		recs = append(recs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: gateway.Namespace,
+				Name:      gateway.Name,
+			},
+		})
+	}
+	return recs
+}
+
+// gatewayConfigurationEventHandler enqueues the Gateways using a GatewayConfiguration
+// on its changes with the wrapped EventHandler. Updates changing only the
+// GatewayConfiguration's status or its rollout slots, which are written at each
+// step of a staged rollout, only enqueue the Gateways which have been granted a
+// slot by the update, as the others are not affected.
+type gatewayConfigurationEventHandler struct {
+	handler.EventHandler
+}
+
+// Update implements handler.EventHandler.
+func (h gatewayConfigurationEventHandler) Update(
+	ctx context.Context,
+	e event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	oldGatewayConfig, okOld := e.ObjectOld.(*operatorv1beta1.GatewayConfiguration)
+	newGatewayConfig, okNew := e.ObjectNew.(*operatorv1beta1.GatewayConfiguration)
+	if !okOld || !okNew || !onlyGatewayRolloutProgressChanged(oldGatewayConfig, newGatewayConfig) {
+		h.EventHandler.Update(ctx, e, q)
+		return
+	}
+
+	// Invalid slots are treated as no slots claimed.
+	claimedSlots := func(gatewayConfig *operatorv1beta1.GatewayConfiguration) (slots gatewayRolloutSlots) {
+		_ = json.Unmarshal([]byte(gatewayConfig.Annotations[consts.GatewayConfigurationRolloutSlotsAnnotation]), &slots)
+		return slots
+	}
+	oldSlots, newSlots := claimedSlots(oldGatewayConfig), claimedSlots(newGatewayConfig)
+	for _, gateway := range newSlots.Gateways {
+		if oldSlots.Hash == newSlots.Hash && lo.Contains(oldSlots.Gateways, gateway) {
+			continue
+		}
+		namespace, name, ok := strings.Cut(gateway, "/")
+		if !ok {
+			continue
+		}
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+	}
+}
+
+// onlyGatewayRolloutProgressChanged returns true when the provided
+// GatewayConfigurations differ only in their status or rollout slots.
+func onlyGatewayRolloutProgressChanged(oldGatewayConfig, newGatewayConfig *operatorv1beta1.GatewayConfiguration) bool {
+	withoutSlots := func(annotations map[string]string) map[string]string {
+		annotations = maps.Clone(annotations)
+		delete(annotations, consts.GatewayConfigurationRolloutSlotsAnnotation)
+		return annotations
+	}
+	return oldGatewayConfig.Generation == newGatewayConfig.Generation &&
+		oldGatewayConfig.DeletionTimestamp.Equal(newGatewayConfig.DeletionTimestamp) &&
+		maps.Equal(oldGatewayConfig.Labels, newGatewayConfig.Labels) &&
+		maps.Equal(withoutSlots(oldGatewayConfig.Annotations), withoutSlots(newGatewayConfig.Annotations))
+}
+
+// listGatewaysUsingGatewayConfig returns all the Gateways whose GatewayClass
+// references the provided GatewayConfiguration.
+func listGatewaysUsingGatewayConfig(
+	ctx context.Context,
+	cl client.Client,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) ([]gwtypes.Gateway, error) {
+	gatewayClassList := new(gatewayv1.GatewayClassList)
+	if err := cl.List(ctx, gatewayClassList); err != nil {
+		return nil, fmt.Errorf("failed listing GatewayClass resources: %w", err)
+	}
+
 	matchingGatewayClasses := make(map[string]struct{})
 	for _, gatewayClass := range gatewayClassList.Items {
 		if gatewayClass.Spec.ParametersRef != nil &&
 			string(gatewayClass.Spec.ParametersRef.Group) == operatorv1beta1.SchemeGroupVersion.Group &&
 			string(gatewayClass.Spec.ParametersRef.Kind) == "GatewayConfiguration" &&
-			gatewayClass.Spec.ParametersRef.Name == gatewayConfig.Name {
+			gatewayClass.Spec.ParametersRef.Name == gatewayConfig.Name &&
+			(gatewayClass.Spec.ParametersRef.Namespace == nil ||
+				string(*gatewayClass.Spec.ParametersRef.Namespace) == gatewayConfig.Namespace) {
 			matchingGatewayClasses[gatewayClass.Name] = struct{}{}
 		}
 	}
 
 	gatewayList := new(gatewayv1.GatewayList)
-	if err := r.Client.List(ctx, gatewayList); err != nil {
-		ctrllog.FromContext(ctx).Error(
-			fmt.Errorf("unexpected error occurred while listing Gateway resources"),
-			"failed to run map funcs",
-			"error", err.Error(),
-		)
-		return nil
+	if err := cl.List(ctx, gatewayList); err != nil {
+		return nil, fmt.Errorf("failed listing Gateway resources: %w", err)
 	}
 
-	var recs []reconcile.Request
+	gateways := make([]gwtypes.Gateway, 0, len(gatewayList.Items))
 	for _, gateway := range gatewayList.Items {
 		if _, ok := matchingGatewayClasses[string(gateway.Spec.GatewayClassName)]; ok {
-			recs = append(recs, reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Namespace: gateway.Namespace,
-					Name:      gateway.Name,
-				},
-			})
+			gateways = append(gateways, gateway)
 		}
 	}
-	return recs
+	return gateways, nil
 }
 
 // listReferenceGrantsForGateway is a watch predicate which finds all Gateways mentioned in a From clause for a
@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/pkg/consts"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	"github.com/kong/gateway-operator/pkg/vars"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// PlanOptions configures how the changes of a GatewayConfiguration are planned.
+// The options mirror the Gateway controller's configuration.
+type PlanOptions struct {
+	// DefaultDataPlaneImage is the image used when the GatewayConfiguration
+	// does not specify one.
+	DefaultDataPlaneImage string
+	// DevelopmentMode is used to deduce whether anonymous reports are enabled
+	// for ControlPlanes.
+	DevelopmentMode bool
+}
+
+// GatewayPlan holds the DataPlane and the ControlPlane of a Gateway with the
+// spec which a proposed GatewayConfiguration would set. They are nil when the
+// Gateway does not have a single DataPlane or ControlPlane yet.
+type GatewayPlan struct {
+	Gateway      types.NamespacedName
+	DataPlane    *operatorv1beta1.DataPlane
+	ControlPlane *operatorv1beta1.ControlPlane
+}
+
+// PlanGatewayConfiguration finds the Gateways configured by the provided
+// GatewayConfiguration through their GatewayClasses and returns their live
+// DataPlanes and ControlPlanes with the spec the GatewayConfiguration would
+// set. Nothing is changed in the cluster. Staged rollouts of GatewayConfiguration
+// changes are not taken into account.
+func PlanGatewayConfiguration(
+	ctx context.Context,
+	cl client.Client,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	opts PlanOptions,
+) ([]GatewayPlan, error) {
+	r := &Reconciler{
+		Client:                cl,
+		DefaultDataPlaneImage: opts.DefaultDataPlaneImage,
+		DevelopmentMode:       opts.DevelopmentMode,
+	}
+
+	var gatewayClasses gatewayv1.GatewayClassList
+	if err := cl.List(ctx, &gatewayClasses); err != nil {
+		return nil, fmt.Errorf("failed listing GatewayClasses: %w", err)
+	}
+	classNames := make(map[gatewayv1.ObjectName]struct{})
+	for _, gwc := range gatewayClasses.Items {
+		if string(gwc.Spec.ControllerName) == vars.ControllerName() &&
+			gatewayClassReferencesGatewayConfiguration(&gwc, gatewayConfig) {
+			classNames[gatewayv1.ObjectName(gwc.Name)] = struct{}{}
+		}
+	}
+	if len(classNames) == 0 {
+		return nil, nil
+	}
+
+	var gateways gatewayv1.GatewayList
+	if err := cl.List(ctx, &gateways); err != nil {
+		return nil, fmt.Errorf("failed listing Gateways: %w", err)
+	}
+
+	var plans []GatewayPlan
+	for i := range gateways.Items {
+		gateway := &gateways.Items[i]
+		if _, ok := classNames[gateway.Spec.GatewayClassName]; !ok {
+			continue
+		}
+		plan, err := r.planGateway(ctx, gateway, gatewayConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed planning Gateway %s/%s: %w", gateway.Namespace, gateway.Name, err)
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+func (r *Reconciler) planGateway(
+	ctx context.Context,
+	gateway *gwtypes.Gateway,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) (GatewayPlan, error) {
+	plan := GatewayPlan{Gateway: client.ObjectKeyFromObject(gateway)}
+
+	dataplanes, err := gatewayutils.ListDataPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return GatewayPlan{}, err
+	}
+	if len(dataplanes) != 1 {
+		return plan, nil
+	}
+	dataplane := dataplanes[0].DeepCopy()
+	expectedDataPlaneOptions, err := r.expectedDataPlaneOptions(gateway, gatewayConfig.DeepCopy())
+	if err != nil {
+		return GatewayPlan{}, err
+	}
+	dataplane.Spec.DataPlaneOptions = *expectedDataPlaneOptions
+	plan.DataPlane = dataplane
+
+	controlplanes, err := gatewayutils.ListControlPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return GatewayPlan{}, err
+	}
+	if len(controlplanes) != 1 {
+		return plan, nil
+	}
+	ingressServiceName, err := gatewayutils.GetDataPlaneServiceName(ctx, r.Client, dataplane, consts.DataPlaneIngressServiceLabelValue)
+	if err != nil {
+		return GatewayPlan{}, err
+	}
+	adminServiceName, err := gatewayutils.GetDataPlaneServiceName(ctx, r.Client, dataplane, consts.DataPlaneAdminServiceLabelValue)
+	if err != nil {
+		return GatewayPlan{}, err
+	}
+	controlplane := controlplanes[0].DeepCopy()
+	controlplane.Spec.ControlPlaneOptions = *r.expectedControlPlaneOptions(
+		gateway, gatewayConfig.DeepCopy(), dataplane.Name, ingressServiceName, adminServiceName, controlplane.Name,
+	)
+	plan.ControlPlane = controlplane
+
+	return plan, nil
+}
+
+// gatewayClassReferencesGatewayConfiguration returns true when the GatewayClass'
+// parametersRef points to the provided GatewayConfiguration.
+func gatewayClassReferencesGatewayConfiguration(
+	gatewayClass *gatewayv1.GatewayClass,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+) bool {
+	ref := gatewayClass.Spec.ParametersRef
+	return ref != nil &&
+		string(ref.Group) == operatorv1beta1.SchemeGroupVersion.Group &&
+		string(ref.Kind) == "GatewayConfiguration" &&
+		ref.Namespace != nil && string(*ref.Namespace) == gatewayConfig.Namespace &&
+		ref.Name == gatewayConfig.Name
+}
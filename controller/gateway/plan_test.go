@@ -0,0 +1,111 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	"github.com/kong/gateway-operator/pkg/vars"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestPlanGatewayConfiguration(t *testing.T) {
+	gatewayClass := func(name, gatewayConfigName string) *gatewayv1.GatewayClass {
+		return &gatewayv1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gatewayv1.GatewayClassSpec{
+				ControllerName: gatewayv1.GatewayController(vars.ControllerName()),
+				ParametersRef: &gatewayv1.ParametersReference{
+					Group:     gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+					Kind:      "GatewayConfiguration",
+					Namespace: lo.ToPtr(gatewayv1.Namespace("ns")),
+					Name:      gatewayConfigName,
+				},
+			},
+		}
+	}
+	gateway := func(name, className string) *gatewayv1.Gateway {
+		gw := &gatewayv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID(name + "-uid")},
+			Spec: gatewayv1.GatewaySpec{
+				GatewayClassName: gatewayv1.ObjectName(className),
+				Listeners: []gatewayv1.Listener{
+					{Name: "http", Protocol: gatewayv1.HTTPProtocolType, Port: 80},
+				},
+			},
+		}
+		gw.SetGroupVersionKind(gatewayv1.SchemeGroupVersion.WithKind("Gateway"))
+		return gw
+	}
+	gw := gateway("gw", "kong")
+	otherGw := gateway("gw-other", "other")
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "gw-dp",
+			Labels:    map[string]string{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+		},
+	}
+	k8sutils.SetOwnerForObject(dataplane, gw)
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(
+			gatewayClass("kong", "gc"),
+			gatewayClass("other", "other-gc"),
+			gw,
+			otherGw,
+			dataplane,
+		).
+		Build()
+
+	gatewayConfig := &operatorv1beta1.GatewayConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "gc"},
+		Spec: operatorv1beta1.GatewayConfigurationSpec{
+			DataPlaneOptions: &operatorv1beta1.GatewayConfigDataPlaneOptions{
+				Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+					DeploymentOptions: operatorv1beta1.DeploymentOptions{
+						PodTemplateSpec: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: consts.DataPlaneProxyContainerName,
+										Env:  []corev1.EnvVar{{Name: "KONG_LOG_LEVEL", Value: "debug"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	plans, err := PlanGatewayConfiguration(context.Background(), cl, gatewayConfig, PlanOptions{
+		DefaultDataPlaneImage: consts.DefaultDataPlaneImage,
+	})
+	require.NoError(t, err)
+	require.Len(t, plans, 1, "only Gateways of GatewayClasses referencing the GatewayConfiguration are planned")
+	p := plans[0]
+	require.Equal(t, types.NamespacedName{Namespace: "ns", Name: "gw"}, p.Gateway)
+	require.Nil(t, p.ControlPlane, "Gateway has no ControlPlane yet")
+	require.NotNil(t, p.DataPlane)
+	require.Equal(t, dataplane.Name, p.DataPlane.Name)
+
+	container := k8sutils.GetPodContainerByName(&p.DataPlane.Spec.Deployment.PodTemplateSpec.Spec, consts.DataPlaneProxyContainerName)
+	require.NotNil(t, container)
+	require.Equal(t, consts.DefaultDataPlaneImage, container.Image)
+	require.Contains(t, container.Env, corev1.EnvVar{Name: "KONG_LOG_LEVEL", Value: "debug"})
+	require.Nil(t, gatewayConfig.Spec.ControlPlaneOptions, "proposed GatewayConfiguration is not modified")
+}
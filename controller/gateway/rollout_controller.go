@@ -0,0 +1,242 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/tracing"
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayConfigurationRolloutReconciler
+// -----------------------------------------------------------------------------
+
+// GatewayConfigurationRolloutReconciler drives the staged rollout of the
+// DataPlane options of the GatewayConfigurations configured with the
+// consts.GatewayConfigurationRolloutAnnotation annotation.
+//
+// For each GatewayConfiguration change, or change of the Gateways using it and
+// of their DataPlanes, it computes the rollout state of all the Gateways once,
+// claims rollout slots for the Gateways allowed to update their DataPlane and
+// reports the rollout progress in the GatewayConfiguration's status. The Gateway
+// Reconciler only updates the DataPlanes of the Gateways holding a slot.
+type GatewayConfigurationRolloutReconciler struct {
+	Client          client.Client
+	DevelopmentMode bool
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GatewayConfigurationRolloutReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	hasRolloutConfigured := func(obj client.Object) bool {
+		_, ok := obj.GetAnnotations()[consts.GatewayConfigurationRolloutAnnotation]
+		return ok
+	}
+	isManagedByGateway := func(obj client.Object) bool {
+		return obj.GetLabels()[consts.GatewayOperatorManagedByLabel] == consts.GatewayManagedLabelValue
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("GatewayConfigurationRollout").
+		// Status updates are written by this controller and do not affect the rollout.
+		For(&operatorv1beta1.GatewayConfiguration{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(hasRolloutConfigured),
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}),
+		)).
+		// watch Gateways so that the slots of the deleted ones are released and
+		// the added ones are taken into account.
+		Watches(
+			&gwtypes.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewayConfigurationsForGateway)).
+		// watch DataPlanes managed by Gateways to progress the rollout once they
+		// are updated and available.
+		Watches(
+			&operatorv1beta1.DataPlane{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewayConfigurationsForDataPlane),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isManagedByGateway))).
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("GatewayConfigurationRollout", r)))
+}
+
+// Reconcile claims the rollout slots for the Gateways allowed to update their
+// DataPlane and sets the condition aggregating the rollout progress on the
+// GatewayConfiguration.
+func (r *GatewayConfigurationRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "gatewayconfigurationrollout", r.DevelopmentMode)
+
+	var gatewayConfig operatorv1beta1.GatewayConfiguration
+	if err := r.Client.Get(ctx, req.NamespacedName, &gatewayConfig); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	opts, err := getGatewayConfigurationRolloutOptions(&gatewayConfig)
+	if err != nil || opts == nil {
+		return ctrl.Result{}, err
+	}
+	targetHash, err := gatewayConfigurationDataPlaneHash(&gatewayConfig)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed calculating GatewayConfiguration hash: %w", err)
+	}
+
+	states, err := listGatewayRolloutStates(ctx, r.Client, &gatewayConfig, opts, targetHash)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	paused := isGatewayConfigurationRolloutPaused(&gatewayConfig)
+
+	slots := getGatewayRolloutSlots(&gatewayConfig, targetHash)
+	markClaimedGatewayRolloutStates(states, slots)
+	slots = releaseGatewayRolloutSlots(states, slots)
+	markClaimedGatewayRolloutStates(states, slots)
+	if !paused {
+		slots = claimGatewayRolloutSlots(states, slots, *opts.MaxUnavailableGateways)
+	}
+
+	if updated, err := r.ensureGatewayRolloutSlots(ctx, logger, &gatewayConfig, slots); err != nil {
+		return ctrl.Result{}, err
+	} else if !updated {
+		// Another writer updated the GatewayConfiguration in the meantime, its
+		// update triggers another reconciliation which re-evaluates the rollout.
+		return ctrl.Result{}, nil
+	}
+
+	old := gatewayConfig.DeepCopy()
+	k8sutils.SetCondition(
+		gatewayConfigurationRolloutCondition(states, paused, gatewayConfig.Generation),
+		&gatewayConfig,
+	)
+	if k8sutils.NeedsUpdate(old, &gatewayConfig) {
+		log.Debug(logger, "updating GatewayConfiguration rollout status")
+		if err := r.Client.Status().Patch(ctx, &gatewayConfig, client.MergeFrom(old)); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed patching GatewayConfiguration %s status: %w", req.NamespacedName, err)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// ensureGatewayRolloutSlots ensures the provided slots are stored on the
+// GatewayConfiguration. The slots are written with the GatewayConfiguration's
+// resourceVersion so that they are never computed from a stale cache. It returns
+// false when the write conflicted.
+func (r *GatewayConfigurationRolloutReconciler) ensureGatewayRolloutSlots(
+	ctx context.Context,
+	logger logr.Logger,
+	gatewayConfig *operatorv1beta1.GatewayConfiguration,
+	slots gatewayRolloutSlots,
+) (bool, error) {
+	value, err := json.Marshal(slots)
+	if err != nil {
+		return false, fmt.Errorf("failed marshaling GatewayConfiguration rollout slots: %w", err)
+	}
+	if gatewayConfig.Annotations[consts.GatewayConfigurationRolloutSlotsAnnotation] == string(value) {
+		return true, nil
+	}
+
+	old := gatewayConfig.DeepCopy()
+	if gatewayConfig.Annotations == nil {
+		gatewayConfig.Annotations = map[string]string{}
+	}
+	gatewayConfig.Annotations[consts.GatewayConfigurationRolloutSlotsAnnotation] = string(value)
+	if err := r.Client.Patch(ctx, gatewayConfig, client.MergeFromWithOptions(old, client.MergeFromWithOptimisticLock{})); err != nil {
+		if k8serrors.IsConflict(err) || k8serrors.IsNotFound(err) {
+			log.Debug(logger, "GatewayConfiguration rollout slots update conflicted")
+			return false, nil
+		}
+		return false, fmt.Errorf("failed updating rollout slots on GatewayConfiguration %s: %w", client.ObjectKeyFromObject(gatewayConfig), err)
+	}
+	log.Debug(logger, "updated GatewayConfiguration rollout slots", "gateways", slots.Gateways)
+	return true, nil
+}
+
+// -----------------------------------------------------------------------------
+// GatewayConfigurationRolloutReconciler - Watch Map Funcs
+// -----------------------------------------------------------------------------
+
+// listGatewayConfigurationsForGateway is a watch map func which finds the
+// GatewayConfiguration used by a Gateway.
+func (r *GatewayConfigurationRolloutReconciler) listGatewayConfigurationsForGateway(ctx context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := obj.(*gwtypes.Gateway)
+	if !ok {
+		ctrllog.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "Gateway", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+	return r.listGatewayConfigurationsForGatewayClass(ctx, string(gateway.Spec.GatewayClassName))
+}
+
+// listGatewayConfigurationsForDataPlane is a watch map func which finds the
+// GatewayConfiguration used by the Gateway owning a DataPlane.
+func (r *GatewayConfigurationRolloutReconciler) listGatewayConfigurationsForDataPlane(ctx context.Context, obj client.Object) []reconcile.Request {
+	dataplane, ok := obj.(*operatorv1beta1.DataPlane)
+	if !ok {
+		ctrllog.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "DataPlane", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+
+	var recs []reconcile.Request
+	for _, ownerRef := range dataplane.OwnerReferences {
+		if ownerRef.Kind != "Gateway" {
+			continue
+		}
+		var gateway gwtypes.Gateway
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: dataplane.Namespace, Name: ownerRef.Name}, &gateway); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				ctrllog.FromContext(ctx).Error(err, "failed to run map funcs", "gateway", ownerRef.Name)
+			}
+			continue
+		}
+		recs = append(recs, r.listGatewayConfigurationsForGatewayClass(ctx, string(gateway.Spec.GatewayClassName))...)
+	}
+	return recs
+}
+
+// listGatewayConfigurationsForGatewayClass returns the request for the
+// GatewayConfiguration referenced by the GatewayClass with the provided name.
+func (r *GatewayConfigurationRolloutReconciler) listGatewayConfigurationsForGatewayClass(ctx context.Context, name string) []reconcile.Request {
+	var gatewayClass gatewayv1.GatewayClass
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: name}, &gatewayClass); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			ctrllog.FromContext(ctx).Error(err, "failed to run map funcs", "gatewayClass", name)
+		}
+		return nil
+	}
+
+	ref := gatewayClass.Spec.ParametersRef
+	if ref == nil ||
+		string(ref.Group) != operatorv1beta1.SchemeGroupVersion.Group ||
+		string(ref.Kind) != "GatewayConfiguration" ||
+		ref.Namespace == nil || *ref.Namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: client.ObjectKey{
+			Namespace: string(*ref.Namespace),
+			Name:      ref.Name,
+		},
+	}}
+}
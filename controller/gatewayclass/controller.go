@@ -7,6 +7,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -14,8 +15,11 @@ import (
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kong/gateway-operator/controller"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/internal/tracing"
 	"github.com/kong/gateway-operator/internal/utils/gatewayclass"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 )
 
@@ -29,6 +33,7 @@ type Reconciler struct {
 	Scheme                        *runtime.Scheme
 	DevelopmentMode               bool
 	GatewayAPIExperimentalEnabled bool
+	EventRecorder                 record.EventRecorder
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -36,11 +41,12 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayv1.GatewayClass{},
 			builder.WithPredicates(predicate.NewPredicateFuncs(r.gatewayClassMatches))).
-		Complete(r)
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("GatewayClass", r)))
 }
 
 // Reconcile moves the current state of an object to the intended state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	logger := log.GetLogger(ctx, "gatewayclass", r.DevelopmentMode)
 
 	log.Trace(logger, "reconciling gatewayclass resource")
@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	orascreds "oras.land/oras-go/v2/registry/remote/credentials"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -24,10 +26,15 @@ import (
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kong/gateway-operator/controller/kongplugininstallation/image"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/secrets/ref"
+	"github.com/kong/gateway-operator/internal/tracing"
+	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
+	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
-	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
 
 	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
 )
@@ -39,13 +46,29 @@ type Reconciler struct {
 	client.Client
 	Scheme          *runtime.Scheme
 	DevelopmentMode bool
+	EventRecorder   record.EventRecorder
+
+	// digestResolver resolves the digest of the manifest an image URL points
+	// to. It's used in tests, when nil image.ResolveDigest is used.
+	digestResolver func(context.Context, string, orascreds.Store) (string, error)
+	// pluginFetcher fetches the plugin from an image URL. It's used in tests,
+	// when nil image.FetchPlugin is used.
+	pluginFetcher func(context.Context, string, orascreds.Store) (image.PluginFiles, error)
+	// signatureVerifier verifies the signature of an image's manifest. It's used
+	// in tests, when nil image.VerifySignature is used.
+	signatureVerifier func(context.Context, string, string, image.TrustedKeys, orascreds.Store) error
+
+	// digestChecks records the last digest check of every KongPluginInstallation.
+	digestChecks digestChecks
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&operatorv1alpha1.KongPluginInstallation{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithEventFilter(predicate.Or(
+			predicate.GenerationChangedPredicate{}, pause.AnnotationChangedPredicate(), updateAnnotationsChangedPredicate(),
+		)).
 		Owns(&corev1.ConfigMap{}, builder.WithPredicates(
 			predicate.Funcs{
 				DeleteFunc: func(e event.DeleteEvent) bool {
@@ -62,15 +85,6 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 		Watches(
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.listKongPluginInstallationsForSecret),
-			builder.WithPredicates(
-				predicate.NewPredicateFuncs(func(obj client.Object) bool {
-					secret, ok := obj.(*corev1.Secret)
-					if !ok {
-						return false
-					}
-					return secret.Type == corev1.SecretTypeDockerConfigJson
-				}),
-			),
 		).
 		Watches(
 			&gatewayv1beta1.ReferenceGrant{},
@@ -81,23 +95,46 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 				),
 			),
 		).
-		Complete(r)
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("KongPluginInstallation",
+			pause.NewReconciler(r.Client, "KongPluginInstallation", kpiConditionsAware, r),
+		)))
 }
 
 // Reconcile moves the current state of an object to the intended state.
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	logger := log.GetLogger(ctx, "kongplugininstallation", r.DevelopmentMode)
 
 	log.Trace(logger, "reconciling KongPluginInstallation resource")
 	var kpi operatorv1alpha1.KongPluginInstallation
 	if err := r.Client.Get(ctx, req.NamespacedName, &kpi); err != nil {
+		if k8serrors.IsNotFound(err) {
+			r.digestChecks.delete(req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	if err := setStatusConditionForKongPluginInstallation(
-		ctx, r.Client, &kpi, metav1.ConditionFalse, operatorv1alpha1.KongPluginInstallationReasonPending, "fetching plugin is in progress",
-	); err != nil {
+	// A KongPluginInstallation already accepted is not marked as pending while
+	// checking whether its image's tag moved, so that the DataPlanes using the
+	// installed plugin aren't disrupted.
+	if !isAcceptedForCurrentGeneration(&kpi) {
+		if err := setStatusConditionForKongPluginInstallation(
+			ctx, r.Client, &kpi, metav1.ConditionFalse, operatorv1alpha1.KongPluginInstallationReasonPending, "fetching plugin is in progress",
+		); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	updateCfg, err := updateConfigForKongPluginInstallation(kpi.Annotations)
+	if err != nil {
+		return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, err.Error())
+	}
+	trustedKeys, msg, err := trustedKeysForKongPluginInstallation(ctx, r.Client, &kpi)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if msg != "" {
+		return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, msg)
+	}
 
 	log.Trace(logger, "managing KongPluginInstallation resource")
 	var credentialsStore orascreds.Store
@@ -142,63 +179,187 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				ctx, r.Client, &kpi, fmt.Sprintf("can't parse secret %q - unexpected type, it should follow 'kubernetes.io/dockerconfigjson'", secretNN),
 			)
 		}
-		var err error
 		credentialsStore, err = orascreds.NewMemoryStoreFromDockerConfig(secretData)
 		if err != nil {
 			return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("can't parse secret: %q data: %s", secretNN, err))
 		}
 	}
 
-	log.Trace(logger, "fetch plugin for KongPluginInstallation resource")
-	plugin, err := image.FetchPlugin(ctx, kpi.Spec.Image, credentialsStore)
-	if err != nil {
-		return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
-	}
-
 	cms, err := k8sutils.ListConfigMapsForOwner(ctx, r.Client, kpi.GetUID())
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	var cm corev1.ConfigMap
-	switch len(cms) {
+	underlyingCMs, partCMs := splitPluginConfigMaps(cms)
+	var cm *corev1.ConfigMap
+	switch len(underlyingCMs) {
 	case 0:
-		if cmName := kpi.Status.UnderlyingConfigMapName; cmName != "" {
-			cm.Name = cmName
-		} else {
-			cm.GenerateName = kpi.Name + "-"
+	case 1:
+		cm = &underlyingCMs[0]
+	default:
+		// It should never happen.
+		return ctrl.Result{}, errors.New("unexpected error happened - more than one ConfigMap found")
+	}
+	installed := installedPluginFor(cm)
+
+	log.Trace(logger, "resolve image digest for KongPluginInstallation resource")
+	now := time.Now()
+	check, err := r.resolveDigest(ctx, &kpi, updateCfg, credentialsStore, now)
+	if err != nil {
+		msg := fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err)
+		if installed.image != kpi.Spec.Image {
+			return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, msg)
 		}
-		k8sresources.LabelObjectAsKongPluginInstallationManaged(&cm)
-		k8sresources.AnnotateConfigMapWithKongPluginInstallation(&cm, kpi)
-		cm.Namespace = kpi.Namespace
-		cm.Data = plugin
-		if err := ctrl.SetControllerReference(&kpi, &cm, r.Scheme); err != nil {
+		// Keep the plugin of the same image installed when checking for an
+		// update fails, the check is retried with a backoff.
+		if err := setStatusConditionsForKongPluginInstallation(ctx, r.Client, &kpi, metav1.Condition{
+			Type:    ConditionTypeUpdateAvailable,
+			Status:  metav1.ConditionUnknown,
+			Reason:  string(operatorv1alpha1.KongPluginInstallationReasonFailed),
+			Message: msg,
+		}); err != nil {
 			return ctrl.Result{}, err
 		}
-		if err := r.Client.Create(ctx, &cm); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check for updates of image %s: %w", kpi.Spec.Image, err)
+	}
+
+	resolved := check.resolved
+	digest, updateAvailable := digestToInstall(kpi.Spec.Image, installed, resolved, updateCfg)
+	pinnedImage, err := image.PinImage(kpi.Spec.Image, digest)
+	if err != nil {
+		return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
+	}
+
+	var (
+		signatureVerifiedCondition *metav1.Condition
+		rejectedDigest             string
+	)
+	if trustedKeys != nil {
+		log.Trace(logger, "verify signature of image for KongPluginInstallation resource", "image", pinnedImage)
+		verifySignature := r.getSignatureVerifier()
+		signatureVerifiedCondition = &metav1.Condition{
+			Type:    ConditionTypeSignatureVerified,
+			Status:  metav1.ConditionTrue,
+			Reason:  ConditionReasonSignatureVerified,
+			Message: fmt.Sprintf("signature of image %s verified", pinnedImage),
+		}
+		if err := verifySignature(ctx, kpi.Spec.Image, digest, *trustedKeys, credentialsStore); err != nil {
+			msg := fmt.Sprintf("signature verification of image %s failed: %s", pinnedImage, err)
+			signatureVerifiedCondition.Status = metav1.ConditionFalse
+			signatureVerifiedCondition.Reason = string(KongPluginInstallationReasonSignatureVerificationFailed)
+			signatureVerifiedCondition.Message = msg
+			// Keep serving the installed digest of the same image when its
+			// signature is still valid, so that a rejected new digest doesn't
+			// disrupt the DataPlanes using the plugin.
+			if installed.image != kpi.Spec.Image || installed.digest == "" || installed.digest == digest ||
+				verifySignature(ctx, kpi.Spec.Image, installed.digest, *trustedKeys, credentialsStore) != nil {
+				return ctrl.Result{}, setStatusConditionsForKongPluginInstallation(
+					ctx, r.Client, &kpi,
+					metav1.Condition{
+						Type:    string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+						Status:  metav1.ConditionFalse,
+						Reason:  string(KongPluginInstallationReasonSignatureVerificationFailed),
+						Message: msg,
+					},
+					*signatureVerifiedCondition,
+				)
+			}
+			if !lo.ContainsBy(kpi.Status.Conditions, func(c metav1.Condition) bool {
+				return c.Type == ConditionTypeSignatureVerified && c.Message == msg
+			}) {
+				events.FromContext(ctx).Event(&kpi, corev1.EventTypeWarning, events.ReasonPluginDigestRejected,
+					fmt.Sprintf("%s, keeping the installed digest %s", msg, installed.digest))
+			}
+			rejectedDigest, digest = digest, installed.digest
+			if pinnedImage, err = image.PinImage(kpi.Spec.Image, digest); err != nil {
+				return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
+			}
+		}
+	}
+
+	if cm == nil || installed.image != kpi.Spec.Image || installed.digest != digest || !pluginConfigMapsComplete(cm, partCMs) {
+		log.Trace(logger, "fetch plugin for KongPluginInstallation resource", "image", pinnedImage)
+		plugin, err := r.getPluginFetcher()(ctx, pinnedImage, credentialsStore)
+		if err != nil {
+			return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
+		}
+		contents, err := splitPluginIntoConfigMaps(plugin)
+		if err != nil {
+			return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
+		}
+		if cm, err = r.ensurePluginConfigMaps(
+			ctx, &kpi, cm, partCMs, contents, installedPlugin{image: kpi.Spec.Image, digest: digest},
+		); err != nil {
 			return ctrl.Result{}, err
 		}
 		kpi.Status.UnderlyingConfigMapName = cm.Name
-	case 1:
-		cm = cms[0]
-		cm.Data = plugin
-		if err := r.Client.Update(ctx, &cm); err != nil {
-			return ctrl.Result{}, err
+
+		if installed.image == kpi.Spec.Image && installed.digest != "" {
+			events.FromContext(ctx).Event(&kpi, corev1.EventTypeNormal, events.ReasonPluginUpdated,
+				fmt.Sprintf("plugin updated from digest %s to %s of image %s", installed.digest, digest, kpi.Spec.Image))
 		}
-	default:
-		// It should never happen.
-		return ctrl.Result{}, errors.New("unexpected error happened - more than one ConfigMap found")
 	}
 
-	return ctrl.Result{}, setStatusConditionForKongPluginInstallation(
-		ctx, r.Client, &kpi, metav1.ConditionTrue, operatorv1alpha1.KongPluginInstallationReasonReady, "plugin successfully saved in cluster as ConfigMap",
+	updateAvailableCondition := metav1.Condition{
+		Type:    ConditionTypeUpdateAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  ConditionReasonUpToDate,
+		Message: fmt.Sprintf("image %s points to the installed digest", kpi.Spec.Image),
+	}
+	switch {
+	case rejectedDigest != "":
+		updateAvailableCondition.Status = metav1.ConditionTrue
+		updateAvailableCondition.Reason = ConditionReasonNewDigest
+		updateAvailableCondition.Message = fmt.Sprintf(
+			"image %s points to the new digest %s whose signature can't be verified, the installed digest %s is kept",
+			kpi.Spec.Image, rejectedDigest, digest,
+		)
+	case updateAvailable:
+		updateAvailableCondition.Status = metav1.ConditionTrue
+		updateAvailableCondition.Reason = ConditionReasonNewDigest
+		updateAvailableCondition.Message = fmt.Sprintf(
+			"image %s points to the new digest %s, set the %s annotation to it to install it",
+			kpi.Spec.Image, resolved, consts.KongPluginInstallationApprovedDigestAnnotation,
+		)
+		if !lo.ContainsBy(kpi.Status.Conditions, func(c metav1.Condition) bool {
+			return c.Type == ConditionTypeUpdateAvailable && c.Message == updateAvailableCondition.Message
+		}) {
+			events.FromContext(ctx).Event(&kpi, corev1.EventTypeNormal, events.ReasonPluginUpdateAvailable, updateAvailableCondition.Message)
+		}
+	}
+
+	conditions := []metav1.Condition{
+		{
+			Type:    string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(operatorv1alpha1.KongPluginInstallationReasonReady),
+			Message: "plugin successfully saved in cluster as ConfigMap",
+		},
+		{
+			Type:    ConditionTypeImagePinned,
+			Status:  metav1.ConditionTrue,
+			Reason:  ConditionReasonPinned,
+			Message: fmt.Sprintf("plugin fetched from image %s", pinnedImage),
+		},
+		updateAvailableCondition,
+	}
+	if signatureVerifiedCondition != nil {
+		conditions = append(conditions, *signatureVerifiedCondition)
+	} else if err := removeStatusConditionForKongPluginInstallation(ctx, r.Client, &kpi, ConditionTypeSignatureVerified); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: check.nextCheckAfter(now)}, setStatusConditionsForKongPluginInstallation(
+		ctx, r.Client, &kpi, conditions...,
 	)
 }
 
+// listKongPluginInstallationsForSecret returns the KongPluginInstallations
+// referencing the Secret, either as .spec.imagePullSecretRef or as the Secret
+// with the keys trusted to sign their image.
 func (r *Reconciler) listKongPluginInstallationsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
-	name, namespace := obj.GetName(), obj.GetNamespace()
-
 	var kpiList operatorv1alpha1.KongPluginInstallationList
-	if err := r.List(ctx, &kpiList); err != nil {
+	if err := r.List(ctx, &kpiList, client.MatchingFields{
+		index.SecretsIndex: obj.GetNamespace() + "/" + obj.GetName(),
+	}); err != nil {
 		ctrllog.FromContext(ctx).Error(
 			err,
 			"failed to run map funcs for secrets",
@@ -206,22 +367,11 @@ func (r *Reconciler) listKongPluginInstallationsForSecret(ctx context.Context, o
 		return nil
 	}
 
-	var recs []reconcile.Request
-	for _, kpi := range kpiList.Items {
-		if kpi.Spec.ImagePullSecretRef == nil {
-			continue
-		}
-		ref.EnsureNamespaceInSecretRef(kpi.Spec.ImagePullSecretRef, gatewayv1.Namespace(kpi.Namespace))
-		if err := ref.DoesFieldReferenceCoreV1Secret(*kpi.Spec.ImagePullSecretRef, "imagePullSecretRef"); err != nil {
-			continue
-		}
-		if string(*kpi.Spec.ImagePullSecretRef.Namespace) == namespace && string(kpi.Spec.ImagePullSecretRef.Name) == name {
-			recs = append(recs, reconcile.Request{
-				NamespacedName: client.ObjectKeyFromObject(&kpi),
-			})
+	return lo.Map(kpiList.Items, func(kpi operatorv1alpha1.KongPluginInstallation, _ int) reconcile.Request {
+		return reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&kpi),
 		}
-	}
-	return recs
+	})
 }
 
 func (r *Reconciler) listReferenceGrantsForKongPluginInstallation(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -261,25 +411,85 @@ func setStatusConditionFailedForKongPluginInstallation(
 func setStatusConditionForKongPluginInstallation(
 	ctx context.Context, client client.Client, kpi *operatorv1alpha1.KongPluginInstallation, conditionStatus metav1.ConditionStatus, reason operatorv1alpha1.KongPluginInstallationConditionReason, msg string,
 ) error {
-	status := metav1.Condition{
-		Type:               string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
-		Status:             conditionStatus,
-		ObservedGeneration: kpi.Generation,
-		LastTransitionTime: metav1.Now(),
-		Reason:             string(reason),
-		Message:            msg,
-	}
-	_, index, found := lo.FindIndexOf(kpi.Status.Conditions, func(c metav1.Condition) bool {
-		return c.Type == string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted)
+	return setStatusConditionsForKongPluginInstallation(ctx, client, kpi, metav1.Condition{
+		Type:    string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+		Status:  conditionStatus,
+		Reason:  string(reason),
+		Message: msg,
 	})
-	if found {
-		// Nothing changed, condition doesn't need to be updated.
-		if c := kpi.Status.Conditions[index]; c.Status == status.Status && c.Reason == status.Reason && c.Message == status.Message {
-			return nil
+}
+
+// setStatusConditionsForKongPluginInstallation sets the provided conditions in
+// the status of the KongPluginInstallation and updates it when any of them
+// changed.
+func setStatusConditionsForKongPluginInstallation(
+	ctx context.Context, client client.Client, kpi *operatorv1alpha1.KongPluginInstallation, conditions ...metav1.Condition,
+) error {
+	var changed bool
+	for _, status := range conditions {
+		status.ObservedGeneration = kpi.Generation
+		status.LastTransitionTime = metav1.Now()
+		_, index, found := lo.FindIndexOf(kpi.Status.Conditions, func(c metav1.Condition) bool {
+			return c.Type == status.Type
+		})
+		if found {
+			// Nothing changed, condition doesn't need to be updated.
+			if c := kpi.Status.Conditions[index]; c.Status == status.Status && c.Reason == status.Reason && c.Message == status.Message {
+				continue
+			}
+			kpi.Status.Conditions[index] = status
+		} else {
+			kpi.Status.Conditions = append(kpi.Status.Conditions, status)
 		}
-		kpi.Status.Conditions[index] = status
-	} else {
-		kpi.Status.Conditions = append(kpi.Status.Conditions, status)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return client.Status().Update(ctx, kpi)
+}
+
+// removeStatusConditionForKongPluginInstallation removes the condition of the
+// provided type from the status of the KongPluginInstallation when it's set.
+func removeStatusConditionForKongPluginInstallation(
+	ctx context.Context, client client.Client, kpi *operatorv1alpha1.KongPluginInstallation, conditionType string,
+) error {
+	conditions := lo.Reject(kpi.Status.Conditions, func(c metav1.Condition, _ int) bool {
+		return c.Type == conditionType
+	})
+	if len(conditions) == len(kpi.Status.Conditions) {
+		return nil
 	}
+	kpi.Status.Conditions = conditions
 	return client.Status().Update(ctx, kpi)
 }
+
+// isAcceptedForCurrentGeneration returns true when the current generation of
+// the KongPluginInstallation has already been accepted.
+func isAcceptedForCurrentGeneration(kpi *operatorv1alpha1.KongPluginInstallation) bool {
+	return lo.ContainsBy(kpi.Status.Conditions, func(c metav1.Condition) bool {
+		return c.Type == string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted) &&
+			c.Status == metav1.ConditionTrue &&
+			c.ObservedGeneration == kpi.Generation
+	})
+}
+
+// kpiConditionsAwareT makes KongPluginInstallation's status conditions accessible
+// through k8sutils.ConditionsAware.
+type kpiConditionsAwareT struct {
+	*operatorv1alpha1.KongPluginInstallation
+}
+
+func kpiConditionsAware(kpi *operatorv1alpha1.KongPluginInstallation) k8sutils.ConditionsAware {
+	return kpiConditionsAwareT{KongPluginInstallation: kpi}
+}
+
+// GetConditions returns the status conditions.
+func (k kpiConditionsAwareT) GetConditions() []metav1.Condition {
+	return k.Status.Conditions
+}
+
+// SetConditions sets the status conditions.
+func (k kpiConditionsAwareT) SetConditions(conditions []metav1.Condition) {
+	k.Status.Conditions = conditions
+}
@@ -0,0 +1,192 @@
+package kongplugininstallation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/kongplugininstallation/image"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// maxConfigMapDataSize is the maximum size of the files stored in a single
+// ConfigMap. It's below the 1 MiB limit of Kubernetes to leave room for the
+// ConfigMap's metadata.
+const maxConfigMapDataSize = 900 * 1024
+
+// pluginConfigMapContent is the content of one of the ConfigMaps a plugin is
+// stored in.
+type pluginConfigMapContent struct {
+	data map[string]string
+	// files maps the keys of data to the paths of the files in the plugin's
+	// directory. It's nil when all the files are stored under their names.
+	files map[string]string
+}
+
+// splitPluginIntoConfigMaps splits the files of the plugin into the contents
+// of as many ConfigMaps as needed. Files in subdirectories are stored under
+// keys with "/" replaced by ".", their paths are recorded in files.
+func splitPluginIntoConfigMaps(plugin image.PluginFiles) ([]pluginConfigMapContent, error) {
+	paths := lo.Keys(plugin)
+	slices.Sort(paths)
+
+	var (
+		contents []pluginConfigMapContent
+		current  = pluginConfigMapContent{data: map[string]string{}, files: map[string]string{}}
+		size     int
+		keys     = make(map[string]string, len(paths))
+	)
+	for _, p := range paths {
+		key := strings.ReplaceAll(p, "/", ".")
+		if other, ok := keys[key]; ok {
+			return nil, fmt.Errorf("files %q and %q can't be both stored under the ConfigMap key %q", other, p, key)
+		}
+		keys[key] = p
+
+		fileSize := len(key) + len(plugin[p])
+		if fileSize > maxConfigMapDataSize {
+			return nil, fmt.Errorf("file %q of %d bytes exceeds the limit of %d bytes of a ConfigMap", p, fileSize, maxConfigMapDataSize)
+		}
+		if size+fileSize > maxConfigMapDataSize {
+			contents = append(contents, current)
+			current = pluginConfigMapContent{data: map[string]string{}, files: map[string]string{}}
+			size = 0
+		}
+		current.data[key] = plugin[p]
+		current.files[key] = p
+		size += fileSize
+	}
+	contents = append(contents, current)
+
+	for i, content := range contents {
+		if lo.EveryBy(lo.Entries(content.files), func(e lo.Entry[string, string]) bool { return e.Key == e.Value }) {
+			contents[i].files = nil
+		}
+	}
+	return contents, nil
+}
+
+// splitPluginConfigMaps splits the ConfigMaps owned by a KongPluginInstallation
+// into its underlying ConfigMaps, of which there should be only one, and the
+// additional ConfigMaps its plugin is split across.
+func splitPluginConfigMaps(cms []corev1.ConfigMap) (underlying []corev1.ConfigMap, parts []corev1.ConfigMap) {
+	return lo.FilterReject(cms, func(cm corev1.ConfigMap, _ int) bool {
+		_, isPart := cm.Annotations[consts.AnnotationKongPluginInstallationPart]
+		return !isPart
+	})
+}
+
+// pluginConfigMapsComplete returns true when all the additional ConfigMaps
+// listed in the underlying ConfigMap exist.
+func pluginConfigMapsComplete(cm *corev1.ConfigMap, parts []corev1.ConfigMap) bool {
+	names, ok := cm.Annotations[consts.AnnotationKongPluginInstallationParts]
+	if !ok {
+		return true
+	}
+	return lo.EveryBy(strings.Split(names, ","), func(name string) bool {
+		return lo.ContainsBy(parts, func(part corev1.ConfigMap) bool { return part.Name == name })
+	})
+}
+
+// setPluginConfigMapContent sets the content of a ConfigMap a plugin is stored in.
+func setPluginConfigMapContent(cm *corev1.ConfigMap, content pluginConfigMapContent) error {
+	cm.Data = content.data
+	return k8sresources.AnnotateConfigMapWithKongPluginInstallationFiles(cm, content.files)
+}
+
+// ensurePluginConfigMaps stores the contents of the plugin in the ConfigMaps of
+// the KongPluginInstallation: the first one in its underlying ConfigMap and the
+// others in additional ConfigMaps, which are listed in the underlying
+// ConfigMap's annotations. The additional ConfigMaps are updated first so that
+// the DataPlanes, which react to changes of the underlying ConfigMap, get the
+// new plugin at once. The additional ConfigMaps no longer needed are deleted
+// last. It returns the underlying ConfigMap.
+func (r *Reconciler) ensurePluginConfigMaps(
+	ctx context.Context,
+	kpi *operatorv1alpha1.KongPluginInstallation,
+	cm *corev1.ConfigMap,
+	parts []corev1.ConfigMap,
+	contents []pluginConfigMapContent,
+	installed installedPlugin,
+) (*corev1.ConfigMap, error) {
+	partNames := make([]string, 0, len(contents)-1)
+	for i, content := range contents[1:] {
+		index := strconv.Itoa(i + 1)
+		part, found := lo.Find(parts, func(part corev1.ConfigMap) bool {
+			return part.Annotations[consts.AnnotationKongPluginInstallationPart] == index
+		})
+		if err := setPluginConfigMapContent(&part, content); err != nil {
+			return nil, err
+		}
+		if found {
+			if err := r.Client.Update(ctx, &part); err != nil {
+				return nil, err
+			}
+		} else {
+			part.GenerateName = kpi.Name + "-"
+			part.Namespace = kpi.Namespace
+			k8sresources.LabelObjectAsKongPluginInstallationManaged(&part)
+			k8sresources.AnnotateConfigMapWithKongPluginInstallation(&part, *kpi)
+			part.Annotations[consts.AnnotationKongPluginInstallationPart] = index
+			if err := ctrl.SetControllerReference(kpi, &part, r.Scheme); err != nil {
+				return nil, err
+			}
+			if err := r.Client.Create(ctx, &part); err != nil {
+				return nil, err
+			}
+		}
+		partNames = append(partNames, part.Name)
+	}
+
+	create := cm == nil
+	if create {
+		cm = &corev1.ConfigMap{}
+		if cmName := kpi.Status.UnderlyingConfigMapName; cmName != "" {
+			cm.Name = cmName
+		} else {
+			cm.GenerateName = kpi.Name + "-"
+		}
+		k8sresources.LabelObjectAsKongPluginInstallationManaged(cm)
+		k8sresources.AnnotateConfigMapWithKongPluginInstallation(cm, *kpi)
+		cm.Namespace = kpi.Namespace
+		if err := ctrl.SetControllerReference(kpi, cm, r.Scheme); err != nil {
+			return nil, err
+		}
+	}
+	annotateConfigMapWithInstalledPlugin(cm, installed)
+	if len(partNames) > 0 {
+		cm.Annotations[consts.AnnotationKongPluginInstallationParts] = strings.Join(partNames, ",")
+	} else {
+		delete(cm.Annotations, consts.AnnotationKongPluginInstallationParts)
+	}
+	if err := setPluginConfigMapContent(cm, contents[0]); err != nil {
+		return nil, err
+	}
+	if create {
+		if err := r.Client.Create(ctx, cm); err != nil {
+			return nil, err
+		}
+	} else if err := r.Client.Update(ctx, cm); err != nil {
+		return nil, err
+	}
+
+	for _, part := range parts {
+		if index, err := strconv.Atoi(part.Annotations[consts.AnnotationKongPluginInstallationPart]); err == nil && index < len(contents) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, &part); client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+	}
+	return cm, nil
+}
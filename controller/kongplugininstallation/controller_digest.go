@@ -0,0 +1,286 @@
+package kongplugininstallation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	orascreds "oras.land/oras-go/v2/registry/remote/credentials"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/kong/gateway-operator/controller/kongplugininstallation/image"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// KongPluginInstallation - Status Conditions
+// -----------------------------------------------------------------------------
+
+const (
+	// ConditionTypeImagePinned indicates that the plugin stored in the
+	// underlying ConfigMap was fetched from the image's manifest with a given
+	// digest. Its message holds the image URL pinned to that digest.
+	ConditionTypeImagePinned string = "ImagePinned"
+
+	// ConditionTypeUpdateAvailable indicates whether the tag of the image
+	// points to a different digest than the installed one, which hasn't been
+	// installed because of the "manual" update policy.
+	ConditionTypeUpdateAvailable string = "UpdateAvailable"
+)
+
+const (
+	// ConditionReasonPinned is used with the ImagePinned condition when the
+	// installed plugin was fetched from a known digest.
+	ConditionReasonPinned string = "Pinned"
+
+	// ConditionReasonNewDigest is used with the UpdateAvailable condition when
+	// the tag of the image points to a new digest waiting for an approval.
+	ConditionReasonNewDigest string = "NewDigest"
+
+	// ConditionReasonUpToDate is used with the UpdateAvailable condition when
+	// the installed digest is the one the tag of the image points to.
+	ConditionReasonUpToDate string = "UpToDate"
+)
+
+// -----------------------------------------------------------------------------
+// KongPluginInstallation - Update Policy
+// -----------------------------------------------------------------------------
+
+const (
+	// UpdatePolicyAuto installs the new digest as soon as the tag of the image
+	// points to it. It's the default update policy.
+	UpdatePolicyAuto = "auto"
+	// UpdatePolicyManual keeps the installed digest until the new one is
+	// approved.
+	UpdatePolicyManual = "manual"
+)
+
+// updateConfig is the configuration, set through the annotations of a
+// KongPluginInstallation, of how updates of its image are detected and
+// installed.
+type updateConfig struct {
+	// interval is the interval at which the tag of the image is checked, zero
+	// when it's only checked on changes.
+	interval       time.Duration
+	policy         string
+	approvedDigest string
+}
+
+// updateConfigForKongPluginInstallation parses the update configuration set
+// through the provided annotations of a KongPluginInstallation.
+func updateConfigForKongPluginInstallation(annotations map[string]string) (updateConfig, error) {
+	cfg := updateConfig{
+		policy:         UpdatePolicyAuto,
+		approvedDigest: annotations[consts.KongPluginInstallationApprovedDigestAnnotation],
+	}
+	if interval, ok := annotations[consts.KongPluginInstallationUpdateIntervalAnnotation]; ok {
+		d, err := time.ParseDuration(interval)
+		if err != nil {
+			return updateConfig{}, fmt.Errorf("invalid %s annotation: %w", consts.KongPluginInstallationUpdateIntervalAnnotation, err)
+		}
+		if d <= 0 {
+			return updateConfig{}, fmt.Errorf("invalid %s annotation: interval %s must be positive", consts.KongPluginInstallationUpdateIntervalAnnotation, d)
+		}
+		cfg.interval = d
+	}
+	if policy, ok := annotations[consts.KongPluginInstallationUpdatePolicyAnnotation]; ok {
+		switch policy {
+		case UpdatePolicyAuto, UpdatePolicyManual:
+			cfg.policy = policy
+		default:
+			return updateConfig{}, fmt.Errorf(
+				"invalid %s annotation: policy %q is not supported, use %q or %q",
+				consts.KongPluginInstallationUpdatePolicyAnnotation, policy, UpdatePolicyAuto, UpdatePolicyManual,
+			)
+		}
+	}
+	return cfg, nil
+}
+
+// updateAnnotationsChangedPredicate triggers a reconciliation when the update
+// configuration set through the annotations of a KongPluginInstallation
+// changes, e.g. when a new digest is approved.
+func updateAnnotationsChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			for _, annotation := range []string{
+				consts.KongPluginInstallationUpdateIntervalAnnotation,
+				consts.KongPluginInstallationUpdatePolicyAnnotation,
+				consts.KongPluginInstallationApprovedDigestAnnotation,
+			} {
+				if e.ObjectOld.GetAnnotations()[annotation] != e.ObjectNew.GetAnnotations()[annotation] {
+					return true
+				}
+			}
+			return false
+		},
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// -----------------------------------------------------------------------------
+// KongPluginInstallation - Digest Pinning
+// -----------------------------------------------------------------------------
+
+// installedPlugin describes the plugin stored in the underlying ConfigMap of a
+// KongPluginInstallation.
+type installedPlugin struct {
+	// image is the image URL, as set in the KongPluginInstallation, the plugin
+	// was fetched from.
+	image string
+	// digest is the digest of the image's manifest the plugin was fetched from.
+	digest string
+}
+
+// installedPluginFor returns the plugin stored in the provided ConfigMap, the
+// zero value when the ConfigMap holds no plugin or one fetched before digests
+// were recorded.
+func installedPluginFor(cm *corev1.ConfigMap) installedPlugin {
+	if cm == nil || len(cm.Data) == 0 {
+		return installedPlugin{}
+	}
+	return installedPlugin{
+		image:  cm.Annotations[consts.AnnotationKongPluginInstallationImage],
+		digest: cm.Annotations[consts.AnnotationKongPluginInstallationDigest],
+	}
+}
+
+// digestToInstall returns the digest of the image's manifest which should be
+// installed given the installed plugin, the digest the image currently
+// resolves to and the update configuration. updateAvailable is true when the
+// resolved digest is kept from being installed by the "manual" update policy.
+func digestToInstall(
+	imageURL string, installed installedPlugin, resolved string, cfg updateConfig,
+) (digest string, updateAvailable bool) {
+	switch {
+	case installed.digest == "", installed.image != imageURL, installed.digest == resolved:
+		return resolved, false
+	case cfg.policy == UpdatePolicyAuto, cfg.approvedDigest == resolved:
+		return resolved, false
+	default:
+		return installed.digest, true
+	}
+}
+
+// digestCheck is the outcome of the last resolution of the digest the image of
+// a KongPluginInstallation points to.
+type digestCheck struct {
+	uid        types.UID
+	generation int64
+	cfg        updateConfig
+	resolved   string
+	checkedAt  time.Time
+}
+
+// current returns true when the check still applies to the provided
+// KongPluginInstallation: neither its spec nor its update configuration changed
+// since and its update interval, if any, didn't elapse.
+func (c digestCheck) current(kpi *operatorv1alpha1.KongPluginInstallation, cfg updateConfig, now time.Time) bool {
+	if c.uid != kpi.UID || c.generation != kpi.Generation || c.cfg != cfg {
+		return false
+	}
+	return cfg.interval == 0 || now.Sub(c.checkedAt) < cfg.interval
+}
+
+// nextCheckAfter returns the time after which the image has to be checked
+// again, zero when it's only checked on changes.
+func (c digestCheck) nextCheckAfter(now time.Time) time.Duration {
+	if c.cfg.interval == 0 {
+		return 0
+	}
+	return max(c.cfg.interval-now.Sub(c.checkedAt), time.Second)
+}
+
+// digestChecks records the last digest check of every KongPluginInstallation
+// so that the registry is only queried when a KongPluginInstallation changes or
+// its update interval elapses, not on every reconciliation.
+type digestChecks struct {
+	lock   sync.Mutex
+	checks map[types.NamespacedName]digestCheck
+}
+
+func (d *digestChecks) get(nn types.NamespacedName) (digestCheck, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	c, ok := d.checks[nn]
+	return c, ok
+}
+
+func (d *digestChecks) set(nn types.NamespacedName, c digestCheck) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.checks == nil {
+		d.checks = make(map[types.NamespacedName]digestCheck)
+	}
+	d.checks[nn] = c
+}
+
+func (d *digestChecks) delete(nn types.NamespacedName) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	delete(d.checks, nn)
+}
+
+// resolveDigest returns the digest the image of the provided KongPluginInstallation
+// points to at the provided time, reusing the last check while it's current.
+func (r *Reconciler) resolveDigest(
+	ctx context.Context,
+	kpi *operatorv1alpha1.KongPluginInstallation,
+	cfg updateConfig,
+	credentialsStore orascreds.Store,
+	now time.Time,
+) (digestCheck, error) {
+	nn := client.ObjectKeyFromObject(kpi)
+	if c, ok := r.digestChecks.get(nn); ok && c.current(kpi, cfg, now) {
+		return c, nil
+	}
+	resolved, err := r.getDigestResolver()(ctx, kpi.Spec.Image, credentialsStore)
+	if err != nil {
+		r.digestChecks.delete(nn)
+		return digestCheck{}, err
+	}
+	c := digestCheck{
+		uid:        kpi.UID,
+		generation: kpi.Generation,
+		cfg:        cfg,
+		resolved:   resolved,
+		checkedAt:  now,
+	}
+	r.digestChecks.set(nn, c)
+	return c, nil
+}
+
+func (r *Reconciler) getDigestResolver() func(context.Context, string, orascreds.Store) (string, error) {
+	if r.digestResolver != nil {
+		return r.digestResolver
+	}
+	return image.ResolveDigest
+}
+
+func (r *Reconciler) getPluginFetcher() func(context.Context, string, orascreds.Store) (image.PluginFiles, error) {
+	if r.pluginFetcher != nil {
+		return r.pluginFetcher
+	}
+	return image.FetchPlugin
+}
+
+// annotateConfigMapWithInstalledPlugin records in the provided ConfigMap's
+// annotations the image and the digest the plugin it stores was fetched from.
+func annotateConfigMapWithInstalledPlugin(cm client.Object, installed installedPlugin) {
+	annotations := cm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[consts.AnnotationKongPluginInstallationImage] = installed.image
+	annotations[consts.AnnotationKongPluginInstallationDigest] = installed.digest
+	cm.SetAnnotations(annotations)
+}
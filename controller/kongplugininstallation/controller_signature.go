@@ -0,0 +1,69 @@
+package kongplugininstallation
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	orascreds "oras.land/oras-go/v2/registry/remote/credentials"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/kongplugininstallation/image"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// KongPluginInstallationReasonSignatureVerificationFailed is used with the
+// SignatureVerified condition when the image of a KongPluginInstallation
+// requiring signed images is not signed or its signatures can't be verified
+// with the trusted keys. It's used with the Accepted condition as well when
+// no verified digest of the image is installed.
+const KongPluginInstallationReasonSignatureVerificationFailed operatorv1alpha1.KongPluginInstallationConditionReason = "SignatureVerificationFailed"
+
+const (
+	// ConditionTypeSignatureVerified indicates whether the signature of the
+	// digest the image of a KongPluginInstallation points to was verified
+	// with the trusted keys. A rejected digest isn't installed, the verified
+	// digest of the image installed before keeps being served. It's set only
+	// when the signature verification is configured.
+	ConditionTypeSignatureVerified string = "SignatureVerified"
+
+	// ConditionReasonSignatureVerified is used with the SignatureVerified
+	// condition when the signature of the digest was verified.
+	ConditionReasonSignatureVerified string = "Verified"
+)
+
+// trustedKeysForKongPluginInstallation returns the keys trusted to sign the
+// image of the KongPluginInstallation, nil when its signature doesn't need to
+// be verified. A non-empty message is returned when the keys can't be loaded
+// because of the user's configuration.
+func trustedKeysForKongPluginInstallation(
+	ctx context.Context, cl client.Client, kpi *operatorv1alpha1.KongPluginInstallation,
+) (keys *image.TrustedKeys, msg string, err error) {
+	secretName, ok := kpi.Annotations[consts.KongPluginInstallationSignatureVerificationSecretAnnotation]
+	if !ok {
+		return nil, "", nil
+	}
+	secretNN := client.ObjectKey{Namespace: kpi.Namespace, Name: secretName}
+	var secret corev1.Secret
+	if err := cl.Get(ctx, secretNN, &secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Sprintf("referenced Secret %q with trusted keys not found", secretNN), nil
+		}
+		return nil, "", fmt.Errorf("something unexpected during fetching secret %s: %w", secretNN, err)
+	}
+	trustedKeys, err := image.ParseTrustedKeys(secret.Data)
+	if err != nil {
+		return nil, fmt.Sprintf("can't parse trusted keys in secret %q: %s", secretNN, err), nil
+	}
+	return &trustedKeys, "", nil
+}
+
+func (r *Reconciler) getSignatureVerifier() func(context.Context, string, string, image.TrustedKeys, orascreds.Store) error {
+	if r.signatureVerifier != nil {
+		return r.signatureVerifier
+	}
+	return image.VerifySignature
+}
@@ -0,0 +1,707 @@
+package kongplugininstallation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	orascreds "oras.land/oras-go/v2/registry/remote/credentials"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/controller/kongplugininstallation/image"
+	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+const (
+	testImage   = "registry.example.com/plugins/rate-limiter:1.0"
+	digestFirst = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	digestMoved = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func TestUpdateConfigForKongPluginInstallation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    updateConfig
+		expectedErr string
+	}{
+		{
+			name:     "no annotations",
+			expected: updateConfig{policy: UpdatePolicyAuto},
+		},
+		{
+			name: "interval, manual policy and approved digest",
+			annotations: map[string]string{
+				consts.KongPluginInstallationUpdateIntervalAnnotation: "10m",
+				consts.KongPluginInstallationUpdatePolicyAnnotation:   "manual",
+				consts.KongPluginInstallationApprovedDigestAnnotation: digestMoved,
+			},
+			expected: updateConfig{interval: 10 * time.Minute, policy: UpdatePolicyManual, approvedDigest: digestMoved},
+		},
+		{
+			name:        "invalid interval",
+			annotations: map[string]string{consts.KongPluginInstallationUpdateIntervalAnnotation: "often"},
+			expectedErr: "invalid gateway-operator.konghq.com/update-interval annotation",
+		},
+		{
+			name:        "negative interval",
+			annotations: map[string]string{consts.KongPluginInstallationUpdateIntervalAnnotation: "-1m"},
+			expectedErr: "interval -1m0s must be positive",
+		},
+		{
+			name:        "unsupported policy",
+			annotations: map[string]string{consts.KongPluginInstallationUpdatePolicyAnnotation: "never"},
+			expectedErr: `policy "never" is not supported`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := updateConfigForKongPluginInstallation(tc.annotations)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cfg)
+		})
+	}
+}
+
+func TestDigestToInstall(t *testing.T) {
+	installed := installedPlugin{image: testImage, digest: digestFirst}
+
+	testCases := []struct {
+		name                    string
+		imageURL                string
+		installed               installedPlugin
+		cfg                     updateConfig
+		expectedDigest          string
+		expectedUpdateAvailable bool
+	}{
+		{
+			name:           "nothing installed",
+			imageURL:       testImage,
+			cfg:            updateConfig{policy: UpdatePolicyManual},
+			expectedDigest: digestMoved,
+		},
+		{
+			name:           "image changed",
+			imageURL:       "registry.example.com/plugins/rate-limiter:2.0",
+			installed:      installed,
+			cfg:            updateConfig{policy: UpdatePolicyManual},
+			expectedDigest: digestMoved,
+		},
+		{
+			name:           "tag moved with auto policy",
+			imageURL:       testImage,
+			installed:      installed,
+			cfg:            updateConfig{policy: UpdatePolicyAuto},
+			expectedDigest: digestMoved,
+		},
+		{
+			name:                    "tag moved with manual policy",
+			imageURL:                testImage,
+			installed:               installed,
+			cfg:                     updateConfig{policy: UpdatePolicyManual, approvedDigest: digestFirst},
+			expectedDigest:          digestFirst,
+			expectedUpdateAvailable: true,
+		},
+		{
+			name:           "tag moved with manual policy and approved digest",
+			imageURL:       testImage,
+			installed:      installed,
+			cfg:            updateConfig{policy: UpdatePolicyManual, approvedDigest: digestMoved},
+			expectedDigest: digestMoved,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			digest, updateAvailable := digestToInstall(tc.imageURL, tc.installed, digestMoved, tc.cfg)
+			require.Equal(t, tc.expectedDigest, digest)
+			require.Equal(t, tc.expectedUpdateAvailable, updateAvailable)
+		})
+	}
+}
+
+func TestDigestCheckCurrent(t *testing.T) {
+	now := time.Now()
+	kpi := &operatorv1alpha1.KongPluginInstallation{
+		ObjectMeta: metav1.ObjectMeta{UID: "uid", Generation: 2},
+	}
+	cfg := updateConfig{policy: UpdatePolicyAuto, interval: time.Minute}
+	check := digestCheck{uid: "uid", generation: 2, cfg: cfg, checkedAt: now.Add(-30 * time.Second)}
+
+	require.True(t, check.current(kpi, cfg, now))
+	require.Equal(t, 30*time.Second, check.nextCheckAfter(now))
+
+	t.Log("the check expires with the update interval")
+	require.False(t, check.current(kpi, cfg, now.Add(time.Minute)))
+
+	t.Log("the check expires when the spec or the update configuration changes")
+	changed := kpi.DeepCopy()
+	changed.Generation = 3
+	require.False(t, check.current(changed, cfg, now))
+	require.False(t, check.current(kpi, updateConfig{policy: UpdatePolicyManual, interval: time.Minute}, now))
+
+	t.Log("without an update interval the check only expires on changes")
+	cfg.interval = 0
+	check.cfg = cfg
+	require.True(t, check.current(kpi, cfg, now.Add(time.Hour)))
+	require.Zero(t, check.nextCheckAfter(now))
+}
+
+func TestReconcileDigestPinning(t *testing.T) {
+	kpi := &operatorv1alpha1.KongPluginInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "rate-limiter",
+			UID:        "kpi-uid",
+			Generation: 1,
+			Annotations: map[string]string{
+				consts.KongPluginInstallationUpdateIntervalAnnotation: "5m",
+				consts.KongPluginInstallationUpdatePolicyAnnotation:   UpdatePolicyManual,
+			},
+		},
+		Spec: operatorv1alpha1.KongPluginInstallationSpec{
+			Image: testImage,
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(kpi).
+		WithStatusSubresource(kpi).
+		Build()
+
+	var (
+		resolved string
+		fetched  []string
+	)
+	eventRecorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Client:        cl,
+		Scheme:        scheme.Get(),
+		EventRecorder: eventRecorder,
+		digestResolver: func(context.Context, string, orascreds.Store) (string, error) {
+			return resolved, nil
+		},
+		pluginFetcher: func(_ context.Context, imageURL string, _ orascreds.Store) (image.PluginFiles, error) {
+			fetched = append(fetched, imageURL)
+			return image.PluginFiles{"handler.lua": imageURL, "schema.lua": "schema"}, nil
+		},
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kpi)}
+
+	reconcileAndGet := func(t *testing.T) (*operatorv1alpha1.KongPluginInstallation, *corev1.ConfigMap) {
+		t.Helper()
+		res, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+		require.Positive(t, res.RequeueAfter)
+		require.LessOrEqual(t, res.RequeueAfter, 5*time.Minute, "the tag is checked again after the update interval")
+
+		var kpi operatorv1alpha1.KongPluginInstallation
+		require.NoError(t, cl.Get(ctx, req.NamespacedName, &kpi))
+		var cm corev1.ConfigMap
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "ns", Name: kpi.Status.UnderlyingConfigMapName}, &cm))
+		return &kpi, &cm
+	}
+
+	t.Run("the resolved digest is installed", func(t *testing.T) {
+		resolved = digestFirst
+		kpi, cm := reconcileAndGet(t)
+		require.Equal(t, []string{"registry.example.com/plugins/rate-limiter@" + digestFirst}, fetched)
+		require.Equal(t, digestFirst, cm.Annotations[consts.AnnotationKongPluginInstallationDigest])
+		require.Equal(t, testImage, cm.Annotations[consts.AnnotationKongPluginInstallationImage])
+
+		pinned := meta.FindStatusCondition(kpi.Status.Conditions, ConditionTypeImagePinned)
+		require.NotNil(t, pinned)
+		require.Equal(t, "plugin fetched from image registry.example.com/plugins/rate-limiter@"+digestFirst, pinned.Message)
+		require.True(t, meta.IsStatusConditionTrue(kpi.Status.Conditions, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted)))
+		require.True(t, meta.IsStatusConditionFalse(kpi.Status.Conditions, ConditionTypeUpdateAvailable))
+	})
+
+	t.Run("the plugin isn't fetched again when the digest is unchanged", func(t *testing.T) {
+		reconcileAndGet(t)
+		require.Len(t, fetched, 1)
+	})
+
+	t.Run("the tag isn't checked again before the update interval elapsed", func(t *testing.T) {
+		resolved = digestMoved
+		kpi, cm := reconcileAndGet(t)
+		require.Equal(t, digestFirst, cm.Annotations[consts.AnnotationKongPluginInstallationDigest])
+		require.True(t, meta.IsStatusConditionFalse(kpi.Status.Conditions, ConditionTypeUpdateAvailable))
+	})
+
+	t.Run("a moved tag is reported as an update with the manual policy", func(t *testing.T) {
+		resolved = digestMoved
+		// the update interval elapsed.
+		r.digestChecks.delete(req.NamespacedName)
+		kpi, cm := reconcileAndGet(t)
+		require.Len(t, fetched, 1)
+		require.Equal(t, digestFirst, cm.Annotations[consts.AnnotationKongPluginInstallationDigest])
+		require.True(t, meta.IsStatusConditionTrue(kpi.Status.Conditions, ConditionTypeUpdateAvailable))
+		require.True(t, meta.IsStatusConditionTrue(kpi.Status.Conditions, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted)))
+		require.Contains(t, <-eventRecorder.Events, "PluginUpdateAvailable")
+	})
+
+	t.Run("an approved digest is installed", func(t *testing.T) {
+		var current operatorv1alpha1.KongPluginInstallation
+		require.NoError(t, cl.Get(ctx, req.NamespacedName, &current))
+		current.Annotations[consts.KongPluginInstallationApprovedDigestAnnotation] = digestMoved
+		require.NoError(t, cl.Update(ctx, &current))
+
+		kpi, cm := reconcileAndGet(t)
+		require.Equal(t, "registry.example.com/plugins/rate-limiter@"+digestMoved, fetched[len(fetched)-1])
+		require.Equal(t, digestMoved, cm.Annotations[consts.AnnotationKongPluginInstallationDigest])
+		require.Equal(t, "registry.example.com/plugins/rate-limiter@"+digestMoved, cm.Data["handler.lua"])
+		require.True(t, meta.IsStatusConditionFalse(kpi.Status.Conditions, ConditionTypeUpdateAvailable))
+		require.Contains(t, <-eventRecorder.Events, "PluginUpdated")
+	})
+}
+
+func TestReconcileSignatureVerification(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	kpi := &operatorv1alpha1.KongPluginInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "rate-limiter",
+			UID:        "kpi-uid",
+			Generation: 1,
+			Annotations: map[string]string{
+				consts.KongPluginInstallationSignatureVerificationSecretAnnotation: "trusted-keys",
+			},
+		},
+		Spec: operatorv1alpha1.KongPluginInstallationSpec{
+			Image: testImage,
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trusted-keys"},
+		Data: map[string][]byte{
+			"cosign.pub": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		objects          []client.Object
+		verifyErr        error
+		expectedStatus   metav1.ConditionStatus
+		expectedReason   operatorv1alpha1.KongPluginInstallationConditionReason
+		expectedMessage  string
+		expectedFetches  int
+		expectedVerified bool
+	}{
+		{
+			name:            "secret with trusted keys not found",
+			objects:         []client.Object{kpi.DeepCopy()},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  operatorv1alpha1.KongPluginInstallationReasonFailed,
+			expectedMessage: `referenced Secret "ns/trusted-keys" with trusted keys not found`,
+		},
+		{
+			name:             "unsigned image",
+			objects:          []client.Object{kpi.DeepCopy(), secret.DeepCopy()},
+			verifyErr:        image.ErrNoSignature,
+			expectedStatus:   metav1.ConditionFalse,
+			expectedReason:   KongPluginInstallationReasonSignatureVerificationFailed,
+			expectedMessage:  "signature verification of image registry.example.com/plugins/rate-limiter@" + digestFirst + " failed: image has no cosign or notation signature",
+			expectedVerified: true,
+		},
+		{
+			name:             "signed image",
+			objects:          []client.Object{kpi.DeepCopy(), secret.DeepCopy()},
+			expectedStatus:   metav1.ConditionTrue,
+			expectedReason:   operatorv1alpha1.KongPluginInstallationReasonReady,
+			expectedMessage:  "plugin successfully saved in cluster as ConfigMap",
+			expectedFetches:  1,
+			expectedVerified: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tc.objects...).
+				WithStatusSubresource(&operatorv1alpha1.KongPluginInstallation{}).
+				Build()
+			var (
+				fetches  int
+				verified bool
+			)
+			r := &Reconciler{
+				Client:        cl,
+				Scheme:        scheme.Get(),
+				EventRecorder: record.NewFakeRecorder(10),
+				digestResolver: func(context.Context, string, orascreds.Store) (string, error) {
+					return digestFirst, nil
+				},
+				pluginFetcher: func(context.Context, string, orascreds.Store) (image.PluginFiles, error) {
+					fetches++
+					return image.PluginFiles{"handler.lua": "handler", "schema.lua": "schema"}, nil
+				},
+				signatureVerifier: func(_ context.Context, _ string, digest string, keys image.TrustedKeys, _ orascreds.Store) error {
+					require.Equal(t, digestFirst, digest)
+					require.Len(t, keys.CosignPublicKeys, 1)
+					verified = true
+					return tc.verifyErr
+				},
+			}
+
+			_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kpi)})
+			require.NoError(t, err)
+
+			var got operatorv1alpha1.KongPluginInstallation
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(kpi), &got))
+			accepted := meta.FindStatusCondition(got.Status.Conditions, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted))
+			require.NotNil(t, accepted)
+			require.Equal(t, tc.expectedStatus, accepted.Status)
+			require.EqualValues(t, tc.expectedReason, accepted.Reason)
+			require.Equal(t, tc.expectedMessage, accepted.Message)
+			require.Equal(t, tc.expectedFetches, fetches)
+			require.Equal(t, tc.expectedVerified, verified)
+			if tc.expectedVerified {
+				signatureVerified := meta.FindStatusCondition(got.Status.Conditions, ConditionTypeSignatureVerified)
+				require.NotNil(t, signatureVerified)
+				require.Equal(t, tc.expectedStatus, signatureVerified.Status)
+			}
+		})
+	}
+}
+
+func TestReconcileSignatureVerificationOfNewDigest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	kpi := &operatorv1alpha1.KongPluginInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "rate-limiter",
+			UID:        "kpi-uid",
+			Generation: 1,
+			Annotations: map[string]string{
+				consts.KongPluginInstallationSignatureVerificationSecretAnnotation: "trusted-keys",
+			},
+		},
+		Spec: operatorv1alpha1.KongPluginInstallationSpec{
+			Image: testImage,
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "trusted-keys"},
+		Data: map[string][]byte{
+			"cosign.pub": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(kpi, secret).
+		WithStatusSubresource(kpi).
+		Build()
+
+	var (
+		resolved = digestFirst
+		signed   = map[string]bool{digestFirst: true}
+		fetched  []string
+	)
+	eventRecorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Client:        cl,
+		Scheme:        scheme.Get(),
+		EventRecorder: eventRecorder,
+		digestResolver: func(context.Context, string, orascreds.Store) (string, error) {
+			return resolved, nil
+		},
+		pluginFetcher: func(_ context.Context, imageURL string, _ orascreds.Store) (image.PluginFiles, error) {
+			fetched = append(fetched, imageURL)
+			return image.PluginFiles{"handler.lua": imageURL, "schema.lua": "schema"}, nil
+		},
+		signatureVerifier: func(_ context.Context, _ string, digest string, _ image.TrustedKeys, _ orascreds.Store) error {
+			if !signed[digest] {
+				return image.ErrNoSignature
+			}
+			return nil
+		},
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kpi)}
+
+	reconcileAndGet := func(t *testing.T) (*operatorv1alpha1.KongPluginInstallation, *corev1.ConfigMap) {
+		t.Helper()
+		// The update interval elapsed.
+		r.digestChecks.delete(req.NamespacedName)
+		_, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+
+		var kpi operatorv1alpha1.KongPluginInstallation
+		require.NoError(t, cl.Get(ctx, req.NamespacedName, &kpi))
+		var cm corev1.ConfigMap
+		require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "ns", Name: kpi.Status.UnderlyingConfigMapName}, &cm))
+		return &kpi, &cm
+	}
+
+	t.Log("the verified digest is installed")
+	kpi, cm := reconcileAndGet(t)
+	require.Equal(t, digestFirst, cm.Annotations[consts.AnnotationKongPluginInstallationDigest])
+	require.True(t, meta.IsStatusConditionTrue(kpi.Status.Conditions, ConditionTypeSignatureVerified))
+
+	t.Log("a new digest failing the verification is rejected and the installed digest is kept")
+	resolved = digestMoved
+	kpi, cm = reconcileAndGet(t)
+	require.Len(t, fetched, 1)
+	require.Equal(t, digestFirst, cm.Annotations[consts.AnnotationKongPluginInstallationDigest])
+	require.True(t, meta.IsStatusConditionTrue(kpi.Status.Conditions, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted)))
+	signatureVerified := meta.FindStatusCondition(kpi.Status.Conditions, ConditionTypeSignatureVerified)
+	require.NotNil(t, signatureVerified)
+	require.Equal(t, metav1.ConditionFalse, signatureVerified.Status)
+	require.EqualValues(t, KongPluginInstallationReasonSignatureVerificationFailed, signatureVerified.Reason)
+	require.Equal(t,
+		"signature verification of image registry.example.com/plugins/rate-limiter@"+digestMoved+" failed: image has no cosign or notation signature",
+		signatureVerified.Message,
+	)
+	pinned := meta.FindStatusCondition(kpi.Status.Conditions, ConditionTypeImagePinned)
+	require.NotNil(t, pinned)
+	require.Equal(t, "plugin fetched from image registry.example.com/plugins/rate-limiter@"+digestFirst, pinned.Message)
+	require.True(t, meta.IsStatusConditionTrue(kpi.Status.Conditions, ConditionTypeUpdateAvailable))
+	require.Contains(t, <-eventRecorder.Events, "PluginDigestRejected")
+
+	t.Log("the new digest is installed once its signature is verified")
+	signed[digestMoved] = true
+	kpi, cm = reconcileAndGet(t)
+	require.Equal(t, digestMoved, cm.Annotations[consts.AnnotationKongPluginInstallationDigest])
+	require.True(t, meta.IsStatusConditionTrue(kpi.Status.Conditions, ConditionTypeSignatureVerified))
+	require.True(t, meta.IsStatusConditionFalse(kpi.Status.Conditions, ConditionTypeUpdateAvailable))
+
+	t.Log("the KongPluginInstallation isn't accepted when the installed digest fails the verification as well")
+	signed = map[string]bool{}
+	kpi, _ = reconcileAndGet(t)
+	accepted := meta.FindStatusCondition(kpi.Status.Conditions, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted))
+	require.NotNil(t, accepted)
+	require.Equal(t, metav1.ConditionFalse, accepted.Status)
+	require.EqualValues(t, KongPluginInstallationReasonSignatureVerificationFailed, accepted.Reason)
+}
+
+func TestListKongPluginInstallationsForSecret(t *testing.T) {
+	objects := []client.Object{
+		&operatorv1alpha1.KongPluginInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "pull-secret",
+			},
+			Spec: operatorv1alpha1.KongPluginInstallationSpec{
+				Image:              testImage,
+				ImagePullSecretRef: &gatewayv1.SecretObjectReference{Name: "credentials"},
+			},
+		},
+		&operatorv1alpha1.KongPluginInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "other",
+				Name:      "pull-secret-in-other-namespace",
+			},
+			Spec: operatorv1alpha1.KongPluginInstallationSpec{
+				Image: testImage,
+				ImagePullSecretRef: &gatewayv1.SecretObjectReference{
+					Namespace: lo.ToPtr(gatewayv1.Namespace("ns")),
+					Name:      "credentials",
+				},
+			},
+		},
+		&operatorv1alpha1.KongPluginInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      "trusted-keys",
+				Annotations: map[string]string{
+					consts.KongPluginInstallationSignatureVerificationSecretAnnotation: "trusted-keys",
+				},
+			},
+			Spec: operatorv1alpha1.KongPluginInstallationSpec{
+				Image: testImage,
+			},
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(objects...).
+		WithIndex(&operatorv1alpha1.KongPluginInstallation{}, index.SecretsIndex, index.SecretsReferencedByKongPluginInstallation).
+		Build()
+	r := &Reconciler{Client: cl}
+
+	secret := func(namespace, name string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	names := func(reqs []reconcile.Request) []string {
+		return lo.Map(reqs, func(req reconcile.Request, _ int) string { return req.String() })
+	}
+	require.ElementsMatch(t,
+		[]string{"ns/pull-secret", "other/pull-secret-in-other-namespace"},
+		names(r.listKongPluginInstallationsForSecret(t.Context(), secret("ns", "credentials"))),
+	)
+	require.Equal(t,
+		[]string{"ns/trusted-keys"},
+		names(r.listKongPluginInstallationsForSecret(t.Context(), secret("ns", "trusted-keys"))),
+	)
+	require.Empty(t, r.listKongPluginInstallationsForSecret(t.Context(), secret("other", "trusted-keys")))
+	require.Empty(t, r.listKongPluginInstallationsForSecret(t.Context(), secret("ns", "unrelated")))
+}
+
+func TestSplitPluginIntoConfigMaps(t *testing.T) {
+	large := strings.Repeat("-", 600*1024)
+
+	t.Run("files at the root of the plugin fit in one ConfigMap", func(t *testing.T) {
+		contents, err := splitPluginIntoConfigMaps(image.PluginFiles{"handler.lua": "handler", "schema.lua": "schema"})
+		require.NoError(t, err)
+		require.Equal(t, []pluginConfigMapContent{
+			{data: map[string]string{"handler.lua": "handler", "schema.lua": "schema"}},
+		}, contents)
+	})
+
+	t.Run("files in subdirectories and across ConfigMaps", func(t *testing.T) {
+		contents, err := splitPluginIntoConfigMaps(image.PluginFiles{
+			"handler.lua":         "handler",
+			"schema.lua":          "schema",
+			"big.lua":             large,
+			"migrations/init.lua": large,
+		})
+		require.NoError(t, err)
+		require.Len(t, contents, 2)
+		require.ElementsMatch(t, []string{"big.lua", "handler.lua"}, lo.Keys(contents[0].data))
+		require.Nil(t, contents[0].files)
+		require.ElementsMatch(t, []string{"migrations.init.lua", "schema.lua"}, lo.Keys(contents[1].data))
+		require.Equal(t, map[string]string{
+			"migrations.init.lua": "migrations/init.lua",
+			"schema.lua":          "schema.lua",
+		}, contents[1].files)
+	})
+
+	t.Run("colliding keys", func(t *testing.T) {
+		_, err := splitPluginIntoConfigMaps(image.PluginFiles{"a/b.lua": "", "a.b.lua": ""})
+		require.EqualError(t, err, `files "a.b.lua" and "a/b.lua" can't be both stored under the ConfigMap key "a.b.lua"`)
+	})
+
+	t.Run("file larger than a ConfigMap", func(t *testing.T) {
+		_, err := splitPluginIntoConfigMaps(image.PluginFiles{"big.lua": large + large})
+		require.EqualError(t, err, fmt.Sprintf(`file "big.lua" of %d bytes exceeds the limit of %d bytes of a ConfigMap`, 2*len(large)+len("big.lua"), maxConfigMapDataSize))
+	})
+}
+
+func TestReconcilePluginSplitAcrossConfigMaps(t *testing.T) {
+	kpi := &operatorv1alpha1.KongPluginInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "ns",
+			Name:       "rate-limiter",
+			UID:        "kpi-uid",
+			Generation: 1,
+		},
+		Spec: operatorv1alpha1.KongPluginInstallationSpec{
+			Image: testImage,
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(kpi).
+		WithStatusSubresource(kpi).
+		Build()
+
+	large := strings.Repeat("-", 600*1024)
+	var (
+		resolved = digestFirst
+		plugin   = image.PluginFiles{
+			"handler.lua":         "handler",
+			"schema.lua":          "schema",
+			"big.lua":             large,
+			"migrations/init.lua": large,
+		}
+	)
+	r := &Reconciler{
+		Client:        cl,
+		Scheme:        scheme.Get(),
+		EventRecorder: record.NewFakeRecorder(10),
+		digestResolver: func(context.Context, string, orascreds.Store) (string, error) {
+			return resolved, nil
+		},
+		pluginFetcher: func(context.Context, string, orascreds.Store) (image.PluginFiles, error) {
+			return plugin, nil
+		},
+	}
+	ctx := t.Context()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kpi)}
+
+	listConfigMaps := func(t *testing.T) (*corev1.ConfigMap, []corev1.ConfigMap) {
+		t.Helper()
+		_, err := r.Reconcile(ctx, req)
+		require.NoError(t, err)
+
+		var got operatorv1alpha1.KongPluginInstallation
+		require.NoError(t, cl.Get(ctx, req.NamespacedName, &got))
+		require.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted)))
+		var cms corev1.ConfigMapList
+		require.NoError(t, cl.List(ctx, &cms, client.InNamespace("ns")))
+		underlying, parts := splitPluginConfigMaps(cms.Items)
+		require.Len(t, underlying, 1)
+		require.Equal(t, got.Status.UnderlyingConfigMapName, underlying[0].Name)
+		return &underlying[0], parts
+	}
+
+	t.Run("a plugin larger than a ConfigMap is split", func(t *testing.T) {
+		cm, parts := listConfigMaps(t)
+		require.ElementsMatch(t, []string{"big.lua", "handler.lua"}, lo.Keys(cm.Data))
+		require.NotContains(t, cm.Annotations, consts.AnnotationKongPluginInstallationFiles)
+		require.Len(t, parts, 1)
+		require.Equal(t, parts[0].Name, cm.Annotations[consts.AnnotationKongPluginInstallationParts])
+		require.Equal(t, "1", parts[0].Annotations[consts.AnnotationKongPluginInstallationPart])
+		require.ElementsMatch(t, []string{"migrations.init.lua", "schema.lua"}, lo.Keys(parts[0].Data))
+		require.JSONEq(t,
+			`{"migrations.init.lua":"migrations/init.lua","schema.lua":"schema.lua"}`,
+			parts[0].Annotations[consts.AnnotationKongPluginInstallationFiles],
+		)
+	})
+
+	t.Run("a deleted part is restored", func(t *testing.T) {
+		_, parts := listConfigMaps(t)
+		require.NoError(t, cl.Delete(ctx, &parts[0]))
+		cm, parts := listConfigMaps(t)
+		require.Len(t, parts, 1)
+		require.Equal(t, parts[0].Name, cm.Annotations[consts.AnnotationKongPluginInstallationParts])
+	})
+
+	t.Run("parts no longer needed are deleted", func(t *testing.T) {
+		resolved = digestMoved
+		// the tag is checked again.
+		r.digestChecks.delete(req.NamespacedName)
+		plugin = image.PluginFiles{"handler.lua": "handler", "schema.lua": "schema"}
+		cm, parts := listConfigMaps(t)
+		require.Empty(t, parts)
+		require.Equal(t, map[string]string(plugin), cm.Data)
+		require.NotContains(t, cm.Annotations, consts.AnnotationKongPluginInstallationParts)
+	})
+}
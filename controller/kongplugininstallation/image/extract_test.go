@@ -0,0 +1,146 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractKongPluginFromLayer(t *testing.T) {
+	const manifest = `{"files": ["handler.lua", "schema.lua", "daos.lua", "migrations/init.lua", "migrations/000_base.lua"]}`
+
+	testCases := []struct {
+		name        string
+		files       map[string]string
+		expected    PluginFiles
+		expectedErr string
+	}{
+		{
+			name:     "plugin without manifest",
+			files:    map[string]string{"handler.lua": "handler", "schema.lua": "schema"},
+			expected: PluginFiles{"handler.lua": "handler", "schema.lua": "schema"},
+		},
+		{
+			name:        "plugin without manifest with an unexpected file",
+			files:       map[string]string{"handler.lua": "handler", "schema.lua": "schema", "daos.lua": "daos"},
+			expectedErr: `file "daos.lua" is unexpected, required files are handler.lua and schema.lua (or list all files in kong-plugin.json)`,
+		},
+		{
+			name:        "plugin without manifest exceeding 1 MiB",
+			files:       map[string]string{"handler.lua": strings.Repeat("a", 1024*1024), "schema.lua": "schema"},
+			expectedErr: "plugin size limit of 1.00 MiB exceeded",
+		},
+		{
+			name: "plugin with manifest and subdirectories",
+			files: map[string]string{
+				"kong-plugin.json":        manifest,
+				"handler.lua":             "handler",
+				"schema.lua":              "schema",
+				"daos.lua":                "daos",
+				"migrations/init.lua":     "init",
+				"migrations/000_base.lua": strings.Repeat("a", 1024*1024),
+			},
+			expected: PluginFiles{
+				"handler.lua":             "handler",
+				"schema.lua":              "schema",
+				"daos.lua":                "daos",
+				"migrations/init.lua":     "init",
+				"migrations/000_base.lua": strings.Repeat("a", 1024*1024),
+			},
+		},
+		{
+			name: "plugin with manifest missing a file",
+			files: map[string]string{
+				"kong-plugin.json":    manifest,
+				"handler.lua":         "handler",
+				"schema.lua":          "schema",
+				"migrations/init.lua": "init",
+			},
+			expectedErr: "files listed in kong-plugin.json not found in the image: daos.lua, migrations/000_base.lua",
+		},
+		{
+			name: "plugin with manifest and a file not listed",
+			files: map[string]string{
+				"kong-plugin.json": `{"files": ["handler.lua", "schema.lua"]}`,
+				"handler.lua":      "handler",
+				"schema.lua":       "schema",
+				"api.lua":          "api",
+			},
+			expectedErr: `file "api.lua" is not listed in kong-plugin.json`,
+		},
+		{
+			name: "plugin with manifest not listing schema.lua",
+			files: map[string]string{
+				"kong-plugin.json": `{"files": ["handler.lua"]}`,
+				"handler.lua":      "handler",
+			},
+			expectedErr: "required files not listed in kong-plugin.json: schema.lua",
+		},
+		{
+			name: "plugin with manifest listing an invalid path",
+			files: map[string]string{
+				"kong-plugin.json": `{"files": ["handler.lua", "schema.lua", "vendor/my lib.lua"]}`,
+				"handler.lua":      "handler",
+				"schema.lua":       "schema",
+			},
+			expectedErr: `invalid kong-plugin.json: path "vendor/my lib.lua" is invalid`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plugin, err := extractKongPluginFromLayer(newLayer(t, tc.files))
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, plugin)
+		})
+	}
+
+	t.Run("file outside of the plugin's directory", func(t *testing.T) {
+		_, err := extractKongPluginFromLayer(newLayer(t, map[string]string{"../handler.lua": "handler"}))
+		require.ErrorContains(t, err, `file "../handler.lua" is outside of the plugin's directory`)
+	})
+
+	t.Run("file size in the header exceeding the size limit", func(t *testing.T) {
+		var b bytes.Buffer
+		gw := gzip.NewWriter(&b)
+		tw := tar.NewWriter(gw)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "handler.lua", Mode: 0o644, Size: 1 << 40}))
+		_, err := tw.Write([]byte("handler"))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+
+		_, err = extractKongPluginFromLayer(&b)
+		require.EqualError(t, err, "plugin size limit of 8.00 MiB exceeded")
+	})
+}
+
+// newLayer returns a gzipped tar archive with the provided files, and the directories containing them.
+func newLayer(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var b bytes.Buffer
+	gw := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gw)
+	dirs := make(map[string]struct{})
+	for name, content := range files {
+		if dir, _, found := strings.Cut(name, "/"); found && dir != ".." {
+			if _, ok := dirs[dir]; !ok {
+				dirs[dir] = struct{}{}
+				require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir + "/", Mode: 0o755}))
+			}
+		}
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return &b
+}
@@ -4,12 +4,17 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"path/filepath"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/types"
@@ -21,6 +26,7 @@ import (
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"oras.land/oras-go/v2/registry/remote/credentials"
 
+	"github.com/kong/gateway-operator/internal/metrics"
 	"github.com/kong/gateway-operator/modules/manager/metadata"
 )
 
@@ -28,12 +34,41 @@ import (
 const (
 	kongPluginHandler = "handler.lua"
 	kongPluginSchema  = "schema.lua"
+	// kongPluginManifest lists the files of a plugin consisting of more files than
+	// handler.lua and schema.lua, possibly in subdirectories, e.g.
+	// {"files": ["handler.lua", "schema.lua", "daos.lua", "migrations/init.lua"]}.
+	kongPluginManifest = "kong-plugin.json"
 )
 
-// PluginFiles maps a plugin's file names to their content.
-// It's expected that each plugin consists of `schema.lua` and `handler.lua` files.
+// PluginFiles maps the paths of a plugin's files, relative to the plugin's directory, to their content.
+// It's expected that each plugin consists of `schema.lua` and `handler.lua` files. Plugins consisting
+// of more files list them in a `kong-plugin.json` manifest.
 type PluginFiles map[string]string
 
+// pluginManifest is the content of the kong-plugin.json manifest of a plugin.
+type pluginManifest struct {
+	// Files are the paths of the plugin's files, relative to the plugin's directory.
+	Files []string `json:"files"`
+}
+
+// pluginFilePathSegment matches the names of the files and directories of a plugin,
+// they're restricted to the characters allowed in the keys of a ConfigMap.
+var pluginFilePathSegment = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// validatePluginFilePath checks that the path is a clean path relative to the plugin's
+// directory made of allowed names.
+func validatePluginFilePath(p string) error {
+	if p == "" || path.IsAbs(p) || path.Clean(p) != p {
+		return fmt.Errorf("path %q is not a clean relative path", p)
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == "." || segment == ".." || !pluginFilePathSegment.MatchString(segment) {
+			return fmt.Errorf("path %q is invalid, names must consist of alphanumeric characters, '-', '_' or '.'", p)
+		}
+	}
+	return nil
+}
+
 // newPluginFilesFromMap creates PluginFiles from a map of files with content.
 // It ensures that the required files handler.lua and schema.lua are only present
 // in the map.
@@ -53,15 +88,111 @@ func newPluginFilesFromMap(pluginFiles map[string]string) (PluginFiles, error) {
 	return PluginFiles(pluginFiles), nil
 }
 
+// newPluginFilesFromManifest creates PluginFiles from a map of files with content,
+// according to the plugin's manifest. It ensures that the files listed in the
+// manifest, which must include handler.lua and schema.lua, are the only ones present.
+func newPluginFilesFromManifest(manifestContent string, files map[string]string) (PluginFiles, error) {
+	var manifest pluginManifest
+	if err := json.Unmarshal([]byte(manifestContent), &manifest); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", kongPluginManifest, err)
+	}
+	listed := make(map[string]struct{}, len(manifest.Files))
+	for _, f := range manifest.Files {
+		if err := validatePluginFilePath(f); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", kongPluginManifest, err)
+		}
+		listed[f] = struct{}{}
+	}
+	var notListed []string
+	for _, f := range []string{kongPluginHandler, kongPluginSchema} {
+		if _, ok := listed[f]; !ok {
+			notListed = append(notListed, f)
+		}
+	}
+	if len(notListed) > 0 {
+		return nil, fmt.Errorf("required files not listed in %s: %s", kongPluginManifest, strings.Join(notListed, ", "))
+	}
+
+	delete(files, kongPluginManifest)
+	var missingFiles []string
+	for _, f := range manifest.Files {
+		if _, ok := files[f]; !ok {
+			missingFiles = append(missingFiles, f)
+		}
+	}
+	if len(missingFiles) > 0 {
+		return nil, fmt.Errorf("files listed in %s not found in the image: %s", kongPluginManifest, strings.Join(missingFiles, ", "))
+	}
+	paths := lo.Keys(files)
+	slices.Sort(paths)
+	for _, f := range paths {
+		if _, ok := listed[f]; !ok {
+			return nil, fmt.Errorf("file %q is not listed in %s", f, kongPluginManifest)
+		}
+		if !utf8.ValidString(files[f]) {
+			return nil, fmt.Errorf("file %q is not a UTF-8 encoded text file", f)
+		}
+	}
+	return PluginFiles(files), nil
+}
+
 // FetchPlugin fetches the content of the plugin from the image URL. When authentication is not needed pass nil.
+// The latency and the outcome of the fetch are recorded in the operator's metrics.
 func FetchPlugin(ctx context.Context, imageURL string, credentialsStore credentials.Store) (PluginFiles, error) {
+	start := time.Now()
+	plugin, err := fetchPlugin(ctx, imageURL, credentialsStore)
+
+	var registryName string
+	if ref, parseErr := name.ParseReference(imageURL); parseErr == nil {
+		registryName = ref.Context().RegistryStr()
+	}
+	metrics.RecordKongPluginFetch(registryName, err == nil, time.Since(start))
+
+	return plugin, err
+}
+
+// ResolveDigest returns the digest of the manifest the image URL currently
+// points to. When the image URL already references a digest, it's returned
+// without contacting the registry. When authentication is not needed pass nil.
+func ResolveDigest(ctx context.Context, imageURL string, credentialsStore credentials.Store) (string, error) {
 	ref, err := name.ParseReference(imageURL)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected format of image url: %w", err)
+		return "", fmt.Errorf("unexpected format of image url: %w", err)
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return digest.DigestStr(), nil
+	}
+	repository, err := newRepository(ctx, imageURL, ref, credentialsStore)
+	if err != nil {
+		return "", err
+	}
+	desc, err := repository.Resolve(ctx, ref.Identifier())
+	if err != nil {
+		return "", fmt.Errorf("can't resolve image: %s, because: %w", imageURL, err)
+	}
+	return desc.Digest.String(), nil
+}
+
+// PinImage returns the image URL of the manifest with the provided digest in
+// the repository of the provided image URL.
+func PinImage(imageURL string, digest string) (string, error) {
+	ref, err := name.ParseReference(imageURL)
+	if err != nil {
+		return "", fmt.Errorf("unexpected format of image url: %w", err)
+	}
+	pinned, err := name.NewDigest(ref.Context().Name() + "@" + digest)
+	if err != nil {
+		return "", fmt.Errorf("unexpected digest %s of image %s: %w", digest, imageURL, err)
 	}
-	registryName, repositoryName, imageTag := ref.Context().RegistryStr(), ref.Context().RepositoryStr(), ref.Identifier()
-	// Errors for NewRegistry(..) and Repository(..) should never happen because the image URL has been already validated above.
-	registry, err := remote.NewRegistry(registryName)
+	return pinned.String(), nil
+}
+
+func newRepository(
+	ctx context.Context, imageURL string, ref name.Reference, credentialsStore credentials.Store,
+) (*remote.Repository, error) {
+	registryName, repositoryName := ref.Context().RegistryStr(), ref.Context().RepositoryStr()
+	// Errors for NewRegistry(..) and Repository(..) should never happen because the image URL has been already validated.
+	reg, err := remote.NewRegistry(registryName)
 	if err != nil {
 		return nil, fmt.Errorf("for image: %s unexpected registry: %s, because: %w", imageURL, registryName, err)
 	}
@@ -69,17 +200,31 @@ func FetchPlugin(ctx context.Context, imageURL string, credentialsStore credenti
 	if credentialsStore != nil {
 		credentialFunc = credentials.Credential(credentialsStore)
 	}
-	registry.Client = &auth.Client{
+	reg.Client = &auth.Client{
 		Client:     auth.DefaultClient.Client,
 		Header:     map[string][]string{"User-Agent": {metadata.Metadata().UserAgent()}},
 		Cache:      auth.NewCache(),
 		Credential: credentialFunc,
 	}
 
-	repository, err := registry.Repository(ctx, repositoryName)
+	repository, err := reg.Repository(ctx, repositoryName)
 	if err != nil {
 		return nil, fmt.Errorf("for image: %s unexpected repository: %s, because: %w", imageURL, registryName, err)
 	}
+	// The concrete type is needed to look up the referrers of manifests.
+	return repository.(*remote.Repository), nil
+}
+
+func fetchPlugin(ctx context.Context, imageURL string, credentialsStore credentials.Store) (PluginFiles, error) {
+	ref, err := name.ParseReference(imageURL)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected format of image url: %w", err)
+	}
+	imageTag := ref.Identifier()
+	repository, err := newRepository(ctx, imageURL, ref, credentialsStore)
+	if err != nil {
+		return nil, err
+	}
 
 	var (
 		mut                        sync.Mutex
@@ -127,17 +272,34 @@ func (sl sizeLimitBytes) String() string {
 	return fmt.Sprintf("%.2f MiB", float64(sl)/(1024*1024))
 }
 
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
 func extractKongPluginFromLayer(r io.Reader) (PluginFiles, error) {
 	// Search for the files walking through the archive.
-	// The size of a plugin is limited to the size of a ConfigMap in Kubernetes.
-	const sizeLimit_1MiB sizeLimitBytes = 1024 * 1024
+	// The size of a plugin without a manifest is limited to the size of a ConfigMap in Kubernetes.
+	// Plugins with a manifest are split across several ConfigMaps when needed.
+	const (
+		sizeLimit_1MiB sizeLimitBytes = 1024 * 1024
+		sizeLimit_8MiB sizeLimitBytes = 8 * 1024 * 1024
+	)
 
 	gr, err := gzip.NewReader(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse layer as tar.gz: %w", err)
 	}
-	pluginFiles := make(map[string]string)
-	for tr := tar.NewReader(io.LimitReader(gr, sizeLimit_1MiB.int64())); ; {
+	layer := &countingReader{r: io.LimitReader(gr, sizeLimit_8MiB.int64())}
+	files := make(map[string]string)
+	for tr := tar.NewReader(layer); ; {
 		h, err := tr.Next()
 		if err == io.EOF {
 			break
@@ -145,23 +307,52 @@ func extractKongPluginFromLayer(r io.Reader) (PluginFiles, error) {
 		if err != nil {
 			return nil, fmt.Errorf("unexpected error during looking for plugin: %w", err)
 		}
+		if h.Typeflag == tar.TypeDir {
+			continue
+		}
+		filePath := path.Clean(strings.TrimPrefix(h.Name, "/"))
+		if !h.FileInfo().Mode().IsRegular() {
+			return nil, fmt.Errorf("file %q is not a regular file", filePath)
+		}
+		if filePath == ".." || strings.HasPrefix(filePath, "../") {
+			return nil, fmt.Errorf("file %q is outside of the plugin's directory", h.Name)
+		}
 
-		switch fileName := filepath.Base(h.Name); fileName {
-		case kongPluginHandler, kongPluginSchema:
-			file := make([]byte, h.Size)
-			if _, err := io.ReadFull(tr, file); err != nil {
-				if errors.Is(err, io.ErrUnexpectedEOF) {
-					return nil, fmt.Errorf("plugin size limit of %s exceeded", sizeLimit_1MiB)
-				}
-				return nil, fmt.Errorf("failed to read %s from image: %w", fileName, err)
+		// The size in the header isn't trusted, the file has to fit in what's left of the limit.
+		if h.Size > sizeLimit_8MiB.int64()-layer.n {
+			return nil, fmt.Errorf("plugin size limit of %s exceeded", sizeLimit_8MiB)
+		}
+		file := make([]byte, h.Size)
+		if _, err := io.ReadFull(tr, file); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, fmt.Errorf("plugin size limit of %s exceeded", sizeLimit_8MiB)
 			}
-			pluginFiles[fileName] = string(file)
+			return nil, fmt.Errorf("failed to read %s from image: %w", filePath, err)
+		}
+		files[filePath] = string(file)
+	}
+
+	if manifest, ok := files[kongPluginManifest]; ok {
+		return newPluginFilesFromManifest(manifest, files)
+	}
+
+	// Without a manifest, only handler.lua and schema.lua are expected.
+	paths := lo.Keys(files)
+	slices.Sort(paths)
+	pluginFiles := make(map[string]string, len(files))
+	for _, filePath := range paths {
+		switch fileName := path.Base(filePath); fileName {
+		case kongPluginHandler, kongPluginSchema:
+			pluginFiles[fileName] = files[filePath]
 		default:
 			return nil, fmt.Errorf(
-				"file %q is unexpected, required files are %s and %s", fileName, kongPluginHandler, kongPluginSchema,
+				"file %q is unexpected, required files are %s and %s (or list all files in %s)",
+				fileName, kongPluginHandler, kongPluginSchema, kongPluginManifest,
 			)
 		}
 	}
-
+	if layer.n > sizeLimit_1MiB.int64() {
+		return nil, fmt.Errorf("plugin size limit of %s exceeded", sizeLimit_1MiB)
+	}
 	return newPluginFilesFromMap(pluginFiles)
 }
@@ -1,6 +1,7 @@
 package image_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -98,6 +99,70 @@ func TestFetchPluginContent(t *testing.T) {
 	})
 }
 
+func TestResolveDigest(t *testing.T) {
+	const digest = "sha256:0d1a0ec0fbbb61d5a2ef9ed2d0cbb2d2b0bcf82bbd2a47b0eb5ae5c9a4d0dc47"
+
+	t.Run("invalid image URL", func(t *testing.T) {
+		_, err := image.ResolveDigest(t.Context(), "foo bar", nil)
+		require.ErrorContains(t, err, "unexpected format of image url: could not parse reference: foo bar")
+	})
+
+	t.Run("image URL referencing a digest is not resolved", func(t *testing.T) {
+		resolved, err := image.ResolveDigest(t.Context(), "registry.example/plugin-example/valid@"+digest, nil)
+		require.NoError(t, err)
+		require.Equal(t, digest, resolved)
+	})
+}
+
+func TestPinImage(t *testing.T) {
+	const digest = "sha256:0d1a0ec0fbbb61d5a2ef9ed2d0cbb2d2b0bcf82bbd2a47b0eb5ae5c9a4d0dc47"
+
+	testCases := []struct {
+		name        string
+		imageURL    string
+		digest      string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "tagged image",
+			imageURL: "registry.example/plugin-example/valid:0.1.0",
+			digest:   digest,
+			expected: "registry.example/plugin-example/valid@" + digest,
+		},
+		{
+			name:     "image without tag",
+			imageURL: "registry.example:5000/valid",
+			digest:   digest,
+			expected: "registry.example:5000/valid@" + digest,
+		},
+		{
+			name:     "image already pinned to another digest",
+			imageURL: "registry.example/valid@sha256:" + strings.Repeat("a", 64),
+			digest:   digest,
+			expected: "registry.example/valid@" + digest,
+		},
+		{
+			name:        "invalid digest",
+			imageURL:    "registry.example/valid:0.1.0",
+			digest:      "sha256:short",
+			expectedErr: "unexpected digest sha256:short of image registry.example/valid:0.1.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pinned, err := image.PinImage(tc.imageURL, tc.digest)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, pinned)
+		})
+	}
+}
+
 func requireExpectedContent(t *testing.T, actual map[string]string) {
 	t.Helper()
 	require.Len(t, actual, 2)
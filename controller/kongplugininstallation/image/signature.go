@@ -0,0 +1,327 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	notation "github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	notationx509 "github.com/notaryproject/notation-core-go/x509"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/samber/lo"
+	sigstoresignature "github.com/sigstore/sigstore/pkg/signature"
+	sigstorepayload "github.com/sigstore/sigstore/pkg/signature/payload"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// Media types, annotations and values of the signatures created by cosign and notation.
+const (
+	cosignSimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	cosignSignatureAnnotation    = "dev.cosignproject.cosign/signature"
+
+	notationSignatureArtifactType = "application/vnd.cncf.notary.signature"
+	notationPayloadContentType    = "application/vnd.cncf.notary.payload.v1+json"
+)
+
+// maxSignatureSize is the maximum size of the signature manifests and payloads fetched from a registry.
+const maxSignatureSize = 1024 * 1024
+
+// ErrNoSignature is returned by VerifySignature when the image has neither a cosign nor a notation signature.
+var ErrNoSignature = errors.New("image has no cosign or notation signature")
+
+// TrustedKeys are the keys the signatures of plugin images are verified against.
+type TrustedKeys struct {
+	// CosignPublicKeys verify the cosign signatures.
+	CosignPublicKeys []crypto.PublicKey
+	// NotationRoots are the root certificates the certificate chains of the notation signatures must chain to.
+	NotationRoots *x509.CertPool
+}
+
+// ParseTrustedKeys parses the PEM encoded public keys (cosign) and certificates (notation) stored
+// in the values of the provided map, e.g. the data of a Secret. Each value may hold several PEM blocks.
+func ParseTrustedKeys(data map[string][]byte) (TrustedKeys, error) {
+	var (
+		keys  TrustedKeys
+		roots []*x509.Certificate
+	)
+	entries := lo.Keys(data)
+	slices.Sort(entries)
+	for _, entry := range entries {
+		rest := data[entry]
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			switch block.Type {
+			case "PUBLIC KEY":
+				key, err := x509.ParsePKIXPublicKey(block.Bytes)
+				if err != nil {
+					return TrustedKeys{}, fmt.Errorf("can't parse public key in %q: %w", entry, err)
+				}
+				keys.CosignPublicKeys = append(keys.CosignPublicKeys, key)
+			case "CERTIFICATE":
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return TrustedKeys{}, fmt.Errorf("can't parse certificate in %q: %w", entry, err)
+				}
+				roots = append(roots, cert)
+			default:
+				return TrustedKeys{}, fmt.Errorf("unexpected PEM block %q in %q, expected PUBLIC KEY or CERTIFICATE", block.Type, entry)
+			}
+		}
+	}
+	if len(keys.CosignPublicKeys) == 0 && len(roots) == 0 {
+		return TrustedKeys{}, errors.New("no PEM encoded public key or certificate found")
+	}
+	if len(roots) > 0 {
+		keys.NotationRoots = x509.NewCertPool()
+		for _, cert := range roots {
+			keys.NotationRoots.AddCert(cert)
+		}
+	}
+	return keys, nil
+}
+
+// VerifySignature verifies that the manifest with the provided digest, in the repository of the image URL,
+// has a cosign or a notation signature made with one of the trusted keys. The signatures are looked up in the
+// same repository: the cosign ones under the `sha256-<hex>.sig` tag and the notation ones among the referrers
+// of the manifest. Keys are never fetched from the network. When authentication is not needed pass nil.
+func VerifySignature(
+	ctx context.Context, imageURL string, digest string, keys TrustedKeys, credentialsStore credentials.Store,
+) error {
+	ref, err := name.ParseReference(imageURL)
+	if err != nil {
+		return fmt.Errorf("unexpected format of image url: %w", err)
+	}
+	repository, err := newRepository(ctx, imageURL, ref, credentialsStore)
+	if err != nil {
+		return err
+	}
+	desc, err := repository.Resolve(ctx, digest)
+	if err != nil {
+		return fmt.Errorf("can't resolve image: %s@%s, because: %w", ref.Context().Name(), digest, err)
+	}
+	return verifySignature(ctx, repository, desc, keys)
+}
+
+// signatureStore is the part of a repository needed to look up the signatures of a manifest.
+type signatureStore interface {
+	content.ReadOnlyGraphStorage
+	content.Resolver
+}
+
+func verifySignature(ctx context.Context, store signatureStore, desc ociv1.Descriptor, keys TrustedKeys) error {
+	var (
+		signed bool
+		errs   []error
+	)
+	for _, verify := range []func(context.Context, signatureStore, ociv1.Descriptor, TrustedKeys) (bool, error){
+		verifyCosignSignatures,
+		verifyNotationSignatures,
+	} {
+		found, err := verify(ctx, store, desc, keys)
+		if found && err == nil {
+			return nil
+		}
+		signed = signed || found
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !signed && len(errs) == 0 {
+		return ErrNoSignature
+	}
+	return errors.Join(errs...)
+}
+
+// verifyCosignSignatures returns whether the manifest has cosign signatures and nil error when one
+// of them is valid.
+func verifyCosignSignatures(
+	ctx context.Context, store signatureStore, desc ociv1.Descriptor, keys TrustedKeys,
+) (bool, error) {
+	digest := desc.Digest.String()
+	signaturesDesc, err := store.Resolve(ctx, strings.Replace(digest, ":", "-", 1)+".sig")
+	if errors.Is(err, errdef.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("can't resolve cosign signatures: %w", err)
+	}
+	var manifest ociv1.Manifest
+	if err := fetchSignatureJSON(ctx, store, signaturesDesc, &manifest); err != nil {
+		return false, fmt.Errorf("can't fetch cosign signatures: %w", err)
+	}
+
+	var errs []error
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != cosignSimpleSigningMediaType {
+			continue
+		}
+		payload, err := fetchSignatureContent(ctx, store, layer)
+		if err != nil {
+			return true, fmt.Errorf("can't fetch cosign signature: %w", err)
+		}
+		err = verifyCosignSignature(payload, layer.Annotations[cosignSignatureAnnotation], digest, keys.CosignPublicKeys)
+		if err == nil {
+			return true, nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return false, nil
+	}
+	return true, fmt.Errorf("invalid cosign signature: %w", errors.Join(errs...))
+}
+
+// verifyCosignSignature verifies the signature of a cosign simple signing payload and that the
+// payload is about the manifest with the provided digest.
+func verifyCosignSignature(payload []byte, signature string, digest string, keys []crypto.PublicKey) error {
+	if len(keys) == 0 {
+		return errors.New("no trusted cosign public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(sig) == 0 {
+		return fmt.Errorf("missing or malformed %s annotation", cosignSignatureAnnotation)
+	}
+	if !lo.ContainsBy(keys, func(key crypto.PublicKey) bool {
+		verifier, err := sigstoresignature.LoadVerifier(key, crypto.SHA256)
+		return err == nil && verifier.VerifySignature(bytes.NewReader(sig), bytes.NewReader(payload)) == nil
+	}) {
+		return errors.New("signature doesn't match any trusted public key")
+	}
+
+	var simpleSigning sigstorepayload.SimpleContainerImage
+	if err := json.Unmarshal(payload, &simpleSigning); err != nil {
+		return fmt.Errorf("malformed payload: %w", err)
+	}
+	if simpleSigning.Critical.Type != sigstorepayload.CosignSignatureType {
+		return fmt.Errorf("unexpected payload type %q", simpleSigning.Critical.Type)
+	}
+	if d := simpleSigning.Critical.Image.DockerManifestDigest; d != digest {
+		return fmt.Errorf("signature is for digest %s", d)
+	}
+	return nil
+}
+
+// verifyNotationSignatures returns whether the manifest has notation signatures and nil error when
+// one of them is valid.
+func verifyNotationSignatures(
+	ctx context.Context, store signatureStore, desc ociv1.Descriptor, keys TrustedKeys,
+) (bool, error) {
+	referrers, err := registry.Referrers(ctx, store, desc, notationSignatureArtifactType)
+	if err != nil {
+		return false, fmt.Errorf("can't list notation signatures: %w", err)
+	}
+
+	var errs []error
+	for _, referrer := range referrers {
+		var manifest ociv1.Manifest
+		if err := fetchSignatureJSON(ctx, store, referrer, &manifest); err != nil {
+			return true, fmt.Errorf("can't fetch notation signature: %w", err)
+		}
+		for _, layer := range manifest.Layers {
+			if layer.MediaType != jws.MediaTypeEnvelope {
+				errs = append(errs, fmt.Errorf("unsupported signature envelope %s", layer.MediaType))
+				continue
+			}
+			envelope, err := fetchSignatureContent(ctx, store, layer)
+			if err != nil {
+				return true, fmt.Errorf("can't fetch notation signature: %w", err)
+			}
+			err = verifyNotationEnvelope(envelope, desc.Digest.String(), keys.NotationRoots)
+			if err == nil {
+				return true, nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return false, nil
+	}
+	return true, fmt.Errorf("invalid notation signature: %w", errors.Join(errs...))
+}
+
+// verifyNotationEnvelope verifies a notation JWS signature envelope: its signature must be made by
+// the leaf certificate of its code signing certificate chain, the chain must chain to one of the roots
+// and its payload must be about the manifest with the provided digest.
+func verifyNotationEnvelope(envelope []byte, digest string, roots *x509.CertPool) error {
+	if roots == nil {
+		return errors.New("no trusted notation root certificate")
+	}
+	env, err := notation.ParseEnvelope(jws.MediaTypeEnvelope, envelope)
+	if err != nil {
+		return fmt.Errorf("malformed envelope: %w", err)
+	}
+	envelopeContent, err := env.Verify()
+	if err != nil {
+		return fmt.Errorf("signature doesn't match the certificate: %w", err)
+	}
+	signerInfo := envelopeContent.SignerInfo
+	if signerInfo.SignedAttributes.SigningScheme != notation.SigningSchemeX509 {
+		return fmt.Errorf("unsupported signing scheme %q", signerInfo.SignedAttributes.SigningScheme)
+	}
+	if expiry := signerInfo.SignedAttributes.Expiry; !expiry.IsZero() && time.Now().After(expiry) {
+		return fmt.Errorf("signature expired at %s", expiry)
+	}
+
+	chain := signerInfo.CertificateChain
+	if err := notationx509.ValidateCodeSigningCertChain(chain, &signerInfo.SignedAttributes.SigningTime); err != nil {
+		return fmt.Errorf("invalid certificate chain: %w", err)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("untrusted certificate chain: %w", err)
+	}
+
+	if ct := envelopeContent.Payload.ContentType; ct != notationPayloadContentType {
+		return fmt.Errorf("unexpected payload content type %q", ct)
+	}
+	var payload struct {
+		TargetArtifact ociv1.Descriptor `json:"targetArtifact"`
+	}
+	if err := json.Unmarshal(envelopeContent.Payload.Content, &payload); err != nil {
+		return fmt.Errorf("malformed payload: %w", err)
+	}
+	if d := payload.TargetArtifact.Digest.String(); d != digest {
+		return fmt.Errorf("signature is for digest %s", d)
+	}
+	return nil
+}
+
+func fetchSignatureContent(ctx context.Context, fetcher content.Fetcher, desc ociv1.Descriptor) ([]byte, error) {
+	if desc.Size > maxSignatureSize {
+		return nil, fmt.Errorf("size %d of %s exceeds the limit of %d bytes", desc.Size, desc.Digest, maxSignatureSize)
+	}
+	return content.FetchAll(ctx, fetcher, desc)
+}
+
+func fetchSignatureJSON(ctx context.Context, fetcher content.Fetcher, desc ociv1.Descriptor, v any) error {
+	b, err := fetchSignatureContent(ctx, fetcher, desc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
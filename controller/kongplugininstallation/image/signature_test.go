@@ -0,0 +1,266 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	notation "github.com/notaryproject/notation-core-go/signature"
+	"github.com/notaryproject/notation-core-go/signature/jws"
+	"github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+func TestParseTrustedKeys(t *testing.T) {
+	key := generateECDSAKey(t)
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	ca, _ := generateCertificate(t, nil, nil, true)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+
+	t.Run("public keys and certificates", func(t *testing.T) {
+		keys, err := ParseTrustedKeys(map[string][]byte{
+			"cosign.pub": pubPEM,
+			"roots.pem":  append(append([]byte{}, caPEM...), caPEM...),
+		})
+		require.NoError(t, err)
+		require.Len(t, keys.CosignPublicKeys, 1)
+		require.NotNil(t, keys.NotationRoots)
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		_, err := ParseTrustedKeys(map[string][]byte{"empty": []byte("not pem")})
+		require.EqualError(t, err, "no PEM encoded public key or certificate found")
+	})
+
+	t.Run("private key", func(t *testing.T) {
+		_, err := ParseTrustedKeys(map[string][]byte{
+			"cosign.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")}),
+		})
+		require.EqualError(t, err, `unexpected PEM block "EC PRIVATE KEY" in "cosign.key", expected PUBLIC KEY or CERTIFICATE`)
+	})
+}
+
+func TestVerifySignature(t *testing.T) {
+	ctx := t.Context()
+
+	cosignKey := generateECDSAKey(t)
+	otherKey := generateECDSAKey(t)
+	ca, caKey := generateCertificate(t, nil, nil, true)
+	leaf, leafKey := generateCertificate(t, ca, caKey, false)
+	otherCA, _ := generateCertificate(t, nil, nil, true)
+
+	trustedKeys := func(cosignKeys []crypto.PublicKey, roots ...*x509.Certificate) TrustedKeys {
+		keys := TrustedKeys{CosignPublicKeys: cosignKeys}
+		if len(roots) > 0 {
+			keys.NotationRoots = x509.NewCertPool()
+			for _, root := range roots {
+				keys.NotationRoots.AddCert(root)
+			}
+		}
+		return keys
+	}
+
+	t.Run("unsigned image", func(t *testing.T) {
+		store, image := newStoreWithImage(t)
+		err := verifySignature(ctx, store, image, trustedKeys([]crypto.PublicKey{cosignKey.Public()}, ca))
+		require.ErrorIs(t, err, ErrNoSignature)
+	})
+
+	t.Run("cosign signature", func(t *testing.T) {
+		store, image := newStoreWithImage(t)
+		pushCosignSignature(t, store, image, cosignKey, image.Digest)
+
+		require.NoError(t, verifySignature(ctx, store, image, trustedKeys([]crypto.PublicKey{otherKey.Public(), cosignKey.Public()})))
+		err := verifySignature(ctx, store, image, trustedKeys([]crypto.PublicKey{otherKey.Public()}))
+		require.ErrorContains(t, err, "invalid cosign signature: signature doesn't match any trusted public key")
+	})
+
+	t.Run("cosign signature of another digest", func(t *testing.T) {
+		store, image := newStoreWithImage(t)
+		pushCosignSignature(t, store, image, cosignKey, digest.FromString("other"))
+
+		err := verifySignature(ctx, store, image, trustedKeys([]crypto.PublicKey{cosignKey.Public()}))
+		require.ErrorContains(t, err, "invalid cosign signature: signature is for digest "+digest.FromString("other").String())
+	})
+
+	t.Run("notation signature", func(t *testing.T) {
+		store, image := newStoreWithImage(t)
+		pushNotationSignature(t, store, image, []*x509.Certificate{leaf, ca}, leafKey, image.Digest)
+
+		require.NoError(t, verifySignature(ctx, store, image, trustedKeys(nil, ca)))
+		err := verifySignature(ctx, store, image, trustedKeys(nil, otherCA))
+		require.ErrorContains(t, err, "invalid notation signature: untrusted certificate chain")
+	})
+
+	t.Run("notation signature made with a key not matching the certificate", func(t *testing.T) {
+		store, image := newStoreWithImage(t)
+		pushNotationSignature(t, store, image, []*x509.Certificate{leaf, ca}, otherKey, image.Digest)
+
+		err := verifySignature(ctx, store, image, trustedKeys(nil, ca))
+		require.ErrorContains(t, err, "invalid notation signature: signature doesn't match the certificate")
+	})
+}
+
+func generateECDSAKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+// generateCertificate generates a CA certificate or a code signing certificate issued by
+// the provided parent. The certificate is self-signed when parent is nil.
+func generateCertificate(
+	t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key := generateECDSAKey(t)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "plugins.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func pushContent(
+	t *testing.T, store *memory.Store, mediaType string, data []byte, annotations map[string]string,
+) ociv1.Descriptor {
+	t.Helper()
+	desc := ociv1.Descriptor{
+		MediaType:   mediaType,
+		Digest:      digest.FromBytes(data),
+		Size:        int64(len(data)),
+		Annotations: annotations,
+	}
+	exists, err := store.Exists(context.Background(), desc)
+	require.NoError(t, err)
+	if !exists {
+		require.NoError(t, store.Push(context.Background(), desc, bytes.NewReader(data)))
+	}
+	return desc
+}
+
+func pushManifest(t *testing.T, store *memory.Store, manifest ociv1.Manifest) ociv1.Descriptor {
+	t.Helper()
+	manifest.Versioned.SchemaVersion = 2
+	manifest.MediaType = ociv1.MediaTypeImageManifest
+	if manifest.Config.MediaType == "" {
+		manifest.Config = pushContent(t, store, ociv1.MediaTypeEmptyJSON, []byte("{}"), nil)
+	}
+	b, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	desc := pushContent(t, store, ociv1.MediaTypeImageManifest, b, nil)
+	desc.ArtifactType = manifest.ArtifactType
+	return desc
+}
+
+func newStoreWithImage(t *testing.T) (*memory.Store, ociv1.Descriptor) {
+	t.Helper()
+	store := memory.New()
+	layer := pushContent(t, store, ociv1.MediaTypeImageLayerGzip, []byte("plugin"), nil)
+	return store, pushManifest(t, store, ociv1.Manifest{Layers: []ociv1.Descriptor{layer}})
+}
+
+func pushCosignSignature(
+	t *testing.T, store *memory.Store, image ociv1.Descriptor, key *ecdsa.PrivateKey, signedDigest digest.Digest,
+) {
+	t.Helper()
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"registry.example.com/plugins/rate-limiter"},` +
+		`"image":{"docker-manifest-digest":"` + signedDigest.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	hash := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+
+	layer := pushContent(t, store, cosignSimpleSigningMediaType, payload, map[string]string{
+		cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+	})
+	desc := pushManifest(t, store, ociv1.Manifest{Layers: []ociv1.Descriptor{layer}})
+	require.NoError(t, store.Tag(context.Background(), desc, "sha256-"+image.Digest.Encoded()+".sig"))
+}
+
+func pushNotationSignature(
+	t *testing.T, store *memory.Store, image ociv1.Descriptor, chain []*x509.Certificate, key *ecdsa.PrivateKey, signedDigest digest.Digest,
+) {
+	t.Helper()
+	payload, err := json.Marshal(map[string]any{
+		"targetArtifact": ociv1.Descriptor{
+			MediaType: ociv1.MediaTypeImageManifest,
+			Digest:    signedDigest,
+			Size:      image.Size,
+		},
+	})
+	require.NoError(t, err)
+	envelope, err := jws.NewEnvelope().Sign(&notation.SignRequest{
+		Payload: notation.Payload{
+			ContentType: notationPayloadContentType,
+			Content:     payload,
+		},
+		Signer:        notationSigner{key: key, chain: chain},
+		SigningTime:   time.Now(),
+		SigningScheme: notation.SigningSchemeX509,
+		SigningAgent:  "notation-go/1.3.0",
+	})
+	require.NoError(t, err)
+
+	layer := pushContent(t, store, jws.MediaTypeEnvelope, envelope, nil)
+	pushManifest(t, store, ociv1.Manifest{
+		ArtifactType: notationSignatureArtifactType,
+		Subject:      &image,
+		Layers:       []ociv1.Descriptor{layer},
+	})
+}
+
+// notationSigner signs notation signatures with a P-256 key and attaches the provided
+// certificate chain, which doesn't have to match the key.
+type notationSigner struct {
+	key   *ecdsa.PrivateKey
+	chain []*x509.Certificate
+}
+
+func (s notationSigner) Sign(payload []byte) ([]byte, []*x509.Certificate, error) {
+	hash := sha256.Sum256(payload)
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, hash[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	b := make([]byte, 64)
+	r.FillBytes(b[:32])
+	sig.FillBytes(b[32:])
+	return b, s.chain, nil
+}
+
+func (s notationSigner) KeySpec() (notation.KeySpec, error) {
+	return notation.KeySpec{Type: notation.KeyTypeEC, Size: 256}, nil
+}
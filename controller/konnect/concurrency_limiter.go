@@ -0,0 +1,56 @@
+package konnect
+
+import (
+	"context"
+	"sync"
+)
+
+// concurrencyLimiter limits the number of concurrently running reconciles to
+// the value returned by limit. Unlike controller's MaxConcurrentReconciles the
+// limit is evaluated on every acquire so it can be changed at runtime.
+type concurrencyLimiter struct {
+	limit func() uint
+
+	lock     sync.Mutex
+	inFlight uint
+	// released is closed and replaced whenever a slot is released so that
+	// waiters can re-evaluate the limit.
+	released chan struct{}
+}
+
+func newConcurrencyLimiter(limit func() uint) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		limit:    limit,
+		released: make(chan struct{}),
+	}
+}
+
+// acquire blocks until a slot is available or the context is done.
+// A limit of 0 is treated as 1.
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	for {
+		l.lock.Lock()
+		if l.inFlight < max(l.limit(), 1) {
+			l.inFlight++
+			l.lock.Unlock()
+			return nil
+		}
+		released := l.released
+		l.lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+// release frees a slot acquired with acquire.
+func (l *concurrencyLimiter) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.inFlight--
+	close(l.released)
+	l.released = make(chan struct{})
+}
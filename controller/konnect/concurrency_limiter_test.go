@@ -0,0 +1,42 @@
+package konnect
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	var limit atomic.Uint64
+	limit.Store(1)
+	l := newConcurrencyLimiter(func() uint { return uint(limit.Load()) })
+
+	require.NoError(t, l.acquire(context.Background()))
+
+	t.Log("second acquire blocks until the context is done")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, l.acquire(ctx), context.DeadlineExceeded)
+
+	t.Log("second acquire unblocks after a release")
+	acquired := make(chan struct{})
+	go func() {
+		require.NoError(t, l.acquire(context.Background()))
+		close(acquired)
+	}()
+	l.release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		require.FailNow(t, "acquire did not unblock after release")
+	}
+
+	t.Log("raising the limit allows more concurrent acquires")
+	limit.Store(2)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, l.acquire(ctx))
+}
@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,13 +23,17 @@ import (
 
 	"github.com/kong/gateway-operator/controller/konnect/ops"
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
 	"github.com/kong/gateway-operator/internal/utils/index"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	konnectresource "github.com/kong/gateway-operator/pkg/utils/konnect/resources"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -47,6 +52,18 @@ type KonnectExtensionReconciler struct {
 	ClusterCASecretName      string
 	ClusterCASecretNamespace string
 	ClusterCAKeyConfig       secrets.KeyConfig
+	EventRecorder            record.EventRecorder
+
+	// SyncPeriodFunc, when set, takes precedence over SyncPeriod and allows
+	// the sync period to be changed without restarting the operator.
+	SyncPeriodFunc func() time.Duration
+}
+
+func (r *KonnectExtensionReconciler) syncPeriod() time.Duration {
+	if r.SyncPeriodFunc != nil {
+		return r.SyncPeriodFunc()
+	}
+	return r.SyncPeriod
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -101,7 +118,7 @@ func (r *KonnectExtensionReconciler) SetupWithManager(ctx context.Context, mgr c
 				enqueueKonnectExtensionsForKonnectGatewayControlPlane(mgr.GetClient()),
 			),
 		).
-		Complete(r)
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("KonnectExtension", r)))
 }
 
 // listExtendableReferencedExtensions returns a list of all the KonnectExtensions referenced by the Extendable object.
@@ -135,8 +152,12 @@ func listExtendableReferencedExtensions[t extensions.ExtendableT](_ context.Cont
 
 // Reconcile reconciles a KonnectExtension object.
 func (r *KonnectExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	var ext konnectv1alpha1.KonnectExtension
 	if err := r.Client.Get(ctx, req.NamespacedName, &ext); err != nil {
+		if k8serrors.IsNotFound(err) {
+			metrics.DeleteManagedCertificateExpiryForOwner(konnectv1alpha1.KonnectExtensionKind, req.NamespacedName)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -411,7 +432,7 @@ func (r *KonnectExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		log.Debug(logger, "DataPlane client certificate list retrieval failed in Konnect")
 		// Setting "Requeue: true" along with RequeueAfter makes the controller bulletproof, as
 		// if the syncPeriod is set to zero, the controller won't requeue.
-		return ctrl.Result{Requeue: true, RequeueAfter: r.SyncPeriod}, err
+		return ctrl.Result{Requeue: true, RequeueAfter: r.syncPeriod()}, err
 	}
 
 	var (
@@ -478,7 +499,7 @@ func (r *KonnectExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 				}
 				// Setting "Requeue: true" along with RequeueAfter makes the controller bulletproof, as
 				// if the syncPeriod is set to zero, the controller won't requeue.
-				return ctrl.Result{Requeue: true, RequeueAfter: r.SyncPeriod}, err
+				return ctrl.Result{Requeue: true, RequeueAfter: r.syncPeriod()}, err
 			}
 			updated, res, err := patch.WithFinalizer(ctx, r.Client, client.Object(certificateSecret), KonnectCleanupFinalizer)
 			if err != nil || !res.IsZero() {
@@ -488,7 +509,7 @@ func (r *KonnectExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 				log.Info(logger, "konnect-cleanup finalizer on the referenced secret updated")
 				// Setting "Requeue: true" along with RequeueAfter makes the controller bulletproof, as
 				// if the syncPeriod is set to zero, the controller won't requeue.
-				return ctrl.Result{Requeue: true, RequeueAfter: r.SyncPeriod}, err
+				return ctrl.Result{Requeue: true, RequeueAfter: r.syncPeriod()}, err
 			}
 		}
 		updated, res, err := patch.WithFinalizer(ctx, r.Client, &ext, KonnectCleanupFinalizer)
@@ -539,7 +560,7 @@ func (r *KonnectExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 				// In case of an error in the Konnect ops, the resync period will take care of a new creation attempt.
 				// Setting "Requeue: true" along with RequeueAfter makes the controller bulletproof, as
 				// if the syncPeriod is set to zero, the controller won't requeue.
-				return ctrl.Result{Requeue: true, RequeueAfter: r.SyncPeriod}, err
+				return ctrl.Result{Requeue: true, RequeueAfter: r.syncPeriod()}, err
 			}
 			return ctrl.Result{Requeue: true}, err
 		}
@@ -638,6 +659,6 @@ func (r *KonnectExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	log.Debug(logger, "reconciled")
 	return ctrl.Result{
 		Requeue:      true,
-		RequeueAfter: r.SyncPeriod,
+		RequeueAfter: r.syncPeriod(),
 	}, nil
 }
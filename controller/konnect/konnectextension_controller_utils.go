@@ -129,7 +129,7 @@ func (r *KonnectExtensionReconciler) getKonnectControlPlane(
 		log.Debug(logger, "ControlPlane retrieval failed in Konnect")
 		// Setting "Requeue: true" along with RequeueAfter makes the controller bulletproof, as
 		// if the syncPeriod is set to zero, the controller won't requeue.
-		return nil, ctrl.Result{Requeue: true, RequeueAfter: r.SyncPeriod}, nil
+		return nil, ctrl.Result{Requeue: true, RequeueAfter: r.syncPeriod()}, nil
 	}
 
 	// set the controlPlaneRefValidCond to true in case the Control Plane is found in Konnect
@@ -8,6 +8,9 @@ import (
 
 	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,8 +18,10 @@ import (
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
 	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
@@ -59,6 +64,9 @@ func Create[
 		statusCode int
 	)
 
+	ctx, span := startOpSpan(ctx, sdk, CreateOp, e)
+	defer func() { endOpSpan(span, e, statusCode, err) }()
+
 	switch ent := any(e).(type) {
 	case *konnectv1alpha1.KonnectGatewayControlPlane:
 		err = createControlPlane(ctx, sdk.GetControlPlaneSDK(), sdk.GetControlPlaneGroupSDK(), cl, ent)
@@ -216,6 +224,7 @@ func Create[
 		)
 	}
 	logOpComplete(ctx, start, CreateOp, e, err)
+	recordOpEvent(ctx, CreateOp, e, err)
 
 	return e, IgnoreUnrecoverableAPIErr(err, loggerForEntity(ctx, e, CreateOp))
 }
@@ -244,6 +253,10 @@ func Delete[
 		entityType = ent.GetTypeName()
 		statusCode int
 	)
+
+	ctx, span := startOpSpan(ctx, sdk, DeleteOp, ent)
+	defer func() { endOpSpan(span, ent, statusCode, err) }()
+
 	switch ent := any(ent).(type) {
 	case *konnectv1alpha1.KonnectGatewayControlPlane:
 		err = deleteControlPlane(ctx, sdk.GetControlPlaneSDK(), ent)
@@ -316,6 +329,7 @@ func Delete[
 		)
 	}
 	logOpComplete(ctx, start, DeleteOp, ent, err)
+	recordOpEvent(ctx, DeleteOp, ent, err)
 
 	// Clear the instance field from the error to avoid requeueing the resource
 	// because of the trace ID in the instance field is different for each request.
@@ -393,7 +407,13 @@ func Update[
 		entityType = e.GetTypeName()
 		statusCode int
 		start      = time.Now()
+		// Checked before the update sets the Programmed condition.
+		changed = !isProgrammedAtCurrentGeneration(e)
 	)
+
+	ctx, span := startOpSpan(ctx, sdk, UpdateOp, e)
+	defer func() { endOpSpan(span, e, statusCode, err) }()
+
 	switch ent := any(e).(type) {
 	case *konnectv1alpha1.KonnectGatewayControlPlane:
 		err = updateControlPlane(ctx, sdk.GetControlPlaneSDK(), sdk.GetControlPlaneGroupSDK(), cl, ent)
@@ -481,10 +501,50 @@ func Update[
 		)
 	}
 	logOpComplete(ctx, start, UpdateOp, e, err)
+	// Periodic resyncs of entities which haven't changed since they were
+	// programmed are not reported, only failures are.
+	if err != nil || changed {
+		recordOpEvent(ctx, UpdateOp, e, err)
+	}
 
 	return ctrl.Result{}, IgnoreUnrecoverableAPIErr(err, loggerForEntity(ctx, e, UpdateOp))
 }
 
+// startOpSpan starts a span for the operation on the Konnect entity.
+// The span is a child of the span from the provided context. The returned
+// context carries the span and is meant to be passed to the Konnect SDK so
+// that its HTTP requests are traced as children of the span.
+func startOpSpan[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](ctx context.Context, sdk sdkops.SDKWrapper, op Op, e TEnt) (context.Context, trace.Span) {
+	ctx, span := tracing.Tracer(ctx).Start(ctx, "konnect "+string(op)+" "+e.GetTypeName(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String(tracing.KonnectEntityTypeKey, e.GetTypeName()),
+			attribute.String(tracing.KonnectServerURLKey, sdk.GetServerURL()),
+			attribute.String(tracing.NamespaceKey, e.GetNamespace()),
+			attribute.String(tracing.NameKey, e.GetName()),
+		),
+	)
+	return ctx, span
+}
+
+// endOpSpan records the Konnect ID of the entity and the HTTP status code
+// of the failed operation, if known, in the span and ends it.
+func endOpSpan[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](span trace.Span, e TEnt, statusCode int, err error) {
+	if id := e.GetKonnectStatus().GetKonnectID(); id != "" {
+		span.SetAttributes(attribute.String(tracing.KonnectIDKey, id))
+	}
+	if statusCode != 0 {
+		span.SetAttributes(attribute.Int(tracing.HTTPStatusCodeKey, statusCode))
+	}
+	tracing.EndSpan(span, err)
+}
+
 func loggerForEntity[
 	T constraints.SupportedKonnectEntityType,
 	TEnt constraints.EntityType[T],
@@ -517,6 +577,46 @@ func logOpComplete[
 	logger.Info("operation in Konnect API complete")
 }
 
+// recordOpEvent emits an Event for the entity with the outcome of the operation
+// in Konnect API, using the recorder from the context.
+// isProgrammedAtCurrentGeneration returns true if the entity has been
+// successfully programmed in Konnect at its current generation.
+func isProgrammedAtCurrentGeneration[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](e TEnt) bool {
+	cond, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityProgrammedConditionType, e)
+	return ok &&
+		cond.Status == metav1.ConditionTrue &&
+		cond.ObservedGeneration == e.GetGeneration()
+}
+
+func recordOpEvent[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](ctx context.Context, op Op, e TEnt, err error) {
+	var reason, failedReason, done string
+	switch op {
+	case CreateOp:
+		reason, failedReason, done = events.ReasonKonnectEntityCreated, events.ReasonKonnectEntityCreateFailed, "Created"
+	case UpdateOp:
+		reason, failedReason, done = events.ReasonKonnectEntityUpdated, events.ReasonKonnectEntityUpdateFailed, "Updated"
+	case DeleteOp:
+		reason, failedReason, done = events.ReasonKonnectEntityDeleted, events.ReasonKonnectEntityDeleteFailed, "Deleted"
+	default:
+		return
+	}
+
+	recorder := events.FromContext(ctx)
+	if err != nil {
+		// The instance field contains a trace ID which differs between requests
+		// and would prevent repeated failures from being rate limited.
+		recorder.Eventf(e, corev1.EventTypeWarning, failedReason, "Failed to %s entity in Konnect: %v", op, clearInstanceFromError(err))
+		return
+	}
+	recorder.Eventf(e, corev1.EventTypeNormal, reason, "%s entity in Konnect with ID %s", done, e.GetKonnectStatus().GetKonnectID())
+}
+
 // wrapErrIfKonnectOpFailed checks the response from the Konnect API and returns a uniform
 // error for all Konnect entities if the operation failed.
 func wrapErrIfKonnectOpFailed[
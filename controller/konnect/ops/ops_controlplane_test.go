@@ -610,7 +610,7 @@ func TestCreateAndUpdateControlPlane_KubernetesMetadataConsistency(t *testing.T)
 		"k8s-generation": "2",
 	}
 	sdkControlPlanes.EXPECT().
-		CreateControlPlane(ctx, sdkkonnectcomp.CreateControlPlaneRequest{
+		CreateControlPlane(mock.Anything, sdkkonnectcomp.CreateControlPlaneRequest{
 			Name:   "cp-1",
 			Labels: expectedLabels,
 		}).
@@ -624,7 +624,7 @@ func TestCreateAndUpdateControlPlane_KubernetesMetadataConsistency(t *testing.T)
 
 	t.Log("Triggering UpdateControlPlane with expected labels")
 	sdkControlPlanes.EXPECT().
-		UpdateControlPlane(ctx, "12345", sdkkonnectcomp.UpdateControlPlaneRequest{
+		UpdateControlPlane(mock.Anything, "12345", sdkkonnectcomp.UpdateControlPlaneRequest{
 			Name:   lo.ToPtr("cp-1"),
 			Labels: expectedLabels,
 		}).
@@ -11,12 +11,20 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
 	sdkmocks "github.com/kong/gateway-operator/controller/konnect/ops/sdk/mocks"
+	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
 	"github.com/kong/gateway-operator/modules/manager/scheme"
 
 	kcfgkonnect "github.com/kong/kubernetes-configuration/api/konnect"
@@ -220,6 +228,124 @@ func TestCreate(t *testing.T) {
 	testCreate(t, testCasesForKonnectGatewayControlPlane)
 }
 
+func TestCreateRecordsSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	injector := ctxinjector.NewCtxInjector(
+		tracing.TracerProviderInjector(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+	ctx := injector.InjectKeyValues(t.Context())
+
+	cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cp",
+			Namespace: "test-ns",
+		},
+		Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+			CreateControlPlaneRequest: sdkkonnectcomp.CreateControlPlaneRequest{
+				Name: "test-cp",
+			},
+		},
+	}
+	sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+	sdk.ControlPlaneSDK.
+		EXPECT().
+		CreateControlPlane(mock.Anything, mock.Anything).
+		Return(nil, &sdkkonnecterrs.SDKError{StatusCode: http.StatusInternalServerError, Message: "internal error"}).
+		Once()
+	fakeClient := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+
+	_, err := Create(ctx, sdk, fakeClient, &metrics.MockRecorder{}, cp)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "konnect create KonnectGatewayControlPlane", span.Name())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), attribute.String(tracing.KonnectEntityTypeKey, "KonnectGatewayControlPlane"))
+	assert.Contains(t, span.Attributes(), attribute.String(tracing.NameKey, "test-cp"))
+	assert.Contains(t, span.Attributes(), attribute.Int(tracing.HTTPStatusCodeKey, http.StatusInternalServerError))
+}
+
+func TestUpdateRecordsEventsOnlyForChanges(t *testing.T) {
+	programmed := func(generation int64) metav1.Condition {
+		return metav1.Condition{
+			Type:               konnectv1alpha1.KonnectEntityProgrammedConditionType,
+			Status:             metav1.ConditionTrue,
+			Reason:             konnectv1alpha1.KonnectEntityProgrammedReasonProgrammed,
+			ObservedGeneration: generation,
+		}
+	}
+	testCases := []struct {
+		name          string
+		conditions    []metav1.Condition
+		updateErr     error
+		expectedEvent string
+	}{
+		{
+			name:          "changed entity",
+			conditions:    []metav1.Condition{programmed(1)},
+			expectedEvent: "Normal KonnectEntityUpdated Updated entity in Konnect with ID cp-id",
+		},
+		{
+			name:          "entity which hasn't been programmed yet",
+			expectedEvent: "Normal KonnectEntityUpdated Updated entity in Konnect with ID cp-id",
+		},
+		{
+			name:       "resync of unchanged entity",
+			conditions: []metav1.Condition{programmed(2)},
+		},
+		{
+			name:          "failed resync of unchanged entity",
+			conditions:    []metav1.Condition{programmed(2)},
+			updateErr:     &sdkkonnecterrs.SDKError{StatusCode: http.StatusInternalServerError, Message: "internal error"},
+			expectedEvent: "Warning KonnectEntityUpdateFailed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cp := &konnectv1alpha1.KonnectGatewayControlPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-cp",
+					Namespace:  "test-ns",
+					Generation: 2,
+				},
+				Spec: konnectv1alpha1.KonnectGatewayControlPlaneSpec{
+					CreateControlPlaneRequest: sdkkonnectcomp.CreateControlPlaneRequest{
+						Name: "test-cp",
+					},
+				},
+				Status: konnectv1alpha1.KonnectGatewayControlPlaneStatus{
+					KonnectEntityStatus: konnectv1alpha1.KonnectEntityStatus{ID: "cp-id"},
+					Conditions:          tc.conditions,
+				},
+			}
+			sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+			call := sdk.ControlPlaneSDK.EXPECT().UpdateControlPlane(mock.Anything, "cp-id", mock.Anything)
+			if tc.updateErr != nil {
+				call.Return(nil, tc.updateErr)
+			} else {
+				call.Return(&sdkkonnectops.UpdateControlPlaneResponse{
+					ControlPlane: &sdkkonnectcomp.ControlPlane{ID: "cp-id"},
+				}, nil)
+			}
+			fakeClient := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+			recorder := record.NewFakeRecorder(10)
+			ctx := events.IntoContext(t.Context(), recorder)
+
+			_, _ = Update(ctx, sdk, 0, fakeClient, &metrics.MockRecorder{}, cp)
+
+			if tc.expectedEvent == "" {
+				require.Empty(t, recorder.Events)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			require.Contains(t, <-recorder.Events, tc.expectedEvent)
+		})
+	}
+}
+
 func testCreate[
 	T constraints.SupportedKonnectEntityType,
 	TEnt constraints.EntityType[T],
@@ -1,8 +1,12 @@
 package sdk
 
 import (
+	"net/http"
+	"time"
+
 	sdkkonnectgo "github.com/Kong/sdk-konnect-go"
 	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // SDKWrapper is a wrapper of Konnect SDK to allow using mock SDKs in tests.
@@ -177,7 +181,12 @@ func NewSDKFactory() SDKFactory {
 	return sdkFactory{}
 }
 
+// konnectSDKTimeout is the timeout of the HTTP client used by the Konnect SDK.
+// It matches the SDK's default.
+const konnectSDKTimeout = 60 * time.Second
+
 // NewKonnectSDK creates a new Konnect SDK.
+// Its HTTP requests are traced as children of the span from their context.
 func (f sdkFactory) NewKonnectSDK(serverURL string, token SDKToken) SDKWrapper {
 	return sdkWrapper{
 		serverURL: serverURL,
@@ -188,6 +197,10 @@ func (f sdkFactory) NewKonnectSDK(serverURL string, token SDKToken) SDKWrapper {
 				},
 			),
 			sdkkonnectgo.WithServerURL(serverURL),
+			sdkkonnectgo.WithClient(&http.Client{
+				Timeout:   konnectSDKTimeout,
+				Transport: otelhttp.NewTransport(http.DefaultTransport),
+			}),
 		),
 	}
 }
@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,8 +22,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/tracing"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/clientops"
 	k8sreduce "github.com/kong/gateway-operator/pkg/utils/kubernetes/reduce"
 
@@ -72,6 +76,10 @@ type KongCredentialSecretReconciler struct {
 	developmentMode bool
 	client          client.Client
 	scheme          *runtime.Scheme
+
+	// EventRecorder records the Events emitted for the reconciled objects, e.g.
+	// when duplicated credentials are reduced.
+	EventRecorder record.EventRecorder
 }
 
 // NewKongCredentialSecretReconciler creates a new KongCredentialSecretReconciler.
@@ -88,7 +96,7 @@ func NewKongCredentialSecretReconciler(
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *KongCredentialSecretReconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager) error {
+func (r *KongCredentialSecretReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	ls := metav1.LabelSelector{
 		MatchExpressions: []metav1.LabelSelectorRequirement{
 			{
@@ -131,7 +139,7 @@ func (r *KongCredentialSecretReconciler) SetupWithManager(_ context.Context, mgr
 		Owns(&configurationv1alpha1.KongCredentialACL{}, builder.MatchEveryOwner).
 		Owns(&configurationv1alpha1.KongCredentialJWT{}, builder.MatchEveryOwner).
 		Owns(&configurationv1alpha1.KongCredentialHMAC{}, builder.MatchEveryOwner).
-		Complete(r)
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("KongCredentialSecret", r)))
 }
 
 func enqueueSecretsForKongConsumer(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -190,6 +198,7 @@ func secretIsUsedByConsumerAttachedToKonnectControlPlane(cl client.Client) func(
 
 // Reconcile reconciles a Secrets that are used as Consumers credentials.
 func (r *KongCredentialSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	var secret corev1.Secret
 	if err := r.client.Get(ctx, req.NamespacedName, &secret); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
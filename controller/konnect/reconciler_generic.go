@@ -9,6 +9,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -18,10 +19,14 @@ import (
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
 	"github.com/kong/gateway-operator/controller/konnect/ops"
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/op"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
@@ -46,6 +51,14 @@ type KonnectEntityReconciler[T constraints.SupportedKonnectEntityType, TEnt cons
 	MaxConcurrentReconciles uint
 
 	MetricRecoder metrics.Recorder
+	EventRecorder record.EventRecorder
+
+	// syncPeriodFunc, when set, takes precedence over SyncPeriod and allows
+	// the sync period to be changed without restarting the operator.
+	syncPeriodFunc func() time.Duration
+	// concurrencyLimiter, when set, limits the number of concurrent reconciles
+	// below MaxConcurrentReconciles without restarting the operator.
+	concurrencyLimiter *concurrencyLimiter
 }
 
 // KonnectEntityReconcilerOption is a functional option for the KonnectEntityReconciler.
@@ -72,6 +85,28 @@ func WithKonnectMaxConcurrentReconciles[T constraints.SupportedKonnectEntityType
 	}
 }
 
+// WithKonnectEntitySyncPeriodFunc sets the function used to get the sync period
+// for the reconciler. It allows the sync period to be changed at runtime.
+func WithKonnectEntitySyncPeriodFunc[T constraints.SupportedKonnectEntityType, TEnt constraints.EntityType[T]](
+	f func() time.Duration,
+) KonnectEntityReconcilerOption[T, TEnt] {
+	return func(r *KonnectEntityReconciler[T, TEnt]) {
+		r.syncPeriodFunc = f
+	}
+}
+
+// WithKonnectMaxConcurrentReconcilesFunc sets the function used to get the max
+// concurrent reconciles for the reconciler. It allows the limit to be changed at
+// runtime, though it cannot exceed the MaxConcurrentReconciles the controller
+// was started with.
+func WithKonnectMaxConcurrentReconcilesFunc[T constraints.SupportedKonnectEntityType, TEnt constraints.EntityType[T]](
+	f func() uint,
+) KonnectEntityReconcilerOption[T, TEnt] {
+	return func(r *KonnectEntityReconciler[T, TEnt]) {
+		r.concurrencyLimiter = newConcurrencyLimiter(f)
+	}
+}
+
 // WithMetricRecoder sets the metric recorder to record metrics of Konnect entity operations of the reconciler.
 func WithMetricRecorder[T constraints.SupportedKonnectEntityType, TEnt constraints.EntityType[T]](
 	metricRecorder metrics.Recorder,
@@ -81,6 +116,16 @@ func WithMetricRecorder[T constraints.SupportedKonnectEntityType, TEnt constrain
 	}
 }
 
+// WithEventRecorder sets the recorder of the Events emitted for the reconciled
+// entities, e.g. when they're created in Konnect.
+func WithEventRecorder[T constraints.SupportedKonnectEntityType, TEnt constraints.EntityType[T]](
+	eventRecorder record.EventRecorder,
+) KonnectEntityReconcilerOption[T, TEnt] {
+	return func(r *KonnectEntityReconciler[T, TEnt]) {
+		r.EventRecorder = eventRecorder
+	}
+}
+
 // NewKonnectEntityReconciler returns a new KonnectEntityReconciler for the given
 // Konnect entity type.
 func NewKonnectEntityReconciler[
@@ -125,7 +170,9 @@ func (r *KonnectEntityReconciler[T, TEnt]) SetupWithManager(ctx context.Context,
 	for _, dep := range ReconciliationWatchOptionsForEntity(r.Client, ent) {
 		b = dep(b)
 	}
-	return b.Complete(r)
+	return b.Complete(sharding.NewReconciler(ctx, tracing.NewReconciler(entityTypeName,
+		pause.NewReconciler(r.Client, entityTypeName, pause.ConditionsAware[TEnt], r),
+	)))
 }
 
 // Reconcile reconciles the given Konnect entity.
@@ -137,6 +184,13 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 		logger         = log.GetLogger(ctx, entityTypeName, r.DevelopmentMode)
 	)
 
+	if r.concurrencyLimiter != nil {
+		if err := r.concurrencyLimiter.acquire(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+		defer r.concurrencyLimiter.release()
+	}
+
 	var (
 		e   T
 		ent = TEnt(&e)
@@ -149,6 +203,7 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 		logger = logger.WithValues("konnect_id", id)
 	}
 	ctx = ctrllog.IntoContext(ctx, logger)
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	log.Debug(logger, "reconciling")
 
 	// If a type has a ControlPlane ref, handle it.
@@ -453,7 +508,7 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 		return ctrl.Result{}, nil
 	}
 
-	res, err = ops.Update[T, TEnt](ctx, sdk, r.SyncPeriod, r.Client, r.MetricRecoder, ent)
+	res, err = ops.Update[T, TEnt](ctx, sdk, r.syncPeriod(), r.Client, r.MetricRecoder, ent)
 	// Set the server URL and org ID regardless of the error.
 	setStatusServerURLAndOrgID(ent, serverURL, apiAuth.Status.OrganizationID)
 	// Update the status of the object regardless of the error.
@@ -481,7 +536,7 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 	// Konnect does not allow subscribing to changes so we need to keep pushing the
 	// desired state periodically.
 	return ctrl.Result{
-		RequeueAfter: r.SyncPeriod,
+		RequeueAfter: r.syncPeriod(),
 	}, nil
 }
 
@@ -555,3 +610,10 @@ func setProgrammedStatusConditionBasedOnOtherConditions[
 	}
 	return ctrl.Result{}, nil
 }
+
+func (r *KonnectEntityReconciler[T, TEnt]) syncPeriod() time.Duration {
+	if r.syncPeriodFunc != nil {
+		return r.syncPeriodFunc()
+	}
+	return r.SyncPeriod
+}
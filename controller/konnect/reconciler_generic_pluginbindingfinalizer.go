@@ -17,6 +17,8 @@ import (
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/internal/tracing"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/clientops"
 	"github.com/kong/gateway-operator/pkg/consts"
 
@@ -62,7 +64,7 @@ func (r *KonnectEntityPluginBindingFinalizerReconciler[T, TEnt]) SetupWithManage
 
 	r.setControllerBuilderOptionsForKongPluginBinding(b)
 
-	return b.Complete(r)
+	return b.Complete(sharding.NewReconciler(ctx, tracing.NewReconciler(entityTypeName+"PluginBindingCleanupFinalizer", r)))
 }
 
 // enqueueObjectReferencedByKongPluginBinding watches for KongPluginBinding objects
@@ -12,6 +12,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -19,8 +20,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/internal/tracing"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sreduce "github.com/kong/gateway-operator/pkg/utils/kubernetes/reduce"
 
@@ -35,6 +39,10 @@ import (
 type KongPluginReconciler struct {
 	developmentMode bool
 	client          client.Client
+
+	// EventRecorder records the Events emitted for the reconciled objects, e.g.
+	// when duplicated KongPluginBindings are reduced.
+	EventRecorder record.EventRecorder
 }
 
 // NewKongPluginReconciler creates a new KongPluginReconciler.
@@ -49,7 +57,7 @@ func NewKongPluginReconciler(
 }
 
 // SetupWithManager sets up the controller with the Manager.
-func (r *KongPluginReconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager) error {
+func (r *KongPluginReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("KongPlugin").
 		For(&configurationv1.KongPlugin{}).
@@ -89,13 +97,14 @@ func (r *KongPluginReconciler) SetupWithManager(_ context.Context, mgr ctrl.Mana
 				predicate.NewPredicateFuncs(objRefersToKonnectGatewayControlPlane[configurationv1beta1.KongConsumerGroup]),
 			),
 		).
-		Complete(r)
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("KongPlugin", r)))
 }
 
 // Reconcile reconciles a KongPlugin object.
 // The purpose of this reconciler is to handle annotations on Kong entities objects that reference KongPlugin objects.
 // As a result of such annotations, KongPluginBinding objects are created and managed by the controller.
 func (r *KongPluginReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	var (
 		entityTypeName = "KongPlugin"
 		logger         = log.GetLogger(ctx, entityTypeName, r.developmentMode)
@@ -21,6 +21,8 @@ import (
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/internal/tracing"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
 	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
@@ -81,7 +83,7 @@ func (r *KonnectAPIAuthConfigurationReconciler) SetupWithManager(ctx context.Con
 		).
 		Named("KonnectAPIAuthConfiguration")
 
-	return b.Complete(r)
+	return b.Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("KonnectAPIAuthConfiguration", r)))
 }
 
 // Reconcile reconciles a KonnectAPIAuthConfiguration object.
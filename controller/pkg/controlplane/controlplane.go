@@ -26,6 +26,11 @@ type DefaultsArgs struct {
 	DataPlaneAdminServiceName   string
 	OwnedByGateway              string
 	AnonymousReportsEnabled     bool
+	// WatchNamespace restricts the ControlPlane to watch only the provided
+	// namespace. It is set when the operator runs in namespace-scoped mode and
+	// it also disables the admission webhook by default, as it requires
+	// a cluster-scoped ValidatingWebhookConfiguration.
+	WatchNamespace string
 }
 
 // -----------------------------------------------------------------------------
@@ -192,13 +197,27 @@ func SetDefaults(
 	}
 
 	const controllerAdmissionWebhookListen = "CONTROLLER_ADMISSION_WEBHOOK_LISTEN"
+	admissionWebhookListen := consts.ControlPlaneAdmissionWebhookEnvVarValue
+	if args.WatchNamespace != "" {
+		admissionWebhookListen = "off"
+	}
 	if _, isOverrideDisabled := dontOverride[controllerAdmissionWebhookListen]; !isOverrideDisabled {
-		if k8sutils.EnvValueByName(container.Env, controllerAdmissionWebhookListen) != consts.ControlPlaneAdmissionWebhookEnvVarValue {
-			container.Env = k8sutils.UpdateEnv(container.Env, controllerAdmissionWebhookListen, consts.ControlPlaneAdmissionWebhookEnvVarValue)
+		if k8sutils.EnvValueByName(container.Env, controllerAdmissionWebhookListen) != admissionWebhookListen {
+			container.Env = k8sutils.UpdateEnv(container.Env, controllerAdmissionWebhookListen, admissionWebhookListen)
 			changed = true
 		}
 	}
 
+	if args.WatchNamespace != "" {
+		const controllerWatchNamespaceEnvVarName = "CONTROLLER_WATCH_NAMESPACE"
+		if _, isOverrideDisabled := dontOverride[controllerWatchNamespaceEnvVarName]; !isOverrideDisabled {
+			if k8sutils.EnvValueByName(container.Env, controllerWatchNamespaceEnvVarName) != args.WatchNamespace {
+				container.Env = k8sutils.UpdateEnv(container.Env, controllerWatchNamespaceEnvVarName, args.WatchNamespace)
+				changed = true
+			}
+		}
+	}
+
 	k8sutils.SetPodContainer(podSpec, container)
 
 	return changed
@@ -7,6 +7,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
 )
@@ -110,3 +111,61 @@ func TestDeduceAnonymousReportsEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDefaultsWatchNamespace(t *testing.T) {
+	tests := []struct {
+		name                  string
+		env                   []corev1.EnvVar
+		watchNamespace        string
+		expectedWebhookListen string
+		expectedWatchNs       string
+	}{
+		{
+			name:                  "cluster-wide mode",
+			expectedWebhookListen: consts.ControlPlaneAdmissionWebhookEnvVarValue,
+		},
+		{
+			name:                  "namespace-scoped mode",
+			watchNamespace:        "ns",
+			expectedWebhookListen: "off",
+			expectedWatchNs:       "ns",
+		},
+		{
+			name:           "namespace-scoped mode with user provided values",
+			watchNamespace: "ns",
+			env: []corev1.EnvVar{
+				{Name: "CONTROLLER_ADMISSION_WEBHOOK_LISTEN", Value: "0.0.0.0:9090"},
+				{Name: "CONTROLLER_WATCH_NAMESPACE", Value: "ns,other"},
+			},
+			expectedWebhookListen: "0.0.0.0:9090",
+			expectedWatchNs:       "ns,other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &operatorv1beta1.ControlPlaneOptions{
+				Deployment: operatorv1beta1.ControlPlaneDeploymentOptions{
+					PodTemplateSpec: &corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: consts.ControlPlaneControllerContainerName,
+									Env:  tt.env,
+								},
+							},
+						},
+					},
+				},
+			}
+			SetDefaults(opts, DefaultsArgs{
+				Namespace:        "ns",
+				ControlPlaneName: "cp",
+				WatchNamespace:   tt.watchNamespace,
+			})
+
+			container := opts.Deployment.PodTemplateSpec.Spec.Containers[0]
+			require.Equal(t, tt.expectedWebhookListen, k8sutils.EnvValueByName(container.Env, "CONTROLLER_ADMISSION_WEBHOOK_LISTEN"))
+			require.Equal(t, tt.expectedWatchNs, k8sutils.EnvValueByName(container.Env, "CONTROLLER_WATCH_NAMESPACE"))
+		})
+	}
+}
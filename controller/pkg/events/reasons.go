@@ -0,0 +1,73 @@
+package events
+
+// Reasons of the Events emitted by the operator's controllers. They're part of
+// the operator's interface, as users filter Events with them, so they should
+// not be changed once released.
+const (
+	// ReasonPromotionStarted is the reason of the Event emitted when a DataPlane's
+	// preview resources start being promoted during a BlueGreen rollout.
+	ReasonPromotionStarted = "PromotionStarted"
+	// ReasonPromoted is the reason of the Event emitted when a DataPlane's preview
+	// resources have been promoted to live during a BlueGreen rollout.
+	ReasonPromoted = "Promoted"
+	// ReasonPromotionFailed is the reason of the Event emitted when a DataPlane's
+	// preview resources fail to be promoted during a BlueGreen rollout.
+	ReasonPromotionFailed = "PromotionFailed"
+	// ReasonRolloutFailed is the reason of the Event emitted when a DataPlane's
+	// preview resources fail to be deployed during a BlueGreen rollout.
+	ReasonRolloutFailed = "RolloutFailed"
+	// ReasonRolledBack is the reason of the Event emitted when a DataPlane's
+	// preview resources are removed, because the BlueGreen rollout strategy has
+	// been removed from its spec, leaving only the live resources.
+	ReasonRolledBack = "RolledBack"
+
+	// ReasonDuplicatesReduced is the reason of the Event emitted when duplicates
+	// of a resource managed for an object have been deleted.
+	ReasonDuplicatesReduced = "DuplicatesReduced"
+
+	// ReasonCertificateIssued is the reason of the Event emitted when a
+	// certificate signed by the cluster CA has been issued for an object.
+	ReasonCertificateIssued = "CertificateIssued"
+	// ReasonCertificateReissued is the reason of the Event emitted when an
+	// object's invalid or outdated certificate has been replaced with a new one.
+	ReasonCertificateReissued = "CertificateReissued"
+
+	// ReasonExtensionApplied is the reason of the Event emitted when the extensions
+	// referenced by an object have been applied.
+	ReasonExtensionApplied = "ExtensionApplied"
+	// ReasonExtensionFailed is the reason of the Event emitted when the extensions
+	// referenced by an object are invalid or fail to be applied.
+	ReasonExtensionFailed = "ExtensionFailed"
+
+	// ReasonKonnectEntityCreated is the reason of the Event emitted when an
+	// object's entity has been created in Konnect.
+	ReasonKonnectEntityCreated = "KonnectEntityCreated"
+	// ReasonKonnectEntityUpdated is the reason of the Event emitted when an
+	// object's entity has been updated in Konnect.
+	ReasonKonnectEntityUpdated = "KonnectEntityUpdated"
+	// ReasonKonnectEntityDeleted is the reason of the Event emitted when an
+	// object's entity has been deleted from Konnect.
+	ReasonKonnectEntityDeleted = "KonnectEntityDeleted"
+	// ReasonKonnectEntityCreateFailed is the reason of the Event emitted when
+	// an object's entity fails to be created in Konnect.
+	ReasonKonnectEntityCreateFailed = "KonnectEntityCreateFailed"
+	// ReasonKonnectEntityUpdateFailed is the reason of the Event emitted when
+	// an object's entity fails to be updated in Konnect.
+	ReasonKonnectEntityUpdateFailed = "KonnectEntityUpdateFailed"
+	// ReasonKonnectEntityDeleteFailed is the reason of the Event emitted when
+	// an object's entity fails to be deleted from Konnect.
+	ReasonKonnectEntityDeleteFailed = "KonnectEntityDeleteFailed"
+
+	// ReasonPluginUpdated is the reason of the Event emitted when the plugin
+	// installed by a KongPluginInstallation has been updated to the new digest
+	// its image's tag points to.
+	ReasonPluginUpdated = "PluginUpdated"
+	// ReasonPluginUpdateAvailable is the reason of the Event emitted when the
+	// tag of a KongPluginInstallation's image points to a new digest which
+	// waits to be approved.
+	ReasonPluginUpdateAvailable = "PluginUpdateAvailable"
+	// ReasonPluginDigestRejected is the reason of the Event emitted when the
+	// signature of the new digest a KongPluginInstallation's image points to
+	// can't be verified and the installed digest is kept.
+	ReasonPluginDigestRejected = "PluginDigestRejected"
+)
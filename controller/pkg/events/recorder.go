@@ -0,0 +1,179 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// DefaultRateLimitInterval is the default interval in which an Event with the
+// same type, reason and message is emitted at most once for an object.
+const DefaultRateLimitInterval = 5 * time.Minute
+
+// maxRateLimitedEvents is the maximum number of recently emitted Events
+// remembered by RateLimitedRecorder. Expired ones are pruned when it's reached
+// and the oldest ones are evicted if that's not enough.
+const maxRateLimitedEvents = 4096
+
+type eventKey struct {
+	object    string
+	eventType string
+	reason    string
+	message   string
+}
+
+// RateLimitedRecorder is a record.EventRecorder which drops the Events emitted
+// for an object with the same type, reason and message as an Event emitted for
+// it within the configured interval. It prevents controllers which reconcile
+// an object periodically, e.g. to sync it with Konnect, from spamming the API
+// server with Events.
+type RateLimitedRecorder struct {
+	recorder record.EventRecorder
+	interval time.Duration
+	now      func() time.Time
+
+	lock    sync.Mutex
+	emitted map[eventKey]time.Time
+}
+
+var _ record.EventRecorder = &RateLimitedRecorder{}
+
+// NewRateLimitedRecorder returns a RateLimitedRecorder emitting the Events
+// with the provided recorder.
+func NewRateLimitedRecorder(recorder record.EventRecorder, interval time.Duration) *RateLimitedRecorder {
+	return &RateLimitedRecorder{
+		recorder: recorder,
+		interval: interval,
+		now:      time.Now,
+		emitted:  make(map[eventKey]time.Time),
+	}
+}
+
+// Event emits an Event unless it has been emitted recently.
+func (r *RateLimitedRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if !r.allow(object, eventtype, reason, message) {
+		return
+	}
+	r.recorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf emits an Event with a formatted message unless it has been emitted recently.
+func (r *RateLimitedRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...any) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf emits an annotated Event with a formatted message unless it
+// has been emitted recently.
+func (r *RateLimitedRecorder) AnnotatedEventf(
+	object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...any,
+) {
+	message := fmt.Sprintf(messageFmt, args...)
+	if !r.allow(object, eventtype, reason, message) {
+		return
+	}
+	r.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+}
+
+func (r *RateLimitedRecorder) allow(object runtime.Object, eventtype, reason, message string) bool {
+	key := eventKey{
+		object:    objectKey(object),
+		eventType: eventtype,
+		reason:    reason,
+		message:   message,
+	}
+	now := r.now()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if last, ok := r.emitted[key]; ok && now.Sub(last) < r.interval {
+		return false
+	}
+	if len(r.emitted) >= maxRateLimitedEvents {
+		for k, last := range r.emitted {
+			if now.Sub(last) >= r.interval {
+				delete(r.emitted, k)
+			}
+		}
+	}
+	for len(r.emitted) >= maxRateLimitedEvents {
+		r.evictOldest()
+	}
+	r.emitted[key] = now
+	return true
+}
+
+// evictOldest removes the least recently emitted Event. An Event evicted
+// before its interval elapses can be emitted again earlier than that.
+func (r *RateLimitedRecorder) evictOldest() {
+	var (
+		oldestKey eventKey
+		oldest    time.Time
+		found     bool
+	)
+	for k, last := range r.emitted {
+		if !found || last.Before(oldest) {
+			oldestKey, oldest, found = k, last, true
+		}
+	}
+	delete(r.emitted, oldestKey)
+}
+
+func objectKey(object runtime.Object) string {
+	if ref, ok := object.(*corev1.ObjectReference); ok {
+		return fmt.Sprintf("%s/%s/%s/%s", ref.Kind, ref.Namespace, ref.Name, ref.UID)
+	}
+	if obj, err := meta.Accessor(object); err == nil {
+		return fmt.Sprintf("%s/%s/%s/%s", object.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName(), obj.GetUID())
+	}
+	return fmt.Sprintf("%p", object)
+}
+
+// OwnerReference returns the reference of the controller owning the provided
+// object, which can be passed to a record.EventRecorder to emit an Event for
+// the owner. It returns nil if the object has no controller owner.
+func OwnerReference(obj metav1.Object) *corev1.ObjectReference {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: owner.APIVersion,
+		Kind:       owner.Kind,
+		Name:       owner.Name,
+		UID:        owner.UID,
+		// Namespaced objects can only be owned by objects from the same namespace.
+		Namespace: obj.GetNamespace(),
+	}
+}
+
+type recorderKey struct{}
+
+// IntoContext returns a copy of the context with the provided recorder, so that
+// the functions called during reconciliation can emit Events with it.
+func IntoContext(ctx context.Context, recorder record.EventRecorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// FromContext returns the recorder stored in the context with IntoContext.
+// It returns a recorder discarding the Events if there's none.
+func FromContext(ctx context.Context) record.EventRecorder {
+	if recorder, ok := ctx.Value(recorderKey{}).(record.EventRecorder); ok && recorder != nil {
+		return recorder
+	}
+	return discardRecorder{}
+}
+
+type discardRecorder struct{}
+
+func (discardRecorder) Event(runtime.Object, string, string, string)          {}
+func (discardRecorder) Eventf(runtime.Object, string, string, string, ...any) {}
+func (discardRecorder) AnnotatedEventf(runtime.Object, map[string]string, string, string, string, ...any) {
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestRateLimitedRecorder(t *testing.T) {
+	fake := record.NewFakeRecorder(10)
+	recorder := NewRateLimitedRecorder(fake, time.Minute)
+	now := time.Now()
+	recorder.now = func() time.Time { return now }
+
+	dp1 := &operatorv1beta1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dp-1", UID: "uid-1"}}
+	dp2 := &operatorv1beta1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dp-2", UID: "uid-2"}}
+
+	recorder.Eventf(dp1, corev1.EventTypeNormal, ReasonPromoted, "promoted %s", "dp-1")
+	recorder.Eventf(dp1, corev1.EventTypeNormal, ReasonPromoted, "promoted %s", "dp-1")
+	require.Equal(t, "Normal Promoted promoted dp-1", <-fake.Events)
+	require.Empty(t, fake.Events, "repeated Event is dropped")
+
+	t.Log("Events with a different object, reason or message are emitted")
+	recorder.Event(dp2, corev1.EventTypeNormal, ReasonPromoted, "promoted dp-1")
+	recorder.Event(dp1, corev1.EventTypeWarning, ReasonPromotionFailed, "promoted dp-1")
+	recorder.Event(dp1, corev1.EventTypeNormal, ReasonPromoted, "promoted again")
+	require.Len(t, fake.Events, 3)
+	for range 3 {
+		<-fake.Events
+	}
+
+	t.Log("repeated Event is emitted after the interval")
+	now = now.Add(time.Minute)
+	recorder.Eventf(dp1, corev1.EventTypeNormal, ReasonPromoted, "promoted %s", "dp-1")
+	require.Equal(t, "Normal Promoted promoted dp-1", <-fake.Events)
+}
+
+func TestRateLimitedRecorderIsBounded(t *testing.T) {
+	recorder := NewRateLimitedRecorder(&record.FakeRecorder{}, time.Hour)
+	now := time.Now()
+	recorder.now = func() time.Time { return now }
+
+	dp := func(i int) *operatorv1beta1.DataPlane {
+		return &operatorv1beta1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: fmt.Sprintf("dp-%d", i)}}
+	}
+	for i := range maxRateLimitedEvents + 10 {
+		now = now.Add(time.Millisecond)
+		recorder.Event(dp(i), corev1.EventTypeNormal, ReasonPromoted, "promoted")
+	}
+	require.Len(t, recorder.emitted, maxRateLimitedEvents, "number of remembered Events has to be bounded")
+
+	t.Log("the least recently emitted Events are evicted")
+	require.True(t, recorder.allow(dp(0), corev1.EventTypeNormal, ReasonPromoted, "promoted"))
+	require.False(t, recorder.allow(dp(maxRateLimitedEvents+9), corev1.EventTypeNormal, ReasonPromoted, "promoted"))
+}
+
+func TestOwnerReference(t *testing.T) {
+	deployment := metav1.ObjectMeta{
+		Namespace: "ns",
+		Name:      "dp-deployment",
+		OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "not-controller", UID: "uid-0"},
+			{
+				APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+				Kind:       "DataPlane",
+				Name:       "dp",
+				UID:        "uid-1",
+				Controller: new(bool),
+			},
+		},
+	}
+	require.Nil(t, OwnerReference(&deployment), "object without a controller owner")
+
+	*deployment.OwnerReferences[1].Controller = true
+	require.Equal(t, &corev1.ObjectReference{
+		APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+		Kind:       "DataPlane",
+		Namespace:  "ns",
+		Name:       "dp",
+		UID:        "uid-1",
+	}, OwnerReference(&deployment))
+}
+
+func TestFromContext(t *testing.T) {
+	require.NotPanics(t, func() {
+		FromContext(context.Background()).Event(&operatorv1beta1.DataPlane{}, corev1.EventTypeNormal, ReasonPromoted, "")
+		FromContext(IntoContext(context.Background(), nil)).Event(&operatorv1beta1.DataPlane{}, corev1.EventTypeNormal, ReasonPromoted, "")
+	}, "Events are discarded when there's no recorder")
+
+	fake := record.NewFakeRecorder(1)
+	FromContext(IntoContext(context.Background(), fake)).Event(&operatorv1beta1.DataPlane{}, corev1.EventTypeNormal, ReasonPromoted, "promoted")
+	require.Equal(t, "Normal Promoted promoted", <-fake.Events)
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	extensionserrors "github.com/kong/gateway-operator/controller/pkg/extensions/errors"
 	"github.com/kong/gateway-operator/controller/pkg/extensions/konnect"
 	"github.com/kong/gateway-operator/controller/pkg/patch"
@@ -50,15 +52,7 @@ func ApplyExtensions[t ExtendableT](ctx context.Context, cl client.Client, o t,
 		return false, ctrl.Result{}, nil
 	}
 
-	if res, err := patch.StatusWithCondition(
-		ctx,
-		cl,
-		o,
-		kcfgconsts.ConditionType(extensionsCondition.Type),
-		extensionsCondition.Status,
-		kcfgconsts.ConditionReason(extensionsCondition.Reason),
-		extensionsCondition.Message,
-	); err != nil || !res.IsZero() {
+	if res, err := patchStatusWithConditionAndEvent(ctx, cl, o, *extensionsCondition); err != nil || !res.IsZero() {
 		return true, res, err
 	}
 	if extensionsCondition.Status == metav1.ConditionFalse {
@@ -113,17 +107,45 @@ func ApplyExtensions[t ExtendableT](ctx context.Context, cl client.Client, o t,
 		}
 	}
 
-	if res, err := patch.StatusWithCondition(
+	if res, err := patchStatusWithConditionAndEvent(ctx, cl, o, konnectExtensionApplied); err != nil || !res.IsZero() {
+		return true, res, err
+	}
+
+	return false, ctrl.Result{}, err
+}
+
+// patchStatusWithConditionAndEvent patches the status of the provided object
+// with the provided extensions' condition. When the condition changes, it emits
+// an Event for the object with the recorder from the context, if the extensions
+// failed or have been applied.
+func patchStatusWithConditionAndEvent[t ExtendableT](ctx context.Context, cl client.Client, o t, condition metav1.Condition) (ctrl.Result, error) {
+	current, ok := k8sutils.GetCondition(kcfgconsts.ConditionType(condition.Type), o)
+	changed := !ok ||
+		current.Status != condition.Status ||
+		current.Reason != condition.Reason ||
+		current.Message != condition.Message
+
+	res, err := patch.StatusWithCondition(
 		ctx,
 		cl,
 		o,
-		kcfgconsts.ConditionType(konnectExtensionApplied.Type),
-		konnectExtensionApplied.Status,
-		kcfgconsts.ConditionReason(konnectExtensionApplied.Reason),
-		konnectExtensionApplied.Message,
-	); err != nil || !res.IsZero() {
-		return true, res, err
+		kcfgconsts.ConditionType(condition.Type),
+		condition.Status,
+		kcfgconsts.ConditionReason(condition.Reason),
+		condition.Message,
+	)
+	if err != nil || !res.IsZero() || !changed {
+		return res, err
 	}
 
-	return false, ctrl.Result{}, err
+	recorder := events.FromContext(ctx)
+	switch {
+	case condition.Status != metav1.ConditionTrue:
+		recorder.Eventf(o, corev1.EventTypeWarning, events.ReasonExtensionFailed, "%s: %s", condition.Type, condition.Message)
+	// Valid extension references are not worth an Event on their own, only
+	// the extensions being applied are.
+	case condition.Type == string(kcfgkonnect.KonnectExtensionAppliedType):
+		recorder.Event(o, corev1.EventTypeNormal, events.ReasonExtensionApplied, condition.Message)
+	}
+	return res, nil
 }
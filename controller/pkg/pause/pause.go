@@ -0,0 +1,227 @@
+package pause
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/patch"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/api/common/consts"
+)
+
+const (
+	// PausedConditionType is the type of the condition set on objects whose
+	// reconciliation is paused with the consts.ReconciliationPausedAnnotation.
+	// It's removed when the reconciliation is resumed.
+	PausedConditionType kcfgconsts.ConditionType = "Paused"
+
+	// PausedReason is the reason of the Paused condition.
+	PausedReason kcfgconsts.ConditionReason = "ReconciliationPaused"
+)
+
+// IsPaused returns true if reconciliation of the provided object is paused
+// with the consts.ReconciliationPausedAnnotation.
+func IsPaused(obj metav1.Object) bool {
+	return obj.GetAnnotations()[consts.ReconciliationPausedAnnotation] == "true"
+}
+
+// ObjectPtr is a pointer to an object which can be reconciled.
+type ObjectPtr[T any] interface {
+	*T
+	client.Object
+}
+
+// ConditionsAwareFunc returns the conditions of the provided object.
+type ConditionsAwareFunc[PT client.Object] func(PT) k8sutils.ConditionsAware
+
+// ConditionsAware is a ConditionsAwareFunc for types which implement
+// k8sutils.ConditionsAware themselves.
+func ConditionsAware[PT interface {
+	client.Object
+	k8sutils.ConditionsAware
+}](obj PT) k8sutils.ConditionsAware {
+	return obj
+}
+
+// NewReconciler wraps the provided reconciler so that it's not called for
+// objects whose reconciliation is paused with the consts.ReconciliationPausedAnnotation.
+// Instead, the Paused condition is set on such objects. The condition is
+// removed when the reconciliation is resumed.
+func NewReconciler[T any, PT ObjectPtr[T]](
+	cl client.Client,
+	kind string,
+	conditionsAware ConditionsAwareFunc[PT],
+	r reconcile.Reconciler,
+	opts ...Option,
+) reconcile.Reconciler {
+	rec := &reconciler[T, PT]{
+		client:          cl,
+		kind:            kind,
+		conditionsAware: conditionsAware,
+		reconciler:      r,
+	}
+	for _, opt := range opts {
+		opt(&rec.options)
+	}
+	return rec
+}
+
+// Option configures the reconciler returned by NewReconciler.
+type Option func(*options)
+
+type options struct {
+	owners []client.Object
+}
+
+// WithPausedOwners makes the reconciler also pause the reconciliation of
+// objects controlled by a paused object of one of the provided types, e.g.
+// of DataPlanes managed for a paused Gateway. See WatchPausedOwners for
+// reconciling such objects when their owner is paused or resumed.
+func WithPausedOwners(owners ...client.Object) Option {
+	return func(o *options) {
+		o.owners = append(o.owners, owners...)
+	}
+}
+
+type reconciler[T any, PT ObjectPtr[T]] struct {
+	options
+
+	client          client.Client
+	kind            string
+	conditionsAware ConditionsAwareFunc[PT]
+	reconciler      reconcile.Reconciler
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *reconciler[T, PT]) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := PT(new(T))
+	if err := r.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		if k8serrors.IsNotFound(err) {
+			metrics.RecordObjectPaused(r.kind, req.NamespacedName, false)
+			return r.reconciler.Reconcile(ctx, req)
+		}
+		return ctrl.Result{}, err
+	}
+
+	paused := IsPaused(obj)
+	message := fmt.Sprintf("Reconciliation is paused with the %s annotation", consts.ReconciliationPausedAnnotation)
+	if !paused {
+		owner, ownerKind, err := r.pausedOwner(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if owner != nil {
+			paused = true
+			message = fmt.Sprintf("Reconciliation is paused with the %s annotation on the owning %s %s",
+				consts.ReconciliationPausedAnnotation, ownerKind, owner.GetName(),
+			)
+		}
+	}
+	metrics.RecordObjectPaused(r.kind, req.NamespacedName, paused)
+
+	logger := ctrllog.FromContext(ctx)
+	old := obj.DeepCopyObject().(PT)
+	conditions := r.conditionsAware(obj)
+	if !paused {
+		if _, ok := k8sutils.GetCondition(PausedConditionType, conditions); ok {
+			removeCondition(conditions, PausedConditionType)
+			if _, err := patch.ApplyStatusPatchIfNotEmpty(ctx, r.client, logger, obj, old); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed removing %s condition: %w", PausedConditionType, err)
+			}
+			log.Info(logger, "reconciliation resumed")
+		}
+		return r.reconciler.Reconcile(ctx, req)
+	}
+
+	log.Debug(logger, "reconciliation paused, skipping", "reason", message)
+	k8sutils.SetCondition(
+		k8sutils.NewConditionWithGeneration(
+			PausedConditionType,
+			metav1.ConditionTrue,
+			PausedReason,
+			message,
+			obj.GetGeneration(),
+		),
+		conditions,
+	)
+	if _, err := patch.ApplyStatusPatchIfNotEmpty(ctx, r.client, logger, obj, old); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed setting %s condition: %w", PausedConditionType, err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// pausedOwner returns the controller owner of the provided object, along with
+// its kind, if it's of one of the types configured with WithPausedOwners and
+// its reconciliation is paused.
+func (r *reconciler[T, PT]) pausedOwner(ctx context.Context, obj client.Object) (client.Object, string, error) {
+	ref := metav1.GetControllerOf(obj)
+	if ref == nil {
+		return nil, "", nil
+	}
+	refGK := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind()
+	for _, o := range r.owners {
+		gvk, err := apiutil.GVKForObject(o, r.client.Scheme())
+		if err != nil {
+			return nil, "", err
+		}
+		if gvk.GroupKind() != refGK {
+			continue
+		}
+
+		owner := o.DeepCopyObject().(client.Object)
+		nn := k8stypes.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}
+		if err := r.client.Get(ctx, nn, owner); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, "", nil
+			}
+			return nil, "", fmt.Errorf("failed getting owning %s %s: %w", gvk.Kind, nn, err)
+		}
+		if owner.GetUID() != ref.UID || !IsPaused(owner) {
+			return nil, "", nil
+		}
+		return owner, gvk.Kind, nil
+	}
+	return nil, "", nil
+}
+
+func removeCondition(resource k8sutils.ConditionsAware, cType kcfgconsts.ConditionType) {
+	conditions := resource.GetConditions()
+	newConditions := make([]metav1.Condition, 0, len(conditions))
+	for _, c := range conditions {
+		if c.Type != string(cType) {
+			newConditions = append(newConditions, c)
+		}
+	}
+	resource.SetConditions(newConditions)
+}
+
+// AnnotationChangedPredicate returns a predicate which passes update events
+// of objects whose reconciliation has been paused or resumed. It's meant to be
+// combined with predicates filtering out updates which don't change objects'
+// generation, as pausing doesn't change it.
+func AnnotationChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return IsPaused(e.ObjectOld) != IsPaused(e.ObjectNew)
+		},
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
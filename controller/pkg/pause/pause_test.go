@@ -0,0 +1,224 @@
+package pause
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestReconciler(t *testing.T) {
+	nn := k8stypes.NamespacedName{Namespace: "ns", Name: "dp"}
+	pausedCondition := k8sutils.NewConditionWithGeneration(PausedConditionType, metav1.ConditionTrue, PausedReason, "", 1)
+
+	testCases := []struct {
+		name                  string
+		dataplane             *operatorv1beta1.DataPlane
+		expectReconcileCall   bool
+		expectPausedCondition bool
+	}{
+		{
+			name:                "not found object is passed to the wrapped reconciler",
+			expectReconcileCall: true,
+		},
+		{
+			name: "object without the annotation is reconciled",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Namespace: nn.Namespace, Name: nn.Name},
+			},
+			expectReconcileCall: true,
+		},
+		{
+			name: "paused object is not reconciled and gets the Paused condition",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   nn.Namespace,
+					Name:        nn.Name,
+					Generation:  1,
+					Annotations: map[string]string{consts.ReconciliationPausedAnnotation: "true"},
+				},
+			},
+			expectPausedCondition: true,
+		},
+		{
+			name: "resumed object is reconciled and its Paused condition is removed",
+			dataplane: &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   nn.Namespace,
+					Name:        nn.Name,
+					Annotations: map[string]string{consts.ReconciliationPausedAnnotation: "false"},
+				},
+				Status: operatorv1beta1.DataPlaneStatus{
+					Conditions: []metav1.Condition{pausedCondition},
+				},
+			},
+			expectReconcileCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithStatusSubresource(&operatorv1beta1.DataPlane{})
+			if tc.dataplane != nil {
+				b = b.WithObjects(tc.dataplane)
+			}
+			cl := b.Build()
+
+			var called bool
+			r := NewReconciler(cl, "DataPlane", ConditionsAware[*operatorv1beta1.DataPlane],
+				reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+					called = true
+					return ctrl.Result{}, nil
+				}),
+			)
+			_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: nn})
+			require.NoError(t, err)
+			require.Equal(t, tc.expectReconcileCall, called)
+
+			if tc.dataplane == nil {
+				return
+			}
+			var dp operatorv1beta1.DataPlane
+			require.NoError(t, cl.Get(t.Context(), nn, &dp))
+			c, ok := k8sutils.GetCondition(PausedConditionType, &dp)
+			require.Equal(t, tc.expectPausedCondition, ok)
+			if ok {
+				require.Equal(t, metav1.ConditionTrue, c.Status)
+				require.Equal(t, string(PausedReason), c.Reason)
+				require.Equal(t, int64(1), c.ObservedGeneration)
+			}
+		})
+	}
+}
+
+func TestReconcilerWithPausedOwners(t *testing.T) {
+	nn := k8stypes.NamespacedName{Namespace: "ns", Name: "dp"}
+	gateway := func(uid k8stypes.UID, annotations map[string]string) *gwtypes.Gateway {
+		return &gwtypes.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   nn.Namespace,
+				Name:        "gw",
+				UID:         uid,
+				Annotations: annotations,
+			},
+		}
+	}
+	paused := map[string]string{consts.ReconciliationPausedAnnotation: "true"}
+
+	testCases := []struct {
+		name                  string
+		gateway               *gwtypes.Gateway
+		expectReconcileCall   bool
+		expectPausedCondition bool
+	}{
+		{
+			name:                "object owned by a Gateway which is not paused is reconciled",
+			gateway:             gateway("gw-uid", nil),
+			expectReconcileCall: true,
+		},
+		{
+			name:                  "object owned by a paused Gateway is not reconciled and gets the Paused condition",
+			gateway:               gateway("gw-uid", paused),
+			expectPausedCondition: true,
+		},
+		{
+			name:                "object whose owner no longer exists is reconciled",
+			expectReconcileCall: true,
+		},
+		{
+			name:                "object whose owner was recreated is reconciled",
+			gateway:             gateway("other-uid", paused),
+			expectReconcileCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: nn.Namespace,
+					Name:      nn.Name,
+				},
+			}
+			require.NoError(t, controllerutil.SetControllerReference(gateway("gw-uid", nil), dp, scheme.Get()))
+			b := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithStatusSubresource(&operatorv1beta1.DataPlane{}).
+				WithObjects(dp)
+			if tc.gateway != nil {
+				b = b.WithObjects(tc.gateway)
+			}
+			cl := b.Build()
+
+			var called bool
+			r := NewReconciler(cl, "DataPlane", ConditionsAware[*operatorv1beta1.DataPlane],
+				reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+					called = true
+					return ctrl.Result{}, nil
+				}),
+				WithPausedOwners(&gwtypes.Gateway{}),
+			)
+			_, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: nn})
+			require.NoError(t, err)
+			require.Equal(t, tc.expectReconcileCall, called)
+
+			require.NoError(t, cl.Get(t.Context(), nn, dp))
+			c, ok := k8sutils.GetCondition(PausedConditionType, dp)
+			require.Equal(t, tc.expectPausedCondition, ok)
+			if ok {
+				require.Contains(t, c.Message, "owning Gateway gw")
+			}
+		})
+	}
+}
+
+func TestListControlledObjects(t *testing.T) {
+	gw := &gwtypes.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "gw", UID: "gw-uid"},
+	}
+	controlled := &operatorv1beta1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "controlled"}}
+	require.NoError(t, controllerutil.SetControllerReference(gw, controlled, scheme.Get()))
+	otherNamespace := controlled.DeepCopy()
+	otherNamespace.Namespace = "other"
+	notControlled := &operatorv1beta1.DataPlane{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "standalone"}}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(controlled, otherNamespace, notControlled).
+		Build()
+
+	requests := listControlledObjects(cl, &operatorv1beta1.DataPlaneList{})(t.Context(), gw)
+	require.Equal(t, []reconcile.Request{
+		{NamespacedName: k8stypes.NamespacedName{Namespace: "ns", Name: "controlled"}},
+	}, requests)
+}
+
+func TestAnnotationChangedPredicate(t *testing.T) {
+	obj := func(annotations map[string]string) client.Object {
+		return &operatorv1beta1.DataPlane{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+	paused := map[string]string{consts.ReconciliationPausedAnnotation: "true"}
+
+	p := AnnotationChangedPredicate()
+	require.True(t, p.Update(event.UpdateEvent{ObjectOld: obj(nil), ObjectNew: obj(paused)}))
+	require.True(t, p.Update(event.UpdateEvent{ObjectOld: obj(paused), ObjectNew: obj(nil)}))
+	require.False(t, p.Update(event.UpdateEvent{ObjectOld: obj(paused), ObjectNew: obj(paused)}))
+	require.False(t, p.Update(event.UpdateEvent{ObjectOld: obj(nil), ObjectNew: obj(map[string]string{"a": "b"})}))
+}
@@ -0,0 +1,70 @@
+package pause
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// WatchPausedOwners makes the controller built with the provided builder
+// reconcile the objects of the provided list type controlled by an object of
+// the owner's type whenever reconciliation of the latter is paused or resumed.
+// It's meant to be used along with WithPausedOwners. Nothing is watched when
+// the owner's type is not served by the cluster, e.g. when Gateway API CRDs
+// are not installed.
+func WatchPausedOwners(mgr ctrl.Manager, b *builder.Builder, owner client.Object, list client.ObjectList) error {
+	gvk, err := apiutil.GVKForObject(owner, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed checking if %s is served: %w", gvk.Kind, err)
+	}
+
+	b.Watches(
+		owner,
+		handler.EnqueueRequestsFromMapFunc(listControlledObjects(mgr.GetClient(), list)),
+		builder.WithPredicates(AnnotationChangedPredicate()),
+	)
+	return nil
+}
+
+// listControlledObjects returns a map func which lists the objects of the
+// provided list type controlled by the mapped object.
+func listControlledObjects(cl client.Client, list client.ObjectList) handler.MapFunc {
+	return func(ctx context.Context, owner client.Object) []reconcile.Request {
+		l := list.DeepCopyObject().(client.ObjectList)
+		if err := cl.List(ctx, l, client.InNamespace(owner.GetNamespace())); err != nil {
+			ctrllog.FromContext(ctx).Error(err, "Failed to list objects controlled by paused or resumed owner",
+				"owner", client.ObjectKeyFromObject(owner),
+			)
+			return nil
+		}
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if !ok || !metav1.IsControlledBy(obj, owner) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+		return requests
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -21,14 +22,15 @@ import (
 	"github.com/go-logr/logr"
 	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/kong/gateway-operator/controller/pkg/dataplane"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/op"
+	"github.com/kong/gateway-operator/internal/metrics"
 	"github.com/kong/gateway-operator/modules/manager/logging"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
@@ -215,7 +217,14 @@ func EnsureCertificate[
 
 	// If there are no secrets yet, then create one.
 	if count == 0 {
-		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, cl)
+		res, secret, err := generateTLSDataSecret(ctx, generatedSecret, subject, mtlsCASecretNN, usages, keyConfig, cl)
+		if err == nil {
+			events.FromContext(ctx).Eventf(owner, corev1.EventTypeNormal, events.ReasonCertificateIssued,
+				"Issued certificate for %s in Secret %s", subject, secret.Name,
+			)
+			recordCertificateExpiry(owner, secret)
+		}
+		return res, secret, err
 	}
 
 	// Otherwise there is already 1 certificate matching specified selectors.
@@ -227,8 +236,11 @@ func EnsureCertificate[
 		if err := cl.Delete(ctx, existingSecret); err != nil {
 			return op.Noop, nil, err
 		}
+		metrics.DeleteManagedCertificateExpiry(existingSecret.Namespace, existingSecret.Name)
 
-		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, cl)
+		return reissueTLSDataSecret(ctx, owner, generatedSecret, subject, mtlsCASecretNN, usages, keyConfig, cl,
+			fmt.Sprintf("certificate in Secret %s was invalid", existingSecret.Name),
+		)
 	}
 
 	// Check if existing certificate is for a different subject.
@@ -241,10 +253,15 @@ func EnsureCertificate[
 		if err := cl.Delete(ctx, existingSecret); err != nil {
 			return op.Noop, nil, err
 		}
+		metrics.DeleteManagedCertificateExpiry(existingSecret.Namespace, existingSecret.Name)
 
-		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, cl)
+		return reissueTLSDataSecret(ctx, owner, generatedSecret, subject, mtlsCASecretNN, usages, keyConfig, cl,
+			fmt.Sprintf("certificate in Secret %s was issued for %s", existingSecret.Name, cert.Subject.CommonName),
+		)
 	}
 
+	metrics.RecordManagedCertificateExpiry(ownerKind(owner), client.ObjectKeyFromObject(owner), existingSecret.Name, cert.NotAfter)
+
 	var updated bool
 	updated, existingSecret.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingSecret.ObjectMeta, generatedSecret.ObjectMeta)
 	if updated {
@@ -300,6 +317,50 @@ func getSecretOpts[T interface {
 	}
 }
 
+// reissueTLSDataSecret generates a TLS certificate data secret replacing the
+// owner's deleted one and emits an Event for the owner with the provided cause.
+func reissueTLSDataSecret(
+	ctx context.Context,
+	owner client.Object,
+	generatedSecret *corev1.Secret,
+	subject string,
+	mtlsCASecret types.NamespacedName,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+	k8sClient client.Client,
+	cause string,
+) (op.Result, *corev1.Secret, error) {
+	res, secret, err := generateTLSDataSecret(ctx, generatedSecret, subject, mtlsCASecret, usages, keyConfig, k8sClient)
+	if err != nil {
+		return res, secret, err
+	}
+	events.FromContext(ctx).Eventf(owner, corev1.EventTypeNormal, events.ReasonCertificateReissued,
+		"Reissued certificate for %s in Secret %s, the previous %s", subject, secret.Name, cause,
+	)
+	recordCertificateExpiry(owner, secret)
+	return res, secret, nil
+}
+
+// recordCertificateExpiry records the expiry of the certificate stored in the
+// Secret owned by the provided object. Secrets without a valid certificate are
+// skipped, as they're going to be replaced.
+func recordCertificateExpiry(owner client.Object, secret *corev1.Secret) {
+	block, _ := pem.Decode(secret.Data["tls.crt"])
+	if block == nil {
+		return
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return
+	}
+	metrics.RecordManagedCertificateExpiry(ownerKind(owner), client.ObjectKeyFromObject(owner), secret.Name, cert.NotAfter)
+}
+
+// ownerKind returns the kind of the object owning a certificate, e.g. DataPlane.
+func ownerKind(owner client.Object) string {
+	return reflect.TypeOf(owner).Elem().Name()
+}
+
 // generateTLSDataSecret generates a TLS certificate data, fills the provided secret with
 // that data and creates it using the k8s client.
 // It returns a boolean indicating whether the secret has been created, the secret
@@ -307,18 +368,53 @@ func getSecretOpts[T interface {
 func generateTLSDataSecret(
 	ctx context.Context,
 	generatedSecret *corev1.Secret,
-	owner client.Object,
 	subject string,
 	mtlsCASecret types.NamespacedName,
 	usages []certificatesv1.KeyUsage,
 	keyConfig KeyConfig,
 	k8sClient client.Client,
 ) (op.Result, *corev1.Secret, error) {
-	priv, pemBlock, signatureAlgorithm, err := CreatePrivateKey(keyConfig)
+	cert, key, caCert, err := IssueCertificate(ctx, k8sClient, mtlsCASecret, []string{subject}, usages, keyConfig)
 	if err != nil {
 		return op.Noop, nil, err
 	}
 
+	generatedSecret.Data = map[string][]byte{
+		"ca.crt":  caCert,
+		"tls.crt": cert,
+		"tls.key": key,
+	}
+
+	err = k8sClient.Create(ctx, generatedSecret)
+	if err != nil {
+		return op.Noop, nil, err
+	}
+
+	return op.Created, generatedSecret, nil
+}
+
+// IssueCertificate issues a certificate for the provided DNS names signed by
+// the CA in the mtlsCASecret Secret. The first DNS name is used as the
+// certificate's subject. It returns the PEM encoded certificate, its private
+// key and the CA certificate.
+func IssueCertificate(
+	ctx context.Context,
+	cl client.Reader,
+	mtlsCASecret types.NamespacedName,
+	dnsNames []string,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+) (cert []byte, key []byte, caCert []byte, err error) {
+	if len(dnsNames) == 0 {
+		return nil, nil, nil, errors.New("at least one DNS name is required to issue a certificate")
+	}
+	subject := dnsNames[0]
+
+	priv, pemBlock, signatureAlgorithm, err := CreatePrivateKey(keyConfig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	template := x509.CertificateRequest{
 		Subject: pkix.Name{
 			CommonName:   subject,
@@ -326,12 +422,12 @@ func generateTLSDataSecret(
 			Country:      []string{"US"},
 		},
 		SignatureAlgorithm: signatureAlgorithm,
-		DNSNames:           []string{subject},
+		DNSNames:           dnsNames,
 	}
 
 	der, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
 	if err != nil {
-		return op.Noop, nil, err
+		return nil, nil, nil, err
 	}
 
 	// This is effectively a placeholder so long as we handle signing internally. When actually creating CSR resources,
@@ -345,10 +441,6 @@ func generateTLSDataSecret(
 	expiration := int32(315400000)
 
 	csr := certificatesv1.CertificateSigningRequest{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: owner.GetNamespace(),
-			Name:      owner.GetName(),
-		},
 		Spec: certificatesv1.CertificateSigningRequestSpec{
 			Request: pem.EncodeToMemory(&pem.Block{
 				Type:  "CERTIFICATE REQUEST",
@@ -361,28 +453,17 @@ func generateTLSDataSecret(
 	}
 
 	var ca corev1.Secret
-	err = k8sClient.Get(ctx, mtlsCASecret, &ca)
+	err = cl.Get(ctx, mtlsCASecret, &ca)
 	if err != nil {
-		return op.Noop, nil, err
+		return nil, nil, nil, err
 	}
 
 	signed, err := signCertificate(csr, &ca)
 	if err != nil {
-		return op.Noop, nil, err
+		return nil, nil, nil, err
 	}
 
-	generatedSecret.Data = map[string][]byte{
-		"ca.crt":  ca.Data["tls.crt"],
-		"tls.crt": signed,
-		"tls.key": pem.EncodeToMemory(pemBlock),
-	}
-
-	err = k8sClient.Create(ctx, generatedSecret)
-	if err != nil {
-		return op.Noop, nil, err
-	}
-
-	return op.Created, generatedSecret, nil
+	return signed, pem.EncodeToMemory(pemBlock), ca.Data["tls.crt"], nil
 }
 
 // GetManagedLabelForServiceSecret returns a label selector for the ServiceSecret.
@@ -4,20 +4,29 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/controller/pkg/pause"
 	"github.com/kong/gateway-operator/controller/pkg/watch"
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
+	"github.com/kong/gateway-operator/internal/tracing"
 	"github.com/kong/gateway-operator/internal/utils/gatewayclass"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 	"github.com/kong/gateway-operator/pkg/vars"
 
@@ -34,6 +43,11 @@ type AIGatewayReconciler struct {
 
 	Scheme          *runtime.Scheme
 	DevelopmentMode bool
+	EventRecorder   record.EventRecorder
+
+	// llmUsageGetter allows overriding how the usage of the LLMs is retrieved
+	// from the DataPlane Pods. It's used in tests.
+	llmUsageGetter aiLLMUsageGetter
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -53,14 +67,26 @@ func (r *AIGatewayReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 			handler.EnqueueRequestsFromMapFunc(r.listAIGatewaysForReferenceGrants),
 			builder.WithPredicates(predicate.NewPredicateFuncs(referenceGrantReferencesAIGateway)),
 		).
-		// TODO watch on Gateways, KongPlugins, e.t.c.
+		Watches(
+			&gatewayv1.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.listAIGatewaysForGateway),
+		).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.listAIGatewaysForSecret),
+			builder.WithPredicates(predicate.NewPredicateFuncs(secretIsNotManagedByAIGateway)),
+		).
+		// TODO watch on KongPlugins, e.t.c.
 		//
 		// See: https://github.com/Kong/gateway-operator/issues/137
-		Complete(r)
+		Complete(sharding.NewReconciler(ctx, tracing.NewReconciler("AIGateway",
+			pause.NewReconciler(r.Client, "AIGateway", pause.ConditionsAware[*operatorv1alpha1.AIGateway], r),
+		)))
 }
 
 // Reconcile reconciles the AIGateway resource.
 func (r *AIGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = events.IntoContext(ctx, r.EventRecorder)
 	logger := log.GetLogger(ctx, "aigateway", r.DevelopmentMode)
 
 	var aigateway operatorv1alpha1.AIGateway
@@ -68,6 +94,13 @@ func (r *AIGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// the resources of a deleted AIGateway are cleaned up regardless of its
+	// GatewayClass which might have been deleted as well.
+	log.Trace(logger, "handling any necessary aigateway cleanup")
+	if aigateway.GetDeletionTimestamp() != nil {
+		return r.cleanup(ctx, logger, &aigateway)
+	}
+
 	log.Trace(logger, "verifying gatewayclass for aigateway")
 	// we verify the GatewayClass in the watch predicates as well, but the watch
 	// predicates are known to be lossy, so they are considered only an optimization
@@ -98,22 +131,36 @@ func (r *AIGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
-	log.Trace(logger, "handling any necessary aigateway cleanup")
-	if aigateway.GetDeletionTimestamp() != nil {
-		log.Debug(logger, "aigateway is being deleted")
-		return ctrl.Result{}, nil
+	log.Trace(logger, "managing the aigateway resource finalizers")
+	oldAIGateway := aigateway.DeepCopy()
+	if controllerutil.AddFinalizer(&aigateway, string(AIGatewayCleanupFinalizer)) {
+		if err := r.Client.Patch(ctx, &aigateway, client.MergeFrom(oldAIGateway)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed adding finalizer to aigateway: %w", err)
+		}
+		log.Debug(logger, "finalizer for cleaning up aigateway resources added")
+		return ctrl.Result{}, nil // update will re-queue
+	}
+
+	log.Trace(logger, "parsing the configuration set through the aigateway annotations")
+	cfg, configErr := aiGatewayAnnotationsConfigFor(&aigateway)
+	acceptedCondition := newAIGatewayAcceptedCondition(&aigateway)
+	if configErr != nil {
+		acceptedCondition = newAIGatewayRejectedCondition(&aigateway, configErr.Error())
 	}
 
 	log.Trace(logger, "marking aigateway as accepted")
-	oldAIGateway := aigateway.DeepCopy()
-	k8sutils.SetCondition(newAIGatewayAcceptedCondition(&aigateway), &aigateway)
+	k8sutils.SetCondition(acceptedCondition, &aigateway)
 	if k8sutils.NeedsUpdate(oldAIGateway, &aigateway) {
 		if err := r.Client.Status().Patch(ctx, &aigateway, client.MergeFrom(oldAIGateway)); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to patch status for aigateway: %w", err)
 		}
-		log.Info(logger, "aigateway marked as accepted")
+		log.Info(logger, "aigateway acceptance updated", "accepted", acceptedCondition.Status)
 		return ctrl.Result{}, nil // update will re-queue
 	}
+	if configErr != nil {
+		log.Debug(logger, "aigateway rejected", "reason", configErr.Error())
+		return ctrl.Result{}, nil
+	}
 
 	log.Info(logger, "managing gateway resources for aigateway")
 	gatewayResourcesChanged, err := r.manageGateway(ctx, logger, &aigateway)
@@ -125,18 +172,37 @@ func (r *AIGatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	log.Info(logger, "configuring plugin and route resources for aigateway")
-	pluginResourcesChanged, err := r.configurePlugins(ctx, logger, &aigateway)
+	pluginResourcesChanged, models, err := r.configurePlugins(ctx, logger, &aigateway, cfg)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Trace(logger, "updating status for aigateway")
+	gateway, err := r.getGateway(ctx, &aigateway)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	var usageSyncAfter time.Duration
+	if len(cfg.tokenBudgets) > 0 {
+		log.Trace(logger, "syncing llm usage for aigateway")
+		models, usageSyncAfter, err = r.syncLLMUsage(ctx, logger, &aigateway, gateway, models)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	setAIGatewayStatus(&aigateway, gateway, models, pluginResourcesChanged)
+	if k8sutils.NeedsUpdate(oldAIGateway, &aigateway) ||
+		!equality.Semantic.DeepEqual(oldAIGateway.Status.Endpoints, aigateway.Status.Endpoints) {
+		if err := r.Client.Status().Patch(ctx, &aigateway, client.MergeFrom(oldAIGateway)); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to patch status for aigateway: %w", err)
+		}
+		log.Debug(logger, "aigateway status updated")
+	}
 	if pluginResourcesChanged {
-		return ctrl.Result{Requeue: true}, err
+		return ctrl.Result{Requeue: true}, nil
 	}
 
-	// TODO: manage status updates
-	//
-	// See: https://github.com/Kong/gateway-operator/issues/137
-
 	log.Info(logger, "reconciliation complete for aigateway resource")
-	return ctrl.Result{}, nil
+	// the usage of the models with a token budget is synced periodically.
+	return ctrl.Result{RequeueAfter: usageSyncAfter}, nil
 }
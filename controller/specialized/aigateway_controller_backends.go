@@ -0,0 +1,311 @@
+package specialized
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGateway - Multi-Backend LLMs
+// -----------------------------------------------------------------------------
+
+const (
+	// AIGatewayPrimaryBackendName is the name of the backend served by the cloud
+	// provider configured in the cloud hosted LLM itself.
+	AIGatewayPrimaryBackendName = "primary"
+
+	// aiGatewayDefaultBackendWeight is the default balancer weight of a backend.
+	aiGatewayDefaultBackendWeight = 100
+)
+
+var (
+	aiGatewayBalancerAlgorithms = []string{
+		"round-robin", "lowest-latency", "lowest-usage", "consistent-hashing", "priority",
+	}
+	aiGatewayFailoverCriteria = []string{
+		"error", "timeout", "invalid_header", "http_403", "http_404", "http_429",
+		"http_500", "http_502", "http_503", "http_504", "non_idempotent",
+	}
+)
+
+// AIGatewayModelBackends configures the backends a cloud hosted LLM of an
+// AIGateway is load balanced across. It is configured through the
+// consts.AIGatewayLLMBackendsAnnotation annotation of an AIGateway.
+type AIGatewayModelBackends struct {
+	// Identifier is the identifier of the cloud hosted LLM.
+	Identifier string `json:"identifier"`
+
+	// Weight is the balancer weight of the LLM's own cloud provider, the
+	// "primary" backend. Defaults to 100.
+	Weight *int `json:"weight,omitempty"`
+
+	// Azure configures the Azure OpenAI deployment of the primary backend. It
+	// is required when the LLM's cloud provider is Azure.
+	Azure *AIGatewayAzureOptions `json:"azure,omitempty"`
+
+	// Backends are the backends serving the LLM next to the primary one.
+	Backends []AIGatewayModelBackend `json:"backends"`
+
+	// Balancer configures how requests are balanced across the backends.
+	Balancer *AIGatewayModelBalancer `json:"balancer,omitempty"`
+}
+
+// AIGatewayModelBackend is an additional backend serving a cloud hosted LLM.
+type AIGatewayModelBackend struct {
+	// Name is the name of the backend, unique across the LLM's backends.
+	Name string `json:"name"`
+
+	// Provider is the cloud provider serving the backend.
+	Provider operatorv1alpha1.AICloudProviderName `json:"provider"`
+
+	// Model is the model name used with the backend. Defaults to the LLM's
+	// model.
+	Model *string `json:"model,omitempty"`
+
+	// Weight is the balancer weight of the backend. Defaults to 100. With the
+	// "priority" algorithm backends with higher weights are preferred and the
+	// ones with lower weights are only used when the former fail.
+	Weight *int `json:"weight,omitempty"`
+
+	// Azure configures the Azure OpenAI deployment. It is required for the
+	// Azure provider.
+	Azure *AIGatewayAzureOptions `json:"azure,omitempty"`
+
+	// SecretRef references the Secret key holding the backend's API key.
+	// Defaults to the key named after the provider in the AIGateway's cloud
+	// provider credentials Secret.
+	SecretRef *SelfHostedLLMSecretKeyRef `json:"secretRef,omitempty"`
+}
+
+// AIGatewayAzureOptions configures an Azure OpenAI deployment.
+type AIGatewayAzureOptions struct {
+	// Instance is the name of the Azure OpenAI instance.
+	Instance string `json:"instance"`
+
+	// DeploymentID is the ID of the model deployment in the instance.
+	DeploymentID string `json:"deploymentID"`
+
+	// APIVersion is the Azure OpenAI API version.
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// AIGatewayModelBalancer configures how the requests to an LLM are balanced
+// across its backends.
+type AIGatewayModelBalancer struct {
+	// Algorithm is the balancing algorithm: "round-robin" (default),
+	// "lowest-latency", "lowest-usage", "consistent-hashing" or "priority".
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Retries is the number of retries on another backend when a request
+	// fails with one of the FailoverCriteria.
+	Retries *int `json:"retries,omitempty"`
+
+	// FailoverCriteria are the conditions in which a request is retried on
+	// another backend, e.g. "error", "timeout" or "http_429".
+	FailoverCriteria []string `json:"failoverCriteria,omitempty"`
+
+	// ConnectTimeout is the timeout in milliseconds for connecting to a backend.
+	ConnectTimeout *int `json:"connectTimeout,omitempty"`
+
+	// ReadTimeout is the timeout in milliseconds between two successive read
+	// operations from a backend.
+	ReadTimeout *int `json:"readTimeout,omitempty"`
+
+	// WriteTimeout is the timeout in milliseconds between two successive write
+	// operations to a backend.
+	WriteTimeout *int `json:"writeTimeout,omitempty"`
+}
+
+// modelBackendsForAIGateway parses the backends configured in the AIGateway's
+// consts.AIGatewayLLMBackendsAnnotation annotation and returns them by model
+// identifier. It returns nil when the annotation is not set.
+func modelBackendsForAIGateway(aigateway *operatorv1alpha1.AIGateway) (map[string]AIGatewayModelBackends, error) {
+	raw, ok := aigateway.GetAnnotations()[consts.AIGatewayLLMBackendsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var list []AIGatewayModelBackends
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", consts.AIGatewayLLMBackendsAnnotation, err)
+	}
+
+	cloudHosted := make(map[string]operatorv1alpha1.CloudHostedLargeLanguageModel)
+	if aigateway.Spec.LargeLanguageModels != nil {
+		for _, llm := range aigateway.Spec.LargeLanguageModels.CloudHosted {
+			cloudHosted[llm.Identifier] = llm
+		}
+	}
+
+	backends := make(map[string]AIGatewayModelBackends, len(list))
+	for i, b := range list {
+		llm, ok := cloudHosted[b.Identifier]
+		if !ok {
+			return nil, fmt.Errorf("identifier '%s' in %s annotation does not match any cloud hosted LLM", b.Identifier, consts.AIGatewayLLMBackendsAnnotation)
+		}
+		if _, ok := backends[b.Identifier]; ok {
+			return nil, fmt.Errorf("identifier '%s' in %s annotation is not unique", b.Identifier, consts.AIGatewayLLMBackendsAnnotation)
+		}
+		if err := validateModelBackends(&llm, &b); err != nil {
+			return nil, fmt.Errorf("invalid backends at index %d in %s annotation: %w", i, consts.AIGatewayLLMBackendsAnnotation, err)
+		}
+		backends[b.Identifier] = b
+	}
+	return backends, nil
+}
+
+func validateModelBackends(llm *operatorv1alpha1.CloudHostedLargeLanguageModel, b *AIGatewayModelBackends) error {
+	if len(b.Backends) == 0 {
+		return fmt.Errorf("at least one backend is required")
+	}
+	if err := validateBackendWeight(AIGatewayPrimaryBackendName, b.Weight); err != nil {
+		return err
+	}
+	if err := validateAzureOptions(AIGatewayPrimaryBackendName, llm.AICloudProvider.Name, b.Azure); err != nil {
+		return err
+	}
+
+	names := map[string]struct{}{AIGatewayPrimaryBackendName: {}}
+	for _, backend := range b.Backends {
+		if errs := validation.IsDNS1123Label(backend.Name); len(errs) > 0 {
+			return fmt.Errorf("backend name '%s' is invalid: %s", backend.Name, strings.Join(errs, ", "))
+		}
+		if _, ok := names[backend.Name]; ok {
+			return fmt.Errorf("backend name '%s' is not unique", backend.Name)
+		}
+		names[backend.Name] = struct{}{}
+
+		if _, err := getAuthHeaderForInference(operatorv1alpha1.AICloudProvider{Name: backend.Provider}); err != nil {
+			return fmt.Errorf("backend '%s' provider '%s' is not supported", backend.Name, backend.Provider)
+		}
+		if err := validateBackendWeight(backend.Name, backend.Weight); err != nil {
+			return err
+		}
+		if err := validateAzureOptions(backend.Name, backend.Provider, backend.Azure); err != nil {
+			return err
+		}
+		if backend.SecretRef != nil && (backend.SecretRef.Name == "" || backend.SecretRef.Key == "") {
+			return fmt.Errorf("backend '%s' secretRef requires a name and a key", backend.Name)
+		}
+	}
+
+	if b.Balancer != nil {
+		if b.Balancer.Algorithm != "" && !slices.Contains(aiGatewayBalancerAlgorithms, b.Balancer.Algorithm) {
+			return fmt.Errorf("balancer algorithm '%s' is not supported (supported algorithms: %s)",
+				b.Balancer.Algorithm, strings.Join(aiGatewayBalancerAlgorithms, ", "))
+		}
+		for _, c := range b.Balancer.FailoverCriteria {
+			if !slices.Contains(aiGatewayFailoverCriteria, c) {
+				return fmt.Errorf("balancer failover criterion '%s' is not supported (supported criteria: %s)",
+					c, strings.Join(aiGatewayFailoverCriteria, ", "))
+			}
+		}
+		if b.Balancer.Retries != nil && (*b.Balancer.Retries < 0 || *b.Balancer.Retries > 32767) {
+			return fmt.Errorf("balancer retries %d is invalid", *b.Balancer.Retries)
+		}
+		for _, timeout := range []struct {
+			name  string
+			value *int
+		}{
+			{"connectTimeout", b.Balancer.ConnectTimeout},
+			{"readTimeout", b.Balancer.ReadTimeout},
+			{"writeTimeout", b.Balancer.WriteTimeout},
+		} {
+			if timeout.value != nil && *timeout.value < 0 {
+				return fmt.Errorf("balancer %s %d is invalid", timeout.name, *timeout.value)
+			}
+		}
+	}
+	return nil
+}
+
+func validateBackendWeight(name string, weight *int) error {
+	if weight != nil && (*weight < 1 || *weight > 65535) {
+		return fmt.Errorf("backend '%s' weight %d is invalid, it must be between 1 and 65535", name, *weight)
+	}
+	return nil
+}
+
+func validateAzureOptions(name string, provider operatorv1alpha1.AICloudProviderName, azure *AIGatewayAzureOptions) error {
+	if provider != operatorv1alpha1.AICloudProviderAzure {
+		return nil
+	}
+	if azure == nil || azure.Instance == "" || azure.DeploymentID == "" {
+		return fmt.Errorf("backend '%s' uses the azure provider and requires azure instance and deploymentID", name)
+	}
+	return nil
+}
+
+// aiGatewayBackendTarget is a backend of a cloud hosted LLM whose credentials
+// have been resolved.
+type aiGatewayBackendTarget struct {
+	name           string
+	provider       operatorv1alpha1.AICloudProviderName
+	model          *string
+	weight         *int
+	azure          *AIGatewayAzureOptions
+	credentialData []byte
+}
+
+// aiCloudGatewayToKongProxyAdvancedPlugin takes an accepted/validated
+// vXalphaY.CloudHostedLargeLanguageModel with its backends and the targets
+// whose credentials have been resolved and transforms them into an
+// ai-proxy-advanced vX.KongPlugin balancing the requests across the targets.
+func aiCloudGatewayToKongProxyAdvancedPlugin(
+	aiCloudLLM *operatorv1alpha1.CloudHostedLargeLanguageModel,
+	backends *AIGatewayModelBackends,
+	aigateway *operatorv1alpha1.AIGateway,
+	targets []aiGatewayBackendTarget,
+) (*configurationv1.KongPlugin, error) {
+	config := AIProxyAdvancedConfig{
+		Targets: make([]AIProxyAdvancedTargetConfig, 0, len(targets)),
+	}
+	if b := backends.Balancer; b != nil {
+		config.Balancer = &AIProxyAdvancedBalancerConfig{
+			Retries:          b.Retries,
+			FailoverCriteria: b.FailoverCriteria,
+			ConnectTimeout:   b.ConnectTimeout,
+			ReadTimeout:      b.ReadTimeout,
+			WriteTimeout:     b.WriteTimeout,
+		}
+		if b.Algorithm != "" {
+			config.Balancer.Algorithm = &b.Algorithm
+		}
+	}
+
+	for _, target := range targets {
+		llmConfig, err := aiCloudProviderLLMConfig(
+			aiCloudLLM, operatorv1alpha1.AICloudProvider{Name: target.provider}, target.model, &target.credentialData,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if target.azure != nil {
+			llmConfig.Model.Options.AzureInstance = &target.azure.Instance
+			llmConfig.Model.Options.AzureDeploymentID = &target.azure.DeploymentID
+			if target.azure.APIVersion != "" {
+				llmConfig.Model.Options.AzureAPIVersion = &target.azure.APIVersion
+			}
+		}
+		weight := aiGatewayDefaultBackendWeight
+		if target.weight != nil {
+			weight = *target.weight
+		}
+		config.Targets = append(config.Targets, AIProxyAdvancedTargetConfig{
+			AICloudProviderLLMConfig: *llmConfig,
+			Weight:                   &weight,
+			Description:              &target.name,
+		})
+	}
+
+	return aiProxyKongPlugin(aiCloudLLM.Identifier, "ai-proxy-advanced", aigateway, &config)
+}
@@ -0,0 +1,188 @@
+package specialized
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+func aiGatewayWithBackends(annotation string) *operatorv1alpha1.AIGateway {
+	aigw := &operatorv1alpha1.AIGateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai"},
+		Spec: operatorv1alpha1.AIGatewaySpec{
+			LargeLanguageModels: &operatorv1alpha1.LargeLanguageModels{
+				CloudHosted: []operatorv1alpha1.CloudHostedLargeLanguageModel{
+					{
+						Identifier:      "gpt",
+						Model:           lo.ToPtr("gpt-4o"),
+						PromptType:      lo.ToPtr(operatorv1alpha1.LLMPromptTypeChat),
+						AICloudProvider: operatorv1alpha1.AICloudProvider{Name: operatorv1alpha1.AICloudProviderOpenAI},
+					},
+				},
+			},
+			CloudProviderCredentials: &operatorv1alpha1.AICloudProviderAPITokenRef{Name: "credentials"},
+		},
+	}
+	if annotation != "" {
+		aigw.Annotations = map[string]string{consts.AIGatewayLLMBackendsAnnotation: annotation}
+	}
+	return aigw
+}
+
+func TestModelBackendsForAIGateway(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotation    string
+		expectedError string
+	}{
+		{
+			name:       "valid backends",
+			annotation: `[{"identifier":"gpt","backends":[{"name":"azure-west","provider":"azure","azure":{"instance":"west","deploymentID":"gpt-4o"}}],"balancer":{"algorithm":"priority","retries":3,"failoverCriteria":["error","http_429"]}}]`,
+		},
+		{
+			name:          "unknown model",
+			annotation:    `[{"identifier":"llama","backends":[{"name":"other","provider":"openai"}]}]`,
+			expectedError: "does not match any cloud hosted LLM",
+		},
+		{
+			name:          "no backends",
+			annotation:    `[{"identifier":"gpt","backends":[]}]`,
+			expectedError: "at least one backend is required",
+		},
+		{
+			name:          "backend named after the primary backend",
+			annotation:    `[{"identifier":"gpt","backends":[{"name":"primary","provider":"openai"}]}]`,
+			expectedError: "backend name 'primary' is not unique",
+		},
+		{
+			name:          "azure backend without deployment",
+			annotation:    `[{"identifier":"gpt","backends":[{"name":"azure","provider":"azure"}]}]`,
+			expectedError: "requires azure instance and deploymentID",
+		},
+		{
+			name:          "unsupported provider",
+			annotation:    `[{"identifier":"gpt","backends":[{"name":"other","provider":"acme"}]}]`,
+			expectedError: "provider 'acme' is not supported",
+		},
+		{
+			name:          "invalid weight",
+			annotation:    `[{"identifier":"gpt","weight":0,"backends":[{"name":"other","provider":"openai"}]}]`,
+			expectedError: "weight 0 is invalid",
+		},
+		{
+			name:          "unsupported failover criterion",
+			annotation:    `[{"identifier":"gpt","backends":[{"name":"other","provider":"openai"}],"balancer":{"failoverCriteria":["always"]}}]`,
+			expectedError: "failover criterion 'always' is not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backends, err := modelBackendsForAIGateway(aiGatewayWithBackends(tc.annotation))
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, backends, "gpt")
+		})
+	}
+}
+
+func TestCloudHostedLLMBackendsPlugin(t *testing.T) {
+	aigw := aiGatewayWithBackends(`[
+		{
+			"identifier": "gpt",
+			"weight": 200,
+			"backends": [
+				{"name": "azure-west", "provider": "azure", "weight": 50, "azure": {"instance": "west", "deploymentID": "gpt-4o"}, "secretRef": {"name": "azure-west", "key": "api-key"}},
+				{"name": "mistral", "provider": "mistral", "model": "mistral-large"}
+			],
+			"balancer": {"algorithm": "priority", "retries": 3, "failoverCriteria": ["error", "http_429"]}
+		}
+	]`)
+	modelBackends, err := modelBackendsForAIGateway(aigw)
+	require.NoError(t, err)
+	backends := modelBackends["gpt"]
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "credentials"},
+		Data:       map[string][]byte{"openai": []byte("openai-key")},
+	}
+	azureWest := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "azure-west"},
+		Data:       map[string][]byte{"api-key": []byte("azure-key")},
+	}
+	r := &AIGatewayReconciler{
+		Client: fakectrlruntimeclient.NewClientBuilder().
+			WithScheme(scheme.Get()).
+			WithObjects(credentials, azureWest).
+			Build(),
+	}
+	ctx := context.Background()
+	creds, err := r.getCloudProviderCredentials(ctx, aigw)
+	require.NoError(t, err)
+
+	t.Log("backends whose credentials can't be resolved are left out")
+	model, plugin, err := r.cloudHostedLLMBackendsPlugin(ctx, aigw, &aigw.Spec.LargeLanguageModels.CloudHosted[0], &backends, creds)
+	require.NoError(t, err)
+	require.NotNil(t, plugin)
+	require.Equal(t, "gpt-ai-proxy-advanced", plugin.Name)
+	require.Equal(t, "ai-proxy-advanced", plugin.PluginName)
+	require.Equal(t, metav1.ConditionTrue, model.credentialsResolved.Status)
+	require.NotNil(t, model.backendsCredentialsResolved)
+	require.Equal(t, metav1.ConditionFalse, model.backendsCredentialsResolved.Status)
+	require.Equal(t, AIGatewayEndpointConditionReasonBackendsPartiallyResolved, model.backendsCredentialsResolved.Reason)
+	require.Contains(t, model.backendsCredentialsResolved.Message, "primary: resolved")
+	require.Contains(t, model.backendsCredentialsResolved.Message, "azure-west: resolved")
+	require.Contains(t, model.backendsCredentialsResolved.Message, "mistral: provider 'mistral' has no API key")
+
+	var config AIProxyAdvancedConfig
+	require.NoError(t, json.Unmarshal(plugin.Config.Raw, &config))
+	require.Equal(t, "priority", *config.Balancer.Algorithm)
+	require.Equal(t, 3, *config.Balancer.Retries)
+	require.Equal(t, []string{"error", "http_429"}, config.Balancer.FailoverCriteria)
+	require.Len(t, config.Targets, 2)
+	primary, azure := config.Targets[0], config.Targets[1]
+	require.Equal(t, 200, *primary.Weight)
+	require.Equal(t, "openai", *primary.Model.Provider)
+	require.Equal(t, "Bearer openai-key", *primary.Auth.HeaderValue)
+	require.Equal(t, 50, *azure.Weight)
+	require.Equal(t, "azure", *azure.Model.Provider)
+	require.Equal(t, "gpt-4o", *azure.Model.Name, "model defaults to the LLM's model")
+	require.Equal(t, "west", *azure.Model.Options.AzureInstance)
+	require.Equal(t, "gpt-4o", *azure.Model.Options.AzureDeploymentID)
+	require.Equal(t, "api-key", *azure.Auth.HeaderName)
+	require.Equal(t, "azure-key", *azure.Auth.HeaderValue)
+
+	t.Log("the model fails when none of its backends can be used")
+	model, plugin, err = r.cloudHostedLLMBackendsPlugin(ctx, aigw, &aigw.Spec.LargeLanguageModels.CloudHosted[0], &backends, cloudProviderCredentials{
+		reason:  AIGatewayEndpointConditionReasonSecretNotFound,
+		message: "secret 'ns/credentials' not found",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, plugin, "the azure-west backend uses its own secret")
+
+	require.NoError(t, r.Client.Delete(ctx, azureWest))
+	model, plugin, err = r.cloudHostedLLMBackendsPlugin(ctx, aigw, &aigw.Spec.LargeLanguageModels.CloudHosted[0], &backends, cloudProviderCredentials{
+		reason:  AIGatewayEndpointConditionReasonSecretNotFound,
+		message: "secret 'ns/credentials' not found",
+	})
+	require.NoError(t, err)
+	require.Nil(t, plugin)
+	require.Equal(t, metav1.ConditionFalse, model.credentialsResolved.Status)
+	require.Equal(t, AIGatewayEndpointConditionReasonSecretNotFound, model.credentialsResolved.Reason)
+	require.Equal(t, AIGatewayEndpointConditionReasonBackendsUnresolved, model.backendsCredentialsResolved.Reason)
+	require.NotNil(t, model.failure())
+}
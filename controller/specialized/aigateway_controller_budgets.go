@@ -0,0 +1,294 @@
+package specialized
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGateway - Token Budgets
+// -----------------------------------------------------------------------------
+
+// AIGatewayBudgetScope is the scope of the counters of a token budget.
+type AIGatewayBudgetScope string
+
+const (
+	// AIGatewayBudgetScopeConsumer gives every consumer its own budget.
+	AIGatewayBudgetScopeConsumer AIGatewayBudgetScope = "consumer"
+
+	// AIGatewayBudgetScopeConsumerGroup gives every consumer group a budget
+	// shared by its consumers.
+	AIGatewayBudgetScopeConsumerGroup AIGatewayBudgetScope = "consumer-group"
+)
+
+// AIGatewayBudgetLimitType is what a limit of a token budget counts.
+type AIGatewayBudgetLimitType string
+
+const (
+	// AIGatewayBudgetLimitTypeRequests counts the requests.
+	AIGatewayBudgetLimitTypeRequests AIGatewayBudgetLimitType = "requests"
+
+	// AIGatewayBudgetLimitTypePromptTokens counts the prompt tokens.
+	AIGatewayBudgetLimitTypePromptTokens AIGatewayBudgetLimitType = "prompt_tokens"
+
+	// AIGatewayBudgetLimitTypeCompletionTokens counts the completion tokens.
+	AIGatewayBudgetLimitTypeCompletionTokens AIGatewayBudgetLimitType = "completion_tokens"
+
+	// AIGatewayBudgetLimitTypeTotalTokens counts both the prompt and the
+	// completion tokens.
+	AIGatewayBudgetLimitTypeTotalTokens AIGatewayBudgetLimitType = "total_tokens"
+)
+
+// AIGatewayTokenBudget caps the requests and the tokens used with the models of
+// an AIGateway. It is configured through the consts.AIGatewayTokenBudgetsAnnotation
+// annotation of an AIGateway.
+//
+// Request limits are enforced by the rate-limiting-advanced plugin and token
+// limits by the ai-rate-limiting-advanced plugin. As a plugin counts a single
+// kind of tokens, all the token limits of a budget must be of the same type.
+type AIGatewayTokenBudget struct {
+	// Name is the name of the budget.
+	Name string `json:"name"`
+
+	// Models are the identifiers of the models the budget applies to. Defaults
+	// to all the models of the AIGateway. A model can have a single budget.
+	Models []string `json:"models,omitempty"`
+
+	// Per is the scope of the budget's counters: "consumer" (default) or
+	// "consumer-group".
+	Per AIGatewayBudgetScope `json:"per,omitempty"`
+
+	// Limits are the limits of the budget.
+	Limits []AIGatewayBudgetLimit `json:"limits"`
+}
+
+// AIGatewayBudgetLimit is a limit of a token budget.
+type AIGatewayBudgetLimit struct {
+	// Type is what the limit counts: "requests", "prompt_tokens",
+	// "completion_tokens" or "total_tokens".
+	Type AIGatewayBudgetLimitType `json:"type"`
+
+	// Limit is the maximum count in a window.
+	Limit int `json:"limit"`
+
+	// Window is the size of the window in seconds.
+	Window int `json:"window"`
+}
+
+// tokenBudgetsForAIGateway parses the budgets configured in the AIGateway's
+// consts.AIGatewayTokenBudgetsAnnotation annotation and returns them by model
+// identifier. The budgets can apply to the cloud hosted and to the provided
+// self-hosted LLMs. It returns nil when the annotation is not set.
+func tokenBudgetsForAIGateway(
+	aigateway *operatorv1alpha1.AIGateway,
+	selfHostedLLMs []SelfHostedLargeLanguageModel,
+) (map[string]AIGatewayTokenBudget, error) {
+	raw, ok := aigateway.GetAnnotations()[consts.AIGatewayTokenBudgetsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var list []AIGatewayTokenBudget
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", consts.AIGatewayTokenBudgetsAnnotation, err)
+	}
+
+	var identifiers []string
+	if aigateway.Spec.LargeLanguageModels != nil {
+		for _, llm := range aigateway.Spec.LargeLanguageModels.CloudHosted {
+			identifiers = append(identifiers, llm.Identifier)
+		}
+	}
+	for _, llm := range selfHostedLLMs {
+		identifiers = append(identifiers, llm.Identifier)
+	}
+
+	budgets := make(map[string]AIGatewayTokenBudget)
+	for i, budget := range list {
+		if err := validateTokenBudget(&budget); err != nil {
+			return nil, fmt.Errorf("invalid budget at index %d in %s annotation: %w", i, consts.AIGatewayTokenBudgetsAnnotation, err)
+		}
+		if budget.Per == "" {
+			budget.Per = AIGatewayBudgetScopeConsumer
+		}
+		models := budget.Models
+		if len(models) == 0 {
+			models = identifiers
+		}
+		for _, identifier := range models {
+			if !slices.Contains(identifiers, identifier) {
+				return nil, fmt.Errorf("budget '%s' in %s annotation references identifier '%s' which does not match any LLM",
+					budget.Name, consts.AIGatewayTokenBudgetsAnnotation, identifier)
+			}
+			if other, ok := budgets[identifier]; ok {
+				return nil, fmt.Errorf("budgets '%s' and '%s' in %s annotation both apply to identifier '%s'",
+					other.Name, budget.Name, consts.AIGatewayTokenBudgetsAnnotation, identifier)
+			}
+			budgets[identifier] = budget
+		}
+	}
+	return budgets, nil
+}
+
+func validateTokenBudget(budget *AIGatewayTokenBudget) error {
+	if budget.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	switch budget.Per {
+	case "", AIGatewayBudgetScopeConsumer, AIGatewayBudgetScopeConsumerGroup:
+	default:
+		return fmt.Errorf("budget '%s' scope '%s' is not supported (supported scopes: %s, %s)",
+			budget.Name, budget.Per, AIGatewayBudgetScopeConsumer, AIGatewayBudgetScopeConsumerGroup)
+	}
+	if len(budget.Limits) == 0 {
+		return fmt.Errorf("budget '%s' requires at least one limit", budget.Name)
+	}
+
+	var tokensType AIGatewayBudgetLimitType
+	for _, limit := range budget.Limits {
+		switch limit.Type {
+		case AIGatewayBudgetLimitTypeRequests:
+		case AIGatewayBudgetLimitTypePromptTokens, AIGatewayBudgetLimitTypeCompletionTokens, AIGatewayBudgetLimitTypeTotalTokens:
+			if tokensType != "" && tokensType != limit.Type {
+				return fmt.Errorf("budget '%s' mixes '%s' and '%s' limits, all the token limits of a budget must be of the same type",
+					budget.Name, tokensType, limit.Type)
+			}
+			tokensType = limit.Type
+		default:
+			return fmt.Errorf("budget '%s' limit type '%s' is not supported (supported types: %s, %s, %s, %s)",
+				budget.Name, limit.Type,
+				AIGatewayBudgetLimitTypeRequests, AIGatewayBudgetLimitTypePromptTokens,
+				AIGatewayBudgetLimitTypeCompletionTokens, AIGatewayBudgetLimitTypeTotalTokens)
+		}
+		if limit.Limit < 1 {
+			return fmt.Errorf("budget '%s' %s limit %d is invalid", budget.Name, limit.Type, limit.Limit)
+		}
+		if limit.Window < 1 {
+			return fmt.Errorf("budget '%s' %s window %d is invalid", budget.Name, limit.Type, limit.Window)
+		}
+	}
+	return nil
+}
+
+// aiTokenBudgetToKongPlugins takes the budget of the LLM with the provided
+// identifier, served by the provided ai-proxy providers, and transforms it into
+// the rate-limiting-advanced and ai-rate-limiting-advanced vX.KongPlugins
+// enforcing its request and token limits, along with the prometheus
+// vX.KongPlugin exposing the LLM's usage metrics.
+func aiTokenBudgetToKongPlugins(
+	identifier string,
+	providers []string,
+	budget *AIGatewayTokenBudget,
+	aigateway *operatorv1alpha1.AIGateway,
+) ([]*configurationv1.KongPlugin, error) {
+	var (
+		requestLimits, tokenLimits []AIGatewayBudgetLimit
+		tokensType                 AIGatewayBudgetLimitType
+	)
+	for _, limit := range budget.Limits {
+		if limit.Type == AIGatewayBudgetLimitTypeRequests {
+			requestLimits = append(requestLimits, limit)
+			continue
+		}
+		tokenLimits = append(tokenLimits, limit)
+		tokensType = limit.Type
+	}
+	limitsAndWindows := func(limits []AIGatewayBudgetLimit) ([]int, []int) {
+		l, w := make([]int, 0, len(limits)), make([]int, 0, len(limits))
+		for _, limit := range limits {
+			l = append(l, limit.Limit)
+			w = append(w, limit.Window)
+		}
+		return l, w
+	}
+
+	var plugins []*configurationv1.KongPlugin
+	if len(requestLimits) > 0 {
+		limit, window := limitsAndWindows(requestLimits)
+		plugin, err := aiProxyKongPlugin(identifier, "rate-limiting-advanced", aigateway, &RateLimitingAdvancedConfig{
+			Identifier: string(budget.Per),
+			Strategy:   "local",
+			Limit:      limit,
+			WindowSize: window,
+		})
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, plugin)
+	}
+	if len(tokenLimits) > 0 {
+		limit, window := limitsAndWindows(tokenLimits)
+		config := AIRateLimitingAdvancedConfig{
+			Identifier:          string(budget.Per),
+			Strategy:            "local",
+			TokensCountStrategy: string(tokensType),
+		}
+		// the tokens of every provider are counted separately.
+		for _, provider := range providers {
+			config.LLMProviders = append(config.LLMProviders, AIRateLimitingAdvancedLLMProviderConfig{
+				Name:       provider,
+				Limit:      limit,
+				WindowSize: window,
+			})
+		}
+		plugin, err := aiProxyKongPlugin(identifier, "ai-rate-limiting-advanced", aigateway, &config)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	plugin, err := aiProxyKongPlugin(identifier, "prometheus", aigateway, &PrometheusConfig{AIMetrics: true, PerConsumer: true})
+	if err != nil {
+		return nil, err
+	}
+	return append(plugins, plugin), nil
+}
+
+// aiProxyUsageKeys returns the distinct, sorted, provider and model pairs
+// served by the provided ai-proxy or ai-proxy-advanced plugin, as reported in
+// the labels of the DataPlane's AI metrics.
+func aiProxyUsageKeys(plugin *configurationv1.KongPlugin) ([]aiLLMUsageKey, error) {
+	var llms []AICloudProviderLLMConfig
+	switch plugin.PluginName {
+	case "ai-proxy":
+		var config AICloudProviderLLMConfig
+		if err := json.Unmarshal(plugin.Config.Raw, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration of plugin %s: %w", plugin.Name, err)
+		}
+		llms = append(llms, config)
+	case "ai-proxy-advanced":
+		var config AIProxyAdvancedConfig
+		if err := json.Unmarshal(plugin.Config.Raw, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse configuration of plugin %s: %w", plugin.Name, err)
+		}
+		for _, target := range config.Targets {
+			llms = append(llms, target.AICloudProviderLLMConfig)
+		}
+	}
+
+	var keys []aiLLMUsageKey
+	for _, llm := range llms {
+		if llm.Model == nil || llm.Model.Provider == nil {
+			continue
+		}
+		key := aiLLMUsageKey{provider: *llm.Model.Provider, model: lo.FromPtr(llm.Model.Name)}
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b aiLLMUsageKey) int {
+		return cmp.Or(strings.Compare(a.provider, b.provider), strings.Compare(a.model, b.model))
+	})
+	return keys, nil
+}
@@ -0,0 +1,493 @@
+package specialized
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestTokenBudgetsForAIGateway(t *testing.T) {
+	selfHostedLLMs := []SelfHostedLargeLanguageModel{{Identifier: "llama"}}
+
+	testCases := []struct {
+		name        string
+		annotation  string
+		expected    map[string]AIGatewayTokenBudget
+		expectedErr string
+	}{
+		{
+			name:     "no annotation",
+			expected: nil,
+		},
+		{
+			name:       "budget applies to all the models by default",
+			annotation: `[{"name":"default","limits":[{"type":"total_tokens","limit":1000,"window":60}]}]`,
+			expected: map[string]AIGatewayTokenBudget{
+				"gpt": {
+					Name: "default", Per: AIGatewayBudgetScopeConsumer,
+					Limits: []AIGatewayBudgetLimit{{Type: AIGatewayBudgetLimitTypeTotalTokens, Limit: 1000, Window: 60}},
+				},
+				"llama": {
+					Name: "default", Per: AIGatewayBudgetScopeConsumer,
+					Limits: []AIGatewayBudgetLimit{{Type: AIGatewayBudgetLimitTypeTotalTokens, Limit: 1000, Window: 60}},
+				},
+			},
+		},
+		{
+			name: "budgets per model",
+			annotation: `[
+				{"name":"gpt","models":["gpt"],"per":"consumer-group","limits":[{"type":"requests","limit":10,"window":1}]},
+				{"name":"llama","models":["llama"],"limits":[{"type":"prompt_tokens","limit":100,"window":60}]}
+			]`,
+			expected: map[string]AIGatewayTokenBudget{
+				"gpt": {
+					Name: "gpt", Models: []string{"gpt"}, Per: AIGatewayBudgetScopeConsumerGroup,
+					Limits: []AIGatewayBudgetLimit{{Type: AIGatewayBudgetLimitTypeRequests, Limit: 10, Window: 1}},
+				},
+				"llama": {
+					Name: "llama", Models: []string{"llama"}, Per: AIGatewayBudgetScopeConsumer,
+					Limits: []AIGatewayBudgetLimit{{Type: AIGatewayBudgetLimitTypePromptTokens, Limit: 100, Window: 60}},
+				},
+			},
+		},
+		{
+			name:        "invalid json",
+			annotation:  `{`,
+			expectedErr: "failed to parse",
+		},
+		{
+			name:        "unknown model",
+			annotation:  `[{"name":"b","models":["claude"],"limits":[{"type":"requests","limit":1,"window":1}]}]`,
+			expectedErr: "references identifier 'claude' which does not match any LLM",
+		},
+		{
+			name: "two budgets for the same model",
+			annotation: `[
+				{"name":"a","limits":[{"type":"requests","limit":1,"window":1}]},
+				{"name":"b","models":["gpt"],"limits":[{"type":"requests","limit":1,"window":1}]}
+			]`,
+			expectedErr: "budgets 'a' and 'b' in gateway-operator.konghq.com/token-budgets annotation both apply to identifier 'gpt'",
+		},
+		{
+			name:        "mixed token types",
+			annotation:  `[{"name":"b","limits":[{"type":"prompt_tokens","limit":1,"window":1},{"type":"total_tokens","limit":1,"window":1}]}]`,
+			expectedErr: "mixes 'prompt_tokens' and 'total_tokens' limits",
+		},
+		{
+			name:        "unsupported scope",
+			annotation:  `[{"name":"b","per":"ip","limits":[{"type":"requests","limit":1,"window":1}]}]`,
+			expectedErr: "scope 'ip' is not supported",
+		},
+		{
+			name:        "unsupported limit type",
+			annotation:  `[{"name":"b","limits":[{"type":"bytes","limit":1,"window":1}]}]`,
+			expectedErr: "limit type 'bytes' is not supported",
+		},
+		{
+			name:        "invalid window",
+			annotation:  `[{"name":"b","limits":[{"type":"requests","limit":1,"window":0}]}]`,
+			expectedErr: "requests window 0 is invalid",
+		},
+		{
+			name:        "no limits",
+			annotation:  `[{"name":"b"}]`,
+			expectedErr: "budget 'b' requires at least one limit",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			aigw := aiGatewayWithBackends("")
+			if tc.annotation != "" {
+				aigw.Annotations = map[string]string{consts.AIGatewayTokenBudgetsAnnotation: tc.annotation}
+			}
+			budgets, err := tokenBudgetsForAIGateway(aigw, selfHostedLLMs)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, budgets)
+		})
+	}
+}
+
+func TestAITokenBudgetToKongPlugins(t *testing.T) {
+	aigw := aiGatewayWithBackends("")
+	budget := &AIGatewayTokenBudget{
+		Name: "default",
+		Per:  AIGatewayBudgetScopeConsumerGroup,
+		Limits: []AIGatewayBudgetLimit{
+			{Type: AIGatewayBudgetLimitTypeRequests, Limit: 10, Window: 60},
+			{Type: AIGatewayBudgetLimitTypeTotalTokens, Limit: 1000, Window: 60},
+			{Type: AIGatewayBudgetLimitTypeTotalTokens, Limit: 10000, Window: 3600},
+		},
+	}
+
+	plugins, err := aiTokenBudgetToKongPlugins("gpt", []string{"openai", "mistral"}, budget, aigw)
+	require.NoError(t, err)
+	require.Len(t, plugins, 3)
+
+	require.Equal(t, "gpt-rate-limiting-advanced", plugins[0].Name)
+	require.Equal(t, "rate-limiting-advanced", plugins[0].PluginName)
+	require.JSONEq(t, `{"identifier":"consumer-group","strategy":"local","limit":[10],"window_size":[60]}`,
+		string(plugins[0].Config.Raw))
+
+	require.Equal(t, "gpt-ai-rate-limiting-advanced", plugins[1].Name)
+	require.Equal(t, "ai-rate-limiting-advanced", plugins[1].PluginName)
+	var config AIRateLimitingAdvancedConfig
+	require.NoError(t, json.Unmarshal(plugins[1].Config.Raw, &config))
+	require.Equal(t, AIRateLimitingAdvancedConfig{
+		Identifier:          "consumer-group",
+		Strategy:            "local",
+		TokensCountStrategy: "total_tokens",
+		LLMProviders: []AIRateLimitingAdvancedLLMProviderConfig{
+			{Name: "openai", Limit: []int{1000, 10000}, WindowSize: []int{60, 3600}},
+			{Name: "mistral", Limit: []int{1000, 10000}, WindowSize: []int{60, 3600}},
+		},
+	}, config)
+
+	require.Equal(t, "gpt-prometheus", plugins[2].Name)
+	require.Equal(t, "prometheus", plugins[2].PluginName)
+	require.JSONEq(t, `{"ai_metrics":true,"per_consumer":true}`, string(plugins[2].Config.Raw))
+
+	t.Run("requests only", func(t *testing.T) {
+		plugins, err := aiTokenBudgetToKongPlugins("gpt", []string{"openai"}, &AIGatewayTokenBudget{
+			Name:   "requests",
+			Per:    AIGatewayBudgetScopeConsumer,
+			Limits: []AIGatewayBudgetLimit{{Type: AIGatewayBudgetLimitTypeRequests, Limit: 1, Window: 1}},
+		}, aigw)
+		require.NoError(t, err)
+		require.Len(t, plugins, 2)
+		require.Equal(t, "rate-limiting-advanced", plugins[0].PluginName)
+		require.Equal(t, "prometheus", plugins[1].PluginName)
+	})
+}
+
+func TestAIProxyUsageKeys(t *testing.T) {
+	aigw := aiGatewayWithBackends(`[{"identifier":"gpt","backends":[
+		{"name":"mini","provider":"openai","model":"gpt-4o-mini"},
+		{"name":"same","provider":"openai"}
+	]}]`)
+	backends, err := modelBackendsForAIGateway(aigw)
+	require.NoError(t, err)
+	llm := &aigw.Spec.LargeLanguageModels.CloudHosted[0]
+
+	plugin, err := aiCloudGatewayToKongPlugin(llm, aigw, lo.ToPtr([]byte("key")))
+	require.NoError(t, err)
+	keys, err := aiProxyUsageKeys(plugin)
+	require.NoError(t, err)
+	require.Equal(t, []aiLLMUsageKey{{provider: "openai", model: "gpt-4o"}}, keys)
+
+	b := backends["gpt"]
+	targets := []aiGatewayBackendTarget{
+		{name: AIGatewayPrimaryBackendName, provider: llm.AICloudProvider.Name, model: llm.Model, credentialData: []byte("key")},
+		{name: "mini", provider: "openai", model: b.Backends[0].Model, credentialData: []byte("key")},
+		{name: "same", provider: "openai", model: llm.Model, credentialData: []byte("key")},
+	}
+	plugin, err = aiCloudGatewayToKongProxyAdvancedPlugin(llm, &b, aigw, targets)
+	require.NoError(t, err)
+	keys, err = aiProxyUsageKeys(plugin)
+	require.NoError(t, err)
+	require.Equal(t, []aiLLMUsageKey{
+		{provider: "openai", model: "gpt-4o"},
+		{provider: "openai", model: "gpt-4o-mini"},
+	}, keys)
+}
+
+func TestParseAILLMUsage(t *testing.T) {
+	const metrics = `# HELP kong_ai_llm_requests_total AI requests total per ai_provider in Kong
+# TYPE kong_ai_llm_requests_total counter
+kong_ai_llm_requests_total{ai_provider="openai",ai_model="gpt-4o",cache_status="",vector_db="",embeddings_provider="",embeddings_model="",workspace="default",consumer="alice"} 12
+kong_ai_llm_requests_total{ai_provider="openai",ai_model="gpt-4o",cache_status="hit",vector_db="redis",embeddings_provider="",embeddings_model="",workspace="default",consumer="alice"} 3
+kong_ai_llm_requests_total{ai_provider="openai",ai_model="gpt-4o",cache_status="",vector_db="",embeddings_provider="",embeddings_model="",workspace="default",consumer="bob"} 2
+kong_ai_llm_requests_total{ai_provider="mistral",ai_model="mistral-tiny",cache_status="",vector_db="",embeddings_provider="",embeddings_model="",workspace="default"} 1
+# HELP kong_ai_llm_tokens_total AI tokens total per ai_provider in Kong
+# TYPE kong_ai_llm_tokens_total counter
+kong_ai_llm_tokens_total{ai_provider="openai",ai_model="gpt-4o",cache_status="",vector_db="",embeddings_provider="",embeddings_model="",token_type="prompt_tokens",workspace="default",consumer="alice"} 120
+kong_ai_llm_tokens_total{ai_provider="openai",ai_model="gpt-4o",cache_status="",vector_db="",embeddings_provider="",embeddings_model="",token_type="completion_tokens",workspace="default",consumer="alice"} 480
+kong_ai_llm_tokens_total{ai_provider="openai",ai_model="gpt-4o",cache_status="",vector_db="",embeddings_provider="",embeddings_model="",token_type="total_tokens",workspace="default",consumer="alice"} 600
+# HELP kong_nginx_connections_total Number of connections by subsystem
+# TYPE kong_nginx_connections_total gauge
+kong_nginx_connections_total{node_id="1",subsystem="http",state="active"} 1
+`
+	usage, err := parseAILLMUsage(strings.NewReader(metrics))
+	require.NoError(t, err)
+	require.Equal(t, map[aiLLMUsageKey]aiLLMUsage{
+		{provider: "openai", model: "gpt-4o", consumer: "alice"}: {Requests: 15, PromptTokens: 120, CompletionTokens: 480},
+		{provider: "openai", model: "gpt-4o", consumer: "bob"}:   {Requests: 2},
+		{provider: "mistral", model: "mistral-tiny"}:             {Requests: 1},
+	}, usage)
+
+	_, err = parseAILLMUsage(strings.NewReader("not metrics {"))
+	require.Error(t, err)
+}
+
+func TestAIGatewayGetLLMUsage(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai", UID: types.UID("gateway-uid")},
+	}
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "dp",
+			Labels:    map[string]string{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway", Name: "ai", UID: gateway.UID},
+			},
+		},
+	}
+	pod := func(name string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, UID: types.UID(name), Labels: map[string]string{"app": "dp"}},
+			Status: corev1.PodStatus{
+				PodIP:      "10.0.0.1",
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	key := aiLLMUsageKey{provider: "openai", model: "gpt-4o", consumer: "alice"}
+	podUsage := map[aiLLMUsageKey]aiLLMUsage{key: {Requests: 1, PromptTokens: 10, CompletionTokens: 20}}
+
+	testCases := []struct {
+		name         string
+		gateway      *gatewayv1.Gateway
+		pods         []*corev1.Pod
+		failing      []string
+		expected     map[types.UID]map[aiLLMUsageKey]aiLLMUsage
+		expectedUIDs []types.UID
+	}{
+		{
+			name:     "no gateway",
+			expected: nil,
+		},
+		{
+			name:         "usage of ready pods",
+			gateway:      gateway,
+			pods:         []*corev1.Pod{pod("a", true), pod("b", true), pod("not-ready", false)},
+			expected:     map[types.UID]map[aiLLMUsageKey]aiLLMUsage{"a": podUsage, "b": podUsage},
+			expectedUIDs: []types.UID{"a", "b", "not-ready"},
+		},
+		{
+			name:         "unreachable pods are skipped",
+			gateway:      gateway,
+			pods:         []*corev1.Pod{pod("a", true), pod("b", true)},
+			failing:      []string{"b"},
+			expected:     map[types.UID]map[aiLLMUsageKey]aiLLMUsage{"a": podUsage},
+			expectedUIDs: []types.UID{"a", "b"},
+		},
+		{
+			name:         "no pod reported",
+			gateway:      gateway,
+			pods:         []*corev1.Pod{pod("a", true)},
+			failing:      []string{"a"},
+			expected:     map[types.UID]map[aiLLMUsageKey]aiLLMUsage{},
+			expectedUIDs: []types.UID{"a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(dataplane)
+			for _, p := range tc.pods {
+				builder = builder.WithObjects(p)
+			}
+			r := &AIGatewayReconciler{
+				Client: builder.Build(),
+				llmUsageGetter: func(_ context.Context, pod *corev1.Pod) (map[aiLLMUsageKey]aiLLMUsage, error) {
+					for _, name := range tc.failing {
+						if pod.Name == name {
+							return nil, errors.New("connection refused")
+						}
+					}
+					return podUsage, nil
+				},
+			}
+			usage, uids, err := r.getLLMUsage(context.Background(), logr.Discard(), tc.gateway)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, usage)
+			require.ElementsMatch(t, tc.expectedUIDs, uids)
+		})
+	}
+}
+
+func TestAccumulateLLMUsage(t *testing.T) {
+	var (
+		alice    = aiLLMUsageKey{provider: "openai", model: "gpt-4o", consumer: "alice"}
+		bob      = aiLLMUsageKey{provider: "openai", model: "gpt-4o", consumer: "bob"}
+		mistral  = aiLLMUsageKey{provider: "mistral", model: "mistral-tiny", consumer: "alice"}
+		gpt      = newAIGatewayModelStatus("gpt").withUsageKeys([]aiLLMUsageKey{{provider: "openai", model: "gpt-4o"}})
+		noBudget = newAIGatewayModelStatus("mistral")
+		models   = []aiGatewayModelStatus{gpt, noBudget}
+	)
+
+	t.Log("first sync counts everything reported so far")
+	usage, counters := accumulateLLMUsage(aiGatewayUsage{}, nil, models,
+		map[types.UID]map[aiLLMUsageKey]aiLLMUsage{
+			"a": {alice: {Requests: 2, PromptTokens: 20, CompletionTokens: 40}, mistral: {Requests: 5}},
+			"b": {bob: {Requests: 1, PromptTokens: 10, CompletionTokens: 10}},
+		},
+		[]types.UID{"a", "b"},
+	)
+	require.Equal(t, map[string]map[string]aiLLMUsage{
+		"gpt": {
+			"alice": {Requests: 2, PromptTokens: 20, CompletionTokens: 40},
+			"bob":   {Requests: 1, PromptTokens: 10, CompletionTokens: 10},
+		},
+	}, usage.Models, "only models with a token budget are accounted")
+	require.Len(t, counters, 2)
+
+	t.Log("the usage since the previous sync is added, a restarted pod counts from zero")
+	usage, counters = accumulateLLMUsage(usage, counters, models,
+		map[types.UID]map[aiLLMUsageKey]aiLLMUsage{
+			"a": {alice: {Requests: 3, PromptTokens: 30, CompletionTokens: 60}},
+			"b": {bob: {Requests: 1, PromptTokens: 5, CompletionTokens: 5}},
+		},
+		[]types.UID{"a", "b"},
+	)
+	require.Equal(t, map[string]map[string]aiLLMUsage{
+		"gpt": {
+			"alice": {Requests: 3, PromptTokens: 30, CompletionTokens: 60},
+			"bob":   {Requests: 2, PromptTokens: 15, CompletionTokens: 15},
+		},
+	}, usage.Models)
+
+	t.Log("unreachable pods keep their counters, deleted pods are dropped")
+	usage, counters = accumulateLLMUsage(usage, counters, models,
+		map[types.UID]map[aiLLMUsageKey]aiLLMUsage{},
+		[]types.UID{"a"},
+	)
+	require.Contains(t, counters, types.UID("a"))
+	require.NotContains(t, counters, types.UID("b"))
+	require.Equal(t, aiLLMUsage{Requests: 3, PromptTokens: 30, CompletionTokens: 60}, usage.Models["gpt"]["alice"])
+
+	t.Log("models never reported aren't present")
+	usage, _ = accumulateLLMUsage(aiGatewayUsage{}, nil, models, nil, nil)
+	require.Empty(t, usage.Models)
+}
+
+func TestAIGatewaySyncLLMUsage(t *testing.T) {
+	aigateway := &operatorv1alpha1.AIGateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai", UID: types.UID("aigateway-uid")},
+	}
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai", UID: types.UID("gateway-uid")},
+	}
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "dp",
+			Labels:    map[string]string{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway", Name: "ai", UID: gateway.UID},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "a", UID: types.UID("a"), Labels: map[string]string{"app": "dp"}},
+		Status: corev1.PodStatus{
+			PodIP:      "10.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	key := aiLLMUsageKey{provider: "openai", model: "gpt-4o", consumer: "alice"}
+	models := func() []aiGatewayModelStatus {
+		return []aiGatewayModelStatus{
+			newAIGatewayModelStatus("gpt").withUsageKeys([]aiLLMUsageKey{{provider: "openai", model: "gpt-4o"}}),
+		}
+	}
+
+	var scrapes int
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(dataplane, pod).
+		Build()
+	r := &AIGatewayReconciler{
+		Client: cl,
+		llmUsageGetter: func(_ context.Context, _ *corev1.Pod) (map[aiLLMUsageKey]aiLLMUsage, error) {
+			scrapes++
+			return map[aiLLMUsageKey]aiLLMUsage{key: {Requests: 1, PromptTokens: 10, CompletionTokens: 20}}, nil
+		},
+	}
+
+	synced, after, err := r.syncLLMUsage(context.Background(), logr.Discard(), aigateway, gateway, models())
+	require.NoError(t, err)
+	require.Equal(t, aiGatewayUsageSyncInterval, after)
+	require.Equal(t, 1, scrapes)
+	require.NotNil(t, synced[0].usageReported)
+	require.Equal(t, metav1.ConditionTrue, synced[0].usageReported.Status)
+	require.Equal(t, "usage of the model per consumer is reported in ConfigMap ai-usage", synced[0].usageReported.Message)
+
+	var cm corev1.ConfigMap
+	require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "ai-usage"}, &cm))
+	require.Equal(t, aigateway.UID, cm.OwnerReferences[0].UID)
+	var usage aiGatewayUsage
+	require.NoError(t, json.Unmarshal([]byte(cm.Data[aiGatewayUsageKey]), &usage))
+	require.Equal(t, map[string]map[string]aiLLMUsage{
+		"gpt": {"alice": {Requests: 1, PromptTokens: 10, CompletionTokens: 20}},
+	}, usage.Models)
+
+	t.Log("the pods aren't queried again before the sync interval elapsed")
+	synced, after, err = r.syncLLMUsage(context.Background(), logr.Discard(), aigateway, gateway, models())
+	require.NoError(t, err)
+	require.Equal(t, 1, scrapes)
+	require.Positive(t, after)
+	require.LessOrEqual(t, after, aiGatewayUsageSyncInterval)
+	require.Equal(t, metav1.ConditionTrue, synced[0].usageReported.Status)
+
+	t.Log("the pods are queried again once the sync interval elapsed")
+	usage.SyncedAt = metav1.NewTime(usage.SyncedAt.Add(-aiGatewayUsageSyncInterval))
+	raw, err := json.Marshal(usage)
+	require.NoError(t, err)
+	cm.Data[aiGatewayUsageKey] = string(raw)
+	require.NoError(t, cl.Update(context.Background(), &cm))
+	_, _, err = r.syncLLMUsage(context.Background(), logr.Discard(), aigateway, gateway, models())
+	require.NoError(t, err)
+	require.Equal(t, 2, scrapes)
+	require.NoError(t, cl.Get(context.Background(), client.ObjectKeyFromObject(&cm), &cm))
+	require.NoError(t, json.Unmarshal([]byte(cm.Data[aiGatewayUsageKey]), &usage))
+	require.Equal(t, aiLLMUsage{Requests: 1, PromptTokens: 10, CompletionTokens: 20}, usage.Models["gpt"]["alice"],
+		"unchanged counters don't add any usage")
+}
+
+func TestAIGatewayModelStatusWithUsage(t *testing.T) {
+	key := aiLLMUsageKey{provider: "openai", model: "gpt-4o"}
+
+	model := newAIGatewayModelStatus("gpt").withUsage(true, "ai-usage")
+	require.Nil(t, model.usageReported, "models without a token budget don't report usage")
+
+	model = newAIGatewayModelStatus("gpt").withUsageKeys([]aiLLMUsageKey{key}).withUsage(true, "ai-usage")
+	require.NotNil(t, model.usageReported)
+	require.Equal(t, metav1.ConditionTrue, model.usageReported.Status)
+	require.Equal(t, AIGatewayEndpointConditionReasonReported, model.usageReported.Reason)
+	require.Equal(t, "usage of the model per consumer is reported in ConfigMap ai-usage", model.usageReported.Message)
+
+	model = newAIGatewayModelStatus("gpt").withUsageKeys([]aiLLMUsageKey{key}).withUsage(false, "ai-usage")
+	require.NotNil(t, model.usageReported)
+	require.Equal(t, metav1.ConditionUnknown, model.usageReported.Status)
+	require.Equal(t, AIGatewayEndpointConditionReasonNotReported, model.usageReported.Reason)
+}
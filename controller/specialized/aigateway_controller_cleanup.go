@@ -0,0 +1,179 @@
+package specialized
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/pkg/clientops"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGatewayReconciler - Pruning
+// -----------------------------------------------------------------------------
+
+// aiGatewayGeneratedResources records the HTTPRoutes and KongPlugins generated
+// for the models of an AIGateway during a reconciliation.
+type aiGatewayGeneratedResources struct {
+	// models are the identifiers of the models whose resources were generated.
+	models  sets.Set[string]
+	routes  sets.Set[string]
+	plugins sets.Set[string]
+}
+
+func newAIGatewayGeneratedResources() *aiGatewayGeneratedResources {
+	return &aiGatewayGeneratedResources{
+		models:  sets.New[string](),
+		routes:  sets.New[string](),
+		plugins: sets.New[string](),
+	}
+}
+
+// add records the HTTPRoute and the plugins generated for the model with the
+// provided identifier.
+func (g *aiGatewayGeneratedResources) add(identifier string, route *gatewayv1.HTTPRoute, plugins ...string) {
+	g.models.Insert(identifier)
+	g.routes.Insert(route.Name)
+	g.plugins.Insert(plugins...)
+}
+
+// pruneModelResources deletes the HTTPRoutes and KongPlugins generated for the
+// AIGateway which are no longer used. identifiers are the identifiers of all
+// the models of the AIGateway.
+func (r *AIGatewayReconciler) pruneModelResources(
+	ctx context.Context,
+	logger logr.Logger,
+	aiGateway *operatorv1alpha1.AIGateway,
+	identifiers []string,
+	generated *aiGatewayGeneratedResources,
+) (
+	bool, // whether any changes were made
+	error,
+) {
+	matchingLabels := client.MatchingLabels(aiGatewayResourceLabels(aiGateway, ""))
+
+	var httpRoutes gatewayv1.HTTPRouteList
+	if err := r.Client.List(ctx, &httpRoutes, matchingLabels); err != nil {
+		return false, fmt.Errorf("failed listing httproutes for aigateway: %w", err)
+	}
+	var kongPlugins configurationv1.KongPluginList
+	if err := r.Client.List(ctx, &kongPlugins, matchingLabels); err != nil {
+		return false, fmt.Errorf("failed listing plugins for aigateway: %w", err)
+	}
+
+	// routes are deleted first so that they never reference a deleted plugin.
+	staleRoutes := filterStaleModelResources(httpRoutes.Items, identifiers, generated.models, generated.routes)
+	if err := clientops.DeleteAll(ctx, r.Client, staleRoutes); err != nil {
+		return false, err
+	}
+	stalePlugins := filterStaleModelResources(kongPlugins.Items, identifiers, generated.models, generated.plugins)
+	if err := clientops.DeleteAll(ctx, r.Client, stalePlugins); err != nil {
+		return len(staleRoutes) > 0, err
+	}
+
+	if len(staleRoutes) == 0 && len(stalePlugins) == 0 {
+		return false, nil
+	}
+	log.Info(logger, "pruned routes and plugins no longer used by aigateway",
+		"httproutes", len(staleRoutes), "plugins", len(stalePlugins))
+	return true, nil
+}
+
+// filterStaleModelResources returns the resources, generated for the models of
+// an AIGateway, which are no longer used: the ones of the models which are not
+// among the provided identifiers anymore and the ones of the models in
+// generatedModels which are not among the generated names. The resources of
+// models which couldn't be configured during the reconciliation are kept.
+func filterStaleModelResources[
+	T any,
+	TPtr interface {
+		*T
+		client.Object
+	},
+](objs []T, identifiers []string, generatedModels sets.Set[string], generatedNames sets.Set[string]) []T {
+	models := sets.New(identifiers...)
+	var stale []T
+	for _, obj := range objs {
+		var objPtr TPtr = &obj
+		model, ok := objPtr.GetLabels()[consts.AIGatewayModelLabel]
+		if !ok {
+			continue
+		}
+		if !models.Has(model) || (generatedModels.Has(model) && !generatedNames.Has(objPtr.GetName())) {
+			stale = append(stale, obj)
+		}
+	}
+	return stale
+}
+
+// -----------------------------------------------------------------------------
+// AIGatewayReconciler - Cleanup
+// -----------------------------------------------------------------------------
+
+// cleanup deletes all the resources generated for an AIGateway which is being
+// deleted and removes its cleanup finalizer once they're gone. The resources
+// are found through their labels rather than their owner references so that
+// resources in other namespaces than the AIGateway's are cleaned up as well.
+func (r *AIGatewayReconciler) cleanup(
+	ctx context.Context,
+	logger logr.Logger,
+	aiGateway *operatorv1alpha1.AIGateway,
+) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(aiGateway, string(AIGatewayCleanupFinalizer)) {
+		log.Debug(logger, "aigateway is being deleted")
+		return ctrl.Result{}, nil
+	}
+
+	matchingLabels := client.MatchingLabels(aiGatewayResourceLabels(aiGateway, ""))
+	var (
+		httpRoutes  gatewayv1.HTTPRouteList
+		kongPlugins configurationv1.KongPluginList
+		gateways    gatewayv1.GatewayList
+		services    corev1.ServiceList
+		secrets     corev1.SecretList
+	)
+	for _, list := range []client.ObjectList{&httpRoutes, &kongPlugins, &gateways, &services, &secrets} {
+		if err := r.Client.List(ctx, list, matchingLabels); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed listing resources of aigateway for cleanup: %w", err)
+		}
+	}
+
+	remaining := len(httpRoutes.Items) + len(kongPlugins.Items) + len(gateways.Items) + len(services.Items) + len(secrets.Items)
+	if remaining > 0 {
+		for _, deleteAll := range []func() error{
+			func() error { return clientops.DeleteAll(ctx, r.Client, httpRoutes.Items) },
+			func() error { return clientops.DeleteAll(ctx, r.Client, kongPlugins.Items) },
+			func() error { return clientops.DeleteAll(ctx, r.Client, gateways.Items) },
+			func() error { return clientops.DeleteAll(ctx, r.Client, services.Items) },
+			func() error { return clientops.DeleteAll(ctx, r.Client, secrets.Items) },
+		} {
+			if err := deleteAll(); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		log.Debug(logger, "deleting resources of aigateway", "remaining", remaining)
+		// Requeue until all the resources, some of which have finalizers of their
+		// own, are gone.
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	oldAIGateway := aiGateway.DeepCopy()
+	controllerutil.RemoveFinalizer(aiGateway, string(AIGatewayCleanupFinalizer))
+	if err := r.Client.Patch(ctx, aiGateway, client.MergeFrom(oldAIGateway)); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	log.Debug(logger, "finalizer for cleaning up aigateway resources removed")
+	return ctrl.Result{}, nil
+}
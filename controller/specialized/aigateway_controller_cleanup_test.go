@@ -0,0 +1,98 @@
+package specialized
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+)
+
+func TestFilterStaleModelResources(t *testing.T) {
+	aigw := aiGatewayWithBackends("")
+	plugin := func(name, identifier string) configurationv1.KongPlugin {
+		return configurationv1.KongPlugin{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns",
+				Name:      name,
+				Labels:    aiGatewayResourceLabels(aigw, identifier),
+			},
+		}
+	}
+	plugins := []configurationv1.KongPlugin{
+		plugin("gpt-ai-proxy", "gpt"),
+		plugin("gpt-ai-proxy-advanced", "gpt"),
+		plugin("gpt-ai-prompt-decorator", "gpt"),
+		plugin("removed-ai-proxy", "removed"),
+		plugin("failing-ai-proxy", "failing"),
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "unlabeled"}},
+	}
+
+	stale := filterStaleModelResources(
+		plugins,
+		[]string{"gpt", "failing"},
+		sets.New("gpt"),
+		sets.New("gpt-ai-proxy", "gpt-ai-prompt-decorator"),
+	)
+	names := make([]string, 0, len(stale))
+	for _, p := range stale {
+		names = append(names, p.Name)
+	}
+	require.ElementsMatch(t, []string{"gpt-ai-proxy-advanced", "removed-ai-proxy"}, names,
+		"plugins of removed models and the ones not generated anymore are stale, the ones of models which couldn't be configured are kept")
+}
+
+func TestAIGatewayCleanup(t *testing.T) {
+	aigw := aiGatewayWithBackends("")
+	aigw.Finalizers = []string{string(AIGatewayCleanupFinalizer)}
+	aigw.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "other",
+			Name:      "gpt-egress",
+			Labels:    aiGatewayResourceLabels(aigw, "gpt"),
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "ai-ai-credentials",
+			Labels:    aiGatewayResourceLabels(aigw, ""),
+		},
+	}
+	unrelated := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "credentials"},
+	}
+	r := &AIGatewayReconciler{
+		Client: fakectrlruntimeclient.NewClientBuilder().
+			WithScheme(scheme.Get()).
+			WithObjects(aigw, route, secret, unrelated).
+			Build(),
+	}
+	ctx := context.Background()
+
+	t.Log("the resources of the aigateway are deleted in all namespaces")
+	res, err := r.cleanup(ctx, logr.Discard(), aigw)
+	require.NoError(t, err)
+	require.True(t, res.Requeue)
+	require.Error(t, r.Client.Get(ctx, client.ObjectKeyFromObject(route), &gatewayv1.HTTPRoute{}))
+	require.Error(t, r.Client.Get(ctx, client.ObjectKeyFromObject(secret), &corev1.Secret{}))
+	require.NoError(t, r.Client.Get(ctx, client.ObjectKeyFromObject(unrelated), &corev1.Secret{}))
+
+	t.Log("the finalizer is removed once all the resources are gone")
+	res, err = r.cleanup(ctx, logr.Discard(), aigw)
+	require.NoError(t, err)
+	require.False(t, res.Requeue)
+	require.Empty(t, aigw.Finalizers)
+}
@@ -1,8 +1,12 @@
 package specialized
 
 import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
 )
@@ -24,3 +28,57 @@ func newAIGatewayAcceptedCondition(obj client.Object) metav1.Condition {
 		LastTransitionTime: metav1.Now(),
 	}
 }
+
+// newAIGatewayRejectedCondition returns a new Accepted condition for the
+// AIGateway resource to indicate to the user that the controller rejected the
+// resource because of its invalid configuration.
+func newAIGatewayRejectedCondition(obj client.Object, message string) metav1.Condition {
+	return newAIGatewayCondition(obj, operatorv1alpha1.AIGatewayConditionTypeAccepted,
+		metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonRejected, message,
+	)
+}
+
+// newAIGatewayCondition returns a new condition of the provided type for the
+// AIGateway resource.
+func newAIGatewayCondition(
+	obj client.Object,
+	conditionType string,
+	status metav1.ConditionStatus,
+	reason string,
+	message string,
+) metav1.Condition {
+	return metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: obj.GetGeneration(),
+		LastTransitionTime: metav1.Now(),
+	}
+}
+
+// newAIGatewayGatewayProgrammedCondition returns a new GatewayProgrammed
+// condition for the AIGateway resource which mirrors the Programmed condition
+// of the provided Gateway. The Gateway is nil when it doesn't exist yet.
+func newAIGatewayGatewayProgrammedCondition(obj client.Object, gateway *gatewayv1.Gateway) metav1.Condition {
+	if gateway == nil {
+		return newAIGatewayCondition(obj, AIGatewayConditionTypeGatewayProgrammed,
+			metav1.ConditionUnknown, operatorv1alpha1.AIGatewayConditionReasonPending,
+			"waiting for the gateway to be created",
+		)
+	}
+	programmed := meta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+	if programmed == nil || programmed.ObservedGeneration != gateway.Generation {
+		return newAIGatewayCondition(obj, AIGatewayConditionTypeGatewayProgrammed,
+			metav1.ConditionUnknown, operatorv1alpha1.AIGatewayConditionReasonPending,
+			fmt.Sprintf("waiting for gateway '%s' to be programmed", gateway.Name),
+		)
+	}
+	message := programmed.Message
+	if message == "" {
+		message = fmt.Sprintf("gateway '%s' programmed condition is %s", gateway.Name, programmed.Status)
+	}
+	return newAIGatewayCondition(obj, AIGatewayConditionTypeGatewayProgrammed,
+		programmed.Status, programmed.Reason, message,
+	)
+}
@@ -19,3 +19,102 @@ const (
 	// resources in an AIGateway.
 	AIGatewayEgressServicePort int = 80
 )
+
+// -----------------------------------------------------------------------------
+// AIGateway - Status Conditions
+// -----------------------------------------------------------------------------
+
+const (
+	// AIGatewayConditionTypeGatewayProgrammed mirrors the Programmed condition
+	// of the Gateway which serves the AIGateway.
+	AIGatewayConditionTypeGatewayProgrammed string = "GatewayProgrammed"
+
+	// AIGatewayEndpointConditionTypeCredentialsResolved indicates whether the
+	// credentials of the endpoint's model could be resolved: the cloud provider
+	// API key for cloud hosted models and the optional credentials Secret for
+	// self-hosted ones.
+	AIGatewayEndpointConditionTypeCredentialsResolved string = "CredentialsResolved"
+
+	// AIGatewayEndpointConditionTypePluginConfigured indicates whether the
+	// KongPlugins proxying the endpoint's model have been configured.
+	AIGatewayEndpointConditionTypePluginConfigured string = "PluginConfigured"
+
+	// AIGatewayEndpointConditionTypeRouteCreated indicates whether the
+	// HTTPRoute exposing the endpoint's model has been created.
+	AIGatewayEndpointConditionTypeRouteCreated string = "RouteCreated"
+
+	// AIGatewayEndpointConditionTypeBackendsCredentialsResolved indicates, for
+	// models load balanced across several backends, whether the credentials of
+	// all the backends have been resolved. Backends without credentials are left
+	// out of the load balancing. Its message lists the state of every backend.
+	AIGatewayEndpointConditionTypeBackendsCredentialsResolved string = "BackendsCredentialsResolved"
+
+	// AIGatewayEndpointConditionTypeUsageReported indicates, for models with a
+	// token budget, whether the DataPlane reported the usage of the model. The
+	// requests and the tokens used by every consumer are accumulated in the
+	// AIGateway's usage ConfigMap named in its message.
+	AIGatewayEndpointConditionTypeUsageReported string = "UsageReported"
+)
+
+const (
+	// AIGatewayEndpointConditionReasonPending is used when a condition can't be
+	// satisfied until a previous step of the endpoint's configuration succeeds.
+	AIGatewayEndpointConditionReasonPending string = "Pending"
+
+	// AIGatewayEndpointConditionReasonResolved is used when the credentials of
+	// the model have been resolved.
+	AIGatewayEndpointConditionReasonResolved string = "Resolved"
+
+	// AIGatewayEndpointConditionReasonMissingCredentialsRef is used when the
+	// AIGateway does not reference a credentials Secret.
+	AIGatewayEndpointConditionReasonMissingCredentialsRef string = "MissingCredentialsRef"
+
+	// AIGatewayEndpointConditionReasonRefNotPermitted is used when referencing
+	// the credentials Secret is not permitted by any ReferenceGrant.
+	AIGatewayEndpointConditionReasonRefNotPermitted string = "RefNotPermitted"
+
+	// AIGatewayEndpointConditionReasonSecretNotFound is used when the
+	// credentials Secret does not exist.
+	AIGatewayEndpointConditionReasonSecretNotFound string = "SecretNotFound"
+
+	// AIGatewayEndpointConditionReasonMissingProviderKey is used when the
+	// credentials Secret has no API key for the model's cloud provider.
+	AIGatewayEndpointConditionReasonMissingProviderKey string = "MissingProviderKey"
+
+	// AIGatewayEndpointConditionReasonMissingSecretKey is used when the Secret
+	// referenced by a self-hosted LLM has no credentials under the configured key.
+	AIGatewayEndpointConditionReasonMissingSecretKey string = "MissingSecretKey"
+
+	// AIGatewayEndpointConditionReasonConfigured is used when the KongPlugins
+	// of the endpoint have been configured.
+	AIGatewayEndpointConditionReasonConfigured string = "Configured"
+
+	// AIGatewayEndpointConditionReasonInvalidConfiguration is used when the
+	// model's configuration can't be translated into KongPlugins.
+	AIGatewayEndpointConditionReasonInvalidConfiguration string = "InvalidConfiguration"
+
+	// AIGatewayEndpointConditionReasonBackendsResolved is used when the
+	// credentials of all the backends of a model have been resolved.
+	AIGatewayEndpointConditionReasonBackendsResolved string = "BackendsResolved"
+
+	// AIGatewayEndpointConditionReasonBackendsPartiallyResolved is used when the
+	// credentials of some of the backends of a model can't be resolved and
+	// requests are balanced across the others.
+	AIGatewayEndpointConditionReasonBackendsPartiallyResolved string = "BackendsPartiallyResolved"
+
+	// AIGatewayEndpointConditionReasonBackendsUnresolved is used when the
+	// credentials of none of the backends of a model can be resolved.
+	AIGatewayEndpointConditionReasonBackendsUnresolved string = "BackendsUnresolved"
+
+	// AIGatewayEndpointConditionReasonCreated is used when the HTTPRoute of the
+	// endpoint has been created.
+	AIGatewayEndpointConditionReasonCreated string = "Created"
+
+	// AIGatewayEndpointConditionReasonReported is used when the usage of the
+	// model has been reported by the DataPlane.
+	AIGatewayEndpointConditionReasonReported string = "Reported"
+
+	// AIGatewayEndpointConditionReasonNotReported is used when none of the
+	// DataPlane Pods reported the usage of the model.
+	AIGatewayEndpointConditionReasonNotReported string = "NotReported"
+)
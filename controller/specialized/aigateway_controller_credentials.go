@@ -0,0 +1,144 @@
+package specialized
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGateway - Plugin Credentials
+// -----------------------------------------------------------------------------
+
+// aiGatewayCredentials collects the auth header values of the AI proxy and
+// semantic cache plugins of an AIGateway. They are stored in a Secret managed
+// by the operator and referenced by the plugins through config patches so that
+// the KongPlugins never contain the API keys. Rotating a key only updates that
+// Secret, the KongPlugins referencing it don't change.
+type aiGatewayCredentials struct {
+	aigateway *operatorv1alpha1.AIGateway
+	data      map[string][]byte
+}
+
+func newAIGatewayCredentials(aigateway *operatorv1alpha1.AIGateway) *aiGatewayCredentials {
+	return &aiGatewayCredentials{
+		aigateway: aigateway,
+		data:      map[string][]byte{},
+	}
+}
+
+// aiGatewayCredentialsSecretName returns the name of the Secret holding the
+// credentials of the AIGateway's plugins.
+func aiGatewayCredentialsSecretName(aigateway *operatorv1alpha1.AIGateway) string {
+	return fmt.Sprintf("%s-ai-credentials", aigateway.Name)
+}
+
+// externalize moves the auth header values out of the configuration of the
+// provided AI proxy or semantic cache plugin into the credentials and replaces them with config
+// patches referencing the credentials Secret.
+func (c *aiGatewayCredentials) externalize(plugin *configurationv1.KongPlugin) error {
+	var (
+		config  any
+		patches []configurationv1.ConfigPatch
+	)
+	switch plugin.PluginName {
+	case "ai-proxy":
+		var aiProxyConfig AICloudProviderLLMConfig
+		if err := json.Unmarshal(plugin.Config.Raw, &aiProxyConfig); err != nil {
+			return fmt.Errorf("failed to parse configuration of plugin %s: %w", plugin.Name, err)
+		}
+		if patch, ok := c.externalizeAuth(aiProxyConfig.Auth, plugin.Name+".auth", "/auth/header_value"); ok {
+			patches = append(patches, patch)
+		}
+		config = aiProxyConfig
+
+	case "ai-proxy-advanced":
+		var aiProxyAdvancedConfig AIProxyAdvancedConfig
+		if err := json.Unmarshal(plugin.Config.Raw, &aiProxyAdvancedConfig); err != nil {
+			return fmt.Errorf("failed to parse configuration of plugin %s: %w", plugin.Name, err)
+		}
+		for i := range aiProxyAdvancedConfig.Targets {
+			patch, ok := c.externalizeAuth(
+				aiProxyAdvancedConfig.Targets[i].Auth,
+				fmt.Sprintf("%s.targets.%d.auth", plugin.Name, i),
+				fmt.Sprintf("/targets/%d/auth/header_value", i),
+			)
+			if ok {
+				patches = append(patches, patch)
+			}
+		}
+		config = aiProxyAdvancedConfig
+
+	case "ai-semantic-cache":
+		var aiSemanticCacheConfig AISemanticCacheConfig
+		if err := json.Unmarshal(plugin.Config.Raw, &aiSemanticCacheConfig); err != nil {
+			return fmt.Errorf("failed to parse configuration of plugin %s: %w", plugin.Name, err)
+		}
+		patch, ok := c.externalizeAuth(
+			aiSemanticCacheConfig.Embeddings.Auth, plugin.Name+".embeddings.auth", "/embeddings/auth/header_value",
+		)
+		if ok {
+			patches = append(patches, patch)
+		}
+		config = aiSemanticCacheConfig
+
+	default:
+		return nil
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration of plugin %s: %w", plugin.Name, err)
+	}
+	plugin.Config.Raw = raw
+	plugin.ConfigPatches = patches
+	return nil
+}
+
+// externalizeAuth stores the header value of the provided auth configuration
+// under the provided key and returns the config patch setting it back at the
+// provided JSON pointer. It returns false when there is no header value.
+func (c *aiGatewayCredentials) externalizeAuth(
+	auth *AICloudProviderAuthConfig,
+	key string,
+	path string,
+) (configurationv1.ConfigPatch, bool) {
+	if auth == nil || auth.HeaderValue == nil {
+		return configurationv1.ConfigPatch{}, false
+	}
+	c.data[key] = []byte(*auth.HeaderValue)
+	auth.HeaderValue = nil
+	return configurationv1.ConfigPatch{
+		Path: path,
+		ValueFrom: configurationv1.ConfigSource{
+			SecretValue: configurationv1.SecretValueFromSource{
+				Secret: aiGatewayCredentialsSecretName(c.aigateway),
+				Key:    key,
+			},
+		},
+	}, true
+}
+
+// secret produces the Secret holding the collected credentials.
+func (c *aiGatewayCredentials) secret() *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      aiGatewayCredentialsSecretName(c.aigateway),
+			Namespace: c.aigateway.Namespace,
+			Labels:    aiGatewayResourceLabels(c.aigateway, ""),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: c.data,
+	}
+
+	k8sutils.SetOwnerForObject(secret, c.aigateway)
+
+	return secret
+}
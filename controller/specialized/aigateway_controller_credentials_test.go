@@ -0,0 +1,108 @@
+package specialized
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+func TestAIGatewayCredentialsExternalize(t *testing.T) {
+	aigw := aiGatewayWithBackends("")
+	llm := &aigw.Spec.LargeLanguageModels.CloudHosted[0]
+
+	t.Log("the API key of an ai-proxy plugin is moved to the credentials secret")
+	credentials := newAIGatewayCredentials(aigw)
+	plugin, err := aiCloudGatewayToKongPlugin(llm, aigw, lo.ToPtr([]byte("openai-key")))
+	require.NoError(t, err)
+	require.NoError(t, credentials.externalize(plugin))
+	require.NotContains(t, string(plugin.Config.Raw), "openai-key")
+
+	var config AICloudProviderLLMConfig
+	require.NoError(t, json.Unmarshal(plugin.Config.Raw, &config))
+	require.Equal(t, "Authorization", *config.Auth.HeaderName)
+	require.Nil(t, config.Auth.HeaderValue)
+	require.Equal(t, []configurationv1.ConfigPatch{
+		{
+			Path: "/auth/header_value",
+			ValueFrom: configurationv1.ConfigSource{
+				SecretValue: configurationv1.SecretValueFromSource{Secret: "ai-ai-credentials", Key: "gpt-ai-proxy.auth"},
+			},
+		},
+	}, plugin.ConfigPatches)
+
+	secret := credentials.secret()
+	require.Equal(t, "ai-ai-credentials", secret.Name)
+	require.Equal(t, "ns", secret.Namespace)
+	require.Equal(t, consts.AIGatewayManagedLabelValue, secret.Labels[consts.GatewayOperatorManagedByLabel])
+	require.Equal(t, map[string][]byte{"gpt-ai-proxy.auth": []byte("Bearer openai-key")}, secret.Data)
+
+	t.Log("the API keys of the targets of an ai-proxy-advanced plugin are moved to the credentials secret")
+	credentials = newAIGatewayCredentials(aigw)
+	plugin, err = aiCloudGatewayToKongProxyAdvancedPlugin(llm, &AIGatewayModelBackends{Identifier: "gpt"}, aigw, []aiGatewayBackendTarget{
+		{name: AIGatewayPrimaryBackendName, provider: operatorv1alpha1.AICloudProviderOpenAI, model: llm.Model, credentialData: []byte("openai-key")},
+		{name: "mistral", provider: operatorv1alpha1.AICloudProviderMistral, model: lo.ToPtr("mistral-large"), credentialData: []byte("mistral-key")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, credentials.externalize(plugin))
+	require.NotContains(t, string(plugin.Config.Raw), "openai-key")
+	require.NotContains(t, string(plugin.Config.Raw), "mistral-key")
+	require.Len(t, plugin.ConfigPatches, 2)
+	require.Equal(t, "/targets/1/auth/header_value", plugin.ConfigPatches[1].Path)
+	require.Equal(t, "gpt-ai-proxy-advanced.targets.1.auth", plugin.ConfigPatches[1].ValueFrom.SecretValue.Key)
+	require.Equal(t, []byte("Bearer mistral-key"), credentials.secret().Data["gpt-ai-proxy-advanced.targets.1.auth"])
+
+	t.Log("plugins without credentials are left untouched")
+	credentials = newAIGatewayCredentials(aigw)
+	plugin, err = aiSelfHostedLLMToKongPlugin(&SelfHostedLargeLanguageModel{
+		Identifier: "llama",
+		PromptType: lo.ToPtr(operatorv1alpha1.LLMPromptTypeChat),
+		Format:     SelfHostedLLMFormatOpenAI,
+		Upstream: SelfHostedLLMUpstream{
+			Service: SelfHostedLLMServiceRef{Name: "vllm", Namespace: "ns", Port: 8000},
+			Scheme:  "http",
+		},
+	}, aigw, nil)
+	require.NoError(t, err)
+	require.NoError(t, credentials.externalize(plugin))
+	require.Empty(t, plugin.ConfigPatches)
+	require.Empty(t, credentials.secret().Data)
+}
+
+func TestAIGatewayReferencesSecret(t *testing.T) {
+	aigw := aiGatewayWithBackends(`[{"identifier":"gpt","backends":[{"name":"azure","provider":"azure","azure":{"instance":"west","deploymentID":"gpt-4o"},"secretRef":{"name":"azure","namespace":"keys","key":"api-key"}}]}]`)
+	aigw.Annotations[consts.AIGatewaySelfHostedLLMsAnnotation] = `[{"identifier":"llama","upstream":{"service":{"name":"vllm","port":8000}},"auth":{"secretRef":{"name":"vllm-token","key":"token"}}}]`
+
+	testCases := []struct {
+		name      string
+		namespace string
+		secret    string
+		expected  bool
+	}{
+		{name: "cloud provider credentials", namespace: "ns", secret: "credentials", expected: true},
+		{name: "model backend secret", namespace: "keys", secret: "azure", expected: true},
+		{name: "self-hosted llm secret", namespace: "ns", secret: "vllm-token", expected: true},
+		{name: "secret in another namespace", namespace: "other", secret: "credentials"},
+		{name: "unrelated secret", namespace: "ns", secret: "other"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, aiGatewayReferencesSecret(aigw, tc.namespace, tc.secret))
+		})
+	}
+
+	t.Log("the credentials secrets managed by the aigateway controller are not watched")
+	require.False(t, secretIsNotManagedByAIGateway(&metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{consts.GatewayOperatorManagedByLabel: consts.AIGatewayManagedLabelValue},
+		},
+	}))
+}
@@ -55,6 +55,123 @@ type AICloudProviderModelConfig struct {
 // AICloudProviderOptionsConfig is a Golang-conversion of the 'Options' configuration
 // for the AI family of Kong plugins.
 type AICloudProviderOptionsConfig struct {
-	MaxTokens   *int    `json:"max_tokens,omitempty"`
-	Temperature *string `json:"temperature,omitempty"`
+	MaxTokens    *int    `json:"max_tokens,omitempty"`
+	Temperature  *string `json:"temperature,omitempty"`
+	UpstreamURL  *string `json:"upstream_url,omitempty"`
+	Llama2Format *string `json:"llama2_format,omitempty"`
+
+	AzureInstance     *string `json:"azure_instance,omitempty"`
+	AzureDeploymentID *string `json:"azure_deployment_id,omitempty"`
+	AzureAPIVersion   *string `json:"azure_api_version,omitempty"`
+}
+
+// AIProxyAdvancedConfig is a Golang-conversion of the 'AI Proxy Advanced' plugin
+// configuration, from the AI family of Kong plugins.
+type AIProxyAdvancedConfig struct {
+	Balancer *AIProxyAdvancedBalancerConfig `json:"balancer,omitempty"`
+	Targets  []AIProxyAdvancedTargetConfig  `json:"targets"`
+}
+
+// AIProxyAdvancedBalancerConfig is a Golang-conversion of the 'Balancer'
+// configuration of the 'AI Proxy Advanced' plugin.
+type AIProxyAdvancedBalancerConfig struct {
+	Algorithm        *string  `json:"algorithm,omitempty"`
+	Retries          *int     `json:"retries,omitempty"`
+	FailoverCriteria []string `json:"failover_criteria,omitempty"`
+	ConnectTimeout   *int     `json:"connect_timeout,omitempty"`
+	ReadTimeout      *int     `json:"read_timeout,omitempty"`
+	WriteTimeout     *int     `json:"write_timeout,omitempty"`
+}
+
+// AIProxyAdvancedTargetConfig is a Golang-conversion of a 'Target' of the
+// 'AI Proxy Advanced' plugin: an LLM configuration with a balancer weight.
+type AIProxyAdvancedTargetConfig struct {
+	AICloudProviderLLMConfig
+
+	Weight      *int    `json:"weight,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// AIRateLimitingAdvancedConfig is a Golang-conversion of the 'AI Rate Limiting
+// Advanced' plugin configuration, from the AI family of Kong plugins.
+type AIRateLimitingAdvancedConfig struct {
+	Identifier          string                                    `json:"identifier"`
+	Strategy            string                                    `json:"strategy"`
+	TokensCountStrategy string                                    `json:"tokens_count_strategy"`
+	LLMProviders        []AIRateLimitingAdvancedLLMProviderConfig `json:"llm_providers"`
+}
+
+// AIRateLimitingAdvancedLLMProviderConfig is a Golang-conversion of the limits
+// of an 'LLM Provider' of the 'AI Rate Limiting Advanced' plugin.
+type AIRateLimitingAdvancedLLMProviderConfig struct {
+	Name       string `json:"name"`
+	Limit      []int  `json:"limit"`
+	WindowSize []int  `json:"window_size"`
+}
+
+// RateLimitingAdvancedConfig is a Golang-conversion of the 'Rate Limiting
+// Advanced' plugin configuration.
+type RateLimitingAdvancedConfig struct {
+	Identifier string `json:"identifier"`
+	Strategy   string `json:"strategy"`
+	Limit      []int  `json:"limit"`
+	WindowSize []int  `json:"window_size"`
+}
+
+// PrometheusConfig is a Golang-conversion of the 'Prometheus' plugin
+// configuration.
+type PrometheusConfig struct {
+	AIMetrics   bool `json:"ai_metrics"`
+	PerConsumer bool `json:"per_consumer"`
+}
+
+// AIPromptGuardConfig is a Golang-conversion of the 'AI Prompt Guard' plugin
+// configuration, from the AI family of Kong plugins.
+type AIPromptGuardConfig struct {
+	AllowPatterns               []string `json:"allow_patterns,omitempty"`
+	DenyPatterns                []string `json:"deny_patterns,omitempty"`
+	AllowAllConversationHistory bool     `json:"allow_all_conversation_history"`
+	MatchAllRoles               bool     `json:"match_all_roles"`
+}
+
+// AISanitizerConfig is a Golang-conversion of the 'AI Sanitizer' plugin
+// configuration, from the AI family of Kong plugins.
+type AISanitizerConfig struct {
+	Anonymize       []string `json:"anonymize"`
+	RedactType      string   `json:"redact_type"`
+	RecoverRedacted bool     `json:"recover_redacted"`
+	Host            *string  `json:"host,omitempty"`
+	Port            *int     `json:"port,omitempty"`
+}
+
+// AISemanticCacheConfig is a Golang-conversion of the 'AI Semantic Cache'
+// plugin configuration, from the AI family of Kong plugins.
+type AISemanticCacheConfig struct {
+	Embeddings AISemanticCacheEmbeddingsConfig `json:"embeddings"`
+	VectorDB   AISemanticCacheVectorDBConfig   `json:"vectordb"`
+	CacheTTL   *int                            `json:"cache_ttl,omitempty"`
+}
+
+// AISemanticCacheEmbeddingsConfig is a Golang-conversion of the 'Embeddings'
+// configuration of the 'AI Semantic Cache' plugin.
+type AISemanticCacheEmbeddingsConfig struct {
+	Auth  *AICloudProviderAuthConfig  `json:"auth,omitempty"`
+	Model *AICloudProviderModelConfig `json:"model"`
+}
+
+// AISemanticCacheVectorDBConfig is a Golang-conversion of the 'VectorDB'
+// configuration of the 'AI Semantic Cache' plugin.
+type AISemanticCacheVectorDBConfig struct {
+	Strategy       string                     `json:"strategy"`
+	Dimensions     int                        `json:"dimensions"`
+	DistanceMetric string                     `json:"distance_metric"`
+	Threshold      *float64                   `json:"threshold,omitempty"`
+	Redis          AISemanticCacheRedisConfig `json:"redis"`
+}
+
+// AISemanticCacheRedisConfig is a Golang-conversion of the 'Redis'
+// configuration of the 'AI Semantic Cache' plugin's vector database.
+type AISemanticCacheRedisConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
 }
@@ -0,0 +1,425 @@
+package specialized
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGateway - Prompt Policies
+// -----------------------------------------------------------------------------
+
+const (
+	// aiPromptGuardMaxPatterns and aiPromptGuardMaxPatternLength are the limits
+	// of the ai-prompt-guard plugin's allow and deny patterns.
+	aiPromptGuardMaxPatterns      = 10
+	aiPromptGuardMaxPatternLength = 500
+)
+
+// AIGatewayPIIRedactType is how the PII found in prompts is redacted.
+type AIGatewayPIIRedactType string
+
+const (
+	// AIGatewayPIIRedactTypePlaceholder replaces the PII with placeholders.
+	AIGatewayPIIRedactTypePlaceholder AIGatewayPIIRedactType = "placeholder"
+
+	// AIGatewayPIIRedactTypeSynthetic replaces the PII with synthetic data of
+	// the same kind.
+	AIGatewayPIIRedactTypeSynthetic AIGatewayPIIRedactType = "synthetic"
+)
+
+// AIGatewayCacheDistanceMetric is the metric used to compare the embeddings of
+// prompts in the semantic cache.
+type AIGatewayCacheDistanceMetric string
+
+const (
+	// AIGatewayCacheDistanceMetricCosine compares embeddings by their cosine
+	// distance.
+	AIGatewayCacheDistanceMetricCosine AIGatewayCacheDistanceMetric = "cosine"
+
+	// AIGatewayCacheDistanceMetricEuclidean compares embeddings by their
+	// euclidean distance.
+	AIGatewayCacheDistanceMetricEuclidean AIGatewayCacheDistanceMetric = "euclidean"
+)
+
+// aiPIICategories are the categories of PII the ai-sanitizer plugin can redact.
+var aiPIICategories = []string{
+	"general", "phone", "email", "creditcard", "crypto", "date", "ip",
+	"nationality", "ner", "domain", "url", "ssn", "medical", "bank",
+	"credentials", "custom", "all", "all_and_credentials",
+}
+
+// aiEmbeddingsProviders are the cloud providers supported for the embeddings
+// model of the semantic cache.
+var aiEmbeddingsProviders = []operatorv1alpha1.AICloudProviderName{
+	operatorv1alpha1.AICloudProviderOpenAI,
+	operatorv1alpha1.AICloudProviderMistral,
+}
+
+// AIGatewayPromptPolicy guards, redacts and caches the prompts sent to a model
+// of an AIGateway. It is configured through the
+// consts.AIGatewayPromptPoliciesAnnotation annotation of an AIGateway.
+//
+// Each policy is enforced by an additional KongPlugin configured next to the
+// model's prompt decorator plugin.
+type AIGatewayPromptPolicy struct {
+	// Identifier is the identifier of the cloud hosted or self-hosted LLM the
+	// policies apply to.
+	Identifier string `json:"identifier"`
+
+	// PromptGuard allows or denies prompts matching patterns, enforced by the
+	// ai-prompt-guard plugin.
+	PromptGuard *AIGatewayPromptGuard `json:"promptGuard,omitempty"`
+
+	// PIIRedaction redacts the PII found in prompts, enforced by the
+	// ai-sanitizer plugin.
+	PIIRedaction *AIGatewayPIIRedaction `json:"piiRedaction,omitempty"`
+
+	// SemanticCache serves the responses of semantically similar prompts from
+	// a cache, enforced by the ai-semantic-cache plugin.
+	SemanticCache *AIGatewaySemanticCache `json:"semanticCache,omitempty"`
+}
+
+// AIGatewayPromptGuard allows or denies prompts matching patterns. When allow
+// patterns are set, prompts must match at least one of them. Prompts matching
+// a deny pattern are always rejected.
+type AIGatewayPromptGuard struct {
+	// AllowPatterns are the regular expressions prompts must match.
+	AllowPatterns []string `json:"allowPatterns,omitempty"`
+
+	// DenyPatterns are the regular expressions prompts must not match.
+	DenyPatterns []string `json:"denyPatterns,omitempty"`
+
+	// MatchAllRoles checks the system and assistant messages as well, only the
+	// user messages are checked by default.
+	MatchAllRoles bool `json:"matchAllRoles,omitempty"`
+
+	// AllowAllConversationHistory only checks the latest message of a
+	// conversation.
+	AllowAllConversationHistory bool `json:"allowAllConversationHistory,omitempty"`
+}
+
+// AIGatewayPIIRedaction redacts the PII found in prompts.
+type AIGatewayPIIRedaction struct {
+	// Categories are the categories of PII to redact, e.g. "email" or "phone".
+	Categories []string `json:"categories"`
+
+	// RedactType is how the PII is redacted: "placeholder" (default) or
+	// "synthetic".
+	RedactType AIGatewayPIIRedactType `json:"redactType,omitempty"`
+
+	// RecoverRedacted restores the redacted PII in the responses.
+	RecoverRedacted bool `json:"recoverRedacted,omitempty"`
+
+	// Service is the PII detection service. Defaults to the one listening on
+	// port 8080 of the DataPlane Pods.
+	Service *AIGatewayPIIService `json:"service,omitempty"`
+}
+
+// AIGatewayPIIService is the PII detection service used to redact prompts.
+type AIGatewayPIIService struct {
+	// Host is the host of the service.
+	Host string `json:"host"`
+
+	// Port is the port of the service.
+	Port int `json:"port"`
+}
+
+// AIGatewaySemanticCache caches the responses of a model, serving them for the
+// prompts semantically similar to the cached ones.
+type AIGatewaySemanticCache struct {
+	// Embeddings is the model computing the embeddings of the prompts.
+	Embeddings AIGatewayEmbeddings `json:"embeddings"`
+
+	// Redis is the Redis vector database storing the embeddings.
+	Redis AIGatewayRedis `json:"redis"`
+
+	// Dimensions is the number of dimensions of the embeddings.
+	Dimensions int `json:"dimensions"`
+
+	// DistanceMetric is the metric used to compare the embeddings: "cosine"
+	// (default) or "euclidean".
+	DistanceMetric AIGatewayCacheDistanceMetric `json:"distanceMetric,omitempty"`
+
+	// Threshold is the maximum distance between the embeddings of similar
+	// prompts.
+	Threshold *float64 `json:"threshold,omitempty"`
+
+	// CacheTTL is the time to live of the cached responses in seconds.
+	CacheTTL *int `json:"cacheTTL,omitempty"`
+}
+
+// AIGatewayEmbeddings is the embeddings model of a semantic cache. It uses the
+// API key of its provider from the AIGateway's cloud provider credentials
+// Secret.
+type AIGatewayEmbeddings struct {
+	// Provider is the cloud provider of the model: "openai" or "mistral".
+	Provider operatorv1alpha1.AICloudProviderName `json:"provider"`
+
+	// Model is the name of the model.
+	Model string `json:"model"`
+}
+
+// AIGatewayRedis is the Redis vector database of a semantic cache.
+type AIGatewayRedis struct {
+	// Host is the host of the Redis server.
+	Host string `json:"host"`
+
+	// Port is the port of the Redis server.
+	Port int `json:"port"`
+}
+
+// promptPoliciesForAIGateway parses the policies configured in the AIGateway's
+// consts.AIGatewayPromptPoliciesAnnotation annotation and returns them by model
+// identifier. The policies can apply to the cloud hosted and to the provided
+// self-hosted LLMs. It returns nil when the annotation is not set.
+func promptPoliciesForAIGateway(
+	aigateway *operatorv1alpha1.AIGateway,
+	selfHostedLLMs []SelfHostedLargeLanguageModel,
+) (map[string]AIGatewayPromptPolicy, error) {
+	raw, ok := aigateway.GetAnnotations()[consts.AIGatewayPromptPoliciesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var list []AIGatewayPromptPolicy
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", consts.AIGatewayPromptPoliciesAnnotation, err)
+	}
+
+	var identifiers []string
+	if aigateway.Spec.LargeLanguageModels != nil {
+		for _, llm := range aigateway.Spec.LargeLanguageModels.CloudHosted {
+			identifiers = append(identifiers, llm.Identifier)
+		}
+	}
+	for _, llm := range selfHostedLLMs {
+		identifiers = append(identifiers, llm.Identifier)
+	}
+
+	policies := make(map[string]AIGatewayPromptPolicy, len(list))
+	for i, policy := range list {
+		if !slices.Contains(identifiers, policy.Identifier) {
+			return nil, fmt.Errorf("identifier '%s' in %s annotation does not match any LLM", policy.Identifier, consts.AIGatewayPromptPoliciesAnnotation)
+		}
+		if _, ok := policies[policy.Identifier]; ok {
+			return nil, fmt.Errorf("identifier '%s' in %s annotation is not unique", policy.Identifier, consts.AIGatewayPromptPoliciesAnnotation)
+		}
+		if err := validatePromptPolicy(&policy); err != nil {
+			return nil, fmt.Errorf("invalid policy at index %d in %s annotation: %w", i, consts.AIGatewayPromptPoliciesAnnotation, err)
+		}
+		policies[policy.Identifier] = policy
+	}
+	return policies, nil
+}
+
+func validatePromptPolicy(policy *AIGatewayPromptPolicy) error {
+	if policy.PromptGuard == nil && policy.PIIRedaction == nil && policy.SemanticCache == nil {
+		return fmt.Errorf("at least one of promptGuard, piiRedaction or semanticCache is required")
+	}
+
+	if g := policy.PromptGuard; g != nil {
+		if len(g.AllowPatterns) == 0 && len(g.DenyPatterns) == 0 {
+			return fmt.Errorf("promptGuard requires at least one allow or deny pattern")
+		}
+		for _, p := range []struct {
+			kind     string
+			patterns []string
+		}{
+			{"allow", g.AllowPatterns},
+			{"deny", g.DenyPatterns},
+		} {
+			if len(p.patterns) > aiPromptGuardMaxPatterns {
+				return fmt.Errorf("promptGuard has %d %s patterns, at most %d are supported", len(p.patterns), p.kind, aiPromptGuardMaxPatterns)
+			}
+			for _, pattern := range p.patterns {
+				if len(pattern) == 0 || len(pattern) > aiPromptGuardMaxPatternLength {
+					return fmt.Errorf("promptGuard %s pattern '%s' must be between 1 and %d characters long",
+						p.kind, pattern, aiPromptGuardMaxPatternLength)
+				}
+			}
+		}
+	}
+
+	if r := policy.PIIRedaction; r != nil {
+		if len(r.Categories) == 0 {
+			return fmt.Errorf("piiRedaction requires at least one category")
+		}
+		for _, category := range r.Categories {
+			if !slices.Contains(aiPIICategories, category) {
+				return fmt.Errorf("piiRedaction category '%s' is not supported (supported categories: %s)",
+					category, strings.Join(aiPIICategories, ", "))
+			}
+		}
+		switch r.RedactType {
+		case "", AIGatewayPIIRedactTypePlaceholder, AIGatewayPIIRedactTypeSynthetic:
+		default:
+			return fmt.Errorf("piiRedaction redactType '%s' is not supported (supported types: %s, %s)",
+				r.RedactType, AIGatewayPIIRedactTypePlaceholder, AIGatewayPIIRedactTypeSynthetic)
+		}
+		if r.Service != nil {
+			if err := validateHostPort("piiRedaction service", r.Service.Host, r.Service.Port); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c := policy.SemanticCache; c != nil {
+		if !slices.Contains(aiEmbeddingsProviders, c.Embeddings.Provider) {
+			return fmt.Errorf("semanticCache embeddings provider '%s' is not supported (supported providers: %s, %s)",
+				c.Embeddings.Provider, operatorv1alpha1.AICloudProviderOpenAI, operatorv1alpha1.AICloudProviderMistral)
+		}
+		if c.Embeddings.Model == "" {
+			return fmt.Errorf("semanticCache embeddings model is required")
+		}
+		if err := validateHostPort("semanticCache redis", c.Redis.Host, c.Redis.Port); err != nil {
+			return err
+		}
+		if c.Dimensions < 1 {
+			return fmt.Errorf("semanticCache dimensions %d is invalid", c.Dimensions)
+		}
+		switch c.DistanceMetric {
+		case "", AIGatewayCacheDistanceMetricCosine, AIGatewayCacheDistanceMetricEuclidean:
+		default:
+			return fmt.Errorf("semanticCache distanceMetric '%s' is not supported (supported metrics: %s, %s)",
+				c.DistanceMetric, AIGatewayCacheDistanceMetricCosine, AIGatewayCacheDistanceMetricEuclidean)
+		}
+		if c.Threshold != nil && *c.Threshold < 0 {
+			return fmt.Errorf("semanticCache threshold %v is invalid", *c.Threshold)
+		}
+		if c.CacheTTL != nil && *c.CacheTTL < 0 {
+			return fmt.Errorf("semanticCache cacheTTL %d is invalid", *c.CacheTTL)
+		}
+	}
+	return nil
+}
+
+func validateHostPort(what, host string, port int) error {
+	if host == "" {
+		return fmt.Errorf("%s host is required", what)
+	}
+	if net.ParseIP(host) == nil {
+		if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+			return fmt.Errorf("%s host '%s' is invalid: %s", what, host, strings.Join(errs, ", "))
+		}
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("%s port %d is invalid", what, port)
+	}
+	return nil
+}
+
+// aiPromptPolicyToKongPlugins takes the validated policy of the LLM with the
+// provided identifier and transforms it into the ai-prompt-guard, ai-sanitizer
+// and ai-semantic-cache vX.KongPlugins enforcing it. embeddingsKey is the API
+// key of the semantic cache's embeddings provider, nil when there's no cache.
+func aiPromptPolicyToKongPlugins(
+	identifier string,
+	policy *AIGatewayPromptPolicy,
+	aigateway *operatorv1alpha1.AIGateway,
+	embeddingsKey []byte,
+) ([]*configurationv1.KongPlugin, error) {
+	var plugins []*configurationv1.KongPlugin
+
+	if g := policy.PromptGuard; g != nil {
+		plugin, err := aiProxyKongPlugin(identifier, "ai-prompt-guard", aigateway, &AIPromptGuardConfig{
+			AllowPatterns:               g.AllowPatterns,
+			DenyPatterns:                g.DenyPatterns,
+			AllowAllConversationHistory: g.AllowAllConversationHistory,
+			MatchAllRoles:               g.MatchAllRoles,
+		})
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	if r := policy.PIIRedaction; r != nil {
+		config := AISanitizerConfig{
+			Anonymize:       r.Categories,
+			RedactType:      string(lo.CoalesceOrEmpty(r.RedactType, AIGatewayPIIRedactTypePlaceholder)),
+			RecoverRedacted: r.RecoverRedacted,
+		}
+		if r.Service != nil {
+			config.Host = lo.ToPtr(r.Service.Host)
+			config.Port = lo.ToPtr(r.Service.Port)
+		}
+		plugin, err := aiProxyKongPlugin(identifier, "ai-sanitizer", aigateway, &config)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	if c := policy.SemanticCache; c != nil {
+		authHeader, err := getAuthHeaderForInference(operatorv1alpha1.AICloudProvider{Name: c.Embeddings.Provider})
+		if err != nil {
+			return nil, err
+		}
+		provider := string(c.Embeddings.Provider)
+		plugin, err := aiProxyKongPlugin(identifier, "ai-semantic-cache", aigateway, &AISemanticCacheConfig{
+			Embeddings: AISemanticCacheEmbeddingsConfig{
+				Auth: &AICloudProviderAuthConfig{
+					HeaderName:  lo.ToPtr(authHeader["HeaderName"]),
+					HeaderValue: lo.ToPtr(fmt.Sprintf(authHeader["HeaderPattern"], string(embeddingsKey))),
+				},
+				Model: &AICloudProviderModelConfig{
+					Provider: &provider,
+					Name:     lo.ToPtr(c.Embeddings.Model),
+				},
+			},
+			VectorDB: AISemanticCacheVectorDBConfig{
+				Strategy:       "redis",
+				Dimensions:     c.Dimensions,
+				DistanceMetric: string(lo.CoalesceOrEmpty(c.DistanceMetric, AIGatewayCacheDistanceMetricCosine)),
+				Threshold:      c.Threshold,
+				Redis: AISemanticCacheRedisConfig{
+					Host: c.Redis.Host,
+					Port: c.Redis.Port,
+				},
+			},
+			CacheTTL: c.CacheTTL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins, nil
+}
+
+// promptPolicyPlugins generates the plugins enforcing the prompt policy of a
+// model. The returned plugins are nil when they can't be generated, the
+// returned status tells why.
+func promptPolicyPlugins(
+	model aiGatewayModelStatus,
+	policy *AIGatewayPromptPolicy,
+	aigateway *operatorv1alpha1.AIGateway,
+	credentials cloudProviderCredentials,
+) (aiGatewayModelStatus, []*configurationv1.KongPlugin) {
+	var embeddingsKey []byte
+	if policy.SemanticCache != nil {
+		var msg string
+		embeddingsKey, _, msg = credentials.apiKey(policy.SemanticCache.Embeddings.Provider)
+		if embeddingsKey == nil {
+			return model.withPluginNotConfigured(fmt.Sprintf("semantic cache embeddings: %s", msg)), nil
+		}
+	}
+	plugins, err := aiPromptPolicyToKongPlugins(model.identifier, policy, aigateway, embeddingsKey)
+	if err != nil {
+		return model.withPluginNotConfigured(err.Error()), nil
+	}
+	return model, plugins
+}
@@ -0,0 +1,237 @@
+package specialized
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+func TestPromptPoliciesForAIGateway(t *testing.T) {
+	selfHostedLLMs := []SelfHostedLargeLanguageModel{{Identifier: "llama"}}
+
+	testCases := []struct {
+		name        string
+		annotation  string
+		expected    map[string]AIGatewayPromptPolicy
+		expectedErr string
+	}{
+		{
+			name:     "no annotation",
+			expected: nil,
+		},
+		{
+			name: "policies for cloud hosted and self-hosted models",
+			annotation: `[
+				{"identifier":"gpt","promptGuard":{"allowPatterns":["^Q:"],"denyPatterns":[".*password.*"]}},
+				{"identifier":"llama","piiRedaction":{"categories":["email"],"recoverRedacted":true}}
+			]`,
+			expected: map[string]AIGatewayPromptPolicy{
+				"gpt": {
+					Identifier:  "gpt",
+					PromptGuard: &AIGatewayPromptGuard{AllowPatterns: []string{"^Q:"}, DenyPatterns: []string{".*password.*"}},
+				},
+				"llama": {
+					Identifier:   "llama",
+					PIIRedaction: &AIGatewayPIIRedaction{Categories: []string{"email"}, RecoverRedacted: true},
+				},
+			},
+		},
+		{
+			name:        "invalid json",
+			annotation:  `{`,
+			expectedErr: "failed to parse",
+		},
+		{
+			name:        "unknown model",
+			annotation:  `[{"identifier":"claude","promptGuard":{"denyPatterns":["x"]}}]`,
+			expectedErr: "identifier 'claude' in gateway-operator.konghq.com/prompt-policies annotation does not match any LLM",
+		},
+		{
+			name: "duplicate model",
+			annotation: `[
+				{"identifier":"gpt","promptGuard":{"denyPatterns":["x"]}},
+				{"identifier":"gpt","promptGuard":{"denyPatterns":["y"]}}
+			]`,
+			expectedErr: "identifier 'gpt' in gateway-operator.konghq.com/prompt-policies annotation is not unique",
+		},
+		{
+			name:        "no policy",
+			annotation:  `[{"identifier":"gpt"}]`,
+			expectedErr: "at least one of promptGuard, piiRedaction or semanticCache is required",
+		},
+		{
+			name:        "prompt guard without patterns",
+			annotation:  `[{"identifier":"gpt","promptGuard":{"matchAllRoles":true}}]`,
+			expectedErr: "promptGuard requires at least one allow or deny pattern",
+		},
+		{
+			name:        "prompt guard with too many patterns",
+			annotation:  `[{"identifier":"gpt","promptGuard":{"denyPatterns":["1","2","3","4","5","6","7","8","9","10","11"]}}]`,
+			expectedErr: "promptGuard has 11 deny patterns, at most 10 are supported",
+		},
+		{
+			name:        "prompt guard with an empty pattern",
+			annotation:  `[{"identifier":"gpt","promptGuard":{"allowPatterns":[""]}}]`,
+			expectedErr: "promptGuard allow pattern '' must be between 1 and 500 characters long",
+		},
+		{
+			name:        "pii redaction without categories",
+			annotation:  `[{"identifier":"gpt","piiRedaction":{}}]`,
+			expectedErr: "piiRedaction requires at least one category",
+		},
+		{
+			name:        "pii redaction with an unsupported redact type",
+			annotation:  `[{"identifier":"gpt","piiRedaction":{"categories":["email"],"redactType":"hash"}}]`,
+			expectedErr: "piiRedaction redactType 'hash' is not supported",
+		},
+		{
+			name:        "pii redaction with an invalid service",
+			annotation:  `[{"identifier":"gpt","piiRedaction":{"categories":["email"],"service":{"host":"pii.ai.svc","port":0}}}]`,
+			expectedErr: "piiRedaction service port 0 is invalid",
+		},
+		{
+			name: "semantic cache with an unsupported embeddings provider",
+			annotation: `[{"identifier":"gpt","semanticCache":{
+				"embeddings":{"provider":"cohere","model":"embed"},"redis":{"host":"redis","port":6379},"dimensions":1024
+			}}]`,
+			expectedErr: "semanticCache embeddings provider 'cohere' is not supported",
+		},
+		{
+			name: "semantic cache without redis host",
+			annotation: `[{"identifier":"gpt","semanticCache":{
+				"embeddings":{"provider":"openai","model":"text-embedding-3-small"},"redis":{"port":6379},"dimensions":1536
+			}}]`,
+			expectedErr: "semanticCache redis host is required",
+		},
+		{
+			name: "semantic cache without dimensions",
+			annotation: `[{"identifier":"gpt","semanticCache":{
+				"embeddings":{"provider":"openai","model":"text-embedding-3-small"},"redis":{"host":"redis","port":6379}
+			}}]`,
+			expectedErr: "semanticCache dimensions 0 is invalid",
+		},
+		{
+			name: "semantic cache with an unsupported distance metric",
+			annotation: `[{"identifier":"gpt","semanticCache":{
+				"embeddings":{"provider":"openai","model":"text-embedding-3-small"},"redis":{"host":"redis","port":6379},
+				"dimensions":1536,"distanceMetric":"manhattan"
+			}}]`,
+			expectedErr: "semanticCache distanceMetric 'manhattan' is not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			aigw := aiGatewayWithBackends("")
+			if tc.annotation != "" {
+				aigw.Annotations = map[string]string{consts.AIGatewayPromptPoliciesAnnotation: tc.annotation}
+			}
+			policies, err := promptPoliciesForAIGateway(aigw, selfHostedLLMs)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, policies)
+		})
+	}
+}
+
+func TestAIPromptPolicyToKongPlugins(t *testing.T) {
+	aigw := aiGatewayWithBackends("")
+	policy := &AIGatewayPromptPolicy{
+		Identifier: "gpt",
+		PromptGuard: &AIGatewayPromptGuard{
+			DenyPatterns:  []string{".*password.*"},
+			MatchAllRoles: true,
+		},
+		PIIRedaction: &AIGatewayPIIRedaction{
+			Categories: []string{"email", "phone"},
+			Service:    &AIGatewayPIIService{Host: "pii.ai.svc", Port: 8080},
+		},
+		SemanticCache: &AIGatewaySemanticCache{
+			Embeddings: AIGatewayEmbeddings{Provider: "openai", Model: "text-embedding-3-small"},
+			Redis:      AIGatewayRedis{Host: "redis.ai.svc", Port: 6379},
+			Dimensions: 1536,
+			CacheTTL:   lo.ToPtr(300),
+		},
+	}
+
+	plugins, err := aiPromptPolicyToKongPlugins("gpt", policy, aigw, []byte("key"))
+	require.NoError(t, err)
+	require.Len(t, plugins, 3)
+
+	require.Equal(t, "gpt-ai-prompt-guard", plugins[0].Name)
+	require.Equal(t, "ai-prompt-guard", plugins[0].PluginName)
+	require.JSONEq(t,
+		`{"deny_patterns":[".*password.*"],"allow_all_conversation_history":false,"match_all_roles":true}`,
+		string(plugins[0].Config.Raw))
+
+	require.Equal(t, "gpt-ai-sanitizer", plugins[1].Name)
+	require.Equal(t, "ai-sanitizer", plugins[1].PluginName)
+	require.JSONEq(t,
+		`{"anonymize":["email","phone"],"redact_type":"placeholder","recover_redacted":false,"host":"pii.ai.svc","port":8080}`,
+		string(plugins[1].Config.Raw))
+
+	require.Equal(t, "gpt-ai-semantic-cache", plugins[2].Name)
+	require.Equal(t, "ai-semantic-cache", plugins[2].PluginName)
+	require.JSONEq(t, `{
+		"embeddings": {
+			"auth": {"header_name":"Authorization","header_value":"Bearer key"},
+			"model": {"provider":"openai","name":"text-embedding-3-small"}
+		},
+		"vectordb": {
+			"strategy":"redis","dimensions":1536,"distance_metric":"cosine",
+			"redis": {"host":"redis.ai.svc","port":6379}
+		},
+		"cache_ttl": 300
+	}`, string(plugins[2].Config.Raw))
+
+	t.Run("semantic cache API key is externalized", func(t *testing.T) {
+		creds := newAIGatewayCredentials(aigw)
+		require.NoError(t, creds.externalize(plugins[2]))
+		require.NotContains(t, string(plugins[2].Config.Raw), "Bearer key")
+		require.Len(t, plugins[2].ConfigPatches, 1)
+		require.Equal(t, "/embeddings/auth/header_value", plugins[2].ConfigPatches[0].Path)
+		require.Equal(t, []byte("Bearer key"), creds.data["gpt-ai-semantic-cache.embeddings.auth"])
+	})
+}
+
+func TestPromptPolicyPlugins(t *testing.T) {
+	aigw := aiGatewayWithBackends("")
+	policy := &AIGatewayPromptPolicy{
+		Identifier: "gpt",
+		SemanticCache: &AIGatewaySemanticCache{
+			Embeddings: AIGatewayEmbeddings{Provider: "mistral", Model: "mistral-embed"},
+			Redis:      AIGatewayRedis{Host: "redis.ai.svc", Port: 6379},
+			Dimensions: 1024,
+		},
+	}
+	credentials := cloudProviderCredentials{
+		secret: &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "credentials"},
+			Data:       map[string][]byte{"openai": []byte("key")},
+		},
+	}
+
+	model, plugins := promptPolicyPlugins(newAIGatewayModelStatus("gpt"), policy, aigw, credentials)
+	require.Nil(t, plugins)
+	require.Equal(t, metav1.ConditionFalse, model.pluginConfigured.Status)
+	require.Equal(t,
+		"semantic cache embeddings: provider 'mistral' has no API key stored in the credentials secret 'ns/credentials'",
+		model.pluginConfigured.Message)
+
+	credentials.secret.Data["mistral"] = []byte("mistral-key")
+	model, plugins = promptPolicyPlugins(newAIGatewayModelStatus("gpt"), policy, aigw, credentials)
+	require.Len(t, plugins, 1)
+	var config AISemanticCacheConfig
+	require.NoError(t, json.Unmarshal(plugins[0].Config.Raw, &config))
+	require.Equal(t, "Bearer mistral-key", *config.Embeddings.Auth.HeaderValue)
+	require.NotEqual(t, AIGatewayEndpointConditionReasonInvalidConfiguration, model.pluginConfigured.Reason)
+}
@@ -2,13 +2,17 @@ package specialized
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 
 	"github.com/go-logr/logr"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kong/gateway-operator/controller/pkg/log"
@@ -16,6 +20,7 @@ import (
 
 	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
 	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+	"github.com/kong/kubernetes-configuration/pkg/metadata"
 )
 
 // -----------------------------------------------------------------------------
@@ -44,12 +49,28 @@ func (r *AIGatewayReconciler) createOrUpdateHttpRoute(
 		}
 		return false, err
 	}
+	labeled, err := r.ensureLabels(ctx, logger, found, httpRoute)
+	if err != nil {
+		return labeled, err
+	}
 
-	// TODO - implement patching
+	// TODO - implement patching of the spec
 	//
 	// See: https://github.com/Kong/gateway-operator/issues/137
 
-	return false, nil
+	// The plugins of a route change when its model switches between a single
+	// backend and multiple backends.
+	plugins := httpRoute.Annotations[metadata.AnnotationKeyPlugins]
+	if found.Annotations[metadata.AnnotationKeyPlugins] == plugins {
+		return labeled, nil
+	}
+	old := found.DeepCopy()
+	if found.Annotations == nil {
+		found.Annotations = map[string]string{}
+	}
+	found.Annotations[metadata.AnnotationKeyPlugins] = plugins
+	log.Info(logger, "updating httproute plugins for aigateway")
+	return true, r.Client.Patch(ctx, found, client.MergeFrom(old))
 }
 
 func (r *AIGatewayReconciler) createOrUpdatePlugin(
@@ -74,12 +95,96 @@ func (r *AIGatewayReconciler) createOrUpdatePlugin(
 		}
 		return false, err
 	}
+	labeled, err := r.ensureLabels(ctx, logger, found, kongPlugin)
+	if err != nil {
+		return labeled, err
+	}
 
-	// TODO - implement patching
-	//
-	// See: https://github.com/Kong/gateway-operator/issues/137
+	// The plugin name is immutable, plugins of a different type get a different
+	// object name so only the configuration has to be kept up to date.
+	if found.InstanceName == kongPlugin.InstanceName &&
+		pluginConfigEqual(found.Config.Raw, kongPlugin.Config.Raw) &&
+		equality.Semantic.DeepEqual(found.ConfigPatches, kongPlugin.ConfigPatches) {
+		return labeled, nil
+	}
+	old := found.DeepCopy()
+	found.InstanceName = kongPlugin.InstanceName
+	found.Config = kongPlugin.Config
+	found.ConfigPatches = kongPlugin.ConfigPatches
+	log.Info(logger, "updating plugin for aigateway")
+	return true, r.Client.Patch(ctx, found, client.MergeFrom(old))
+}
+
+// ensureLabels adds the labels of the desired resource to the existing one
+// when they're missing, e.g. when it was created before the resources of the
+// AIGateway were labeled.
+func (r *AIGatewayReconciler) ensureLabels(
+	ctx context.Context,
+	logger logr.Logger,
+	found client.Object,
+	desired client.Object,
+) (bool, error) {
+	labels := found.GetLabels()
+	if lo.EveryBy(lo.Entries(desired.GetLabels()), func(e lo.Entry[string, string]) bool {
+		v, ok := labels[e.Key]
+		return ok && v == e.Value
+	}) {
+		return false, nil
+	}
+	old := found.DeepCopyObject().(client.Object)
+	found.SetLabels(lo.Assign(labels, desired.GetLabels()))
+	log.Debug(logger, "labeling resource for aigateway", "name", found.GetName())
+	return true, r.Client.Patch(ctx, found, client.MergeFrom(old))
+}
+
+// pluginConfigEqual returns true when both plugin configurations are
+// semantically equal, regardless of the JSON formatting.
+func pluginConfigEqual(a, b []byte) bool {
+	var aConfig, bConfig any
+	if err := json.Unmarshal(a, &aConfig); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &bConfig); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aConfig, bConfig)
+}
+
+// createOrUpdateCredentialsSecret creates or updates the Secret holding the
+// credentials of the AIGateway's plugins. Only creating the Secret is reported
+// as a change: updating its data (e.g. when an API key is rotated) doesn't
+// change any of the plugins referencing it.
+func (r *AIGatewayReconciler) createOrUpdateCredentialsSecret(
+	ctx context.Context,
+	logger logr.Logger,
+	secret *corev1.Secret,
+) (bool, error) {
+	log.Trace(logger, "checking for any existing credentials secret for aigateway")
 
-	return false, nil
+	found := &corev1.Secret{}
+	err := r.Client.Get(ctx, types.NamespacedName{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+	}, found)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			log.Info(logger, "creating credentials secret for aigateway")
+			return true, r.Client.Create(ctx, secret)
+		}
+		return false, err
+	}
+	labeled, err := r.ensureLabels(ctx, logger, found, secret)
+	if err != nil {
+		return labeled, err
+	}
+
+	if equality.Semantic.DeepEqual(found.Data, secret.Data) {
+		return labeled, nil
+	}
+	old := found.DeepCopy()
+	found.Data = secret.Data
+	log.Info(logger, "updating credentials secret for aigateway")
+	return labeled, r.Client.Patch(ctx, found, client.MergeFrom(old))
 }
 
 func (r *AIGatewayReconciler) createOrUpdateGateway(
@@ -110,7 +215,7 @@ func (r *AIGatewayReconciler) createOrUpdateGateway(
 	//
 	// See: https://github.com/Kong/gateway-operator/issues/137
 
-	return false, nil
+	return r.ensureLabels(ctx, logger, found, gateway)
 }
 
 func (r *AIGatewayReconciler) createOrUpdateSvc(
@@ -141,7 +246,7 @@ func (r *AIGatewayReconciler) createOrUpdateSvc(
 	//
 	// See: https://github.com/Kong/gateway-operator/issues/137
 
-	return false, nil
+	return r.ensureLabels(ctx, logger, found, service)
 }
 
 // -----------------------------------------------------------------------------
@@ -167,12 +272,36 @@ func (r *AIGatewayReconciler) manageGateway(
 	return change, nil
 }
 
+// getGateway returns the Gateway of the AIGateway or nil when it doesn't exist
+// yet.
+func (r *AIGatewayReconciler) getGateway(
+	ctx context.Context,
+	aiGateway *operatorv1alpha1.AIGateway,
+) (*gatewayv1.Gateway, error) {
+	gateway := &gatewayv1.Gateway{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(aiGatewayToGateway(aiGateway)), gateway); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not get Gateway for aigateway, %w", err)
+	}
+	return gateway, nil
+}
+
+// configurePlugins configures the sink Service and, for every cloud hosted and
+// self-hosted LLM, the KongPlugins and the HTTPRoute serving it, including the
+// plugins enforcing its prompt policies and its token budget if any. The routes and plugins of the LLMs
+// which were removed are deleted. Problems which
+// require the user to fix the AIGateway or its credentials Secrets don't return
+// an error but are reported in the returned per model status instead.
 func (r *AIGatewayReconciler) configurePlugins(
 	ctx context.Context,
 	logger logr.Logger,
 	aiGateway *operatorv1alpha1.AIGateway,
+	cfg *aiGatewayAnnotationsConfig,
 ) (
 	bool, // whether any changes were made
+	[]aiGatewayModelStatus,
 	error,
 ) {
 	changes := false
@@ -184,97 +313,436 @@ func (r *AIGatewayReconciler) configurePlugins(
 		changes = true
 	}
 	if err != nil {
-		return changes, err
+		return changes, nil, err
 	}
 
 	log.Trace(logger, "retrieving the cloud provider credentials secret for aigateway")
-	if aiGateway.Spec.CloudProviderCredentials == nil {
-		return changes, fmt.Errorf("ai gateway '%s' requires secret reference for Cloud Provider API keys", aiGateway.Name)
-	}
-	credentialSecretName := aiGateway.Spec.CloudProviderCredentials.Name
-	credentialSecretNamespace := aiGateway.Namespace
-	if aiGateway.Spec.CloudProviderCredentials.Namespace != nil {
-		credentialSecretNamespace = *aiGateway.Spec.CloudProviderCredentials.Namespace
-	}
-
-	// check if referencing the credential secret is allowed by referencegrants.
-	msg, allowed, err := secretref.CheckReferenceGrantForSecret(ctx, r.Client, aiGateway, gatewayv1.SecretObjectReference{
-		Name:      gatewayv1.ObjectName(credentialSecretName),
-		Namespace: lo.ToPtr(gatewayv1.Namespace(credentialSecretNamespace)),
-	})
+	credentials, err := r.getCloudProviderCredentials(ctx, aiGateway)
 	if err != nil {
-		return false, err
-	}
-	if !allowed {
-		return false, fmt.Errorf("Referencing Secret %s/%s is not allowed: %s", credentialSecretNamespace, credentialSecretName, msg)
+		return changes, nil, err
 	}
 
-	credentialSecret := &corev1.Secret{}
-	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: credentialSecretNamespace, Name: credentialSecretName}, credentialSecret); err != nil {
-		if k8serrors.IsNotFound(err) {
-			return changes, nil
+	log.Trace(logger, "generating plugins for aigateway")
+	var (
+		modelPlugins = make([]aiGatewayModelPlugins, 0, len(aiGateway.Spec.LargeLanguageModels.CloudHosted)+len(cfg.selfHostedLLMs))
+		pluginCreds  = newAIGatewayCredentials(aiGateway)
+	)
+	add := func(model aiGatewayModelStatus, aiProxyPlugin *configurationv1.KongPlugin, defaultPrompts []operatorv1alpha1.LLMPrompt) {
+		var policyPlugins, budgetPlugins []*configurationv1.KongPlugin
+		if policy, ok := cfg.promptPolicies[model.identifier]; ok && aiProxyPlugin != nil {
+			log.Trace(logger, "configuring the prompt policy plugins for aigateway")
+			model, policyPlugins = promptPolicyPlugins(model, &policy, aiGateway, credentials)
+			if policyPlugins == nil {
+				aiProxyPlugin = nil
+			}
 		}
-		return changes, fmt.Errorf(
-			"ai gateway '%s' references secret '%s/%s' but it could not be read, %w",
-			aiGateway.Name, credentialSecretNamespace, credentialSecretName, err,
-		)
+		if budget, ok := cfg.tokenBudgets[model.identifier]; ok && aiProxyPlugin != nil {
+			log.Trace(logger, "configuring the token budget plugins for aigateway")
+			keys, err := aiProxyUsageKeys(aiProxyPlugin)
+			if err == nil {
+				providers := lo.Uniq(lo.Map(keys, func(k aiLLMUsageKey, _ int) string { return k.provider }))
+				budgetPlugins, err = aiTokenBudgetToKongPlugins(model.identifier, providers, &budget, aiGateway)
+			}
+			if err != nil {
+				model, aiProxyPlugin = model.withPluginNotConfigured(err.Error()), nil
+			} else {
+				model = model.withUsageKeys(keys)
+			}
+		}
+		if aiProxyPlugin != nil {
+			for _, plugin := range append([]*configurationv1.KongPlugin{aiProxyPlugin}, policyPlugins...) {
+				if err := pluginCreds.externalize(plugin); err != nil {
+					model, aiProxyPlugin = model.withPluginNotConfigured(err.Error()), nil
+					break
+				}
+			}
+		}
+		modelPlugins = append(modelPlugins, aiGatewayModelPlugins{
+			model:          model,
+			aiProxyPlugin:  aiProxyPlugin,
+			defaultPrompts: defaultPrompts,
+			policyPlugins:  policyPlugins,
+			budgetPlugins:  budgetPlugins,
+		})
 	}
 
-	log.Trace(logger, "generating routes and plugins for aigateway")
 	for _, v := range aiGateway.Spec.LargeLanguageModels.CloudHosted {
 		cloudHostedLLM := v
-
-		log.Trace(logger, "determining whether we have API keys configured for cloud provider")
-		credentialData, ok := credentialSecret.Data[string(cloudHostedLLM.AICloudProvider.Name)]
-		if !ok {
-			return changes, fmt.Errorf(
-				"ai gateway '%s' references provider '%s' but it has no API key stored in the credentials secret",
-				aiGateway.Name, string(cloudHostedLLM.AICloudProvider.Name),
-			)
+		var (
+			model         aiGatewayModelStatus
+			aiProxyPlugin *configurationv1.KongPlugin
+		)
+		if backends, ok := cfg.modelBackends[cloudHostedLLM.Identifier]; ok {
+			log.Trace(logger, "configuring the aiproxy advanced plugin for aigateway")
+			model, aiProxyPlugin, err = r.cloudHostedLLMBackendsPlugin(ctx, aiGateway, &cloudHostedLLM, &backends, credentials)
+			if err != nil {
+				return changes, nil, err
+			}
+		} else {
+			log.Trace(logger, "configuring the base aiproxy plugin for aigateway")
+			model, aiProxyPlugin = cloudHostedLLMPlugin(aiGateway, &cloudHostedLLM, credentials)
 		}
+		add(model, aiProxyPlugin, cloudHostedLLM.DefaultPrompts)
+	}
 
-		log.Trace(logger, "configuring the base aiproxy plugin for aigateway")
-		aiProxyPlugin, err := aiCloudGatewayToKongPlugin(&cloudHostedLLM, aiGateway, &credentialData)
+	for i := range cfg.selfHostedLLMs {
+		selfHostedLLM := &cfg.selfHostedLLMs[i]
+		log.Trace(logger, "configuring the base aiproxy plugin for self-hosted llm")
+		model, aiProxyPlugin, err := r.selfHostedLLMPlugin(ctx, aiGateway, selfHostedLLM)
 		if err != nil {
-			return changes, err
+			return changes, nil, err
+		}
+		add(model, aiProxyPlugin, selfHostedLLM.DefaultPrompts)
+	}
+
+	// the credentials secret is configured before the plugins referencing it.
+	log.Trace(logger, "configuring the plugin credentials secret for aigateway")
+	changed, err = r.createOrUpdateCredentialsSecret(ctx, logger, pluginCreds.secret())
+	if changed {
+		changes = true
+	}
+	if err != nil {
+		return changes, nil, err
+	}
+
+	log.Trace(logger, "configuring routes and plugins for aigateway")
+	var (
+		models    = make([]aiGatewayModelStatus, 0, len(modelPlugins))
+		generated = newAIGatewayGeneratedResources()
+	)
+	for _, mp := range modelPlugins {
+		if mp.aiProxyPlugin == nil {
+			models = append(models, mp.model)
+			continue
 		}
-		changed, err := r.createOrUpdatePlugin(ctx, logger, aiProxyPlugin)
+		model, changed, err := r.configureModelResources(ctx, logger, aiGateway, aiGatewaySinkService, mp, generated)
 		if changed {
 			changes = true
 		}
 		if err != nil {
-			return changes, err
+			return changes, nil, err
 		}
+		models = append(models, model)
+	}
 
-		log.Trace(logger, "configuring the ai prompt decorator plugin for aigateway")
-		decoratorPlugin, err := aiCloudGatewayToKongPromptDecoratorPlugin(&cloudHostedLLM, aiGateway)
-		if err != nil {
-			return changes, err
+	log.Trace(logger, "pruning routes and plugins no longer used by aigateway")
+	identifiers := lo.Map(models, func(m aiGatewayModelStatus, _ int) string { return m.identifier })
+	changed, err = r.pruneModelResources(ctx, logger, aiGateway, identifiers, generated)
+	if changed {
+		changes = true
+	}
+	if err != nil {
+		return changes, nil, err
+	}
+
+	return changes, models, nil
+}
+
+// cloudHostedLLMPlugin generates the ai-proxy plugin of a cloud hosted LLM. The
+// returned plugin is nil when it can't be generated, the returned status tells
+// why.
+func cloudHostedLLMPlugin(
+	aiGateway *operatorv1alpha1.AIGateway,
+	cloudHostedLLM *operatorv1alpha1.CloudHostedLargeLanguageModel,
+	credentials cloudProviderCredentials,
+) (aiGatewayModelStatus, *configurationv1.KongPlugin) {
+	model := newAIGatewayModelStatus(cloudHostedLLM.Identifier)
+
+	credentialData, reason, msg := credentials.apiKey(cloudHostedLLM.AICloudProvider.Name)
+	if credentialData == nil {
+		return model.withCredentialsNotResolved(reason, msg), nil
+	}
+	model = model.withCredentialsResolved()
+
+	aiProxyPlugin, err := aiCloudGatewayToKongPlugin(cloudHostedLLM, aiGateway, &credentialData)
+	if err != nil {
+		return model.withPluginNotConfigured(err.Error()), nil
+	}
+	return model, aiProxyPlugin
+}
+
+// cloudHostedLLMBackendsPlugin generates the ai-proxy-advanced plugin of a
+// cloud hosted LLM load balanced across several backends. Backends whose
+// credentials can't be resolved are left out. The returned plugin is nil when
+// it can't be generated, the returned status tells why.
+func (r *AIGatewayReconciler) cloudHostedLLMBackendsPlugin(
+	ctx context.Context,
+	aiGateway *operatorv1alpha1.AIGateway,
+	cloudHostedLLM *operatorv1alpha1.CloudHostedLargeLanguageModel,
+	backends *AIGatewayModelBackends,
+	credentials cloudProviderCredentials,
+) (aiGatewayModelStatus, *configurationv1.KongPlugin, error) {
+	model := newAIGatewayModelStatus(cloudHostedLLM.Identifier)
+
+	var (
+		names      = []string{AIGatewayPrimaryBackendName}
+		targets    []aiGatewayBackendTarget
+		unresolved = map[string]string{}
+		// the reason of the first backend which can't be used is reported
+		// when none can be used.
+		firstReason, firstMsg string
+	)
+	addTarget := func(target aiGatewayBackendTarget, reason, msg string) {
+		if target.credentialData != nil {
+			targets = append(targets, target)
+			return
 		}
-		if decoratorPlugin != nil {
-			changed, err := r.createOrUpdatePlugin(ctx, logger, decoratorPlugin)
-			if changed {
-				changes = true
-			}
+		unresolved[target.name] = msg
+		if firstReason == "" {
+			firstReason, firstMsg = reason, fmt.Sprintf("backend %s: %s", target.name, msg)
+		}
+	}
+
+	credentialData, reason, msg := credentials.apiKey(cloudHostedLLM.AICloudProvider.Name)
+	addTarget(aiGatewayBackendTarget{
+		name:           AIGatewayPrimaryBackendName,
+		provider:       cloudHostedLLM.AICloudProvider.Name,
+		model:          cloudHostedLLM.Model,
+		weight:         backends.Weight,
+		azure:          backends.Azure,
+		credentialData: credentialData,
+	}, reason, msg)
+
+	for _, backend := range backends.Backends {
+		names = append(names, backend.Name)
+		var (
+			credentialData []byte
+			reason, msg    string
+		)
+		if backend.SecretRef != nil {
+			var err error
+			credentialData, reason, msg, err = r.getReferencedSecretKey(ctx, aiGateway, backend.SecretRef)
 			if err != nil {
-				return changes, err
+				return model, nil, err
 			}
+		} else {
+			credentialData, reason, msg = credentials.apiKey(backend.Provider)
 		}
+		addTarget(aiGatewayBackendTarget{
+			name:           backend.Name,
+			provider:       backend.Provider,
+			model:          lo.CoalesceOrEmpty(backend.Model, cloudHostedLLM.Model),
+			weight:         backend.Weight,
+			azure:          backend.Azure,
+			credentialData: credentialData,
+		}, reason, msg)
+	}
+
+	model = model.withBackendsCredentials(names, unresolved)
+	if len(targets) == 0 {
+		return model.withCredentialsNotResolved(firstReason, firstMsg), nil, nil
+	}
+	model = model.withCredentialsResolved()
+
+	aiProxyPlugin, err := aiCloudGatewayToKongProxyAdvancedPlugin(cloudHostedLLM, backends, aiGateway, targets)
+	if err != nil {
+		return model.withPluginNotConfigured(err.Error()), nil, nil
+	}
+	return model, aiProxyPlugin, nil
+}
+
+// selfHostedLLMPlugin generates the ai-proxy plugin of a self-hosted LLM. The
+// returned plugin is nil when it can't be generated, the returned status tells
+// why.
+func (r *AIGatewayReconciler) selfHostedLLMPlugin(
+	ctx context.Context,
+	aiGateway *operatorv1alpha1.AIGateway,
+	selfHostedLLM *SelfHostedLargeLanguageModel,
+) (aiGatewayModelStatus, *configurationv1.KongPlugin, error) {
+	model := newAIGatewayModelStatus(selfHostedLLM.Identifier)
 
-		log.Trace(logger, "configuring an httproute for aigateway")
-		plugins := []string{aiProxyPlugin.Name}
-		if decoratorPlugin != nil {
-			plugins = append(plugins, decoratorPlugin.Name)
+	var credentialData *[]byte
+	if selfHostedLLM.Auth != nil {
+		data, reason, msg, err := r.getReferencedSecretKey(ctx, aiGateway, &selfHostedLLM.Auth.SecretRef)
+		if err != nil {
+			return model, nil, err
+		}
+		if data == nil {
+			return model.withCredentialsNotResolved(reason, msg), nil, nil
 		}
-		httpRoute := aiCloudGatewayToHTTPRoute(&cloudHostedLLM, aiGateway, aiGatewaySinkService, plugins)
-		changed, err = r.createOrUpdateHttpRoute(ctx, logger, httpRoute)
+		credentialData = &data
+	}
+	model = model.withCredentialsResolved()
+
+	aiProxyPlugin, err := aiSelfHostedLLMToKongPlugin(selfHostedLLM, aiGateway, credentialData)
+	if err != nil {
+		return model.withPluginNotConfigured(err.Error()), nil, nil
+	}
+	return model, aiProxyPlugin, nil
+}
+
+// aiGatewayModelPlugins are the plugins generated for a single LLM of an
+// AIGateway, before they're configured.
+type aiGatewayModelPlugins struct {
+	model          aiGatewayModelStatus
+	aiProxyPlugin  *configurationv1.KongPlugin
+	defaultPrompts []operatorv1alpha1.LLMPrompt
+	// policyPlugins enforce the prompt policies of the LLM.
+	policyPlugins []*configurationv1.KongPlugin
+	// budgetPlugins enforce the token budget of the LLM and expose its usage.
+	budgetPlugins []*configurationv1.KongPlugin
+}
+
+// configureModelResources configures the ai-proxy plugin, the optional prompt
+// decorator, prompt policy and token budget plugins and the HTTPRoute serving a
+// single LLM.
+// The configured resources are recorded in generated.
+func (r *AIGatewayReconciler) configureModelResources(
+	ctx context.Context,
+	logger logr.Logger,
+	aiGateway *operatorv1alpha1.AIGateway,
+	aiGatewaySinkService *corev1.Service,
+	mp aiGatewayModelPlugins,
+	generated *aiGatewayGeneratedResources,
+) (
+	aiGatewayModelStatus,
+	bool, // whether any changes were made
+	error,
+) {
+	changes := false
+	model := mp.model
+
+	log.Trace(logger, "configuring the ai prompt decorator plugin for aigateway")
+	decoratorPlugin, err := aiCloudGatewayToKongPromptDecoratorPlugin(model.identifier, mp.defaultPrompts, aiGateway)
+	if err != nil {
+		return model.withPluginNotConfigured(err.Error()), changes, nil
+	}
+
+	kongPlugins := []*configurationv1.KongPlugin{mp.aiProxyPlugin}
+	if decoratorPlugin != nil {
+		kongPlugins = append(kongPlugins, decoratorPlugin)
+	}
+	kongPlugins = append(kongPlugins, mp.policyPlugins...)
+	kongPlugins = append(kongPlugins, mp.budgetPlugins...)
+	plugins := make([]string, 0, len(kongPlugins))
+	for _, plugin := range kongPlugins {
+		changed, err := r.createOrUpdatePlugin(ctx, logger, plugin)
 		if changed {
 			changes = true
 		}
 		if err != nil {
-			return changes, err
+			return model, changes, err
 		}
+		plugins = append(plugins, plugin.Name)
 	}
+	model = model.withPluginConfigured()
 
-	return changes, nil
+	log.Trace(logger, "configuring an httproute for aigateway")
+	httpRoute := aiCloudGatewayToHTTPRoute(model.identifier, aiGateway, aiGatewaySinkService, plugins)
+	changed, err := r.createOrUpdateHttpRoute(ctx, logger, httpRoute)
+	if changed {
+		changes = true
+	}
+	if err != nil {
+		return model, changes, err
+	}
+	model = model.withRouteCreated()
+	generated.add(model.identifier, httpRoute, plugins...)
+
+	return model, changes, nil
+}
+
+// cloudProviderCredentials is the cloud provider credentials Secret of an
+// AIGateway. When the Secret can't be used, secret is nil and reason and
+// message explain why.
+type cloudProviderCredentials struct {
+	secret  *corev1.Secret
+	reason  string
+	message string
+}
+
+// apiKey returns the API key of the provided cloud provider. When it can't be
+// resolved the returned key is nil and the reason and the message explain why.
+func (c cloudProviderCredentials) apiKey(provider operatorv1alpha1.AICloudProviderName) ([]byte, string, string) {
+	if c.secret == nil {
+		return nil, c.reason, c.message
+	}
+	data, ok := c.secret.Data[string(provider)]
+	if !ok {
+		return nil, AIGatewayEndpointConditionReasonMissingProviderKey,
+			fmt.Sprintf("provider '%s' has no API key stored in the credentials secret '%s/%s'",
+				provider, c.secret.Namespace, c.secret.Name)
+	}
+	return data, "", ""
+}
+
+// getCloudProviderCredentials returns the cloud provider credentials Secret of
+// the AIGateway.
+func (r *AIGatewayReconciler) getCloudProviderCredentials(
+	ctx context.Context,
+	aiGateway *operatorv1alpha1.AIGateway,
+) (cloudProviderCredentials, error) {
+	if aiGateway.Spec.CloudProviderCredentials == nil {
+		return cloudProviderCredentials{
+			reason:  AIGatewayEndpointConditionReasonMissingCredentialsRef,
+			message: "a secret reference for Cloud Provider API keys is required",
+		}, nil
+	}
+	credentialSecretName := aiGateway.Spec.CloudProviderCredentials.Name
+	credentialSecretNamespace := aiGateway.Namespace
+	if aiGateway.Spec.CloudProviderCredentials.Namespace != nil {
+		credentialSecretNamespace = *aiGateway.Spec.CloudProviderCredentials.Namespace
+	}
+	secret, reason, msg, err := r.getReferencedSecret(ctx, aiGateway, credentialSecretNamespace, credentialSecretName)
+	return cloudProviderCredentials{secret: secret, reason: reason, message: msg}, err
+}
+
+// getReferencedSecretKey returns the data of the Secret key referenced by the
+// AIGateway. When it can't be used the returned data is nil and the reason and
+// the message explain why.
+func (r *AIGatewayReconciler) getReferencedSecretKey(
+	ctx context.Context,
+	aiGateway *operatorv1alpha1.AIGateway,
+	ref *SelfHostedLLMSecretKeyRef,
+) ([]byte, string, string, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = aiGateway.Namespace
+	}
+	secret, reason, msg, err := r.getReferencedSecret(ctx, aiGateway, namespace, ref.Name)
+	if err != nil || secret == nil {
+		return nil, reason, msg, err
+	}
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, AIGatewayEndpointConditionReasonMissingSecretKey,
+			fmt.Sprintf("secret '%s/%s' has no key '%s'", secret.Namespace, secret.Name, ref.Key), nil
+	}
+	return data, "", "", nil
+}
+
+// getReferencedSecret returns the Secret referenced by the AIGateway. When the
+// Secret can't be used the returned Secret is nil and the reason and the message
+// explain why.
+func (r *AIGatewayReconciler) getReferencedSecret(
+	ctx context.Context,
+	aiGateway *operatorv1alpha1.AIGateway,
+	namespace string,
+	name string,
+) (*corev1.Secret, string, string, error) {
+	// check if referencing the secret is allowed by referencegrants.
+	msg, allowed, err := secretref.CheckReferenceGrantForSecret(ctx, r.Client, aiGateway, gatewayv1.SecretObjectReference{
+		Name:      gatewayv1.ObjectName(name),
+		Namespace: lo.ToPtr(gatewayv1.Namespace(namespace)),
+	})
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !allowed {
+		return nil, AIGatewayEndpointConditionReasonRefNotPermitted,
+			fmt.Sprintf("referencing secret '%s/%s' is not allowed: %s", namespace, name, msg), nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, AIGatewayEndpointConditionReasonSecretNotFound,
+				fmt.Sprintf("secret '%s/%s' not found", namespace, name), nil
+		}
+		return nil, "", "", fmt.Errorf(
+			"ai gateway '%s' references secret '%s/%s' but it could not be read, %w",
+			aiGateway.Name, namespace, name, err,
+		)
+	}
+	return secret, "", "", nil
 }
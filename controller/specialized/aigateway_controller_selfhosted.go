@@ -0,0 +1,241 @@
+package specialized
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGateway - Self-Hosted LLMs
+// -----------------------------------------------------------------------------
+
+// SelfHostedLLMFormat is the format of the API exposed by a self-hosted model
+// server.
+type SelfHostedLLMFormat string
+
+const (
+	// SelfHostedLLMFormatOpenAI is used for model servers exposing an OpenAI
+	// compatible API, e.g. vLLM.
+	SelfHostedLLMFormatOpenAI SelfHostedLLMFormat = "openai"
+
+	// SelfHostedLLMFormatOllama is used for model servers exposing the Ollama API.
+	SelfHostedLLMFormatOllama SelfHostedLLMFormat = "ollama"
+
+	// SelfHostedLLMFormatRaw is used for model servers accepting raw Llama 2
+	// prompts.
+	SelfHostedLLMFormatRaw SelfHostedLLMFormat = "raw"
+)
+
+// SelfHostedLargeLanguageModel is the configuration of a Large Language Model
+// served by a model server running in the cluster. It is configured through
+// the consts.AIGatewaySelfHostedLLMsAnnotation annotation of an AIGateway.
+type SelfHostedLargeLanguageModel struct {
+	// Identifier is the unique name which identifies the LLM. As for cloud
+	// hosted LLMs, the model is accessed via "http://${endpoint}/${identifier}".
+	Identifier string `json:"identifier"`
+
+	// Model is the model name of the LLM (e.g. llama3).
+	Model *string `json:"model,omitempty"`
+
+	// PromptType is the type of prompt to be used for inference requests to
+	// the LLM. Defaults to "completions".
+	PromptType *operatorv1alpha1.LLMPromptType `json:"promptType,omitempty"`
+
+	// DefaultPrompts is a list of prompts that should be provided to the LLM
+	// by default.
+	DefaultPrompts []operatorv1alpha1.LLMPrompt `json:"defaultPrompts,omitempty"`
+
+	// DefaultPromptParams configures the parameters which will be sent with
+	// any and every inference request.
+	DefaultPromptParams *operatorv1alpha1.LLMPromptParams `json:"defaultPromptParams,omitempty"`
+
+	// Format is the format of the model server's API. Defaults to "openai".
+	Format SelfHostedLLMFormat `json:"format,omitempty"`
+
+	// Upstream is the in-cluster model server.
+	Upstream SelfHostedLLMUpstream `json:"upstream"`
+
+	// Auth configures the credentials sent to the model server. When not set
+	// requests are sent without credentials.
+	Auth *SelfHostedLLMAuth `json:"auth,omitempty"`
+}
+
+// SelfHostedLLMUpstream is the in-cluster model server of a self-hosted LLM.
+type SelfHostedLLMUpstream struct {
+	// Service is the Service backing the model server.
+	Service SelfHostedLLMServiceRef `json:"service"`
+
+	// Path is the path of the model server's inference API, e.g.
+	// "/v1/chat/completions".
+	Path string `json:"path,omitempty"`
+
+	// Scheme is the scheme used to connect to the model server. Defaults to
+	// "http".
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// SelfHostedLLMServiceRef references the Service backing a model server.
+type SelfHostedLLMServiceRef struct {
+	// Name is the name of the Service.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Service. Defaults to the AIGateway's
+	// namespace.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Port is the port of the Service.
+	Port int32 `json:"port"`
+}
+
+// SelfHostedLLMAuth configures the credentials sent to a model server.
+type SelfHostedLLMAuth struct {
+	// SecretRef references the Secret key holding the credentials. Secrets
+	// from other namespaces require a ReferenceGrant.
+	SecretRef SelfHostedLLMSecretKeyRef `json:"secretRef"`
+
+	// HeaderName is the name of the header carrying the credentials. Defaults
+	// to "Authorization".
+	HeaderName string `json:"headerName,omitempty"`
+
+	// HeaderPattern is the pattern of the header's value, with %s replaced by
+	// the credentials. Defaults to "Bearer %s".
+	HeaderPattern string `json:"headerPattern,omitempty"`
+}
+
+// SelfHostedLLMSecretKeyRef references a key of a Secret.
+type SelfHostedLLMSecretKeyRef struct {
+	// Name is the name of the Secret.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the Secret. Defaults to the AIGateway's
+	// namespace.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key is the key of the Secret holding the credentials.
+	Key string `json:"key"`
+}
+
+// selfHostedLLMsForAIGateway parses the self-hosted LLMs configured in the
+// AIGateway's consts.AIGatewaySelfHostedLLMsAnnotation annotation, applying
+// the defaults. It returns nil when the annotation is not set.
+func selfHostedLLMsForAIGateway(aigateway *operatorv1alpha1.AIGateway) ([]SelfHostedLargeLanguageModel, error) {
+	raw, ok := aigateway.GetAnnotations()[consts.AIGatewaySelfHostedLLMsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var llms []SelfHostedLargeLanguageModel
+	if err := json.Unmarshal([]byte(raw), &llms); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %w", consts.AIGatewaySelfHostedLLMsAnnotation, err)
+	}
+
+	identifiers := make(map[string]struct{}, len(llms))
+	if aigateway.Spec.LargeLanguageModels != nil {
+		for _, llm := range aigateway.Spec.LargeLanguageModels.CloudHosted {
+			identifiers[llm.Identifier] = struct{}{}
+		}
+	}
+	for i := range llms {
+		llm := &llms[i]
+		if err := validateSelfHostedLLM(llm); err != nil {
+			return nil, fmt.Errorf("invalid self-hosted LLM at index %d in %s annotation: %w", i, consts.AIGatewaySelfHostedLLMsAnnotation, err)
+		}
+		if _, ok := identifiers[llm.Identifier]; ok {
+			return nil, fmt.Errorf("identifier '%s' in %s annotation is not unique", llm.Identifier, consts.AIGatewaySelfHostedLLMsAnnotation)
+		}
+		identifiers[llm.Identifier] = struct{}{}
+
+		if llm.PromptType == nil {
+			promptType := operatorv1alpha1.LLMPromptTypeCompletion
+			llm.PromptType = &promptType
+		}
+		if llm.Format == "" {
+			llm.Format = SelfHostedLLMFormatOpenAI
+		}
+		if llm.Upstream.Scheme == "" {
+			llm.Upstream.Scheme = "http"
+		}
+		if llm.Upstream.Service.Namespace == "" {
+			llm.Upstream.Service.Namespace = aigateway.Namespace
+		}
+		if llm.Auth != nil {
+			if llm.Auth.SecretRef.Namespace == "" {
+				llm.Auth.SecretRef.Namespace = aigateway.Namespace
+			}
+			if llm.Auth.HeaderName == "" {
+				llm.Auth.HeaderName = "Authorization"
+			}
+			if llm.Auth.HeaderPattern == "" {
+				llm.Auth.HeaderPattern = "Bearer %s"
+			}
+		}
+	}
+	return llms, nil
+}
+
+func validateSelfHostedLLM(llm *SelfHostedLargeLanguageModel) error {
+	if llm.Identifier == "" {
+		return fmt.Errorf("identifier is required")
+	}
+	if errs := validation.IsDNS1123Label(llm.Identifier); len(errs) > 0 {
+		return fmt.Errorf("identifier '%s' is invalid: %s", llm.Identifier, strings.Join(errs, ", "))
+	}
+	if llm.PromptType != nil &&
+		*llm.PromptType != operatorv1alpha1.LLMPromptTypeChat &&
+		*llm.PromptType != operatorv1alpha1.LLMPromptTypeCompletion {
+		return fmt.Errorf("prompt type '%s' is not supported", *llm.PromptType)
+	}
+	switch llm.Format {
+	case "", SelfHostedLLMFormatOpenAI, SelfHostedLLMFormatOllama, SelfHostedLLMFormatRaw:
+	default:
+		return fmt.Errorf("format '%s' is not supported (supported formats: %s, %s, %s)",
+			llm.Format, SelfHostedLLMFormatOpenAI, SelfHostedLLMFormatOllama, SelfHostedLLMFormatRaw)
+	}
+	switch llm.Upstream.Scheme {
+	case "", "http", "https":
+	default:
+		return fmt.Errorf("upstream scheme '%s' is not supported (supported schemes: http, https)", llm.Upstream.Scheme)
+	}
+	if llm.Upstream.Service.Name == "" {
+		return fmt.Errorf("upstream service name is required")
+	}
+	if llm.Upstream.Service.Port < 1 || llm.Upstream.Service.Port > 65535 {
+		return fmt.Errorf("upstream service port %d is invalid", llm.Upstream.Service.Port)
+	}
+	if llm.Upstream.Path != "" && !strings.HasPrefix(llm.Upstream.Path, "/") {
+		return fmt.Errorf("upstream path '%s' must start with '/'", llm.Upstream.Path)
+	}
+	if llm.Auth != nil {
+		if llm.Auth.SecretRef.Name == "" || llm.Auth.SecretRef.Key == "" {
+			return fmt.Errorf("auth secretRef requires a name and a key")
+		}
+		if llm.Auth.HeaderPattern != "" && strings.Count(llm.Auth.HeaderPattern, "%s") != 1 {
+			return fmt.Errorf("auth headerPattern must contain exactly one '%%s'")
+		}
+	}
+	return nil
+}
+
+// selfHostedLLMUpstreamURL returns the URL of the self-hosted LLM's model
+// server, using the cluster DNS name of its Service.
+func selfHostedLLMUpstreamURL(llm *SelfHostedLargeLanguageModel) string {
+	u := url.URL{
+		Scheme: llm.Upstream.Scheme,
+		Host: net.JoinHostPort(
+			fmt.Sprintf("%s.%s.svc", llm.Upstream.Service.Name, llm.Upstream.Service.Namespace),
+			strconv.Itoa(int(llm.Upstream.Service.Port)),
+		),
+		Path: llm.Upstream.Path,
+	}
+	return u.String()
+}
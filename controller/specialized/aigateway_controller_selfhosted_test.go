@@ -0,0 +1,143 @@
+package specialized
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+func TestSelfHostedLLMsForAIGateway(t *testing.T) {
+	aigateway := func(annotation string) *operatorv1alpha1.AIGateway {
+		aigw := &operatorv1alpha1.AIGateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai"},
+			Spec: operatorv1alpha1.AIGatewaySpec{
+				LargeLanguageModels: &operatorv1alpha1.LargeLanguageModels{
+					CloudHosted: []operatorv1alpha1.CloudHostedLargeLanguageModel{{Identifier: "gpt"}},
+				},
+			},
+		}
+		if annotation != "" {
+			aigw.Annotations = map[string]string{consts.AIGatewaySelfHostedLLMsAnnotation: annotation}
+		}
+		return aigw
+	}
+
+	testCases := []struct {
+		name          string
+		annotation    string
+		expected      []SelfHostedLargeLanguageModel
+		expectedError string
+	}{
+		{
+			name: "annotation not set",
+		},
+		{
+			name:       "defaults are applied",
+			annotation: `[{"identifier":"llama","upstream":{"service":{"name":"vllm","port":8000}},"auth":{"secretRef":{"name":"token","key":"token"}}}]`,
+			expected: []SelfHostedLargeLanguageModel{
+				{
+					Identifier: "llama",
+					PromptType: lo.ToPtr(operatorv1alpha1.LLMPromptTypeCompletion),
+					Format:     SelfHostedLLMFormatOpenAI,
+					Upstream: SelfHostedLLMUpstream{
+						Service: SelfHostedLLMServiceRef{Name: "vllm", Namespace: "ns", Port: 8000},
+						Scheme:  "http",
+					},
+					Auth: &SelfHostedLLMAuth{
+						SecretRef:     SelfHostedLLMSecretKeyRef{Name: "token", Namespace: "ns", Key: "token"},
+						HeaderName:    "Authorization",
+						HeaderPattern: "Bearer %s",
+					},
+				},
+			},
+		},
+		{
+			name:          "invalid JSON",
+			annotation:    `{`,
+			expectedError: "failed to parse",
+		},
+		{
+			name:          "identifier used by a cloud hosted LLM",
+			annotation:    `[{"identifier":"gpt","upstream":{"service":{"name":"vllm","port":8000}}}]`,
+			expectedError: "identifier 'gpt'",
+		},
+		{
+			name:          "unsupported format",
+			annotation:    `[{"identifier":"llama","format":"grpc","upstream":{"service":{"name":"vllm","port":8000}}}]`,
+			expectedError: "format 'grpc' is not supported",
+		},
+		{
+			name:          "missing upstream port",
+			annotation:    `[{"identifier":"llama","upstream":{"service":{"name":"vllm"}}}]`,
+			expectedError: "upstream service port 0 is invalid",
+		},
+		{
+			name:          "auth without key",
+			annotation:    `[{"identifier":"llama","upstream":{"service":{"name":"vllm","port":8000}},"auth":{"secretRef":{"name":"token"}}}]`,
+			expectedError: "auth secretRef requires a name and a key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			llms, err := selfHostedLLMsForAIGateway(aigateway(tc.annotation))
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, llms)
+		})
+	}
+}
+
+func TestAISelfHostedLLMToKongPlugin(t *testing.T) {
+	aigateway := &operatorv1alpha1.AIGateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai"},
+	}
+	llm := &SelfHostedLargeLanguageModel{
+		Identifier: "llama",
+		Model:      lo.ToPtr("llama3"),
+		PromptType: lo.ToPtr(operatorv1alpha1.LLMPromptTypeChat),
+		Format:     SelfHostedLLMFormatOllama,
+		Upstream: SelfHostedLLMUpstream{
+			Service: SelfHostedLLMServiceRef{Name: "ollama", Namespace: "models", Port: 11434},
+			Path:    "/api/chat",
+			Scheme:  "http",
+		},
+		Auth: &SelfHostedLLMAuth{
+			HeaderName:    "Authorization",
+			HeaderPattern: "Bearer %s",
+		},
+	}
+
+	plugin, err := aiSelfHostedLLMToKongPlugin(llm, aigateway, lo.ToPtr([]byte("secret-token")))
+	require.NoError(t, err)
+	require.Equal(t, "llama-ai-proxy", plugin.Name)
+	require.Equal(t, "ai-proxy", plugin.PluginName)
+
+	var config AICloudProviderLLMConfig
+	require.NoError(t, json.Unmarshal(plugin.Config.Raw, &config))
+	require.Equal(t, "llm/v1/chat", *config.RouteType)
+	require.Equal(t, "llama2", *config.Model.Provider)
+	require.Equal(t, "llama3", *config.Model.Name)
+	require.Equal(t, "http://ollama.models.svc:11434/api/chat", *config.Model.Options.UpstreamURL)
+	require.Equal(t, "ollama", *config.Model.Options.Llama2Format)
+	require.Equal(t, "Authorization", *config.Auth.HeaderName)
+	require.Equal(t, "Bearer secret-token", *config.Auth.HeaderValue)
+
+	t.Log("requests are sent without credentials when no auth is configured")
+	llm.Auth = nil
+	plugin, err = aiSelfHostedLLMToKongPlugin(llm, aigateway, nil)
+	require.NoError(t, err)
+	config = AICloudProviderLLMConfig{}
+	require.NoError(t, json.Unmarshal(plugin.Config.Raw, &config))
+	require.Nil(t, config.Auth)
+}
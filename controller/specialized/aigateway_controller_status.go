@@ -0,0 +1,323 @@
+package specialized
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGatewayReconciler - Per Model Status
+// -----------------------------------------------------------------------------
+
+// aiGatewayModelStatus holds the outcome of configuring the resources which
+// serve a single cloud hosted LLM of an AIGateway.
+type aiGatewayModelStatus struct {
+	identifier          string
+	credentialsResolved metav1.Condition
+	pluginConfigured    metav1.Condition
+	routeCreated        metav1.Condition
+
+	// backendsCredentialsResolved is only set for models load balanced across several
+	// backends.
+	backendsCredentialsResolved *metav1.Condition
+
+	// usageKeys are the provider and model pairs the usage of a model with a
+	// token budget is reported for, accountUsage is set for such models.
+	usageKeys    []aiLLMUsageKey
+	accountUsage bool
+	// usageReported is only set for models with a token budget.
+	usageReported *metav1.Condition
+}
+
+func newAIGatewayModelStatus(identifier string) aiGatewayModelStatus {
+	return aiGatewayModelStatus{
+		identifier: identifier,
+		credentialsResolved: metav1.Condition{
+			Type:    AIGatewayEndpointConditionTypeCredentialsResolved,
+			Status:  metav1.ConditionFalse,
+			Reason:  AIGatewayEndpointConditionReasonPending,
+			Message: "waiting for the credentials to be resolved",
+		},
+		pluginConfigured: metav1.Condition{
+			Type:    AIGatewayEndpointConditionTypePluginConfigured,
+			Status:  metav1.ConditionFalse,
+			Reason:  AIGatewayEndpointConditionReasonPending,
+			Message: "waiting for the credentials to be resolved",
+		},
+		routeCreated: metav1.Condition{
+			Type:    AIGatewayEndpointConditionTypeRouteCreated,
+			Status:  metav1.ConditionFalse,
+			Reason:  AIGatewayEndpointConditionReasonPending,
+			Message: "waiting for the plugins to be configured",
+		},
+	}
+}
+
+func (m aiGatewayModelStatus) withCredentialsNotResolved(reason, message string) aiGatewayModelStatus {
+	m.credentialsResolved.Reason = reason
+	m.credentialsResolved.Message = message
+	return m
+}
+
+func (m aiGatewayModelStatus) withCredentialsResolved() aiGatewayModelStatus {
+	m.credentialsResolved.Status = metav1.ConditionTrue
+	m.credentialsResolved.Reason = AIGatewayEndpointConditionReasonResolved
+	m.credentialsResolved.Message = "credentials resolved"
+	m.pluginConfigured.Message = "waiting for the plugins to be configured"
+	return m
+}
+
+func (m aiGatewayModelStatus) withPluginNotConfigured(message string) aiGatewayModelStatus {
+	m.pluginConfigured.Reason = AIGatewayEndpointConditionReasonInvalidConfiguration
+	m.pluginConfigured.Message = message
+	return m
+}
+
+func (m aiGatewayModelStatus) withPluginConfigured() aiGatewayModelStatus {
+	m.pluginConfigured.Status = metav1.ConditionTrue
+	m.pluginConfigured.Reason = AIGatewayEndpointConditionReasonConfigured
+	m.pluginConfigured.Message = "plugins configured"
+	return m
+}
+
+func (m aiGatewayModelStatus) withRouteCreated() aiGatewayModelStatus {
+	m.routeCreated.Status = metav1.ConditionTrue
+	m.routeCreated.Reason = AIGatewayEndpointConditionReasonCreated
+	m.routeCreated.Message = "httproute created"
+	return m
+}
+
+// withBackendsCredentials sets whether the credentials of the backends of a
+// model load balanced across several backends have been resolved, unresolved
+// holds the reason of every backend whose credentials can't be resolved.
+func (m aiGatewayModelStatus) withBackendsCredentials(backends []string, unresolved map[string]string) aiGatewayModelStatus {
+	c := metav1.Condition{
+		Type:    AIGatewayEndpointConditionTypeBackendsCredentialsResolved,
+		Status:  metav1.ConditionTrue,
+		Reason:  AIGatewayEndpointConditionReasonBackendsResolved,
+		Message: "credentials of all backends are resolved",
+	}
+	if len(unresolved) > 0 {
+		c.Status = metav1.ConditionFalse
+		c.Reason = AIGatewayEndpointConditionReasonBackendsPartiallyResolved
+		if len(unresolved) == len(backends) {
+			c.Reason = AIGatewayEndpointConditionReasonBackendsUnresolved
+		}
+		states := make([]string, 0, len(backends))
+		for _, name := range backends {
+			state, ok := unresolved[name]
+			if !ok {
+				state = "resolved"
+			}
+			states = append(states, fmt.Sprintf("%s: %s", name, state))
+		}
+		c.Message = strings.Join(states, "; ")
+	}
+	m.backendsCredentialsResolved = &c
+	return m
+}
+
+// withUsageKeys enables the usage accounting of a model with a token budget,
+// served by the provided provider and model pairs.
+func (m aiGatewayModelStatus) withUsageKeys(keys []aiLLMUsageKey) aiGatewayModelStatus {
+	m.usageKeys = keys
+	m.accountUsage = true
+	return m
+}
+
+// withUsage sets whether the usage of a model with a token budget has been
+// reported by the DataPlane Pods into the usage ConfigMap with the provided
+// name. The message doesn't hold the usage itself so that the status doesn't
+// change, and trigger a reconciliation, on every sync.
+func (m aiGatewayModelStatus) withUsage(reported bool, configMapName string) aiGatewayModelStatus {
+	if !m.accountUsage {
+		return m
+	}
+	c := metav1.Condition{
+		Type:    AIGatewayEndpointConditionTypeUsageReported,
+		Status:  metav1.ConditionUnknown,
+		Reason:  AIGatewayEndpointConditionReasonNotReported,
+		Message: "none of the DataPlane Pods reported the usage of the model",
+	}
+	if reported {
+		c.Status = metav1.ConditionTrue
+		c.Reason = AIGatewayEndpointConditionReasonReported
+		c.Message = fmt.Sprintf("usage of the model per consumer is reported in ConfigMap %s", configMapName)
+	}
+	m.usageReported = &c
+	return m
+}
+
+func (m aiGatewayModelStatus) conditions() []metav1.Condition {
+	conditions := m.coreConditions()
+	if m.backendsCredentialsResolved != nil {
+		conditions = append(conditions, *m.backendsCredentialsResolved)
+	}
+	if m.usageReported != nil {
+		conditions = append(conditions, *m.usageReported)
+	}
+	return conditions
+}
+
+// coreConditions returns the conditions which all have to be true for the
+// model to be served.
+func (m aiGatewayModelStatus) coreConditions() []metav1.Condition {
+	return []metav1.Condition{m.credentialsResolved, m.pluginConfigured, m.routeCreated}
+}
+
+// failure returns the first condition which requires the user's intervention
+// or nil when there is none.
+func (m aiGatewayModelStatus) failure() *metav1.Condition {
+	for _, c := range m.coreConditions() {
+		if c.Status == metav1.ConditionFalse && c.Reason != AIGatewayEndpointConditionReasonPending {
+			return &c
+		}
+	}
+	return nil
+}
+
+func (m aiGatewayModelStatus) configured() bool {
+	return m.credentialsResolved.Status == metav1.ConditionTrue &&
+		m.pluginConfigured.Status == metav1.ConditionTrue &&
+		m.routeCreated.Status == metav1.ConditionTrue
+}
+
+// -----------------------------------------------------------------------------
+// AIGatewayReconciler - Status
+// -----------------------------------------------------------------------------
+
+// setAIGatewayStatus sets the conditions and the endpoints of the AIGateway
+// from its Gateway and the outcome of configuring each of its models. The
+// Gateway is nil when it doesn't exist yet. provisioning indicates that owned
+// resources have just been created and may not be active yet.
+func setAIGatewayStatus(
+	aigateway *operatorv1alpha1.AIGateway,
+	gateway *gatewayv1.Gateway,
+	models []aiGatewayModelStatus,
+	provisioning bool,
+) {
+	gatewayProgrammed := newAIGatewayGatewayProgrammedCondition(aigateway, gateway)
+	k8sutils.SetCondition(gatewayProgrammed, aigateway)
+	address := aiGatewayAddress(gateway)
+
+	var (
+		endpoints = make([]operatorv1alpha1.AIGatewayEndpoint, 0, len(models))
+		failures  []string
+		pending   []string
+	)
+	for _, model := range models {
+		var ready metav1.Condition
+		switch failure := model.failure(); {
+		case failure != nil:
+			ready = newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeEndpointReady,
+				metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonFailed, failure.Message)
+			failures = append(failures, fmt.Sprintf("endpoint %s error: %s", model.identifier, failure.Message))
+		case !model.configured():
+			ready = newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeEndpointReady,
+				metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonDeploying, "waiting for resources to be configured")
+			pending = append(pending, model.identifier)
+		case gatewayProgrammed.Status != metav1.ConditionTrue || address == "":
+			ready = newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeEndpointReady,
+				metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonDeploying, "waiting for the gateway to be programmed and assigned an address")
+			pending = append(pending, model.identifier)
+		default:
+			ready = newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeEndpointReady,
+				metav1.ConditionTrue, operatorv1alpha1.AIGatewayConditionReasonDeployed, "endpoint is ready for inference")
+		}
+
+		var conditions []metav1.Condition
+		if previous := findAIGatewayEndpoint(aigateway.Status.Endpoints, model.identifier); previous != nil {
+			conditions = slices.Clone(previous.Conditions)
+		}
+		if model.backendsCredentialsResolved == nil {
+			meta.RemoveStatusCondition(&conditions, AIGatewayEndpointConditionTypeBackendsCredentialsResolved)
+		}
+		if model.usageReported == nil {
+			meta.RemoveStatusCondition(&conditions, AIGatewayEndpointConditionTypeUsageReported)
+		}
+		for _, c := range append(model.conditions(), ready) {
+			c.ObservedGeneration = aigateway.Generation
+			meta.SetStatusCondition(&conditions, c)
+		}
+
+		var endpointURL string
+		if address != "" {
+			endpointURL = aiGatewayEndpointURL(address, model.identifier)
+		}
+		endpoints = append(endpoints, operatorv1alpha1.AIGatewayEndpoint{
+			NetworkAccessHint: operatorv1alpha1.NetworkInternetAccessible,
+			URL:               endpointURL,
+			AvailableModels:   []string{model.identifier},
+			Conditions:        conditions,
+		})
+	}
+	aigateway.Status.Endpoints = endpoints
+
+	switch {
+	case len(failures) > 0:
+		message := strings.Join(failures, "; ")
+		k8sutils.SetCondition(newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeProvisioning,
+			metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonFailed, message), aigateway)
+		k8sutils.SetCondition(newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeEndpointReady,
+			metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonFailed, message), aigateway)
+	case provisioning || len(pending) > 0:
+		k8sutils.SetCondition(newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeProvisioning,
+			metav1.ConditionTrue, operatorv1alpha1.AIGatewayConditionReasonDeploying, "provisioning resources for the aigateway"), aigateway)
+		message := "waiting for resources to become active"
+		if len(pending) > 0 {
+			message = fmt.Sprintf("waiting for endpoints: %s", strings.Join(pending, ", "))
+		}
+		k8sutils.SetCondition(newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeEndpointReady,
+			metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonDeploying, message), aigateway)
+	default:
+		k8sutils.SetCondition(newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeProvisioning,
+			metav1.ConditionFalse, operatorv1alpha1.AIGatewayConditionReasonDeployed, "all resources provisioned"), aigateway)
+		k8sutils.SetCondition(newAIGatewayCondition(aigateway, operatorv1alpha1.AIGatewayConditionTypeEndpointReady,
+			metav1.ConditionTrue, operatorv1alpha1.AIGatewayConditionReasonDeployed, "all endpoints are ready"), aigateway)
+	}
+}
+
+// aiGatewayAddress returns the first address of the Gateway or an empty string
+// when it has none.
+func aiGatewayAddress(gateway *gatewayv1.Gateway) string {
+	if gateway == nil || len(gateway.Status.Addresses) == 0 {
+		return ""
+	}
+	return gateway.Status.Addresses[0].Value
+}
+
+// aiGatewayEndpointURL returns the URL the model with the provided identifier
+// can be consumed at. It matches the path of the HTTPRoute generated by
+// aiCloudGatewayToHTTPRoute, the Gateway's listener uses the default HTTP port.
+func aiGatewayEndpointURL(address, identifier string) string {
+	host := address
+	if ip := net.ParseIP(address); ip != nil && ip.To4() == nil {
+		host = "[" + address + "]"
+	}
+	u := url.URL{
+		Scheme: "http",
+		Host:   host,
+		Path:   "/" + identifier,
+	}
+	return u.String()
+}
+
+func findAIGatewayEndpoint(endpoints []operatorv1alpha1.AIGatewayEndpoint, identifier string) *operatorv1alpha1.AIGatewayEndpoint {
+	for i := range endpoints {
+		if slices.Contains(endpoints[i].AvailableModels, identifier) {
+			return &endpoints[i]
+		}
+	}
+	return nil
+}
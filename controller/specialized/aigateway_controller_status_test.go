@@ -0,0 +1,136 @@
+package specialized
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+func TestSetAIGatewayStatus(t *testing.T) {
+	aigateway := func() *operatorv1alpha1.AIGateway {
+		return &operatorv1alpha1.AIGateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai", Generation: 2},
+		}
+	}
+	programmedGateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ai", Generation: 1},
+		Status: gatewayv1.GatewayStatus{
+			Addresses: []gatewayv1.GatewayStatusAddress{{Value: "10.0.0.1"}},
+			Conditions: []metav1.Condition{
+				{
+					Type:               string(gatewayv1.GatewayConditionProgrammed),
+					Status:             metav1.ConditionTrue,
+					Reason:             string(gatewayv1.GatewayReasonProgrammed),
+					ObservedGeneration: 1,
+				},
+			},
+		},
+	}
+	readyModel := newAIGatewayModelStatus("gpt").
+		withCredentialsResolved().
+		withPluginConfigured().
+		withRouteCreated()
+
+	testCases := []struct {
+		name                    string
+		gateway                 *gatewayv1.Gateway
+		models                  []aiGatewayModelStatus
+		provisioning            bool
+		expectedGatewayStatus   metav1.ConditionStatus
+		expectedProvisioning    string
+		expectedEndpointsReady  metav1.ConditionStatus
+		expectedEndpointReasons []string
+		expectedURLs            []string
+	}{
+		{
+			name:                    "gateway not created yet",
+			models:                  []aiGatewayModelStatus{readyModel},
+			expectedGatewayStatus:   metav1.ConditionUnknown,
+			expectedProvisioning:    operatorv1alpha1.AIGatewayConditionReasonDeploying,
+			expectedEndpointsReady:  metav1.ConditionFalse,
+			expectedEndpointReasons: []string{operatorv1alpha1.AIGatewayConditionReasonDeploying},
+			expectedURLs:            []string{""},
+		},
+		{
+			name:                    "programmed gateway and configured models",
+			gateway:                 programmedGateway,
+			models:                  []aiGatewayModelStatus{readyModel},
+			expectedGatewayStatus:   metav1.ConditionTrue,
+			expectedProvisioning:    operatorv1alpha1.AIGatewayConditionReasonDeployed,
+			expectedEndpointsReady:  metav1.ConditionTrue,
+			expectedEndpointReasons: []string{operatorv1alpha1.AIGatewayConditionReasonDeployed},
+			expectedURLs:            []string{"http://10.0.0.1/gpt"},
+		},
+		{
+			name:    "missing provider key fails only the affected endpoint",
+			gateway: programmedGateway,
+			models: []aiGatewayModelStatus{
+				readyModel,
+				newAIGatewayModelStatus("command").withCredentialsNotResolved(
+					AIGatewayEndpointConditionReasonMissingProviderKey, "provider 'cohere' has no API key",
+				),
+			},
+			expectedGatewayStatus:  metav1.ConditionTrue,
+			expectedProvisioning:   operatorv1alpha1.AIGatewayConditionReasonFailed,
+			expectedEndpointsReady: metav1.ConditionFalse,
+			expectedEndpointReasons: []string{
+				operatorv1alpha1.AIGatewayConditionReasonDeployed,
+				operatorv1alpha1.AIGatewayConditionReasonFailed,
+			},
+			expectedURLs: []string{"http://10.0.0.1/gpt", "http://10.0.0.1/command"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			aigw := aigateway()
+			setAIGatewayStatus(aigw, tc.gateway, tc.models, tc.provisioning)
+
+			gatewayProgrammed := meta.FindStatusCondition(aigw.Status.Conditions, AIGatewayConditionTypeGatewayProgrammed)
+			require.NotNil(t, gatewayProgrammed)
+			require.Equal(t, tc.expectedGatewayStatus, gatewayProgrammed.Status)
+
+			provisioning := meta.FindStatusCondition(aigw.Status.Conditions, operatorv1alpha1.AIGatewayConditionTypeProvisioning)
+			require.NotNil(t, provisioning)
+			require.Equal(t, tc.expectedProvisioning, provisioning.Reason)
+
+			endpointsReady := meta.FindStatusCondition(aigw.Status.Conditions, operatorv1alpha1.AIGatewayConditionTypeEndpointReady)
+			require.NotNil(t, endpointsReady)
+			require.Equal(t, tc.expectedEndpointsReady, endpointsReady.Status)
+
+			require.Len(t, aigw.Status.Endpoints, len(tc.models))
+			for i, endpoint := range aigw.Status.Endpoints {
+				require.Equal(t, []string{tc.models[i].identifier}, endpoint.AvailableModels)
+				require.Equal(t, tc.expectedURLs[i], endpoint.URL)
+				require.Len(t, endpoint.Conditions, 4)
+				ready := meta.FindStatusCondition(endpoint.Conditions, operatorv1alpha1.AIGatewayConditionTypeEndpointReady)
+				require.NotNil(t, ready)
+				require.Equal(t, tc.expectedEndpointReasons[i], ready.Reason)
+				for _, c := range endpoint.Conditions {
+					require.Equal(t, aigw.Generation, c.ObservedGeneration)
+				}
+			}
+		})
+	}
+
+	t.Run("failure message names the endpoint", func(t *testing.T) {
+		aigw := aigateway()
+		setAIGatewayStatus(aigw, programmedGateway, []aiGatewayModelStatus{
+			newAIGatewayModelStatus("gpt").withCredentialsResolved().withPluginNotConfigured("unsupported prompt type"),
+		}, false)
+		endpointsReady := meta.FindStatusCondition(aigw.Status.Conditions, operatorv1alpha1.AIGatewayConditionTypeEndpointReady)
+		require.NotNil(t, endpointsReady)
+		require.Equal(t, "endpoint gpt error: unsupported prompt type", endpointsReady.Message)
+	})
+}
+
+func TestAIGatewayEndpointURL(t *testing.T) {
+	require.Equal(t, "http://10.0.0.1/gpt", aiGatewayEndpointURL("10.0.0.1", "gpt"))
+	require.Equal(t, "http://ai.example.com/gpt", aiGatewayEndpointURL("ai.example.com", "gpt"))
+	require.Equal(t, "http://[fd00::1]/gpt", aiGatewayEndpointURL("fd00::1", "gpt"))
+}
@@ -0,0 +1,448 @@
+package specialized
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/controller/pkg/log"
+	"github.com/kong/gateway-operator/pkg/consts"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGateway - LLM Usage
+// -----------------------------------------------------------------------------
+
+const (
+	// aiGatewayUsageSyncInterval is the interval at which the usage of the LLMs
+	// of an AIGateway with token budgets is collected from the DataPlane Pods.
+	aiGatewayUsageSyncInterval = time.Minute
+
+	// aiLLMUsageRequestTimeout is the timeout for the requests sent to the
+	// DataPlane Pods' metrics endpoint.
+	aiLLMUsageRequestTimeout = 5 * time.Second
+
+	// maxConcurrentLLMUsageRequests is the maximum number of DataPlane Pods
+	// queried concurrently for the usage of the LLMs.
+	maxConcurrentLLMUsageRequests = 10
+
+	// aiLLMRequestsMetric and aiLLMTokensMetric are the AI metrics exposed by
+	// the prometheus plugin when its ai_metrics are enabled.
+	aiLLMRequestsMetric = "kong_ai_llm_requests_total"
+	aiLLMTokensMetric   = "kong_ai_llm_tokens_total"
+
+	// aiGatewayUsageKey is the key of the usage ConfigMap holding the usage
+	// of the models of an AIGateway per consumer.
+	aiGatewayUsageKey = "usage.json"
+
+	// aiGatewayUsageCountersKey is the key of the usage ConfigMap holding the
+	// last counters reported by every DataPlane Pod, from which the usage
+	// accumulated since the previous sync is computed.
+	aiGatewayUsageCountersKey = "counters.json"
+)
+
+// aiLLMUsageKey identifies the usage of an LLM by a consumer in the DataPlane's
+// AI metrics. consumer is empty for requests without an authenticated consumer.
+type aiLLMUsageKey struct {
+	provider string
+	model    string
+	consumer string
+}
+
+// servedBy returns true when the usage is of one of the provided provider and
+// model pairs, regardless of the consumer.
+func (k aiLLMUsageKey) servedBy(keys []aiLLMUsageKey) bool {
+	for _, key := range keys {
+		if k.provider == key.provider && k.model == key.model {
+			return true
+		}
+	}
+	return false
+}
+
+// aiLLMUsage is the usage of an LLM, either reported by the DataPlane's AI
+// metrics since its Pods started or accumulated by the operator.
+type aiLLMUsage struct {
+	Requests         float64 `json:"requests"`
+	PromptTokens     float64 `json:"promptTokens"`
+	CompletionTokens float64 `json:"completionTokens"`
+}
+
+func (u aiLLMUsage) add(other aiLLMUsage) aiLLMUsage {
+	return aiLLMUsage{
+		Requests:         u.Requests + other.Requests,
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+	}
+}
+
+// since returns the usage counted since the provided previous counters of the
+// same Pod. The counters of a restarted Pod start from zero again, in which
+// case all of the current usage is counted.
+func (u aiLLMUsage) since(previous aiLLMUsage) aiLLMUsage {
+	if u.Requests < previous.Requests || u.PromptTokens < previous.PromptTokens ||
+		u.CompletionTokens < previous.CompletionTokens {
+		return u
+	}
+	return aiLLMUsage{
+		Requests:         u.Requests - previous.Requests,
+		PromptTokens:     u.PromptTokens - previous.PromptTokens,
+		CompletionTokens: u.CompletionTokens - previous.CompletionTokens,
+	}
+}
+
+// aiLLMUsageGetter returns the usage of the LLMs reported by the provided
+// DataPlane Pod.
+type aiLLMUsageGetter func(ctx context.Context, pod *corev1.Pod) (map[aiLLMUsageKey]aiLLMUsage, error)
+
+// getLLMUsageGetter returns the aiLLMUsageGetter used by the Reconciler.
+func (r *AIGatewayReconciler) getLLMUsageGetter() aiLLMUsageGetter {
+	if r.llmUsageGetter != nil {
+		return r.llmUsageGetter
+	}
+	return aiLLMUsageFromMetricsEndpoint(&http.Client{Timeout: aiLLMUsageRequestTimeout})
+}
+
+// aiLLMUsageFromMetricsEndpoint returns an aiLLMUsageGetter which scrapes the
+// metrics endpoint of the DataPlane Pods.
+func aiLLMUsageFromMetricsEndpoint(httpClient *http.Client) aiLLMUsageGetter {
+	return func(ctx context.Context, pod *corev1.Pod) (map[aiLLMUsageKey]aiLLMUsage, error) {
+		url := fmt.Sprintf("http://%s%s",
+			net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(dataPlanePodMetricsPort(pod))), consts.DataPlaneMetricsEndpoint)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+		}
+		return parseAILLMUsage(resp.Body)
+	}
+}
+
+// dataPlanePodMetricsPort returns the port of the metrics endpoint of the
+// provided DataPlane Pod.
+func dataPlanePodMetricsPort(pod *corev1.Pod) int {
+	container := k8sutils.GetPodContainerByName(&pod.Spec, consts.DataPlaneProxyContainerName)
+	if container != nil {
+		for _, p := range container.Ports {
+			if p.Name == "metrics" {
+				return int(p.ContainerPort)
+			}
+		}
+	}
+	return consts.DataPlaneMetricsPort
+}
+
+// parseAILLMUsage parses the AI metrics from the provided metrics in the
+// Prometheus text format.
+func parseAILLMUsage(in io.Reader) (map[aiLLMUsageKey]aiLLMUsage, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing metrics: %w", err)
+	}
+
+	usage := map[aiLLMUsageKey]aiLLMUsage{}
+	key := func(m *dto.Metric) (aiLLMUsageKey, string) {
+		l := make(map[string]string, len(m.GetLabel()))
+		for _, lp := range m.GetLabel() {
+			l[lp.GetName()] = lp.GetValue()
+		}
+		return aiLLMUsageKey{provider: l["ai_provider"], model: l["ai_model"], consumer: l["consumer"]}, l["token_type"]
+	}
+	if family, ok := families[aiLLMRequestsMetric]; ok {
+		for _, m := range family.GetMetric() {
+			k, _ := key(m)
+			usage[k] = usage[k].add(aiLLMUsage{Requests: m.GetCounter().GetValue()})
+		}
+	}
+	if family, ok := families[aiLLMTokensMetric]; ok {
+		for _, m := range family.GetMetric() {
+			k, tokenType := key(m)
+			switch tokenType {
+			case "prompt_tokens":
+				usage[k] = usage[k].add(aiLLMUsage{PromptTokens: m.GetCounter().GetValue()})
+			case "completion_tokens":
+				usage[k] = usage[k].add(aiLLMUsage{CompletionTokens: m.GetCounter().GetValue()})
+			}
+		}
+	}
+	return usage, nil
+}
+
+// getLLMUsage returns the usage of the LLMs reported by every ready Pod of the
+// DataPlanes of the provided Gateway, by Pod UID, querying at most
+// maxConcurrentLLMUsageRequests Pods at a time. Pods which can't be queried are
+// left out. It also returns the UIDs of all the Pods of the DataPlanes.
+func (r *AIGatewayReconciler) getLLMUsage(
+	ctx context.Context,
+	logger logr.Logger,
+	gateway *gatewayv1.Gateway,
+) (map[types.UID]map[aiLLMUsageKey]aiLLMUsage, []types.UID, error) {
+	if gateway == nil {
+		return nil, nil, nil
+	}
+	dataplanes, err := gatewayutils.ListDataPlanesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed listing dataplanes for aigateway: %w", err)
+	}
+
+	var (
+		getUsage    = r.getLLMUsageGetter()
+		mu          sync.Mutex
+		wg          sync.WaitGroup
+		sem         = make(chan struct{}, maxConcurrentLLMUsageRequests)
+		usage       = map[types.UID]map[aiLLMUsageKey]aiLLMUsage{}
+		uids        []types.UID
+		unreachable []string
+	)
+	for _, dataplane := range dataplanes {
+		var pods corev1.PodList
+		if err := r.Client.List(ctx, &pods,
+			client.InNamespace(dataplane.Namespace),
+			client.MatchingLabels{"app": dataplane.Name},
+		); err != nil {
+			return nil, nil, fmt.Errorf("failed listing pods of dataplane %s for aigateway: %w", dataplane.Name, err)
+		}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			uids = append(uids, pod.UID)
+			if !pod.DeletionTimestamp.IsZero() || pod.Status.PodIP == "" || !k8sutils.IsPodReady(pod) {
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				podUsage, err := getUsage(ctx, pod)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					unreachable = append(unreachable, pod.Name)
+					return
+				}
+				usage[pod.UID] = podUsage
+			}()
+		}
+	}
+	wg.Wait()
+	if len(unreachable) > 0 {
+		log.Debug(logger, "failed getting llm usage from dataplane pods", "pods", strings.Join(unreachable, ", "))
+	}
+	return usage, uids, nil
+}
+
+// -----------------------------------------------------------------------------
+// AIGateway - LLM Usage ConfigMap
+// -----------------------------------------------------------------------------
+
+// aiGatewayUsage is the usage of the models of an AIGateway, accumulated by the
+// operator across the DataPlane Pods and their restarts.
+type aiGatewayUsage struct {
+	// SyncedAt is the time the usage was last collected from the DataPlane Pods.
+	SyncedAt metav1.Time `json:"syncedAt"`
+
+	// Models holds the usage of every model with a token budget by consumer.
+	// A model is only present once a DataPlane Pod reported its usage.
+	Models map[string]map[string]aiLLMUsage `json:"models,omitempty"`
+}
+
+// aiLLMUsageCounter is the last counter of an LLM reported by a DataPlane Pod.
+type aiLLMUsageCounter struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Consumer string `json:"consumer,omitempty"`
+	aiLLMUsage
+}
+
+// aiGatewayUsageConfigMapName returns the name of the ConfigMap in which the
+// usage of the models of the provided AIGateway is reported.
+func aiGatewayUsageConfigMapName(aigateway *operatorv1alpha1.AIGateway) string {
+	return aigateway.Name + "-usage"
+}
+
+// syncLLMUsage accumulates the usage of the provided models reported by the
+// DataPlane Pods of the AIGateway's Gateway into the AIGateway's usage
+// ConfigMap, at most once per aiGatewayUsageSyncInterval. The ConfigMap isn't
+// watched so that the usage doesn't trigger reconciliations. It returns the
+// models with their usage reported and the time after which it has to be
+// synced again.
+func (r *AIGatewayReconciler) syncLLMUsage(
+	ctx context.Context,
+	logger logr.Logger,
+	aigateway *operatorv1alpha1.AIGateway,
+	gateway *gatewayv1.Gateway,
+	models []aiGatewayModelStatus,
+) ([]aiGatewayModelStatus, time.Duration, error) {
+	var (
+		cm = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: aigateway.Namespace,
+				Name:      aiGatewayUsageConfigMapName(aigateway),
+			},
+		}
+		usage    aiGatewayUsage
+		counters map[types.UID][]aiLLMUsageCounter
+		exists   = true
+	)
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(&cm), &cm); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, 0, fmt.Errorf("failed getting usage configmap for aigateway: %w", err)
+		}
+		exists = false
+	}
+	if raw, ok := cm.Data[aiGatewayUsageKey]; ok {
+		if err := json.Unmarshal([]byte(raw), &usage); err != nil {
+			log.Debug(logger, "discarding invalid usage of aigateway", "error", err)
+			usage = aiGatewayUsage{}
+		}
+	}
+	if raw, ok := cm.Data[aiGatewayUsageCountersKey]; ok {
+		if err := json.Unmarshal([]byte(raw), &counters); err != nil {
+			log.Debug(logger, "discarding invalid usage counters of aigateway", "error", err)
+			counters = nil
+		}
+	}
+
+	withUsage := func() []aiGatewayModelStatus {
+		for i := range models {
+			_, reported := usage.Models[models[i].identifier]
+			models[i] = models[i].withUsage(reported, cm.Name)
+		}
+		return models
+	}
+	if elapsed := time.Since(usage.SyncedAt.Time); elapsed < aiGatewayUsageSyncInterval {
+		return withUsage(), aiGatewayUsageSyncInterval - elapsed, nil
+	}
+
+	log.Trace(logger, "retrieving llm usage for aigateway")
+	podsUsage, uids, err := r.getLLMUsage(ctx, logger, gateway)
+	if err != nil {
+		return nil, 0, err
+	}
+	usage, counters = accumulateLLMUsage(usage, counters, models, podsUsage, uids)
+	usage.SyncedAt = metav1.Now()
+
+	usageJSON, err := json.Marshal(usage)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed marshaling usage of aigateway: %w", err)
+	}
+	countersJSON, err := json.Marshal(counters)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed marshaling usage counters of aigateway: %w", err)
+	}
+	old := cm.DeepCopy()
+	cm.Labels = aiGatewayResourceLabels(aigateway, "")
+	cm.Data = map[string]string{
+		aiGatewayUsageKey:         string(usageJSON),
+		aiGatewayUsageCountersKey: string(countersJSON),
+	}
+	k8sutils.SetOwnerForObject(&cm, aigateway)
+	if !exists {
+		if err := r.Client.Create(ctx, &cm); err != nil {
+			return nil, 0, fmt.Errorf("failed creating usage configmap for aigateway: %w", err)
+		}
+	} else if err := r.Client.Patch(ctx, &cm, client.MergeFrom(old)); err != nil {
+		return nil, 0, fmt.Errorf("failed patching usage configmap for aigateway: %w", err)
+	}
+	return withUsage(), aiGatewayUsageSyncInterval, nil
+}
+
+// accumulateLLMUsage adds the usage reported by the DataPlane Pods since the
+// provided counters to the usage of the models accounting it, by consumer.
+// Pods which couldn't be queried keep their counters, the counters of Pods
+// which no longer exist are dropped. It returns the updated usage and counters.
+func accumulateLLMUsage(
+	usage aiGatewayUsage,
+	counters map[types.UID][]aiLLMUsageCounter,
+	models []aiGatewayModelStatus,
+	podsUsage map[types.UID]map[aiLLMUsageKey]aiLLMUsage,
+	uids []types.UID,
+) (aiGatewayUsage, map[types.UID][]aiLLMUsageCounter) {
+	delta := map[aiLLMUsageKey]aiLLMUsage{}
+	newCounters := make(map[types.UID][]aiLLMUsageCounter, len(uids))
+	for _, uid := range uids {
+		podUsage, ok := podsUsage[uid]
+		if !ok {
+			if c, ok := counters[uid]; ok {
+				newCounters[uid] = c
+			}
+			continue
+		}
+		previous := make(map[aiLLMUsageKey]aiLLMUsage, len(counters[uid]))
+		for _, c := range counters[uid] {
+			previous[aiLLMUsageKey{provider: c.Provider, model: c.Model, consumer: c.Consumer}] = c.aiLLMUsage
+		}
+		podCounters := make([]aiLLMUsageCounter, 0, len(podUsage))
+		for key, u := range podUsage {
+			delta[key] = delta[key].add(u.since(previous[key]))
+			podCounters = append(podCounters, aiLLMUsageCounter{
+				Provider: key.provider, Model: key.model, Consumer: key.consumer, aiLLMUsage: u,
+			})
+		}
+		slices.SortFunc(podCounters, func(a, b aiLLMUsageCounter) int {
+			return cmp.Or(
+				strings.Compare(a.Provider, b.Provider),
+				strings.Compare(a.Model, b.Model),
+				strings.Compare(a.Consumer, b.Consumer),
+			)
+		})
+		newCounters[uid] = podCounters
+	}
+
+	accumulated := make(map[string]map[string]aiLLMUsage)
+	for _, model := range models {
+		if !model.accountUsage {
+			continue
+		}
+		consumers, reported := usage.Models[model.identifier]
+		if !reported && len(podsUsage) == 0 {
+			continue
+		}
+		consumers = maps.Clone(consumers)
+		if consumers == nil {
+			consumers = map[string]aiLLMUsage{}
+		}
+		for key, u := range delta {
+			if key.servedBy(model.usageKeys) {
+				consumers[key.consumer] = consumers[key.consumer].add(u)
+			}
+		}
+		accumulated[model.identifier] = consumers
+	}
+	usage.Models = accumulated
+	return usage, newCounters
+}
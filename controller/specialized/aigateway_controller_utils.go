@@ -12,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
 	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
@@ -67,6 +68,22 @@ func getAuthHeaderForInference(provider operatorv1alpha1.AICloudProvider) (map[s
 // AIGateway - Generators
 // ----------------------------------------------------------------------------
 
+// aiGatewayResourceLabels returns the labels of the resources generated for the
+// AIGateway, used to find them when they have to be pruned or cleaned up.
+// identifier is the identifier of the LLM the resource is generated for, it's
+// empty for the resources shared by all the LLMs.
+func aiGatewayResourceLabels(aigateway *operatorv1alpha1.AIGateway, identifier string) map[string]string {
+	labels := map[string]string{
+		consts.GatewayOperatorManagedByLabel:          consts.AIGatewayManagedLabelValue,
+		consts.GatewayOperatorManagedByNameLabel:      aigateway.Name,
+		consts.GatewayOperatorManagedByNamespaceLabel: aigateway.Namespace,
+	}
+	if identifier != "" {
+		labels[consts.AIGatewayModelLabel] = identifier
+	}
+	return labels
+}
+
 // aiGatewayToGateway takes an accepted/validated v1alpha1.AIGateway struct and produces a v1.Gateway (k8sig resources)
 // and a v1beta1.GatewayConfiguration (kong extensions) that will host the Large Language Model deployments
 func aiGatewayToGateway(
@@ -80,6 +97,7 @@ func aiGatewayToGateway(
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      aigateway.Name,
 			Namespace: aigateway.Namespace,
+			Labels:    aiGatewayResourceLabels(aigateway, ""),
 		},
 		Spec: gatewayv1.GatewaySpec{
 			GatewayClassName: gatewayv1.ObjectName(aigateway.Spec.GatewayClassName),
@@ -99,18 +117,19 @@ func aiGatewayToGateway(
 	return gateway
 }
 
-// aiCloudGatewayToDecoratorPlugin take an accepted/validated vXalphaY.CloudHostedLargeLanguageModel struct
+// aiCloudGatewayToDecoratorPlugin takes the identifier and the default prompts of an LLM
 // and produces an ai-prompt-decorator vX.KongPlugin if required
 func aiCloudGatewayToKongPromptDecoratorPlugin(
-	aiCloudGateway *operatorv1alpha1.CloudHostedLargeLanguageModel,
+	identifier string,
+	defaultPrompts []operatorv1alpha1.LLMPrompt,
 	aigateway *operatorv1alpha1.AIGateway,
 ) (*configurationv1.KongPlugin, error) {
 	var thisDecoratorPlugin *configurationv1.KongPlugin
 
-	if len(aiCloudGateway.DefaultPrompts) > 0 {
+	if len(defaultPrompts) > 0 {
 		thisPluginConfig := AICloudPromptDecoratorConfig{
 			&AICloudPromptDecoratorPrompts{
-				Prepend: aiCloudGateway.DefaultPrompts,
+				Prepend: defaultPrompts,
 			},
 		}
 
@@ -118,7 +137,7 @@ func aiCloudGatewayToKongPromptDecoratorPlugin(
 		if err != nil {
 			return nil, fmt.Errorf(
 				"ai cloud gateway with Identifier '%s' resource could not be parsed into a ai-prompt-decorator KongPlugin configuration, check object",
-				aiCloudGateway.Identifier,
+				identifier,
 			)
 		}
 
@@ -128,13 +147,14 @@ func aiCloudGatewayToKongPromptDecoratorPlugin(
 				APIVersion: configurationv1.SchemeGroupVersion.String(),
 			},
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      fmt.Sprintf("%s-ai-prompt-decorator", aiCloudGateway.Identifier),
+				Name:      fmt.Sprintf("%s-ai-prompt-decorator", identifier),
 				Namespace: aigateway.Namespace,
+				Labels:    aiGatewayResourceLabels(aigateway, identifier),
 			},
 
 			PluginName:   "ai-prompt-decorator",
 			Protocols:    configurationv1.StringsToKongProtocols([]string{"http", "https"}),
-			InstanceName: fmt.Sprintf("%s-ai-prompt-decorator", aiCloudGateway.Identifier),
+			InstanceName: fmt.Sprintf("%s-ai-prompt-decorator", identifier),
 			Config: v1.JSON{
 				Raw: thisPluginConfBytes,
 			},
@@ -157,6 +177,7 @@ func aiCloudGatewayToKubeSvc(aiGateway *operatorv1alpha1.AIGateway) *corev1.Serv
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-ai-sink", aiGateway.Name),
 			Namespace: aiGateway.Namespace,
+			Labels:    aiGatewayResourceLabels(aiGateway, ""),
 			Annotations: map[string]string{
 				"konghq.com/protocol": "https",
 				"konghq.com/retries":  "1",
@@ -181,22 +202,23 @@ func aiCloudGatewayToKubeSvc(aiGateway *operatorv1alpha1.AIGateway) *corev1.Serv
 	return svc
 }
 
-// aiCloudGatewayToHTTPRoute takes an AIGateway, and a CloudHostedLargeLanguageModel,
+// aiCloudGatewayToHTTPRoute takes an AIGateway, and the identifier of one of its LLMs,
 // and produces an HTTPRoute that will become the egress point for this provider/model combo.
 func aiCloudGatewayToHTTPRoute(
-	aiCloudLLM *operatorv1alpha1.CloudHostedLargeLanguageModel,
+	identifier string,
 	aigateway *operatorv1alpha1.AIGateway,
 	kubeSvc *corev1.Service,
 	plugins []string,
 ) *gatewayv1.HTTPRoute {
 	backendKind := "Service"
 	matchType := "Exact"
-	exactPath := fmt.Sprintf("/%s", aiCloudLLM.Identifier)
+	exactPath := fmt.Sprintf("/%s", identifier)
 
 	httpRoute := &gatewayv1.HTTPRoute{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-egress", aiCloudLLM.Identifier),
+			Name:      fmt.Sprintf("%s-egress", identifier),
 			Namespace: aigateway.Namespace,
+			Labels:    aiGatewayResourceLabels(aigateway, identifier),
 			Annotations: map[string]string{
 				metadata.AnnotationKeyPlugins: strings.Join(plugins, ","),
 			},
@@ -249,24 +271,30 @@ func aiCloudGatewayToKongPlugin(
 	aigateway *operatorv1alpha1.AIGateway,
 	credentialData *[]byte,
 ) (*configurationv1.KongPlugin, error) {
-	providerName := string(aiCloudLLM.AICloudProvider.Name)
-	routeType := ""
-	switch *aiCloudLLM.PromptType {
-	case operatorv1alpha1.LLMPromptTypeChat:
-		routeType = "llm/v1/chat"
+	thisAIProxyPluginConfig, err := aiCloudProviderLLMConfig(aiCloudLLM, aiCloudLLM.AICloudProvider, aiCloudLLM.Model, credentialData)
+	if err != nil {
+		return nil, err
+	}
 
-	case operatorv1alpha1.LLMPromptTypeCompletion:
-		routeType = "llm/v1/completions"
+	return aiProxyKongPlugin(aiCloudLLM.Identifier, "ai-proxy", aigateway, thisAIProxyPluginConfig)
+}
 
-	default:
-		return nil, fmt.Errorf(
-			"ai cloud gateway with Identifier '%s' uses prompt type '%s' but it is not yet supported",
-			aiCloudLLM.Identifier,
-			string(*aiCloudLLM.PromptType))
+// aiCloudProviderLLMConfig produces the LLM configuration of the AI proxy plugins
+// for a cloud hosted LLM served by the provided cloud provider and model.
+func aiCloudProviderLLMConfig(
+	aiCloudLLM *operatorv1alpha1.CloudHostedLargeLanguageModel,
+	provider operatorv1alpha1.AICloudProvider,
+	model *string,
+	credentialData *[]byte,
+) (*AICloudProviderLLMConfig, error) {
+	providerName := string(provider.Name)
+	routeType, err := aiProxyRouteType(aiCloudLLM.Identifier, aiCloudLLM.PromptType)
+	if err != nil {
+		return nil, err
 	}
 
 	// Find and parse the auth header format
-	authHeader, err := getAuthHeaderForInference(aiCloudLLM.AICloudProvider)
+	authHeader, err := getAuthHeaderForInference(provider)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"ai cloud gateway with Identifier '%s' does not have auth header info defined, %w",
@@ -289,7 +317,7 @@ func aiCloudGatewayToKongPlugin(
 		},
 		Model: &AICloudProviderModelConfig{
 			Provider: &providerName,
-			Name:     aiCloudLLM.Model,
+			Name:     model,
 			Options:  &AICloudProviderOptionsConfig{},
 		},
 	}
@@ -300,11 +328,89 @@ func aiCloudGatewayToKongPlugin(
 		thisAIProxyPluginConfig.Model.Options.Temperature = aiCloudLLM.DefaultPromptParams.Temperature
 	}
 
-	thisAIProxyPluginConfigJSON, err := json.Marshal(&thisAIProxyPluginConfig)
+	return &thisAIProxyPluginConfig, nil
+}
+
+// aiSelfHostedLLMToKongPlugin takes a validated SelfHostedLargeLanguageModel
+// and transforms it into a vX.KongPlugin proxying requests to its in-cluster
+// model server. credentialData is nil when the model server doesn't require
+// credentials.
+func aiSelfHostedLLMToKongPlugin(
+	llm *SelfHostedLargeLanguageModel,
+	aigateway *operatorv1alpha1.AIGateway,
+	credentialData *[]byte,
+) (*configurationv1.KongPlugin, error) {
+	routeType, err := aiProxyRouteType(llm.Identifier, llm.PromptType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Self-hosted model servers are proxied by the llama2 provider of the
+	// ai-proxy plugin which supports custom upstream URLs and API formats.
+	providerName := "llama2"
+	upstreamURL := selfHostedLLMUpstreamURL(llm)
+	format := string(llm.Format)
+	thisAIProxyPluginConfig := AICloudProviderLLMConfig{
+		RouteType: &routeType,
+		Logging: &AICloudProviderLoggingConfig{
+			LogStatistics: true,
+			LogPayloads:   false,
+		},
+		Model: &AICloudProviderModelConfig{
+			Provider: &providerName,
+			Name:     llm.Model,
+			Options: &AICloudProviderOptionsConfig{
+				UpstreamURL:  &upstreamURL,
+				Llama2Format: &format,
+			},
+		},
+	}
+	if llm.Auth != nil && credentialData != nil {
+		authHeaderValue := fmt.Sprintf(llm.Auth.HeaderPattern, string(*credentialData))
+		thisAIProxyPluginConfig.Auth = &AICloudProviderAuthConfig{
+			HeaderName:  &llm.Auth.HeaderName,
+			HeaderValue: &authHeaderValue,
+		}
+	}
+	if llm.DefaultPromptParams != nil {
+		thisAIProxyPluginConfig.Model.Options.MaxTokens = llm.DefaultPromptParams.MaxTokens
+		thisAIProxyPluginConfig.Model.Options.Temperature = llm.DefaultPromptParams.Temperature
+	}
+
+	return aiProxyKongPlugin(llm.Identifier, "ai-proxy", aigateway, &thisAIProxyPluginConfig)
+}
+
+// aiProxyRouteType returns the ai-proxy plugin route type for the prompt type
+// of the LLM with the provided identifier.
+func aiProxyRouteType(identifier string, promptType *operatorv1alpha1.LLMPromptType) (string, error) {
+	switch *promptType {
+	case operatorv1alpha1.LLMPromptTypeChat:
+		return "llm/v1/chat", nil
+
+	case operatorv1alpha1.LLMPromptTypeCompletion:
+		return "llm/v1/completions", nil
+
+	default:
+		return "", fmt.Errorf(
+			"ai cloud gateway with Identifier '%s' uses prompt type '%s' but it is not yet supported",
+			identifier,
+			string(*promptType))
+	}
+}
+
+// aiProxyKongPlugin produces the vX.KongPlugin of the provided AI proxy plugin
+// for the LLM with the provided identifier.
+func aiProxyKongPlugin(
+	identifier string,
+	pluginName string,
+	aigateway *operatorv1alpha1.AIGateway,
+	config any,
+) (*configurationv1.KongPlugin, error) {
+	thisAIProxyPluginConfigJSON, err := json.Marshal(config)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"ai cloud gateway with Identifier '%s' resource could not be parsed into a KongPlugin configuration, check object",
-			aiCloudLLM.Identifier)
+			identifier)
 	}
 
 	thisAIProxyPlugin := configurationv1.KongPlugin{
@@ -313,13 +419,14 @@ func aiCloudGatewayToKongPlugin(
 			APIVersion: configurationv1.SchemeGroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-ai-proxy", aiCloudLLM.Identifier),
+			Name:      fmt.Sprintf("%s-%s", identifier, pluginName),
 			Namespace: aigateway.Namespace,
+			Labels:    aiGatewayResourceLabels(aigateway, identifier),
 		},
 
-		PluginName:   "ai-proxy",
+		PluginName:   pluginName,
 		Protocols:    configurationv1.StringsToKongProtocols([]string{"http", "https"}),
-		InstanceName: fmt.Sprintf("%s-ai-proxy", aiCloudLLM.Identifier),
+		InstanceName: fmt.Sprintf("%s-%s", identifier, pluginName),
 		Config: v1.JSON{
 			Raw: thisAIProxyPluginConfigJSON,
 		},
@@ -0,0 +1,50 @@
+package specialized
+
+import (
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// -----------------------------------------------------------------------------
+// AIGateway - Annotations Configuration
+// -----------------------------------------------------------------------------
+
+// aiGatewayAnnotationsConfig is the configuration of an AIGateway set through
+// its annotations.
+type aiGatewayAnnotationsConfig struct {
+	selfHostedLLMs []SelfHostedLargeLanguageModel
+	modelBackends  map[string]AIGatewayModelBackends
+	tokenBudgets   map[string]AIGatewayTokenBudget
+	promptPolicies map[string]AIGatewayPromptPolicy
+}
+
+// aiGatewayAnnotationsConfigFor parses and validates the configuration set
+// through the annotations of the provided AIGateway.
+func aiGatewayAnnotationsConfigFor(aigateway *operatorv1alpha1.AIGateway) (*aiGatewayAnnotationsConfig, error) {
+	var (
+		cfg aiGatewayAnnotationsConfig
+		err error
+	)
+	if cfg.selfHostedLLMs, err = selfHostedLLMsForAIGateway(aigateway); err != nil {
+		return nil, err
+	}
+	if cfg.modelBackends, err = modelBackendsForAIGateway(aigateway); err != nil {
+		return nil, err
+	}
+	if cfg.tokenBudgets, err = tokenBudgetsForAIGateway(aigateway, cfg.selfHostedLLMs); err != nil {
+		return nil, err
+	}
+	if cfg.promptPolicies, err = promptPoliciesForAIGateway(aigateway, cfg.selfHostedLLMs); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// ValidateAIGateway validates the configuration set through the annotations of
+// the provided AIGateway. It's used at admission time so that invalid
+// AIGateways are rejected before being reconciled. The AIGatewayReconciler
+// marks the invalid AIGateways which weren't validated at admission time as
+// not accepted.
+func ValidateAIGateway(aigateway *operatorv1alpha1.AIGateway) error {
+	_, err := aiGatewayAnnotationsConfigFor(aigateway)
+	return err
+}
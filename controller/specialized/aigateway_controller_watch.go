@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 
 	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -15,6 +17,7 @@ import (
 
 	operatorerrors "github.com/kong/gateway-operator/internal/errors"
 	"github.com/kong/gateway-operator/internal/utils/gatewayclass"
+	"github.com/kong/gateway-operator/pkg/consts"
 
 	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
 )
@@ -82,6 +85,106 @@ func (r *AIGatewayReconciler) listAIGatewaysForGatewayClass(ctx context.Context,
 	return
 }
 
+// listAIGatewaysForGateway returns a request for the AIGateway owning the
+// Gateway so that its status follows the Gateway's status.
+func (r *AIGatewayReconciler) listAIGatewaysForGateway(ctx context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := obj.(*gatewayv1.Gateway)
+	if !ok {
+		ctrllog.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "Gateway", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+
+	for _, ownerRef := range gateway.GetOwnerReferences() {
+		if ownerRef.Kind != "AIGateway" || !strings.HasPrefix(ownerRef.APIVersion, operatorv1alpha1.SchemeGroupVersion.Group+"/") {
+			continue
+		}
+		return []reconcile.Request{
+			{
+				NamespacedName: types.NamespacedName{
+					Namespace: gateway.Namespace,
+					Name:      ownerRef.Name,
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// listAIGatewaysForSecret returns a request for every AIGateway referencing the
+// Secret, either as its cloud provider credentials or in the secret references
+// of its model backends and self-hosted LLMs, so that rotated API keys are
+// propagated to the AIGateway's credentials Secret.
+func (r *AIGatewayReconciler) listAIGatewaysForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		ctrllog.FromContext(ctx).Error(
+			operatorerrors.ErrUnexpectedObject,
+			"failed to run map funcs",
+			"expected", "Secret", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+
+	aigateways := new(operatorv1alpha1.AIGatewayList)
+	if err := r.Client.List(ctx, aigateways); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "could not list aigateways in map func")
+		return nil
+	}
+
+	var recs []reconcile.Request
+	for i := range aigateways.Items {
+		aigateway := &aigateways.Items[i]
+		if aiGatewayReferencesSecret(aigateway, secret.Namespace, secret.Name) {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(aigateway),
+			})
+		}
+	}
+	return recs
+}
+
+// aiGatewayReferencesSecret returns true when the AIGateway references the
+// Secret with the provided namespace and name. Annotations which can't be
+// parsed don't reference any Secret.
+func aiGatewayReferencesSecret(aigateway *operatorv1alpha1.AIGateway, namespace, name string) bool {
+	matches := func(refNamespace, refName string) bool {
+		return lo.CoalesceOrEmpty(refNamespace, aigateway.Namespace) == namespace && refName == name
+	}
+
+	if ref := aigateway.Spec.CloudProviderCredentials; ref != nil &&
+		matches(lo.FromPtr(ref.Namespace), ref.Name) {
+		return true
+	}
+	if selfHostedLLMs, err := selfHostedLLMsForAIGateway(aigateway); err == nil {
+		for _, llm := range selfHostedLLMs {
+			if llm.Auth != nil && matches(llm.Auth.SecretRef.Namespace, llm.Auth.SecretRef.Name) {
+				return true
+			}
+		}
+	}
+	if modelBackends, err := modelBackendsForAIGateway(aigateway); err == nil {
+		for _, backends := range modelBackends {
+			for _, backend := range backends.Backends {
+				if backend.SecretRef != nil && matches(backend.SecretRef.Namespace, backend.SecretRef.Name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// secretIsNotManagedByAIGateway is the predicate function for watching Secrets.
+// The credentials Secrets managed by the AIGateway controller are filtered out
+// as they are only updated by the controller itself.
+func secretIsNotManagedByAIGateway(obj client.Object) bool {
+	return obj.GetLabels()[consts.GatewayOperatorManagedByLabel] != consts.AIGatewayManagedLabelValue
+}
+
 // listAIGatewaysForReferenceGrants lists AIGateways whose group, kind and namespace appeared in `spec.from` of ReferenceGrants.
 // The listed AIGateways in are allowed to reference the resources in the `spec.to` of the ReferenceGrant.
 func (r *AIGatewayReconciler) listAIGatewaysForReferenceGrants(ctx context.Context, obj client.Object) []reconcile.Request {
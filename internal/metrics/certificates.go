@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// MetricNameManagedCertificateExpiry is the metric of expiry timestamps of the certificates managed by the operator.
+	MetricNameManagedCertificateExpiry = "gateway_operator_managed_certificate_expiry_timestamp_seconds"
+	// NamespaceKey is the namespace of the object.
+	NamespaceKey = "namespace"
+	// NameKey is the name of the object.
+	NameKey = "name"
+	// SecretKey is the name of the Secret storing a certificate.
+	SecretKey = "secret"
+)
+
+var managedCertificateExpiry = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: MetricNameManagedCertificateExpiry,
+		Help: "Unix timestamp at which the certificates issued by the operator with its cluster CA expire. " +
+			"`" + KindKey + "`, `" + NamespaceKey + "` and `" + NameKey + "` describe the object owning the certificate. " +
+			"`" + SecretKey + "` describes the name of the Secret storing it.",
+	},
+	[]string{KindKey, NamespaceKey, NameKey, SecretKey},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(managedCertificateExpiry)
+}
+
+// RecordManagedCertificateExpiry records the expiry of the certificate stored in
+// the Secret owned by the object of the given kind.
+func RecordManagedCertificateExpiry(kind string, owner k8stypes.NamespacedName, secret string, notAfter time.Time) {
+	managedCertificateExpiry.WithLabelValues(kind, owner.Namespace, owner.Name, secret).Set(float64(notAfter.Unix()))
+}
+
+// DeleteManagedCertificateExpiry removes the expiry of the certificate stored in
+// the Secret, e.g. after it has been replaced.
+func DeleteManagedCertificateExpiry(namespace, secret string) {
+	managedCertificateExpiry.DeletePartialMatch(prometheus.Labels{NamespaceKey: namespace, SecretKey: secret})
+}
+
+// DeleteManagedCertificateExpiryForOwner removes the expiries of the certificates
+// owned by the deleted object of the given kind.
+func DeleteManagedCertificateExpiryForOwner(kind string, owner k8stypes.NamespacedName) {
+	managedCertificateExpiry.DeletePartialMatch(prometheus.Labels{KindKey: kind, NamespaceKey: owner.Namespace, NameKey: owner.Name})
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestRecordManagedCertificateExpiry(t *testing.T) {
+	dp := k8stypes.NamespacedName{Namespace: "ns", Name: "dp"}
+	cp := k8stypes.NamespacedName{Namespace: "ns", Name: "cp"}
+	notAfter := time.Unix(1_900_000_000, 0)
+
+	RecordManagedCertificateExpiry("DataPlane", dp, "dp-cert-1", notAfter)
+	RecordManagedCertificateExpiry("ControlPlane", cp, "cp-cert-1", notAfter)
+	RecordManagedCertificateExpiry("ControlPlane", cp, "cp-cert-2", notAfter)
+	require.Equal(t, float64(notAfter.Unix()),
+		testutil.ToFloat64(managedCertificateExpiry.WithLabelValues("DataPlane", "ns", "dp", "dp-cert-1")),
+	)
+	require.Equal(t, 3, testutil.CollectAndCount(managedCertificateExpiry))
+
+	t.Log("replaced certificate's expiry is removed")
+	DeleteManagedCertificateExpiry("ns", "dp-cert-1")
+	require.Equal(t, 2, testutil.CollectAndCount(managedCertificateExpiry))
+
+	t.Log("expiries of certificates owned by a deleted object are removed")
+	DeleteManagedCertificateExpiryForOwner("ControlPlane", cp)
+	require.Equal(t, 0, testutil.CollectAndCount(managedCertificateExpiry))
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// DataPlaneRolloutOutcome is the outcome of a DataPlane's BlueGreen rollout.
+type DataPlaneRolloutOutcome string
+
+const (
+	// DataPlaneRolloutOutcomePromoted means that the preview resources have been promoted to live.
+	DataPlaneRolloutOutcomePromoted DataPlaneRolloutOutcome = "promoted"
+	// DataPlaneRolloutOutcomePromotionFailed means that the preview resources failed to be promoted.
+	DataPlaneRolloutOutcomePromotionFailed DataPlaneRolloutOutcome = "promotion_failed"
+	// DataPlaneRolloutOutcomeRolloutFailed means that the preview resources failed to be deployed.
+	DataPlaneRolloutOutcomeRolloutFailed DataPlaneRolloutOutcome = "rollout_failed"
+)
+
+const (
+	// MetricNameDataPlaneRolloutPhaseDuration is the metric of durations of the phases of DataPlanes' BlueGreen rollouts.
+	MetricNameDataPlaneRolloutPhaseDuration = "gateway_operator_dataplane_rollout_phase_duration_seconds"
+	// MetricNameDataPlaneRolloutCount is the metric of number of DataPlanes' BlueGreen rollouts, grouped by outcome.
+	MetricNameDataPlaneRolloutCount = "gateway_operator_dataplane_rollout_count"
+	// PhaseKey is the phase of a DataPlane's rollout, i.e. the reason of its RolledOut condition.
+	PhaseKey = "phase"
+	// OutcomeKey is the outcome of a DataPlane's rollout.
+	OutcomeKey = "outcome"
+)
+
+var (
+	dataPlaneRolloutPhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: MetricNameDataPlaneRolloutPhaseDuration,
+			Help: "How long did the phases of DataPlanes' BlueGreen rollouts take in seconds. " +
+				"`" + PhaseKey + "` describes the phase, i.e. the reason of the DataPlane's `RolledOut` condition during it.",
+			// Duration range from 1s to 1 day.
+			Buckets: prometheus.ExponentialBucketsRange(1, time.Hour.Seconds()*24, 20),
+		},
+		[]string{PhaseKey},
+	)
+
+	dataPlaneRolloutCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricNameDataPlaneRolloutCount,
+			Help: fmt.Sprintf(
+				"Count of finished DataPlanes' BlueGreen rollouts. "+
+					"`%s` describes whether the preview resources have been promoted (`%s`), "+
+					"failed to be promoted (`%s`) or failed to be deployed (`%s`).",
+				OutcomeKey, DataPlaneRolloutOutcomePromoted, DataPlaneRolloutOutcomePromotionFailed, DataPlaneRolloutOutcomeRolloutFailed,
+			),
+		},
+		[]string{OutcomeKey},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(dataPlaneRolloutPhaseDuration, dataPlaneRolloutCount)
+}
+
+// RecordDataPlaneRolloutPhase records the duration of a finished phase of a DataPlane's BlueGreen rollout.
+func RecordDataPlaneRolloutPhase(phase string, duration time.Duration) {
+	dataPlaneRolloutPhaseDuration.WithLabelValues(phase).Observe(duration.Seconds())
+}
+
+// RecordDataPlaneRolloutOutcome records the outcome of a DataPlane's BlueGreen rollout.
+func RecordDataPlaneRolloutOutcome(outcome DataPlaneRolloutOutcome) {
+	dataPlaneRolloutCount.WithLabelValues(string(outcome)).Inc()
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// MetricNamePausedObjects is the metric of number of objects whose reconciliation is paused, grouped by kind.
+	MetricNamePausedObjects = "gateway_operator_paused_objects"
+	// KindKey is the kind of the object.
+	KindKey = "kind"
+)
+
+var (
+	pausedObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricNamePausedObjects,
+			Help: "Number of objects whose reconciliation is paused with the `gateway-operator.konghq.com/paused` annotation. " +
+				"`" + KindKey + "` describes the kind of the paused objects.",
+		},
+		[]string{KindKey},
+	)
+
+	pausedObjectsTracker = pausedTracker{
+		objects: make(map[string]map[k8stypes.NamespacedName]struct{}),
+	}
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(pausedObjects)
+}
+
+type pausedTracker struct {
+	lock    sync.Mutex
+	objects map[string]map[k8stypes.NamespacedName]struct{}
+}
+
+// RecordObjectPaused records whether reconciliation of the object of the given
+// kind is paused and updates the number of paused objects of that kind.
+// Deleted objects have to be recorded as not paused.
+func RecordObjectPaused(kind string, nn k8stypes.NamespacedName, paused bool) {
+	t := &pausedObjectsTracker
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	objects, ok := t.objects[kind]
+	if !ok {
+		objects = make(map[k8stypes.NamespacedName]struct{})
+		t.objects[kind] = objects
+	}
+	if paused {
+		objects[nn] = struct{}{}
+	} else {
+		delete(objects, nn)
+	}
+	pausedObjects.WithLabelValues(kind).Set(float64(len(objects)))
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestRecordObjectPaused(t *testing.T) {
+	const kind = "TestKind"
+	nn1 := k8stypes.NamespacedName{Namespace: "ns", Name: "obj-1"}
+	nn2 := k8stypes.NamespacedName{Namespace: "ns", Name: "obj-2"}
+
+	RecordObjectPaused(kind, nn1, true)
+	RecordObjectPaused(kind, nn2, true)
+	require.Equal(t, float64(2), testutil.ToFloat64(pausedObjects.WithLabelValues(kind)))
+
+	t.Log("recording the same object again doesn't change the number of paused objects")
+	RecordObjectPaused(kind, nn1, true)
+	require.Equal(t, float64(2), testutil.ToFloat64(pausedObjects.WithLabelValues(kind)))
+
+	RecordObjectPaused(kind, nn1, false)
+	require.Equal(t, float64(1), testutil.ToFloat64(pausedObjects.WithLabelValues(kind)))
+	RecordObjectPaused(kind, nn2, false)
+	require.Equal(t, float64(0), testutil.ToFloat64(pausedObjects.WithLabelValues(kind)))
+}
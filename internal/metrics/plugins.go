@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// MetricNameKongPluginFetchCount is the metric of number of fetches of KongPluginInstallations' images, grouped by registry and successful status.
+	MetricNameKongPluginFetchCount = "gateway_operator_kong_plugin_fetch_count"
+	// MetricNameKongPluginFetchDuration is the metric of durations of the fetches.
+	MetricNameKongPluginFetchDuration = "gateway_operator_kong_plugin_fetch_duration_seconds"
+	// RegistryKey is the registry an image is fetched from.
+	RegistryKey = "registry"
+)
+
+var (
+	kongPluginFetchCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: MetricNameKongPluginFetchCount,
+			Help: fmt.Sprintf(
+				"Count of successful/failed fetches of KongPluginInstallations' images. "+
+					"`%s` describes the registry the image is fetched from and is empty if the image URL is invalid. "+
+					"`%s` describes whether the fetch is successful (`%s`) or not (`%s`).",
+				RegistryKey, SuccessKey, SuccessTrue, SuccessFalse,
+			),
+		},
+		[]string{RegistryKey, SuccessKey},
+	)
+
+	kongPluginFetchDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: MetricNameKongPluginFetchDuration,
+			Help: fmt.Sprintf(
+				"How long did the fetches of KongPluginInstallations' images take in seconds. "+
+					"`%s` describes the registry the image is fetched from and is empty if the image URL is invalid. "+
+					"`%s` describes whether the fetch is successful (`%s`) or not (`%s`).",
+				RegistryKey, SuccessKey, SuccessTrue, SuccessFalse,
+			),
+			// Duration range from 10ms to 5min.
+			Buckets: prometheus.ExponentialBucketsRange(0.01, (5 * time.Minute).Seconds(), 20),
+		},
+		[]string{RegistryKey, SuccessKey},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(kongPluginFetchCount, kongPluginFetchDuration)
+}
+
+// RecordKongPluginFetch records a fetch of a KongPluginInstallation's image from the registry.
+func RecordKongPluginFetch(registry string, success bool, duration time.Duration) {
+	s := SuccessFalse
+	if success {
+		s = SuccessTrue
+	}
+	kongPluginFetchCount.WithLabelValues(registry, s).Inc()
+	kongPluginFetchDuration.WithLabelValues(registry, s).Observe(duration.Seconds())
+}
@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/pkg/vars"
+
+	kcfgdataplane "github.com/kong/kubernetes-configuration/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// MetricNameObjectReady is the metric of readiness of the objects managed by the operator.
+const MetricNameObjectReady = "gateway_operator_object_ready"
+
+// ReadinessKind is a kind of objects whose readiness is reported by the ReadinessCollector.
+type ReadinessKind string
+
+const (
+	// ReadinessKindGateway reports whether the Gateways of GatewayClasses
+	// managed by the operator are Programmed.
+	ReadinessKindGateway ReadinessKind = "Gateway"
+	// ReadinessKindDataPlane reports whether the DataPlanes are Ready.
+	ReadinessKindDataPlane ReadinessKind = "DataPlane"
+	// ReadinessKindControlPlane reports whether the ControlPlanes are Ready.
+	ReadinessKindControlPlane ReadinessKind = "ControlPlane"
+)
+
+// readinessCollectTimeout is the timeout of listing the objects of all the kinds
+// when the metrics are scraped.
+const readinessCollectTimeout = 10 * time.Second
+
+var objectReadyDesc = prometheus.NewDesc(
+	MetricNameObjectReady,
+	"Whether the object managed by the operator is ready (1) or not (0). "+
+		"Gateways are ready when they're Programmed, DataPlanes and ControlPlanes when they're Ready. "+
+		"`"+KindKey+"`, `"+NamespaceKey+"` and `"+NameKey+"` describe the object.",
+	[]string{KindKey, NamespaceKey, NameKey},
+	nil,
+)
+
+// ReadinessCollector is a prometheus.Collector reporting readiness of the objects
+// of the configured kinds. The objects are listed when the metrics are scraped,
+// so that the metric always reflects their current status, including deletions.
+// The reader should be backed by the manager's cache.
+type ReadinessCollector struct {
+	reader client.Reader
+	kinds  []ReadinessKind
+}
+
+var _ prometheus.Collector = &ReadinessCollector{}
+
+// NewReadinessCollector returns a ReadinessCollector listing the objects of the
+// provided kinds with the reader.
+func NewReadinessCollector(reader client.Reader, kinds ...ReadinessKind) *ReadinessCollector {
+	return &ReadinessCollector{
+		reader: reader,
+		kinds:  kinds,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ReadinessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- objectReadyDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *ReadinessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), readinessCollectTimeout)
+	defer cancel()
+
+	for _, kind := range c.kinds {
+		if err := c.collect(ctx, kind, ch); err != nil {
+			ch <- prometheus.NewInvalidMetric(objectReadyDesc, fmt.Errorf("failed collecting readiness of %ss: %w", kind, err))
+		}
+	}
+}
+
+func (c *ReadinessCollector) collect(ctx context.Context, kind ReadinessKind, ch chan<- prometheus.Metric) error {
+	send := func(obj metav1.Object, conditions []metav1.Condition, conditionType string) {
+		var value float64
+		if meta.IsStatusConditionTrue(conditions, conditionType) {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(objectReadyDesc, prometheus.GaugeValue, value,
+			string(kind), obj.GetNamespace(), obj.GetName(),
+		)
+	}
+
+	switch kind {
+	case ReadinessKindGateway:
+		var gatewayClasses gatewayv1.GatewayClassList
+		if err := c.reader.List(ctx, &gatewayClasses); err != nil {
+			return err
+		}
+		managed := make(map[gatewayv1.ObjectName]struct{}, len(gatewayClasses.Items))
+		for _, gwc := range gatewayClasses.Items {
+			if string(gwc.Spec.ControllerName) == vars.ControllerName() {
+				managed[gatewayv1.ObjectName(gwc.Name)] = struct{}{}
+			}
+		}
+
+		var gateways gatewayv1.GatewayList
+		if err := c.reader.List(ctx, &gateways); err != nil {
+			return err
+		}
+		for i := range gateways.Items {
+			gw := &gateways.Items[i]
+			if _, ok := managed[gw.Spec.GatewayClassName]; !ok {
+				continue
+			}
+			send(gw, gw.Status.Conditions, string(gatewayv1.GatewayConditionProgrammed))
+		}
+
+	case ReadinessKindDataPlane:
+		var dataplanes operatorv1beta1.DataPlaneList
+		if err := c.reader.List(ctx, &dataplanes); err != nil {
+			return err
+		}
+		for i := range dataplanes.Items {
+			dp := &dataplanes.Items[i]
+			send(dp, dp.Status.Conditions, string(kcfgdataplane.ReadyType))
+		}
+
+	case ReadinessKindControlPlane:
+		var controlplanes operatorv1beta1.ControlPlaneList
+		if err := c.reader.List(ctx, &controlplanes); err != nil {
+			return err
+		}
+		for i := range controlplanes.Items {
+			cp := &controlplanes.Items[i]
+			send(cp, cp.Status.Conditions, string(kcfgdataplane.ReadyType))
+		}
+
+	default:
+		return fmt.Errorf("unsupported kind %s", kind)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/gateway-operator/pkg/vars"
+
+	kcfgdataplane "github.com/kong/kubernetes-configuration/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestReadinessCollector(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, gatewayv1.Install(s))
+	require.NoError(t, operatorv1beta1.AddToScheme(s))
+
+	condition := func(cType string, status metav1.ConditionStatus) []metav1.Condition {
+		return []metav1.Condition{{Type: cType, Status: status}}
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(
+			&gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+				Spec:       gatewayv1.GatewayClassSpec{ControllerName: gatewayv1.GatewayController(vars.ControllerName())},
+			},
+			&gatewayv1.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Spec:       gatewayv1.GatewayClassSpec{ControllerName: "example.com/other"},
+			},
+			&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "gw"},
+				Spec:       gatewayv1.GatewaySpec{GatewayClassName: "kong"},
+				Status: gatewayv1.GatewayStatus{
+					Conditions: condition(string(gatewayv1.GatewayConditionProgrammed), metav1.ConditionTrue),
+				},
+			},
+			&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "gw-other"},
+				Spec:       gatewayv1.GatewaySpec{GatewayClassName: "other"},
+			},
+			&operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dp"},
+				Status: operatorv1beta1.DataPlaneStatus{
+					Conditions: condition(string(kcfgdataplane.ReadyType), metav1.ConditionFalse),
+				},
+			},
+			&operatorv1beta1.ControlPlane{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cp"},
+				Status: operatorv1beta1.ControlPlaneStatus{
+					Conditions: condition(string(kcfgdataplane.ReadyType), metav1.ConditionTrue),
+				},
+			},
+		).
+		Build()
+
+	t.Log("objects of all the configured kinds are reported, Gateways of unmanaged GatewayClasses are skipped")
+	collector := NewReadinessCollector(cl, ReadinessKindGateway, ReadinessKindDataPlane, ReadinessKindControlPlane)
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP gateway_operator_object_ready Whether the object managed by the operator is ready (1) or not (0). Gateways are ready when they're Programmed, DataPlanes and ControlPlanes when they're Ready. `+"`kind`, `namespace` and `name`"+` describe the object.
+# TYPE gateway_operator_object_ready gauge
+gateway_operator_object_ready{kind="ControlPlane",name="cp",namespace="ns"} 1
+gateway_operator_object_ready{kind="DataPlane",name="dp",namespace="ns"} 0
+gateway_operator_object_ready{kind="Gateway",name="gw",namespace="ns"} 1
+`), MetricNameObjectReady))
+
+	t.Log("objects of kinds which are not configured are not reported")
+	collector = NewReadinessCollector(cl, ReadinessKindDataPlane)
+	require.Equal(t, 1, testutil.CollectAndCount(collector, MetricNameObjectReady))
+}
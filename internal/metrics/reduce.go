@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// MetricNameReducedDuplicates is the metric of number of deleted duplicates of managed objects, grouped by kind.
+const MetricNameReducedDuplicates = "gateway_operator_reduced_duplicates_count"
+
+var reducedDuplicates = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: MetricNameReducedDuplicates,
+		Help: "Count of deleted duplicates of objects managed by the operator. " +
+			"`" + KindKey + "` describes the kind of the deleted objects.",
+	},
+	[]string{KindKey},
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(reducedDuplicates)
+}
+
+// RecordDuplicatesReduced records the number of deleted duplicates of the given kind.
+func RecordDuplicatesReduced(kind string, count int) {
+	reducedDuplicates.WithLabelValues(kind).Add(float64(count))
+}
@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+// NewClient wraps the provided client so that every write (create, update,
+// patch and delete, including subresources) is recorded as a span.
+// Reads are served from the cache and are not traced.
+//
+// Created objects get the span's traceparent in consts.TraceParentAnnotation,
+// which is refreshed when they are updated or patched, so that their
+// reconciliation can be linked to the trace which wrote them (see
+// WithWriterLinks). Objects not created by the operator are not annotated.
+func NewClient(c client.Client) client.Client {
+	return &tracingClient{Client: c}
+}
+
+type tracingClient struct {
+	client.Client
+}
+
+func (c *tracingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	ctx, span := c.startSpan(ctx, "create", "", obj)
+	injectTraceParent(ctx, obj)
+	err := c.Client.Create(ctx, obj, opts...)
+	EndSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	ctx, span := c.startSpan(ctx, "update", "", obj)
+	refreshTraceParent(ctx, obj)
+	err := c.Client.Update(ctx, obj, opts...)
+	EndSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	ctx, span := c.startSpan(ctx, "patch", "", obj)
+	refreshTraceParent(ctx, obj)
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	EndSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	ctx, span := c.startSpan(ctx, "delete", "", obj)
+	err := c.Client.Delete(ctx, obj, opts...)
+	EndSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	ctx, span := c.startSpan(ctx, "deletecollection", "", obj)
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	EndSpan(span, err)
+	return err
+}
+
+func (c *tracingClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *tracingClient) SubResource(subResource string) client.SubResourceClient {
+	return &tracingSubResourceClient{
+		SubResourceClient: c.Client.SubResource(subResource),
+		client:            c,
+		subResource:       subResource,
+	}
+}
+
+// refreshTraceParent updates consts.TraceParentAnnotation of the provided
+// object if it has one, i.e. if it was created by the operator.
+func refreshTraceParent(ctx context.Context, obj client.Object) {
+	if _, ok := obj.GetAnnotations()[consts.TraceParentAnnotation]; ok {
+		injectTraceParent(ctx, obj)
+	}
+}
+
+// startSpan starts a span for the client operation on the provided object.
+func (c *tracingClient) startSpan(ctx context.Context, op string, subResource string, obj client.Object) (context.Context, trace.Span) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		kind = gvk.Kind
+	}
+	name := "k8s " + op + " " + kind
+	if subResource != "" {
+		name += "/" + subResource
+	}
+	return Tracer(ctx).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String(KindKey, kind),
+			attribute.String(NamespaceKey, obj.GetNamespace()),
+			attribute.String(NameKey, obj.GetName()),
+		),
+	)
+}
+
+type tracingSubResourceClient struct {
+	client.SubResourceClient
+	client      *tracingClient
+	subResource string
+}
+
+func (c *tracingSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	ctx, span := c.client.startSpan(ctx, "create", c.subResource, obj)
+	err := c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+	EndSpan(span, err)
+	return err
+}
+
+func (c *tracingSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	ctx, span := c.client.startSpan(ctx, "update", c.subResource, obj)
+	err := c.SubResourceClient.Update(ctx, obj, opts...)
+	EndSpan(span, err)
+	return err
+}
+
+func (c *tracingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	ctx, span := c.client.startSpan(ctx, "patch", c.subResource, obj)
+	err := c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+	EndSpan(span, err)
+	return err
+}
@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+func TestClient(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	injector := ctxinjector.NewCtxInjector(TracerProviderInjector(tp))
+	ctx := injector.InjectKeyValues(t.Context())
+
+	cl := NewClient(fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithStatusSubresource(&corev1.Service{}).
+		Build(),
+	)
+
+	ctx, parent := Tracer(ctx).Start(ctx, "reconcile")
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "svc",
+		},
+	}
+	require.NoError(t, cl.Create(ctx, svc))
+	require.NoError(t, cl.Status().Update(ctx, svc))
+	require.NoError(t, cl.Delete(ctx, svc))
+	require.Error(t, cl.Delete(ctx, svc))
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 5)
+	expectedNames := []string{
+		"k8s create Service",
+		"k8s update Service/status",
+		"k8s delete Service",
+		"k8s delete Service",
+	}
+	for i, name := range expectedNames {
+		span := spans[i]
+		assert.Equal(t, name, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), "client spans have to be children of the reconcile span")
+		assert.Contains(t, span.Attributes(), attribute.String(KindKey, "Service"))
+		assert.Contains(t, span.Attributes(), attribute.String(NamespaceKey, "ns"))
+		assert.Contains(t, span.Attributes(), attribute.String(NameKey, "svc"))
+	}
+	assert.Equal(t, codes.Unset, spans[2].Status().Code)
+	assert.Equal(t, codes.Error, spans[3].Status().Code, "failed delete has to be recorded as an error")
+}
+
+func TestClientTraceParentAnnotation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	injector := ctxinjector.NewCtxInjector(TracerProviderInjector(tp))
+	ctx := injector.InjectKeyValues(t.Context())
+
+	userSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "user",
+		},
+	}
+	cl := NewClient(fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(userSvc).
+		Build(),
+	)
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "svc",
+		},
+	}
+	require.NoError(t, cl.Create(ctx, svc))
+	created := svc.Annotations[consts.TraceParentAnnotation]
+	require.NotEmpty(t, created, "created objects have to be annotated")
+	require.Equal(t, recorder.Ended()[0].SpanContext().SpanID(), spanContextFromObject(ctx, svc).SpanID())
+
+	require.NoError(t, cl.Update(ctx, svc))
+	require.NotEqual(t, created, svc.Annotations[consts.TraceParentAnnotation], "annotation has to be refreshed on update")
+	require.Equal(t, recorder.Ended()[1].SpanContext().SpanID(), spanContextFromObject(ctx, svc).SpanID())
+
+	require.NoError(t, cl.Patch(ctx, userSvc, client.MergeFrom(userSvc.DeepCopy())))
+	require.NotContains(t, userSvc.Annotations, consts.TraceParentAnnotation, "objects not created by the operator must not be annotated")
+
+	require.NoError(t, cl.Create(t.Context(), &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "untraced",
+		},
+	}))
+	untraced := &corev1.Service{}
+	require.NoError(t, cl.Get(t.Context(), client.ObjectKey{Namespace: "ns", Name: "untraced"}, untraced))
+	require.NotContains(t, untraced.Annotations, consts.TraceParentAnnotation, "objects created without a span must not be annotated")
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+// traceParentKey is the key of the W3C traceparent in the TraceContext carrier.
+const traceParentKey = "traceparent"
+
+// injectTraceParent sets consts.TraceParentAnnotation on the provided object
+// to the traceparent of the span from the provided context. It returns false
+// when the context has no valid span, e.g. when tracing is disabled.
+func injectTraceParent(ctx context.Context, obj client.Object) bool {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	traceParent, ok := carrier[traceParentKey]
+	if !ok {
+		return false
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[consts.TraceParentAnnotation] = traceParent
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// spanContextFromObject returns the span context stored in the
+// consts.TraceParentAnnotation of the provided object. The returned span
+// context is invalid when the object has no such annotation.
+func spanContextFromObject(ctx context.Context, obj client.Object) trace.SpanContext {
+	traceParent, ok := obj.GetAnnotations()[consts.TraceParentAnnotation]
+	if !ok {
+		return trace.SpanContext{}
+	}
+	ctx = propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+	return trace.SpanContextFromContext(ctx)
+}
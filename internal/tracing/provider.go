@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/kong/gateway-operator/modules/manager/metadata"
+)
+
+// Config configures the export of traces.
+type Config struct {
+	// OTLPEndpoint is the host:port of the OTLP gRPC collector traces are exported to.
+	OTLPEndpoint string
+	// Insecure disables TLS when connecting to the collector.
+	Insecure bool
+	// SamplingRatio is the ratio of the traces which are sampled, between 0 and 1.
+	SamplingRatio float64
+}
+
+// SetupTracerProvider sets up the global TracerProvider exporting traces
+// to the configured OTLP collector and the global W3C trace context propagator.
+// It returns a function which flushes the remaining spans and shuts down
+// the provider.
+func SetupTracerProvider(ctx context.Context, cfg Config, meta metadata.Info) (func(context.Context) error, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(meta.ProjectName),
+			semconv.ServiceVersion(meta.Release),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp.Shutdown, nil
+}
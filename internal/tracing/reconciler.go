@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NewReconciler wraps the provided reconciler so that every Reconcile call
+// is recorded as a span. Spans started during the reconciliation, e.g. for
+// Kubernetes client writes or Konnect API calls, are its children.
+func NewReconciler(controllerName string, r reconcile.Reconciler, opts ...ReconcilerOption) reconcile.Reconciler {
+	rec := &reconciler{
+		name:       controllerName,
+		reconciler: r,
+	}
+	for _, opt := range opts {
+		opt(rec)
+	}
+	return rec
+}
+
+// ReconcilerOption configures the reconciler returned by NewReconciler.
+type ReconcilerOption func(*reconciler)
+
+// WithWriterLinks makes the reconciliation spans link to the span which last
+// wrote the reconciled object, as stored in its consts.TraceParentAnnotation,
+// e.g. the reconciliation of the Gateway which created the reconciled DataPlane.
+// The object is read with the provided reader, into a copy of obj.
+func WithWriterLinks(reader client.Reader, obj client.Object) ReconcilerOption {
+	return func(r *reconciler) {
+		r.reader = reader
+		r.obj = obj
+	}
+}
+
+type reconciler struct {
+	name       string
+	reconciler reconcile.Reconciler
+	reader     client.Reader
+	obj        client.Object
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	opts := []trace.SpanStartOption{
+		trace.WithAttributes(
+			attribute.String(ControllerKey, r.name),
+			attribute.String(ReconcileIDKey, string(controller.ReconcileIDFromContext(ctx))),
+			attribute.String(NamespaceKey, req.Namespace),
+			attribute.String(NameKey, req.Name),
+		),
+	}
+	if sc := r.writerSpanContext(ctx, req); sc.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	ctx, span := Tracer(ctx).Start(ctx, "reconcile "+r.name, opts...)
+	res, err := r.reconciler.Reconcile(ctx, req)
+	if res.RequeueAfter > 0 {
+		span.SetAttributes(attribute.String(RequeueAfterKey, res.RequeueAfter.String()))
+	}
+	EndSpan(span, err)
+	return res, err
+}
+
+// writerSpanContext returns the span context of the span which last wrote
+// the reconciled object, if configured with WithWriterLinks and known.
+func (r *reconciler) writerSpanContext(ctx context.Context, req ctrl.Request) trace.SpanContext {
+	if r.reader == nil {
+		return trace.SpanContext{}
+	}
+	obj, ok := r.obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return trace.SpanContext{}
+	}
+	// The reconciler reads the object anyway so errors are left to it.
+	if err := r.reader.Get(ctx, req.NamespacedName, obj); err != nil {
+		return trace.SpanContext{}
+	}
+	return spanContextFromObject(ctx, obj)
+}
@@ -0,0 +1,131 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+)
+
+func TestReconciler(t *testing.T) {
+	req := ctrl.Request{NamespacedName: k8stypes.NamespacedName{Namespace: "ns", Name: "dp"}}
+
+	testCases := []struct {
+		name           string
+		result         ctrl.Result
+		err            error
+		expectedStatus codes.Code
+		expectedAttrs  []attribute.KeyValue
+	}{
+		{
+			name:           "successful reconciliation",
+			result:         ctrl.Result{RequeueAfter: time.Minute},
+			expectedStatus: codes.Unset,
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String(ControllerKey, "DataPlane"),
+				attribute.String(NamespaceKey, "ns"),
+				attribute.String(NameKey, "dp"),
+				attribute.String(RequeueAfterKey, "1m0s"),
+			},
+		},
+		{
+			name:           "failed reconciliation",
+			err:            errors.New("failed"),
+			expectedStatus: codes.Error,
+			expectedAttrs: []attribute.KeyValue{
+				attribute.String(ControllerKey, "DataPlane"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			injector := ctxinjector.NewCtxInjector(TracerProviderInjector(tp))
+
+			var reconcileSpan trace.SpanContext
+			r := NewReconciler("DataPlane", reconcile.Func(func(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+				reconcileSpan = trace.SpanContextFromContext(ctx)
+				return tc.result, tc.err
+			}))
+
+			res, err := r.Reconcile(injector.InjectKeyValues(t.Context()), req)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.result, res)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, "reconcile DataPlane", span.Name())
+			assert.Equal(t, tc.expectedStatus, span.Status().Code)
+			assert.Equal(t, reconcileSpan, span.SpanContext(), "span has to be propagated to the wrapped reconciler")
+			for _, attr := range tc.expectedAttrs {
+				assert.Contains(t, span.Attributes(), attr)
+			}
+		})
+	}
+}
+
+func TestReconcilerWithWriterLinks(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	injector := ctxinjector.NewCtxInjector(TracerProviderInjector(tp))
+	ctx := injector.InjectKeyValues(t.Context())
+
+	cl := NewClient(fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		Build(),
+	)
+
+	writer := NewReconciler("Gateway", reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: req.Namespace,
+				Name:      req.Name,
+			},
+		}
+		err := cl.Create(ctx, svc)
+		return ctrl.Result{}, err
+	}))
+	owned := NewReconciler("Service", reconcile.Func(func(context.Context, ctrl.Request) (ctrl.Result, error) {
+		return ctrl.Result{}, nil
+	}), WithWriterLinks(cl, &corev1.Service{}))
+
+	req := ctrl.Request{NamespacedName: k8stypes.NamespacedName{Namespace: "ns", Name: "svc"}}
+	_, err := owned.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = writer.Reconcile(ctx, req)
+	require.NoError(t, err)
+	_, err = owned.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	assert.Equal(t, "reconcile Service", spans[0].Name())
+	assert.Empty(t, spans[0].Links(), "objects which do not exist yet cannot be linked")
+	assert.Equal(t, "k8s create Service", spans[1].Name())
+	writerSpan := spans[1].SpanContext()
+	assert.Equal(t, "reconcile Service", spans[3].Name())
+	require.Len(t, spans[3].Links(), 1)
+	assert.Equal(t, writerSpan.TraceID(), spans[3].Links()[0].SpanContext.TraceID())
+	assert.Equal(t, writerSpan.SpanID(), spans[3].Links()[0].SpanContext.SpanID())
+	assert.NotEqual(t, writerSpan.TraceID(), spans[3].SpanContext().TraceID(), "reconciliation has to start its own trace")
+}
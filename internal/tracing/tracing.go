@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
+)
+
+// TracerName is the name of the tracer used by the operator.
+const TracerName = "github.com/kong/gateway-operator"
+
+// Attribute keys used in the operator's spans.
+const (
+	// ControllerKey is the name of the controller which reconciles the object.
+	ControllerKey = "gateway_operator.controller"
+	// ReconcileIDKey is the ID of the reconciliation assigned by controller-runtime.
+	ReconcileIDKey = "gateway_operator.reconcile_id"
+	// RequeueAfterKey is the duration after which the reconciled object is requeued.
+	RequeueAfterKey = "gateway_operator.requeue_after"
+	// KindKey is the kind of the Kubernetes object.
+	KindKey = "k8s.object.kind"
+	// NamespaceKey is the namespace of the Kubernetes object.
+	NamespaceKey = "k8s.namespace.name"
+	// NameKey is the name of the Kubernetes object.
+	NameKey = "k8s.object.name"
+	// KonnectEntityTypeKey is the type of the Konnect entity.
+	KonnectEntityTypeKey = "konnect.entity_type"
+	// KonnectIDKey is the Konnect ID of the entity.
+	KonnectIDKey = "konnect.id"
+	// KonnectServerURLKey is the URL of the Konnect server.
+	KonnectServerURLKey = "konnect.server_url"
+	// HTTPStatusCodeKey is the HTTP status code of the response.
+	HTTPStatusCodeKey = "http.response.status_code"
+)
+
+type tracerProviderKey struct{}
+
+// TracerProviderInjector returns a ctxinjector.KeyValueInjectorFunc which
+// injects the provided TracerProvider into the context. Spans started by
+// Tracer for such a context use the injected provider.
+func TracerProviderInjector(tp trace.TracerProvider) ctxinjector.KeyValueInjectorFunc {
+	return func() (any, any) {
+		return tracerProviderKey{}, tp
+	}
+}
+
+// Tracer returns the operator's tracer from the TracerProvider injected into
+// the context or from the global TracerProvider when there's none.
+func Tracer(ctx context.Context) trace.Tracer {
+	if tp, ok := ctx.Value(tracerProviderKey{}).(trace.TracerProvider); ok {
+		return tp.Tracer(TracerName)
+	}
+	return otel.GetTracerProvider().Tracer(TracerName)
+}
+
+// EndSpan records the error, if any, in the span and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
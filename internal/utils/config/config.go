@@ -16,6 +16,9 @@ const (
 
 	kongLuaPackagePathVarName      = "KONG_LUA_PACKAGE_PATH"
 	kongLuaPackagePathDefaultValue = "/opt/?.lua;;"
+	// kongLuaPackagePathDirectoryModulesValue resolves the modules made of a
+	// directory with an init.lua file as well.
+	kongLuaPackagePathDirectoryModulesValue = "/opt/?.lua;/opt/?/init.lua;;"
 )
 
 // -----------------------------------------------------------------------------
@@ -63,7 +66,10 @@ func EnvVarMapToSlice(envMap map[string]string) []corev1.EnvVar {
 // needed for configuring the Kong Gateway with the provided Kong Plugin
 // names. If kongPluginNames is nil or empty, nil is returned. Kong will use bundled
 // plugins by default if we do not override `KONG_PLUGINS`.
-func ConfigureKongPluginRelatedEnvVars(kongPluginNames []string) []corev1.EnvVar {
+// The Lua package path resolves modules made of a directory with an init.lua
+// file only when withDirectoryModules is true, so that the Pods using other
+// plugins aren't restarted.
+func ConfigureKongPluginRelatedEnvVars(kongPluginNames []string, withDirectoryModules bool) []corev1.EnvVar {
 	if len(kongPluginNames) == 0 {
 		return nil
 	}
@@ -71,6 +77,10 @@ func ConfigureKongPluginRelatedEnvVars(kongPluginNames []string) []corev1.EnvVar
 	// Const "bundled" is required to have the default plugins enabled.
 	kpiNames = append(kpiNames, kongPluginsDefaultValue)
 	kpiNames = append(kpiNames, kongPluginNames...)
+	luaPackagePath := kongLuaPackagePathDefaultValue
+	if withDirectoryModules {
+		luaPackagePath = kongLuaPackagePathDirectoryModulesValue
+	}
 	return []corev1.EnvVar{
 		{
 			Name:  kongPluginsEnvVarName,
@@ -78,7 +88,7 @@ func ConfigureKongPluginRelatedEnvVars(kongPluginNames []string) []corev1.EnvVar
 		},
 		{
 			Name:  kongLuaPackagePathVarName,
-			Value: kongLuaPackagePathDefaultValue,
+			Value: luaPackagePath,
 		},
 	}
 }
@@ -13,7 +13,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/kong/gateway-operator/controller/pkg/extensions"
+	"github.com/kong/gateway-operator/controller/pkg/secrets/ref"
+	"github.com/kong/gateway-operator/pkg/consts"
 
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
 	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
 )
@@ -29,6 +32,11 @@ const (
 	// KonnectExtensionIndex is the key to be used to access the .spec.extensions indexed values,
 	// in a form of list of namespace/name strings.
 	KonnectExtensionIndex = "KonnectExtension"
+
+	// SecretsIndex is the key to be used to access the Secrets referenced by KongPluginInstallations
+	// (.spec.imagePullSecretRef and the Secret with the keys trusted to sign the image),
+	// in a form of list of namespace/name strings.
+	SecretsIndex = "Secrets"
 )
 
 // DataPlaneNameOnControlPlane indexes the ControlPlane .spec.dataplaneName field
@@ -83,6 +91,40 @@ func KongPluginInstallationsOnDataPlane(ctx context.Context, c cache.Cache) erro
 	)
 }
 
+// SecretsOnKongPluginInstallation indexes the Secrets referenced by the KongPluginInstallation
+// on the "Secrets" key.
+func SecretsOnKongPluginInstallation(ctx context.Context, c cache.Cache) error {
+	if _, err := c.GetInformer(ctx, &operatorv1alpha1.KongPluginInstallation{}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get informer for v1alpha1 KongPluginInstallation: %w, disabling indexing Secrets for KongPluginInstallations", err)
+	}
+	return c.IndexField(ctx, &operatorv1alpha1.KongPluginInstallation{}, SecretsIndex, SecretsReferencedByKongPluginInstallation)
+}
+
+// SecretsReferencedByKongPluginInstallation returns the namespace/name strings of the Secrets
+// referenced by the KongPluginInstallation, it's the extract function of the "Secrets" index.
+func SecretsReferencedByKongPluginInstallation(o client.Object) []string {
+	kpi, ok := o.(*operatorv1alpha1.KongPluginInstallation)
+	if !ok {
+		return nil
+	}
+	var result []string
+	if name, ok := kpi.Annotations[consts.KongPluginInstallationSignatureVerificationSecretAnnotation]; ok {
+		result = append(result, kpi.Namespace+"/"+name)
+	}
+	if secretRef := kpi.Spec.ImagePullSecretRef; secretRef != nil &&
+		ref.DoesFieldReferenceCoreV1Secret(*secretRef, "imagePullSecretRef") == nil {
+		namespace := kpi.Namespace
+		if secretRef.Namespace != nil && *secretRef.Namespace != "" {
+			namespace = string(*secretRef.Namespace)
+		}
+		result = append(result, namespace+"/"+string(secretRef.Name))
+	}
+	return result
+}
+
 // ExtendableOnKonnectExtension indexes the Object .spec.extensions field
 // on the "KonnectExtension" key.
 func ExtendableOnKonnectExtension[T extensions.ExtendableT](ctx context.Context, c cache.Cache, obj T) error {
@@ -13,6 +13,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kong/gateway-operator/controller/specialized"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
 )
 
@@ -81,6 +84,11 @@ var (
 		Version:  operatorv1beta1.SchemeGroupVersion.Version,
 		Resource: "dataplanes",
 	}
+	aiGatewayGVResource = metav1.GroupVersionResource{
+		Group:    operatorv1alpha1.SchemeGroupVersion.Group,
+		Version:  operatorv1alpha1.SchemeGroupVersion.Version,
+		Resource: "aigateways",
+	}
 )
 
 func (h *RequestHandler) handleValidation(_ context.Context, req *admissionv1.AdmissionRequest) (
@@ -126,6 +134,18 @@ func (h *RequestHandler) handleValidation(_ context.Context, req *admissionv1.Ad
 				return nil, err
 			}
 		}
+	case aiGatewayGVResource:
+		if req.Operation == admissionv1.Create || req.Operation == admissionv1.Update {
+			aiGateway := operatorv1alpha1.AIGateway{}
+			_, _, err := deserializer.Decode(req.Object.Raw, nil, &aiGateway)
+			if err != nil {
+				return nil, err
+			}
+			if err := specialized.ValidateAIGateway(&aiGateway); err != nil {
+				ok = false
+				msg = err.Error()
+			}
+		}
 	}
 
 	response.UID = req.UID
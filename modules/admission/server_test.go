@@ -17,6 +17,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
 )
 
@@ -114,3 +117,106 @@ func TestHandleDataPlaneValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleAIGatewayValidation(t *testing.T) {
+	handler := NewRequestHandler(fakeclient.NewClientBuilder().Build(), logr.Discard())
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	aiGateway := func(annotations map[string]string) *operatorv1alpha1.AIGateway {
+		return &operatorv1alpha1.AIGateway{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: operatorv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "AIGateway",
+			},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ai", Annotations: annotations},
+			Spec: operatorv1alpha1.AIGatewaySpec{
+				LargeLanguageModels: &operatorv1alpha1.LargeLanguageModels{
+					CloudHosted: []operatorv1alpha1.CloudHostedLargeLanguageModel{
+						{
+							Identifier:      "gpt",
+							AICloudProvider: operatorv1alpha1.AICloudProvider{Name: operatorv1alpha1.AICloudProviderOpenAI},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		aiGateway *operatorv1alpha1.AIGateway
+		hasError  bool
+		errMsg    string
+	}{
+		{
+			name:      "without annotations",
+			aiGateway: aiGateway(nil),
+		},
+		{
+			name: "with valid prompt policies",
+			aiGateway: aiGateway(map[string]string{
+				consts.AIGatewayPromptPoliciesAnnotation: `[{"identifier":"gpt","promptGuard":{"denyPatterns":[".*password.*"]}}]`,
+			}),
+		},
+		{
+			name: "with prompt policies of an unknown model",
+			aiGateway: aiGateway(map[string]string{
+				consts.AIGatewayPromptPoliciesAnnotation: `[{"identifier":"llama","promptGuard":{"denyPatterns":[".*password.*"]}}]`,
+			}),
+			hasError: true,
+			errMsg:   "identifier 'llama' in gateway-operator.konghq.com/prompt-policies annotation does not match any LLM",
+		},
+		{
+			name: "with invalid prompt policies",
+			aiGateway: aiGateway(map[string]string{
+				consts.AIGatewayPromptPoliciesAnnotation: `[{"identifier":"gpt","piiRedaction":{"categories":["passport"]}}]`,
+			}),
+			hasError: true,
+			errMsg: "invalid policy at index 0 in gateway-operator.konghq.com/prompt-policies annotation: " +
+				"piiRedaction category 'passport' is not supported (supported categories: general, phone, email, creditcard, crypto, " +
+				"date, ip, nationality, ner, domain, url, ssn, medical, bank, credentials, custom, all, all_and_credentials)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			review := &admissionv1.AdmissionReview{
+				Request: &admissionv1.AdmissionRequest{
+					Kind: metav1.GroupVersionKind{
+						Group:   operatorv1alpha1.SchemeGroupVersion.Group,
+						Version: operatorv1alpha1.SchemeGroupVersion.Version,
+						Kind:    "AIGateway",
+					},
+					Resource:  aiGatewayGVResource,
+					Name:      tc.aiGateway.Name,
+					Namespace: tc.aiGateway.Namespace,
+					Operation: admissionv1.Create,
+					Object: runtime.RawExtension{
+						Object: tc.aiGateway,
+					},
+				},
+			}
+
+			buf, err := json.Marshal(review)
+			require.NoError(t, err)
+			resp, err := http.Post(server.URL, "application/json", bytes.NewReader(buf))
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			resp.Body.Close()
+			respReview := &admissionv1.AdmissionReview{}
+			require.NoError(t, json.Unmarshal(body, respReview))
+			validationResp := respReview.Response
+
+			if !tc.hasError {
+				require.True(t, validationResp.Allowed)
+				require.EqualValues(t, http.StatusOK, validationResp.Result.Code)
+			} else {
+				require.False(t, validationResp.Allowed)
+				require.EqualValues(t, http.StatusBadRequest, validationResp.Result.Code)
+				require.Equal(t, tc.errMsg, validationResp.Result.Message)
+			}
+		})
+	}
+}
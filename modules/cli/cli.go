@@ -4,10 +4,14 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/samber/lo"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/kong/gateway-operator/modules/manager"
@@ -46,12 +50,15 @@ func New(m metadata.Info) *CLI {
 	flagSet.StringVar(&deferCfg.ClusterCASecretNamespace, "cluster-ca-secret-namespace", "", "Name of the namespace for Secret containing the cluster CA certificate.")
 	flagSet.Var(&cfg.ClusterCAKeyType, "cluster-ca-key-type", "Type of the key used for the cluster CA certificate (possible values: ecdsa, rsa). Default: ecdsa.")
 	flagSet.IntVar(&cfg.ClusterCAKeySize, "cluster-ca-key-size", mgrconfig.DefaultClusterCAKeySize, "Size (in bits) of the key used for the cluster CA certificate. Only used for RSA keys.")
+	flagSet.IntVar(&cfg.ShardCount, "shard-count", 0, "Number of shards the reconciled objects are distributed across (by hashing their namespace). When set, every replica of the operator runs the controllers and reconciles only the objects from the shards it owns through Leases in the leader election namespace. Disabled when 0 (default).")
+	flagSet.StringVar(&deferCfg.WatchNamespaces, "watch-namespaces", "", "Comma-separated list of namespaces to watch. If empty (default), all namespaces are watched. When set, ControlPlanes are granted namespaced Roles instead of ClusterRoles and controllers that require cluster-scoped resources (e.g. Gateway) have to be disabled.")
 
 	// controllers for standard APIs and features
 	flagSet.BoolVar(&cfg.GatewayControllerEnabled, "enable-controller-gateway", true, "Enable the Gateway controller.")
 	flagSet.BoolVar(&cfg.ControlPlaneControllerEnabled, "enable-controller-controlplane", true, "Enable the ControlPlane controller.")
 	flagSet.BoolVar(&cfg.DataPlaneControllerEnabled, "enable-controller-dataplane", true, "Enable the DataPlane controller.")
 	flagSet.BoolVar(&cfg.DataPlaneBlueGreenControllerEnabled, "enable-controller-dataplane-bluegreen", true, "Enable the DataPlane BlueGreen controller. Mutually exclusive with DataPlane controller.")
+	flagSet.DurationVar(&cfg.DataPlaneConfigSyncCheckInterval, "dataplane-config-sync-check-interval", 0, "Interval at which the configuration loaded by DataPlane Pods is checked and reported in the DataPlane's ConfigurationSynced condition. The check is disabled when set to 0, which is the default.")
 
 	// controllers for specialized APIs and features
 	flagSet.BoolVar(&cfg.AIGatewayControllerEnabled, "enable-controller-aigateway", false, "Enable the AIGateway controller. (Experimental).")
@@ -63,14 +70,23 @@ func New(m metadata.Info) *CLI {
 	flagSet.DurationVar(&cfg.KonnectSyncPeriod, "konnect-sync-period", consts.DefaultKonnectSyncPeriod, "Sync period for Konnect entities. After a successful reconciliation of Konnect entities the controller will wait this duration before enforcing configuration on Konnect once again.")
 	flagSet.UintVar(&cfg.KonnectMaxConcurrentReconciles, "konnect-controller-max-concurrent-reconciles", consts.DefaultKonnectMaxConcurrentReconciles, "Maximum number of concurrent reconciles for Konnect entities.")
 
+	// tracing options
+	flagSet.StringVar(&cfg.TracingOTLPEndpoint, "tracing-otlp-endpoint", "", "The host:port of the OTLP gRPC collector to export traces of reconciliations, Kubernetes client writes and Konnect API calls to. Tracing is disabled when empty (default).")
+	flagSet.BoolVar(&cfg.TracingOTLPInsecure, "tracing-otlp-insecure", false, "Disable TLS when connecting to the OTLP collector.")
+	flagSet.Float64Var(&cfg.TracingSamplingRatio, "tracing-sampling-ratio", 1, "Ratio of sampled traces, between 0 and 1. Traces started by a sampled parent are always sampled.")
+
 	// webhook and validation options
-	var validatingWebhookEnabled bool
-	flagSet.BoolVar(&validatingWebhookEnabled, "enable-validating-webhook", false, "Enable the validating webhook. DEPRECATED: This flag is no-op and will be removed in a future release.")
+	flagSet.BoolVar(&cfg.ValidatingWebhookEnabled, "enable-validating-webhook", false, "Enable the validating webhook for AIGateways. It's served along with the conversion webhook, on the port and through the Service configured for it, with a certificate issued by the cluster CA.")
 	var validatingWebhookConfigBaseImage string
 	flagSet.StringVar(&validatingWebhookConfigBaseImage, "webhook-certificate-config-base-image", consts.WebhookCertificateConfigBaseImage, "The base image for the certgen Jobs. DEPRECATED: This flag is no-op and will be removed in a future release.")
 	var validatingWebhookConfigShellImage string
 	flagSet.StringVar(&validatingWebhookConfigShellImage, "webhook-certificate-config-shell-image", consts.WebhookCertificateConfigShellImage, "The shell image for the certgen Jobs. DEPRECATED: This flag is no-op and will be removed in a future release.")
 
+	flagSet.BoolVar(&cfg.ConversionWebhookEnabled, "enable-conversion-webhook", false, "Enable the conversion webhook for the operator's CRDs served at multiple versions. The operator configures such CRDs to use the webhook, which is served with a certificate issued by the cluster CA.")
+	flagSet.IntVar(&cfg.ConversionWebhookPort, "conversion-webhook-port", manager.DefaultConversionWebhookPort, "The port the conversion and the validating webhooks listen on.")
+	flagSet.StringVar(&cfg.ConversionWebhookServiceName, "conversion-webhook-service-name", manager.DefaultConversionWebhookServiceName, "Name of the Service in the operator's namespace through which the API server reaches the conversion and the validating webhooks.")
+
+	flagSet.StringVar(&deferCfg.ConfigFile, "config-file", "", "Path to the operator's configuration file (OperatorConfiguration). Settings which are not set in the file fall back to the flags' values and flags which are explicitly set take precedence over the file. Reloadable settings are applied without restart when the file changes.")
 	flagSet.BoolVar(&deferCfg.Version, "version", false, "Print version information.")
 
 	developmentModeEnabled := manager.DefaultConfig().DevelopmentMode
@@ -88,6 +104,7 @@ func New(m metadata.Info) *CLI {
 		cfg:             &cfg,
 		loggerOpts:      loggerOpts,
 		deferFlagValues: &deferCfg,
+		envBoundFlags:   make(map[string]struct{}),
 		metadata:        m,
 	}
 }
@@ -101,6 +118,8 @@ type CLI struct {
 	// logic after parsing flagSet to determine desired configuration.
 	deferFlagValues *flagsForFurtherEvaluation
 	cfg             *manager.Config
+	// envBoundFlags contains names of flags which values were set from environment variables.
+	envBoundFlags map[string]struct{}
 
 	metadata metadata.Info
 }
@@ -108,6 +127,8 @@ type CLI struct {
 type flagsForFurtherEvaluation struct {
 	DisableLeaderElection    bool
 	ClusterCASecretNamespace string
+	WatchNamespaces          string
+	ConfigFile               string
 	Version                  bool
 }
 
@@ -133,6 +154,7 @@ func (c *CLI) bindEnvVarsToFlags() (err error) {
 			if err := f.Value.Set(envValue); err != nil {
 				panic(err)
 			}
+			c.envBoundFlags[f.Name] = struct{}{}
 		}
 	})
 
@@ -169,6 +191,13 @@ func (c *CLI) Parse(arguments []string) manager.Config {
 		os.Exit(1)
 	}
 
+	if c.deferFlagValues.ConfigFile != "" {
+		if err := c.applyConfigurationFile(c.deferFlagValues.ConfigFile); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
 	anonymousReportsEnabled := c.cfg.AnonymousReports
 	if developmentModeEnabled {
 		// If developmentModeEnabled is true, we want to disable `telemetry` to not pollute telemetry results.
@@ -224,6 +253,8 @@ func (c *CLI) Parse(arguments []string) manager.Config {
 	c.cfg.LoggerOpts = logging.SetupLogEncoder(c.cfg.DevelopmentMode || c.loggerOpts.Development, c.loggerOpts)
 	c.cfg.LeaderElectionNamespace = controllerNamespace
 	c.cfg.AnonymousReports = anonymousReportsEnabled
+	c.cfg.WatchNamespaces = parseWatchNamespaces(c.deferFlagValues.WatchNamespaces)
+	c.cfg.ConfigFilePath = c.deferFlagValues.ConfigFile
 
 	return *c.cfg
 }
@@ -234,3 +265,51 @@ func (c *CLI) Parse(arguments []string) manager.Config {
 func (c *CLI) FlagSet() *flag.FlagSet {
 	return c.flagSet
 }
+
+// parseWatchNamespaces parses the comma-separated list of namespaces provided
+// with the --watch-namespaces flag, skipping empty and duplicated entries.
+func parseWatchNamespaces(value string) []string {
+	var namespaces []string
+	for _, ns := range strings.Split(value, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || lo.Contains(namespaces, ns) {
+			continue
+		}
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces
+}
+
+// applyConfigurationFile sets the flags corresponding to the settings from
+// the provided configuration file, unless they were explicitly set through
+// command line arguments or environment variables.
+func (c *CLI) applyConfigurationFile(path string) error {
+	fileCfg, err := mgrconfig.LoadOperatorConfiguration(path)
+	if err != nil {
+		return err
+	}
+
+	explicitlySet := maps.Clone(c.envBoundFlags)
+	c.flagSet.Visit(func(f *flag.Flag) {
+		explicitlySet[f.Name] = struct{}{}
+	})
+	c.cfg.ConfigFileOverriddenFlags = slices.Sorted(maps.Keys(explicitlySet))
+	for name, value := range fileCfg.FlagValues() {
+		if _, ok := explicitlySet[name]; ok {
+			continue
+		}
+		if err := c.flagSet.Set(name, value); err != nil {
+			return fmt.Errorf("invalid value %q of flag %s in configuration file %s: %w", value, name, path, err)
+		}
+	}
+
+	// The log level has to be backed by an atomic level to be reloadable.
+	if c.loggerOpts.Level == nil {
+		level := zapcore.InfoLevel
+		if c.loggerOpts.Development {
+			level = zapcore.DebugLevel
+		}
+		c.loggerOpts.Level = uberzap.NewAtomicLevelAt(level)
+	}
+	return nil
+}
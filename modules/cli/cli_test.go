@@ -1,11 +1,16 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/stretchr/testify/require"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/kong/gateway-operator/modules/manager"
@@ -111,6 +116,43 @@ func TestParse(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			name: "watch namespaces argument is set",
+			args: []string{
+				"--watch-namespaces=ns1, ns2,,ns1",
+			},
+			expectedCfg: func() manager.Config {
+				cfg := expectedDefaultCfg()
+				cfg.WatchNamespaces = []string{"ns1", "ns2"}
+				return cfg
+			},
+		},
+		{
+			name: "tracing arguments are set",
+			args: []string{
+				"--tracing-otlp-endpoint=otel-collector:4317",
+				"--tracing-otlp-insecure",
+				"--tracing-sampling-ratio=0.25",
+			},
+			expectedCfg: func() manager.Config {
+				cfg := expectedDefaultCfg()
+				cfg.TracingOTLPEndpoint = "otel-collector:4317"
+				cfg.TracingOTLPInsecure = true
+				cfg.TracingSamplingRatio = 0.25
+				return cfg
+			},
+		},
+		{
+			name: "shard count argument is set",
+			args: []string{
+				"--shard-count=4",
+			},
+			expectedCfg: func() manager.Config {
+				cfg := expectedDefaultCfg()
+				cfg.ShardCount = 4
+				return cfg
+			},
+		},
 	}
 
 	for _, tC := range testCases {
@@ -162,6 +204,44 @@ func TestParseWithAdditionalFlags(t *testing.T) {
 	require.Equal(t, additionalConfig{OptionBool: true, OptionString: "passed", OptionalInt: 1}, additionalCfg)
 }
 
+func TestParseWithConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+leaderElection: false
+metricsBindAddress: ":9090"
+controllers:
+  konnect: true
+konnect:
+  syncPeriod: 30s
+logLevel: debug
+`), 0o600))
+
+	t.Setenv("GATEWAY_OPERATOR_ENABLE_CONTROLLER_KONNECT", "false")
+	cli := New(metadata.Metadata())
+	cfg := cli.Parse([]string{"--config-file=" + configFile, "--metrics-bind-address=:9091"})
+
+	expectedCfg := expectedDefaultCfg()
+	expectedCfg.ConfigFilePath = configFile
+	expectedCfg.ConfigFileOverriddenFlags = []string{"config-file", "enable-controller-konnect", "metrics-bind-address"}
+	// Set by the configuration file.
+	expectedCfg.LeaderElection = false
+	expectedCfg.KonnectSyncPeriod = 30 * time.Second
+	// Command line arguments and environment variables take precedence over the configuration file.
+	expectedCfg.MetricsAddr = ":9091"
+	expectedCfg.KonnectControllersEnabled = false
+
+	require.Empty(t, cmp.Diff(
+		expectedCfg, cfg,
+		// Those fields contain functions that are not comparable in Go.
+		cmpopts.IgnoreFields(manager.Config{}, "LoggerOpts.EncoderConfigOptions", "LoggerOpts.TimeEncoder", "LoggerOpts.Level")),
+	)
+	level, ok := cfg.LoggerOpts.Level.(uberzap.AtomicLevel)
+	require.True(t, ok, "log level has to be atomic to be reloadable")
+	require.Equal(t, zapcore.DebugLevel, level.Level())
+}
+
 func expectedDefaultCfg() manager.Config {
 	return manager.Config{
 		MetricsAddr:                             ":8080",
@@ -186,6 +266,9 @@ func expectedDefaultCfg() manager.Config {
 		DataPlaneBlueGreenControllerEnabled:     true,
 		KonnectControllersEnabled:               false,
 		KonnectSyncPeriod:                       consts.DefaultKonnectSyncPeriod,
+		TracingSamplingRatio:                    1,
+		ConversionWebhookPort:                   manager.DefaultConversionWebhookPort,
+		ConversionWebhookServiceName:            manager.DefaultConversionWebhookServiceName,
 		KongPluginInstallationControllerEnabled: false,
 		LoggerOpts:                              &zap.Options{},
 		KonnectMaxConcurrentReconciles:          consts.DefaultKonnectMaxConcurrentReconciles,
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/kong/gateway-operator/modules/plan"
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+// PlanCommand is the name of the subcommand which previews how a proposed
+// DataPlane, ControlPlane or GatewayConfiguration would change the resources
+// the operator manages instead of running the operator.
+const PlanCommand = "plan"
+
+// ParsePlan parses the arguments of the plan subcommand, which should not
+// include the subcommand name. It returns the configuration of the plan.
+func ParsePlan(arguments []string) (plan.Config, error) {
+	flagSet := flag.NewFlagSet(PlanCommand, flag.ContinueOnError)
+
+	var (
+		cfg             plan.Config
+		watchNamespaces string
+	)
+	flagSet.StringVar(&cfg.KubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file. If not set, the default config discovery is used.")
+	flagSet.StringVar(&cfg.FilePath, "file", "", "Path to the manifest of the proposed DataPlane, ControlPlane or GatewayConfiguration.")
+	flagSet.StringVar(&cfg.DefaultDataPlaneImage, "default-dataplane-image", consts.DefaultDataPlaneImage, "Image used for DataPlanes which do not specify one. Should match the operator's default.")
+	flagSet.BoolVar(&cfg.DevelopmentMode, "development-mode", false, "Plan as the operator running in development mode does, e.g. without image version validation.")
+	flagSet.BoolVar(&cfg.EnforceConfig, "enforce-config", true, "Plan as the operator running with --enforce-config does. If set to false, Deployments are only compared when their owner's spec changes.")
+	flagSet.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma-separated list of namespaces the operator watches. Should match the operator's --watch-namespaces.")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return plan.Config{}, err
+	}
+	if cfg.FilePath == "" {
+		return plan.Config{}, errors.New("--file has to be set")
+	}
+	cfg.WatchNamespaces = parseWatchNamespaces(watchNamespaces)
+
+	return cfg, nil
+}
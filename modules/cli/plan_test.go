@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/gateway-operator/modules/plan"
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+func TestParsePlan(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedCfg   plan.Config
+		expectedError bool
+	}{
+		{
+			name: "file with defaults",
+			args: []string{"--file", "dataplane.yaml"},
+			expectedCfg: plan.Config{
+				FilePath:              "dataplane.yaml",
+				DefaultDataPlaneImage: consts.DefaultDataPlaneImage,
+				EnforceConfig:         true,
+			},
+		},
+		{
+			name: "file with custom options",
+			args: []string{
+				"--file", "gatewayconfiguration.yaml",
+				"--kubeconfig", "/tmp/kubeconfig",
+				"--default-dataplane-image", "kong:3.9",
+				"--development-mode",
+				"--enforce-config=false",
+				"--watch-namespaces", "ns1,ns2",
+			},
+			expectedCfg: plan.Config{
+				KubeconfigPath:        "/tmp/kubeconfig",
+				FilePath:              "gatewayconfiguration.yaml",
+				DefaultDataPlaneImage: "kong:3.9",
+				DevelopmentMode:       true,
+				WatchNamespaces:       []string{"ns1", "ns2"},
+			},
+		},
+		{
+			name:          "no file",
+			args:          []string{},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := ParsePlan(tc.args)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCfg, cfg)
+		})
+	}
+}
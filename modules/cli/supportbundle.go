@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/gateway-operator/modules/supportbundle"
+)
+
+// SupportBundleCommand is the name of the subcommand which collects a support
+// bundle for a Gateway or a DataPlane instead of running the operator.
+const SupportBundleCommand = "support-bundle"
+
+// ParseSupportBundle parses the arguments of the support-bundle subcommand,
+// which should not include the subcommand name. It returns the configuration
+// of the support bundle collection.
+func ParseSupportBundle(arguments []string) (supportbundle.Config, error) {
+	flagSet := flag.NewFlagSet(SupportBundleCommand, flag.ContinueOnError)
+
+	var (
+		cfg       supportbundle.Config
+		gateway   string
+		dataplane string
+	)
+	flagSet.StringVar(&cfg.KubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file. If not set, the default config discovery is used.")
+	flagSet.StringVar(&gateway, "gateway", "", "The Gateway (namespace/name) to collect the support bundle for. Mutually exclusive with --dataplane.")
+	flagSet.StringVar(&dataplane, "dataplane", "", "The DataPlane (namespace/name) to collect the support bundle for. Mutually exclusive with --gateway.")
+	flagSet.StringVar(&cfg.OutputPath, "output", "support-bundle.tar.gz", "Path of the written support bundle tarball.")
+	flagSet.StringVar(&cfg.OperatorNamespace, "operator-namespace", "kong-system", "Namespace of the operator's Pods whose logs are collected. Logs are not collected when empty.")
+	flagSet.StringVar(&cfg.OperatorPodSelector, "operator-pod-selector", "control-plane=controller-manager", "Label selector of the operator's Pods whose logs are collected.")
+	flagSet.Int64Var(&cfg.LogTailLines, "log-tail-lines", 5000, "Number of the most recent log lines collected from each operator container. All lines are collected when 0.")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return supportbundle.Config{}, err
+	}
+
+	switch {
+	case gateway != "" && dataplane != "":
+		return supportbundle.Config{}, errors.New("only one of --gateway and --dataplane can be set")
+	case gateway != "":
+		cfg.Kind = supportbundle.KindGateway
+		cfg.Object = parseNamespacedName(gateway)
+	case dataplane != "":
+		cfg.Kind = supportbundle.KindDataPlane
+		cfg.Object = parseNamespacedName(dataplane)
+	default:
+		return supportbundle.Config{}, errors.New("one of --gateway and --dataplane has to be set")
+	}
+	if cfg.LogTailLines < 0 {
+		return supportbundle.Config{}, fmt.Errorf("--log-tail-lines has to be non-negative, got %d", cfg.LogTailLines)
+	}
+
+	return cfg, nil
+}
+
+// parseNamespacedName parses the provided namespace/name string. The namespace
+// defaults to "default" when not provided.
+func parseNamespacedName(value string) k8stypes.NamespacedName {
+	namespace, name, found := strings.Cut(value, "/")
+	if !found {
+		return k8stypes.NamespacedName{Namespace: "default", Name: value}
+	}
+	return k8stypes.NamespacedName{Namespace: namespace, Name: name}
+}
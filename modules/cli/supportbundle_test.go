@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/gateway-operator/modules/supportbundle"
+)
+
+func TestParseSupportBundle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedCfg   supportbundle.Config
+		expectedError bool
+	}{
+		{
+			name: "gateway with defaults",
+			args: []string{"--gateway", "ns/gw"},
+			expectedCfg: supportbundle.Config{
+				Kind:                supportbundle.KindGateway,
+				Object:              k8stypes.NamespacedName{Namespace: "ns", Name: "gw"},
+				OutputPath:          "support-bundle.tar.gz",
+				OperatorNamespace:   "kong-system",
+				OperatorPodSelector: "control-plane=controller-manager",
+				LogTailLines:        5000,
+			},
+		},
+		{
+			name: "dataplane in the default namespace with custom options",
+			args: []string{
+				"--dataplane", "dp",
+				"--output", "/tmp/bundle.tar.gz",
+				"--kubeconfig", "/tmp/kubeconfig",
+				"--operator-namespace", "operator",
+				"--operator-pod-selector", "app=operator",
+				"--log-tail-lines", "0",
+			},
+			expectedCfg: supportbundle.Config{
+				KubeconfigPath:      "/tmp/kubeconfig",
+				Kind:                supportbundle.KindDataPlane,
+				Object:              k8stypes.NamespacedName{Namespace: "default", Name: "dp"},
+				OutputPath:          "/tmp/bundle.tar.gz",
+				OperatorNamespace:   "operator",
+				OperatorPodSelector: "app=operator",
+			},
+		},
+		{
+			name:          "neither gateway nor dataplane",
+			args:          []string{},
+			expectedError: true,
+		},
+		{
+			name:          "both gateway and dataplane",
+			args:          []string{"--gateway", "ns/gw", "--dataplane", "ns/dp"},
+			expectedError: true,
+		},
+		{
+			name:          "negative log tail lines",
+			args:          []string{"--gateway", "ns/gw", "--log-tail-lines", "-1"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := ParseSupportBundle(tc.args)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCfg, cfg)
+		})
+	}
+}
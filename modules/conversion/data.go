@@ -0,0 +1,63 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+)
+
+// pushHubData stores the provided hub object's fields, except its type and
+// metadata, in the consts.ConversionDataAnnotation annotation of obj.
+func pushHubData(obj, hub *unstructured.Unstructured) error {
+	data := make(map[string]any, len(hub.Object))
+	for k, v := range hub.Object {
+		switch k {
+		case "apiVersion", "kind", "metadata":
+			continue
+		}
+		data[k] = v
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversion data: %w", err)
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[consts.ConversionDataAnnotation] = string(b)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// popHubData removes the consts.ConversionDataAnnotation annotation from obj
+// and returns the hub object's fields stored in it. It returns nil if obj
+// doesn't have the annotation.
+func popHubData(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	annotations := obj.GetAnnotations()
+	data, ok := annotations[consts.ConversionDataAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	delete(annotations, consts.ConversionDataAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+
+	// Whole numbers are decoded as int64, the same way they are in the
+	// converted objects, so that the stored fields can be compared with them.
+	stored := &unstructured.Unstructured{}
+	if err := utiljson.Unmarshal([]byte(data), &stored.Object); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s annotation: %w", consts.ConversionDataAnnotation, err)
+	}
+	return stored, nil
+}
@@ -0,0 +1,37 @@
+package conversion
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
+	configurationv1beta1 "github.com/kong/kubernetes-configuration/api/configuration/v1beta1"
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// NewDefaultRegistry returns a Registry with the conversions of the operator's
+// APIs. DataPlanes, ControlPlanes and GatewayConfigurations are converted
+// between v1alpha1 and their hub version v1beta1. The other kinds are served at
+// a single version and are registered with that version as their hub. When a
+// kind gets a new version, the new version becomes its hub and the conversions
+// of its older versions have to be registered here with Register before the
+// hubs are.
+func NewDefaultRegistry(scheme *runtime.Scheme) (*Registry, error) {
+	r := NewRegistry()
+	if err := registerOperatorV1alpha1(r); err != nil {
+		return nil, err
+	}
+	if err := r.RegisterHubs(scheme,
+		operatorv1alpha1.SchemeGroupVersion,
+		operatorv1beta1.SchemeGroupVersion,
+		konnectv1alpha1.SchemeGroupVersion,
+		configurationv1.SchemeGroupVersion,
+		configurationv1alpha1.SchemeGroupVersion,
+		configurationv1beta1.SchemeGroupVersion,
+	); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,103 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Path is the path the conversion webhook is served at.
+const Path = "/convert"
+
+// Handler handles the ConversionReview requests sent by the API server for
+// custom resources using the Webhook conversion strategy.
+type Handler struct {
+	Logger   logr.Logger
+	Registry *Registry
+}
+
+// NewHandler creates a Handler converting objects with the provided Registry.
+func NewHandler(registry *Registry, l logr.Logger) *Handler {
+	return &Handler{
+		Logger:   l.WithValues("component", "conversion-webhook"),
+		Registry: registry,
+	}
+}
+
+// ServeHTTP serves for HTTP requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.Logger.Error(err, "failed to read request from client")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(data, review); err != nil {
+		h.Logger.Error(err, "failed to parse ConversionReview object")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "ConversionReview doesn't contain a request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = h.convert(review.Request)
+	review.Request = nil
+	data, err = json.Marshal(review)
+	if err != nil {
+		h.Logger.Error(err, "failed to marshal response")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		h.Logger.Error(err, "failed to write response")
+	}
+}
+
+func (h *Handler) convert(req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	resp := &apiextensionsv1.ConversionResponse{
+		UID:              req.UID,
+		ConvertedObjects: make([]runtime.RawExtension, 0, len(req.Objects)),
+	}
+	for _, raw := range req.Objects {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(raw.Raw); err != nil {
+			return failedResponse(req, fmt.Errorf("failed to unmarshal object: %w", err))
+		}
+		if err := h.Registry.Convert(obj, req.DesiredAPIVersion); err != nil {
+			h.Logger.Error(err, "failed to convert object",
+				"kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName(),
+				"desiredAPIVersion", req.DesiredAPIVersion,
+			)
+			return failedResponse(req, err)
+		}
+		b, err := obj.MarshalJSON()
+		if err != nil {
+			return failedResponse(req, fmt.Errorf("failed to marshal converted object: %w", err))
+		}
+		resp.ConvertedObjects = append(resp.ConvertedObjects, runtime.RawExtension{Raw: b})
+	}
+	resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return resp
+}
+
+func failedResponse(req *apiextensionsv1.ConversionRequest, err error) *apiextensionsv1.ConversionResponse {
+	return &apiextensionsv1.ConversionResponse{
+		UID: req.UID,
+		Result: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestHandler(t *testing.T) {
+	handler := NewHandler(testRegistry(t), logr.Discard())
+
+	testCases := []struct {
+		name              string
+		objects           []*unstructured.Unstructured
+		desiredAPIVersion string
+		expectedStatus    string
+		expectedObjects   []*unstructured.Unstructured
+	}{
+		{
+			name: "objects are converted",
+			objects: []*unstructured.Unstructured{
+				widget("v1", map[string]any{"size": int64(1)}),
+				widget("v2", map[string]any{"replicas": int64(2)}),
+			},
+			desiredAPIVersion: "example.konghq.com/v2",
+			expectedStatus:    metav1.StatusSuccess,
+			expectedObjects: []*unstructured.Unstructured{
+				widget("v2", map[string]any{"replicas": int64(1)}),
+				widget("v2", map[string]any{"replicas": int64(2)}),
+			},
+		},
+		{
+			name: "unregistered version",
+			objects: []*unstructured.Unstructured{
+				widget("v1", map[string]any{"size": int64(1)}),
+			},
+			desiredAPIVersion: "example.konghq.com/v3",
+			expectedStatus:    metav1.StatusFailure,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			review := apiextensionsv1.ConversionReview{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+					Kind:       "ConversionReview",
+				},
+				Request: &apiextensionsv1.ConversionRequest{
+					UID:               types.UID("uid"),
+					DesiredAPIVersion: tc.desiredAPIVersion,
+				},
+			}
+			for _, obj := range tc.objects {
+				b, err := obj.MarshalJSON()
+				require.NoError(t, err)
+				review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: b})
+			}
+			body, err := json.Marshal(review)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body)))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var resp apiextensionsv1.ConversionReview
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			require.Nil(t, resp.Request)
+			require.NotNil(t, resp.Response)
+			require.Equal(t, types.UID("uid"), resp.Response.UID)
+			require.Equal(t, tc.expectedStatus, resp.Response.Result.Status)
+			require.Len(t, resp.Response.ConvertedObjects, len(tc.expectedObjects))
+			for i, expected := range tc.expectedObjects {
+				obj := &unstructured.Unstructured{}
+				require.NoError(t, obj.UnmarshalJSON(resp.Response.ConvertedObjects[i].Raw))
+				require.Equal(t, expected, obj)
+			}
+		})
+	}
+}
+
+func TestHandlerInvalidRequest(t *testing.T) {
+	handler := NewHandler(testRegistry(t), logr.Discard())
+
+	for name, body := range map[string]string{
+		"invalid JSON":        "{",
+		"missing the request": `{"apiVersion":"apiextensions.k8s.io/v1","kind":"ConversionReview"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, bytes.NewBufferString(body)))
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package conversion
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// operatorV1alpha1 is the version DataPlanes, ControlPlanes and
+// GatewayConfigurations were served at before v1beta1.
+const operatorV1alpha1 = "v1alpha1"
+
+// registerOperatorV1alpha1 registers the conversions of DataPlanes,
+// ControlPlanes and GatewayConfigurations between v1alpha1 and their hub
+// version v1beta1.
+//
+// v1alpha1 configured the Deployments' Pods with spec.deployment.pods, which
+// v1beta1 replaced with spec.deployment.podTemplateSpec:
+//
+//	pods:                        podTemplateSpec:
+//	  labels                       metadata.labels
+//	  affinity                     spec.affinity
+//	  volumes                      spec.volumes
+//	  containerImage, version      spec.containers[<name>].image
+//	  env                          spec.containers[<name>].env
+//	  envFrom                      spec.containers[<name>].envFrom
+//	  resources                    spec.containers[<name>].resources
+//	  volumeMounts                 spec.containers[<name>].volumeMounts
+//
+// where <name> is the name of the proxy or controller container. The rest of
+// the spec is identical.
+func registerOperatorV1alpha1(r *Registry) error {
+	gv := operatorv1beta1.SchemeGroupVersion
+	conversions := []struct {
+		kind  string
+		paths []deploymentPath
+	}{
+		{
+			kind:  "DataPlane",
+			paths: []deploymentPath{{fields: []string{"spec", "deployment"}, container: consts.DataPlaneProxyContainerName}},
+		},
+		{
+			kind:  "ControlPlane",
+			paths: []deploymentPath{{fields: []string{"spec", "deployment"}, container: consts.ControlPlaneControllerContainerName}},
+		},
+		{
+			kind: "GatewayConfiguration",
+			paths: []deploymentPath{
+				{fields: []string{"spec", "dataPlaneOptions", "deployment"}, container: consts.DataPlaneProxyContainerName},
+				{fields: []string{"spec", "controlPlaneOptions", "deployment"}, container: consts.ControlPlaneControllerContainerName},
+			},
+		},
+	}
+	for _, c := range conversions {
+		if err := r.Register(gv.WithKind(c.kind).GroupKind(), gv.Version, Spoke{
+			Version: operatorV1alpha1,
+			ToHub: func(obj *unstructured.Unstructured) error {
+				for _, p := range c.paths {
+					if err := podsToPodTemplateSpec(obj, p); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			FromHub: func(obj *unstructured.Unstructured) error {
+				for _, p := range c.paths {
+					if err := podTemplateSpecToPods(obj, p); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deploymentPath points to the deployment options in an object.
+type deploymentPath struct {
+	// fields is the path of the deployment options.
+	fields []string
+	// container is the name of the container configured with the pods options.
+	container string
+}
+
+// podContainerFields are the v1alpha1 pods options which map to the fields of
+// the same name of the v1beta1 container.
+var podContainerFields = []string{"env", "envFrom", "resources", "volumeMounts"}
+
+// podsToPodTemplateSpec converts v1alpha1 pods options to the v1beta1 Pod template.
+func podsToPodTemplateSpec(obj *unstructured.Unstructured, p deploymentPath) error {
+	podsPath := append(p.fields[:len(p.fields):len(p.fields)], "pods")
+	pods, found, err := unstructured.NestedMap(obj.Object, podsPath...)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", strings.Join(podsPath, "."), err)
+	}
+	unstructured.RemoveNestedField(obj.Object, podsPath...)
+	if !found || len(pods) == 0 {
+		return nil
+	}
+
+	template := map[string]any{}
+	if labels, ok := pods["labels"]; ok {
+		template["metadata"] = map[string]any{"labels": labels}
+	}
+	spec := map[string]any{}
+	for _, f := range []string{"affinity", "volumes"} {
+		if v, ok := pods[f]; ok {
+			spec[f] = v
+		}
+	}
+	container := map[string]any{"name": p.container}
+	for _, f := range podContainerFields {
+		if v, ok := pods[f]; ok {
+			container[f] = v
+		}
+	}
+	if image, ok := pods["containerImage"].(string); ok && image != "" {
+		if version, ok := pods["version"].(string); ok && version != "" {
+			image += ":" + version
+		}
+		container["image"] = image
+	}
+	if len(container) > 1 {
+		spec["containers"] = []any{container}
+	}
+	if len(spec) > 0 {
+		template["spec"] = spec
+	}
+	if len(template) == 0 {
+		return nil
+	}
+	return unstructured.SetNestedMap(obj.Object, template, append(p.fields[:len(p.fields):len(p.fields)], "podTemplateSpec")...)
+}
+
+// podTemplateSpecToPods converts the v1beta1 Pod template to v1alpha1 pods options.
+// The parts of the template v1alpha1 can't represent, e.g. other containers,
+// are dropped and restored from the conversion data when converting back.
+func podTemplateSpecToPods(obj *unstructured.Unstructured, p deploymentPath) error {
+	templatePath := append(p.fields[:len(p.fields):len(p.fields)], "podTemplateSpec")
+	template, found, err := unstructured.NestedMap(obj.Object, templatePath...)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", strings.Join(templatePath, "."), err)
+	}
+	unstructured.RemoveNestedField(obj.Object, templatePath...)
+	if !found {
+		return nil
+	}
+
+	pods := map[string]any{}
+	if labels, found, _ := unstructured.NestedFieldNoCopy(template, "metadata", "labels"); found {
+		pods["labels"] = runtime.DeepCopyJSONValue(labels)
+	}
+	for _, f := range []string{"affinity", "volumes"} {
+		if v, found, _ := unstructured.NestedFieldNoCopy(template, "spec", f); found {
+			pods[f] = runtime.DeepCopyJSONValue(v)
+		}
+	}
+	containers, _, _ := unstructured.NestedSlice(template, "spec", "containers")
+	for _, c := range containers {
+		container, ok := c.(map[string]any)
+		if !ok || container["name"] != p.container {
+			continue
+		}
+		for _, f := range podContainerFields {
+			if v, ok := container[f]; ok {
+				pods[f] = v
+			}
+		}
+		if image, ok := container["image"].(string); ok && image != "" {
+			pods["containerImage"], pods["version"] = splitImage(image)
+			if pods["version"] == "" {
+				delete(pods, "version")
+			}
+		}
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+	return unstructured.SetNestedMap(obj.Object, pods, append(p.fields[:len(p.fields):len(p.fields)], "pods")...)
+}
+
+// splitImage splits the provided image into its repository and tag. Images
+// referenced by digest are returned as they are.
+func splitImage(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	i := strings.LastIndex(image, ":")
+	if i == -1 || strings.Contains(image[i:], "/") {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
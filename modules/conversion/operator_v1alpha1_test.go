@@ -0,0 +1,201 @@
+package conversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func operatorObject(version, kind string, spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": operatorv1beta1.SchemeGroupVersion.Group + "/" + version,
+		"kind":       kind,
+		"metadata": map[string]any{
+			"namespace": "ns",
+			"name":      "obj",
+		},
+		"spec": spec,
+	}}
+}
+
+func TestOperatorV1alpha1Conversion(t *testing.T) {
+	pods := map[string]any{
+		"labels":         map[string]any{"app": "kong"},
+		"containerImage": "kong/kong-gateway",
+		"version":        "3.9",
+		"env": []any{
+			map[string]any{"name": "KONG_LOG_LEVEL", "value": "debug"},
+		},
+		"resources": map[string]any{
+			"limits": map[string]any{"cpu": "1"},
+		},
+	}
+	podTemplateSpec := func(container string) map[string]any {
+		return map[string]any{
+			"metadata": map[string]any{"labels": map[string]any{"app": "kong"}},
+			"spec": map[string]any{
+				"containers": []any{
+					map[string]any{
+						"name":  container,
+						"image": "kong/kong-gateway:3.9",
+						"env": []any{
+							map[string]any{"name": "KONG_LOG_LEVEL", "value": "debug"},
+						},
+						"resources": map[string]any{
+							"limits": map[string]any{"cpu": "1"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		kind    string
+		spoke   map[string]any
+		hub     map[string]any
+		typedFn func() runtime.Object
+	}{
+		{
+			name: "DataPlane",
+			kind: "DataPlane",
+			spoke: map[string]any{
+				"deployment": map[string]any{"replicas": int64(2), "pods": pods},
+			},
+			hub: map[string]any{
+				"deployment": map[string]any{"replicas": int64(2), "podTemplateSpec": podTemplateSpec(consts.DataPlaneProxyContainerName)},
+			},
+			typedFn: func() runtime.Object { return &operatorv1beta1.DataPlane{} },
+		},
+		{
+			name: "ControlPlane",
+			kind: "ControlPlane",
+			spoke: map[string]any{
+				"deployment": map[string]any{"pods": pods},
+			},
+			hub: map[string]any{
+				"deployment": map[string]any{"podTemplateSpec": podTemplateSpec(consts.ControlPlaneControllerContainerName)},
+			},
+			typedFn: func() runtime.Object { return &operatorv1beta1.ControlPlane{} },
+		},
+		{
+			name: "GatewayConfiguration",
+			kind: "GatewayConfiguration",
+			spoke: map[string]any{
+				"dataPlaneOptions":    map[string]any{"deployment": map[string]any{"pods": pods}},
+				"controlPlaneOptions": map[string]any{"deployment": map[string]any{"pods": pods}},
+			},
+			hub: map[string]any{
+				"dataPlaneOptions":    map[string]any{"deployment": map[string]any{"podTemplateSpec": podTemplateSpec(consts.DataPlaneProxyContainerName)}},
+				"controlPlaneOptions": map[string]any{"deployment": map[string]any{"podTemplateSpec": podTemplateSpec(consts.ControlPlaneControllerContainerName)}},
+			},
+			typedFn: func() runtime.Object { return &operatorv1beta1.GatewayConfiguration{} },
+		},
+		{
+			name:    "DataPlane without pods options",
+			kind:    "DataPlane",
+			spoke:   map[string]any{"deployment": map[string]any{"replicas": int64(1)}},
+			hub:     map[string]any{"deployment": map[string]any{"replicas": int64(1)}},
+			typedFn: func() runtime.Object { return &operatorv1beta1.DataPlane{} },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewDefaultRegistry(scheme.Get())
+			require.NoError(t, err)
+			spoke := operatorObject("v1alpha1", tc.kind, tc.spoke)
+
+			obj := spoke.DeepCopy()
+			require.NoError(t, r.Convert(obj, operatorv1beta1.SchemeGroupVersion.String()))
+			require.Equal(t, operatorObject("v1beta1", tc.kind, tc.hub), obj)
+			require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(obj.Object, tc.typedFn(), true),
+				"converted object has to be a valid v1beta1 object",
+			)
+
+			t.Log("v1alpha1 -> v1beta1 -> v1alpha1 round trip is lossless")
+			require.NoError(t, r.Convert(obj, operatorv1beta1.SchemeGroupVersion.Group+"/v1alpha1"))
+			unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+			require.Equal(t, spoke, obj)
+		})
+	}
+}
+
+func TestOperatorV1alpha1ConversionFromHubIsLossless(t *testing.T) {
+	r, err := NewDefaultRegistry(scheme.Get())
+	require.NoError(t, err)
+
+	hub := operatorObject("v1beta1", "DataPlane", map[string]any{
+		"deployment": map[string]any{
+			"podTemplateSpec": map[string]any{
+				"metadata": map[string]any{"annotations": map[string]any{"a": "b"}},
+				"spec": map[string]any{
+					"containers": []any{
+						map[string]any{
+							"name":  consts.DataPlaneProxyContainerName,
+							"image": "kong/kong-gateway:3.9",
+						},
+						map[string]any{
+							"name":  "sidecar",
+							"image": "busybox",
+						},
+					},
+					"nodeSelector": map[string]any{"zone": "a"},
+				},
+			},
+		},
+	})
+
+	obj := hub.DeepCopy()
+	require.NoError(t, r.Convert(obj, operatorv1beta1.SchemeGroupVersion.Group+"/v1alpha1"))
+	deployment, _, _ := unstructured.NestedMap(obj.Object, "spec", "deployment")
+	require.Equal(t, map[string]any{
+		"pods": map[string]any{
+			"containerImage": "kong/kong-gateway",
+			"version":        "3.9",
+		},
+	}, deployment)
+	require.Contains(t, obj.GetAnnotations(), consts.ConversionDataAnnotation)
+
+	t.Log("v1beta1 -> v1alpha1 -> v1beta1 round trip restores what v1alpha1 can't represent")
+	require.NoError(t, r.Convert(obj, operatorv1beta1.SchemeGroupVersion.String()))
+	require.Equal(t, hub, obj)
+
+	t.Log("changes made through v1alpha1 are kept")
+	require.NoError(t, r.Convert(obj, operatorv1beta1.SchemeGroupVersion.Group+"/v1alpha1"))
+	require.NoError(t, unstructured.SetNestedField(obj.Object, "3.10", "spec", "deployment", "pods", "version"))
+	require.NoError(t, r.Convert(obj, operatorv1beta1.SchemeGroupVersion.String()))
+	var dp operatorv1beta1.DataPlane
+	require.NoError(t, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &dp))
+	require.Equal(t, []corev1.Container{
+		{Name: consts.DataPlaneProxyContainerName, Image: "kong/kong-gateway:3.10"},
+		{Name: "sidecar", Image: "busybox"},
+	}, dp.Spec.Deployment.PodTemplateSpec.Spec.Containers)
+	require.Equal(t, map[string]string{"zone": "a"}, dp.Spec.Deployment.PodTemplateSpec.Spec.NodeSelector)
+}
+
+func TestSplitImage(t *testing.T) {
+	testCases := []struct {
+		image, repository, tag string
+	}{
+		{image: "kong:3.9", repository: "kong", tag: "3.9"},
+		{image: "kong", repository: "kong"},
+		{image: "localhost:5000/kong", repository: "localhost:5000/kong"},
+		{image: "localhost:5000/kong:3.9", repository: "localhost:5000/kong", tag: "3.9"},
+		{image: "kong@sha256:abc", repository: "kong@sha256:abc"},
+	}
+	for _, tc := range testCases {
+		repository, tag := splitImage(tc.image)
+		require.Equal(t, tc.repository, repository, tc.image)
+		require.Equal(t, tc.tag, tag, tc.image)
+	}
+}
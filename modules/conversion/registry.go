@@ -0,0 +1,308 @@
+package conversion
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Spoke describes the conversion of one of the kind's versions to and from
+// the kind's hub version. Both functions convert the provided object in place.
+// When they're called, the object's apiVersion is already set to the target
+// version. Nil functions mean that the versions' schemas are identical.
+type Spoke struct {
+	// Version is the spoke version.
+	Version string
+	// ToHub converts the object from the spoke version to the hub version.
+	ToHub func(obj *unstructured.Unstructured) error
+	// FromHub converts the object from the hub version to the spoke version.
+	FromHub func(obj *unstructured.Unstructured) error
+}
+
+type kindConversion struct {
+	hubVersion string
+	spokes     map[string]Spoke
+}
+
+// Registry holds the conversions of the kinds served at multiple versions.
+// Every kind has a hub version, which all the other (spoke) versions are
+// converted to and from.
+type Registry struct {
+	kinds map[schema.GroupKind]kindConversion
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		kinds: make(map[schema.GroupKind]kindConversion),
+	}
+}
+
+// Register registers the conversions of the provided kind between its hub
+// version and the provided spoke versions.
+func (r *Registry) Register(gk schema.GroupKind, hubVersion string, spokes ...Spoke) error {
+	if _, ok := r.kinds[gk]; ok {
+		return fmt.Errorf("conversion of %s is already registered", gk)
+	}
+	kc := kindConversion{
+		hubVersion: hubVersion,
+		spokes:     make(map[string]Spoke, len(spokes)),
+	}
+	for _, spoke := range spokes {
+		if spoke.Version == hubVersion {
+			return fmt.Errorf("spoke version %s of %s can't be the hub version", spoke.Version, gk)
+		}
+		if _, ok := kc.spokes[spoke.Version]; ok {
+			return fmt.Errorf("spoke version %s of %s is already registered", spoke.Version, gk)
+		}
+		kc.spokes[spoke.Version] = spoke
+	}
+	r.kinds[gk] = kc
+	return nil
+}
+
+// RegisterHubs registers all the kinds from the provided group versions which
+// are known to the scheme and haven't been registered yet. Each of them has to
+// be served at a single version, which becomes its hub version. Kinds served
+// at multiple versions have to be registered with their spokes before calling
+// this.
+func (r *Registry) RegisterHubs(scheme *runtime.Scheme, gvs ...schema.GroupVersion) error {
+	versions := make(map[schema.GroupKind][]string)
+	for _, gv := range gvs {
+		for kind, t := range scheme.KnownTypes(gv) {
+			if strings.HasSuffix(kind, "List") {
+				continue
+			}
+			// Skip the types registered in every group version, e.g. WatchEvent or ListOptions.
+			if _, ok := reflect.New(t).Interface().(metav1.Object); !ok {
+				continue
+			}
+			gk := gv.WithKind(kind).GroupKind()
+			versions[gk] = append(versions[gk], gv.Version)
+		}
+	}
+
+	for gk, vs := range versions {
+		if _, ok := r.kinds[gk]; ok {
+			continue
+		}
+		if len(vs) > 1 {
+			slices.Sort(vs)
+			return fmt.Errorf("%s is served at multiple versions %v, its conversions have to be registered explicitly", gk, vs)
+		}
+		if err := r.Register(gk, vs[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GroupKinds returns the registered kinds.
+func (r *Registry) GroupKinds() []schema.GroupKind {
+	gks := make([]schema.GroupKind, 0, len(r.kinds))
+	for gk := range r.kinds {
+		gks = append(gks, gk)
+	}
+	slices.SortFunc(gks, func(a, b schema.GroupKind) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return gks
+}
+
+// Versions returns the versions of the provided kind the registry can convert
+// between, with the hub version first. It returns nil for unregistered kinds.
+func (r *Registry) Versions(gk schema.GroupKind) []string {
+	kc, ok := r.kinds[gk]
+	if !ok {
+		return nil
+	}
+	versions := []string{kc.hubVersion}
+	for v := range kc.spokes {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions[1:])
+	return versions
+}
+
+// Convert converts the provided object to the provided apiVersion, going
+// through the kind's hub version. Conversions from the hub version to spoke
+// versions store the fields the spoke versions can't represent in the object's
+// annotation, so that they're restored when the object is converted back.
+func (r *Registry) Convert(obj *unstructured.Unstructured, toAPIVersion string) error {
+	from := obj.GroupVersionKind()
+	to, err := schema.ParseGroupVersion(toAPIVersion)
+	if err != nil {
+		return fmt.Errorf("invalid apiVersion %q: %w", toAPIVersion, err)
+	}
+	if from.Group != to.Group {
+		return fmt.Errorf("can't convert %s to a different group %s", from, to.Group)
+	}
+	kc, ok := r.kinds[from.GroupKind()]
+	if !ok {
+		return fmt.Errorf("conversion of %s is not registered", from.GroupKind())
+	}
+	if from.Version == to.Version {
+		return nil
+	}
+
+	if from.Version != kc.hubVersion {
+		spoke, ok := kc.spokes[from.Version]
+		if !ok {
+			return fmt.Errorf("version %s of %s is not registered", from.Version, from.GroupKind())
+		}
+		if err := spokeToHub(obj, spoke, kc.hubVersion); err != nil {
+			return fmt.Errorf("failed to convert %s to %s: %w", from, kc.hubVersion, err)
+		}
+	}
+
+	if to.Version != kc.hubVersion {
+		spoke, ok := kc.spokes[to.Version]
+		if !ok {
+			return fmt.Errorf("version %s of %s is not registered", to.Version, from.GroupKind())
+		}
+		if err := hubToSpoke(obj, spoke); err != nil {
+			return fmt.Errorf("failed to convert %s to %s: %w", from.GroupKind().WithVersion(kc.hubVersion), to.Version, err)
+		}
+	}
+	return nil
+}
+
+func spokeToHub(obj *unstructured.Unstructured, spoke Spoke, hubVersion string) error {
+	stored, err := popHubData(obj)
+	if err != nil {
+		return err
+	}
+
+	obj.SetAPIVersion(schema.GroupVersion{Group: obj.GroupVersionKind().Group, Version: hubVersion}.String())
+	if spoke.ToHub != nil {
+		if err := spoke.ToHub(obj); err != nil {
+			return err
+		}
+	}
+	if stored == nil {
+		return nil
+	}
+
+	// Only the fields which are lost in the hub -> spoke -> hub round trip are
+	// restored. The others could have been changed through the spoke version.
+	stored.SetAPIVersion(obj.GetAPIVersion())
+	stored.SetKind(obj.GetKind())
+	roundTripped := stored.DeepCopy()
+	if err := hubToSpokeWithoutData(roundTripped, spoke); err != nil {
+		return err
+	}
+	roundTripped.SetAPIVersion(obj.GetAPIVersion())
+	if spoke.ToHub != nil {
+		if err := spoke.ToHub(roundTripped); err != nil {
+			return err
+		}
+	}
+	restoreLostFields(obj.Object, stored.Object, roundTripped.Object)
+	return nil
+}
+
+func hubToSpoke(obj *unstructured.Unstructured, spoke Spoke) error {
+	hub := obj.DeepCopy()
+	if err := hubToSpokeWithoutData(obj, spoke); err != nil {
+		return err
+	}
+	return pushHubData(obj, hub)
+}
+
+func hubToSpokeWithoutData(obj *unstructured.Unstructured, spoke Spoke) error {
+	obj.SetAPIVersion(schema.GroupVersion{Group: obj.GroupVersionKind().Group, Version: spoke.Version}.String())
+	if spoke.FromHub != nil {
+		return spoke.FromHub(obj)
+	}
+	return nil
+}
+
+// restoreLostFields sets the fields from stored which are missing in
+// roundTripped on obj, unless obj already has them. Lists of objects with
+// names, e.g. containers, are merged by the items' names.
+func restoreLostFields(obj, stored, roundTripped map[string]any) {
+	for k, storedValue := range stored {
+		rtValue, inRoundTripped := roundTripped[k]
+		if !inRoundTripped {
+			if _, ok := obj[k]; !ok {
+				obj[k] = runtime.DeepCopyJSONValue(storedValue)
+			}
+			continue
+		}
+		if storedList, ok := storedValue.([]any); ok {
+			rtList, ok := rtValue.([]any)
+			if !ok {
+				continue
+			}
+			objList, ok := obj[k].([]any)
+			if !ok {
+				continue
+			}
+			obj[k] = restoreLostNamedItems(objList, storedList, rtList)
+			continue
+		}
+		storedMap, ok := storedValue.(map[string]any)
+		if !ok {
+			continue
+		}
+		rtMap, ok := rtValue.(map[string]any)
+		if !ok {
+			continue
+		}
+		objMap, ok := obj[k].(map[string]any)
+		if !ok {
+			continue
+		}
+		restoreLostFields(objMap, storedMap, rtMap)
+	}
+}
+
+// restoreLostNamedItems appends the items from stored which are missing in
+// roundTripped to obj, unless obj already has them, and restores the lost
+// fields of the items present in all of them. Items are matched by their
+// names; items without names are left as they are.
+func restoreLostNamedItems(obj, stored, roundTripped []any) []any {
+	objItems := namedItems(obj)
+	rtItems := namedItems(roundTripped)
+	for _, item := range stored {
+		storedItem, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, ok := storedItem["name"].(string)
+		if !ok {
+			continue
+		}
+		objItem, inObj := objItems[name]
+		rtItem, inRoundTripped := rtItems[name]
+		switch {
+		case !inRoundTripped && !inObj:
+			obj = append(obj, runtime.DeepCopyJSONValue(storedItem))
+		case inRoundTripped && inObj:
+			restoreLostFields(objItem, storedItem, rtItem)
+		}
+	}
+	return obj
+}
+
+// namedItems returns the items of the provided list which are objects with
+// a name, by their names.
+func namedItems(list []any) map[string]map[string]any {
+	items := make(map[string]map[string]any, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if name, ok := m["name"].(string); ok {
+			items[name] = m
+		}
+	}
+	return items
+}
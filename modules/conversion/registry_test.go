@@ -0,0 +1,199 @@
+package conversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+var testGK = schema.GroupKind{Group: "example.konghq.com", Kind: "Widget"}
+
+// testRegistry returns a Registry with the Widget kind, whose hub version v2
+// has spec.replicas and spec.image, while the spoke version v1 has spec.size
+// in place of spec.replicas and no spec.image.
+func testRegistry(t *testing.T) *Registry {
+	t.Helper()
+
+	r := NewRegistry()
+	require.NoError(t, r.Register(testGK, "v2", Spoke{
+		Version: "v1",
+		ToHub: func(obj *unstructured.Unstructured) error {
+			size, found, err := unstructured.NestedInt64(obj.Object, "spec", "size")
+			if err != nil || !found {
+				return err
+			}
+			unstructured.RemoveNestedField(obj.Object, "spec", "size")
+			return unstructured.SetNestedField(obj.Object, size, "spec", "replicas")
+		},
+		FromHub: func(obj *unstructured.Unstructured) error {
+			replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+			if err != nil {
+				return err
+			}
+			unstructured.RemoveNestedField(obj.Object, "spec", "replicas")
+			unstructured.RemoveNestedField(obj.Object, "spec", "image")
+			if !found {
+				return nil
+			}
+			return unstructured.SetNestedField(obj.Object, replicas, "spec", "size")
+		},
+	}))
+	return r
+}
+
+func widget(version string, spec map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": testGK.WithVersion(version).GroupVersion().String(),
+		"kind":       testGK.Kind,
+		"metadata": map[string]any{
+			"namespace": "ns",
+			"name":      "widget",
+		},
+		"spec": spec,
+	}}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	r := testRegistry(t)
+	hub := widget("v2", map[string]any{
+		"replicas": int64(3),
+		"image":    "kong:3.9",
+	})
+
+	obj := hub.DeepCopy()
+	require.NoError(t, r.Convert(obj, "example.konghq.com/v1"))
+	require.Equal(t, "example.konghq.com/v1", obj.GetAPIVersion())
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	require.Equal(t, map[string]any{"size": int64(3)}, spec)
+	require.Contains(t, obj.GetAnnotations(), consts.ConversionDataAnnotation)
+
+	t.Log("converting back to the hub version restores the fields the spoke can't represent")
+	require.NoError(t, r.Convert(obj, "example.konghq.com/v2"))
+	require.Equal(t, hub, obj)
+}
+
+func TestConvertRoundTripWithChangesInSpoke(t *testing.T) {
+	r := testRegistry(t)
+
+	obj := widget("v2", map[string]any{
+		"replicas": int64(3),
+		"image":    "kong:3.9",
+	})
+	require.NoError(t, r.Convert(obj, "example.konghq.com/v1"))
+
+	t.Log("fields changed through the spoke version are not overwritten by the stored ones")
+	require.NoError(t, unstructured.SetNestedField(obj.Object, int64(5), "spec", "size"))
+	require.NoError(t, r.Convert(obj, "example.konghq.com/v2"))
+	require.Equal(t, widget("v2", map[string]any{
+		"replicas": int64(5),
+		"image":    "kong:3.9",
+	}), obj)
+}
+
+func TestConvertFromSpokeWithoutStoredData(t *testing.T) {
+	r := testRegistry(t)
+
+	obj := widget("v1", map[string]any{"size": int64(2)})
+	require.NoError(t, r.Convert(obj, "example.konghq.com/v2"))
+	require.Equal(t, widget("v2", map[string]any{"replicas": int64(2)}), obj)
+}
+
+func TestConvertErrors(t *testing.T) {
+	r := testRegistry(t)
+
+	testCases := []struct {
+		name         string
+		obj          *unstructured.Unstructured
+		toAPIVersion string
+	}{
+		{
+			name:         "unregistered kind",
+			obj:          &unstructured.Unstructured{Object: map[string]any{"apiVersion": "example.konghq.com/v1", "kind": "Gadget"}},
+			toAPIVersion: "example.konghq.com/v2",
+		},
+		{
+			name:         "different group",
+			obj:          widget("v1", nil),
+			toAPIVersion: "other.konghq.com/v2",
+		},
+		{
+			name:         "unregistered target version",
+			obj:          widget("v2", nil),
+			toAPIVersion: "example.konghq.com/v3",
+		},
+		{
+			name:         "unregistered source version",
+			obj:          widget("v3", nil),
+			toAPIVersion: "example.konghq.com/v2",
+		},
+		{
+			name:         "invalid apiVersion",
+			obj:          widget("v1", nil),
+			toAPIVersion: "a/b/c",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, r.Convert(tc.obj, tc.toAPIVersion))
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	r := NewRegistry()
+	require.Error(t, r.Register(testGK, "v1", Spoke{Version: "v1"}), "spoke can't be the hub version")
+	require.Error(t, r.Register(testGK, "v2", Spoke{Version: "v1"}, Spoke{Version: "v1"}), "spoke versions have to be unique")
+	require.NoError(t, r.Register(testGK, "v2", Spoke{Version: "v1"}, Spoke{Version: "v1beta1"}))
+	require.Error(t, r.Register(testGK, "v2"), "kind can't be registered twice")
+	require.Equal(t, []string{"v2", "v1", "v1beta1"}, r.Versions(testGK))
+	require.Nil(t, r.Versions(schema.GroupKind{Group: "example.konghq.com", Kind: "Gadget"}))
+}
+
+func TestRegisterHubs(t *testing.T) {
+	s := scheme.Get()
+
+	t.Run("kinds served at a single version", func(t *testing.T) {
+		r := NewRegistry()
+		require.NoError(t, r.RegisterHubs(s, operatorv1beta1.SchemeGroupVersion))
+		require.Equal(t, []string{"v1beta1"}, r.Versions(operatorv1beta1.SchemeGroupVersion.WithKind("DataPlane").GroupKind()))
+		require.NotContains(t, r.GroupKinds(), operatorv1beta1.SchemeGroupVersion.WithKind("DataPlaneList").GroupKind())
+	})
+
+	t.Run("kinds served at multiple versions", func(t *testing.T) {
+		s := scheme.Get()
+		s.AddKnownTypeWithName(operatorv1alpha1.SchemeGroupVersion.WithKind("DataPlane"), &operatorv1beta1.DataPlane{})
+
+		r := NewRegistry()
+		require.Error(t, r.RegisterHubs(s, operatorv1alpha1.SchemeGroupVersion, operatorv1beta1.SchemeGroupVersion))
+
+		r = NewRegistry()
+		gk := operatorv1beta1.SchemeGroupVersion.WithKind("DataPlane").GroupKind()
+		require.NoError(t, r.Register(gk, "v1beta1", Spoke{Version: "v1alpha1"}))
+		require.NoError(t, r.RegisterHubs(s, operatorv1alpha1.SchemeGroupVersion, operatorv1beta1.SchemeGroupVersion))
+		require.Equal(t, []string{"v1beta1", "v1alpha1"}, r.Versions(gk))
+	})
+}
+
+func TestNewDefaultRegistry(t *testing.T) {
+	r, err := NewDefaultRegistry(scheme.Get())
+	require.NoError(t, err)
+	for _, kind := range []string{"DataPlane", "ControlPlane", "GatewayConfiguration"} {
+		require.Equal(t,
+			[]string{"v1beta1", "v1alpha1"},
+			r.Versions(operatorv1beta1.SchemeGroupVersion.WithKind(kind).GroupKind()),
+		)
+	}
+	require.Equal(t,
+		[]string{"v1alpha1"},
+		r.Versions(operatorv1alpha1.SchemeGroupVersion.WithKind("AIGateway").GroupKind()),
+	)
+}
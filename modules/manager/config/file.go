@@ -0,0 +1,362 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OperatorConfigurationAPIVersion is the supported apiVersion of the operator's configuration file.
+	OperatorConfigurationAPIVersion = "gateway-operator.konghq.com/v1alpha1"
+	// OperatorConfigurationKind is the kind of the operator's configuration file.
+	OperatorConfigurationKind = "OperatorConfiguration"
+)
+
+// OperatorConfiguration is the versioned configuration file of the operator.
+// It is an alternative to the command line flags: every setting which is not set
+// in the file falls back to the value of its flag (or the flag's default value),
+// and flags which are explicitly set take precedence over the file.
+//
+// Settings marked as reloadable are applied without restarting the operator
+// when the file changes.
+//
+// Example:
+//
+//	apiVersion: gateway-operator.konghq.com/v1alpha1
+//	kind: OperatorConfiguration
+//	logLevel: debug
+//	controllers:
+//	  konnect: true
+//	konnect:
+//	  syncPeriod: 30s
+type OperatorConfiguration struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	MetricsBindAddress     *string  `json:"metricsBindAddress,omitempty"`
+	MetricsAccessFilter    *string  `json:"metricsAccessFilter,omitempty"`
+	HealthProbeBindAddress *string  `json:"healthProbeBindAddress,omitempty"`
+	LeaderElection         *bool    `json:"leaderElection,omitempty"`
+	EnforceConfig          *bool    `json:"enforceConfig,omitempty"`
+	ControllerName         *string  `json:"controllerName,omitempty"`
+	WatchNamespaces        []string `json:"watchNamespaces,omitempty"`
+	ShardCount             *int     `json:"shardCount,omitempty"`
+	GatewayAPIExperimental *bool    `json:"gatewayAPIExperimental,omitempty"`
+
+	ClusterCA   ClusterCAConfiguration   `json:"clusterCA,omitempty"`
+	Controllers ControllersConfiguration `json:"controllers,omitempty"`
+	DataPlane   DataPlaneConfiguration   `json:"dataPlane,omitempty"`
+	Konnect     KonnectConfiguration     `json:"konnect,omitempty"`
+	Tracing     TracingConfiguration     `json:"tracing,omitempty"`
+
+	ConversionWebhook ConversionWebhookConfiguration `json:"conversionWebhook,omitempty"`
+	ValidatingWebhook ValidatingWebhookConfiguration `json:"validatingWebhook,omitempty"`
+
+	// LogLevel is the log level: debug, info, error or an integer greater
+	// than 0 for custom debug levels. Reloadable.
+	LogLevel *string `json:"logLevel,omitempty"`
+	// AnonymousReports enables sending anonymized usage data. Reloadable.
+	AnonymousReports *bool `json:"anonymousReports,omitempty"`
+}
+
+// ClusterCAConfiguration configures the cluster CA.
+type ClusterCAConfiguration struct {
+	SecretName      *string  `json:"secretName,omitempty"`
+	SecretNamespace *string  `json:"secretNamespace,omitempty"`
+	KeyType         *KeyType `json:"keyType,omitempty"`
+	KeySize         *int     `json:"keySize,omitempty"`
+}
+
+// ControllersConfiguration enables or disables the operator's controllers.
+type ControllersConfiguration struct {
+	Gateway                *bool `json:"gateway,omitempty"`
+	ControlPlane           *bool `json:"controlPlane,omitempty"`
+	DataPlane              *bool `json:"dataPlane,omitempty"`
+	DataPlaneBlueGreen     *bool `json:"dataPlaneBlueGreen,omitempty"`
+	AIGateway              *bool `json:"aiGateway,omitempty"`
+	KongPluginInstallation *bool `json:"kongPluginInstallation,omitempty"`
+	Konnect                *bool `json:"konnect,omitempty"`
+}
+
+// DataPlaneConfiguration configures the DataPlane controllers.
+type DataPlaneConfiguration struct {
+	// ConfigSyncCheckInterval is the interval at which the configuration loaded
+	// by DataPlane Pods is checked. Reloadable.
+	ConfigSyncCheckInterval *metav1.Duration `json:"configSyncCheckInterval,omitempty"`
+}
+
+// KonnectConfiguration configures the Konnect controllers.
+type KonnectConfiguration struct {
+	// SyncPeriod is the sync period of Konnect entities. Reloadable.
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles
+	// of Konnect entities. Reloadable, but it cannot be increased above
+	// the value the operator was started with without a restart.
+	MaxConcurrentReconciles *uint `json:"maxConcurrentReconciles,omitempty"`
+}
+
+// TracingConfiguration configures the export of traces.
+type TracingConfiguration struct {
+	// OTLPEndpoint is the host:port of the OTLP gRPC collector.
+	// Tracing is disabled when it's empty.
+	OTLPEndpoint *string `json:"otlpEndpoint,omitempty"`
+	// Insecure disables TLS when connecting to the collector.
+	Insecure *bool `json:"insecure,omitempty"`
+	// SamplingRatio is the ratio of sampled traces, between 0 and 1.
+	SamplingRatio *float64 `json:"samplingRatio,omitempty"`
+}
+
+// ConversionWebhookConfiguration configures the conversion webhook.
+type ConversionWebhookConfiguration struct {
+	// Enabled enables the conversion webhook.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Port is the port the conversion webhook listens on.
+	Port *int `json:"port,omitempty"`
+	// ServiceName is the name of the Service through which the API server
+	// reaches the conversion webhook.
+	ServiceName *string `json:"serviceName,omitempty"`
+}
+
+// ValidatingWebhookConfiguration configures the validating webhook for
+// AIGateways, served on the conversion webhook's port and Service.
+type ValidatingWebhookConfiguration struct {
+	// Enabled enables the validating webhook.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// LoadOperatorConfiguration reads, parses and validates the configuration file
+// at the provided path.
+func LoadOperatorConfiguration(path string) (*OperatorConfiguration, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file %s: %w", path, err)
+	}
+	return ParseOperatorConfiguration(b)
+}
+
+// ParseOperatorConfiguration parses and validates the provided configuration file
+// content. Unknown fields are rejected.
+func ParseOperatorConfiguration(b []byte) (*OperatorConfiguration, error) {
+	var cfg OperatorConfiguration
+	if err := yaml.UnmarshalStrict(bytes.TrimSpace(b), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse configuration file: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration file: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Validate validates the configuration.
+func (c *OperatorConfiguration) Validate() error {
+	var errs []error
+	if c.APIVersion != OperatorConfigurationAPIVersion {
+		errs = append(errs, fmt.Errorf("unsupported apiVersion %q, expected %q", c.APIVersion, OperatorConfigurationAPIVersion))
+	}
+	if c.Kind != OperatorConfigurationKind {
+		errs = append(errs, fmt.Errorf("unsupported kind %q, expected %q", c.Kind, OperatorConfigurationKind))
+	}
+	if c.MetricsAccessFilter != nil && *c.MetricsAccessFilter != "off" && *c.MetricsAccessFilter != "rbac" {
+		errs = append(errs, fmt.Errorf("invalid metricsAccessFilter %q, expected one of: off, rbac", *c.MetricsAccessFilter))
+	}
+	if c.ShardCount != nil && *c.ShardCount < 0 {
+		errs = append(errs, fmt.Errorf("shardCount cannot be negative, got %d", *c.ShardCount))
+	}
+	if c.ClusterCA.KeyType != nil && *c.ClusterCA.KeyType != ECDSA && *c.ClusterCA.KeyType != RSA {
+		errs = append(errs, fmt.Errorf("invalid clusterCA.keyType %q, expected one of: ecdsa, rsa", *c.ClusterCA.KeyType))
+	}
+	if c.ClusterCA.KeySize != nil && *c.ClusterCA.KeySize <= 0 {
+		errs = append(errs, fmt.Errorf("clusterCA.keySize has to be positive, got %d", *c.ClusterCA.KeySize))
+	}
+	if c.LogLevel != nil {
+		if _, err := ParseLogLevel(*c.LogLevel); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if d := c.DataPlane.ConfigSyncCheckInterval; d != nil && d.Duration < 0 {
+		errs = append(errs, fmt.Errorf("dataPlane.configSyncCheckInterval cannot be negative, got %s", d.Duration))
+	}
+	if d := c.Konnect.SyncPeriod; d != nil && d.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("konnect.syncPeriod has to be positive, got %s", d.Duration))
+	}
+	if n := c.Konnect.MaxConcurrentReconciles; n != nil && *n == 0 {
+		errs = append(errs, errors.New("konnect.maxConcurrentReconciles has to be positive"))
+	}
+	if r := c.Tracing.SamplingRatio; r != nil && (*r < 0 || *r > 1) {
+		errs = append(errs, fmt.Errorf("tracing.samplingRatio has to be between 0 and 1, got %v", *r))
+	}
+	if p := c.ConversionWebhook.Port; p != nil && (*p <= 0 || *p > 65535) {
+		errs = append(errs, fmt.Errorf("conversionWebhook.port has to be between 1 and 65535, got %d", *p))
+	}
+	return errors.Join(errs...)
+}
+
+// FlagValues returns the values of the command line flags corresponding to
+// the settings set in the configuration file, keyed by the flag names.
+func (c *OperatorConfiguration) FlagValues() map[string]string {
+	values := make(map[string]string)
+	setString := func(flag string, v *string) {
+		if v != nil {
+			values[flag] = *v
+		}
+	}
+	setBool := func(flag string, v *bool) {
+		if v != nil {
+			values[flag] = strconv.FormatBool(*v)
+		}
+	}
+	setInt := func(flag string, v *int) {
+		if v != nil {
+			values[flag] = strconv.Itoa(*v)
+		}
+	}
+	setDuration := func(flag string, v *metav1.Duration) {
+		if v != nil {
+			values[flag] = v.Duration.String()
+		}
+	}
+
+	setString("metrics-bind-address", c.MetricsBindAddress)
+	setString("metrics-access-filter", c.MetricsAccessFilter)
+	setString("health-probe-bind-address", c.HealthProbeBindAddress)
+	if c.LeaderElection != nil {
+		values["no-leader-election"] = strconv.FormatBool(!*c.LeaderElection)
+	}
+	setBool("enforce-config", c.EnforceConfig)
+	setString("controller-name", c.ControllerName)
+	if len(c.WatchNamespaces) > 0 {
+		values["watch-namespaces"] = strings.Join(c.WatchNamespaces, ",")
+	}
+	setInt("shard-count", c.ShardCount)
+	setBool("enable-gateway-api-experimental", c.GatewayAPIExperimental)
+
+	setString("cluster-ca-secret", c.ClusterCA.SecretName)
+	setString("cluster-ca-secret-namespace", c.ClusterCA.SecretNamespace)
+	if c.ClusterCA.KeyType != nil {
+		values["cluster-ca-key-type"] = string(*c.ClusterCA.KeyType)
+	}
+	setInt("cluster-ca-key-size", c.ClusterCA.KeySize)
+
+	setBool("enable-controller-gateway", c.Controllers.Gateway)
+	setBool("enable-controller-controlplane", c.Controllers.ControlPlane)
+	setBool("enable-controller-dataplane", c.Controllers.DataPlane)
+	setBool("enable-controller-dataplane-bluegreen", c.Controllers.DataPlaneBlueGreen)
+	setBool("enable-controller-aigateway", c.Controllers.AIGateway)
+	setBool("enable-controller-kongplugininstallation", c.Controllers.KongPluginInstallation)
+	setBool("enable-controller-konnect", c.Controllers.Konnect)
+
+	setDuration("dataplane-config-sync-check-interval", c.DataPlane.ConfigSyncCheckInterval)
+	setDuration("konnect-sync-period", c.Konnect.SyncPeriod)
+	if c.Konnect.MaxConcurrentReconciles != nil {
+		values["konnect-controller-max-concurrent-reconciles"] = strconv.FormatUint(uint64(*c.Konnect.MaxConcurrentReconciles), 10)
+	}
+
+	setString("tracing-otlp-endpoint", c.Tracing.OTLPEndpoint)
+	setBool("tracing-otlp-insecure", c.Tracing.Insecure)
+	if c.Tracing.SamplingRatio != nil {
+		values["tracing-sampling-ratio"] = strconv.FormatFloat(*c.Tracing.SamplingRatio, 'f', -1, 64)
+	}
+
+	setBool("enable-conversion-webhook", c.ConversionWebhook.Enabled)
+	setInt("conversion-webhook-port", c.ConversionWebhook.Port)
+	setString("conversion-webhook-service-name", c.ConversionWebhook.ServiceName)
+	setBool("enable-validating-webhook", c.ValidatingWebhook.Enabled)
+
+	setString("zap-log-level", c.LogLevel)
+	setBool("anonymous-reports", c.AnonymousReports)
+
+	return values
+}
+
+// WithoutReloadableSettings returns a copy of the configuration with
+// the reloadable settings cleared. It's used to detect changes requiring
+// a restart of the operator.
+func (c *OperatorConfiguration) WithoutReloadableSettings() OperatorConfiguration {
+	cfg := *c
+	cfg.LogLevel = nil
+	cfg.AnonymousReports = nil
+	cfg.DataPlane.ConfigSyncCheckInterval = nil
+	cfg.Konnect.SyncPeriod = nil
+	cfg.Konnect.MaxConcurrentReconciles = nil
+	return cfg
+}
+
+// ParseLogLevel parses the log level in the format accepted by the
+// --zap-log-level flag: debug, info, error or an integer greater than 0
+// for custom debug levels.
+func ParseLogLevel(level string) (zapcore.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	l, err := strconv.Atoi(level)
+	if err != nil || l <= 0 {
+		return 0, fmt.Errorf("invalid log level %q, expected one of: debug, info, error or an integer greater than 0", level)
+	}
+	return zapcore.Level(int8(-1 * l)), nil //nolint:gosec
+}
+
+// ReloadableSettings holds the settings which can be changed while the operator
+// is running. It is safe for concurrent use.
+type ReloadableSettings struct {
+	konnectSyncPeriod                atomic.Int64
+	konnectMaxConcurrentReconciles   atomic.Uint64
+	dataPlaneConfigSyncCheckInterval atomic.Int64
+}
+
+// NewReloadableSettings returns ReloadableSettings initialized with the provided values.
+func NewReloadableSettings(konnectSyncPeriod time.Duration, konnectMaxConcurrentReconciles uint, dataPlaneConfigSyncCheckInterval time.Duration) *ReloadableSettings {
+	s := &ReloadableSettings{}
+	s.SetKonnectSyncPeriod(konnectSyncPeriod)
+	s.SetKonnectMaxConcurrentReconciles(konnectMaxConcurrentReconciles)
+	s.SetDataPlaneConfigSyncCheckInterval(dataPlaneConfigSyncCheckInterval)
+	return s
+}
+
+// KonnectSyncPeriod returns the sync period of Konnect entities.
+func (s *ReloadableSettings) KonnectSyncPeriod() time.Duration {
+	return time.Duration(s.konnectSyncPeriod.Load())
+}
+
+// SetKonnectSyncPeriod sets the sync period of Konnect entities.
+func (s *ReloadableSettings) SetKonnectSyncPeriod(d time.Duration) {
+	s.konnectSyncPeriod.Store(int64(d))
+}
+
+// KonnectMaxConcurrentReconciles returns the maximum number of concurrent
+// reconciles of Konnect entities.
+func (s *ReloadableSettings) KonnectMaxConcurrentReconciles() uint {
+	return uint(s.konnectMaxConcurrentReconciles.Load())
+}
+
+// SetKonnectMaxConcurrentReconciles sets the maximum number of concurrent
+// reconciles of Konnect entities.
+func (s *ReloadableSettings) SetKonnectMaxConcurrentReconciles(n uint) {
+	s.konnectMaxConcurrentReconciles.Store(uint64(n))
+}
+
+// DataPlaneConfigSyncCheckInterval returns the interval at which the configuration
+// loaded by DataPlane Pods is checked.
+func (s *ReloadableSettings) DataPlaneConfigSyncCheckInterval() time.Duration {
+	return time.Duration(s.dataPlaneConfigSyncCheckInterval.Load())
+}
+
+// SetDataPlaneConfigSyncCheckInterval sets the interval at which the configuration
+// loaded by DataPlane Pods is checked.
+func (s *ReloadableSettings) SetDataPlaneConfigSyncCheckInterval(d time.Duration) {
+	s.dataPlaneConfigSyncCheckInterval.Store(int64(d))
+}
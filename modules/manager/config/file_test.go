@@ -0,0 +1,142 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseOperatorConfiguration(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expected      *OperatorConfiguration
+		expectedError string
+	}{
+		{
+			name: "valid configuration",
+			content: `
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+leaderElection: false
+watchNamespaces:
+- ns1
+- ns2
+clusterCA:
+  keyType: rsa
+  keySize: 2048
+controllers:
+  konnect: true
+konnect:
+  syncPeriod: 30s
+  maxConcurrentReconciles: 4
+logLevel: debug
+`,
+			expected: &OperatorConfiguration{
+				APIVersion:      OperatorConfigurationAPIVersion,
+				Kind:            OperatorConfigurationKind,
+				LeaderElection:  lo.ToPtr(false),
+				WatchNamespaces: []string{"ns1", "ns2"},
+				ClusterCA: ClusterCAConfiguration{
+					KeyType: lo.ToPtr(RSA),
+					KeySize: lo.ToPtr(2048),
+				},
+				Controllers: ControllersConfiguration{
+					Konnect: lo.ToPtr(true),
+				},
+				Konnect: KonnectConfiguration{
+					SyncPeriod:              &metav1.Duration{Duration: 30 * time.Second},
+					MaxConcurrentReconciles: lo.ToPtr(uint(4)),
+				},
+				LogLevel: lo.ToPtr("debug"),
+			},
+		},
+		{
+			name: "unsupported apiVersion",
+			content: `
+apiVersion: gateway-operator.konghq.com/v1
+kind: OperatorConfiguration
+`,
+			expectedError: `unsupported apiVersion "gateway-operator.konghq.com/v1"`,
+		},
+		{
+			name: "unknown field",
+			content: `
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+unknown: true
+`,
+			expectedError: `unknown field "unknown"`,
+		},
+		{
+			name: "invalid values",
+			content: `
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+logLevel: verbose
+konnect:
+  syncPeriod: 0s
+`,
+			expectedError: `invalid log level "verbose"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := ParseOperatorConfiguration([]byte(tc.content))
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, cfg)
+		})
+	}
+}
+
+func TestOperatorConfigurationFlagValues(t *testing.T) {
+	cfg := OperatorConfiguration{
+		LeaderElection:  lo.ToPtr(false),
+		WatchNamespaces: []string{"ns1", "ns2"},
+		ShardCount:      lo.ToPtr(3),
+		ClusterCA: ClusterCAConfiguration{
+			KeyType: lo.ToPtr(ECDSA),
+		},
+		DataPlane: DataPlaneConfiguration{
+			ConfigSyncCheckInterval: &metav1.Duration{Duration: time.Minute},
+		},
+		Konnect: KonnectConfiguration{
+			MaxConcurrentReconciles: lo.ToPtr(uint(4)),
+		},
+		Tracing: TracingConfiguration{
+			OTLPEndpoint:  lo.ToPtr("otel-collector:4317"),
+			SamplingRatio: lo.ToPtr(0.5),
+		},
+		ConversionWebhook: ConversionWebhookConfiguration{
+			Enabled: lo.ToPtr(true),
+		},
+		ValidatingWebhook: ValidatingWebhookConfiguration{
+			Enabled: lo.ToPtr(true),
+		},
+		LogLevel:         lo.ToPtr("2"),
+		AnonymousReports: lo.ToPtr(false),
+	}
+
+	require.Equal(t, map[string]string{
+		"no-leader-election":                           "true",
+		"watch-namespaces":                             "ns1,ns2",
+		"shard-count":                                  "3",
+		"cluster-ca-key-type":                          "ecdsa",
+		"dataplane-config-sync-check-interval":         "1m0s",
+		"konnect-controller-max-concurrent-reconciles": "4",
+		"tracing-otlp-endpoint":                        "otel-collector:4317",
+		"tracing-sampling-ratio":                       "0.5",
+		"enable-conversion-webhook":                    "true",
+		"enable-validating-webhook":                    "true",
+		"zap-log-level":                                "2",
+		"anonymous-reports":                            "false",
+	}, cfg.FlagValues())
+}
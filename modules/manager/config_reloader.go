@@ -0,0 +1,198 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	mgrconfig "github.com/kong/gateway-operator/modules/manager/config"
+)
+
+// configFileReloadInterval is the interval at which the configuration file
+// is checked for changes.
+const configFileReloadInterval = 10 * time.Second
+
+// reloadableSettingsFromConfig returns ReloadableSettings initialized with
+// the values from the provided Config.
+func reloadableSettingsFromConfig(cfg *Config) *mgrconfig.ReloadableSettings {
+	return mgrconfig.NewReloadableSettings(
+		cfg.KonnectSyncPeriod,
+		cfg.KonnectMaxConcurrentReconciles,
+		cfg.DataPlaneConfigSyncCheckInterval,
+	)
+}
+
+// anonymousReports starts and stops the anonymous reports on demand.
+type anonymousReports struct {
+	start func() (func(), error)
+
+	lock sync.Mutex
+	stop func()
+}
+
+// setEnabled starts or stops the anonymous reports. It's a no-op when
+// the reports are already in the requested state.
+func (r *anonymousReports) setEnabled(enabled bool) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch {
+	case enabled && r.stop == nil:
+		stop, err := r.start()
+		if err != nil {
+			return err
+		}
+		r.stop = stop
+	case !enabled && r.stop != nil:
+		r.stop()
+		r.stop = nil
+	}
+	return nil
+}
+
+// configFileReloader periodically checks the operator's configuration file
+// and applies the reloadable settings when it changes. Changes to settings
+// which are not reloadable are only logged as they require a restart.
+type configFileReloader struct {
+	logger   logr.Logger
+	path     string
+	interval time.Duration
+
+	// overriddenFlags contains the flags which take precedence over the file.
+	overriddenFlags  map[string]struct{}
+	logLevel         zapcore.LevelEnabler
+	settings         *mgrconfig.ReloadableSettings
+	anonymousReports *anonymousReports
+	developmentMode  bool
+
+	content  []byte
+	baseline mgrconfig.OperatorConfiguration
+}
+
+func newConfigFileReloader(
+	logger logr.Logger,
+	cfg *Config,
+	reports *anonymousReports,
+) (*configFileReloader, error) {
+	content, err := os.ReadFile(cfg.ConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file %s: %w", cfg.ConfigFilePath, err)
+	}
+	fileCfg, err := mgrconfig.ParseOperatorConfiguration(content)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &configFileReloader{
+		logger:           logger,
+		path:             cfg.ConfigFilePath,
+		interval:         configFileReloadInterval,
+		overriddenFlags:  make(map[string]struct{}, len(cfg.ConfigFileOverriddenFlags)),
+		settings:         cfg.ReloadableSettings,
+		anonymousReports: reports,
+		developmentMode:  cfg.DevelopmentMode,
+		content:          content,
+		baseline:         fileCfg.WithoutReloadableSettings(),
+	}
+	if cfg.LoggerOpts != nil {
+		r.logLevel = cfg.LoggerOpts.Level
+	}
+	for _, f := range cfg.ConfigFileOverriddenFlags {
+		r.overriddenFlags[f] = struct{}{}
+	}
+	return r, nil
+}
+
+// NeedLeaderElection implements LeaderElectionRunnable. Every replica has to
+// apply the configuration changes.
+func (r *configFileReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements Runnable.
+func (r *configFileReloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.reload()
+		}
+	}
+}
+
+// reload applies the configuration file if its content has changed since
+// the last check. Invalid configuration is logged and ignored.
+func (r *configFileReloader) reload() {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		r.logger.Error(err, "failed to read configuration file", "path", r.path)
+		return
+	}
+	if bytes.Equal(content, r.content) {
+		return
+	}
+	r.content = content
+
+	fileCfg, err := mgrconfig.ParseOperatorConfiguration(content)
+	if err != nil {
+		r.logger.Error(err, "ignoring invalid configuration file, keeping the previous configuration", "path", r.path)
+		return
+	}
+
+	r.logger.Info("configuration file changed, applying reloadable settings", "path", r.path)
+	if !reflect.DeepEqual(fileCfg.WithoutReloadableSettings(), r.baseline) {
+		r.logger.Info("configuration file contains changes which require a restart to be applied", "path", r.path)
+	}
+	r.apply(fileCfg)
+}
+
+// apply applies the reloadable settings set in the configuration file.
+// Settings which are not set in the file or which are overridden by flags
+// are left unchanged.
+func (r *configFileReloader) apply(fileCfg *mgrconfig.OperatorConfiguration) {
+	values := fileCfg.FlagValues()
+	isSet := func(flag string) bool {
+		if _, ok := r.overriddenFlags[flag]; ok {
+			return false
+		}
+		_, ok := values[flag]
+		return ok
+	}
+
+	if isSet("zap-log-level") {
+		// Validated when parsing the configuration file.
+		level, _ := mgrconfig.ParseLogLevel(*fileCfg.LogLevel)
+		if atomicLevel, ok := r.logLevel.(uberzap.AtomicLevel); ok {
+			atomicLevel.SetLevel(level)
+		} else {
+			r.logger.Info("log level cannot be changed without a restart")
+		}
+	}
+	if isSet("anonymous-reports") && r.anonymousReports != nil {
+		// Anonymous reports are never sent for development builds.
+		if err := r.anonymousReports.setEnabled(*fileCfg.AnonymousReports && !r.developmentMode); err != nil {
+			r.logger.Error(err, "failed to apply anonymous reports setting")
+		}
+	}
+	if isSet("dataplane-config-sync-check-interval") {
+		r.settings.SetDataPlaneConfigSyncCheckInterval(fileCfg.DataPlane.ConfigSyncCheckInterval.Duration)
+	}
+	if isSet("konnect-sync-period") {
+		r.settings.SetKonnectSyncPeriod(fileCfg.Konnect.SyncPeriod.Duration)
+	}
+	if isSet("konnect-controller-max-concurrent-reconciles") {
+		r.settings.SetKonnectMaxConcurrentReconciles(*fileCfg.Konnect.MaxConcurrentReconciles)
+	}
+}
@@ -0,0 +1,90 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestConfigFileReloader(t *testing.T) {
+	const initialConfig = `
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+logLevel: info
+konnect:
+  syncPeriod: 1m
+`
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(initialConfig), 0o600))
+
+	level := uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+	cfg := DefaultConfig()
+	cfg.ConfigFilePath = configFile
+	cfg.ConfigFileOverriddenFlags = []string{"konnect-controller-max-concurrent-reconciles"}
+	cfg.LoggerOpts = &zap.Options{Level: level}
+	cfg.KonnectSyncPeriod = time.Minute
+	cfg.ReloadableSettings = reloadableSettingsFromConfig(&cfg)
+
+	var reportsStarted, reportsStopped int
+	reports := &anonymousReports{
+		start: func() (func(), error) {
+			reportsStarted++
+			return func() { reportsStopped++ }, nil
+		},
+	}
+	require.NoError(t, reports.setEnabled(true))
+
+	r, err := newConfigFileReloader(logr.Discard(), &cfg, reports)
+	require.NoError(t, err)
+
+	t.Log("reloadable settings are applied when the file changes")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+logLevel: debug
+anonymousReports: false
+dataPlane:
+  configSyncCheckInterval: 10s
+konnect:
+  syncPeriod: 30s
+  maxConcurrentReconciles: 2
+`), 0o600))
+	r.reload()
+	require.Equal(t, zapcore.DebugLevel, level.Level())
+	require.Equal(t, 10*time.Second, cfg.ReloadableSettings.DataPlaneConfigSyncCheckInterval())
+	require.Equal(t, 30*time.Second, cfg.ReloadableSettings.KonnectSyncPeriod())
+	require.Equal(t, 1, reportsStarted)
+	require.Equal(t, 1, reportsStopped)
+
+	t.Log("settings overridden by flags are not applied")
+	require.Equal(t, cfg.KonnectMaxConcurrentReconciles, cfg.ReloadableSettings.KonnectMaxConcurrentReconciles())
+
+	t.Log("invalid configuration is ignored")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+logLevel: info
+konnect:
+  syncPeriod: -1s
+`), 0o600))
+	r.reload()
+	require.Equal(t, zapcore.DebugLevel, level.Level())
+	require.Equal(t, 30*time.Second, cfg.ReloadableSettings.KonnectSyncPeriod())
+
+	t.Log("anonymous reports are started again when enabled")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+apiVersion: gateway-operator.konghq.com/v1alpha1
+kind: OperatorConfiguration
+anonymousReports: true
+`), 0o600))
+	r.reload()
+	require.Equal(t, 2, reportsStarted)
+	require.Equal(t, 1, reportsStopped)
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/samber/lo"
+	"go.opentelemetry.io/otel"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -23,11 +26,15 @@ import (
 	"github.com/kong/gateway-operator/controller/konnect"
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
 	sdkops "github.com/kong/gateway-operator/controller/konnect/ops/sdk"
+	"github.com/kong/gateway-operator/controller/pkg/ctxinjector"
+	"github.com/kong/gateway-operator/controller/pkg/events"
 	"github.com/kong/gateway-operator/controller/pkg/log"
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
 	"github.com/kong/gateway-operator/controller/specialized"
 	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/internal/tracing"
 	"github.com/kong/gateway-operator/internal/utils/index"
+	mgrconfig "github.com/kong/gateway-operator/modules/manager/config"
 	"github.com/kong/gateway-operator/pkg/consts"
 	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
 
@@ -44,6 +51,8 @@ const (
 	GatewayClassControllerName = "GatewayClass"
 	// GatewayControllerName is the name of the Gateway controller.
 	GatewayControllerName = "Gateway"
+	// GatewayConfigurationRolloutControllerName is the name of the GatewayConfigurationRollout controller.
+	GatewayConfigurationRolloutControllerName = "GatewayConfigurationRollout"
 	// ControlPlaneControllerName is the name of ControlPlane controller.
 	ControlPlaneControllerName = "ControlPlane"
 	// DataPlaneControllerName is the name of the DataPlane controller.
@@ -195,6 +204,15 @@ func setupIndexes(ctx context.Context, mgr manager.Manager, cfg Config) error {
 			}
 		}
 	}
+	if cfg.KongPluginInstallationControllerEnabled {
+		log.GetLogger(ctx, "KongPluginInstallation", cfg.DevelopmentMode).Info(
+			"creating index",
+			"indexField", index.SecretsIndex,
+		)
+		if err := index.SecretsOnKongPluginInstallation(ctx, mgr.GetCache()); err != nil {
+			return fmt.Errorf("failed to setup index for Secrets on KongPluginInstallation: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -203,6 +221,24 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 	ctx := context.Background()
 	// metricRecorder is the recorder used to record custom metrics in the controller manager's metrics server.
 	metricRecorder := metrics.NewGlobalCtrlRuntimeMetricsRecorder()
+	// settings holds the values which can be changed at runtime, e.g. by
+	// reloading the operator's configuration file.
+	settings := c.ReloadableSettings
+	if settings == nil {
+		settings = reloadableSettingsFromConfig(c)
+	}
+	// contextInjector propagates the TracerProvider set up by Run (or the no-op
+	// one when tracing is disabled) to the DataPlane reconcilers.
+	contextInjector := ctxinjector.NewCtxInjector(tracing.TracerProviderInjector(otel.GetTracerProvider()))
+	// newEventRecorder returns the recorder of the Events emitted by the controller
+	// with the provided name. The Events are rate limited as some controllers
+	// reconcile objects periodically, e.g. to sync them with Konnect.
+	newEventRecorder := func(controllerName string) record.EventRecorder {
+		return events.NewRateLimitedRecorder(
+			mgr.GetEventRecorderFor(strings.ToLower(controllerName)),
+			events.DefaultRateLimitInterval,
+		)
+	}
 
 	// These checks prevent controller-runtime spamming in logs about failing
 	// to get informer from cache.
@@ -413,6 +449,7 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				Scheme:                        mgr.GetScheme(),
 				DevelopmentMode:               c.DevelopmentMode,
 				GatewayAPIExperimentalEnabled: c.GatewayAPIExperimentalEnabled,
+				EventRecorder:                 newEventRecorder(GatewayClassControllerName),
 			},
 		},
 		// Gateway controller
@@ -424,6 +461,15 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				DevelopmentMode:       c.DevelopmentMode,
 				DefaultDataPlaneImage: consts.DefaultDataPlaneImage,
 				KonnectEnabled:        c.KonnectControllersEnabled,
+				EventRecorder:         newEventRecorder(GatewayControllerName),
+			},
+		},
+		// GatewayConfiguration staged rollout controller
+		GatewayConfigurationRolloutControllerName: {
+			Enabled: c.GatewayControllerEnabled,
+			Controller: &gateway.GatewayConfigurationRolloutReconciler{
+				Client:          mgr.GetClient(),
+				DevelopmentMode: c.DevelopmentMode,
 			},
 		},
 		// ControlPlane controller
@@ -438,6 +484,8 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				DevelopmentMode:          c.DevelopmentMode,
 				KonnectEnabled:           c.KonnectControllersEnabled,
 				EnforceConfig:            c.EnforceConfig,
+				EventRecorder:            newEventRecorder(ControlPlaneControllerName),
+				WatchNamespaces:          c.WatchNamespaces,
 			},
 		},
 		// DataPlane controller
@@ -454,9 +502,12 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 					BeforeDeployment: dataplane.CreateCallbackManager(),
 					AfterDeployment:  dataplane.CreateCallbackManager(),
 				},
-				DefaultImage:   consts.DefaultDataPlaneImage,
-				KonnectEnabled: c.KonnectControllersEnabled,
-				EnforceConfig:  c.EnforceConfig,
+				DefaultImage:                consts.DefaultDataPlaneImage,
+				KonnectEnabled:              c.KonnectControllersEnabled,
+				EnforceConfig:               c.EnforceConfig,
+				ConfigSyncCheckIntervalFunc: settings.DataPlaneConfigSyncCheckInterval,
+				ContextInjector:             contextInjector,
+				EventRecorder:               newEventRecorder(DataPlaneControllerName),
 			},
 		},
 		// DataPlaneBlueGreen controller
@@ -480,16 +531,21 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 						BeforeDeployment: dataplane.CreateCallbackManager(),
 						AfterDeployment:  dataplane.CreateCallbackManager(),
 					},
-					KonnectEnabled: c.KonnectControllersEnabled,
-					EnforceConfig:  c.EnforceConfig,
+					KonnectEnabled:              c.KonnectControllersEnabled,
+					EnforceConfig:               c.EnforceConfig,
+					ConfigSyncCheckIntervalFunc: settings.DataPlaneConfigSyncCheckInterval,
+					ContextInjector:             contextInjector,
+					EventRecorder:               newEventRecorder(DataPlaneControllerName),
 				},
 				Callbacks: dataplane.DataPlaneCallbacks{
 					BeforeDeployment: dataplane.CreateCallbackManager(),
 					AfterDeployment:  dataplane.CreateCallbackManager(),
 				},
-				DefaultImage:   consts.DefaultDataPlaneImage,
-				KonnectEnabled: c.KonnectControllersEnabled,
-				EnforceConfig:  c.EnforceConfig,
+				DefaultImage:    consts.DefaultDataPlaneImage,
+				KonnectEnabled:  c.KonnectControllersEnabled,
+				EnforceConfig:   c.EnforceConfig,
+				ContextInjector: contextInjector,
+				EventRecorder:   newEventRecorder(DataPlaneBlueGreenControllerName),
 			},
 		},
 		DataPlaneOwnedServiceFinalizerControllerName: {
@@ -520,6 +576,7 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				Client:          mgr.GetClient(),
 				Scheme:          mgr.GetScheme(),
 				DevelopmentMode: c.DevelopmentMode,
+				EventRecorder:   newEventRecorder(AIGatewayControllerName),
 			},
 		},
 		// KongPluginInstallation controller
@@ -529,6 +586,7 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 				Client:          mgr.GetClient(),
 				Scheme:          mgr.GetScheme(),
 				DevelopmentMode: c.DevelopmentMode,
+				EventRecorder:   newEventRecorder(KongPluginInstallationControllerName),
 			},
 		},
 	}
@@ -541,6 +599,11 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 
 		// REVIEW: Should we define the recorder here, or define it out of the section to allow setting custom metrics in other controllers
 
+		kongPluginReconciler := konnect.NewKongPluginReconciler(c.DevelopmentMode, mgr.GetClient())
+		kongPluginReconciler.EventRecorder = newEventRecorder(KongPluginControllerName)
+		kongCredentialSecretReconciler := konnect.NewKongCredentialSecretReconciler(c.DevelopmentMode, mgr.GetClient(), mgr.GetScheme())
+		kongCredentialSecretReconciler.EventRecorder = newEventRecorder(KongCredentialsSecretControllerName)
+
 		sdkFactory := sdkops.NewSDKFactory()
 		controllerFactory := konnectControllerFactory{
 			sdkFactory:              sdkFactory,
@@ -549,6 +612,8 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 			syncPeriod:              c.KonnectSyncPeriod,
 			maxConcurrentReconciles: c.KonnectMaxConcurrentReconciles,
 			metricRecorder:          metricRecorder,
+			newEventRecorder:        newEventRecorder,
+			settings:                settings,
 		}
 
 		konnectControllers := map[string]ControllerDef{
@@ -562,20 +627,13 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 			},
 
 			KongPluginControllerName: {
-				Enabled: c.KonnectControllersEnabled,
-				Controller: konnect.NewKongPluginReconciler(
-					c.DevelopmentMode,
-					mgr.GetClient(),
-				),
+				Enabled:    c.KonnectControllersEnabled,
+				Controller: kongPluginReconciler,
 			},
 
 			KongCredentialsSecretControllerName: {
-				Enabled: c.KonnectControllersEnabled,
-				Controller: konnect.NewKongCredentialSecretReconciler(
-					c.DevelopmentMode,
-					mgr.GetClient(),
-					mgr.GetScheme(),
-				),
+				Enabled:    c.KonnectControllersEnabled,
+				Controller: kongCredentialSecretReconciler,
 			},
 
 			KonnectExtensionControllerName: {
@@ -584,10 +642,11 @@ func SetupControllers(mgr manager.Manager, c *Config) (map[string]ControllerDef,
 					SdkFactory:               sdkFactory,
 					DevelopmentMode:          c.DevelopmentMode,
 					Client:                   mgr.GetClient(),
-					SyncPeriod:               c.KonnectSyncPeriod,
+					SyncPeriodFunc:           settings.KonnectSyncPeriod,
 					ClusterCASecretName:      c.ClusterCASecretName,
 					ClusterCASecretNamespace: c.ClusterCASecretNamespace,
 					ClusterCAKeyConfig:       clusterCAKeyConfig,
+					EventRecorder:            newEventRecorder(KonnectExtensionControllerName),
 				},
 			},
 
@@ -768,6 +827,8 @@ type konnectControllerFactory struct {
 	syncPeriod              time.Duration
 	maxConcurrentReconciles uint
 	metricRecorder          metrics.Recorder
+	newEventRecorder        func(controllerName string) record.EventRecorder
+	settings                *mgrconfig.ReloadableSettings
 }
 
 func newKonnectEntityController[
@@ -782,7 +843,10 @@ func newKonnectEntityController[
 			f.client,
 			konnect.WithKonnectEntitySyncPeriod[T, TEnt](f.syncPeriod),
 			konnect.WithKonnectMaxConcurrentReconciles[T, TEnt](f.maxConcurrentReconciles),
+			konnect.WithKonnectEntitySyncPeriodFunc[T, TEnt](f.settings.KonnectSyncPeriod),
+			konnect.WithKonnectMaxConcurrentReconcilesFunc[T, TEnt](f.settings.KonnectMaxConcurrentReconciles),
 			konnect.WithMetricRecorder[T, TEnt](f.metricRecorder),
+			konnect.WithEventRecorder[T, TEnt](f.newEventRecorder(constraints.EntityTypeName[T]())),
 		),
 	}
 }
@@ -22,11 +22,13 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -37,18 +39,23 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/internal/metrics"
 	"github.com/kong/gateway-operator/internal/telemetry"
+	"github.com/kong/gateway-operator/internal/tracing"
 	mgrconfig "github.com/kong/gateway-operator/modules/manager/config"
 	"github.com/kong/gateway-operator/modules/manager/metadata"
+	"github.com/kong/gateway-operator/modules/manager/sharding"
 	"github.com/kong/gateway-operator/pkg/vars"
 )
 
@@ -73,12 +80,51 @@ type Config struct {
 	ClusterCAKeySize         int
 	LoggerOpts               *zap.Options
 	EnforceConfig            bool
+	// WatchNamespaces restricts the manager's cache and all the controllers to
+	// the provided namespaces. All namespaces are watched when it's empty.
+	WatchNamespaces []string
+	// ShardCount is the number of shards the reconciled objects are distributed
+	// across. When set, every replica runs the controllers and only reconciles
+	// the objects from the shards it owns. Sharding is disabled when it's 0.
+	ShardCount int
+	// ConfigFilePath is the path to the operator's configuration file. When set,
+	// the file is watched and reloadable settings are applied when it changes.
+	ConfigFilePath string
+	// ConfigFileOverriddenFlags contains the names of the flags which were
+	// explicitly set and hence take precedence over the configuration file,
+	// also when it's reloaded.
+	ConfigFileOverriddenFlags []string
+	// ReloadableSettings holds the settings which can be changed at runtime.
+	// When nil, it's set up by Run based on the values of this Config.
+	ReloadableSettings *mgrconfig.ReloadableSettings
+	// TracingOTLPEndpoint is the host:port of the OTLP gRPC collector traces
+	// of reconciliations, Kubernetes client writes and Konnect API calls
+	// are exported to. Tracing is disabled when it's empty.
+	TracingOTLPEndpoint string
+	// TracingOTLPInsecure disables TLS when connecting to the OTLP collector.
+	TracingOTLPInsecure bool
+	// TracingSamplingRatio is the ratio of sampled traces, between 0 and 1.
+	TracingSamplingRatio float64
+	// ConversionWebhookEnabled enables the conversion webhook for the operator's
+	// CRDs served at multiple versions.
+	ConversionWebhookEnabled bool
+	// ValidatingWebhookEnabled enables the validating webhook for AIGateways.
+	// It's served along with the conversion webhook, on its port and Service.
+	ValidatingWebhookEnabled bool
+	// ConversionWebhookPort is the port the conversion and the validating
+	// webhooks listen on.
+	ConversionWebhookPort int
+	// ConversionWebhookServiceName is the name of the Service in the controller
+	// namespace through which the API server reaches the conversion and the
+	// validating webhooks.
+	ConversionWebhookServiceName string
 
 	// controllers for standard APIs and features
 	GatewayControllerEnabled            bool
 	ControlPlaneControllerEnabled       bool
 	DataPlaneControllerEnabled          bool
 	DataPlaneBlueGreenControllerEnabled bool
+	DataPlaneConfigSyncCheckInterval    time.Duration
 
 	// Controllers for specialty APIs and experimental features.
 	AIGatewayControllerEnabled              bool
@@ -157,17 +203,67 @@ func Run(
 		setupLog.Info("leader election disabled")
 	}
 
+	if len(cfg.WatchNamespaces) > 0 {
+		if err := validateNamespaceScopedConfig(cfg); err != nil {
+			return err
+		}
+		setupLog.Info("namespace-scoped mode enabled", "namespaces", cfg.WatchNamespaces)
+	}
+
+	newClient := cfg.NewClientFunc
+	if cfg.TracingOTLPEndpoint != "" {
+		if cfg.TracingSamplingRatio < 0 || cfg.TracingSamplingRatio > 1 {
+			return fmt.Errorf("tracing sampling ratio has to be between 0 and 1, got %v", cfg.TracingSamplingRatio)
+		}
+		shutdownTracing, err := tracing.SetupTracerProvider(context.Background(), tracing.Config{
+			OTLPEndpoint:  cfg.TracingOTLPEndpoint,
+			Insecure:      cfg.TracingOTLPInsecure,
+			SamplingRatio: cfg.TracingSamplingRatio,
+		}, metadata)
+		if err != nil {
+			return fmt.Errorf("unable to set up tracing: %w", err)
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				setupLog.Error(err, "failed to flush traces")
+			}
+		}()
+		newClient = newTracingClientFunc(newClient)
+		setupLog.Info("tracing enabled", "endpoint", cfg.TracingOTLPEndpoint, "sampling_ratio", cfg.TracingSamplingRatio)
+	}
+
 	restCfg := ctrl.GetConfigOrDie()
 	restCfg.UserAgent = metadata.UserAgent()
 
+	controllerCfg := config.Controller{
+		// This is needed because controller-runtime since v0.19.0 keeps a global list of controller
+		// names and panics if there are duplicates. This is a workaround for that in tests.
+		// Ref: https://github.com/kubernetes-sigs/controller-runtime/pull/2902#issuecomment-2284194683
+		SkipNameValidation: lo.ToPtr(true),
+	}
+	var (
+		newCache         cache.NewCacheFunc
+		shardCoordinator *sharding.Coordinator
+	)
+	if cfg.ShardCount > 0 {
+		var err error
+		shardCoordinator, err = setupShardCoordinator(restCfg, scheme, cfg)
+		if err != nil {
+			return fmt.Errorf("unable to set up sharding: %w", err)
+		}
+		newCache = sharding.NewCacheFunc(shardCoordinator)
+		// All the replicas run the controllers, each one reconciling only the
+		// objects from the shards it owns. Leader election is still used by
+		// the other runnables which have to run in a single replica.
+		controllerCfg.NeedLeaderElection = lo.ToPtr(false)
+		setupLog.Info("sharding enabled", "shards", cfg.ShardCount, "identity", shardCoordinator.Identity())
+	}
+
 	mgr, err := ctrl.NewManager(restCfg, ctrl.Options{
-		Controller: config.Controller{
-			// This is needed because controller-runtime since v0.19.0 keeps a global list of controller
-			// names and panics if there are duplicates. This is a workaround for that in tests.
-			// Ref: https://github.com/kubernetes-sigs/controller-runtime/pull/2902#issuecomment-2284194683
-			SkipNameValidation: lo.ToPtr(true),
-		},
-		Scheme: scheme,
+		Controller: controllerCfg,
+		Scheme:     scheme,
 		Metrics: server.Options{
 			BindAddress: cfg.MetricsAddr,
 			FilterProvider: func() func(c *rest.Config, httpClient *http.Client) (server.Filter, error) {
@@ -186,12 +282,20 @@ func Run(
 		LeaderElection:          cfg.LeaderElection,
 		LeaderElectionNamespace: cfg.LeaderElectionNamespace,
 		LeaderElectionID:        "a7feedc84.konghq.com",
-		NewClient:               cfg.NewClientFunc,
+		NewClient:               newClient,
+		Cache:                   cacheOptionsForWatchNamespaces(cfg),
+		NewCache:                newCache,
 	})
 	if err != nil {
 		return err
 	}
 
+	if shardCoordinator != nil {
+		if err := mgr.Add(shardCoordinator); err != nil {
+			return fmt.Errorf("unable to add shard coordinator: %w", err)
+		}
+	}
+
 	keyType, err := KeyTypeToX509PublicKeyAlgorithm(cfg.ClusterCAKeyType)
 	if err != nil {
 		return fmt.Errorf("unsupported cluster CA key type: %w", err)
@@ -211,12 +315,36 @@ func Run(
 		return fmt.Errorf("unable to start manager: %w", err)
 	}
 
+	if cfg.ConversionWebhookEnabled || cfg.ValidatingWebhookEnabled {
+		var validation http.Handler
+		if admissionRequestHandler != nil {
+			validation = admissionRequestHandler(mgr.GetClient(), ctrl.Log.WithName("admission"))
+		}
+		webhook, err := newWebhookServer(mgr, cfg, caMgr.keyConfig, validation)
+		if err != nil {
+			return fmt.Errorf("unable to set up webhook server: %w", err)
+		}
+		if err := mgr.Add(webhook); err != nil {
+			return fmt.Errorf("unable to add webhook server: %w", err)
+		}
+		setupLog.Info("webhook server enabled", "port", cfg.ConversionWebhookPort,
+			"conversion", cfg.ConversionWebhookEnabled, "validation", cfg.ValidatingWebhookEnabled)
+	}
+
 	ctx := context.Background()
+	if shardCoordinator != nil {
+		// Controllers only reconcile the objects from the shards owned by this replica.
+		ctx = sharding.IntoContext(ctx, shardCoordinator)
+	}
 
 	if err := setupIndexes(ctx, mgr, cfg); err != nil {
 		return err
 	}
 
+	if cfg.ReloadableSettings == nil {
+		cfg.ReloadableSettings = reloadableSettingsFromConfig(&cfg)
+	}
+
 	controllers, err := setupControllers(mgr, &cfg)
 	if err != nil {
 		setupLog.Error(err, "failed setting up controllers")
@@ -228,6 +356,10 @@ func Run(
 		}
 	}
 
+	if err := ctrlmetrics.Registry.Register(newReadinessCollector(mgr, cfg)); err != nil {
+		return fmt.Errorf("unable to register readiness metrics: %w", err)
+	}
+
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		return fmt.Errorf("unable to set up ready check: %w", err)
 	}
@@ -240,12 +372,25 @@ func Run(
 
 	// Enable anonnymous reporting when configured but not for development builds
 	// to reduce the noise.
+	reports := &anonymousReports{
+		start: func() (func(), error) {
+			return setupAnonymousReports(ctx, restCfg, setupLog, metadata, cfg)
+		},
+	}
+	defer func() { _ = reports.setEnabled(false) }()
 	if cfg.AnonymousReports && !cfg.DevelopmentMode {
-		stopAnonymousReports, err := setupAnonymousReports(ctx, restCfg, setupLog, metadata, cfg)
-		if err != nil {
+		if err := reports.setEnabled(true); err != nil {
 			setupLog.Error(err, "failed setting up anonymous reports")
-		} else {
-			defer stopAnonymousReports()
+		}
+	}
+
+	if cfg.ConfigFilePath != "" {
+		reloader, err := newConfigFileReloader(ctrl.Log.WithName("config_reloader"), &cfg, reports)
+		if err != nil {
+			return err
+		}
+		if err := mgr.Add(reloader); err != nil {
+			return fmt.Errorf("unable to add configuration file reloader: %w", err)
 		}
 	}
 
@@ -392,3 +537,107 @@ func setupAnonymousReports(
 
 	return tMgr.Stop, nil
 }
+
+// validateNamespaceScopedConfig checks that none of the enabled controllers
+// require cluster-scoped resources, which cannot be watched when the operator
+// is restricted to a set of namespaces.
+func validateNamespaceScopedConfig(cfg Config) error {
+	var errs []error
+	if cfg.GatewayControllerEnabled {
+		errs = append(errs, errors.New("the Gateway controller requires cluster-scoped GatewayClasses, disable it with --enable-controller-gateway=false"))
+	}
+	if cfg.AIGatewayControllerEnabled {
+		errs = append(errs, errors.New("the AIGateway controller requires cluster-scoped GatewayClasses, disable it with --enable-controller-aigateway=false"))
+	}
+	if cfg.KonnectControllersEnabled {
+		errs = append(errs, errors.New("the Konnect controllers require cluster-scoped KongVaults, disable them with --enable-controller-konnect=false"))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("unsupported configuration in namespace-scoped mode (--watch-namespaces=%s): %w",
+			strings.Join(cfg.WatchNamespaces, ","), errors.Join(errs...),
+		)
+	}
+	return nil
+}
+
+// cacheOptionsForWatchNamespaces returns the manager's cache options restricting
+// it to the watched namespaces and the namespace of the cluster CA Secret.
+// When no namespaces are configured, the cache is cluster-wide.
+func cacheOptionsForWatchNamespaces(cfg Config) cache.Options {
+	if len(cfg.WatchNamespaces) == 0 {
+		return cache.Options{}
+	}
+
+	namespaces := make(map[string]cache.Config, len(cfg.WatchNamespaces)+1)
+	for _, ns := range cfg.WatchNamespaces {
+		namespaces[ns] = cache.Config{}
+	}
+	if cfg.ClusterCASecretNamespace != "" {
+		namespaces[cfg.ClusterCASecretNamespace] = cache.Config{}
+	}
+	return cache.Options{
+		DefaultNamespaces: namespaces,
+	}
+}
+
+// setupShardCoordinator creates the Coordinator distributing the shards between
+// the operator replicas. Replicas are identified by their Pod name.
+func setupShardCoordinator(restCfg *rest.Config, scheme *runtime.Scheme, cfg Config) (*sharding.Coordinator, error) {
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine replica identity: %w", err)
+		}
+		identity = hostname
+	}
+
+	// The Coordinator uses a client which is not backed by the manager's cache
+	// as it has to acquire the shards before the controllers start.
+	cl, err := client.New(restCfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return sharding.NewCoordinator(cl, ctrl.Log.WithName("sharding"), sharding.Config{
+		Shards:    cfg.ShardCount,
+		Namespace: cfg.LeaderElectionNamespace,
+		Identity:  identity,
+	})
+}
+
+// tracingShutdownTimeout is the time given to export the remaining spans
+// when the manager stops.
+const tracingShutdownTimeout = 5 * time.Second
+
+// newTracingClientFunc returns a client.NewClientFunc which wraps the clients
+// created by the provided function (or the default one when nil) so that
+// their writes are traced.
+func newTracingClientFunc(newClient client.NewClientFunc) client.NewClientFunc {
+	if newClient == nil {
+		newClient = client.New
+	}
+	return func(config *rest.Config, options client.Options) (client.Client, error) {
+		c, err := newClient(config, options)
+		if err != nil {
+			return nil, err
+		}
+		return tracing.NewClient(c), nil
+	}
+}
+
+// newReadinessCollector returns a collector reporting readiness of the objects
+// of the kinds reconciled by the enabled controllers, read from the manager's cache.
+func newReadinessCollector(mgr manager.Manager, cfg Config) *metrics.ReadinessCollector {
+	var kinds []metrics.ReadinessKind
+	if cfg.GatewayControllerEnabled {
+		kinds = append(kinds, metrics.ReadinessKindGateway)
+	}
+	if cfg.GatewayControllerEnabled || cfg.DataPlaneControllerEnabled || cfg.DataPlaneBlueGreenControllerEnabled {
+		kinds = append(kinds, metrics.ReadinessKindDataPlane)
+	}
+	if cfg.GatewayControllerEnabled || cfg.ControlPlaneControllerEnabled {
+		kinds = append(kinds, metrics.ReadinessKindControlPlane)
+	}
+	return metrics.NewReadinessCollector(mgr.GetClient(), kinds...)
+}
@@ -0,0 +1,155 @@
+package sharding
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewCacheFunc returns a function creating the manager's cache which only
+// delivers events about the objects belonging to the shards owned by the
+// provided Coordinator. Events about cluster-scoped objects are always delivered.
+// When the Coordinator acquires new shards, add events are replayed for all
+// the cached objects belonging to them so that they get reconciled.
+//
+// Reads through the cache are not affected. Requests enqueued for objects from
+// shards which are not owned (e.g. by map funcs of cluster-scoped objects) are
+// dropped by the reconcilers wrapped with NewReconciler.
+func NewCacheFunc(coordinator *Coordinator) cache.NewCacheFunc {
+	return func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+		c, err := cache.New(config, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &shardedCache{Cache: c, coordinator: coordinator}, nil
+	}
+}
+
+type shardedCache struct {
+	cache.Cache
+	coordinator *Coordinator
+}
+
+// GetInformer implements cache.Cache.
+func (c *shardedCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	i, err := c.Cache.GetInformer(ctx, obj, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &shardedInformer{Informer: i, coordinator: c.coordinator}, nil
+}
+
+// GetInformerForKind implements cache.Cache.
+func (c *shardedCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	i, err := c.Cache.GetInformerForKind(ctx, gvk, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &shardedInformer{Informer: i, coordinator: c.coordinator}, nil
+}
+
+type shardedInformer struct {
+	cache.Informer
+	coordinator *Coordinator
+}
+
+// shardedRegistration wraps the registration of a handler to be able to
+// unsubscribe it from the Coordinator when it's removed.
+type shardedRegistration struct {
+	toolscache.ResourceEventHandlerRegistration
+	unsubscribe func()
+}
+
+// AddEventHandler implements cache.Informer.
+func (i *shardedInformer) AddEventHandler(handler toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.register(handler, func(h toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+		return i.Informer.AddEventHandler(h)
+	})
+}
+
+// AddEventHandlerWithResyncPeriod implements cache.Informer.
+func (i *shardedInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, resyncPeriod time.Duration) (toolscache.ResourceEventHandlerRegistration, error) {
+	return i.register(handler, func(h toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+		return i.Informer.AddEventHandlerWithResyncPeriod(h, resyncPeriod)
+	})
+}
+
+// RemoveEventHandler implements cache.Informer.
+func (i *shardedInformer) RemoveEventHandler(handle toolscache.ResourceEventHandlerRegistration) error {
+	if r, ok := handle.(*shardedRegistration); ok {
+		r.unsubscribe()
+		handle = r.ResourceEventHandlerRegistration
+	}
+	return i.Informer.RemoveEventHandler(handle)
+}
+
+func (i *shardedInformer) register(
+	handler toolscache.ResourceEventHandler,
+	add func(toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error),
+) (toolscache.ResourceEventHandlerRegistration, error) {
+	reg, err := add(&shardFilteringHandler{handler: handler, coordinator: i.coordinator})
+	if err != nil {
+		return nil, err
+	}
+
+	unsubscribe := func() {}
+	// Informers created by controller-runtime's cache expose their store which
+	// allows replaying the objects from the newly acquired shards.
+	if s, ok := i.Informer.(interface{ GetStore() toolscache.Store }); ok {
+		unsubscribe = i.coordinator.subscribe(func(acquired map[int]struct{}) {
+			for _, obj := range s.GetStore().List() {
+				o, err := meta.Accessor(obj)
+				if err != nil || o.GetNamespace() == "" {
+					continue
+				}
+				if _, ok := acquired[ShardForNamespace(o.GetNamespace(), i.coordinator.cfg.Shards)]; ok {
+					handler.OnAdd(obj, false)
+				}
+			}
+		})
+	}
+	return &shardedRegistration{ResourceEventHandlerRegistration: reg, unsubscribe: unsubscribe}, nil
+}
+
+// shardFilteringHandler only passes the events about objects belonging
+// to the shards owned by the Coordinator to the wrapped handler.
+type shardFilteringHandler struct {
+	handler     toolscache.ResourceEventHandler
+	coordinator *Coordinator
+}
+
+func (h *shardFilteringHandler) OnAdd(obj any, isInInitialList bool) {
+	if h.owns(obj) {
+		h.handler.OnAdd(obj, isInInitialList)
+	}
+}
+
+func (h *shardFilteringHandler) OnUpdate(oldObj, newObj any) {
+	if h.owns(newObj) {
+		h.handler.OnUpdate(oldObj, newObj)
+	}
+}
+
+func (h *shardFilteringHandler) OnDelete(obj any) {
+	if h.owns(obj) {
+		h.handler.OnDelete(obj)
+	}
+}
+
+func (h *shardFilteringHandler) owns(obj any) bool {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		// Let the wrapped handler deal with unexpected objects.
+		return true
+	}
+	return h.coordinator.OwnsNamespace(o.GetNamespace())
+}
@@ -0,0 +1,123 @@
+package sharding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+// fakeInformer is a cache.Informer delivering events to the registered handlers
+// and exposing its store like the informers created by controller-runtime.
+type fakeInformer struct {
+	cache.Informer
+	store    toolscache.Store
+	handlers []toolscache.ResourceEventHandler
+}
+
+func (i *fakeInformer) AddEventHandler(h toolscache.ResourceEventHandler) (toolscache.ResourceEventHandlerRegistration, error) {
+	i.handlers = append(i.handlers, h)
+	return nil, nil
+}
+
+func (i *fakeInformer) RemoveEventHandler(toolscache.ResourceEventHandlerRegistration) error {
+	i.handlers = nil
+	return nil
+}
+
+func (i *fakeInformer) GetStore() toolscache.Store {
+	return i.store
+}
+
+func (i *fakeInformer) add(obj any) {
+	_ = i.store.Add(obj)
+	for _, h := range i.handlers {
+		h.OnAdd(obj, false)
+	}
+}
+
+func (i *fakeInformer) delete(obj any) {
+	for _, h := range i.handlers {
+		h.OnDelete(obj)
+	}
+}
+
+type recordingHandler struct {
+	added   []string
+	deleted []string
+}
+
+func (h *recordingHandler) OnAdd(obj any, _ bool) {
+	h.added = append(h.added, key(obj))
+}
+
+func (h *recordingHandler) OnUpdate(_, obj any) {
+	h.added = append(h.added, key(obj))
+}
+
+func (h *recordingHandler) OnDelete(obj any) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	h.deleted = append(h.deleted, key(obj))
+}
+
+func key(obj any) string {
+	o := obj.(metav1.Object)
+	return o.GetNamespace() + "/" + o.GetName()
+}
+
+func TestShardedInformer(t *testing.T) {
+	const shards = 2
+	// Find namespaces belonging to each of the shards.
+	nsInShard := map[int]string{}
+	for i := 0; len(nsInShard) < shards; i++ {
+		ns := "ns-" + string(rune('a'+i))
+		if _, ok := nsInShard[ShardForNamespace(ns, shards)]; !ok {
+			nsInShard[ShardForNamespace(ns, shards)] = ns
+		}
+	}
+
+	coordinator, err := NewCoordinator(nil, logr.Discard(), Config{
+		Shards:    shards,
+		Namespace: "kong-system",
+		Identity:  "a",
+	})
+	require.NoError(t, err)
+	coordinator.owned[0] = time.Now()
+
+	underlying := &fakeInformer{store: toolscache.NewStore(toolscache.MetaNamespaceKeyFunc)}
+	informer := &shardedInformer{Informer: underlying, coordinator: coordinator}
+	handler := &recordingHandler{}
+	reg, err := informer.AddEventHandler(handler)
+	require.NoError(t, err)
+
+	owned := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: nsInShard[0], Name: "owned"}}
+	notOwned := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: nsInShard[1], Name: "not-owned"}}
+	clusterScoped := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-scoped"}}
+	underlying.add(owned)
+	underlying.add(notOwned)
+	underlying.add(clusterScoped)
+	underlying.delete(toolscache.DeletedFinalStateUnknown{Obj: owned})
+	underlying.delete(notOwned)
+
+	require.Equal(t, []string{key(owned), key(clusterScoped)}, handler.added)
+	require.Equal(t, []string{key(owned)}, handler.deleted)
+
+	t.Log("acquiring a shard replays the cached objects belonging to it")
+	handler.added = nil
+	coordinator.owned[1] = time.Now()
+	for _, f := range coordinator.subscribers {
+		f(map[int]struct{}{1: {}})
+	}
+	require.Equal(t, []string{key(notOwned)}, handler.added)
+
+	t.Log("removed handlers are not notified anymore")
+	require.NoError(t, informer.RemoveEventHandler(reg))
+	require.Empty(t, coordinator.subscribers)
+}
@@ -0,0 +1,460 @@
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LeaseTypeLabel is the label set on the Leases used for sharding to tell
+	// apart the Leases representing replicas from the ones representing shards.
+	LeaseTypeLabel = "gateway-operator.konghq.com/shard-lease-type"
+	// LeaseTypeMember is the value of LeaseTypeLabel for Leases representing
+	// operator replicas taking part in sharding.
+	LeaseTypeMember = "member"
+	// LeaseTypeShard is the value of LeaseTypeLabel for Leases representing shards.
+	LeaseTypeShard = "shard"
+
+	// DefaultLeaseDuration is the default duration of the sharding Leases.
+	DefaultLeaseDuration = 15 * time.Second
+	// DefaultRenewDeadline is the default duration after the last successful
+	// renewal of a shard's Lease after which this replica stops processing
+	// the shard.
+	DefaultRenewDeadline = 10 * time.Second
+	// DefaultRenewPeriod is the default period in which the sharding Leases are renewed
+	// and ownership of shards is rebalanced.
+	DefaultRenewPeriod = 5 * time.Second
+
+	leaseNamePrefix = "gateway-operator"
+)
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
+
+// Config is the configuration of the sharding Coordinator.
+type Config struct {
+	// Shards is the number of shards objects are distributed across.
+	Shards int
+	// Namespace is the namespace in which the sharding Leases are created.
+	Namespace string
+	// Identity is the unique identity of the operator replica, e.g. its Pod name.
+	Identity string
+	// LeaseDuration is the duration after which a Lease which was not renewed
+	// is considered expired.
+	LeaseDuration time.Duration
+	// RenewDeadline is the duration after the last successful renewal of
+	// a shard's Lease after which this replica stops processing the shard.
+	// It has to be shorter than LeaseDuration so that the shard is not processed
+	// anymore when another replica can take it over.
+	RenewDeadline time.Duration
+	// RenewPeriod is the period in which Leases are renewed and shards rebalanced.
+	RenewPeriod time.Duration
+}
+
+// Coordinator distributes shards between the operator replicas using Leases.
+// Each replica renews its own member Lease and shards are evenly assigned to
+// the live members. A shard is only owned by a replica once it holds the shard's
+// Lease so that a shard is never owned by two replicas at the same time: a shard
+// is only considered owned for RenewDeadline after its Lease was last renewed,
+// and shards whose Lease could not be renewed are dropped right away.
+//
+// Objects are assigned to shards by hashing their namespace so that all the
+// objects related to each other (e.g. Gateway, DataPlane and ControlPlane)
+// are reconciled by the same replica.
+type Coordinator struct {
+	client client.Client
+	logger logr.Logger
+	cfg    Config
+	now    func() time.Time
+
+	lock sync.RWMutex
+	// owned maps the shards owned by this replica to the time their Lease was
+	// last renewed.
+	owned       map[int]time.Time
+	subscribers map[int]func(acquired map[int]struct{})
+	nextSubID   int
+}
+
+// NewCoordinator returns a new Coordinator. The provided client should not be
+// backed by the manager's cache, as the Coordinator has to be able to operate
+// before the cache is started.
+func NewCoordinator(cl client.Client, logger logr.Logger, cfg Config) (*Coordinator, error) {
+	if cfg.Shards < 1 {
+		return nil, fmt.Errorf("number of shards has to be positive, got %d", cfg.Shards)
+	}
+	if cfg.Namespace == "" {
+		return nil, errors.New("namespace for sharding Leases is required")
+	}
+	if cfg.Identity == "" {
+		return nil, errors.New("identity of the replica is required")
+	}
+	if cfg.LeaseDuration == 0 {
+		cfg.LeaseDuration = DefaultLeaseDuration
+	}
+	if cfg.RenewDeadline == 0 {
+		cfg.RenewDeadline = DefaultRenewDeadline
+	}
+	if cfg.RenewPeriod == 0 {
+		cfg.RenewPeriod = DefaultRenewPeriod
+	}
+	if cfg.RenewDeadline >= cfg.LeaseDuration {
+		return nil, fmt.Errorf("renew deadline (%s) has to be shorter than lease duration (%s)", cfg.RenewDeadline, cfg.LeaseDuration)
+	}
+	if cfg.RenewPeriod >= cfg.RenewDeadline {
+		return nil, fmt.Errorf("renew period (%s) has to be shorter than renew deadline (%s)", cfg.RenewPeriod, cfg.RenewDeadline)
+	}
+
+	return &Coordinator{
+		client:      cl,
+		logger:      logger,
+		cfg:         cfg,
+		now:         time.Now,
+		owned:       make(map[int]time.Time),
+		subscribers: make(map[int]func(map[int]struct{})),
+	}, nil
+}
+
+// Identity returns the identity of this replica.
+func (c *Coordinator) Identity() string {
+	return c.cfg.Identity
+}
+
+// ShardForNamespace returns the shard the objects from the provided namespace belong to.
+func ShardForNamespace(namespace string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(shards)) //nolint:gosec
+}
+
+// OwnsNamespace returns true if the objects from the provided namespace
+// belong to a shard owned by this replica. Cluster-scoped objects (with empty
+// namespace) are considered owned by every replica.
+func (c *Coordinator) OwnsNamespace(namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	now := c.now()
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.ownsShardLocked(ShardForNamespace(namespace, c.cfg.Shards), now)
+}
+
+// OwnedShards returns the sorted list of shards owned by this replica.
+func (c *Coordinator) OwnedShards() []int {
+	now := c.now()
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	shards := lo.Filter(lo.Keys(c.owned), func(shard int, _ int) bool {
+		return c.ownsShardLocked(shard, now)
+	})
+	slices.Sort(shards)
+	return shards
+}
+
+// ownsShardLocked returns true if the provided shard is owned by this replica
+// and its Lease was renewed within the renew deadline. c.lock has to be held.
+func (c *Coordinator) ownsShardLocked(shard int, now time.Time) bool {
+	renewed, ok := c.owned[shard]
+	return ok && now.Before(renewed.Add(c.cfg.RenewDeadline))
+}
+
+// dropAll stops processing all the shards owned by this replica without
+// releasing their Leases.
+func (c *Coordinator) dropAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.owned = make(map[int]time.Time)
+}
+
+// NeedLeaderElection implements the manager.LeaderElectionRunnable interface.
+// The Coordinator runs on every replica.
+func (c *Coordinator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the Leases and rebalances the shards until the context is done.
+// It implements the manager.Runnable interface.
+func (c *Coordinator) Start(ctx context.Context) error {
+	c.logger.Info("starting shard coordinator",
+		"identity", c.cfg.Identity, "shards", c.cfg.Shards, "namespace", c.cfg.Namespace,
+	)
+
+	ticker := time.NewTicker(c.cfg.RenewPeriod)
+	defer ticker.Stop()
+	for {
+		if err := c.sync(ctx); err != nil {
+			c.logger.Error(err, "failed to synchronize shard ownership")
+		}
+
+		select {
+		case <-ctx.Done():
+			// Release the owned shards so that other replicas can take them over
+			// without waiting for the Leases to expire.
+			releaseCtx, cancel := context.WithTimeout(context.Background(), c.cfg.RenewPeriod)
+			defer cancel()
+			return c.releaseAll(releaseCtx)
+		case <-ticker.C:
+		}
+	}
+}
+
+// subscribe registers a function called with the shards newly acquired by
+// this replica. It returns a function removing the subscription.
+func (c *Coordinator) subscribe(f func(acquired map[int]struct{})) func() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	id := c.nextSubID
+	c.nextSubID++
+	c.subscribers[id] = f
+	return func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		delete(c.subscribers, id)
+	}
+}
+
+// sync renews this replica's member Lease, releases the shards which are no
+// longer assigned to it and acquires the shards which are.
+//
+// Shards whose Lease cannot be proven to still be held by this replica are
+// dropped, i.e. when any of the Lease operations fails.
+func (c *Coordinator) sync(ctx context.Context) error {
+	// Leases are renewed with this time so it's taken before any request is sent
+	// to make sure that the shards are dropped before their Leases expire.
+	now := c.now()
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.RenewDeadline)
+	defer cancel()
+
+	if err := c.renewMemberLease(ctx, now); err != nil {
+		c.dropAll()
+		return fmt.Errorf("failed to renew member Lease: %w", err)
+	}
+
+	var leases coordinationv1.LeaseList
+	if err := c.client.List(ctx, &leases,
+		client.InNamespace(c.cfg.Namespace),
+		client.HasLabels{LeaseTypeLabel},
+	); err != nil {
+		c.dropAll()
+		return fmt.Errorf("failed to list sharding Leases: %w", err)
+	}
+
+	var (
+		members     = []string{c.cfg.Identity}
+		shardLeases = make(map[int]*coordinationv1.Lease)
+	)
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		switch lease.Labels[LeaseTypeLabel] {
+		case LeaseTypeMember:
+			if holder := lo.FromPtr(lease.Spec.HolderIdentity); holder != "" && !isLeaseExpired(lease, now) {
+				members = append(members, holder)
+			}
+		case LeaseTypeShard:
+			shard, err := shardFromLease(lease)
+			if err != nil || shard >= c.cfg.Shards {
+				continue
+			}
+			shardLeases[shard] = lease
+		}
+	}
+	desired := assignShards(lo.Uniq(members), c.cfg.Identity, c.cfg.Shards)
+
+	// Stop processing the shards which are no longer assigned to this replica
+	// before releasing their Leases.
+	var toRelease []int
+	c.lock.Lock()
+	for shard := range c.owned {
+		if _, ok := desired[shard]; !ok {
+			delete(c.owned, shard)
+			toRelease = append(toRelease, shard)
+		}
+	}
+	c.lock.Unlock()
+
+	var errs []error
+	for _, shard := range toRelease {
+		if lease, ok := shardLeases[shard]; ok {
+			if err := c.releaseLease(ctx, lease); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(toRelease) > 0 {
+		c.logger.Info("released shards", "shards", toRelease)
+	}
+
+	acquired := make(map[int]struct{})
+	for shard := range desired {
+		held, err := c.acquireShardLease(ctx, shard, shardLeases[shard], now)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		c.lock.Lock()
+		alreadyOwned := c.ownsShardLocked(shard, c.now())
+		if held {
+			c.owned[shard] = now
+		} else {
+			delete(c.owned, shard)
+		}
+		c.lock.Unlock()
+		if held && !alreadyOwned {
+			acquired[shard] = struct{}{}
+		}
+	}
+
+	if len(acquired) > 0 {
+		c.logger.Info("acquired shards", "shards", lo.Keys(acquired), "owned", c.OwnedShards())
+		c.lock.RLock()
+		subscribers := lo.Values(c.subscribers)
+		c.lock.RUnlock()
+		for _, f := range subscribers {
+			f(acquired)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Coordinator) renewMemberLease(ctx context.Context, now time.Time) error {
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.cfg.Namespace,
+			Name:      fmt.Sprintf("%s-member-%s", leaseNamePrefix, c.cfg.Identity),
+		},
+	}
+	err := c.client.Get(ctx, client.ObjectKeyFromObject(lease), lease)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	c.setHolder(lease, LeaseTypeMember, now)
+	if k8serrors.IsNotFound(err) {
+		return c.client.Create(ctx, lease)
+	}
+	return c.client.Update(ctx, lease)
+}
+
+// acquireShardLease makes sure that this replica holds the Lease of the provided
+// shard. It returns false if the Lease is held by another replica and has not expired.
+func (c *Coordinator) acquireShardLease(ctx context.Context, shard int, lease *coordinationv1.Lease, now time.Time) (bool, error) {
+	if lease == nil {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: c.cfg.Namespace,
+				Name:      fmt.Sprintf("%s-shard-%d", leaseNamePrefix, shard),
+			},
+		}
+		c.setHolder(lease, LeaseTypeShard, now)
+		if err := c.client.Create(ctx, lease); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to create Lease for shard %d: %w", shard, err)
+		}
+		return true, nil
+	}
+
+	holder := lo.FromPtr(lease.Spec.HolderIdentity)
+	if holder != "" && holder != c.cfg.Identity && !isLeaseExpired(lease, now) {
+		return false, nil
+	}
+	c.setHolder(lease, LeaseTypeShard, now)
+	if err := c.client.Update(ctx, lease); err != nil {
+		if k8serrors.IsConflict(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update Lease for shard %d: %w", shard, err)
+	}
+	return true, nil
+}
+
+func (c *Coordinator) releaseLease(ctx context.Context, lease *coordinationv1.Lease) error {
+	if lo.FromPtr(lease.Spec.HolderIdentity) != c.cfg.Identity {
+		return nil
+	}
+	lease.Spec.HolderIdentity = nil
+	if err := c.client.Update(ctx, lease); err != nil && !k8serrors.IsConflict(err) && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to release Lease %s: %w", lease.Name, err)
+	}
+	return nil
+}
+
+// releaseAll releases all the Leases held by this replica.
+func (c *Coordinator) releaseAll(ctx context.Context) error {
+	c.dropAll()
+
+	var leases coordinationv1.LeaseList
+	if err := c.client.List(ctx, &leases,
+		client.InNamespace(c.cfg.Namespace),
+		client.HasLabels{LeaseTypeLabel},
+	); err != nil {
+		return fmt.Errorf("failed to list sharding Leases: %w", err)
+	}
+	var errs []error
+	for i := range leases.Items {
+		errs = append(errs, c.releaseLease(ctx, &leases.Items[i]))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Coordinator) setHolder(lease *coordinationv1.Lease, leaseType string, now time.Time) {
+	if lease.Labels == nil {
+		lease.Labels = make(map[string]string)
+	}
+	lease.Labels[LeaseTypeLabel] = leaseType
+	if lo.FromPtr(lease.Spec.HolderIdentity) != c.cfg.Identity {
+		lease.Spec.HolderIdentity = lo.ToPtr(c.cfg.Identity)
+		lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+		lease.Spec.LeaseTransitions = lo.ToPtr(lo.FromPtr(lease.Spec.LeaseTransitions) + 1)
+	}
+	lease.Spec.LeaseDurationSeconds = lo.ToPtr(int32(c.cfg.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+}
+
+func isLeaseExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(now)
+}
+
+func shardFromLease(lease *coordinationv1.Lease) (int, error) {
+	var shard int
+	_, err := fmt.Sscanf(lease.Name, leaseNamePrefix+"-shard-%d", &shard)
+	if err != nil {
+		return 0, err
+	}
+	if lease.Name != fmt.Sprintf("%s-shard-%s", leaseNamePrefix, strconv.Itoa(shard)) {
+		return 0, fmt.Errorf("unexpected shard Lease name %s", lease.Name)
+	}
+	return shard, nil
+}
+
+// assignShards returns the shards assigned to the provided identity when
+// distributing the shards in a round-robin fashion between the sorted members.
+func assignShards(members []string, identity string, shards int) map[int]struct{} {
+	members = slices.Clone(members)
+	sort.Strings(members)
+	idx := slices.Index(members, identity)
+	assigned := make(map[int]struct{})
+	if idx < 0 {
+		return assigned
+	}
+	for shard := idx; shard < shards; shard += len(members) {
+		assigned[shard] = struct{}{}
+	}
+	return assigned
+}
@@ -0,0 +1,238 @@
+package sharding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+)
+
+func TestAssignShards(t *testing.T) {
+	testCases := []struct {
+		name     string
+		members  []string
+		identity string
+		shards   int
+		expected []int
+	}{
+		{
+			name:     "single member owns all shards",
+			members:  []string{"a"},
+			identity: "a",
+			shards:   3,
+			expected: []int{0, 1, 2},
+		},
+		{
+			name:     "shards are distributed in a round-robin fashion between sorted members",
+			members:  []string{"b", "a"},
+			identity: "b",
+			shards:   5,
+			expected: []int{1, 3},
+		},
+		{
+			name:     "more members than shards",
+			members:  []string{"a", "b", "c"},
+			identity: "c",
+			shards:   2,
+			expected: []int{},
+		},
+		{
+			name:     "unknown identity",
+			members:  []string{"a"},
+			identity: "b",
+			shards:   2,
+			expected: []int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assigned := assignShards(tc.members, tc.identity, tc.shards)
+			require.Len(t, assigned, len(tc.expected))
+			for _, shard := range tc.expected {
+				require.Contains(t, assigned, shard)
+			}
+		})
+	}
+}
+
+func TestShardForNamespace(t *testing.T) {
+	for _, ns := range []string{"default", "kong", "team-a", "team-b"} {
+		shard := ShardForNamespace(ns, 4)
+		require.GreaterOrEqual(t, shard, 0)
+		require.Less(t, shard, 4)
+		require.Equal(t, shard, ShardForNamespace(ns, 4), "shard has to be stable")
+	}
+}
+
+func TestCoordinatorRebalancing(t *testing.T) {
+	const shards = 4
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		Build()
+
+	now := time.Now()
+	newCoordinator := func(identity string) *Coordinator {
+		c, err := NewCoordinator(cl, logr.Discard(), Config{
+			Shards:    shards,
+			Namespace: "kong-system",
+			Identity:  identity,
+		})
+		require.NoError(t, err)
+		c.now = func() time.Time { return now }
+		return c
+	}
+
+	a := newCoordinator("a")
+	var acquiredByA []int
+	a.subscribe(func(acquired map[int]struct{}) {
+		for shard := range acquired {
+			acquiredByA = append(acquiredByA, shard)
+		}
+	})
+
+	ctx := t.Context()
+	t.Log("single replica acquires all the shards")
+	require.NoError(t, a.sync(ctx))
+	require.Equal(t, []int{0, 1, 2, 3}, a.OwnedShards())
+	require.ElementsMatch(t, []int{0, 1, 2, 3}, acquiredByA)
+	for ns := range map[string]struct{}{"default": {}, "kong": {}, "": {}} {
+		require.True(t, a.OwnsNamespace(ns))
+	}
+
+	t.Log("new replica joins and waits for the shards to be released")
+	b := newCoordinator("b")
+	require.NoError(t, b.sync(ctx))
+	require.Empty(t, b.OwnedShards())
+
+	t.Log("first replica releases the shards assigned to the new one")
+	require.NoError(t, a.sync(ctx))
+	require.Equal(t, []int{0, 2}, a.OwnedShards())
+
+	require.NoError(t, b.sync(ctx))
+	require.Equal(t, []int{1, 3}, b.OwnedShards())
+
+	var shardLease coordinationv1.Lease
+	require.NoError(t, cl.Get(ctx, client.ObjectKey{Namespace: "kong-system", Name: "gateway-operator-shard-1"}, &shardLease))
+	require.Equal(t, "b", *shardLease.Spec.HolderIdentity)
+
+	t.Log("shards are not processed anymore once their Leases were not renewed within the renew deadline")
+	now = now.Add(DefaultRenewDeadline)
+	require.Empty(t, a.OwnedShards())
+	require.Empty(t, b.OwnedShards())
+
+	t.Log("second replica leaves without releasing the shards, the first one takes them over once Leases expire")
+	now = now.Add(DefaultLeaseDuration - DefaultRenewDeadline + time.Second)
+	acquiredByA = nil
+	require.NoError(t, a.sync(ctx))
+	require.Equal(t, []int{0, 1, 2, 3}, a.OwnedShards())
+	require.ElementsMatch(t, []int{0, 1, 2, 3}, acquiredByA)
+}
+
+func TestCoordinatorDropsShardsWhenLeasesCannotBeRenewed(t *testing.T) {
+	var (
+		failMemberLease bool
+		failShardLease  bool
+	)
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				switch {
+				case failMemberLease && obj.GetLabels()[LeaseTypeLabel] == LeaseTypeMember:
+					return errors.New("member Lease update failed")
+				case failShardLease && obj.GetName() == "gateway-operator-shard-1":
+					return errors.New("shard Lease update failed")
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	c, err := NewCoordinator(cl, logr.Discard(), Config{
+		Shards:    2,
+		Namespace: "kong-system",
+		Identity:  "a",
+	})
+	require.NoError(t, err)
+
+	ctx := t.Context()
+	require.NoError(t, c.sync(ctx))
+	require.Equal(t, []int{0, 1}, c.OwnedShards())
+
+	t.Log("shard whose Lease cannot be renewed is dropped")
+	failShardLease = true
+	require.Error(t, c.sync(ctx))
+	require.Equal(t, []int{0}, c.OwnedShards())
+
+	t.Log("all shards are dropped when the member Lease cannot be renewed")
+	failShardLease, failMemberLease = false, true
+	require.Error(t, c.sync(ctx))
+	require.Empty(t, c.OwnedShards())
+	require.False(t, c.OwnsNamespace("default"))
+
+	t.Log("shards are owned again once the Leases are renewed")
+	failMemberLease = false
+	require.NoError(t, c.sync(ctx))
+	require.Equal(t, []int{0, 1}, c.OwnedShards())
+}
+
+func TestCoordinatorReleaseAll(t *testing.T) {
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		Build()
+	c, err := NewCoordinator(cl, logr.Discard(), Config{
+		Shards:    2,
+		Namespace: "kong-system",
+		Identity:  "a",
+	})
+	require.NoError(t, err)
+
+	ctx := t.Context()
+	require.NoError(t, c.sync(ctx))
+	require.Len(t, c.OwnedShards(), 2)
+
+	require.NoError(t, c.releaseAll(ctx))
+	require.Empty(t, c.OwnedShards())
+
+	var leases coordinationv1.LeaseList
+	require.NoError(t, cl.List(ctx, &leases))
+	require.Len(t, leases.Items, 3)
+	for _, lease := range leases.Items {
+		require.Nil(t, lease.Spec.HolderIdentity, lease.Name)
+	}
+}
+
+func TestNewCoordinatorValidation(t *testing.T) {
+	_, err := NewCoordinator(nil, logr.Discard(), Config{Namespace: "ns", Identity: "a"})
+	require.Error(t, err)
+	_, err = NewCoordinator(nil, logr.Discard(), Config{Shards: 1, Identity: "a"})
+	require.Error(t, err)
+	_, err = NewCoordinator(nil, logr.Discard(), Config{Shards: 1, Namespace: "ns"})
+	require.Error(t, err)
+	_, err = NewCoordinator(nil, logr.Discard(), Config{
+		Shards: 1, Namespace: "ns", Identity: "a",
+		LeaseDuration: 3 * time.Second, RenewDeadline: 2 * time.Second, RenewPeriod: 2 * time.Second,
+	})
+	require.Error(t, err)
+	_, err = NewCoordinator(nil, logr.Discard(), Config{
+		Shards: 1, Namespace: "ns", Identity: "a",
+		LeaseDuration: 3 * time.Second, RenewDeadline: 3 * time.Second, RenewPeriod: time.Second,
+	})
+	require.Error(t, err)
+
+	c, err := NewCoordinator(nil, logr.Discard(), Config{Shards: 1, Namespace: "ns", Identity: "a"})
+	require.NoError(t, err)
+	require.Equal(t, DefaultLeaseDuration, c.cfg.LeaseDuration)
+	require.Equal(t, DefaultRenewDeadline, c.cfg.RenewDeadline)
+	require.Equal(t, DefaultRenewPeriod, c.cfg.RenewPeriod)
+	require.False(t, c.NeedLeaderElection())
+}
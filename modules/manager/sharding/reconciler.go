@@ -0,0 +1,62 @@
+package sharding
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type coordinatorContextKey struct{}
+
+// IntoContext returns a copy of the provided context carrying the Coordinator.
+// It is used to pass the Coordinator to the controllers' SetupWithManager.
+func IntoContext(ctx context.Context, coordinator *Coordinator) context.Context {
+	return context.WithValue(ctx, coordinatorContextKey{}, coordinator)
+}
+
+// FromContext returns the Coordinator carried by the provided context, if any.
+func FromContext(ctx context.Context) *Coordinator {
+	c, _ := ctx.Value(coordinatorContextKey{}).(*Coordinator)
+	return c
+}
+
+// NewReconciler wraps the provided reconciler so that it only reconciles
+// the requests for objects from the shards owned by the Coordinator carried by
+// the provided context. The reconciler is returned as is when sharding is disabled.
+//
+// Filtering the events delivered by the cache (see NewCacheFunc) is not enough
+// to guarantee that: requests are also enqueued by map funcs of cluster-scoped
+// objects (e.g. GatewayClasses) which are delivered to every replica, and by
+// requeues of requests for objects from shards released in the meantime.
+// Requests for cluster-scoped objects are always reconciled.
+func NewReconciler(ctx context.Context, r reconcile.Reconciler) reconcile.Reconciler {
+	coordinator := FromContext(ctx)
+	if coordinator == nil {
+		return r
+	}
+	return &reconciler{
+		coordinator: coordinator,
+		reconciler:  r,
+	}
+}
+
+type reconciler struct {
+	coordinator *Coordinator
+	reconciler  reconcile.Reconciler
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	if !r.coordinator.OwnsNamespace(req.Namespace) {
+		// The request is dropped, the object will be reconciled by the replica
+		// acquiring its shard.
+		return ctrl.Result{}, nil
+	}
+	res, err := r.reconciler.Reconcile(ctx, req)
+	if !r.coordinator.OwnsNamespace(req.Namespace) {
+		// The shard was released during the reconciliation, do not requeue.
+		return ctrl.Result{}, nil
+	}
+	return res, err
+}
@@ -0,0 +1,70 @@
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconciler(t *testing.T) {
+	const shards = 2
+	nsInShard := map[int]string{}
+	for i := 0; len(nsInShard) < shards; i++ {
+		ns := "ns-" + string(rune('a'+i))
+		if _, ok := nsInShard[ShardForNamespace(ns, shards)]; !ok {
+			nsInShard[ShardForNamespace(ns, shards)] = ns
+		}
+	}
+
+	var reconciled []string
+	inner := reconcile.Func(func(_ context.Context, req ctrl.Request) (ctrl.Result, error) {
+		reconciled = append(reconciled, req.String())
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	})
+
+	t.Log("reconciler is not wrapped when sharding is disabled")
+	require.NotNil(t, NewReconciler(t.Context(), inner))
+	_, wrapped := NewReconciler(t.Context(), inner).(*reconciler)
+	require.False(t, wrapped)
+
+	coordinator, err := NewCoordinator(nil, logr.Discard(), Config{
+		Shards:    shards,
+		Namespace: "kong-system",
+		Identity:  "a",
+	})
+	require.NoError(t, err)
+	coordinator.owned[0] = time.Now()
+	r := NewReconciler(IntoContext(t.Context(), coordinator), inner)
+
+	owned := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: nsInShard[0], Name: "owned"}}
+	notOwned := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: nsInShard[1], Name: "not-owned"}}
+	clusterScoped := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster-scoped"}}
+
+	res, err := r.Reconcile(t.Context(), owned)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, res.RequeueAfter)
+
+	res, err = r.Reconcile(t.Context(), notOwned)
+	require.NoError(t, err)
+	require.Zero(t, res)
+
+	res, err = r.Reconcile(t.Context(), clusterScoped)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, res.RequeueAfter)
+
+	require.Equal(t, []string{owned.String(), clusterScoped.String()}, reconciled)
+
+	t.Log("requeues of released shards are dropped")
+	delete(coordinator.owned, 0)
+	reconciled = nil
+	res, err = r.Reconcile(t.Context(), owned)
+	require.NoError(t, err)
+	require.Zero(t, res)
+	require.Empty(t, reconciled)
+}
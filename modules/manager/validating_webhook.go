@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;create;patch
+
+const (
+	// validatingWebhookPath is the path the validating webhook is served at.
+	validatingWebhookPath = "/validate"
+
+	// validatingWebhookTimeoutSeconds is the time the API server waits for the
+	// validating webhook before ignoring it.
+	validatingWebhookTimeoutSeconds = 5
+)
+
+// validatingWebhookConfigurationName returns the name of the
+// ValidatingWebhookConfiguration of the webhooks served through the provided
+// Service. It's unique for every operator installation.
+func (w *webhookServer) validatingWebhookConfigurationName() string {
+	return fmt.Sprintf("%s.%s.validation.gateway-operator.konghq.com", w.service.Name, w.service.Namespace)
+}
+
+// configureValidatingWebhook creates or updates the ValidatingWebhookConfiguration
+// through which the API server sends the AIGateways to the validating webhook.
+//
+// The AIGateways are validated by their controller as well, the webhook
+// rejects invalid AIGateways early. Its failures are thus ignored so that
+// AIGateways can be managed while the operator is unavailable.
+func (w *webhookServer) configureValidatingWebhook(ctx context.Context, caBundle []byte) error {
+	webhooks := []admissionregistrationv1.ValidatingWebhook{
+		{
+			Name: "aigateways.validation.gateway-operator.konghq.com",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Namespace: w.service.Namespace,
+					Name:      w.service.Name,
+					Path:      ptr.To(validatingWebhookPath),
+				},
+				CABundle: caBundle,
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{
+				{
+					Operations: []admissionregistrationv1.OperationType{
+						admissionregistrationv1.Create,
+						admissionregistrationv1.Update,
+					},
+					Rule: admissionregistrationv1.Rule{
+						APIGroups:   []string{operatorv1alpha1.SchemeGroupVersion.Group},
+						APIVersions: []string{operatorv1alpha1.SchemeGroupVersion.Version},
+						Resources:   []string{"aigateways"},
+					},
+				},
+			},
+			FailurePolicy:           ptr.To(admissionregistrationv1.Ignore),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+			TimeoutSeconds:          ptr.To[int32](validatingWebhookTimeoutSeconds),
+		},
+	}
+
+	var vwc admissionregistrationv1.ValidatingWebhookConfiguration
+	err := w.reader.Get(ctx, client.ObjectKey{Name: w.validatingWebhookConfigurationName()}, &vwc)
+	switch {
+	case k8serrors.IsNotFound(err):
+		vwc = admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: w.validatingWebhookConfigurationName()},
+			Webhooks:   webhooks,
+		}
+		if err := w.client.Create(ctx, &vwc); err != nil {
+			return fmt.Errorf("failed to create ValidatingWebhookConfiguration %s: %w", vwc.Name, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get ValidatingWebhookConfiguration %s: %w", w.validatingWebhookConfigurationName(), err)
+	default:
+		old := vwc.DeepCopy()
+		vwc.Webhooks = webhooks
+		if err := w.client.Patch(ctx, &vwc, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed to configure ValidatingWebhookConfiguration %s: %w", vwc.Name, err)
+		}
+	}
+	w.logger.Info("configured validating webhook", "validatingwebhookconfiguration", vwc.Name)
+	return nil
+}
@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+)
+
+func TestWebhookServerConfigureValidatingWebhook(t *testing.T) {
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		Build()
+	w := &webhookServer{
+		logger:  logr.Discard(),
+		client:  cl,
+		reader:  cl,
+		service: types.NamespacedName{Namespace: "kong-system", Name: "webhook"},
+	}
+	name := types.NamespacedName{Name: "webhook.kong-system.validation.gateway-operator.konghq.com"}
+
+	t.Log("the ValidatingWebhookConfiguration is created")
+	require.NoError(t, w.configureValidatingWebhook(t.Context(), []byte("ca")))
+	var vwc admissionregistrationv1.ValidatingWebhookConfiguration
+	require.NoError(t, cl.Get(t.Context(), name, &vwc))
+	require.Len(t, vwc.Webhooks, 1)
+	webhook := vwc.Webhooks[0]
+	require.Equal(t, []byte("ca"), webhook.ClientConfig.CABundle)
+	require.Equal(t, "kong-system", webhook.ClientConfig.Service.Namespace)
+	require.Equal(t, "webhook", webhook.ClientConfig.Service.Name)
+	require.Equal(t, validatingWebhookPath, *webhook.ClientConfig.Service.Path)
+	require.Equal(t, []string{"aigateways"}, webhook.Rules[0].Resources)
+	require.Equal(t, admissionregistrationv1.Ignore, *webhook.FailurePolicy)
+
+	t.Log("the CA bundle of an existing ValidatingWebhookConfiguration is updated")
+	require.NoError(t, w.configureValidatingWebhook(t.Context(), []byte("new-ca")))
+	require.NoError(t, cl.Get(t.Context(), name, &vwc))
+	require.Len(t, vwc.Webhooks, 1)
+	require.Equal(t, []byte("new-ca"), vwc.Webhooks[0].ClientConfig.CABundle)
+}
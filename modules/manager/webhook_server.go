@@ -0,0 +1,292 @@
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kong/gateway-operator/controller/pkg/secrets"
+	"github.com/kong/gateway-operator/modules/conversion"
+)
+
+// The conversion webhook is configured only for the CRDs of the kinds with
+// conversions registered in conversion.NewDefaultRegistry. Patching CRDs is
+// limited to them.
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=patch,resourceNames=controlplanes.gateway-operator.konghq.com;dataplanes.gateway-operator.konghq.com;gatewayconfigurations.gateway-operator.konghq.com
+
+const (
+	// DefaultConversionWebhookPort is the default port the conversion and the
+	// validating webhooks listen on.
+	DefaultConversionWebhookPort = 9443
+	// DefaultConversionWebhookServiceName is the default name of the conversion
+	// and the validating webhooks' Service.
+	DefaultConversionWebhookServiceName = "gateway-operator-conversion-webhook"
+
+	webhookCAPollInterval  = time.Second
+	webhookShutdownTimeout = 10 * time.Second
+)
+
+var crdGVK = schema.GroupVersionKind{
+	Group:   "apiextensions.k8s.io",
+	Version: "v1",
+	Kind:    "CustomResourceDefinition",
+}
+
+// webhookServer serves the conversion and the validating webhooks with a
+// certificate issued by the cluster CA. It configures the operator's CRDs
+// served at multiple versions to use the conversion webhook and registers the
+// validating webhook's ValidatingWebhookConfiguration.
+type webhookServer struct {
+	logger     logr.Logger
+	client     client.Client
+	reader     client.Reader
+	conversion http.Handler
+	validation http.Handler
+	registry   *conversion.Registry
+	port       int
+	service    types.NamespacedName
+	caSecret   types.NamespacedName
+	keyConfig  secrets.KeyConfig
+}
+
+var _ manager.LeaderElectionRunnable = &webhookServer{}
+
+// newWebhookServer returns the webhookServer serving the webhooks enabled in
+// the provided Config. validation handles the validating webhook's requests.
+func newWebhookServer(mgr manager.Manager, cfg Config, keyConfig secrets.KeyConfig, validation http.Handler) (*webhookServer, error) {
+	w := &webhookServer{
+		logger: ctrl.Log.WithName("webhook_server"),
+		client: mgr.GetClient(),
+		reader: mgr.GetAPIReader(),
+		port:   cfg.ConversionWebhookPort,
+		service: types.NamespacedName{
+			Namespace: cfg.ControllerNamespace,
+			Name:      cfg.ConversionWebhookServiceName,
+		},
+		caSecret: types.NamespacedName{
+			Namespace: cfg.ClusterCASecretNamespace,
+			Name:      cfg.ClusterCASecretName,
+		},
+		keyConfig: keyConfig,
+	}
+	if cfg.ConversionWebhookEnabled {
+		registry, err := conversion.NewDefaultRegistry(mgr.GetScheme())
+		if err != nil {
+			return nil, err
+		}
+		w.registry = registry
+		w.conversion = conversion.NewHandler(registry, w.logger.WithName("conversion"))
+	}
+	if cfg.ValidatingWebhookEnabled {
+		w.validation = validation
+	}
+	return w, nil
+}
+
+// NeedLeaderElection returns false as all the replicas behind the webhooks'
+// Service have to serve them.
+func (w *webhookServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start issues the webhooks' serving certificate, starts serving the webhooks
+// and configures the API server to use them. It blocks until the context is
+// cancelled.
+func (w *webhookServer) Start(ctx context.Context) error {
+	caCert, cert, err := w.issueServingCertificate(ctx)
+	if err != nil {
+		return err
+	}
+
+	listener, err := tls.Listen("tcp", net.JoinHostPort("", strconv.Itoa(w.port)), &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %d: %w", w.port, err)
+	}
+	mux := http.NewServeMux()
+	if w.conversion != nil {
+		mux.Handle(conversion.Path, w.conversion)
+	}
+	if w.validation != nil {
+		mux.Handle(validatingWebhookPath, w.validation)
+	}
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	if w.conversion != nil {
+		if err := w.configureCRDs(ctx, caCert); err != nil {
+			_ = server.Close()
+			return err
+		}
+	}
+	if w.validation != nil {
+		if err := w.configureValidatingWebhook(ctx, caCert); err != nil {
+			_ = server.Close()
+			return err
+		}
+	}
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("webhook server failed: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// issueServingCertificate issues the webhooks' serving certificate signed by
+// the cluster CA for the webhooks' Service. It waits until the cluster CA
+// Secret is created. It returns the PEM encoded CA certificate and the serving
+// certificate.
+func (w *webhookServer) issueServingCertificate(ctx context.Context) ([]byte, tls.Certificate, error) {
+	var (
+		certPEM, keyPEM, caPEM []byte
+		lastErr                error
+	)
+	dnsNames := []string{
+		fmt.Sprintf("%s.%s.svc", w.service.Name, w.service.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", w.service.Name, w.service.Namespace),
+	}
+	err := wait.PollUntilContextCancel(ctx, webhookCAPollInterval, true, func(ctx context.Context) (bool, error) {
+		certPEM, keyPEM, caPEM, lastErr = secrets.IssueCertificate(
+			ctx, w.reader, w.caSecret, dnsNames,
+			[]certificatesv1.KeyUsage{
+				certificatesv1.UsageKeyEncipherment,
+				certificatesv1.UsageDigitalSignature,
+				certificatesv1.UsageServerAuth,
+			},
+			w.keyConfig,
+		)
+		if lastErr != nil {
+			w.logger.V(1).Info("waiting for the cluster CA to issue the serving certificate", "error", lastErr.Error())
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, tls.Certificate{}, fmt.Errorf("failed to issue webhook serving certificate: %w", errors.Join(err, lastErr))
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, tls.Certificate{}, fmt.Errorf("invalid webhook serving certificate: %w", err)
+	}
+	return caPEM, cert, nil
+}
+
+// configureCRDs configures the CRDs of the kinds registered in the webhook's
+// conversion registry, which are served at multiple versions, to use the
+// conversion webhook.
+func (w *webhookServer) configureCRDs(ctx context.Context, caBundle []byte) error {
+	var crds unstructured.UnstructuredList
+	crds.SetGroupVersionKind(crdGVK.GroupVersion().WithKind(crdGVK.Kind + "List"))
+	if err := w.reader.List(ctx, &crds); err != nil {
+		return fmt.Errorf("failed to list CustomResourceDefinitions: %w", err)
+	}
+
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		gk := schema.GroupKind{Group: group, Kind: kind}
+		ok, err := w.canConvertAllVersions(crd, gk)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		old := crd.DeepCopy()
+		if err := unstructured.SetNestedField(crd.Object, w.crdConversion(caBundle), "spec", "conversion"); err != nil {
+			return err
+		}
+		if err := w.client.Patch(ctx, crd, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed to configure conversion webhook of CustomResourceDefinition %s: %w", crd.GetName(), err)
+		}
+		w.logger.Info("configured conversion webhook", "crd", crd.GetName())
+	}
+	return nil
+}
+
+// canConvertAllVersions returns true if the provided CRD is served at multiple
+// versions and the webhook can convert between all of them. It returns false
+// for CRDs of kinds not registered in the webhook's conversion registry.
+func (w *webhookServer) canConvertAllVersions(crd *unstructured.Unstructured, gk schema.GroupKind) (bool, error) {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return false, fmt.Errorf("invalid versions of CustomResourceDefinition %s: %w", crd.GetName(), err)
+	}
+	if len(versions) < 2 {
+		return false, nil
+	}
+	registeredVersions := w.registry.Versions(gk)
+	if registeredVersions == nil {
+		return false, nil
+	}
+	convertible := make(map[string]struct{})
+	for _, v := range registeredVersions {
+		convertible[v] = struct{}{}
+	}
+	for _, v := range versions {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return false, nil
+		}
+		name, _, _ := unstructured.NestedString(m, "name")
+		if _, ok := convertible[name]; !ok {
+			w.logger.Info("CustomResourceDefinition has versions without registered conversions, skipping",
+				"crd", crd.GetName(), "version", name,
+			)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (w *webhookServer) crdConversion(caBundle []byte) map[string]any {
+	return map[string]any{
+		"strategy": "Webhook",
+		"webhook": map[string]any{
+			"conversionReviewVersions": []any{"v1"},
+			"clientConfig": map[string]any{
+				"caBundle": base64.StdEncoding.EncodeToString(caBundle),
+				"service": map[string]any{
+					"namespace": w.service.Namespace,
+					"name":      w.service.Name,
+					"path":      conversion.Path,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/modules/conversion"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+)
+
+func TestConversionWebhookConfigureCRDs(t *testing.T) {
+	crd := func(name, group, kind string, versions ...string) *unstructured.Unstructured {
+		vs := make([]any, 0, len(versions))
+		for _, v := range versions {
+			vs = append(vs, map[string]any{"name": v, "served": true, "storage": v == versions[0]})
+		}
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{
+				"group":    group,
+				"names":    map[string]any{"kind": kind},
+				"versions": vs,
+			},
+		}}
+		obj.SetGroupVersionKind(crdGVK)
+		obj.SetName(name)
+		return obj
+	}
+
+	registry := conversion.NewRegistry()
+	require.NoError(t, registry.Register(
+		schema.GroupKind{Group: "example.konghq.com", Kind: "Widget"}, "v2",
+		conversion.Spoke{Version: "v1"},
+	))
+	require.NoError(t, registry.Register(
+		schema.GroupKind{Group: "example.konghq.com", Kind: "Gadget"}, "v2",
+	))
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithObjects(
+			crd("widgets.example.konghq.com", "example.konghq.com", "Widget", "v2", "v1"),
+			crd("gadgets.example.konghq.com", "example.konghq.com", "Gadget", "v2", "v1"),
+			crd("singles.example.konghq.com", "example.konghq.com", "Single", "v1"),
+			crd("others.other.konghq.com", "other.konghq.com", "Other", "v2", "v1"),
+		).
+		Build()
+
+	w := &webhookServer{
+		logger:   logr.Discard(),
+		client:   cl,
+		reader:   cl,
+		registry: registry,
+		service:  types.NamespacedName{Namespace: "kong-system", Name: "webhook"},
+	}
+	require.NoError(t, w.configureCRDs(t.Context(), []byte("ca")))
+
+	conversionOf := func(name string) map[string]any {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(crdGVK)
+		require.NoError(t, cl.Get(t.Context(), types.NamespacedName{Name: name}, obj))
+		c, _, err := unstructured.NestedMap(obj.Object, "spec", "conversion")
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Log("CRDs with all versions registered use the webhook")
+	c := conversionOf("widgets.example.konghq.com")
+	require.Equal(t, "Webhook", c["strategy"])
+	caBundle, _, _ := unstructured.NestedString(c, "webhook", "clientConfig", "caBundle")
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("ca")), caBundle)
+	service, _, _ := unstructured.NestedStringMap(c, "webhook", "clientConfig", "service")
+	require.Equal(t, map[string]string{"namespace": "kong-system", "name": "webhook", "path": conversion.Path}, service)
+
+	t.Log("CRDs with unregistered versions, served at a single version or of unregistered kinds are not changed")
+	require.Nil(t, conversionOf("gadgets.example.konghq.com"))
+	require.Nil(t, conversionOf("singles.example.konghq.com"))
+	require.Nil(t, conversionOf("others.other.konghq.com"))
+}
+
+func TestConversionWebhookCRDsHavePatchPermissions(t *testing.T) {
+	registry, err := conversion.NewDefaultRegistry(scheme.Get())
+	require.NoError(t, err)
+
+	// Keep in sync with the resourceNames of the kubebuilder RBAC marker in webhook_server.go.
+	permitted := []string{
+		"controlplanes.gateway-operator.konghq.com",
+		"dataplanes.gateway-operator.konghq.com",
+		"gatewayconfigurations.gateway-operator.konghq.com",
+	}
+	var converted []string
+	for _, gk := range registry.GroupKinds() {
+		if len(registry.Versions(gk)) > 1 {
+			converted = append(converted, strings.ToLower(gk.Kind)+"s."+gk.Group)
+		}
+	}
+	require.ElementsMatch(t, permitted, converted)
+}
@@ -0,0 +1,195 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/controlplane"
+	"github.com/kong/gateway-operator/controller/dataplane"
+	"github.com/kong/gateway-operator/controller/gateway"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// Config is the configuration of the plan. The generator related options
+// should match the configuration of the running operator.
+type Config struct {
+	// KubeconfigPath is the path to the kubeconfig file. If empty, the default
+	// controller-runtime config discovery is used.
+	KubeconfigPath string
+	// FilePath is the path to the manifest of the proposed DataPlane,
+	// ControlPlane or GatewayConfiguration.
+	FilePath string
+	// DefaultDataPlaneImage is the image used for DataPlanes which do not
+	// specify one.
+	DefaultDataPlaneImage string
+	// DevelopmentMode disables image version validation.
+	DevelopmentMode bool
+	// EnforceConfig makes the plan compare Deployments even when the spec hash
+	// of their owner did not change.
+	EnforceConfig bool
+	// WatchNamespaces is the set of namespaces the operator is restricted to.
+	WatchNamespaces []string
+}
+
+// Run reads the proposed object from cfg.FilePath, plans the changes of the
+// resources it owns and writes the report to w.
+func Run(ctx context.Context, cfg Config, scheme *runtime.Scheme, w io.Writer) error {
+	data, err := os.ReadFile(cfg.FilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read proposed object: %w", err)
+	}
+	obj, _, err := serializer.NewCodecFactory(scheme).UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decode proposed object: %w", err)
+	}
+
+	restCfg, err := restConfig(cfg.KubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+	cl, err := client.New(restCfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	changes, err := Plan(ctx, cl, obj, cfg)
+	if err != nil {
+		return err
+	}
+	return WriteReport(w, changes)
+}
+
+func restConfig(kubeconfigPath string) (*rest.Config, error) {
+	if kubeconfigPath != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	}
+	return ctrl.GetConfig()
+}
+
+// Plan runs the operator's generators for the proposed DataPlane, ControlPlane
+// or GatewayConfiguration and returns how the live resources they own would
+// change. Nothing is changed in the cluster.
+func Plan(ctx context.Context, cl client.Client, proposed runtime.Object, cfg Config) ([]Change, error) {
+	switch obj := proposed.(type) {
+	case *operatorv1beta1.DataPlane:
+		var live operatorv1beta1.DataPlane
+		found, err := getLive(ctx, cl, obj, &live)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			live.Spec = obj.Spec
+			obj = &live
+		}
+		return planDataPlane(ctx, cl, obj, cfg)
+	case *operatorv1beta1.ControlPlane:
+		var live operatorv1beta1.ControlPlane
+		found, err := getLive(ctx, cl, obj, &live)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			live.Spec = obj.Spec
+			obj = &live
+		}
+		return planControlPlane(ctx, cl, obj, cfg)
+	case *operatorv1beta1.GatewayConfiguration:
+		defaultNamespace(obj)
+		gatewayPlans, err := gateway.PlanGatewayConfiguration(ctx, cl, obj, gateway.PlanOptions{
+			DefaultDataPlaneImage: cfg.DefaultDataPlaneImage,
+			DevelopmentMode:       cfg.DevelopmentMode,
+		})
+		if err != nil {
+			return nil, err
+		}
+		var changes []Change
+		for _, p := range gatewayPlans {
+			if p.DataPlane != nil {
+				c, err := planDataPlane(ctx, cl, p.DataPlane, cfg)
+				if err != nil {
+					return nil, err
+				}
+				changes = append(changes, c...)
+			}
+			if p.ControlPlane != nil {
+				c, err := planControlPlane(ctx, cl, p.ControlPlane, cfg)
+				if err != nil {
+					return nil, err
+				}
+				changes = append(changes, c...)
+			}
+		}
+		return changes, nil
+	default:
+		return nil, fmt.Errorf("unsupported object %T, supported kinds: DataPlane, ControlPlane, GatewayConfiguration", proposed)
+	}
+}
+
+func planDataPlane(ctx context.Context, cl client.Client, dp *operatorv1beta1.DataPlane, cfg Config) ([]Change, error) {
+	p, err := dataplane.PlanOwnedResources(ctx, cl, dp, dataplane.PlanOptions{
+		DefaultImage:    cfg.DefaultDataPlaneImage,
+		DevelopmentMode: cfg.DevelopmentMode,
+		EnforceConfig:   cfg.EnforceConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed planning DataPlane %s/%s: %w", dp.Namespace, dp.Name, err)
+	}
+	owner := fmt.Sprintf("DataPlane %s/%s", dp.Namespace, dp.Name)
+	deploymentChange, err := deploymentChange(owner, p.LiveDeployment, p.DesiredDeployment)
+	if err != nil {
+		return nil, err
+	}
+	serviceChange, err := serviceChange(owner, p.LiveIngressService, p.DesiredIngressService)
+	if err != nil {
+		return nil, err
+	}
+	return []Change{deploymentChange, serviceChange}, nil
+}
+
+func planControlPlane(ctx context.Context, cl client.Client, cp *operatorv1beta1.ControlPlane, cfg Config) ([]Change, error) {
+	p, err := controlplane.PlanOwnedResources(ctx, cl, cp, controlplane.PlanOptions{
+		DevelopmentMode: cfg.DevelopmentMode,
+		EnforceConfig:   cfg.EnforceConfig,
+		WatchNamespaces: cfg.WatchNamespaces,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed planning ControlPlane %s/%s: %w", cp.Namespace, cp.Name, err)
+	}
+	c, err := deploymentChange(fmt.Sprintf("ControlPlane %s/%s", cp.Namespace, cp.Name), p.LiveDeployment, p.DesiredDeployment)
+	if err != nil {
+		return nil, err
+	}
+	return []Change{c}, nil
+}
+
+// getLive gets the live version of the proposed object into live. The proposed
+// object's spec is then set on the live one by the caller so that the resources
+// it owns can be found through the live UID and status. It returns false when
+// the object does not exist yet.
+func getLive(ctx context.Context, cl client.Client, proposed client.Object, live client.Object) (bool, error) {
+	defaultNamespace(proposed)
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(proposed), live); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get live object %s: %w", client.ObjectKeyFromObject(proposed), err)
+	}
+	return true, nil
+}
+
+func defaultNamespace(obj client.Object) {
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace("default")
+	}
+}
@@ -0,0 +1,107 @@
+package plan
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	"github.com/kong/gateway-operator/pkg/consts"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestPlanDataPlane(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{
+		DefaultDataPlaneImage: consts.DefaultDataPlaneImage,
+		DevelopmentMode:       true,
+	}
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dp", UID: "dp-uid"},
+	}
+	dataplane.SetGroupVersionKind(operatorv1beta1.SchemeGroupVersion.WithKind("DataPlane"))
+
+	t.Log("resources of a DataPlane which does not exist yet would be created")
+	cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+	changes, err := Plan(ctx, cl, dataplane.DeepCopy(), cfg)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	for _, c := range changes {
+		require.Equal(t, ActionCreate, c.Action)
+		require.NotEmpty(t, c.Diff)
+	}
+
+	t.Log("live resources matching the proposed DataPlane would not change")
+	plannedDeployment, err := desiredObject[appsv1.Deployment](changes[0])
+	require.NoError(t, err)
+	plannedDeployment.Name = "dp-deployment"
+	plannedService, err := desiredObject[corev1.Service](changes[1])
+	require.NoError(t, err)
+	plannedService.Name = "dp-ingress"
+	cl = fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(dataplane, plannedDeployment, plannedService).
+		Build()
+	changes, err = Plan(ctx, cl, dataplane.DeepCopy(), cfg)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	for _, c := range changes {
+		require.Equal(t, ActionNone, c.Action, c.Diff)
+	}
+
+	t.Log("pod template and Service type changes are flagged")
+	proposed := dataplane.DeepCopy()
+	proposed.Spec.Deployment.PodTemplateSpec = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: consts.DataPlaneProxyContainerName,
+					Env:  []corev1.EnvVar{{Name: "KONG_LOG_LEVEL", Value: "debug"}},
+				},
+			},
+		},
+	}
+	proposed.Spec.Network.Services = &operatorv1beta1.DataPlaneServices{
+		Ingress: &operatorv1beta1.DataPlaneServiceOptions{
+			ServiceOptions: operatorv1beta1.ServiceOptions{Type: corev1.ServiceTypeClusterIP},
+		},
+	}
+	changes, err = Plan(ctx, cl, proposed, cfg)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	deploymentChange, serviceChange := changes[0], changes[1]
+	require.Equal(t, ActionUpdate, deploymentChange.Action)
+	require.True(t, deploymentChange.RestartsPods)
+	require.Contains(t, deploymentChange.Diff, "KONG_LOG_LEVEL")
+	require.Equal(t, ActionUpdate, serviceChange.Action)
+	require.True(t, serviceChange.DisruptsService)
+
+	var out bytes.Buffer
+	require.NoError(t, WriteReport(&out, changes))
+	require.Contains(t, out.String(), "Deployment ns/dp-deployment (owned by DataPlane ns/dp): update")
+	require.Contains(t, out.String(), "Pods will be restarted")
+	require.Contains(t, out.String(), "traffic may be disrupted")
+}
+
+func TestPlanUnsupportedObject(t *testing.T) {
+	cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+	_, err := Plan(context.Background(), cl, &corev1.ConfigMap{}, Config{})
+	require.ErrorContains(t, err, "unsupported object")
+}
+
+// desiredObject decodes the desired object of a change which would be created.
+func desiredObject[T any](c Change) (*T, error) {
+	obj := new(T)
+	if err := yaml.Unmarshal([]byte(c.Diff), obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
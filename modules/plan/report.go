@@ -0,0 +1,144 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Action is the action the operator would take on an owned resource.
+type Action string
+
+const (
+	// ActionCreate means the resource does not exist yet and would be created.
+	ActionCreate Action = "create"
+	// ActionUpdate means the live resource would be updated.
+	ActionUpdate Action = "update"
+	// ActionNone means the live resource would not change.
+	ActionNone Action = "none"
+)
+
+// Change describes how an owned resource would change.
+type Change struct {
+	// Owner describes the DataPlane or ControlPlane owning the resource.
+	Owner string
+	// Kind is the kind of the resource.
+	Kind string
+	// Object is the namespaced name of the resource. The name is empty for
+	// resources which would be created with a generated name.
+	Object types.NamespacedName
+	// Action is the action the operator would take.
+	Action Action
+	// Diff is the diff between the live and the desired resource. For
+	// resources which would be created it holds the whole desired resource.
+	Diff string
+	// RestartsPods is true when the change rolls out new Pods.
+	RestartsPods bool
+	// DisruptsService is true when the change may disrupt the Service's
+	// traffic: its type, selector, ports or external traffic policy change.
+	DisruptsService bool
+}
+
+func deploymentChange(owner string, live, desired *appsv1.Deployment) (Change, error) {
+	if live == nil {
+		return createChange(owner, "Deployment", desired)
+	}
+	c, err := updateChange(owner, "Deployment", live, desired)
+	if err != nil || c.Action != ActionUpdate {
+		return c, err
+	}
+	c.RestartsPods = !equality.Semantic.DeepEqual(live.Spec.Template, desired.Spec.Template)
+	return c, nil
+}
+
+func serviceChange(owner string, live, desired *corev1.Service) (Change, error) {
+	if live == nil {
+		return createChange(owner, "Service", desired)
+	}
+	c, err := updateChange(owner, "Service", live, desired)
+	if err != nil || c.Action != ActionUpdate {
+		return c, err
+	}
+	c.DisruptsService = live.Spec.Type != desired.Spec.Type ||
+		live.Spec.ExternalTrafficPolicy != desired.Spec.ExternalTrafficPolicy ||
+		!equality.Semantic.DeepEqual(live.Spec.Selector, desired.Spec.Selector) ||
+		!equality.Semantic.DeepEqual(live.Spec.Ports, desired.Spec.Ports)
+	return c, nil
+}
+
+func createChange(owner, kind string, desired client.Object) (Change, error) {
+	b, err := yaml.Marshal(desired)
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to marshal desired %s: %w", kind, err)
+	}
+	return Change{
+		Owner:  owner,
+		Kind:   kind,
+		Object: client.ObjectKeyFromObject(desired),
+		Action: ActionCreate,
+		Diff:   string(b),
+	}, nil
+}
+
+func updateChange(owner, kind string, live, desired client.Object) (Change, error) {
+	liveFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to convert live %s: %w", kind, err)
+	}
+	desiredFields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return Change{}, fmt.Errorf("failed to convert desired %s: %w", kind, err)
+	}
+	c := Change{
+		Owner:  owner,
+		Kind:   kind,
+		Object: client.ObjectKeyFromObject(live),
+		Action: ActionNone,
+		Diff:   cmp.Diff(liveFields, desiredFields),
+	}
+	if c.Diff != "" {
+		c.Action = ActionUpdate
+	}
+	return c, nil
+}
+
+// WriteReport writes a human readable report of the changes to w.
+func WriteReport(w io.Writer, changes []Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No owned resources found for the proposed object.")
+		return err
+	}
+	for _, c := range changes {
+		name := c.Object.String()
+		if c.Object.Name == "" {
+			name = c.Object.Namespace + "/<generated>"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s (owned by %s): %s\n", c.Kind, name, c.Owner, c.Action); err != nil {
+			return err
+		}
+		if c.RestartsPods {
+			if _, err := fmt.Fprintln(w, "  WARNING: the Pod template changes, Pods will be restarted"); err != nil {
+				return err
+			}
+		}
+		if c.DisruptsService {
+			if _, err := fmt.Fprintln(w, "  WARNING: the Service's type, selector, ports or external traffic policy change, traffic may be disrupted"); err != nil {
+				return err
+			}
+		}
+		if c.Action != ActionNone {
+			if _, err := fmt.Fprintf(w, "%s\n", c.Diff); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package supportbundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/gateway-operator/internal/types"
+	gatewayutils "github.com/kong/gateway-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+// collector collects the objects related to the support bundle's root object.
+type collector struct {
+	cl        client.Client
+	clientset kubernetes.Interface
+	now       time.Time
+
+	objects         []client.Object
+	seen            map[types.UID]struct{}
+	konnectEntities []client.Object
+
+	events          []corev1.Event
+	konnectStatuses []konnectEntityStatus
+	certificates    []certificateSummary
+	logs            map[string][]byte
+	errors          []string
+}
+
+func newCollector(cl client.Client, clientset kubernetes.Interface, now time.Time) *collector {
+	return &collector{
+		cl:        cl,
+		clientset: clientset,
+		now:       now,
+		seen:      make(map[types.UID]struct{}),
+		logs:      make(map[string][]byte),
+	}
+}
+
+// recordError records a non-fatal collection error in the bundle.
+func (c *collector) recordError(err error) {
+	c.errors = append(c.errors, err.Error())
+}
+
+// add adds the provided object to the bundle. It returns false if the object
+// has already been added. Objects are redacted before being added and
+// Secrets' certificates are summarized.
+func (c *collector) add(obj client.Object) bool {
+	if _, ok := c.seen[obj.GetUID()]; ok {
+		return false
+	}
+	c.seen[obj.GetUID()] = struct{}{}
+
+	obj = obj.DeepCopyObject().(client.Object)
+	gvk, err := apiutil.GVKForObject(obj, c.cl.Scheme())
+	if err != nil {
+		c.recordError(fmt.Errorf("failed to get kind of %s: %w", client.ObjectKeyFromObject(obj), err))
+		return false
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	obj.SetManagedFields(nil)
+
+	if secret, ok := obj.(*corev1.Secret); ok {
+		c.certificates = append(c.certificates, summarizeCertificates(secret, c.now)...)
+	}
+	redactObject(obj)
+	c.objects = append(c.objects, obj)
+	return true
+}
+
+// addAll adds all the provided objects to the bundle or records the error
+// returned when listing them.
+func addAll[T any, PT interface {
+	*T
+	client.Object
+}](c *collector, what string, owner client.Object, objs []T, err error) {
+	if err != nil {
+		c.recordError(fmt.Errorf("failed to list %s for %s: %w", what, client.ObjectKeyFromObject(owner), err))
+		return
+	}
+	for i := range objs {
+		c.add(PT(&objs[i]))
+	}
+}
+
+func (c *collector) collectGateway(ctx context.Context, nn types.NamespacedName) error {
+	var gateway gwtypes.Gateway
+	if err := c.cl.Get(ctx, nn, &gateway); err != nil {
+		return fmt.Errorf("failed to get Gateway %s: %w", nn, err)
+	}
+	c.add(&gateway)
+
+	c.collectGatewayClass(ctx, string(gateway.Spec.GatewayClassName))
+
+	dataplanes, err := gatewayutils.ListDataPlanesForGateway(ctx, c.cl, &gateway)
+	if err != nil {
+		c.recordError(fmt.Errorf("failed to list DataPlanes for Gateway %s: %w", nn, err))
+	}
+	for i := range dataplanes {
+		c.collectDataPlane(ctx, &dataplanes[i])
+	}
+
+	controlplanes, err := gatewayutils.ListControlPlanesForGateway(ctx, c.cl, &gateway)
+	if err != nil {
+		c.recordError(fmt.Errorf("failed to list ControlPlanes for Gateway %s: %w", nn, err))
+	}
+	for i := range controlplanes {
+		c.collectControlPlane(ctx, &controlplanes[i])
+	}
+
+	networkPolicies, err := gatewayutils.ListNetworkPoliciesForGateway(ctx, c.cl, &gateway)
+	addAll(c, "NetworkPolicies", &gateway, networkPolicies, err)
+
+	return nil
+}
+
+// collectGatewayClass collects the GatewayClass with the provided name and
+// the GatewayConfiguration referenced in its parametersRef.
+func (c *collector) collectGatewayClass(ctx context.Context, name string) {
+	var gatewayClass gatewayv1.GatewayClass
+	if err := c.cl.Get(ctx, client.ObjectKey{Name: name}, &gatewayClass); err != nil {
+		c.recordError(fmt.Errorf("failed to get GatewayClass %s: %w", name, err))
+		return
+	}
+	c.add(&gatewayClass)
+
+	ref := gatewayClass.Spec.ParametersRef
+	if ref == nil ||
+		string(ref.Group) != operatorv1beta1.SchemeGroupVersion.Group ||
+		string(ref.Kind) != "GatewayConfiguration" ||
+		ref.Namespace == nil {
+		return
+	}
+	var gatewayConfig operatorv1beta1.GatewayConfiguration
+	nn := client.ObjectKey{Namespace: string(*ref.Namespace), Name: ref.Name}
+	if err := c.cl.Get(ctx, nn, &gatewayConfig); err != nil {
+		c.recordError(fmt.Errorf("failed to get GatewayConfiguration %s: %w", nn, err))
+		return
+	}
+	if c.add(&gatewayConfig) {
+		c.collectKonnectExtensions(ctx, &gatewayConfig, gatewayConfig.GetExtensions())
+	}
+}
+
+func (c *collector) collectDataPlaneByName(ctx context.Context, nn types.NamespacedName) error {
+	var dataplane operatorv1beta1.DataPlane
+	if err := c.cl.Get(ctx, nn, &dataplane); err != nil {
+		return fmt.Errorf("failed to get DataPlane %s: %w", nn, err)
+	}
+	c.collectDataPlane(ctx, &dataplane)
+	return nil
+}
+
+// collectDataPlane collects the provided DataPlane and the resources it owns.
+func (c *collector) collectDataPlane(ctx context.Context, dataplane *operatorv1beta1.DataPlane) {
+	if !c.add(dataplane) {
+		return
+	}
+	c.collectOwnedResources(ctx, dataplane)
+	c.collectKonnectExtensions(ctx, dataplane, dataplane.GetExtensions())
+}
+
+// collectControlPlane collects the provided ControlPlane and the resources it owns.
+func (c *collector) collectControlPlane(ctx context.Context, controlplane *operatorv1beta1.ControlPlane) {
+	if !c.add(controlplane) {
+		return
+	}
+	c.collectOwnedResources(ctx, controlplane)
+	c.collectKonnectExtensions(ctx, controlplane, controlplane.GetExtensions())
+}
+
+// collectOwnedResources collects the namespaced resources owned by the provided object.
+func (c *collector) collectOwnedResources(ctx context.Context, owner client.Object) {
+	namespace, uid := owner.GetNamespace(), owner.GetUID()
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(ctx, c.cl, namespace, uid)
+	addAll(c, "Deployments", owner, deployments, err)
+	services, err := k8sutils.ListServicesForOwner(ctx, c.cl, namespace, uid)
+	addAll(c, "Services", owner, services, err)
+	serviceAccounts, err := k8sutils.ListServiceAccountsForOwner(ctx, c.cl, namespace, uid)
+	addAll(c, "ServiceAccounts", owner, serviceAccounts, err)
+	hpas, err := k8sutils.ListHPAsForOwner(ctx, c.cl, namespace, uid)
+	addAll(c, "HorizontalPodAutoscalers", owner, hpas, err)
+	pdbs, err := k8sutils.ListPodDisruptionBudgetsForOwner(ctx, c.cl, namespace, uid)
+	addAll(c, "PodDisruptionBudgets", owner, pdbs, err)
+	secrets, err := k8sutils.ListSecretsForOwner(ctx, c.cl, uid, client.InNamespace(namespace))
+	addAll(c, "Secrets", owner, secrets, err)
+}
+
+// collectPods collects the Pods of the collected Deployments.
+func (c *collector) collectPods(ctx context.Context) {
+	for _, obj := range c.objects {
+		deployment, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+		if err != nil {
+			c.recordError(fmt.Errorf("invalid selector of Deployment %s: %w", client.ObjectKeyFromObject(deployment), err))
+			continue
+		}
+		var pods corev1.PodList
+		if err := c.cl.List(ctx, &pods,
+			client.InNamespace(deployment.GetNamespace()),
+			client.MatchingLabelsSelector{Selector: selector},
+		); err != nil {
+			c.recordError(fmt.Errorf("failed to list Pods for Deployment %s: %w", client.ObjectKeyFromObject(deployment), err))
+			continue
+		}
+		addAll(c, "Pods", deployment, pods.Items, nil)
+	}
+}
+
+// collectEvents collects the Events involving the collected objects.
+func (c *collector) collectEvents(ctx context.Context) {
+	namespaces := make(map[string]struct{})
+	for _, obj := range c.objects {
+		if obj.GetNamespace() != "" {
+			namespaces[obj.GetNamespace()] = struct{}{}
+		}
+	}
+	for namespace := range namespaces {
+		var events corev1.EventList
+		if err := c.cl.List(ctx, &events, client.InNamespace(namespace)); err != nil {
+			c.recordError(fmt.Errorf("failed to list Events in namespace %s: %w", namespace, err))
+			continue
+		}
+		for _, event := range events.Items {
+			if _, ok := c.seen[event.InvolvedObject.UID]; !ok {
+				continue
+			}
+			event.ManagedFields = nil
+			c.events = append(c.events, event)
+		}
+	}
+}
+
+// collectKonnectExtensions collects the KonnectExtensions referenced in the
+// provided extensions of the owner object together with the Konnect entities
+// they refer to.
+func (c *collector) collectKonnectExtensions(ctx context.Context, owner client.Object, extensions []commonv1alpha1.ExtensionRef) {
+	for _, ext := range extensions {
+		if ext.Group != konnectv1alpha1.SchemeGroupVersion.Group || ext.Kind != konnectv1alpha1.KonnectExtensionKind {
+			continue
+		}
+		nn := client.ObjectKey{Namespace: owner.GetNamespace(), Name: ext.Name}
+		if ext.Namespace != nil && *ext.Namespace != "" {
+			nn.Namespace = *ext.Namespace
+		}
+		var konnectExtension konnectv1alpha1.KonnectExtension
+		if err := c.cl.Get(ctx, nn, &konnectExtension); err != nil {
+			c.recordError(fmt.Errorf("failed to get KonnectExtension %s: %w", nn, err))
+			continue
+		}
+		if !c.addKonnectEntity(&konnectExtension) {
+			continue
+		}
+
+		if clientAuth := konnectExtension.Status.DataPlaneClientAuth; clientAuth != nil && clientAuth.CertificateSecretRef != nil {
+			var secret corev1.Secret
+			secretNN := client.ObjectKey{Namespace: konnectExtension.Namespace, Name: clientAuth.CertificateSecretRef.Name}
+			if err := c.cl.Get(ctx, secretNN, &secret); err != nil {
+				c.recordError(fmt.Errorf("failed to get KonnectExtension's certificate Secret %s: %w", secretNN, err))
+			} else {
+				c.add(&secret)
+			}
+		}
+
+		cpRef := konnectExtension.Spec.Konnect.ControlPlane.Ref
+		if cpRef.Type != commonv1alpha1.ControlPlaneRefKonnectNamespacedRef || cpRef.KonnectNamespacedRef == nil {
+			continue
+		}
+		cpNN := client.ObjectKey{Namespace: konnectExtension.Namespace, Name: cpRef.KonnectNamespacedRef.Name}
+		if cpRef.KonnectNamespacedRef.Namespace != "" {
+			cpNN.Namespace = cpRef.KonnectNamespacedRef.Namespace
+		}
+		var konnectControlPlane konnectv1alpha1.KonnectGatewayControlPlane
+		if err := c.cl.Get(ctx, cpNN, &konnectControlPlane); err != nil {
+			c.recordError(fmt.Errorf("failed to get KonnectGatewayControlPlane %s: %w", cpNN, err))
+			continue
+		}
+		if !c.addKonnectEntity(&konnectControlPlane) {
+			continue
+		}
+
+		authNN := client.ObjectKey{
+			Namespace: konnectControlPlane.Namespace,
+			Name:      konnectControlPlane.Spec.KonnectConfiguration.APIAuthConfigurationRef.Name,
+		}
+		var apiAuth konnectv1alpha1.KonnectAPIAuthConfiguration
+		if err := c.cl.Get(ctx, authNN, &apiAuth); err != nil {
+			c.recordError(fmt.Errorf("failed to get KonnectAPIAuthConfiguration %s: %w", authNN, err))
+			continue
+		}
+		c.addKonnectEntity(&apiAuth)
+	}
+}
+
+// addKonnectEntity adds the provided Konnect entity to the ones whose statuses
+// are collected. Only statuses are collected as Konnect entities' specs can
+// contain credentials.
+func (c *collector) addKonnectEntity(obj client.Object) bool {
+	if _, ok := c.seen[obj.GetUID()]; ok {
+		return false
+	}
+	c.seen[obj.GetUID()] = struct{}{}
+	c.konnectEntities = append(c.konnectEntities, obj)
+	return true
+}
+
+// collectOperatorLogs collects the most recent logs of the operator's Pods'
+// containers matching the provided label selector.
+func (c *collector) collectOperatorLogs(ctx context.Context, namespace, selector string, tailLines int64) {
+	if namespace == "" {
+		return
+	}
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		c.recordError(fmt.Errorf("failed to list operator Pods in namespace %s: %w", namespace, err))
+		return
+	}
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			opts := &corev1.PodLogOptions{Container: container.Name}
+			if tailLines > 0 {
+				opts.TailLines = &tailLines
+			}
+			logs, err := c.readLogs(ctx, namespace, pod.Name, opts)
+			if err != nil {
+				c.recordError(fmt.Errorf("failed to get logs of container %s of Pod %s/%s: %w", container.Name, namespace, pod.Name, err))
+				continue
+			}
+			c.logs[path.Join("logs", namespace, pod.Name, container.Name+".log")] = logs
+		}
+	}
+}
+
+func (c *collector) readLogs(ctx context.Context, namespace, pod string, opts *corev1.PodLogOptions) ([]byte, error) {
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	return io.ReadAll(stream)
+}
@@ -0,0 +1,177 @@
+package supportbundle
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+// redactedValue replaces the values of Secrets' data, literal environment
+// variables' values and kubectl's last applied configurations in the bundle.
+const redactedValue = "REDACTED"
+
+// lastAppliedConfigAnnotation contains the whole object as applied with kubectl,
+// including Secrets' data.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// redactSecret replaces the values of the provided Secret's data, keeping its keys.
+func redactSecret(secret *corev1.Secret) {
+	for k := range secret.Data {
+		secret.Data[k] = []byte(redactedValue)
+	}
+	for k := range secret.StringData {
+		secret.StringData[k] = redactedValue
+	}
+}
+
+// redactObject redacts the parts of the provided object which can contain
+// credentials: the last applied configuration annotation and the literal
+// values of environment variables of the Pod templates, e.g. set for the
+// DataPlane with KONG_* variables. Values sourced from Secrets or ConfigMaps
+// are only references and are kept.
+func redactObject(obj client.Object) {
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[lastAppliedConfigAnnotation]; ok {
+			annotations[lastAppliedConfigAnnotation] = redactedValue
+		}
+	}
+
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		redactSecret(o)
+	case *corev1.Pod:
+		redactPodSpec(&o.Spec)
+	case *appsv1.Deployment:
+		redactPodSpec(&o.Spec.Template.Spec)
+	case *operatorv1beta1.DataPlane:
+		redactPodTemplateSpec(o.Spec.Deployment.PodTemplateSpec)
+	case *operatorv1beta1.ControlPlane:
+		redactPodTemplateSpec(o.Spec.Deployment.PodTemplateSpec)
+	case *operatorv1beta1.GatewayConfiguration:
+		if o.Spec.DataPlaneOptions != nil {
+			redactPodTemplateSpec(o.Spec.DataPlaneOptions.Deployment.PodTemplateSpec)
+		}
+		if o.Spec.ControlPlaneOptions != nil {
+			redactPodTemplateSpec(o.Spec.ControlPlaneOptions.Deployment.PodTemplateSpec)
+		}
+	}
+}
+
+func redactPodTemplateSpec(template *corev1.PodTemplateSpec) {
+	if template != nil {
+		redactPodSpec(&template.Spec)
+	}
+}
+
+func redactPodSpec(spec *corev1.PodSpec) {
+	for i := range spec.InitContainers {
+		redactEnv(spec.InitContainers[i].Env)
+	}
+	for i := range spec.Containers {
+		redactEnv(spec.Containers[i].Env)
+	}
+	for i := range spec.EphemeralContainers {
+		redactEnv(spec.EphemeralContainers[i].Env)
+	}
+}
+
+func redactEnv(env []corev1.EnvVar) {
+	for i := range env {
+		if env[i].Value != "" {
+			env[i].Value = redactedValue
+		}
+	}
+}
+
+// certificateSummary describes a certificate found in a collected Secret.
+type certificateSummary struct {
+	Secret    string    `json:"secret"`
+	Key       string    `json:"key"`
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dnsNames,omitempty"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	Expired   bool      `json:"expired"`
+	ExpiresIn string    `json:"expiresIn,omitempty"`
+}
+
+// summarizeCertificates returns the summaries of the PEM encoded certificates
+// found in the provided Secret's data.
+func summarizeCertificates(secret *corev1.Secret, now time.Time) []certificateSummary {
+	var summaries []certificateSummary
+	for _, key := range slices.Sorted(maps.Keys(secret.Data)) {
+		rest := secret.Data[key]
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			summary := certificateSummary{
+				Secret:    client.ObjectKeyFromObject(secret).String(),
+				Key:       key,
+				Subject:   cert.Subject.String(),
+				Issuer:    cert.Issuer.String(),
+				DNSNames:  cert.DNSNames,
+				NotBefore: cert.NotBefore,
+				NotAfter:  cert.NotAfter,
+				Expired:   now.After(cert.NotAfter),
+			}
+			if !summary.Expired {
+				summary.ExpiresIn = cert.NotAfter.Sub(now).Truncate(time.Minute).String()
+			}
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
+
+// konnectEntityStatus describes the status of a Konnect entity.
+type konnectEntityStatus struct {
+	Kind      string         `json:"kind"`
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Status    map[string]any `json:"status,omitempty"`
+}
+
+// collectKonnectStatuses collects the statuses of the collected Konnect entities.
+func (c *collector) collectKonnectStatuses() {
+	for _, obj := range c.konnectEntities {
+		gvk, err := apiutil.GVKForObject(obj, c.cl.Scheme())
+		if err != nil {
+			c.recordError(fmt.Errorf("failed to get kind of %s: %w", client.ObjectKeyFromObject(obj), err))
+			continue
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			c.recordError(fmt.Errorf("failed to convert %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err))
+			continue
+		}
+		status, _ := u["status"].(map[string]any)
+		c.konnectStatuses = append(c.konnectStatuses, konnectEntityStatus{
+			Kind:      gvk.Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Status:    status,
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// KindGateway is the kind of the root object for bundles collected for a Gateway.
+	KindGateway = "Gateway"
+	// KindDataPlane is the kind of the root object for bundles collected for a DataPlane.
+	KindDataPlane = "DataPlane"
+)
+
+// Config is the configuration of the support bundle collection.
+type Config struct {
+	// KubeconfigPath is the path to the kubeconfig file. If empty, the default
+	// controller-runtime config discovery is used.
+	KubeconfigPath string
+	// Kind is the kind of the object the bundle is collected for: Gateway or DataPlane.
+	Kind string
+	// Object is the namespaced name of the object the bundle is collected for.
+	Object types.NamespacedName
+	// OutputPath is the path of the written tarball.
+	OutputPath string
+	// OperatorNamespace is the namespace of the operator's Pods whose logs are collected.
+	OperatorNamespace string
+	// OperatorPodSelector is the label selector of the operator's Pods whose logs are collected.
+	OperatorPodSelector string
+	// LogTailLines is the number of the most recent log lines collected from each container.
+	LogTailLines int64
+}
+
+// Run collects the support bundle for the object configured in cfg and writes
+// it to cfg.OutputPath as a gzipped tarball.
+func Run(ctx context.Context, cfg Config, scheme *runtime.Scheme) error {
+	restCfg, err := restConfig(cfg.KubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to get Kubernetes client config: %w", err)
+	}
+	cl, err := client.New(restCfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	f, err := os.Create(cfg.OutputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create support bundle file: %w", err)
+	}
+	if err := Collect(ctx, cl, clientset, cfg, f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func restConfig(kubeconfigPath string) (*rest.Config, error) {
+	if kubeconfigPath != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	}
+	return ctrl.GetConfig()
+}
+
+// Collect walks the ownership graph of the object configured in cfg, collects
+// the related objects, Events, Konnect entity statuses, certificate expiry
+// summaries and the operator's logs and writes them to w as a gzipped tarball.
+// Secrets' data is redacted.
+//
+// Only failures to get the root object are returned as errors. Other collection
+// failures are recorded in the bundle's errors.txt file.
+func Collect(
+	ctx context.Context,
+	cl client.Client,
+	clientset kubernetes.Interface,
+	cfg Config,
+	w io.Writer,
+) error {
+	c := newCollector(cl, clientset, time.Now())
+
+	switch cfg.Kind {
+	case KindGateway:
+		if err := c.collectGateway(ctx, cfg.Object); err != nil {
+			return err
+		}
+	case KindDataPlane:
+		if err := c.collectDataPlaneByName(ctx, cfg.Object); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported kind %q, supported kinds: %s, %s", cfg.Kind, KindGateway, KindDataPlane)
+	}
+	c.collectPods(ctx)
+	c.collectEvents(ctx)
+	c.collectKonnectStatuses()
+	c.collectOperatorLogs(ctx, cfg.OperatorNamespace, cfg.OperatorPodSelector, cfg.LogTailLines)
+
+	return c.write(w)
+}
+
+// write writes the collected data to w as a gzipped tarball.
+func (c *collector) write(w io.Writer) error {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	files := make(map[string][]byte)
+	for _, obj := range c.objects {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			c.recordError(fmt.Errorf("failed to marshal %s: %w", client.ObjectKeyFromObject(obj), err))
+			continue
+		}
+		files[objectPath(obj)] = b
+	}
+	for name, v := range map[string]any{
+		"events.yaml":           c.events,
+		"konnect-statuses.yaml": c.konnectStatuses,
+		"certificates.yaml":     c.certificates,
+	} {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		files[name] = b
+	}
+	for name, logs := range c.logs {
+		files[name] = logs
+	}
+	if len(c.errors) > 0 {
+		files["errors.txt"] = []byte(strings.Join(c.errors, "\n") + "\n")
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		content := files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0o600,
+			Size:    int64(len(content)),
+			ModTime: c.now,
+		}); err != nil {
+			return fmt.Errorf("failed to write %s header: %w", name, err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to close support bundle tarball: %w", err)
+	}
+	return gzw.Close()
+}
+
+// objectPath returns the path of the provided object in the bundle.
+func objectPath(obj client.Object) string {
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = "_cluster"
+	}
+	kind := strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind)
+	return path.Join("objects", namespace, kind, obj.GetName()+".yaml")
+}
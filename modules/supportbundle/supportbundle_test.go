@@ -0,0 +1,234 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	fakeclientset "k8s.io/client-go/kubernetes/fake"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+	"github.com/kong/gateway-operator/test/helpers/certificate"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/api/konnect/v1alpha1"
+)
+
+func TestCollect(t *testing.T) {
+	const namespace = "ns"
+
+	secretEnv := corev1.EnvVar{Name: "KONG_PG_PASSWORD", Value: "dp-env-password"}
+	secretRefEnv := corev1.EnvVar{
+		Name: "KONG_LICENSE_DATA",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "license"},
+				Key:                  "license",
+			},
+		},
+	}
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "proxy", Env: []corev1.EnvVar{secretEnv, secretRefEnv}}},
+	}
+
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "dp", UID: "dp-uid"},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				Deployment: operatorv1beta1.DataPlaneDeploymentOptions{
+					DeploymentOptions: operatorv1beta1.DeploymentOptions{
+						PodTemplateSpec: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{Name: "proxy", Env: []corev1.EnvVar{secretEnv}}},
+							},
+						},
+					},
+				},
+				Extensions: []commonv1alpha1.ExtensionRef{
+					{
+						Group:         konnectv1alpha1.SchemeGroupVersion.Group,
+						Kind:          konnectv1alpha1.KonnectExtensionKind,
+						NamespacedRef: commonv1alpha1.NamespacedRef{Name: "konnect-ext"},
+					},
+				},
+			},
+		},
+	}
+	dataplane.SetGroupVersionKind(operatorv1beta1.SchemeGroupVersion.WithKind("DataPlane"))
+	owner := k8sutils.GenerateOwnerReferenceForObject(dataplane)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: "dp-deployment", UID: "deployment-uid",
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "dp"}},
+			Template: corev1.PodTemplateSpec{Spec: podSpec},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: "dp-pod", UID: "pod-uid", Labels: map[string]string{"app": "dp"},
+			Annotations: map[string]string{lastAppliedConfigAnnotation: secretEnv.Value},
+		},
+		Spec: podSpec,
+	}
+	certPEM, keyPEM := certificate.MustGenerateSelfSignedCertPEMFormat(certificate.WithCommonName("dp.example.com"))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: "dp-cert", UID: "secret-uid",
+			OwnerReferences: []metav1.OwnerReference{owner},
+			Annotations:     map[string]string{lastAppliedConfigAnnotation: string(keyPEM)},
+		},
+		Data: map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+	}
+	notOwnedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "other", UID: "other-uid"},
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: namespace, Name: "dp-event"},
+		InvolvedObject: corev1.ObjectReference{UID: dataplane.UID},
+		Reason:         "TestReason",
+	}
+	otherEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: namespace, Name: "other-event"},
+		InvolvedObject: corev1.ObjectReference{UID: notOwnedSecret.UID},
+	}
+	konnectExtension := &konnectv1alpha1.KonnectExtension{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "konnect-ext", UID: "konnect-ext-uid"},
+		Spec: konnectv1alpha1.KonnectExtensionSpec{
+			Konnect: konnectv1alpha1.KonnectExtensionKonnectSpec{
+				ControlPlane: konnectv1alpha1.KonnectExtensionControlPlane{
+					Ref: commonv1alpha1.ControlPlaneRef{
+						Type:      commonv1alpha1.ControlPlaneRefKonnectID,
+						KonnectID: lo.ToPtr("konnect-cp-id"),
+					},
+				},
+			},
+		},
+		Status: konnectv1alpha1.KonnectExtensionStatus{
+			Konnect: &konnectv1alpha1.KonnectExtensionControlPlaneStatus{ControlPlaneID: "konnect-cp-id"},
+		},
+	}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(dataplane, deployment, pod, secret, notOwnedSecret, event, otherEvent, konnectExtension).
+		Build()
+	clientset := fakeclientset.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "kong-system", Name: "operator",
+			Labels: map[string]string{"control-plane": "controller-manager"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "manager"}}},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, Collect(t.Context(), cl, clientset, Config{
+		Kind:                KindDataPlane,
+		Object:              k8stypes.NamespacedName{Namespace: namespace, Name: "dp"},
+		OperatorNamespace:   "kong-system",
+		OperatorPodSelector: "control-plane=controller-manager",
+	}, &buf))
+	files := readTarball(t, &buf)
+
+	require.ElementsMatch(t, []string{
+		"objects/ns/dataplane/dp.yaml",
+		"objects/ns/deployment/dp-deployment.yaml",
+		"objects/ns/pod/dp-pod.yaml",
+		"objects/ns/secret/dp-cert.yaml",
+		"events.yaml",
+		"konnect-statuses.yaml",
+		"certificates.yaml",
+		"logs/kong-system/operator/manager.log",
+	}, lo.Keys(files))
+
+	t.Log("Secret data is redacted")
+	var collectedSecret corev1.Secret
+	require.NoError(t, yaml.Unmarshal(files["objects/ns/secret/dp-cert.yaml"], &collectedSecret))
+	require.Equal(t, "Secret", collectedSecret.Kind)
+	require.Equal(t, map[string][]byte{
+		"tls.crt": []byte(redactedValue),
+		"tls.key": []byte(redactedValue),
+	}, collectedSecret.Data)
+	require.Equal(t, redactedValue, collectedSecret.Annotations[lastAppliedConfigAnnotation])
+	for name, content := range files {
+		require.NotContains(t, string(content), string(keyPEM), "file %s contains the Secret's data", name)
+	}
+
+	t.Log("literal environment variables' values and last applied configurations are redacted")
+	for name, content := range files {
+		require.NotContains(t, string(content), secretEnv.Value, "file %s contains an environment variable's value", name)
+	}
+	var collectedPod corev1.Pod
+	require.NoError(t, yaml.Unmarshal(files["objects/ns/pod/dp-pod.yaml"], &collectedPod))
+	require.Equal(t, redactedValue, collectedPod.Annotations[lastAppliedConfigAnnotation])
+	require.Equal(t, []corev1.EnvVar{
+		{Name: secretEnv.Name, Value: redactedValue},
+		secretRefEnv,
+	}, collectedPod.Spec.Containers[0].Env, "references to Secrets have to be kept")
+
+	t.Log("only Events of the collected objects are collected")
+	var events []corev1.Event
+	require.NoError(t, yaml.Unmarshal(files["events.yaml"], &events))
+	require.Len(t, events, 1)
+	require.Equal(t, "TestReason", events[0].Reason)
+
+	t.Log("certificates found in Secrets are summarized")
+	var certificates []certificateSummary
+	require.NoError(t, yaml.Unmarshal(files["certificates.yaml"], &certificates))
+	require.Len(t, certificates, 1)
+	require.Equal(t, "ns/dp-cert", certificates[0].Secret)
+	require.Equal(t, "tls.crt", certificates[0].Key)
+	require.Contains(t, certificates[0].Subject, "dp.example.com")
+	require.False(t, certificates[0].Expired)
+
+	t.Log("statuses of the referenced Konnect entities are collected")
+	var konnectStatuses []konnectEntityStatus
+	require.NoError(t, yaml.Unmarshal(files["konnect-statuses.yaml"], &konnectStatuses))
+	require.Len(t, konnectStatuses, 1)
+	require.Equal(t, konnectv1alpha1.KonnectExtensionKind, konnectStatuses[0].Kind)
+	require.Equal(t, "konnect-cp-id", konnectStatuses[0].Status["konnect"].(map[string]any)["controlPlaneID"])
+}
+
+func TestCollectMissingRootObject(t *testing.T) {
+	cl := fakectrlruntimeclient.NewClientBuilder().WithScheme(scheme.Get()).Build()
+	err := Collect(t.Context(), cl, fakeclientset.NewSimpleClientset(), Config{
+		Kind:   KindGateway,
+		Object: k8stypes.NamespacedName{Namespace: "ns", Name: "gw"},
+	}, io.Discard)
+	require.Error(t, err)
+}
+
+func readTarball(t *testing.T, r io.Reader) map[string][]byte {
+	t.Helper()
+
+	gzr, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	tr := tar.NewReader(gzr)
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		b, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = b
+	}
+	return files
+}
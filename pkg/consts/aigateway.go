@@ -0,0 +1,124 @@
+package consts
+
+const (
+	// AIGatewayManagedLabelValue indicates that an object's lifecycle is managed
+	// by the AIGateway controller.
+	AIGatewayManagedLabelValue = "aigateway"
+
+	// AIGatewayModelLabel is the label set on the KongPlugins and HTTPRoutes
+	// generated for an AIGateway. Its value is the identifier of the Large
+	// Language Model they are generated for.
+	AIGatewayModelLabel = OperatorLabelPrefix + "aigateway-model"
+)
+
+const (
+	// AIGatewaySelfHostedLLMsAnnotation can be set on an AIGateway to serve
+	// Large Language Models from model servers running in the cluster (e.g. vLLM
+	// or Ollama) next to the ones configured in spec.largeLanguageModels.cloudHosted.
+	// The value of such an annotation is a JSON list of models, each with an
+	// identifier that is unique across all the models of the AIGateway, the
+	// Service backing the model server, the format of its API and an optional
+	// Secret holding the credentials sent to it.
+	//
+	// Example:
+	// gateway-operator.konghq.com/self-hosted-llms: |
+	//   [
+	//     {
+	//       "identifier": "llama",
+	//       "model": "llama3",
+	//       "promptType": "chat",
+	//       "format": "openai",
+	//       "upstream": {
+	//         "service": {"name": "vllm", "namespace": "models", "port": 8000},
+	//         "path": "/v1/chat/completions"
+	//       },
+	//       "auth": {"secretRef": {"name": "vllm-token", "key": "token"}}
+	//     }
+	//   ]
+	AIGatewaySelfHostedLLMsAnnotation = OperatorLabelPrefix + "self-hosted-llms"
+)
+
+const (
+	// AIGatewayLLMBackendsAnnotation can be set on an AIGateway to load balance
+	// the requests to its cloud hosted LLMs across several backends, e.g. Azure
+	// OpenAI in two regions plus OpenAI. The value of such an annotation is a
+	// JSON list with, for each model identifier, the backends to use next to the
+	// model's own cloud provider (the "primary" backend), their weights and the
+	// balancer's algorithm, retries and failover criteria.
+	// Additional backends use the API key of their provider from the AIGateway's
+	// cloud provider credentials Secret unless they reference a Secret key.
+	//
+	// Example:
+	// gateway-operator.konghq.com/llm-backends: |
+	//   [
+	//     {
+	//       "identifier": "gpt",
+	//       "weight": 100,
+	//       "backends": [
+	//         {
+	//           "name": "azure-west",
+	//           "provider": "azure",
+	//           "model": "gpt-4o",
+	//           "weight": 50,
+	//           "azure": {"instance": "west", "deploymentID": "gpt-4o"},
+	//           "secretRef": {"name": "azure-west", "key": "api-key"}
+	//         }
+	//       ],
+	//       "balancer": {"algorithm": "priority", "retries": 3, "failoverCriteria": ["error", "timeout", "http_429"]}
+	//     }
+	//   ]
+	AIGatewayLLMBackendsAnnotation = OperatorLabelPrefix + "llm-backends"
+)
+
+const (
+	// AIGatewayTokenBudgetsAnnotation can be set on an AIGateway to cap the
+	// requests and the tokens each consumer, or each consumer group, can use
+	// per time window. The value of such an annotation is a JSON list of
+	// budgets, each with the identifiers of the models it applies to (all of
+	// them when omitted), whether the counters are per consumer or per consumer
+	// group and its limits, with their window in seconds. Setting it also
+	// enables the AI metrics of the DataPlane, from which the usage of each
+	// model by every consumer is accumulated in the AIGateway's usage ConfigMap.
+	//
+	// Example:
+	// gateway-operator.konghq.com/token-budgets: |
+	//   [
+	//     {
+	//       "name": "team",
+	//       "models": ["gpt"],
+	//       "per": "consumer-group",
+	//       "limits": [
+	//         {"type": "requests", "limit": 1000, "window": 3600},
+	//         {"type": "total_tokens", "limit": 1000000, "window": 86400}
+	//       ]
+	//     }
+	//   ]
+	AIGatewayTokenBudgetsAnnotation = OperatorLabelPrefix + "token-budgets"
+)
+
+const (
+	// AIGatewayPromptPoliciesAnnotation can be set on an AIGateway to guard,
+	// redact and cache the prompts sent to its models. The value of such an
+	// annotation is a JSON list with, for each model identifier, the allow and
+	// deny prompt patterns, the categories of PII to redact and the semantic
+	// cache of the responses, with the embeddings model and the Redis vector
+	// database it uses. The embeddings model uses the API key of its provider
+	// from the AIGateway's cloud provider credentials Secret.
+	//
+	// Example:
+	// gateway-operator.konghq.com/prompt-policies: |
+	//   [
+	//     {
+	//       "identifier": "gpt",
+	//       "promptGuard": {"denyPatterns": [".*(password|secret).*"]},
+	//       "piiRedaction": {"categories": ["email", "phone"], "recoverRedacted": true},
+	//       "semanticCache": {
+	//         "embeddings": {"provider": "openai", "model": "text-embedding-3-small"},
+	//         "redis": {"host": "redis.ai.svc", "port": 6379},
+	//         "dimensions": 1536,
+	//         "cacheTTL": 300
+	//       }
+	//     }
+	//   ]
+	AIGatewayPromptPoliciesAnnotation = OperatorLabelPrefix + "prompt-policies"
+)
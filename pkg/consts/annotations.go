@@ -6,3 +6,30 @@ const (
 	// DataPlane and trigger a rolling update.
 	AnnotationPodTemplateSpecHash = "gateway-operator.konghq.com/spec-hash"
 )
+
+const (
+	// ReconciliationPausedAnnotation can be set to "true" on a Gateway, ControlPlane,
+	// DataPlane, AIGateway, KongPluginInstallation or Konnect entity to pause its
+	// reconciliation, e.g. to manually patch the resources managed for it during
+	// an incident. While paused, the operator doesn't change the object nor the
+	// resources it manages (including their cleanup on deletion) and only sets
+	// the object's Paused condition. Removing the annotation or setting it to any
+	// other value resumes the reconciliation.
+	ReconciliationPausedAnnotation = "gateway-operator.konghq.com/paused"
+)
+
+const (
+	// ConversionDataAnnotation is set by the conversion webhook on objects
+	// converted from their hub version to an older version. It holds the fields
+	// which can't be represented in the older version so that they can be
+	// restored when the object is converted back to the hub version.
+	ConversionDataAnnotation = "gateway-operator.konghq.com/conversion-data"
+)
+
+const (
+	// TraceParentAnnotation is set by the operator on the objects it creates and
+	// updates when tracing is enabled. It holds the W3C traceparent of the span
+	// which wrote the object so that the reconciliation of the object can be
+	// linked to the reconciliation which caused it.
+	TraceParentAnnotation = "gateway-operator.konghq.com/traceparent"
+)
@@ -19,6 +19,35 @@ const (
 	// ControlPlaneManagedLabelValue indicates that an object's lifecycle is managed
 	// by the controlplane controller.
 	ControlPlaneManagedLabelValue = "controlplane"
+
+	// ControlPlaneDeploymentStateLabel indicates the state of a ControlPlane Deployment.
+	// Useful for progressive rollouts.
+	ControlPlaneDeploymentStateLabel = "gateway-operator.konghq.com/controlplane-deployment-state"
+
+	// ControlPlaneStateLabelValuePreview indicates that a ControlPlane Deployment
+	// is a "preview" Deployment which translates the configuration against the
+	// "preview" DataPlane Pods during a blue/green rollout.
+	ControlPlaneStateLabelValuePreview = "preview"
+
+	// ControlPlaneRolloutAnnotation can be set on a ControlPlane to roll out
+	// changes of its Deployment using the blue/green strategy. The new version
+	// is first deployed as a "preview" Deployment configuring the DataPlane's
+	// "preview" Pods (which requires the DataPlane to use the BlueGreen rollout
+	// strategy) and the "live" Deployment is only updated once the preview one
+	// has successfully synced its configuration and has been promoted.
+	// The value of such an annotation has the same format as DataPlane's
+	// spec.deployment.rollout.
+	//
+	// Example:
+	// gateway-operator.konghq.com/controlplane-rollout: |
+	//   {"strategy": {"blueGreen": {"promotion": {"strategy": "BreakBeforePromotion"}}}}
+	ControlPlaneRolloutAnnotation = "gateway-operator.konghq.com/controlplane-rollout"
+
+	// ControlPlanePromoteWhenReadyAnnotation can be set to "true" on a ControlPlane
+	// using the BreakBeforePromotion strategy to signal that the "preview"
+	// Deployment should be promoted once it is ready.
+	// It is removed by the operator once the promotion is done.
+	ControlPlanePromoteWhenReadyAnnotation = "gateway-operator.konghq.com/promote-when-ready"
 )
 
 // -----------------------------------------------------------------------------
@@ -38,6 +67,14 @@ const (
 	// ControlPlaneControllerContainerName is the name of the ingress controller container in a ControlPlane Deployment.
 	ControlPlaneControllerContainerName = "controller"
 
+	// ControlPlaneMetricsPort is the port on which the ingress controller
+	// exposes its metrics.
+	ControlPlaneMetricsPort = 10255
+
+	// ControlPlaneMetricsEndpoint is the endpoint of the ingress controller's
+	// metrics listener.
+	ControlPlaneMetricsEndpoint = "/metrics"
+
 	// DataPlaneInitRetryDelay is the time delay between every attempt (on controller startup)
 	// to connect to the Kong Admin API. It needs to be customized to 5 seconds to avoid
 	// the ControlPlane crash due to DataPlane slow starts.
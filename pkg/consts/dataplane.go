@@ -40,6 +40,58 @@ const (
 	// Useful for progressive rollouts.
 	DataPlanePodStateLabel = "gateway-operator.konghq.com/dataplane-pod-state"
 
+	// DataPlanePodConfigHashAnnotation is the annotation set on the DataPlane Pods
+	// by the DataPlane controller to report the hash of the Kong configuration
+	// loaded by the Pod, as reported by the Pod's status endpoint.
+	DataPlanePodConfigHashAnnotation = "gateway-operator.konghq.com/config-hash"
+
+	// DataPlaneExpectedConfigHashAnnotation can be set on a DataPlane, by the
+	// system pushing the configuration to its Pods, to the hash of the Kong
+	// configuration the Pods are expected to have loaded, as reported by the
+	// "configuration_hash" field of their status endpoint. It's used to report
+	// the Pods with outdated configuration in the DataPlane's ConfigurationSynced
+	// condition. When it's not set, the configuration loaded by the majority of
+	// the Pods is expected.
+	DataPlaneExpectedConfigHashAnnotation = "gateway-operator.konghq.com/expected-config-hash"
+
+	// DataPlaneIngressServiceNameLabel is the label that is used for the additional
+	// ingress Services created by the DataPlane controller to store the name under
+	// which the Service has been configured in DataPlaneIngressServicesAnnotation.
+	DataPlaneIngressServiceNameLabel = "gateway-operator.konghq.com/dataplane-ingress-service-name"
+
+	// DataPlaneIngressServicesAnnotation can be set on a DataPlane to expose its
+	// Pods through additional, named ingress Services next to the one configured
+	// in DataPlane's spec.network.services.ingress.
+	// The value of such an annotation is a JSON list of Service options, each with
+	// a unique name that is a valid DNS label.
+	//
+	// Example:
+	// gateway-operator.konghq.com/ingress-services: |
+	//   [
+	//     {
+	//       "name": "internal",
+	//       "type": "LoadBalancer",
+	//       "annotations": {"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+	//       "externalTrafficPolicy": "Local",
+	//       "ports": [{"port": 80, "targetPort": 8000}]
+	//     }
+	//   ]
+	DataPlaneIngressServicesAnnotation = "gateway-operator.konghq.com/ingress-services"
+
+	// DataPlaneNetworkPolicyAnnotation can be set on a GatewayConfiguration or on
+	// a DataPlane to configure the NetworkPolicy generated for the DataPlane's Pods.
+	// When set on both, the DataPlane's annotation takes precedence.
+	// The value of such an annotation is a JSON document with optional "ingress"
+	// and "egress" allow-lists.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy: |
+	//   {
+	//     "ingress": {"proxyFrom": [{"namespaceSelector": {"matchLabels": {"kubernetes.io/metadata.name": "ingress-nginx"}}}]},
+	//     "egress": {"allowDNS": true, "allowedNamespaces": ["upstreams"]}
+	//   }
+	DataPlaneNetworkPolicyAnnotation = "gateway-operator.konghq.com/network-policy"
+
 	// DataPlaneStateLabelValuePreview indicates that a DataPlane resource is
 	// a "preview" resource.
 	// This is used in:
@@ -61,6 +113,11 @@ const (
 	// DataPlaneIngressServiceLabelValue indicates that the service is intended to expose the
 	// DataPlane proxy.
 	DataPlaneIngressServiceLabelValue ServiceType = "ingress"
+
+	// DataPlaneAdditionalIngressServiceLabelValue indicates that the service is one
+	// of the additional services configured through DataPlaneIngressServicesAnnotation
+	// that are intended to expose the DataPlane proxy.
+	DataPlaneAdditionalIngressServiceLabelValue ServiceType = "additional-ingress"
 )
 
 // -----------------------------------------------------------------------------
@@ -125,6 +182,10 @@ const (
 	// DataPlaneStatusReadyEndpoint is the endpoint to use for DataPlane readiness probe
 	// in the context of managed gateways.
 	DataPlaneStatusReadyEndpoint = "/status/ready"
+
+	// DataPlaneMetricsEndpoint is the endpoint of the DataPlane's status listener
+	// exposing the metrics of the prometheus plugin.
+	DataPlaneMetricsEndpoint = "/metrics"
 )
 
 // -----------------------------------------------------------------------------
@@ -0,0 +1,41 @@
+package consts
+
+// -----------------------------------------------------------------------------
+// Consts - GatewayConfiguration rollout annotations
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayConfigurationRolloutAnnotation can be set on a GatewayConfiguration
+	// to roll out changes of its DataPlane options to the Gateways using it in
+	// stages instead of updating all of them at once.
+	// The value of such an annotation is a JSON document with the following fields:
+	// - "maxUnavailableGateways": the maximum number of Gateways whose DataPlane
+	//   can be unavailable during the rollout. Defaults to 1.
+	// - "orderByLabel": the key of a Gateway label used to order the rollout.
+	//   Gateways are rolled out in stages, one per label value, in ascending
+	//   order of the values (numerically when they are integers). Gateways
+	//   without the label are rolled out last.
+	//
+	// Example:
+	// gateway-operator.konghq.com/rollout: |
+	//   {"maxUnavailableGateways": 2, "orderByLabel": "example.com/rollout-stage"}
+	GatewayConfigurationRolloutAnnotation = "gateway-operator.konghq.com/rollout"
+
+	// GatewayConfigurationRolloutPausedAnnotation can be set to "true" on
+	// a GatewayConfiguration to pause the rollout configured with
+	// GatewayConfigurationRolloutAnnotation. Removing it or setting it to any
+	// other value resumes the rollout.
+	GatewayConfigurationRolloutPausedAnnotation = "gateway-operator.konghq.com/rollout-paused"
+
+	// GatewayConfigurationRolloutSlotsAnnotation is the annotation set by the
+	// GatewayConfigurationRollout controller on a GatewayConfiguration to claim
+	// the rollout slots of the Gateways allowed to update their DataPlane. Claims
+	// are written with the GatewayConfiguration's resourceVersion so that they
+	// are never computed from a stale cache.
+	GatewayConfigurationRolloutSlotsAnnotation = "gateway-operator.konghq.com/rollout-slots"
+
+	// DataPlaneGatewayConfigurationHashAnnotation is the annotation set by the
+	// Gateway controller on the DataPlanes it manages to store the hash of the
+	// GatewayConfiguration's DataPlane options applied to the DataPlane.
+	DataPlaneGatewayConfigurationHashAnnotation = "gateway-operator.konghq.com/gateway-configuration-hash"
+)
@@ -12,4 +12,49 @@ const (
 	// AnnotationKongPluginInstallationGenerationInternal is the annotation key used to store KongPluginInstallation
 	// and its generation, internal usage to re-trigger deployment when KongPluginInstallation changes.
 	AnnotationKongPluginInstallationGenerationInternal = OperatorLabelPrefix + "kong-plugin-installation-generation"
+
+	// AnnotationKongPluginInstallationDigest is the annotation key used to store the digest of the image manifest
+	// the plugin stored in a KongPluginInstallation's ConfigMap was fetched from.
+	AnnotationKongPluginInstallationDigest = OperatorLabelPrefix + "kong-plugin-installation-digest"
+
+	// AnnotationKongPluginInstallationImage is the annotation key used to store the image URL, as set in the
+	// KongPluginInstallation, the plugin stored in a KongPluginInstallation's ConfigMap was fetched from.
+	AnnotationKongPluginInstallationImage = OperatorLabelPrefix + "kong-plugin-installation-image"
+
+	// AnnotationKongPluginInstallationFiles is the annotation key used to store, as a JSON object, the paths
+	// in the plugin's directory of the files stored under the keys of a ConfigMap. It's only set when some
+	// of the plugin's files are in subdirectories, otherwise the keys are the files' names.
+	AnnotationKongPluginInstallationFiles = OperatorLabelPrefix + "kong-plugin-installation-files"
+
+	// AnnotationKongPluginInstallationParts is the annotation key used to store, in the underlying ConfigMap
+	// of a KongPluginInstallation, the comma separated names of the additional ConfigMaps the plugin is split
+	// across when it doesn't fit in one ConfigMap.
+	AnnotationKongPluginInstallationParts = OperatorLabelPrefix + "kong-plugin-installation-parts"
+
+	// AnnotationKongPluginInstallationPart is the annotation key used to store the index of an additional
+	// ConfigMap a plugin is split across.
+	AnnotationKongPluginInstallationPart = OperatorLabelPrefix + "kong-plugin-installation-part"
+)
+
+const (
+	// KongPluginInstallationUpdateIntervalAnnotation can be set on a KongPluginInstallation to periodically check
+	// whether the tag of its image points to a new digest. Its value is a duration, e.g. "10m". When not set,
+	// the tag is only checked when the KongPluginInstallation or its image pull Secret change.
+	KongPluginInstallationUpdateIntervalAnnotation = OperatorLabelPrefix + "update-interval"
+
+	// KongPluginInstallationUpdatePolicyAnnotation can be set on a KongPluginInstallation to control what
+	// happens when the tag of its image points to a new digest: "auto" (default) installs the new digest,
+	// updating the DataPlanes using the plugin, while "manual" keeps the installed digest until the new one
+	// is approved with the KongPluginInstallationApprovedDigestAnnotation annotation.
+	KongPluginInstallationUpdatePolicyAnnotation = OperatorLabelPrefix + "update-policy"
+
+	// KongPluginInstallationApprovedDigestAnnotation can be set on a KongPluginInstallation with the "manual"
+	// update policy to approve the installation of the digest its image's tag now points to.
+	KongPluginInstallationApprovedDigestAnnotation = OperatorLabelPrefix + "approved-digest"
+
+	// KongPluginInstallationSignatureVerificationSecretAnnotation can be set on a KongPluginInstallation to the
+	// name of a Secret, in the KongPluginInstallation's namespace, holding the PEM encoded public keys (cosign)
+	// and root certificates (notation) trusted to sign its image. When set, the image is only installed when
+	// it has a valid cosign or notation signature made with one of them.
+	KongPluginInstallationSignatureVerificationSecretAnnotation = OperatorLabelPrefix + "signature-verification-secret"
 )
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -169,6 +170,7 @@ func GetDataPlaneForControlPlane(
 
 // GetDataPlaneServiceName is a helper function that retrieves the name of the service owned by provided dataplane.
 // It accepts a string as the last argument to specify which service to retrieve (proxy/admin)
+// "preview" services created for DataPlanes using the BlueGreen rollout strategy are ignored.
 func GetDataPlaneServiceName(
 	ctx context.Context,
 	c client.Client,
@@ -187,6 +189,9 @@ func GetDataPlaneServiceName(
 	if err != nil {
 		return "", err
 	}
+	services = lo.Reject(services, func(s corev1.Service, _ int) bool {
+		return s.Labels[consts.DataPlaneServiceStateLabel] == consts.DataPlaneStateLabelValuePreview
+	})
 
 	count := len(services)
 	if count > 1 {
@@ -200,6 +205,36 @@ func GetDataPlaneServiceName(
 	return services[0].Name, nil
 }
 
+// GetDataPlanePreviewServiceName is a helper function that retrieves the name of
+// the "preview" service of the provided type owned by the provided dataplane.
+// Such services exist only for DataPlanes using the BlueGreen rollout strategy.
+func GetDataPlanePreviewServiceName(
+	ctx context.Context,
+	c client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+	serviceTypeLabelValue consts.ServiceType,
+) (string, error) {
+	services, err := k8sutils.ListServicesForOwner(ctx,
+		c,
+		dataplane.Namespace,
+		dataplane.UID,
+		client.MatchingLabels{
+			consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+			consts.DataPlaneServiceTypeLabel:     string(serviceTypeLabelValue),
+			consts.DataPlaneServiceStateLabel:    consts.DataPlaneStateLabelValuePreview,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if count := len(services); count != 1 {
+		return "", fmt.Errorf("found %d preview %s services for DataPlane: expected 1", count, serviceTypeLabelValue)
+	}
+
+	return services[0].Name, nil
+}
+
 // ListNetworkPoliciesForGateway is a helper function that returns a list of NetworkPolicies
 // that are owned and managed by a Gateway.
 func ListNetworkPoliciesForGateway(
@@ -226,6 +226,74 @@ func ListClusterRoleBindings(
 	return clusterRoleBindingList.Items, nil
 }
 
+// ListRolesForOwner is a helper function which gets a list of Roles
+// using the provided list options and reduce by OwnerReference UID and namespace to efficiently
+// list only the objects owned by the provided UID.
+func ListRolesForOwner(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	uid types.UID,
+	listOpts ...client.ListOption,
+) ([]rbacv1.Role, error) {
+	roleList := &rbacv1.RoleList{}
+
+	err := c.List(
+		ctx,
+		roleList,
+		append(
+			[]client.ListOption{client.InNamespace(namespace)},
+			listOpts...,
+		)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]rbacv1.Role, 0)
+	for _, role := range roleList.Items {
+		if IsOwnedByRefUID(&role, uid) {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles, nil
+}
+
+// ListRoleBindingsForOwner is a helper function which gets a list of RoleBindings
+// using the provided list options and reduce by OwnerReference UID and namespace to efficiently
+// list only the objects owned by the provided UID.
+func ListRoleBindingsForOwner(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	uid types.UID,
+	listOpts ...client.ListOption,
+) ([]rbacv1.RoleBinding, error) {
+	roleBindingList := &rbacv1.RoleBindingList{}
+
+	err := c.List(
+		ctx,
+		roleBindingList,
+		append(
+			[]client.ListOption{client.InNamespace(namespace)},
+			listOpts...,
+		)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	roleBindings := make([]rbacv1.RoleBinding, 0)
+	for _, roleBinding := range roleBindingList.Items {
+		if IsOwnedByRefUID(&roleBinding, uid) {
+			roleBindings = append(roleBindings, roleBinding)
+		}
+	}
+
+	return roleBindings, nil
+}
+
 // ListConfigMapsForOwner is a helper function which gets a list of ConfigMaps
 // using the provided list options and reduce by OwnerReference UID to efficiently
 // list only the objects owned by the provided UID.
@@ -0,0 +1,66 @@
+package reduce
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/gateway-operator/controller/pkg/events"
+	"github.com/kong/gateway-operator/internal/metrics"
+	"github.com/kong/gateway-operator/pkg/clientops"
+)
+
+// deleteAll deletes all the provided duplicates and emits an Event for their
+// owner with the recorder from the context.
+func deleteAll[
+	T any,
+	TPtr interface {
+		*T
+		client.Object
+	},
+](ctx context.Context, k8sClient client.Client, duplicates []T) error {
+	if err := clientops.DeleteAll[T, TPtr](ctx, k8sClient, duplicates); err != nil {
+		return err
+	}
+	recordDuplicatesReduced[T, TPtr](ctx, duplicates)
+	return nil
+}
+
+// recordDuplicatesReduced records the number of the provided deleted duplicates
+// in the operator's metrics and emits an Event for their owner with the recorder
+// from the context. The Event is skipped for duplicates without a controller owner.
+func recordDuplicatesReduced[
+	T any,
+	TPtr interface {
+		*T
+		client.Object
+	},
+](ctx context.Context, duplicates []T) {
+	if len(duplicates) == 0 {
+		return
+	}
+
+	kind := reflect.TypeFor[T]().Name()
+	metrics.RecordDuplicatesReduced(kind, len(duplicates))
+
+	var (
+		owner *corev1.ObjectReference
+		names = make([]string, 0, len(duplicates))
+	)
+	for i := range duplicates {
+		obj := TPtr(&duplicates[i])
+		if owner == nil {
+			owner = events.OwnerReference(obj)
+		}
+		names = append(names, obj.GetName())
+	}
+	if owner == nil {
+		return
+	}
+	events.FromContext(ctx).Eventf(owner, corev1.EventTypeNormal, events.ReasonDuplicatesReduced,
+		"Deleted %d duplicate %s(s): %s", len(duplicates), kind, strings.Join(names, ", "),
+	)
+}
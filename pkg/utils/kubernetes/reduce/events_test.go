@@ -0,0 +1,56 @@
+package reduce
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/gateway-operator/controller/pkg/events"
+	"github.com/kong/gateway-operator/modules/manager/scheme"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
+)
+
+func TestReduceEmitsEventForOwner(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		TypeMeta:   metav1.TypeMeta{APIVersion: operatorv1beta1.SchemeGroupVersion.String(), Kind: "DataPlane"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "dp", UID: "dp-uid"},
+	}
+	owner := k8sutils.GenerateOwnerReferenceForObject(dataplane)
+	serviceAccounts := []corev1.ServiceAccount{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns", Name: "sa-1", OwnerReferences: []metav1.OwnerReference{owner},
+				CreationTimestamp: metav1.Unix(1, 0),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns", Name: "sa-2", OwnerReferences: []metav1.OwnerReference{owner},
+				CreationTimestamp: metav1.Unix(2, 0),
+			},
+		},
+	}
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(&serviceAccounts[0], &serviceAccounts[1]).
+		Build()
+
+	fake := record.NewFakeRecorder(1)
+	fake.IncludeObject = true
+	ctx := events.IntoContext(t.Context(), fake)
+	require.NoError(t, ReduceServiceAccounts(ctx, cl, serviceAccounts))
+
+	event := <-fake.Events
+	require.Contains(t, event, "Normal DuplicatesReduced Deleted 1 duplicate ServiceAccount(s): sa-2")
+	require.Contains(t, event, "DataPlane")
+
+	t.Log("no Event is emitted when there are no duplicates")
+	require.NoError(t, ReduceServiceAccounts(ctx, cl, serviceAccounts[:1]))
+	require.Empty(t, fake.Events)
+}
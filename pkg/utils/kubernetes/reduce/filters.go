@@ -94,6 +94,52 @@ func filterClusterRoles(clusterRoles []rbacv1.ClusterRole) []rbacv1.ClusterRole
 	return append(clusterRoles[:best], clusterRoles[best+1:]...)
 }
 
+// -----------------------------------------------------------------------------
+// Filter functions - Roles
+// -----------------------------------------------------------------------------
+
+// filterRoles filters out the Role to be kept and returns
+// all the Roles to be deleted.
+// The filtered-out Role is decided as follows:
+//  1. creationTimestamp (newer is better, because newer Roles can contain new policy rules)
+func filterRoles(roles []rbacv1.Role) []rbacv1.Role {
+	if len(roles) < 2 {
+		return []rbacv1.Role{}
+	}
+
+	best := 0
+	for i, r := range roles {
+		if r.CreationTimestamp.After(roles[best].CreationTimestamp.Time) {
+			best = i
+		}
+	}
+
+	return append(roles[:best], roles[best+1:]...)
+}
+
+// -----------------------------------------------------------------------------
+// Filter functions - RoleBindings
+// -----------------------------------------------------------------------------
+
+// filterRoleBindings filters out the RoleBinding to be kept and returns
+// all the RoleBindings to be deleted.
+// The filtered-out RoleBinding is decided as follows:
+// 1. creationTimestamp (older is better)
+func filterRoleBindings(roleBindings []rbacv1.RoleBinding) []rbacv1.RoleBinding {
+	if len(roleBindings) < 2 {
+		return []rbacv1.RoleBinding{}
+	}
+
+	toFilter := 0
+	for i, roleBinding := range roleBindings {
+		if roleBinding.CreationTimestamp.Before(&roleBindings[toFilter].CreationTimestamp) {
+			toFilter = i
+		}
+	}
+
+	return append(roleBindings[:toFilter], roleBindings[toFilter+1:]...)
+}
+
 // -----------------------------------------------------------------------------
 // Filter functions - ClusterRoleBindings
 // -----------------------------------------------------------------------------
@@ -521,6 +521,48 @@ func TestFilterClusterRoles(t *testing.T) {
 	}
 }
 
+func TestFilterRoles(t *testing.T) {
+	now := metav1.Now()
+	roles := []rbacv1.Role{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "older",
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Second)),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "newer",
+				CreationTimestamp: now,
+			},
+		},
+	}
+
+	filtered := filterRoles(roles)
+	require.Equal(t, []string{"older"}, lo.Map(filtered, func(r rbacv1.Role, _ int) string { return r.Name }))
+}
+
+func TestFilterRoleBindings(t *testing.T) {
+	now := metav1.Now()
+	roleBindings := []rbacv1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "newer",
+				CreationTimestamp: now,
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "older",
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Second)),
+			},
+		},
+	}
+
+	filtered := filterRoleBindings(roleBindings)
+	require.Equal(t, []string{"newer"}, lo.Map(filtered, func(rb rbacv1.RoleBinding, _ int) string { return rb.Name }))
+}
+
 func TestFilterHPA(t *testing.T) {
 	now := time.Now()
 	testCases := []struct {
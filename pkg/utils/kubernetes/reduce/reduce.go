@@ -16,7 +16,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/gateway-operator/controller/konnect/constraints"
-	"github.com/kong/gateway-operator/pkg/clientops"
 
 	configurationv1alpha1 "github.com/kong/kubernetes-configuration/api/configuration/v1alpha1"
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
@@ -41,6 +40,7 @@ func ReduceSecrets(ctx context.Context, k8sClient client.Client, secrets []corev
 			return err
 		}
 	}
+	recordDuplicatesReduced(ctx, filteredSecrets)
 	return nil
 }
 
@@ -49,7 +49,7 @@ func ReduceSecrets(ctx context.Context, k8sClient client.Client, secrets []corev
 // ReduceServiceAccounts detects the best serviceAccount in the set and deletes all the others.
 func ReduceServiceAccounts(ctx context.Context, k8sClient client.Client, serviceAccounts []corev1.ServiceAccount) error {
 	filteredServiceAccounts := filterServiceAccounts(serviceAccounts)
-	return clientops.DeleteAll(ctx, k8sClient, filteredServiceAccounts)
+	return deleteAll(ctx, k8sClient, filteredServiceAccounts)
 }
 
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=delete
@@ -57,7 +57,7 @@ func ReduceServiceAccounts(ctx context.Context, k8sClient client.Client, service
 // ReduceClusterRoles detects the best ClusterRole in the set and deletes all the others.
 func ReduceClusterRoles(ctx context.Context, k8sClient client.Client, clusterRoles []rbacv1.ClusterRole) error {
 	filteredClusterRoles := filterClusterRoles(clusterRoles)
-	return clientops.DeleteAll(ctx, k8sClient, filteredClusterRoles)
+	return deleteAll(ctx, k8sClient, filteredClusterRoles)
 }
 
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=delete
@@ -65,7 +65,23 @@ func ReduceClusterRoles(ctx context.Context, k8sClient client.Client, clusterRol
 // ReduceClusterRoleBindings detects the best ClusterRoleBinding in the set and deletes all the others.
 func ReduceClusterRoleBindings(ctx context.Context, k8sClient client.Client, clusterRoleBindings []rbacv1.ClusterRoleBinding) error {
 	filteredCLusterRoleBindings := filterClusterRoleBindings(clusterRoleBindings)
-	return clientops.DeleteAll(ctx, k8sClient, filteredCLusterRoleBindings)
+	return deleteAll(ctx, k8sClient, filteredCLusterRoleBindings)
+}
+
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=delete
+
+// ReduceRoles detects the best Role in the set and deletes all the others.
+func ReduceRoles(ctx context.Context, k8sClient client.Client, roles []rbacv1.Role) error {
+	filteredRoles := filterRoles(roles)
+	return deleteAll(ctx, k8sClient, filteredRoles)
+}
+
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=delete
+
+// ReduceRoleBindings detects the best RoleBinding in the set and deletes all the others.
+func ReduceRoleBindings(ctx context.Context, k8sClient client.Client, roleBindings []rbacv1.RoleBinding) error {
+	filteredRoleBindings := filterRoleBindings(roleBindings)
+	return deleteAll(ctx, k8sClient, filteredRoleBindings)
 }
 
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=delete
@@ -84,6 +100,7 @@ func ReduceDeployments(ctx context.Context, k8sClient client.Client, deployments
 			return err
 		}
 	}
+	recordDuplicatesReduced(ctx, filteredDeployments)
 	return nil
 }
 
@@ -117,6 +134,7 @@ func ReduceServices(ctx context.Context, k8sClient client.Client, services []cor
 			return err
 		}
 	}
+	recordDuplicatesReduced(ctx, filteredServices)
 	return nil
 }
 
@@ -136,6 +154,7 @@ func ReduceServicesByName(ctx context.Context, k8sClient client.Client, services
 			return err
 		}
 	}
+	recordDuplicatesReduced(ctx, filteredServices)
 	return nil
 }
 
@@ -144,7 +163,7 @@ func ReduceServicesByName(ctx context.Context, k8sClient client.Client, services
 // ReduceNetworkPolicies detects the best NetworkPolicy in the set and deletes all the others.
 func ReduceNetworkPolicies(ctx context.Context, k8sClient client.Client, networkPolicies []networkingv1.NetworkPolicy) error {
 	filteredNetworkPolicies := filterNetworkPolicies(networkPolicies)
-	return clientops.DeleteAll(ctx, k8sClient, filteredNetworkPolicies)
+	return deleteAll(ctx, k8sClient, filteredNetworkPolicies)
 }
 
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=delete
@@ -155,7 +174,7 @@ type HPAFilterFunc func([]autoscalingv2.HorizontalPodAutoscaler) []autoscalingv2
 // ReduceHPAs detects the best HorizontalPodAutoscaler in the set and deletes all the others.
 func ReduceHPAs(ctx context.Context, k8sClient client.Client, hpas []autoscalingv2.HorizontalPodAutoscaler, filter HPAFilterFunc) error {
 	filtered := filter(hpas)
-	return clientops.DeleteAll(ctx, k8sClient, filtered)
+	return deleteAll(ctx, k8sClient, filtered)
 }
 
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=delete
@@ -165,7 +184,7 @@ type PDBFilterFunc func([]policyv1.PodDisruptionBudget) []policyv1.PodDisruption
 
 // ReducePodDisruptionBudgets detects the best PodDisruptionBudget in the set and deletes all the others.
 func ReducePodDisruptionBudgets(ctx context.Context, k8sClient client.Client, pdbs []policyv1.PodDisruptionBudget, filter PDBFilterFunc) error {
-	return clientops.DeleteAll(ctx, k8sClient, pdbs)
+	return deleteAll(ctx, k8sClient, pdbs)
 }
 
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=delete
@@ -173,7 +192,7 @@ func ReducePodDisruptionBudgets(ctx context.Context, k8sClient client.Client, pd
 // ReduceValidatingWebhookConfigurations detects the best ValidatingWebhookConfiguration in the set and deletes all the others.
 func ReduceValidatingWebhookConfigurations(ctx context.Context, k8sClient client.Client, webhookConfigurations []admregv1.ValidatingWebhookConfiguration) error {
 	filteredWebhookConfigurations := filterValidatingWebhookConfigurations(webhookConfigurations)
-	return clientops.DeleteAll(ctx, k8sClient, filteredWebhookConfigurations)
+	return deleteAll(ctx, k8sClient, filteredWebhookConfigurations)
 }
 
 // +kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=dataplanes,verbs=delete
@@ -181,7 +200,7 @@ func ReduceValidatingWebhookConfigurations(ctx context.Context, k8sClient client
 // ReduceDataPlanes detects the best DataPlane in the set and deletes all the others.
 func ReduceDataPlanes(ctx context.Context, k8sClient client.Client, dataplanes []operatorv1beta1.DataPlane) error {
 	filteredDataPlanes := filterDataPlanes(dataplanes)
-	return clientops.DeleteAll(ctx, k8sClient, filteredDataPlanes)
+	return deleteAll(ctx, k8sClient, filteredDataPlanes)
 }
 
 // +kubebuilder:rbac:groups=configuration.konghq.com,resources=kongpluginbindings,verbs=delete
@@ -189,7 +208,7 @@ func ReduceDataPlanes(ctx context.Context, k8sClient client.Client, dataplanes [
 // ReduceKongPluginBindings detects the best KongPluginBinding in the set and deletes all the others.
 func ReduceKongPluginBindings(ctx context.Context, k8sClient client.Client, kpbs []configurationv1alpha1.KongPluginBinding) error {
 	filteredKongPluginBindings := filterKongPluginBindings(kpbs)
-	return clientops.DeleteAll(ctx, k8sClient, filteredKongPluginBindings)
+	return deleteAll(ctx, k8sClient, filteredKongPluginBindings)
 }
 
 // ReduceKongCredentials detects the best KongCredential in the set and deletes all the others.
@@ -198,5 +217,5 @@ func ReduceKongCredentials[
 	TPtr constraints.KongCredential[T],
 ](ctx context.Context, k8sClient client.Client, kongCredentials []T) error {
 	filtered := filterKongCredentials[T, TPtr](kongCredentials)
-	return clientops.DeleteAll[T, TPtr](ctx, k8sClient, filtered)
+	return deleteAll[T, TPtr](ctx, k8sClient, filtered)
 }
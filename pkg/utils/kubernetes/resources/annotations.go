@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"encoding/json"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -23,6 +24,43 @@ func AnnotateConfigMapWithKongPluginInstallation(cm *corev1.ConfigMap, kpi opera
 	cm.SetAnnotations(annotations)
 }
 
+// AnnotateConfigMapWithKongPluginInstallationFiles sets the annotation storing
+// the paths, in the plugin's directory, of the files stored under the keys of
+// the ConfigMap. The annotation is removed when files is empty.
+func AnnotateConfigMapWithKongPluginInstallationFiles(cm *corev1.ConfigMap, files map[string]string) error {
+	annotations := cm.GetAnnotations()
+	if len(files) == 0 {
+		delete(annotations, consts.AnnotationKongPluginInstallationFiles)
+		return nil
+	}
+	b, err := json.Marshal(files)
+	if err != nil {
+		return fmt.Errorf("failed to marshal plugin files: %w", err)
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[consts.AnnotationKongPluginInstallationFiles] = string(b)
+	cm.SetAnnotations(annotations)
+	return nil
+}
+
+// GetKongPluginInstallationFiles returns the paths, in the plugin's directory,
+// of the files stored under the keys of the ConfigMap. It returns nil when the
+// files are stored under their names.
+func GetKongPluginInstallationFiles(cm *corev1.ConfigMap) (map[string]string, error) {
+	annotation, ok := cm.GetAnnotations()[consts.AnnotationKongPluginInstallationFiles]
+	if !ok {
+		return nil, nil
+	}
+	var files map[string]string
+	if err := json.Unmarshal([]byte(annotation), &files); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation of ConfigMap %s: %w",
+			consts.AnnotationKongPluginInstallationFiles, client.ObjectKeyFromObject(cm), err)
+	}
+	return files, nil
+}
+
 // AnnotateObjWithHash sets the hash of the provided toHash object in the provided
 // obj's annotations.
 func AnnotateObjWithHash[T any](
@@ -0,0 +1,124 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sutils "github.com/kong/gateway-operator/pkg/utils/kubernetes"
+)
+
+// clusterScopedControlPlaneResources contains the resources referenced in the
+// ControlPlane ClusterRoles which are cluster-scoped and hence cannot be granted
+// through a namespaced Role.
+var clusterScopedControlPlaneResources = map[string]struct{}{
+	"customresourcedefinitions": {},
+	"gatewayclasses":            {},
+	"ingressclasses":            {},
+	"kongclusterplugins":        {},
+	"konglicenses":              {},
+	"kongvaults":                {},
+	"namespaces":                {},
+	"nodes":                     {},
+}
+
+// -----------------------------------------------------------------------------
+// Role generators
+// -----------------------------------------------------------------------------
+
+// GenerateNewRoleForControlPlane is a helper function that generates a namespaced
+// Role for the ControlPlane, to be used when the operator only watches a set
+// of namespaces. Its rules are derived from the ClusterRole generated for the
+// ControlPlane's image version, skipping the non-resource URLs and cluster-scoped
+// resources which cannot be granted by a Role.
+func GenerateNewRoleForControlPlane(namespace, controlplaneName, image string, devMode bool) (*rbacv1.Role, error) {
+	clusterRole, err := GenerateNewClusterRoleForControlPlane(controlplaneName, image, devMode)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    namespace,
+			GenerateName: k8sutils.TrimGenerateName(fmt.Sprintf("%s-%s-", consts.ControlPlanePrefix, controlplaneName)),
+			Labels: map[string]string{
+				"app": controlplaneName,
+			},
+		},
+		Rules: namespacedPolicyRules(clusterRole.Rules),
+	}
+	LabelObjectAsControlPlaneManaged(role)
+	return role, nil
+}
+
+// namespacedPolicyRules returns the provided rules without the non-resource URLs
+// and the cluster-scoped resources. Rules left without resources are dropped.
+func namespacedPolicyRules(rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	namespacedRules := make([]rbacv1.PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		resources := lo.Reject(rule.Resources, func(resource string, _ int) bool {
+			_, clusterScoped := clusterScopedControlPlaneResources[strings.Split(resource, "/")[0]]
+			return clusterScoped
+		})
+		if len(resources) == 0 {
+			continue
+		}
+		rule = *rule.DeepCopy()
+		rule.Resources = resources
+		rule.NonResourceURLs = nil
+		namespacedRules = append(namespacedRules, rule)
+	}
+	return namespacedRules
+}
+
+// -----------------------------------------------------------------------------
+// RoleBinding generators
+// -----------------------------------------------------------------------------
+
+// GenerateNewRoleBindingForControlPlane is a helper to generate a RoleBinding
+// resource to bind the namespaced Role to the service account used by the
+// controlplane deployment.
+func GenerateNewRoleBindingForControlPlane(namespace, controlplaneName, serviceAccountName, roleName string) *rbacv1.RoleBinding {
+	rb := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    namespace,
+			GenerateName: k8sutils.TrimGenerateName(fmt.Sprintf("%s-%s-", consts.ControlPlanePrefix, controlplaneName)),
+			Labels: map[string]string{
+				"app": controlplaneName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     roleName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      serviceAccountName,
+				Namespace: namespace,
+			},
+		},
+	}
+	LabelObjectAsControlPlaneManaged(rb)
+	return rb
+}
+
+// CompareRoleName compares RoleRef in RoleBinding with given role name.
+// It returns true if the referenced role is the role with the given name.
+func CompareRoleName(existingRoleBinding *rbacv1.RoleBinding, roleName string) bool {
+	return existingRoleBinding.RoleRef.APIGroup == "rbac.authorization.k8s.io" &&
+		existingRoleBinding.RoleRef.Kind == "Role" &&
+		existingRoleBinding.RoleRef.Name == roleName
+}
+
+// RoleBindingContainsServiceAccount returns true if the subjects of the RoleBinding contains given service account.
+func RoleBindingContainsServiceAccount(existingRoleBinding *rbacv1.RoleBinding, namespace string, serviceAccountName string) bool {
+	return lo.ContainsBy(existingRoleBinding.Subjects, func(s rbacv1.Subject) bool {
+		return s.Kind == "ServiceAccount" && s.Namespace == namespace && s.Name == serviceAccountName
+	})
+}
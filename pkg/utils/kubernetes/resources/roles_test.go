@@ -0,0 +1,75 @@
+package resources_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/kong/gateway-operator/pkg/consts"
+	k8sresources "github.com/kong/gateway-operator/pkg/utils/kubernetes/resources"
+)
+
+func TestGenerateNewRoleForControlPlane(t *testing.T) {
+	testCases := []struct {
+		name          string
+		image         string
+		devMode       bool
+		expectedError bool
+	}{
+		{
+			name:  "supported image",
+			image: "kong/kubernetes-ingress-controller:3.4.1",
+		},
+		{
+			name:  "default image",
+			image: "",
+		},
+		{
+			name:          "unsupported image",
+			image:         "kong/kubernetes-ingress-controller:2.9.0",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			role, err := k8sresources.GenerateNewRoleForControlPlane("ns", "cp", tc.image, tc.devMode)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, "ns", role.Namespace)
+			require.Equal(t, "controlplane-cp-", role.GenerateName)
+			require.Equal(t, consts.ControlPlaneManagedLabelValue, role.Labels[consts.GatewayOperatorManagedByLabel])
+			require.NotEmpty(t, role.Rules)
+
+			clusterScoped := []string{"namespaces", "nodes", "gatewayclasses", "ingressclasses", "kongclusterplugins", "kongvaults", "konglicenses", "customresourcedefinitions"}
+			var resources []string
+			for _, rule := range role.Rules {
+				require.Empty(t, rule.NonResourceURLs)
+				require.NotEmpty(t, rule.Resources)
+				for _, r := range rule.Resources {
+					require.NotContains(t, clusterScoped, strings.Split(r, "/")[0])
+				}
+				resources = append(resources, rule.Resources...)
+			}
+			require.Contains(t, resources, "secrets")
+			require.Contains(t, resources, "gateways")
+			require.Contains(t, resources, "kongplugins")
+		})
+	}
+}
+
+func TestGenerateNewRoleBindingForControlPlane(t *testing.T) {
+	rb := k8sresources.GenerateNewRoleBindingForControlPlane("ns", "cp", "sa", "role")
+	require.Equal(t, "ns", rb.Namespace)
+	require.True(t, k8sresources.CompareRoleName(rb, "role"))
+	require.False(t, k8sresources.CompareRoleName(rb, "other"))
+	require.True(t, k8sresources.RoleBindingContainsServiceAccount(rb, "ns", "sa"))
+	require.False(t, k8sresources.RoleBindingContainsServiceAccount(rb, "other", "sa"))
+	require.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "sa", Namespace: "ns"}}, rb.Subjects)
+}
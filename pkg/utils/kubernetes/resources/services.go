@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	pkgapiscorev1 "k8s.io/kubernetes/pkg/apis/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -274,6 +276,89 @@ func GenerateNewAdmissionWebhookServiceForControlPlane(cp *operatorv1beta1.Contr
 	return svc, nil
 }
 
+// DataPlaneAdditionalIngressServiceOptions describes an additional, named ingress
+// Service of a DataPlane configured through the consts.DataPlaneIngressServicesAnnotation
+// annotation.
+type DataPlaneAdditionalIngressServiceOptions struct {
+	// Name identifies the Service among the DataPlane's additional ingress Services.
+	// It must be a valid DNS label.
+	Name string `json:"name"`
+	// Type determines how the Service is exposed.
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// Annotations are the annotations that are applied to the Service.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ExternalTrafficPolicy is the externalTrafficPolicy set on the Service.
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+	// Ports are the ports exposed by the Service.
+	// When empty, DefaultDataPlaneIngressServicePorts are used.
+	Ports []operatorv1beta1.DataPlaneServicePort `json:"ports,omitempty"`
+}
+
+// GetDataPlaneAdditionalIngressServices returns the additional ingress Services
+// configured for the DataPlane through the consts.DataPlaneIngressServicesAnnotation
+// annotation. It returns an error when the annotation cannot be parsed or when
+// the Services' names are invalid or not unique.
+func GetDataPlaneAdditionalIngressServices(dataplane *operatorv1beta1.DataPlane) ([]DataPlaneAdditionalIngressServiceOptions, error) {
+	value, ok := dataplane.Annotations[consts.DataPlaneIngressServicesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var services []DataPlaneAdditionalIngressServiceOptions
+	if err := json.Unmarshal([]byte(value), &services); err != nil {
+		return nil, fmt.Errorf("failed parsing %s annotation: %w", consts.DataPlaneIngressServicesAnnotation, err)
+	}
+
+	names := make(map[string]struct{}, len(services))
+	for _, svc := range services {
+		if errs := validation.IsDNS1123Label(svc.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid ingress Service name %q in %s annotation: %s",
+				svc.Name, consts.DataPlaneIngressServicesAnnotation, strings.Join(errs, ", "))
+		}
+		if _, ok := names[svc.Name]; ok {
+			return nil, fmt.Errorf("duplicate ingress Service name %q in %s annotation",
+				svc.Name, consts.DataPlaneIngressServicesAnnotation)
+		}
+		names[svc.Name] = struct{}{}
+	}
+	return services, nil
+}
+
+// DataPlaneForAdditionalIngressService returns a copy of the provided DataPlane
+// with its ingress Service options replaced by the provided additional ingress
+// Service options. The returned DataPlane can be used with the ingress Service
+// generators to generate the additional Service.
+func DataPlaneForAdditionalIngressService(
+	dataplane *operatorv1beta1.DataPlane,
+	svcOpts DataPlaneAdditionalIngressServiceOptions,
+) *operatorv1beta1.DataPlane {
+	dp := dataplane.DeepCopy()
+	svcType := svcOpts.Type
+	if svcType == "" {
+		svcType = DefaultDataPlaneIngressServiceType
+	}
+	dp.Spec.Network.Services = &operatorv1beta1.DataPlaneServices{
+		Ingress: &operatorv1beta1.DataPlaneServiceOptions{
+			Ports: svcOpts.Ports,
+			ServiceOptions: operatorv1beta1.ServiceOptions{
+				Type:                  svcType,
+				Annotations:           svcOpts.Annotations,
+				ExternalTrafficPolicy: svcOpts.ExternalTrafficPolicy,
+			},
+		},
+	}
+	return dp
+}
+
+// ServiceWithAdditionalIngressServiceGenerateName sets the generateName of an
+// additional ingress Service of the provided DataPlane.
+func ServiceWithAdditionalIngressServiceGenerateName(dataplane *operatorv1beta1.DataPlane, name string) ServiceOpt {
+	return func(s *corev1.Service) {
+		s.Name = ""
+		s.GenerateName = k8sutils.TrimGenerateName(fmt.Sprintf("%s-ingress-%s-%s-", consts.DataPlanePrefix, name, dataplane.Name))
+	}
+}
+
 // GetDataPlaneIngressServiceName fetches the specified name of ingress service of dataplane.
 // If the service name is not specified, it returns an empty string.
 func GetDataPlaneIngressServiceName(dataPlane *operatorv1beta1.DataPlane) string {
@@ -10,6 +10,8 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/kong/gateway-operator/pkg/consts"
+
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/api/gateway-operator/v1beta1"
 )
 
@@ -297,3 +299,114 @@ func TestGenerateNewIngressServiceForDataPlane(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDataPlaneAdditionalIngressServices(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    []DataPlaneAdditionalIngressServiceOptions
+		expectedErr bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name: "valid annotation",
+			annotations: map[string]string{
+				consts.DataPlaneIngressServicesAnnotation: `[
+					{"name":"internal","type":"ClusterIP","annotations":{"foo":"bar"},"ports":[{"port":80,"targetPort":8000}]},
+					{"name":"public","externalTrafficPolicy":"Local"}
+				]`,
+			},
+			expected: []DataPlaneAdditionalIngressServiceOptions{
+				{
+					Name:        "internal",
+					Type:        corev1.ServiceTypeClusterIP,
+					Annotations: map[string]string{"foo": "bar"},
+					Ports: []operatorv1beta1.DataPlaneServicePort{
+						{Port: 80, TargetPort: intstr.FromInt(8000)},
+					},
+				},
+				{
+					Name:                  "public",
+					ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyLocal,
+				},
+			},
+		},
+		{
+			name: "malformed annotation",
+			annotations: map[string]string{
+				consts.DataPlaneIngressServicesAnnotation: `{"name":"internal"}`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid name",
+			annotations: map[string]string{
+				consts.DataPlaneIngressServicesAnnotation: `[{"name":"Internal_1"}]`,
+			},
+			expectedErr: true,
+		},
+		{
+			name: "duplicate names",
+			annotations: map[string]string{
+				consts.DataPlaneIngressServicesAnnotation: `[{"name":"internal"},{"name":"internal"}]`,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dataplane := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dp",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+			services, err := GetDataPlaneAdditionalIngressServices(dataplane)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, services)
+		})
+	}
+}
+
+func TestDataPlaneForAdditionalIngressService(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+		},
+		Spec: operatorv1beta1.DataPlaneSpec{
+			DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+				Network: operatorv1beta1.DataPlaneNetworkOptions{
+					Services: &operatorv1beta1.DataPlaneServices{
+						Ingress: &operatorv1beta1.DataPlaneServiceOptions{
+							ServiceOptions: operatorv1beta1.ServiceOptions{
+								Name: lo.ToPtr("primary"),
+								Type: corev1.ServiceTypeNodePort,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dp := DataPlaneForAdditionalIngressService(dataplane, DataPlaneAdditionalIngressServiceOptions{
+		Name:        "internal",
+		Annotations: map[string]string{"foo": "bar"},
+	})
+	require.Equal(t, &operatorv1beta1.DataPlaneServiceOptions{
+		ServiceOptions: operatorv1beta1.ServiceOptions{
+			Type:        DefaultDataPlaneIngressServiceType,
+			Annotations: map[string]string{"foo": "bar"},
+		},
+	}, dp.Spec.Network.Services.Ingress)
+	require.Equal(t, lo.ToPtr("primary"), dataplane.Spec.Network.Services.Ingress.Name, "original DataPlane should not be modified")
+}
@@ -84,3 +84,24 @@ func TestHasSameVolumeSource(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSecretVolumeSecretName(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{
+				Name: "config",
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}},
+				},
+			},
+			{
+				Name:         "certificate",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "cert"}},
+			},
+		},
+	}
+
+	require.Equal(t, "cert", GetSecretVolumeSecretName(podSpec, "certificate"))
+	require.Empty(t, GetSecretVolumeSecretName(podSpec, "config"))
+	require.Empty(t, GetSecretVolumeSecretName(podSpec, "missing"))
+}
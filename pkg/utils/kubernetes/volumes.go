@@ -33,3 +33,15 @@ func HasSameVolumeSource(baseVolumeSource, comparedVolumeSource *corev1.VolumeSo
 
 	return true
 }
+
+// GetSecretVolumeSecretName returns the name of the Secret referenced by the
+// Secret volume with the provided name or an empty string when there is no
+// such volume.
+func GetSecretVolumeSecretName(podSpec corev1.PodSpec, volumeName string) string {
+	for _, v := range podSpec.Volumes {
+		if v.Name == volumeName && v.Secret != nil {
+			return v.Secret.SecretName
+		}
+	}
+	return ""
+}